@@ -349,6 +349,9 @@ Build the args for the service binary.
 {{- end }}
 - -enable-cert-manager={{ .Values.controller.enableCertManager }}
 - -enable-oidc={{ .Values.controller.enableOIDC }}
+{{- if .Values.controller.enableHTTP3 }}
+- -enable-http3={{ .Values.controller.enableHTTP3 }}
+{{- end }}
 - -enable-external-dns={{ .Values.controller.enableExternalDNS }}
 - -default-http-listener-port={{ .Values.controller.defaultHTTPListenerPort}}
 - -default-https-listener-port={{ .Values.controller.defaultHTTPSListenerPort}}
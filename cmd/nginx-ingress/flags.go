@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"path"
 	"regexp"
+	"slices"
 	"strings"
 
 	internalValidation "github.com/nginx/kubernetes-ingress/internal/validation"
@@ -181,6 +183,9 @@ var (
 	enableOIDC = flag.Bool("enable-oidc", false,
 		"Enable OIDC Policies.")
 
+	enableHTTP3 = flag.Bool("enable-http3", false,
+		"Enable HTTP/3 listeners on the HTTPS port for the default server and for VirtualServer resources that enable HTTP/3. Requires NGINX 1.25.0 or NGINX Plus R30 or later")
+
 	enableSnippets = flag.Bool("enable-snippets", false,
 		"Enable custom NGINX configuration snippets in Ingress, VirtualServer, VirtualServerRoute and TransportServer resources.")
 
@@ -223,8 +228,22 @@ var (
 
 	enableDynamicWeightChangesReload = flag.Bool(dynamicWeightChangesParam, false, "Enable changing weights of split clients without reloading NGINX. Requires -nginx-plus")
 
+	shortUpstreamNames = flag.Bool("short-upstream-names", false,
+		"Shorten the names of the upstreams of VirtualServer and VirtualServerRoute resources by replacing the namespaces and the names of the resources with a hash. The full names are kept as comments in the generated configuration")
+
+	upstreamServerPodComments = flag.Bool("upstream-server-pod-comments", false,
+		"Add the name of the pod as a comment to each server of the upstreams of VirtualServer and VirtualServerRoute resources in the generated configuration, for debugging")
+
 	enableDirectiveAutoadjust = flag.Bool("enable-directive-autoadjust", false, "Enable automatic adjustment of NGINX directives to avoid conflicting NGINX configuration. Results may vary and might not be ideal in all cases.")
 
+	allowInternalProxyPassURL = flag.Bool("allow-internal-proxy-pass-url", false,
+		"Allow the proxyPassURL action of VirtualServer and VirtualServerRoute resources to reference cluster-internal addresses. Requires -enable-custom-resources")
+
+	filesActionRootsFlag = flag.String("files-action-roots", "",
+		"Comma-separated list of the directories that the files action of VirtualServer and VirtualServerRoute resources can serve, along with their subdirectories, for example, /usr/share/nginx/html. The directories must be absolute paths. If not set, the files action is not allowed. Requires -enable-custom-resources")
+
+	filesActionRoots []string
+
 	allowEmptyIngressHost = flag.Bool("allow-empty-ingress-host", false,
 		`Allows Ingress resources to omit the host field. If multiple Ingress resources without a host conflict,
 	NGINX Ingress Controller resolves the collision using the winner selection algorithm. To use multiple
@@ -379,6 +398,11 @@ func mustValidateFlags(ctx context.Context) {
 		nl.Fatalf(l, "Invalid value for nginx-status-allow-cidrs: %v", err)
 	}
 
+	filesActionRoots, err = parseFilesActionRoots(*filesActionRootsFlag)
+	if err != nil {
+		nl.Fatalf(l, "Invalid value for files-action-roots: %v", err)
+	}
+
 	if *appProtectLogLevel != appProtectLogLevelDefault && *appProtect && *nginxPlus {
 		appProtectLogLevelValidationError := validateLogLevel(*appProtectLogLevel)
 		if appProtectLogLevelValidationError != nil {
@@ -503,6 +527,31 @@ func parseNginxStatusAllowCIDRs(input string) (cidrs []string, err error) {
 	return cidrs, nil
 }
 
+// parseFilesActionRoots parses the comma-separated list of the directories that the files action can serve.
+// It returns an error if a directory is not an absolute path, is / or contains '..' segments.
+func parseFilesActionRoots(input string) ([]string, error) {
+	if input == "" {
+		return nil, nil
+	}
+
+	var roots []string
+	for _, r := range strings.Split(input, ",") {
+		root := strings.TrimSpace(r)
+		if !path.IsAbs(root) {
+			return nil, fmt.Errorf("invalid directory %q: must be an absolute path", root)
+		}
+		if slices.Contains(strings.Split(root, "/"), "..") {
+			return nil, fmt.Errorf("invalid directory %q: must not contain '..' segments", root)
+		}
+		root = path.Clean(root)
+		if root == "/" {
+			return nil, fmt.Errorf("invalid directory %q: must not be /", root)
+		}
+		roots = append(roots, root)
+	}
+	return roots, nil
+}
+
 // validateCIDRorIP makes sure a given string is either a valid CIDR block or IP address.
 // It an error if it is not valid.
 func validateCIDRorIP(cidr string) error {
@@ -63,6 +63,50 @@ func TestParseNginxStatusAllowCIDRs(t *testing.T) {
 	}
 }
 
+func TestParseFilesActionRoots(t *testing.T) {
+	badRoots := []string{
+		"html",
+		"/usr/share/nginx/html,",
+		"/",
+		"/usr/share/..",
+		"/usr/share/nginx/../../etc",
+	}
+	for _, badRoot := range badRoots {
+		_, err := parseFilesActionRoots(badRoot)
+		if err == nil {
+			t.Errorf("parseFilesActionRoots(%q) returned no error when it should have returned an error", badRoot)
+		}
+	}
+
+	goodRoots := []struct {
+		input    string
+		expected []string
+	}{
+		{
+			"",
+			nil,
+		},
+		{
+			"/usr/share/nginx/html",
+			[]string{"/usr/share/nginx/html"},
+		},
+		{
+			"/usr/share/nginx/html/, /srv//static",
+			[]string{"/usr/share/nginx/html", "/srv/static"},
+		},
+	}
+	for _, goodRoot := range goodRoots {
+		result, err := parseFilesActionRoots(goodRoot.input)
+		if err != nil {
+			t.Errorf("parseFilesActionRoots(%q) returned an error when it should have returned no error: %q", goodRoot.input, err)
+		}
+
+		if !reflect.DeepEqual(result, goodRoot.expected) {
+			t.Errorf("parseFilesActionRoots(%q) returned %v expected %v", goodRoot.input, result, goodRoot.expected)
+		}
+	}
+}
+
 func TestValidateCIDRorIP(t *testing.T) {
 	badCIDRs := []string{"localhost", "thing", "~", "!!!", "", " ", "-1"}
 	for _, badCIDR := range badCIDRs {
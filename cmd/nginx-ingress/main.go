@@ -222,6 +222,7 @@ func main() {
 		MainAppProtectV5EnforcerAddr:   *appProtectEnforcerAddress,
 		EnableLatencyMetrics:           *enableLatencyMetrics,
 		EnableOIDC:                     *enableOIDC,
+		EnableHTTP3:                    *enableHTTP3,
 		SSLRejectHandshake:             sslRejectHandshake,
 		EnableCertManager:              *enableCertManager,
 		DynamicSSLReload:               *enableDynamicSSLReload,
@@ -231,6 +232,8 @@ func main() {
 		NginxVersion:                   nginxVersion,
 		AppProtectBundlePath:           appProtectBundlePath,
 		DefaultCABundle:                caBundlePath,
+		ShortUpstreamNames:             *shortUpstreamNames,
+		UpstreamServerPodComments:      *upstreamServerPodComments,
 	}
 
 	if *nginxPlus {
@@ -282,6 +285,9 @@ func main() {
 		cr_validation.IsCertManagerEnabled(*enableCertManager),
 		cr_validation.IsExternalDNSEnabled(*enableExternalDNS),
 		cr_validation.IsDirectiveAutoadjustEnabled(*enableDirectiveAutoadjust),
+		cr_validation.IsInternalProxyPassURLAllowed(*allowInternalProxyPassURL),
+		cr_validation.FilesActionRoots(filesActionRoots),
+		cr_validation.IsWeightChangesDynamicReload(*enableDynamicWeightChangesReload),
 	)
 
 	if *enableServiceInsight {
@@ -325,6 +331,7 @@ func main() {
 		VirtualServerValidator:       virtualServerValidator,
 		IsPrometheusEnabled:          *enablePrometheusMetrics,
 		IsLatencyMetricsEnabled:      *enableLatencyMetrics,
+		UpstreamServerPodComments:    *upstreamServerPodComments,
 		IsTLSPassthroughEnabled:      *enableTLSPassthrough,
 		TLSPassthroughPort:           *tlsPassthroughPort,
 		SnippetsEnabled:              *enableSnippets,
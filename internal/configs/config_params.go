@@ -19,6 +19,7 @@ type ConfigParams struct {
 	DefaultServerReturn                    string
 	FailTimeout                            string
 	HealthCheckEnabled                     bool
+	HealthCheckGRPCService                 string
 	HealthCheckMandatory                   bool
 	HealthCheckMandatoryQueue              int64
 	HSTS                                   bool
@@ -81,6 +82,7 @@ type ConfigParams struct {
 	ProxyConnectTimeout                    string
 	AddHeaders                             []version2.AddHeader
 	ProxyHideHeaders                       []string
+	DefaultProxyHideHeaders                []string
 	ProxyMaxTempFileSize                   string
 	ProxyPassHeaders                       []string
 	ProxySetHeaders                        []version2.Header
@@ -106,6 +108,8 @@ type ConfigParams struct {
 	SlowStart                              string
 	SSLRedirect                            bool
 	UpstreamZoneSize                       string
+	UpstreamZoneOmitMaxServers             int
+	UpstreamSSLMaxVerifyDepth              int
 	UseClusterIP                           bool
 	VariablesHashBucketSize                uint64
 	VariablesHashMaxSize                   uint64
@@ -169,6 +173,7 @@ type StaticConfigParams struct {
 	MainAppProtectV5EnforcerAddr   string
 	EnableLatencyMetrics           bool
 	EnableOIDC                     bool
+	EnableHTTP3                    bool
 	SSLRejectHandshake             bool
 	EnableCertManager              bool
 	DynamicSSLReload               bool
@@ -178,6 +183,8 @@ type StaticConfigParams struct {
 	NginxVersion                   nginx.Version
 	AppProtectBundlePath           string
 	DefaultCABundle                string
+	ShortUpstreamNames             bool
+	UpstreamServerPodComments      bool
 }
 
 // GlobalConfigParams holds global configuration parameters. For now, it only holds listeners.
@@ -267,12 +274,14 @@ func NewDefaultConfigParams(ctx context.Context, isPlus bool) *ConfigParams {
 		MainMapHashBucketSize:         "256",
 		MainMapHashMaxSize:            "2048",
 		ProxyBuffering:                true,
+		DefaultProxyHideHeaders:       []string{"X-Powered-By"},
 		MainWorkerProcesses:           "auto",
 		MainWorkerConnections:         "1024",
 		HSTSMaxAge:                    2592000,
 		Ports:                         []int{80},
 		SSLPorts:                      []int{443},
 		MaxFails:                      1,
+		UpstreamSSLMaxVerifyDepth:     defaultUpstreamSSLMaxVerifyDepth,
 		MaxConns:                      0,
 		UpstreamZoneSize:              upstreamZoneSize,
 		FailTimeout:                   "10s",
@@ -311,3 +320,26 @@ func NewDefaultMGMTConfigParams(ctx context.Context) *MGMTConfigParams {
 		Secrets:              MGMTSecrets{},
 	}
 }
+
+// defaultUpstreamSSLMaxVerifyDepth is the maximum depth of the upstream certificate chain verification
+// unless configured otherwise, as verifying long pathological chains is expensive.
+const defaultUpstreamSSLMaxVerifyDepth = 10
+
+// minHTTP3NginxVersion is the first version of NGINX with HTTP/3 support. NGINX Plus supports HTTP/3 since R30, which is based on it.
+const minHTTP3NginxVersion = "1.25.0"
+
+// isHTTP3Supported checks if the version of NGINX supports HTTP/3.
+func isHTTP3Supported(version nginx.Version) bool {
+	supported, err := version.OSSGreaterThanOrEqualTo(minHTTP3NginxVersion)
+	return err == nil && supported
+}
+
+// minHealthCheckKeepaliveTimeNginxVersion is the first version of NGINX with the keepalive_time parameter of health checks.
+const minHealthCheckKeepaliveTimeNginxVersion = "1.21.7"
+
+// isHealthCheckKeepaliveTimeSupported checks if the version of NGINX supports the keepalive_time parameter of health checks.
+// An unknown version is assumed to support it.
+func isHealthCheckKeepaliveTimeSupported(version nginx.Version) bool {
+	supported, err := version.OSSGreaterThanOrEqualTo(minHealthCheckKeepaliveTimeNginxVersion)
+	return err != nil || supported
+}
@@ -95,6 +95,16 @@ func ParseConfigMap(ctx context.Context, cfgm *v1.ConfigMap, nginxPlus bool, has
 		cfgParams.ProxyHideHeaders = proxyHideHeaders
 	}
 
+	if defaultProxyHideHeaders, exists := cfgm.Data["default-proxy-hide-headers"]; exists {
+		// an empty value disables the default hidden headers
+		cfgParams.DefaultProxyHideHeaders = nil
+		for _, h := range strings.Split(defaultProxyHideHeaders, ",") {
+			if h = strings.TrimSpace(h); h != "" {
+				cfgParams.DefaultProxyHideHeaders = append(cfgParams.DefaultProxyHideHeaders, h)
+			}
+		}
+	}
+
 	if proxyPassHeaders, exists := GetMapKeyAsStringSlice(cfgm.Data, "proxy-pass-headers", cfgm, ","); exists {
 		cfgParams.ProxyPassHeaders = proxyPassHeaders
 	}
@@ -512,10 +522,56 @@ func ParseConfigMap(ctx context.Context, cfgm *v1.ConfigMap, nginxPlus bool, has
 		cfgParams.UpstreamZoneSize = upstreamZoneSize
 	}
 
+	if upstreamZoneOmitMaxServers, exists, err := GetMapKeyAsInt(cfgm.Data, "upstream-zone-omit-max-servers", cfgm); exists {
+		if err != nil {
+			nl.Error(l, err)
+			eventLog.Event(cfgm, v1.EventTypeWarning, nl.EventReasonInvalidValue, err.Error())
+			configOk = false
+		} else if upstreamZoneOmitMaxServers < 0 {
+			errorText := fmt.Sprintf("ConfigMap %s/%s key %s must not be negative, ignoring", cfgm.Namespace, cfgm.Name, "upstream-zone-omit-max-servers")
+			nl.Warn(l, errorText)
+			eventLog.Event(cfgm, v1.EventTypeWarning, nl.EventReasonInvalidValue, errorText)
+			configOk = false
+		} else if nginxPlus {
+			errorText := fmt.Sprintf("ConfigMap %s/%s key %s is not supported in NGINX Plus, as the upstream zones are required for the dynamic reconfiguration of the upstreams, ignoring", cfgm.Namespace, cfgm.Name, "upstream-zone-omit-max-servers")
+			nl.Warn(l, errorText)
+			eventLog.Event(cfgm, v1.EventTypeWarning, nl.EventReasonInvalidValue, errorText)
+			configOk = false
+		} else {
+			cfgParams.UpstreamZoneOmitMaxServers = upstreamZoneOmitMaxServers
+		}
+	}
+
+	if upstreamSSLMaxVerifyDepth, exists, err := GetMapKeyAsInt(cfgm.Data, "upstream-ssl-max-verify-depth", cfgm); exists {
+		if err != nil {
+			nl.Error(l, err)
+			eventLog.Event(cfgm, v1.EventTypeWarning, nl.EventReasonInvalidValue, err.Error())
+			configOk = false
+		} else if upstreamSSLMaxVerifyDepth < 1 {
+			errorText := fmt.Sprintf("ConfigMap %s/%s key %s must be positive, ignoring", cfgm.Namespace, cfgm.Name, "upstream-ssl-max-verify-depth")
+			nl.Warn(l, errorText)
+			eventLog.Event(cfgm, v1.EventTypeWarning, nl.EventReasonInvalidValue, errorText)
+			configOk = false
+		} else {
+			cfgParams.UpstreamSSLMaxVerifyDepth = upstreamSSLMaxVerifyDepth
+		}
+	}
+
 	if failTimeout, exists := cfgm.Data["fail-timeout"]; exists {
 		cfgParams.FailTimeout = failTimeout
 	}
 
+	if healthCheckGRPCService, exists := cfgm.Data["health-check-grpc-service"]; exists {
+		if err := validation.ValidateGRPCService(healthCheckGRPCService); err != nil {
+			errorText := fmt.Sprintf("ConfigMap %s/%s key %s is invalid, ignoring: %v", cfgm.Namespace, cfgm.Name, "health-check-grpc-service", err)
+			nl.Warn(l, errorText)
+			eventLog.Event(cfgm, v1.EventTypeWarning, nl.EventReasonInvalidValue, errorText)
+			configOk = false
+		} else {
+			cfgParams.HealthCheckGRPCService = healthCheckGRPCService
+		}
+	}
+
 	if mainTemplate, exists := cfgm.Data["main-template"]; exists {
 		cfgParams.MainTemplate = &mainTemplate
 	} else {
@@ -1278,10 +1334,11 @@ func GenerateNginxMainConfig(staticCfgParams *StaticConfigParams, config *Config
 			SIDSTimeout:     config.OIDC.SIDSTimeout,
 			SIDSZoneSize:    config.OIDC.SIDSZoneSize,
 		},
-		ZoneSyncConfig:          zoneSyncConfig,
-		DynamicSSLReloadEnabled: staticCfgParams.DynamicSSLReload,
-		StaticSSLPath:           staticCfgParams.StaticSSLPath,
-		NginxVersion:            staticCfgParams.NginxVersion,
+		ZoneSyncConfig:                    zoneSyncConfig,
+		DynamicSSLReloadEnabled:           staticCfgParams.DynamicSSLReload,
+		DynamicWeightChangesReloadEnabled: staticCfgParams.DynamicWeightChangesReload,
+		StaticSSLPath:                     staticCfgParams.StaticSSLPath,
+		NginxVersion:                      staticCfgParams.NginxVersion,
 	}
 	return nginxCfg
 }
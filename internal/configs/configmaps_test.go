@@ -264,6 +264,198 @@ func TestParseConfigMapAccessLog(t *testing.T) {
 	}
 }
 
+func TestParseConfigMapUpstreamZoneOmitMaxServers(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		value  string
+		isPlus bool
+		want   int
+		wantOk bool
+		msg    string
+	}{
+		{
+			value:  "1",
+			isPlus: false,
+			want:   1,
+			wantOk: true,
+			msg:    "OSS with a threshold",
+		},
+		{
+			value:  "0",
+			isPlus: false,
+			want:   0,
+			wantOk: true,
+			msg:    "OSS with the zone always generated",
+		},
+		{
+			value:  "-1",
+			isPlus: false,
+			want:   0,
+			wantOk: false,
+			msg:    "OSS with a negative threshold",
+		},
+		{
+			value:  "one",
+			isPlus: false,
+			want:   0,
+			wantOk: false,
+			msg:    "OSS with an invalid threshold",
+		},
+		{
+			value:  "1",
+			isPlus: true,
+			want:   0,
+			wantOk: false,
+			msg:    "Plus with a threshold",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			cm := &v1.ConfigMap{
+				Data: map[string]string{
+					"upstream-zone-omit-max-servers": test.value,
+				},
+			}
+			result, configOk := ParseConfigMap(context.Background(), cm, test.isPlus, false, false, false, false, makeEventLogger())
+			if configOk != test.wantOk {
+				t.Errorf("ParseConfigMap() returned configOk %v, want %v", configOk, test.wantOk)
+			}
+			if result.UpstreamZoneOmitMaxServers != test.want {
+				t.Errorf("want %d, got %d", test.want, result.UpstreamZoneOmitMaxServers)
+			}
+		})
+	}
+}
+
+func TestParseConfigMapUpstreamSSLMaxVerifyDepth(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		value  string
+		want   int
+		wantOk bool
+		msg    string
+	}{
+		{
+			value:  "5",
+			want:   5,
+			wantOk: true,
+			msg:    "valid maximum",
+		},
+		{
+			value:  "0",
+			want:   defaultUpstreamSSLMaxVerifyDepth,
+			wantOk: false,
+			msg:    "zero maximum",
+		},
+		{
+			value:  "five",
+			want:   defaultUpstreamSSLMaxVerifyDepth,
+			wantOk: false,
+			msg:    "invalid maximum",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			cm := &v1.ConfigMap{
+				Data: map[string]string{
+					"upstream-ssl-max-verify-depth": test.value,
+				},
+			}
+			result, configOk := ParseConfigMap(context.Background(), cm, false, false, false, false, false, makeEventLogger())
+			if configOk != test.wantOk {
+				t.Errorf("ParseConfigMap() returned configOk %v, want %v", configOk, test.wantOk)
+			}
+			if result.UpstreamSSLMaxVerifyDepth != test.want {
+				t.Errorf("want %d, got %d", test.want, result.UpstreamSSLMaxVerifyDepth)
+			}
+		})
+	}
+}
+
+func TestParseConfigMapDefaultProxyHideHeaders(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		data map[string]string
+		want []string
+		msg  string
+	}{
+		{
+			data: map[string]string{},
+			want: []string{"X-Powered-By"},
+			msg:  "default",
+		},
+		{
+			data: map[string]string{
+				"default-proxy-hide-headers": "Server, X-Powered-By,X-AspNet-Version",
+			},
+			want: []string{"Server", "X-Powered-By", "X-AspNet-Version"},
+			msg:  "custom headers",
+		},
+		{
+			data: map[string]string{
+				"default-proxy-hide-headers": "",
+			},
+			want: nil,
+			msg:  "disabled",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			cm := &v1.ConfigMap{
+				Data: test.data,
+			}
+			result, _ := ParseConfigMap(context.Background(), cm, false, false, false, false, false, makeEventLogger())
+			if !reflect.DeepEqual(result.DefaultProxyHideHeaders, test.want) {
+				t.Errorf("want %v, got %v", test.want, result.DefaultProxyHideHeaders)
+			}
+		})
+	}
+}
+
+func TestParseConfigMapHealthCheckGRPCService(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		data map[string]string
+		want string
+		msg  string
+	}{
+		{
+			data: map[string]string{},
+			want: "",
+			msg:  "default",
+		},
+		{
+			data: map[string]string{
+				"health-check-grpc-service": "grpc.health.v1.Health",
+			},
+			want: "grpc.health.v1.Health",
+			msg:  "custom service",
+		},
+		{
+			data: map[string]string{
+				"health-check-grpc-service": "grpc.health; return 200",
+			},
+			want: "",
+			msg:  "invalid service",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			cm := &v1.ConfigMap{
+				Data: test.data,
+			}
+			result, _ := ParseConfigMap(context.Background(), cm, true, false, false, false, false, makeEventLogger())
+			if result.HealthCheckGRPCService != test.want {
+				t.Errorf("want %q, got %q", test.want, result.HealthCheckGRPCService)
+			}
+		})
+	}
+}
+
 func TestParseConfigMapAccessLogDefault(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -2,8 +2,14 @@ package configs
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"sort"
 	"strings"
 
 	nl "github.com/nginx/kubernetes-ingress/internal/logger"
@@ -37,6 +43,7 @@ const (
 	appProtectDosPolicyFolder       = "/etc/nginx/dos/policies/"
 	appProtectDosLogConfFolder      = "/etc/nginx/dos/logconfs/"
 	appProtectDosAllowListFolder    = "/etc/nginx/dos/allowlist/"
+	sharedLimitReqZonesConfigName   = "_shared-limit-req-zones"
 
 	// sharedInputFailureThreshold is the number of consecutive per-resource config
 	// validation failures that triggers early exit from UpdateConfig's resource loop.
@@ -123,29 +130,33 @@ type metricLabelsIndex struct {
 // This allows the Ingress Controller to incrementally build the NGINX configuration during the IC start and
 // then apply it at the end of the start.
 type Configurator struct {
-	nginxManager                 nginx.Manager
-	staticCfgParams              *StaticConfigParams
-	CfgParams                    *ConfigParams
-	MgmtCfgParams                *MGMTConfigParams
-	templateExecutor             *version1.TemplateExecutor
-	templateExecutorV2           *version2.TemplateExecutor
-	ingresses                    map[string]*IngressEx
-	minions                      map[string]map[string]bool
-	mergeableIngresses           map[string]*MergeableIngresses
-	virtualServers               map[string]*VirtualServerEx
-	transportServers             map[string]*TransportServerEx
-	tlsPassthroughPairs          map[string]tlsPassthroughPair
-	isWildcardEnabled            bool
-	isPlus                       bool
-	labelUpdater                 collector.LabelUpdater
-	metricLabelsIndex            *metricLabelsIndex
-	isPrometheusEnabled          bool
-	latencyCollector             latCollector.LatencyCollector
-	isLatencyMetricsEnabled      bool
-	isReloadsEnabled             bool
-	isDynamicSSLReloadEnabled    bool
-	ingressControllerReplicas    int
-	effectiveBatchExclusionCount int
+	nginxManager                   nginx.Manager
+	staticCfgParams                *StaticConfigParams
+	CfgParams                      *ConfigParams
+	MgmtCfgParams                  *MGMTConfigParams
+	templateExecutor               *version1.TemplateExecutor
+	templateExecutorV2             *version2.TemplateExecutor
+	ingresses                      map[string]*IngressEx
+	minions                        map[string]map[string]bool
+	mergeableIngresses             map[string]*MergeableIngresses
+	virtualServers                 map[string]*VirtualServerEx
+	transportServers               map[string]*TransportServerEx
+	tlsPassthroughPairs            map[string]tlsPassthroughPair
+	sharedLimitReqZones            map[string][]version2.LimitReqZone
+	sharedLimitReqZonesConfig      []byte
+	virtualServerSecretFiles       map[string][]string
+	virtualServerNextUpstreamTries map[string]map[string]int
+	isWildcardEnabled              bool
+	isPlus                         bool
+	labelUpdater                   collector.LabelUpdater
+	metricLabelsIndex              *metricLabelsIndex
+	isPrometheusEnabled            bool
+	latencyCollector               latCollector.LatencyCollector
+	isLatencyMetricsEnabled        bool
+	isReloadsEnabled               bool
+	isDynamicSSLReloadEnabled      bool
+	ingressControllerReplicas      int
+	effectiveBatchExclusionCount   int
 }
 
 // ConfiguratorParams is a collection of parameters used for the
@@ -183,27 +194,30 @@ func NewConfigurator(p ConfiguratorParams) *Configurator {
 	}
 
 	cnf := Configurator{
-		nginxManager:              p.NginxManager,
-		staticCfgParams:           p.StaticCfgParams,
-		CfgParams:                 p.Config,
-		MgmtCfgParams:             p.MGMTCfgParams,
-		ingresses:                 make(map[string]*IngressEx),
-		virtualServers:            make(map[string]*VirtualServerEx),
-		transportServers:          make(map[string]*TransportServerEx),
-		templateExecutor:          p.TemplateExecutor,
-		templateExecutorV2:        p.TemplateExecutorV2,
-		minions:                   make(map[string]map[string]bool),
-		mergeableIngresses:        make(map[string]*MergeableIngresses),
-		tlsPassthroughPairs:       make(map[string]tlsPassthroughPair),
-		isPlus:                    p.IsPlus,
-		isWildcardEnabled:         p.IsWildcardEnabled,
-		labelUpdater:              p.LabelUpdater,
-		metricLabelsIndex:         metricLabelsIndex,
-		isPrometheusEnabled:       p.IsPrometheusEnabled,
-		latencyCollector:          p.LatencyCollector,
-		isLatencyMetricsEnabled:   p.IsLatencyMetricsEnabled,
-		isDynamicSSLReloadEnabled: p.IsDynamicSSLReloadEnabled,
-		isReloadsEnabled:          false,
+		nginxManager:                   p.NginxManager,
+		staticCfgParams:                p.StaticCfgParams,
+		CfgParams:                      p.Config,
+		MgmtCfgParams:                  p.MGMTCfgParams,
+		ingresses:                      make(map[string]*IngressEx),
+		virtualServers:                 make(map[string]*VirtualServerEx),
+		transportServers:               make(map[string]*TransportServerEx),
+		templateExecutor:               p.TemplateExecutor,
+		templateExecutorV2:             p.TemplateExecutorV2,
+		minions:                        make(map[string]map[string]bool),
+		mergeableIngresses:             make(map[string]*MergeableIngresses),
+		tlsPassthroughPairs:            make(map[string]tlsPassthroughPair),
+		sharedLimitReqZones:            make(map[string][]version2.LimitReqZone),
+		virtualServerSecretFiles:       make(map[string][]string),
+		virtualServerNextUpstreamTries: make(map[string]map[string]int),
+		isPlus:                         p.IsPlus,
+		isWildcardEnabled:              p.IsWildcardEnabled,
+		labelUpdater:                   p.LabelUpdater,
+		metricLabelsIndex:              metricLabelsIndex,
+		isPrometheusEnabled:            p.IsPrometheusEnabled,
+		latencyCollector:               p.LatencyCollector,
+		isLatencyMetricsEnabled:        p.IsLatencyMetricsEnabled,
+		isDynamicSSLReloadEnabled:      p.IsDynamicSSLReloadEnabled,
+		isReloadsEnabled:               false,
 	}
 	return &cnf
 }
@@ -345,7 +359,7 @@ func (cnf *Configurator) upstreamsForVirtualServer(vsEx *VirtualServerEx) []stri
 
 	nl.Debugf(l, "Get upstreamName for vs: %s", vs.Spec.Host)
 
-	virtualServerUpstreamNamer := NewUpstreamNamerForVirtualServer(vs)
+	virtualServerUpstreamNamer := newUpstreamNamerForVirtualServer(vs, cnf.staticCfgParams.ShortUpstreamNames)
 
 	for _, u := range vs.Spec.Upstreams {
 		upstreamName := virtualServerUpstreamNamer.GetNameForUpstream(u.Name)
@@ -354,7 +368,7 @@ func (cnf *Configurator) upstreamsForVirtualServer(vsEx *VirtualServerEx) []stri
 	}
 
 	for _, vsr := range vsEx.VirtualServerRoutes {
-		upstreamNamer := NewUpstreamNamerForVirtualServerRoute(vs, vsr)
+		upstreamNamer := newUpstreamNamerForVirtualServerRoute(vs, vsr, cnf.staticCfgParams.ShortUpstreamNames)
 		for _, u := range vsr.Spec.Upstreams {
 			upstreamName := upstreamNamer.GetNameForUpstream(u.Name)
 			nl.Debugf(l, "upstream: %s, upstreamName: %s", u.Name, upstreamName)
@@ -435,6 +449,7 @@ func GenerateDefaultServerConfig(staticCfgParams *StaticConfigParams, cfgParams
 			ServerTokens:        cfgParams.ServerTokens,
 			TLSPassthrough:      staticCfgParams.TLSPassthrough,
 			HTTP2:               cfgParams.HTTP2,
+			HTTP3:               staticCfgParams.EnableHTTP3 && isHTTP3Supported(staticCfgParams.NginxVersion),
 			ProxyProtocol:       cfgParams.ProxyProtocol,
 			RealIPHeader:        cfgParams.RealIPHeader,
 			SetRealIPFrom:       cfgParams.SetRealIPFrom,
@@ -744,11 +759,38 @@ func (cnf *Configurator) addOrUpdateVirtualServer(virtualServerEx *VirtualServer
 	vsc := newVirtualServerConfigurator(cnf.CfgParams, cnf.isPlus, cnf.IsResolverConfigured(), cnf.staticCfgParams, cnf.isWildcardEnabled, nil)
 	vsc.IngressControllerReplicas = cnf.ingressControllerReplicas
 	vsCfg, warnings := vsc.GenerateVirtualServerConfig(virtualServerEx, apResources, dosResources)
+	var secretFiles []string
+	if mtls := vsCfg.Server.IngressMTLS; mtls != nil && len(mtls.ClientCrlFiles) > 0 {
+		crlName := getFileNameForIngressMTLSCrlVirtualServer(virtualServerEx.VirtualServer)
+		crlPath, err := cnf.addOrUpdateIngressMTLSCrl(crlName, mtls.ClientCrlFiles)
+		if err != nil {
+			return false, warnings, weightUpdates, fmt.Errorf("error generating VirtualServer CRL file: %v: %w", crlName, err)
+		}
+		mtls.ClientCrl = crlPath
+		secretFiles = append(secretFiles, crlName)
+	}
+	for _, basicAuth := range getBasicAuthsWithUsers(&vsCfg) {
+		usersName := getFileNameForBasicAuthUsersVirtualServer(virtualServerEx.VirtualServer, basicAuth.Users)
+		basicAuth.Secret = cnf.nginxManager.CreateSecret(usersName, []byte(basicAuth.Users), nginx.HtpasswdSecretFileMode)
+		if !slices.Contains(secretFiles, usersName) {
+			secretFiles = append(secretFiles, usersName)
+		}
+	}
 	content, err := cnf.templateExecutorV2.ExecuteVirtualServerTemplate(&vsCfg)
 	if err != nil {
 		return false, warnings, weightUpdates, fmt.Errorf("error generating VirtualServer config: %v: %w", name, err)
 	}
 
+	// The shared rate limit zones must be declared before the VS config that uses them is written
+	// for the same reason as the OIDC config below.
+	for _, msg := range cnf.findConflictingSharedLimitReqZones(name, vsCfg.SharedLimitReqZones) {
+		warnings.AddWarning(virtualServerEx.VirtualServer, msg)
+	}
+	sharedZonesChanged, err := cnf.updateSharedLimitReqZonesConfig(name, vsCfg.SharedLimitReqZones)
+	if err != nil {
+		return false, warnings, weightUpdates, err
+	}
+
 	// Order matters only when -enable-config-safety=true: ConfigRollbackManager.CreateConfig
 	// runs `nginx -t` deletes the new file on failure.
 	// VS template emits `include oidc-conf.d/oidc_<ns>_<vs>.conf;`, so the OIDC config must
@@ -769,10 +811,12 @@ func (cnf *Configurator) addOrUpdateVirtualServer(virtualServerEx *VirtualServer
 	if err != nil {
 		return false, warnings, weightUpdates, fmt.Errorf("error validating VirtualServer config %v: %w", name, err)
 	}
-	if oidcChanged {
+	if oidcChanged || sharedZonesChanged {
 		changed = true
 	}
 	cnf.virtualServers[name] = virtualServerEx
+	cnf.updateVirtualServerSecretFiles(name, secretFiles)
+	cnf.virtualServerNextUpstreamTries[name] = getNextUpstreamTries(&vsCfg)
 
 	if (cnf.isPlus && cnf.isPrometheusEnabled) || cnf.isLatencyMetricsEnabled {
 		cnf.updateVirtualServerMetricsLabels(virtualServerEx, vsCfg.Upstreams)
@@ -784,13 +828,40 @@ func (cnf *Configurator) addOrUpdateVirtualServer(virtualServerEx *VirtualServer
 				continue
 			}
 			variableNamer := *NewVSVariableNamer(virtualServerEx.VirtualServer)
-			value := variableNamer.GetNameOfKeyOfMapForWeights(splitClient.SplitClientsIndex, splitClient.Weights[0], splitClient.Weights[1])
+			value := variableNamer.GetNameOfKeyOfMapForWeights(splitClient.SplitClientsID, splitClient.Weights[0], splitClient.Weights[1])
 			weightUpdates = append(weightUpdates, WeightUpdate{Zone: splitClient.ZoneName, Key: splitClient.Key, Value: value})
 		}
 	}
 	return changed, warnings, weightUpdates, nil
 }
 
+// updateVirtualServerSecretFiles records the secret files generated from the policies of the VirtualServer
+// and deletes the previously generated files that the VirtualServer config no longer uses.
+func (cnf *Configurator) updateVirtualServerSecretFiles(name string, secretFiles []string) {
+	for _, f := range cnf.virtualServerSecretFiles[name] {
+		if !slices.Contains(secretFiles, f) {
+			cnf.nginxManager.DeleteSecret(f)
+		}
+	}
+	if len(secretFiles) == 0 {
+		delete(cnf.virtualServerSecretFiles, name)
+		return
+	}
+	cnf.virtualServerSecretFiles[name] = secretFiles
+}
+
+// getNextUpstreamTries returns the next_upstream_tries of the locations of the VirtualServer config by their paths.
+// The tries of the upstreams with failover depend on the number of their servers.
+func getNextUpstreamTries(vsCfg *version2.VirtualServerConfig) map[string]int {
+	tries := make(map[string]int)
+	for _, loc := range vsCfg.Server.Locations {
+		if loc.ProxyNextUpstreamTries != 0 {
+			tries[loc.Path] = loc.ProxyNextUpstreamTries
+		}
+	}
+	return tries
+}
+
 // AddOrUpdateVirtualServers adds or updates NGINX configuration for multiple VirtualServer resources.
 func (cnf *Configurator) AddOrUpdateVirtualServers(virtualServerExes []*VirtualServerEx) (Warnings, error) {
 	allWarnings := newWarnings()
@@ -960,6 +1031,78 @@ func (cnf *Configurator) updateTLSPassthroughHostsConfig() (bool, error) {
 	return cnf.nginxManager.CreateTLSPassthroughHostsConfig(content), nil
 }
 
+// findConflictingSharedLimitReqZones returns the messages about the shared rate limit zones of the VirtualServer config
+// with the given name that have the same name as a zone of the same or another VirtualServer, but a different key,
+// size or rate. Only one of such zones is declared.
+func (cnf *Configurator) findConflictingSharedLimitReqZones(name string, zones []version2.LimitReqZone) []string {
+	var msgs []string
+	zonesByName := make(map[string]version2.LimitReqZone)
+	for _, z := range zones {
+		if zone, exists := zonesByName[z.ZoneName]; exists && zone != z {
+			msgs = append(msgs, fmt.Sprintf("The shared rate limit zone %s is configured more than once with a different key, size or rate, only one of them is used", z.ZoneName))
+			continue
+		}
+		zonesByName[z.ZoneName] = z
+	}
+
+	for _, otherName := range slices.Sorted(maps.Keys(cnf.sharedLimitReqZones)) {
+		vsEx, exists := cnf.virtualServers[otherName]
+		if otherName == name || !exists {
+			continue
+		}
+		for _, other := range cnf.sharedLimitReqZones[otherName] {
+			if zone, exists := zonesByName[other.ZoneName]; exists && zone != other {
+				msgs = append(msgs, fmt.Sprintf("The shared rate limit zone %s conflicts with the zone of the same name of VirtualServer %s/%s, which has a different key, size or rate, only one of them is used",
+					other.ZoneName, vsEx.VirtualServer.Namespace, vsEx.VirtualServer.Name))
+				delete(zonesByName, other.ZoneName)
+			}
+		}
+	}
+	return msgs
+}
+
+// updateSharedLimitReqZonesConfig updates the shared rate limit zones of the VirtualServer config with the given name
+// and writes the config file that declares the zones of all VirtualServers, if its content changes. Every zone is
+// declared once, as NGINX doesn't allow declaring a zone more than once.
+func (cnf *Configurator) updateSharedLimitReqZonesConfig(name string, zones []version2.LimitReqZone) (bool, error) {
+	if len(zones) == 0 {
+		if _, exists := cnf.sharedLimitReqZones[name]; !exists {
+			return false, nil
+		}
+		delete(cnf.sharedLimitReqZones, name)
+	} else {
+		cnf.sharedLimitReqZones[name] = zones
+	}
+
+	content, err := cnf.templateExecutorV2.ExecuteSharedLimitReqZonesTemplate(generateSharedLimitReqZones(cnf.sharedLimitReqZones))
+	if err != nil {
+		return false, fmt.Errorf("error generating config for shared rate limit zones: %w", err)
+	}
+	if bytes.Equal(content, cnf.sharedLimitReqZonesConfig) {
+		return false, nil
+	}
+
+	changed, err := cnf.nginxManager.CreateConfig(sharedLimitReqZonesConfigName, content)
+	if err != nil {
+		return false, fmt.Errorf("error validating config for shared rate limit zones: %w", err)
+	}
+	cnf.sharedLimitReqZonesConfig = content
+	return changed, nil
+}
+
+// generateSharedLimitReqZones returns the shared rate limit zones of all VirtualServer configs. If the configs have
+// conflicting zones with the same name, the zone of the first config by name is used.
+func generateSharedLimitReqZones(zonesByConfig map[string][]version2.LimitReqZone) []version2.LimitReqZone {
+	var zones []version2.LimitReqZone
+	for _, name := range slices.Sorted(maps.Keys(zonesByConfig)) {
+		zones = append(zones, zonesByConfig[name]...)
+	}
+	sort.SliceStable(zones, func(i, j int) bool {
+		return zones[i].ZoneName < zones[j].ZoneName
+	})
+	return removeDuplicateLimitReqZones(zones)
+}
+
 func generateTLSPassthroughHostsConfig(tlsPassthroughPairs map[string]tlsPassthroughPair) *version2.TLSPassthroughHostsConfig {
 	cfg := version2.TLSPassthroughHostsConfig{}
 
@@ -978,6 +1121,47 @@ func (cnf *Configurator) AddOrUpdateCASecret(secret *api_v1.Secret, crtFileName,
 	return fmt.Sprintf("%s %s", crtFilePath, crlFilePath)
 }
 
+// addOrUpdateIngressMTLSCrl combines the CRL files into a single file, since NGINX supports only one ssl_crl file,
+// and returns the path of the combined file.
+func (cnf *Configurator) addOrUpdateIngressMTLSCrl(name string, crlFiles []string) (string, error) {
+	data, err := combineCrlFiles(crlFiles)
+	if err != nil {
+		return "", err
+	}
+	return cnf.nginxManager.CreateSecret(name, data, nginx.ReadWriteOnlyFileMode), nil
+}
+
+// getBasicAuthsWithUsers returns the basic auth configs of the server and the locations with the users specified
+// in the policy instead of a secret.
+func getBasicAuthsWithUsers(vsCfg *version2.VirtualServerConfig) []*version2.BasicAuth {
+	var basicAuths []*version2.BasicAuth
+	if ba := vsCfg.Server.BasicAuth; ba != nil && ba.Users != "" {
+		basicAuths = append(basicAuths, ba)
+	}
+	for _, loc := range vsCfg.Server.Locations {
+		if ba := loc.BasicAuth; ba != nil && ba.Users != "" {
+			basicAuths = append(basicAuths, ba)
+		}
+	}
+	return basicAuths
+}
+
+// combineCrlFiles concatenates the PEM encoded CRL files, returning an error if any of them cannot be read.
+func combineCrlFiles(crlFiles []string) ([]byte, error) {
+	var data []byte
+	for _, f := range crlFiles {
+		crl, err := os.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CRL file %s: %w", f, err)
+		}
+		data = append(data, crl...)
+		if len(crl) > 0 && crl[len(crl)-1] != '\n' {
+			data = append(data, '\n')
+		}
+	}
+	return data, nil
+}
+
 func (cnf *Configurator) addOrUpdateJWKSecret(secret *api_v1.Secret) string {
 	name := objectMetaToFileName(&secret.ObjectMeta)
 	data := secret.Data[JWTKeyKey]
@@ -1177,11 +1361,17 @@ func (cnf *Configurator) DeleteVirtualServer(key string, skipReload bool) error
 			}
 		}
 	}
+	cnf.updateVirtualServerSecretFiles(name, nil)
+	delete(cnf.virtualServerNextUpstreamTries, name)
 
 	if cnf.isPlus {
 		cnf.nginxManager.DeleteKeyValStateFiles(name)
 	}
 
+	if _, err := cnf.updateSharedLimitReqZonesConfig(name, nil); err != nil {
+		return fmt.Errorf("error when removing VirtualServer %v: %w", key, err)
+	}
+
 	delete(cnf.virtualServers, name)
 	if (cnf.isPlus && cnf.isPrometheusEnabled) || cnf.isLatencyMetricsEnabled {
 		cnf.deleteVirtualServerMetricsLabels(key)
@@ -1315,12 +1505,21 @@ func (cnf *Configurator) UpdateEndpointsForVirtualServers(virtualServerExes []*V
 	allWarnings := newWarnings()
 
 	for _, vs := range virtualServerExes {
+		name := getFileNameForVirtualServer(vs.VirtualServer)
+		prevTries, hadTries := cnf.virtualServerNextUpstreamTries[name]
+
 		_, warnings, _, err := cnf.addOrUpdateVirtualServer(vs)
 		if err != nil {
 			return allWarnings, fmt.Errorf("error adding or updating VirtualServer %v/%v: %w", vs.VirtualServer.Namespace, vs.VirtualServer.Name, err)
 		}
 		allWarnings.Add(warnings)
 
+		// the API cannot update the tries of the upstreams with failover, which change with the number of their servers
+		if cnf.isPlus && hadTries && !maps.Equal(prevTries, cnf.virtualServerNextUpstreamTries[name]) {
+			nl.Debugf(l, "The next upstream tries of VirtualServer %v/%v changed with its endpoints; reloading configuration", vs.VirtualServer.Namespace, vs.VirtualServer.Name)
+			reloadPlus = true
+		}
+
 		if cnf.isPlus {
 			err := cnf.updatePlusEndpointsForVirtualServer(vs)
 			if err != nil {
@@ -2143,6 +2342,17 @@ func getFileNameForOIDCVirtualServer(virtualServer *conf_v1.VirtualServer) strin
 	return fmt.Sprintf("oidc_%s_%s", virtualServer.Namespace, virtualServer.Name)
 }
 
+func getFileNameForIngressMTLSCrlVirtualServer(virtualServer *conf_v1.VirtualServer) string {
+	return fmt.Sprintf("ingress_mtls_crl_%s_%s", virtualServer.Namespace, virtualServer.Name)
+}
+
+// getFileNameForBasicAuthUsersVirtualServer returns the name of the htpasswd file with the users, which includes
+// the hash of the users, so that the VirtualServer gets a separate file for every basic auth policy with users.
+func getFileNameForBasicAuthUsersVirtualServer(virtualServer *conf_v1.VirtualServer, users string) string {
+	hash := sha256.Sum256([]byte(users))
+	return fmt.Sprintf("basic_auth_%s_%s_%s", virtualServer.Namespace, virtualServer.Name, hex.EncodeToString(hash[:])[:8])
+}
+
 func getFileNameForTransportServer(transportServer *conf_v1.TransportServer) string {
 	return fmt.Sprintf("ts_%s_%s", transportServer.Namespace, transportServer.Name)
 }
@@ -5,8 +5,11 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"reflect"
 	"regexp"
+	"strings"
 	"sync"
 	"testing"
 
@@ -768,6 +771,56 @@ func TestGenerateDefaultServerConfig(t *testing.T) {
 	}
 }
 
+func TestGenerateDefaultServerConfigWithHTTP3(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		enableHTTP3  bool
+		nginxVersion string
+		want         bool
+	}{
+		{
+			name:         "enabled and supported by NGINX",
+			enableHTTP3:  true,
+			nginxVersion: "nginx version: nginx/1.27.4",
+			want:         true,
+		},
+		{
+			name:         "enabled and supported by NGINX Plus",
+			enableHTTP3:  true,
+			nginxVersion: "nginx version: nginx/1.27.4 (nginx-plus-r34)",
+			want:         true,
+		},
+		{
+			name:         "enabled but not supported by NGINX",
+			enableHTTP3:  true,
+			nginxVersion: "nginx version: nginx/1.23.4",
+			want:         false,
+		},
+		{
+			name:         "not enabled",
+			enableHTTP3:  false,
+			nginxVersion: "nginx version: nginx/1.27.4",
+			want:         false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			staticCfg := &StaticConfigParams{
+				EnableHTTP3:  tt.enableHTTP3,
+				NginxVersion: nginx.NewVersion(tt.nginxVersion),
+			}
+
+			got := GenerateDefaultServerConfig(staticCfg, &ConfigParams{})
+			if got.Servers[0].HTTP3 != tt.want {
+				t.Errorf("GenerateDefaultServerConfig() returned HTTP3 %v but expected %v", got.Servers[0].HTTP3, tt.want)
+			}
+		})
+	}
+}
+
 func TestGetVirtualServerConfigFileName(t *testing.T) {
 	t.Parallel()
 	vs := conf_v1.VirtualServer{
@@ -1936,6 +1989,34 @@ func TestUpstreamsForHost_VirtualServerRoutes(t *testing.T) {
 	}
 }
 
+func TestUpstreamsForHost_ReturnsShortUpstreamNames(t *testing.T) {
+	t.Parallel()
+
+	tcnf := createTestConfigurator(t)
+	tcnf.staticCfgParams.ShortUpstreamNames = true
+	tcnf.virtualServers = map[string]*VirtualServerEx{
+		"vs": validVirtualServerExWithRouteUpstreams,
+	}
+
+	vs := validVirtualServerExWithRouteUpstreams.VirtualServer
+	var want []string
+	for _, vsr := range validVirtualServerExWithRouteUpstreams.VirtualServerRoutes {
+		namer := newUpstreamNamerForVirtualServerRoute(vs, vsr, true)
+		for _, u := range vsr.Spec.Upstreams {
+			want = append(want, namer.GetNameForUpstream(u.Name))
+		}
+	}
+	got := tcnf.UpstreamsForHost("cafe.example.com")
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+	for _, name := range got {
+		if strings.Contains(name, "cafe-vs") {
+			t.Errorf("UpstreamsForHost() returned the upstream %q, which is not shortened", name)
+		}
+	}
+}
+
 func TestUpstreamsForHost_DoesNotReturnUpstreamsOnBogusHostname(t *testing.T) {
 	t.Parallel()
 
@@ -4166,3 +4247,458 @@ func TestBuildFilenameToErrorKey_MultipleEmptyHostIngressesAllMap(t *testing.T)
 		}
 	}
 }
+
+func TestCombineCrlFiles(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	rootCrl := filepath.Join(dir, "root-ca.crl")
+	intermediateCrl := filepath.Join(dir, "intermediate-ca.crl")
+	if err := os.WriteFile(rootCrl, []byte("-----BEGIN X509 CRL-----\nroot\n-----END X509 CRL-----"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(intermediateCrl, []byte("-----BEGIN X509 CRL-----\nintermediate\n-----END X509 CRL-----\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "-----BEGIN X509 CRL-----\nroot\n-----END X509 CRL-----\n" +
+		"-----BEGIN X509 CRL-----\nintermediate\n-----END X509 CRL-----\n"
+
+	got, err := combineCrlFiles([]string{rootCrl, intermediateCrl})
+	if err != nil {
+		t.Fatalf("combineCrlFiles() returned unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("combineCrlFiles() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestCombineCrlFilesFailsOnMissingFile(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	rootCrl := filepath.Join(dir, "root-ca.crl")
+	if err := os.WriteFile(rootCrl, []byte("-----BEGIN X509 CRL-----\nroot\n-----END X509 CRL-----\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := combineCrlFiles([]string{rootCrl, filepath.Join(dir, "missing.crl")})
+	if err == nil {
+		t.Error("combineCrlFiles() returned no error for a missing file")
+	}
+}
+
+func TestAddOrUpdateIngressMTLSCrl(t *testing.T) {
+	t.Parallel()
+	cnf := createTestConfigurator(t)
+	dir := t.TempDir()
+	rootCrl := filepath.Join(dir, "root-ca.crl")
+	if err := os.WriteFile(rootCrl, []byte("-----BEGIN X509 CRL-----\nroot\n-----END X509 CRL-----\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := cnf.addOrUpdateIngressMTLSCrl("ingress_mtls_crl_default_cafe", []string{rootCrl})
+	if err != nil {
+		t.Fatalf("addOrUpdateIngressMTLSCrl() returned unexpected error: %v", err)
+	}
+	want := cnf.nginxManager.GetFilenameForSecret("ingress_mtls_crl_default_cafe")
+	if got != want {
+		t.Errorf("addOrUpdateIngressMTLSCrl() returned %q but expected %q", got, want)
+	}
+}
+
+type configRecordingFakeManager struct {
+	*nginx.FakeManager
+	configs map[string]string
+}
+
+func (m *configRecordingFakeManager) CreateConfig(name string, content []byte) (bool, error) {
+	m.configs[name] = string(content)
+	return m.FakeManager.CreateConfig(name, content)
+}
+
+func createSharedRateLimitVirtualServerEx(name string) *VirtualServerEx {
+	return &VirtualServerEx{
+		VirtualServer: &conf_v1.VirtualServer{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: conf_v1.VirtualServerSpec{
+				Host: name + ".example.com",
+				Policies: []conf_v1.PolicyReference{
+					{Name: "rate-limit-policy"},
+				},
+				Upstreams: []conf_v1.Upstream{
+					{Name: "tea", Service: "tea-svc", Port: 80},
+				},
+				Routes: []conf_v1.Route{
+					{Path: "/tea", Action: &conf_v1.Action{Pass: "tea"}},
+				},
+			},
+		},
+		Policies: map[string]*conf_v1.Policy{
+			"default/rate-limit-policy": {
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "rate-limit-policy",
+					Namespace: "default",
+				},
+				Spec: conf_v1.PolicySpec{
+					RateLimit: &conf_v1.RateLimit{
+						Key:      "$binary_remote_addr",
+						ZoneSize: "10M",
+						Rate:     "10r/s",
+						Shared:   true,
+					},
+				},
+			},
+		},
+		Endpoints: map[string][]string{
+			"default/tea-svc:80": {"10.0.0.10:80"},
+		},
+	}
+}
+
+func TestAddOrUpdateVirtualServersWithSharedRateLimitZone(t *testing.T) {
+	t.Parallel()
+
+	manager := &configRecordingFakeManager{
+		FakeManager: nginx.NewFakeManager("/etc/nginx"),
+		configs:     make(map[string]string),
+	}
+	cnf := createTestConfiguratorWithManager(t, manager)
+
+	zone := "limit_req_zone $binary_remote_addr zone=pol_rl_default_rate_limit_policy_shared:10M rate=10r/s;"
+
+	for _, name := range []string{"cafe", "tea"} {
+		if _, err := cnf.AddOrUpdateVirtualServer(createSharedRateLimitVirtualServerEx(name)); err != nil {
+			t.Fatalf("AddOrUpdateVirtualServer() returned unexpected error: %v", err)
+		}
+
+		vsConfig := manager.configs["vs_default_"+name]
+		if strings.Contains(vsConfig, "limit_req_zone") {
+			t.Errorf("VirtualServer config %s declares the shared zone:\n%s", name, vsConfig)
+		}
+		if !strings.Contains(vsConfig, "limit_req zone=pol_rl_default_rate_limit_policy_shared") {
+			t.Errorf("VirtualServer config %s doesn't use the shared zone:\n%s", name, vsConfig)
+		}
+	}
+
+	if count := strings.Count(manager.configs[sharedLimitReqZonesConfigName], zone); count != 1 {
+		t.Errorf("shared zones config declares the zone %d times, expected once:\n%s", count, manager.configs[sharedLimitReqZonesConfigName])
+	}
+
+	if err := cnf.DeleteVirtualServer("default/cafe", true); err != nil {
+		t.Fatalf("DeleteVirtualServer() returned unexpected error: %v", err)
+	}
+	if !strings.Contains(manager.configs[sharedLimitReqZonesConfigName], zone) {
+		t.Errorf("shared zones config doesn't declare the zone still used by a VirtualServer:\n%s", manager.configs[sharedLimitReqZonesConfigName])
+	}
+
+	if err := cnf.DeleteVirtualServer("default/tea", true); err != nil {
+		t.Fatalf("DeleteVirtualServer() returned unexpected error: %v", err)
+	}
+	if strings.Contains(manager.configs[sharedLimitReqZonesConfigName], "limit_req_zone") {
+		t.Errorf("shared zones config declares zones that are no longer used:\n%s", manager.configs[sharedLimitReqZonesConfigName])
+	}
+}
+
+type configCountingFakeManager struct {
+	*configRecordingFakeManager
+	writes map[string]int
+}
+
+func (m *configCountingFakeManager) CreateConfig(name string, content []byte) (bool, error) {
+	m.writes[name]++
+	return m.configRecordingFakeManager.CreateConfig(name, content)
+}
+
+func TestAddOrUpdateVirtualServersWithSharedRateLimitZoneWritesConfigOnChange(t *testing.T) {
+	t.Parallel()
+
+	manager := &configCountingFakeManager{
+		configRecordingFakeManager: &configRecordingFakeManager{
+			FakeManager: nginx.NewFakeManager("/etc/nginx"),
+			configs:     make(map[string]string),
+		},
+		writes: make(map[string]int),
+	}
+	cnf := createTestConfiguratorWithManager(t, manager)
+
+	for _, name := range []string{"cafe", "tea", "cafe"} {
+		if _, err := cnf.AddOrUpdateVirtualServer(createSharedRateLimitVirtualServerEx(name)); err != nil {
+			t.Fatalf("AddOrUpdateVirtualServer() returned unexpected error: %v", err)
+		}
+	}
+	if writes := manager.writes[sharedLimitReqZonesConfigName]; writes != 1 {
+		t.Errorf("shared zones config was written %d times, expected once", writes)
+	}
+}
+
+func TestAddOrUpdateVirtualServersWithConflictingSharedRateLimitZones(t *testing.T) {
+	t.Parallel()
+
+	manager := &configRecordingFakeManager{
+		FakeManager: nginx.NewFakeManager("/etc/nginx"),
+		configs:     make(map[string]string),
+	}
+	cnf := createTestConfiguratorWithManager(t, manager)
+
+	cafe := createSharedRateLimitVirtualServerEx("cafe")
+	tea := createSharedRateLimitVirtualServerEx("tea")
+	tea.Policies["default/rate-limit-policy"].Spec.RateLimit.Rate = "20r/s"
+
+	sharedWarning := "RateLimit policy default/rate-limit-policy is shared: the limit is enforced together for all resources that reference the policy"
+	warnings, err := cnf.AddOrUpdateVirtualServer(cafe)
+	if err != nil {
+		t.Fatalf("AddOrUpdateVirtualServer() returned unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]string{sharedWarning}, warnings[cafe.VirtualServer]); diff != "" {
+		t.Errorf("AddOrUpdateVirtualServer() returned unexpected warnings (-want +got):\n%s", diff)
+	}
+
+	tests := []struct {
+		vsEx            *VirtualServerEx
+		expectedWarning string
+	}{
+		{
+			vsEx:            tea,
+			expectedWarning: "The shared rate limit zone pol_rl_default_rate_limit_policy_shared conflicts with the zone of the same name of VirtualServer default/cafe, which has a different key, size or rate, only one of them is used",
+		},
+		{
+			vsEx:            cafe,
+			expectedWarning: "The shared rate limit zone pol_rl_default_rate_limit_policy_shared conflicts with the zone of the same name of VirtualServer default/tea, which has a different key, size or rate, only one of them is used",
+		},
+	}
+	for _, test := range tests {
+		warnings, err := cnf.AddOrUpdateVirtualServer(test.vsEx)
+		if err != nil {
+			t.Fatalf("AddOrUpdateVirtualServer() returned unexpected error: %v", err)
+		}
+		if diff := cmp.Diff([]string{sharedWarning, test.expectedWarning}, warnings[test.vsEx.VirtualServer]); diff != "" {
+			t.Errorf("AddOrUpdateVirtualServer() returned unexpected warnings for %s (-want +got):\n%s", test.vsEx.VirtualServer.Name, diff)
+		}
+	}
+
+	zones := manager.configs[sharedLimitReqZonesConfigName]
+	if count := strings.Count(zones, "zone=pol_rl_default_rate_limit_policy_shared:"); count != 1 {
+		t.Errorf("shared zones config declares the zone %d times, expected once:\n%s", count, zones)
+	}
+}
+
+type secretRecordingFakeManager struct {
+	*configRecordingFakeManager
+	secrets map[string]string
+}
+
+func (m *secretRecordingFakeManager) CreateSecret(name string, content []byte, mode os.FileMode) string {
+	m.secrets[name] = string(content)
+	return m.FakeManager.CreateSecret(name, content, mode)
+}
+
+func (m *secretRecordingFakeManager) DeleteSecret(name string) {
+	delete(m.secrets, name)
+	m.FakeManager.DeleteSecret(name)
+}
+
+func TestUpdateVirtualServerSecretFiles(t *testing.T) {
+	t.Parallel()
+
+	manager := &secretRecordingFakeManager{
+		configRecordingFakeManager: &configRecordingFakeManager{
+			FakeManager: nginx.NewFakeManager("/etc/nginx"),
+			configs:     make(map[string]string),
+		},
+		secrets: make(map[string]string),
+	}
+	cnf := createTestConfiguratorWithManager(t, manager)
+
+	crlName := "ingress_mtls_crl_default_cafe"
+	usersName := "basic_auth_users_default_cafe"
+	for _, name := range []string{crlName, usersName} {
+		manager.CreateSecret(name, []byte("data"), nginx.ReadWriteOnlyFileMode)
+	}
+	cnf.updateVirtualServerSecretFiles("vs_default_cafe", []string{crlName, usersName})
+
+	// the policy of the VirtualServer no longer combines CRL files
+	cnf.updateVirtualServerSecretFiles("vs_default_cafe", []string{usersName})
+	if _, exists := manager.secrets[crlName]; exists {
+		t.Errorf("updateVirtualServerSecretFiles() didn't delete the unused file %s", crlName)
+	}
+	if _, exists := manager.secrets[usersName]; !exists {
+		t.Errorf("updateVirtualServerSecretFiles() deleted the used file %s", usersName)
+	}
+
+	if err := cnf.DeleteVirtualServer("default/cafe", true); err != nil {
+		t.Fatalf("DeleteVirtualServer() returned unexpected error: %v", err)
+	}
+	if len(manager.secrets) != 0 {
+		t.Errorf("DeleteVirtualServer() didn't delete the files %v", manager.secrets)
+	}
+	if len(cnf.virtualServerSecretFiles) != 0 {
+		t.Errorf("DeleteVirtualServer() didn't forget the files %v", cnf.virtualServerSecretFiles)
+	}
+}
+
+func TestAddOrUpdateVirtualServerWithBasicAuthUsers(t *testing.T) {
+	t.Parallel()
+
+	manager := &secretRecordingFakeManager{
+		configRecordingFakeManager: &configRecordingFakeManager{
+			FakeManager: nginx.NewFakeManager("/etc/nginx"),
+			configs:     make(map[string]string),
+		},
+		secrets: make(map[string]string),
+	}
+	cnf := createTestConfiguratorWithManager(t, manager)
+
+	vsEx := &VirtualServerEx{
+		VirtualServer: &conf_v1.VirtualServer{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "cafe",
+				Namespace: "default",
+			},
+			Spec: conf_v1.VirtualServerSpec{
+				Host: "cafe.example.com",
+				Upstreams: []conf_v1.Upstream{
+					{Name: "tea", Service: "tea-svc", Port: 80},
+				},
+				Routes: []conf_v1.Route{
+					{
+						Path:     "/tea",
+						Policies: []conf_v1.PolicyReference{{Name: "basic-auth-policy"}},
+						Action:   &conf_v1.Action{Pass: "tea"},
+					},
+				},
+			},
+		},
+		Policies: map[string]*conf_v1.Policy{
+			"default/basic-auth-policy": {
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "basic-auth-policy",
+					Namespace: "default",
+				},
+				Spec: conf_v1.PolicySpec{
+					BasicAuth: &conf_v1.BasicAuth{
+						Realm: "Tea",
+						Users: []conf_v1.BasicAuthUser{
+							{Name: "alice", Hash: "$apr1$abcdefgh$h9FWgUz3n9YxylKLlR5SQ/"},
+						},
+					},
+				},
+			},
+		},
+		Endpoints: map[string][]string{
+			"default/tea-svc:80": {"10.0.0.10:80"},
+		},
+	}
+
+	if _, err := cnf.AddOrUpdateVirtualServer(vsEx); err != nil {
+		t.Fatalf("AddOrUpdateVirtualServer() returned unexpected error: %v", err)
+	}
+
+	users := "alice:$apr1$abcdefgh$h9FWgUz3n9YxylKLlR5SQ/\n"
+	usersName := getFileNameForBasicAuthUsersVirtualServer(vsEx.VirtualServer, users)
+	if got := manager.secrets[usersName]; got != users {
+		t.Errorf("AddOrUpdateVirtualServer() wrote the users file %q but expected %q", got, users)
+	}
+	want := fmt.Sprintf("auth_basic_user_file %s;", manager.GetFilenameForSecret(usersName))
+	if vsConfig := manager.configs["vs_default_cafe"]; !strings.Contains(vsConfig, want) {
+		t.Errorf("VirtualServer config doesn't contain %q:\n%s", want, vsConfig)
+	}
+
+	// the users of the policy are changed
+	vsEx.Policies["default/basic-auth-policy"].Spec.BasicAuth.Users = []conf_v1.BasicAuthUser{
+		{Name: "bob", Hash: "$apr1$ijklmnop$Wq7dPHMc5r8TmbOPq6Qmq1"},
+	}
+	if _, err := cnf.AddOrUpdateVirtualServer(vsEx); err != nil {
+		t.Fatalf("AddOrUpdateVirtualServer() returned unexpected error: %v", err)
+	}
+
+	newUsers := "bob:$apr1$ijklmnop$Wq7dPHMc5r8TmbOPq6Qmq1\n"
+	newUsersName := getFileNameForBasicAuthUsersVirtualServer(vsEx.VirtualServer, newUsers)
+	if got := manager.secrets[newUsersName]; got != newUsers {
+		t.Errorf("AddOrUpdateVirtualServer() wrote the users file %q but expected %q", got, newUsers)
+	}
+	if _, exists := manager.secrets[usersName]; exists {
+		t.Errorf("AddOrUpdateVirtualServer() didn't delete the previous users file %s", usersName)
+	}
+
+	if err := cnf.DeleteVirtualServer("default/cafe", true); err != nil {
+		t.Fatalf("DeleteVirtualServer() returned unexpected error: %v", err)
+	}
+	if len(manager.secrets) != 0 {
+		t.Errorf("DeleteVirtualServer() didn't delete the users files %v", manager.secrets)
+	}
+}
+
+type reloadCountingFakeManager struct {
+	*nginx.FakeManager
+	reloads int
+}
+
+func (m *reloadCountingFakeManager) Reload(isEndpointsUpdate bool) error {
+	m.reloads++
+	return m.FakeManager.Reload(isEndpointsUpdate)
+}
+
+func TestUpdateEndpointsForVirtualServersWithFailover(t *testing.T) {
+	t.Parallel()
+
+	manager := &reloadCountingFakeManager{FakeManager: nginx.NewFakeManager("/etc/nginx")}
+	cnf := createTestConfiguratorWithManager(t, manager)
+	cnf.isPlus = true
+
+	vsEx := &VirtualServerEx{
+		VirtualServer: &conf_v1.VirtualServer{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "cafe",
+				Namespace: "default",
+			},
+			Spec: conf_v1.VirtualServerSpec{
+				Host: "cafe.example.com",
+				Upstreams: []conf_v1.Upstream{
+					{
+						Name:       "tea",
+						Service:    "tea-svc",
+						Port:       80,
+						Backup:     "backup-svc",
+						BackupPort: new(uint16(8090)),
+						Failover:   &conf_v1.UpstreamFailover{},
+					},
+				},
+				Routes: []conf_v1.Route{
+					{
+						Path:   "/tea",
+						Action: &conf_v1.Action{Pass: "tea"},
+					},
+				},
+			},
+		},
+		Endpoints: map[string][]string{
+			"default/tea-svc:80":      {"10.0.0.20:80", "10.0.0.21:80"},
+			"default/backup-svc:8090": {"backup.corp.local:8090"},
+		},
+	}
+
+	if _, err := cnf.AddOrUpdateVirtualServer(vsEx); err != nil {
+		t.Fatalf("AddOrUpdateVirtualServer() returned unexpected error: %v", err)
+	}
+	manager.reloads = 0
+
+	// the endpoints change without changing the number of the primary servers
+	vsEx.Endpoints["default/tea-svc:80"] = []string{"10.0.0.20:80", "10.0.0.22:80"}
+	if _, err := cnf.UpdateEndpointsForVirtualServers([]*VirtualServerEx{vsEx}); err != nil {
+		t.Fatalf("UpdateEndpointsForVirtualServers() returned unexpected error: %v", err)
+	}
+	if manager.reloads != 0 {
+		t.Errorf("UpdateEndpointsForVirtualServers() reloaded NGINX %d times but expected no reloads", manager.reloads)
+	}
+
+	// the number of the primary servers changes the next upstream tries
+	vsEx.Endpoints["default/tea-svc:80"] = []string{"10.0.0.20:80", "10.0.0.21:80", "10.0.0.22:80"}
+	if _, err := cnf.UpdateEndpointsForVirtualServers([]*VirtualServerEx{vsEx}); err != nil {
+		t.Fatalf("UpdateEndpointsForVirtualServers() returned unexpected error: %v", err)
+	}
+	if manager.reloads != 1 {
+		t.Errorf("UpdateEndpointsForVirtualServers() reloaded NGINX %d times but expected 1 reload", manager.reloads)
+	}
+}
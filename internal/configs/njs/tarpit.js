@@ -0,0 +1,10 @@
+function delay(r) {
+    const delay = Number(r.variables.tarpit_delay);
+    const code = Number(r.variables.tarpit_code);
+
+    setTimeout(function () {
+        r.return(code);
+    }, delay);
+}
+
+export default { delay };
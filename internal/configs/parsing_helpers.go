@@ -4,9 +4,11 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -150,16 +152,29 @@ func ParseLBMethodForPlus(method string) (string, error) {
 	return "", fmt.Errorf("invalid load balancing method: %q", method)
 }
 
+// hashKeyRegexp matches the key of the hash load balancing method: NGINX variables combined with text that contains
+// no whitespace, quotes or characters special to the NGINX configuration.
+var hashKeyRegexp = regexp.MustCompile(`^(\$[a-zA-Z_][a-zA-Z0-9_]*|\$\{[a-zA-Z_][a-zA-Z0-9_]*\}|[a-zA-Z0-9_.:/-])+$`)
+
+// validateHashLBMethod validates the hash load balancing method of the form "hash key [consistent]" and returns it
+// with the extra whitespace removed.
 func validateHashLBMethod(method string) (string, error) {
-	keyWords := strings.Split(method, " ")
+	keyWords := strings.Fields(method)
 
-	if keyWords[0] == "hash" {
-		if len(keyWords) == 2 || (len(keyWords) == 3 && keyWords[2] == "consistent") {
-			return method, nil
-		}
+	if len(keyWords) == 0 || keyWords[0] != "hash" || len(keyWords) > 3 {
+		return "", fmt.Errorf("invalid load balancing method: %q", method)
+	}
+	if len(keyWords) == 1 || keyWords[1] == "consistent" {
+		return "", fmt.Errorf("invalid load balancing method: %q: the hash key is missing", method)
+	}
+	if !hashKeyRegexp.MatchString(keyWords[1]) {
+		return "", fmt.Errorf("invalid load balancing method: %q: invalid hash key %q", method, keyWords[1])
+	}
+	if len(keyWords) == 3 && keyWords[2] != "consistent" {
+		return "", fmt.Errorf("invalid load balancing method: %q: the only supported hash parameter is 'consistent'", method)
 	}
 
-	return "", fmt.Errorf("invalid load balancing method: %q", method)
+	return strings.Join(keyWords, " "), nil
 }
 
 // ParseBool ensures that the string value is a valid bool
@@ -184,6 +199,16 @@ func ParseAddHeaderInherit(value string) (string, error) {
 	}
 }
 
+var errorLogLevels = []string{"debug", "info", "notice", "warn", "error", "crit", "alert", "emerg"}
+
+// ParseErrorLogLevel validates the level of the error_log directive.
+func ParseErrorLogLevel(value string) (string, error) {
+	if slices.Contains(errorLogLevels, value) {
+		return value, nil
+	}
+	return "", fmt.Errorf("must be one of: %s", strings.Join(errorLogLevels, ", "))
+}
+
 // ParseInt ensures that the string value is a valid int
 func ParseInt(s string) (int, error) {
 	return strconv.Atoi(s)
@@ -227,6 +252,20 @@ func ParseTime(s string) (string, error) {
 	return fmt.Sprintf("%s%s%s%s%s%s%s%s", years, months, weeks, days, hours, mins, secs, millis), nil
 }
 
+// ParseTimeDuration ensures that the string value is a valid time and converts it to a duration.
+// The time must not use units larger than hours (h).
+func ParseTimeDuration(s string) (time.Duration, error) {
+	t, err := ParseTime(s)
+	if err != nil {
+		return 0, err
+	}
+	d, err := time.ParseDuration(t)
+	if err != nil {
+		return 0, errors.New("invalid time string")
+	}
+	return d, nil
+}
+
 // OffsetFmt http://nginx.org/en/docs/syntax.html
 const OffsetFmt = `\d+[kKmMgG]?`
 
@@ -3,6 +3,7 @@ package configs
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	v1 "k8s.io/api/core/v1"
@@ -393,6 +394,8 @@ func TestParseLBMethod(t *testing.T) {
 		{"random two least_conn", "random two least_conn"},
 		{"hash $request_id", "hash $request_id"},
 		{"hash $request_id consistent", "hash $request_id consistent"},
+		{"hash $arg_key consistent", "hash $arg_key consistent"},
+		{"hash  ${arg_key}:$host   consistent", "hash ${arg_key}:$host consistent"},
 	}
 
 	invalidInput := []string{
@@ -400,7 +403,13 @@ func TestParseLBMethod(t *testing.T) {
 		"blabla",
 		"least_time header",
 		"hash123",
+		"hash",
+		"hash consistent",
 		"hash $request_id conwrongspelling",
+		"hash $arg_key consistent extra",
+		"hash $arg_key;",
+		"hash ${arg_key",
+		"hash \"$arg_key\"",
 		"random one",
 		"random two least_time=header",
 		"random two least_time=last_byte",
@@ -518,6 +527,38 @@ func TestParseTime(t *testing.T) {
 	}
 }
 
+func TestParseTimeDuration(t *testing.T) {
+	t.Parallel()
+	testsWithValidInput := []struct {
+		input    string
+		expected time.Duration
+	}{
+		{"1h30m 5 100ms", time.Hour + 30*time.Minute + 5*time.Second + 100*time.Millisecond},
+		{"500ms", 500 * time.Millisecond},
+		{"5", 5 * time.Second},
+		{"1m", time.Minute},
+	}
+	invalidInput := []string{"", "5d", "1w", "-5s", "ss"}
+
+	for _, test := range testsWithValidInput {
+		result, err := ParseTimeDuration(test.input)
+		if err != nil {
+			t.Fatalf("ParseTimeDuration(%q) returned an error for valid input", test.input)
+		}
+
+		if result != test.expected {
+			t.Errorf("ParseTimeDuration(%q) returned %v expected %v", test.input, result, test.expected)
+		}
+	}
+
+	for _, test := range invalidInput {
+		result, err := ParseTimeDuration(test)
+		if err == nil {
+			t.Errorf("ParseTimeDuration(%q) didn't return error. Returned: %v", test, result)
+		}
+	}
+}
+
 func TestParseOffset(t *testing.T) {
 	t.Parallel()
 	testsWithValidInput := []string{"1", "2k", "2K", "3m", "3M", "4g", "4G"}
@@ -728,6 +769,27 @@ func TestParseAddHeaderInherit(t *testing.T) {
 	}
 }
 
+func TestParseErrorLogLevel(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range errorLogLevels {
+		result, err := ParseErrorLogLevel(input)
+		if err != nil {
+			t.Fatalf("ParseErrorLogLevel(%q) returned an error for valid input", input)
+		}
+
+		if result != input {
+			t.Errorf("ParseErrorLogLevel(%q) returned %q expected %q", input, result, input)
+		}
+	}
+
+	for _, input := range []string{"", "DEBUG", "warning", "info;"} {
+		if _, err := ParseErrorLogLevel(input); err == nil {
+			t.Errorf("ParseErrorLogLevel(%q) does not return an error for invalid input", input)
+		}
+	}
+}
+
 func TestParseInt(t *testing.T) {
 	t.Parallel()
 	testsWithValidInput := []struct {
@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"net/url"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/nginx/kubernetes-ingress/internal/configs/version2"
@@ -26,10 +28,19 @@ import (
 // that handles sign-in redirect requests (e.g. oauth2-proxy expects /oauth2).
 const DefaultSigninRedirectBasePath = "/oauth2"
 
+// satisfyAny is the value of the satisfy field of AccessControl policies that allows requests
+// from allowed IP addresses to bypass authentication.
+const satisfyAny = "any"
+
+// rateLimitTierVariable is the variable with the tier of a tiered rate limit that a request falls into,
+// for use in custom log formats.
+const rateLimitTierVariable = "$rl_tier"
+
 // rateLimit hold the configuration for the ratelimiting Policy
 type rateLimit struct {
 	Reqs             []version2.LimitReq
 	Zones            []version2.LimitReqZone
+	SharedZones      []version2.LimitReqZone
 	GroupMaps        []version2.Map
 	PolicyGroupMaps  []version2.Map
 	Options          version2.LimitReqOptions
@@ -41,6 +52,8 @@ type jwtAuth struct {
 	Auth        *version2.JWTAuth
 	List        map[string]*version2.JWTAuth
 	JWKSEnabled bool
+	// ClaimMaps holds the maps that check the audience and issuer claims of the token.
+	ClaimMaps []version2.Map
 }
 
 type apiKeyClient struct {
@@ -50,16 +63,18 @@ type apiKeyClient struct {
 
 // apiKeyAuth hold the configuration for the APIKey Policy
 type apiKeyAuth struct {
-	Enabled   bool
-	Key       *version2.APIKey
-	Clients   []apiKeyClient
-	ClientMap map[string][]apiKeyClient
+	Enabled           bool
+	RejectCodeEnabled bool
+	Key               *version2.APIKey
+	Clients           []apiKeyClient
+	ClientMap         map[string][]apiKeyClient
 }
 
 type policiesCfg struct {
 	Allow           []string
 	Context         context.Context
 	Deny            []string
+	Satisfy         string
 	RateLimit       rateLimit
 	JWTAuth         jwtAuth
 	ExternalAuth    *version2.ExternalAuth
@@ -75,6 +90,8 @@ type policiesCfg struct {
 	HSTS            *version2.HSTS
 	ErrorReturn     *version2.Return
 	BundleValidator bundleValidator
+	// Variables holds the variables that the policies set for a request, so that they can be logged.
+	Variables []version2.Variable
 }
 
 type policyOwnerDetails struct {
@@ -94,6 +111,8 @@ type policyOptions struct {
 	defaultCABundle string
 	replicas        int
 	oidcPolicyName  string
+	// maxVerifyDepth is the maximum depth of the upstream certificate chain verification. Zero means no limit.
+	maxVerifyDepth int
 	// oidcConfig holds the already-built OIDC config from the first route or spec that defined
 	// this OIDC policy. It is reused by addOIDCConfig() when the same policy name is encountered
 	// on subsequent routes.
@@ -131,6 +150,9 @@ func (p *policiesCfg) addAccessControlConfig(accessControl *conf_v1.AccessContro
 			"AccessControl policy (or policies) with deny rules is overridden by policy (or policies) with allow rules",
 		)
 	}
+	if accessControl.Satisfy == satisfyAny {
+		p.Satisfy = satisfyAny
+	}
 	return res
 }
 
@@ -147,28 +169,42 @@ func (p *policiesCfg) addRateLimitConfig(
 	polKey := fmt.Sprintf("%v/%v", policy.Namespace, policy.Name)
 	l := nl.LoggerFromContext(p.Context)
 
-	rlZoneName := rfc1123ToSnake(fmt.Sprintf("pol_rl_%v_%v_%v_%v_%v", policy.Namespace, policy.Name, ownerDetails.parentNamespace, ownerDetails.parentName, ownerDetails.parentType))
-	if zoneSync {
-		rlZoneName = fmt.Sprintf("%v_sync", rlZoneName)
-	}
-	if rateLimit.Condition != nil {
+	rlZoneName := generateRateLimitZoneName(policy, ownerDetails, zoneSync)
+	if rateLimit.Shared {
+		lrz, warningText := generateLimitReqZone(rlZoneName, policy, podReplicas, zoneSync)
+		if warningText != "" {
+			nl.Warn(l, warningText)
+		}
+		sizeRateLimitZone(&lrz, rateLimit, polKey, res)
+		p.RateLimit.SharedZones = append(p.RateLimit.SharedZones, lrz)
+		res.addWarningf("RateLimit policy %s is shared: the limit is enforced together for all resources that reference the policy", polKey)
+	} else if rateLimit.Condition != nil {
 		lrz, warningText := generateGroupedLimitReqZone(rlZoneName, policy, podReplicas, ownerDetails, zoneSync, context, path)
 		if warningText != "" {
 			nl.Warn(l, warningText)
 		}
+		sizeRateLimitZone(&lrz, rateLimit, polKey, res)
 		p.RateLimit.PolicyGroupMaps = append(p.RateLimit.PolicyGroupMaps, *generateLRZPolicyGroupMap(lrz))
 		if rateLimit.Condition.JWT != nil && rateLimit.Condition.JWT.Claim != "" && rateLimit.Condition.JWT.Match != "" {
 			p.RateLimit.AuthJWTClaimSets = append(p.RateLimit.AuthJWTClaimSets, generateAuthJwtClaimSet(*rateLimit.Condition.JWT, ownerDetails))
 		}
 		p.RateLimit.Zones = append(p.RateLimit.Zones, lrz)
+		if !p.addVariable(rateLimitTierVariable, lrz.GroupVariable) {
+			res.addWarningf("RateLimit policy %s has a different condition than the first tiered RateLimit policy in this context: %s is only set to the tiers of the first condition", polKey, rateLimitTierVariable)
+		}
 	} else {
 		lrz, warningText := generateLimitReqZone(rlZoneName, policy, podReplicas, zoneSync)
 		if warningText != "" {
 			nl.Warn(l, warningText)
 		}
+		sizeRateLimitZone(&lrz, rateLimit, polKey, res)
 		p.RateLimit.Zones = append(p.RateLimit.Zones, lrz)
 	}
 
+	if rateLimit.Delay != nil && generateBool(rateLimit.NoDelay, false) {
+		res.addWarningf("RateLimit policy %s sets both noDelay and delay: noDelay takes precedence and the delay of %d is ignored", polKey, *rateLimit.Delay)
+	}
+
 	p.RateLimit.Reqs = append(p.RateLimit.Reqs, generateLimitReq(rlZoneName, rateLimit))
 	if len(p.RateLimit.Reqs) == 1 {
 		p.RateLimit.Options = generateLimitReqOptions(rateLimit)
@@ -187,12 +223,48 @@ func (p *policiesCfg) addRateLimitConfig(
 	return res
 }
 
+// addVariable sets the variable for a request, unless it is already set by another policy.
+// It returns false if another policy has already set the variable to a different value.
+func (p *policiesCfg) addVariable(name string, value string) bool {
+	for _, v := range p.Variables {
+		if v.Name == name {
+			return v.Value == value
+		}
+	}
+	p.Variables = append(p.Variables, version2.Variable{Name: name, Value: value})
+	return true
+}
+
+// generateRateLimitZoneName returns the name of the zone of a RateLimit policy. The name of a shared zone
+// doesn't include the owner of the policy reference, so that all owners use the same zone.
+func generateRateLimitZoneName(policy *conf_v1.Policy, ownerDetails policyOwnerDetails, zoneSync bool) string {
+	var rlZoneName string
+	if policy.Spec.RateLimit.Shared {
+		rlZoneName = rfc1123ToSnake(fmt.Sprintf("pol_rl_%v_%v_shared", policy.Namespace, policy.Name))
+	} else {
+		rlZoneName = rfc1123ToSnake(fmt.Sprintf("pol_rl_%v_%v_%v_%v_%v", policy.Namespace, policy.Name, ownerDetails.parentNamespace, ownerDetails.parentName, ownerDetails.parentType))
+	}
+	if zoneSync {
+		rlZoneName = fmt.Sprintf("%v_sync", rlZoneName)
+	}
+	return rlZoneName
+}
+
+// generateJWTToken returns the variable that contains the JWT, taken either from the token or the tokenCookie field.
+func generateJWTToken(jwtAuth *conf_v1.JWTAuth) string {
+	if jwtAuth.TokenCookie != "" {
+		return fmt.Sprintf("$cookie_%s", jwtAuth.TokenCookie)
+	}
+	return jwtAuth.Token
+}
+
 // nolint:gocyclo
 func (p *policiesCfg) addJWTAuthConfig(
 	jwtAuth *conf_v1.JWTAuth,
 	polKey string,
 	polNamespace string,
 	secretRefs map[string]*secrets.SecretReference,
+	ownerDetails policyOwnerDetails,
 ) *validationResults {
 	res := newValidationResults()
 	if p.JWTAuth.Auth != nil {
@@ -219,8 +291,9 @@ func (p *policiesCfg) addJWTAuthConfig(
 		p.JWTAuth.Auth = &version2.JWTAuth{
 			Secret: secretRef.Path,
 			Realm:  jwtAuth.Realm,
-			Token:  jwtAuth.Token,
+			Token:  generateJWTToken(jwtAuth),
 		}
+		p.addJWTClaimsConfig(jwtAuth, polKey, ownerDetails)
 		return res
 	} else if jwtAuth.JwksURI != "" {
 		uri, _ := url.Parse(jwtAuth.JwksURI)
@@ -273,21 +346,72 @@ func (p *policiesCfg) addJWTAuthConfig(
 			SSLVerify:      jwtAuth.SSLVerify,
 			TrustedCert:    trustedCertPath,
 			SSLVerifyDepth: sslVerifyDepth,
+			Resolvers:      jwtAuth.Resolvers,
 		}
 
 		p.JWTAuth.Auth = &version2.JWTAuth{
 			Key:      polKey,
 			JwksURI:  *JwksURI,
 			Realm:    jwtAuth.Realm,
-			Token:    jwtAuth.Token,
+			Token:    generateJWTToken(jwtAuth),
 			KeyCache: jwtAuth.KeyCache,
 		}
+		p.addJWTClaimsConfig(jwtAuth, polKey, ownerDetails)
 		p.JWTAuth.JWKSEnabled = true
 		return res
 	}
 	return res
 }
 
+// addJWTClaimsConfig configures the checks of the audience and issuer claims of the token.
+// Every claim is checked with a map, which evaluates to 1 on a match, and the token is rejected
+// unless all maps match.
+func (p *policiesCfg) addJWTClaimsConfig(jwtAuth *conf_v1.JWTAuth, polKey string, ownerDetails policyOwnerDetails) {
+	if len(jwtAuth.Audiences) > 0 {
+		params := []version2.Parameter{{Value: "default", Result: "0"}}
+		for _, aud := range jwtAuth.Audiences {
+			// the variable of an array claim holds the comma-separated values
+			params = append(params, version2.Parameter{
+				Value:  fmt.Sprintf(`"~(^|,)%s(,|$)"`, escapeNginxString(regexp.QuoteMeta(aud))),
+				Result: "1",
+			})
+		}
+		p.addJWTClaimMap("$jwt_claim_aud", generateJWTClaimVariableName(polKey, ownerDetails, "aud"), params)
+	}
+
+	if jwtAuth.Issuer != "" {
+		params := []version2.Parameter{
+			{Value: "default", Result: "0"},
+			// string keys of a map are compared case-insensitively, so the issuer is matched by a regexp
+			{Value: fmt.Sprintf(`"~^%s$"`, escapeNginxString(regexp.QuoteMeta(jwtAuth.Issuer))), Result: "1"},
+		}
+		p.addJWTClaimMap("$jwt_claim_iss", generateJWTClaimVariableName(polKey, ownerDetails, "iss"), params)
+	}
+}
+
+func (p *policiesCfg) addJWTClaimMap(source string, variable string, params []version2.Parameter) {
+	p.JWTAuth.ClaimMaps = append(p.JWTAuth.ClaimMaps, version2.Map{
+		Source:     source,
+		Variable:   variable,
+		Parameters: params,
+		Volatile:   true,
+	})
+	p.JWTAuth.Auth.Require = append(p.JWTAuth.Auth.Require, variable)
+}
+
+func generateJWTClaimVariableName(polKey string, ownerDetails policyOwnerDetails, claim string) string {
+	polNamespace, polName, _ := strings.Cut(polKey, "/")
+	return fmt.Sprintf(
+		"$jwt_%s_%s_%s_%s_%s_%s",
+		rfc1123ToSnake(ownerDetails.parentNamespace),
+		rfc1123ToSnake(ownerDetails.parentName),
+		rfc1123ToSnake(ownerDetails.parentType),
+		rfc1123ToSnake(polNamespace),
+		rfc1123ToSnake(polName),
+		claim,
+	)
+}
+
 func (p *policiesCfg) addExternalAuthConfig(
 	externalAuth *conf_v1.ExternalAuth,
 	polKey string,
@@ -422,6 +546,14 @@ func (p *policiesCfg) addBasicAuthConfig(
 		return res
 	}
 
+	if basicAuth.Secret == "" && len(basicAuth.Users) > 0 {
+		p.BasicAuth = &version2.BasicAuth{
+			Realm: basicAuth.Realm,
+			Users: generateHtpasswd(basicAuth.Users),
+		}
+		return res
+	}
+
 	basicSecretKey := fmt.Sprintf("%v/%v", polNamespace, basicAuth.Secret)
 	secretRef := secretRefs[basicSecretKey]
 	var secretType api_v1.SecretType
@@ -445,6 +577,15 @@ func (p *policiesCfg) addBasicAuthConfig(
 	return res
 }
 
+// generateHtpasswd generates the content of a htpasswd file for the users.
+func generateHtpasswd(users []conf_v1.BasicAuthUser) string {
+	var b strings.Builder
+	for _, u := range users {
+		fmt.Fprintf(&b, "%s:%s\n", u.Name, u.Hash)
+	}
+	return b.String()
+}
+
 func (p *policiesCfg) addIngressMTLSConfig(
 	ingressMTLS *conf_v1.IngressMTLS,
 	polKey string,
@@ -504,6 +645,9 @@ func (p *policiesCfg) addIngressMTLSConfig(
 	if _, hasCrlKey := secretRef.Secret.Data[CACrlKey]; hasCrlKey && ingressMTLS.CrlFileName != "" {
 		res.addWarningf("Both ca.crl in the Secret and ingressMTLS.crlFileName fields cannot be used. ca.crl in %s will be ignored and %s will be applied", secretKey, polKey)
 	}
+	if _, hasCrlKey := secretRef.Secret.Data[CACrlKey]; hasCrlKey && len(ingressMTLS.CrlFileNames) > 0 {
+		res.addWarningf("Both ca.crl in the Secret and ingressMTLS.crlFileNames fields cannot be used. ca.crl in %s will be ignored and %s will be applied", secretKey, polKey)
+	}
 
 	if ingressMTLS.CrlFileName != "" {
 		p.IngressMTLS = &version2.IngressMTLS{
@@ -512,6 +656,18 @@ func (p *policiesCfg) addIngressMTLSConfig(
 			VerifyClient: verifyClient,
 			VerifyDepth:  verifyDepth,
 		}
+	} else if len(ingressMTLS.CrlFileNames) > 0 {
+		var crlFiles []string
+		for _, f := range ingressMTLS.CrlFileNames {
+			crlFiles = append(crlFiles, fmt.Sprintf("%s/%s", DefaultSecretPath, f))
+		}
+		// The ClientCrl is set to the combined file when the VirtualServer config is generated
+		p.IngressMTLS = &version2.IngressMTLS{
+			ClientCert:     caFields[0],
+			ClientCrlFiles: crlFiles,
+			VerifyClient:   verifyClient,
+			VerifyDepth:    verifyDepth,
+		}
 	} else if _, hasCrlKey := secretRef.Secret.Data[CACrlKey]; hasCrlKey {
 		p.IngressMTLS = &version2.IngressMTLS{
 			ClientCert:   caFields[0],
@@ -534,6 +690,7 @@ func (p *policiesCfg) addEgressMTLSConfig(
 	polKey string,
 	polNamespace string,
 	secretRefs map[string]*secrets.SecretReference,
+	maxVerifyDepth int,
 ) *validationResults {
 	res := newValidationResults()
 	if p.EgressMTLS != nil {
@@ -606,21 +763,43 @@ func (p *policiesCfg) addEgressMTLSConfig(
 		trustedSecretPath = caFields[0]
 	}
 
+	verifyDepth := generateIntFromPointer(egressMTLS.VerifyDepth, 1)
+	if maxVerifyDepth > 0 && verifyDepth > maxVerifyDepth {
+		res.addWarningf(
+			"EgressMTLS policy %s verifyDepth %d exceeds the maximum of %d, the depth is clamped to %d",
+			polKey, verifyDepth, maxVerifyDepth, maxVerifyDepth,
+		)
+		verifyDepth = maxVerifyDepth
+	}
+
 	p.EgressMTLS = &version2.EgressMTLS{
 		Certificate:    tlsSecretPath,
 		CertificateKey: tlsSecretPath,
 		Ciphers:        generateString(egressMTLS.Ciphers, "DEFAULT"),
 		Protocols:      generateString(egressMTLS.Protocols, "TLSv1 TLSv1.1 TLSv1.2"),
 		VerifyServer:   egressMTLS.VerifyServer,
-		VerifyDepth:    generateIntFromPointer(egressMTLS.VerifyDepth, 1),
+		VerifyDepth:    verifyDepth,
 		SessionReuse:   generateBool(egressMTLS.SessionReuse, true),
-		ServerName:     egressMTLS.ServerName,
+		ServerName:     egressMTLS.ServerName || isVariableSSLName(egressMTLS.SSLName),
 		TrustedCert:    trustedSecretPath,
-		SSLName:        generateString(egressMTLS.SSLName, "$proxy_host"),
+		SSLName:        generateEgressMTLSSSLName(egressMTLS.SSLName),
 	}
 	return res
 }
 
+// isVariableSSLName checks whether the server name of an EgressMTLS policy is a variable of the request.
+func isVariableSSLName(sslName string) bool {
+	return strings.HasPrefix(sslName, "${")
+}
+
+// generateEgressMTLSSSLName returns the value of proxy_ssl_name for the server name of an EgressMTLS policy.
+func generateEgressMTLSSSLName(sslName string) string {
+	if isVariableSSLName(sslName) {
+		return "$" + strings.TrimSuffix(strings.TrimPrefix(sslName, "${"), "}")
+	}
+	return generateString(sslName, "$proxy_host")
+}
+
 // nolint:gocyclo
 func (p *policiesCfg) addOIDCConfig(
 	oidc *conf_v1.OIDC,
@@ -757,6 +936,7 @@ func (p *policiesCfg) addOIDCConfig(
 			VerifyDepth:           sslVerifyDepth,
 			CAFile:                trustedCertPath,
 			PolicyName:            polKey,
+			Resolvers:             oidc.Resolvers,
 		}
 	}
 
@@ -812,6 +992,10 @@ func (p *policiesCfg) addAPIKeyConfig(
 		Query:   apiKey.SuppliedIn.Query,
 		MapName: mapName,
 	}
+	if apiKey.RejectCode != nil {
+		p.APIKey.Key.RejectCode = *apiKey.RejectCode
+		p.APIKey.RejectCodeEnabled = true
+	}
 	p.APIKey.Enabled = true
 	return res
 }
@@ -939,6 +1123,10 @@ func (p *policiesCfg) addCacheConfig(
 		cache,
 		ownerDetails,
 	)
+
+	if slices.Contains(p.Cache.AllowedMethods, "POST") && !strings.Contains(p.Cache.CacheKey, "request_body") {
+		res.addWarningf("Cache policy %s caches the responses of POST requests, but the cache key %s doesn't include $request_body, so POST requests with different bodies share the cached responses", polKey, p.Cache.CacheKey)
+	}
 	return res
 }
 
@@ -1224,6 +1412,7 @@ func generatePolicies(
 	config := newPoliciesConfig(bundleValidator)
 	config.Context = ctx
 
+	refCounts := make(map[string]int)
 	for _, p := range policyRefs {
 		polNamespace := p.Namespace
 		if polNamespace == "" {
@@ -1232,6 +1421,15 @@ func generatePolicies(
 
 		key := fmt.Sprintf("%s/%s", polNamespace, p.Name)
 
+		// a policy referenced more than once in the same context is applied once, as applying it again would duplicate its directives
+		refCounts[key]++
+		if refCounts[key] > 1 {
+			if refCounts[key] == 2 {
+				warnings.AddWarningf(ownerDetails.owner, "Policy %s is referenced more than once, the duplicate references are ignored", key)
+			}
+			continue
+		}
+
 		if pol, exists := policies[key]; exists {
 			// Reject policy types that are not supported on Ingress resources.
 			// IsPolicySupportedOnIngress is the single source of truth for the allowlist.
@@ -1257,7 +1455,7 @@ func generatePolicies(
 					path,
 				)
 			case pol.Spec.JWTAuth != nil:
-				res = config.addJWTAuthConfig(pol.Spec.JWTAuth, key, polNamespace, policyOpts.secretRefs)
+				res = config.addJWTAuthConfig(pol.Spec.JWTAuth, key, polNamespace, policyOpts.secretRefs, ownerDetails)
 			case pol.Spec.ExternalAuth != nil:
 				res = config.addExternalAuthConfig(pol.Spec.ExternalAuth, key, polNamespace, p.Name, policyOpts.secretRefs, policyOpts, ownerDetails)
 			case pol.Spec.BasicAuth != nil:
@@ -1272,7 +1470,7 @@ func generatePolicies(
 					policyOpts.secretRefs,
 				)
 			case pol.Spec.EgressMTLS != nil:
-				res = config.addEgressMTLSConfig(pol.Spec.EgressMTLS, key, polNamespace, policyOpts.secretRefs)
+				res = config.addEgressMTLSConfig(pol.Spec.EgressMTLS, key, polNamespace, policyOpts.secretRefs, policyOpts.maxVerifyDepth)
 			case pol.Spec.OIDC != nil:
 				res = config.addOIDCConfig(pol.Spec.OIDC, key, polNamespace, policyOpts)
 			case pol.Spec.APIKey != nil:
@@ -1338,6 +1536,8 @@ func generateLimitReq(zoneName string, rateLimitPol *conf_v1.RateLimit) version2
 
 	if rateLimitPol.Burst != nil {
 		limitReq.Burst = *rateLimitPol.Burst
+	} else if rateLimitPol.BurstFactor != nil {
+		limitReq.Burst = burstFromRate(rateLimitPol.Rate, *rateLimitPol.BurstFactor)
 	}
 	if rateLimitPol.Delay != nil {
 		limitReq.Delay = *rateLimitPol.Delay
@@ -1351,6 +1551,104 @@ func generateLimitReq(zoneName string, rateLimitPol *conf_v1.RateLimit) version2
 	return limitReq
 }
 
+// burstFromRate computes an absolute burst as a multiple of the number of requests allowed per rate window.
+// For example, a rate of 10r/s with a factor of 2 results in a burst of 20.
+func burstFromRate(rate string, factor int) int {
+	match := rateRegexp.FindStringSubmatch(strings.ToLower(rate))
+	if match == nil {
+		return 0
+	}
+
+	number, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0
+	}
+
+	return number * factor
+}
+
+// generateRateLimitKey returns the key of the rate limit, concatenating the keys
+// into a composite key when more than one is set.
+func generateRateLimitKey(rateLimit *conf_v1.RateLimit) string {
+	if len(rateLimit.Keys) > 0 {
+		return strings.Join(rateLimit.Keys, "")
+	}
+	return rateLimit.Key
+}
+
+// rateLimitZoneExpectedKeys is the number of the distinct keys, for example, clients, that the zone of a rate limit
+// is expected to keep. When the zone is full, NGINX removes the least recently used states, which weakens the limit.
+const rateLimitZoneExpectedKeys = 8192
+
+// rateLimitKeyVariableRegexp matches the variables of the key of a rate limit, for example, ${binary_remote_addr}.
+var rateLimitKeyVariableRegexp = regexp.MustCompile(`\$\{?([a-zA-Z0-9_]+)\}?`)
+
+// estimateRateLimitZoneSize returns the size in bytes of the zone recommended for the key of a rate limit,
+// or 0 when the key has no variables other than $request_method, as the number of such keys is small.
+func estimateRateLimitZoneSize(key string) uint64 {
+	keyLength := len(rateLimitKeyVariableRegexp.ReplaceAllString(key, ""))
+	highCardinality := false
+	for _, match := range rateLimitKeyVariableRegexp.FindAllStringSubmatch(key, -1) {
+		switch match[1] {
+		case "request_method":
+			keyLength += len("OPTIONS")
+		case "binary_remote_addr":
+			// the size of an IPv6 address
+			keyLength += 16
+			highCardinality = true
+		default:
+			keyLength += 64
+			highCardinality = true
+		}
+	}
+	if !highCardinality {
+		return 0
+	}
+
+	// a state keeps the key after a node of about 80 bytes and is allocated in a slab of the size of a power of two
+	return rateLimitZoneExpectedKeys * nextPowerOfTwo(uint64(80+keyLength))
+}
+
+// parseZoneSize returns the size of a zone in bytes, or 0 when the size is invalid.
+func parseZoneSize(size string) uint64 {
+	size = strings.ToLower(size)
+	multiplier := uint64(1)
+	if strings.HasSuffix(size, "k") {
+		multiplier = 1 << 10
+	} else if strings.HasSuffix(size, "m") {
+		multiplier = 1 << 20
+	}
+	number, err := strconv.ParseUint(strings.TrimRight(size, "km"), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return number * multiplier
+}
+
+// formatZoneSize returns the size of a zone in megabytes, or in kilobytes, rounded up, when the size isn't a multiple of a megabyte.
+func formatZoneSize(size uint64) string {
+	if size%(1<<20) == 0 {
+		return fmt.Sprintf("%dm", size>>20)
+	}
+	return fmt.Sprintf("%dk", (size+(1<<10)-1)>>10)
+}
+
+// sizeRateLimitZone warns when the zone of a rate limit is smaller than the size recommended for its key,
+// or increases the size of the zone to the recommended size, when autoZoneSize is enabled.
+func sizeRateLimitZone(lrz *version2.LimitReqZone, rateLimit *conf_v1.RateLimit, polKey string, res *validationResults) {
+	key := generateRateLimitKey(rateLimit)
+	recommended := estimateRateLimitZoneSize(key)
+	if recommended == 0 || parseZoneSize(lrz.ZoneSize) >= recommended {
+		return
+	}
+
+	if rateLimit.AutoZoneSize {
+		lrz.ZoneSize = formatZoneSize(recommended)
+		return
+	}
+	res.addWarningf("RateLimit policy %s: the zoneSize %s might be too small for the key %s, the recommended size for %d keys is %s, or enable autoZoneSize", polKey, lrz.ZoneSize, key, rateLimitZoneExpectedKeys, formatZoneSize(recommended))
+}
+
 func generateLimitReqZone(zoneName string, policy *conf_v1.Policy, podReplicas int, zoneSync bool) (version2.LimitReqZone, string) {
 	rateLimitPol := policy.Spec.RateLimit
 	rate := rateLimitPol.Rate
@@ -1365,7 +1663,7 @@ func generateLimitReqZone(zoneName string, policy *conf_v1.Policy, podReplicas i
 	}
 	return version2.LimitReqZone{
 		ZoneName: zoneName,
-		Key:      rateLimitPol.Key,
+		Key:      generateRateLimitKey(rateLimitPol),
 		ZoneSize: rateLimitPol.ZoneSize,
 		Rate:     rate,
 		Sync:     zoneSync,
@@ -1394,7 +1692,7 @@ func generateGroupedLimitReqZone(
 	}
 	lrz := version2.LimitReqZone{
 		ZoneName: zoneName,
-		Key:      rateLimitPol.Key,
+		Key:      generateRateLimitKey(rateLimitPol),
 		ZoneSize: rateLimitPol.ZoneSize,
 		Rate:     rate,
 		Sync:     zoneSync,
@@ -1426,7 +1724,7 @@ func generateGroupedLimitReqZone(
 			encPath,
 		))
 		lrz.Key = rfc1123ToSnake(fmt.Sprintf("$%s", zoneName))
-		lrz.PolicyResult = rateLimitPol.Key
+		lrz.PolicyResult = generateRateLimitKey(rateLimitPol)
 		lrz.GroupDefault = rateLimitPol.Condition.Default
 		lrz.GroupSource = generateAuthJwtClaimSetVariable(rateLimitPol.Condition.JWT.Claim, ownerDetails)
 	}
@@ -1451,7 +1749,7 @@ func generateGroupedLimitReqZone(
 			encPath,
 		))
 		lrz.Key = rfc1123ToSnake(fmt.Sprintf("$%s", zoneName))
-		lrz.PolicyResult = rateLimitPol.Key
+		lrz.PolicyResult = generateRateLimitKey(rateLimitPol)
 		lrz.GroupDefault = rateLimitPol.Condition.Default
 		lrz.GroupSource = variable.Name
 	}
@@ -1473,6 +1771,7 @@ func generateLRZGroupMaps(rlzs []version2.LimitReqZone) map[string]*version2.Map
 						Result: lrz.PolicyValue,
 					},
 				},
+				Volatile: isAuthJwtClaimSetVariable(lrz.GroupSource),
 			}
 			if lrz.GroupDefault {
 				s.Parameters = append(s.Parameters, version2.Parameter{
@@ -1507,6 +1806,7 @@ func generateLRZPolicyGroupMap(lrz version2.LimitReqZone) *version2.Map {
 		Source:     lrz.GroupVariable,
 		Variable:   fmt.Sprintf("$%s", rfc1123ToSnake(lrz.ZoneName)),
 		Parameters: params,
+		Volatile:   isAuthJwtClaimSetVariable(lrz.GroupSource),
 	}
 }
 
@@ -1539,6 +1839,12 @@ func generateAuthJwtClaimSetVariable(claim string, ownerDetails policyOwnerDetai
 	)
 }
 
+// isAuthJwtClaimSetVariable checks if the variable is set from a claim of the JWT by auth_jwt_claim_set, which is only
+// resolved once the token is validated, so that the maps with the variable as the source must be volatile.
+func isAuthJwtClaimSetVariable(variable string) bool {
+	return strings.HasPrefix(variable, "$jwt_")
+}
+
 func generateAuthJwtClaimSetClaim(claim string) string {
 	return strings.Join(strings.Split(claim, "."), " ")
 }
@@ -1587,9 +1893,9 @@ func generateCacheConfig(cache *conf_v1.Cache, ownerDetails policyOwnerDetails)
 		CacheMinUses:          cache.CacheMinUses,
 	}
 
-	// Map lock fields
-	if cache.Lock != nil {
-		cacheConfig.CacheLock = cache.Lock.Enable
+	// Map lock fields. The timeout and age have no effect without the lock.
+	if cache.Lock != nil && cache.Lock.Enable {
+		cacheConfig.CacheLock = true
 		cacheConfig.CacheLockTimeout = cache.Lock.Timeout
 		cacheConfig.CacheLockAge = cache.Lock.Age
 	}
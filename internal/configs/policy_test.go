@@ -497,6 +497,9 @@ func TestGeneratePolicies(t *testing.T) {
 						},
 					},
 				},
+				Variables: []version2.Variable{
+					{Name: "$rl_tier", Value: "$rl_default_test_vs_variable_apikey_client_name_route_L2NvZmZlZQ"},
+				},
 			},
 			msg: "tiered rate limits",
 		},
@@ -533,6 +536,66 @@ func TestGeneratePolicies(t *testing.T) {
 			},
 			msg: "jwt reference",
 		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "jwt-policy-claims",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/jwt-policy-claims": {
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "jwt-policy-claims",
+						Namespace: "default",
+					},
+					Spec: conf_v1.PolicySpec{
+						JWTAuth: &conf_v1.JWTAuth{
+							Realm:     "My Test API",
+							Secret:    "jwt-secret",
+							Audiences: []string{"my-api", "api.example.com"},
+							Issuer:    "https://idp.example.com",
+						},
+					},
+				},
+			},
+			expected: policiesCfg{
+				Context: ctx,
+				JWTAuth: jwtAuth{
+					Auth: &version2.JWTAuth{
+						Secret: "/etc/nginx/secrets/default-jwt-secret",
+						Realm:  "My Test API",
+						Require: []string{
+							"$jwt_default_test_vs_default_jwt_policy_claims_aud",
+							"$jwt_default_test_vs_default_jwt_policy_claims_iss",
+						},
+					},
+					ClaimMaps: []version2.Map{
+						{
+							Source:   "$jwt_claim_aud",
+							Variable: "$jwt_default_test_vs_default_jwt_policy_claims_aud",
+							Parameters: []version2.Parameter{
+								{Value: "default", Result: "0"},
+								{Value: `"~(^|,)my-api(,|$)"`, Result: "1"},
+								{Value: `"~(^|,)api\\.example\\.com(,|$)"`, Result: "1"},
+							},
+							Volatile: true,
+						},
+						{
+							Source:   "$jwt_claim_iss",
+							Variable: "$jwt_default_test_vs_default_jwt_policy_claims_iss",
+							Parameters: []version2.Parameter{
+								{Value: "default", Result: "0"},
+								{Value: `"~^https://idp\\.example\\.com$"`, Result: "1"},
+							},
+							Volatile: true,
+						},
+					},
+					JWKSEnabled: false,
+				},
+			},
+			msg: "jwt reference with audiences and issuer",
+		},
 		{
 			policyRefs: []conf_v1.PolicyReference{
 				{
@@ -655,6 +718,83 @@ func TestGeneratePolicies(t *testing.T) {
 			},
 			msg: "basic auth reference",
 		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "allow-policy",
+					Namespace: "default",
+				},
+				{
+					Name:      "basic-auth-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/allow-policy": {
+					Spec: conf_v1.PolicySpec{
+						AccessControl: &conf_v1.AccessControl{
+							Allow:   []string{"10.0.0.0/8"},
+							Satisfy: "any",
+						},
+					},
+				},
+				"default/basic-auth-policy": {
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "basic-auth-policy",
+						Namespace: "default",
+					},
+					Spec: conf_v1.PolicySpec{
+						BasicAuth: &conf_v1.BasicAuth{
+							Realm:  "My Test API",
+							Secret: "htpasswd-secret",
+						},
+					},
+				},
+			},
+			expected: policiesCfg{
+				Allow:   []string{"10.0.0.0/8"},
+				Satisfy: "any",
+				Context: ctx,
+				BasicAuth: &version2.BasicAuth{
+					Secret: "/etc/nginx/secrets/default-htpasswd-secret",
+					Realm:  "My Test API",
+				},
+			},
+			msg: "access control with satisfy any and basic auth reference",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "basic-auth-users-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/basic-auth-users-policy": {
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "basic-auth-users-policy",
+						Namespace: "default",
+					},
+					Spec: conf_v1.PolicySpec{
+						BasicAuth: &conf_v1.BasicAuth{
+							Realm: "My Test API",
+							Users: []conf_v1.BasicAuthUser{
+								{Name: "alice", Hash: "$apr1$abcdefgh$h9FWgUz3n9YxylKLlR5SQ/"},
+								{Name: "bob", Hash: "{SHA}5en6G6MezRroT3XKqkdPOmY/BfQ="},
+							},
+						},
+					},
+				},
+			},
+			expected: policiesCfg{
+				Context: ctx,
+				BasicAuth: &version2.BasicAuth{
+					Realm: "My Test API",
+					Users: "alice:$apr1$abcdefgh$h9FWgUz3n9YxylKLlR5SQ/\nbob:{SHA}5en6G6MezRroT3XKqkdPOmY/BfQ=\n",
+				},
+			},
+			msg: "basic auth with users",
+		},
 		{
 			policyRefs: []conf_v1.PolicyReference{
 				{
@@ -754,6 +894,40 @@ func TestGeneratePolicies(t *testing.T) {
 			},
 			msg: "ingressMTLS reference with crl field in policy",
 		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "ingress-mtls-policy-crls",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/ingress-mtls-policy-crls": {
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "ingress-mtls-policy-crls",
+						Namespace: "default",
+					},
+					Spec: conf_v1.PolicySpec{
+						IngressMTLS: &conf_v1.IngressMTLS{
+							ClientCertSecret: "ingress-mtls-secret",
+							CrlFileNames:     []string{"root-ca.crl", "intermediate-ca.crl"},
+							VerifyClient:     "off",
+						},
+					},
+				},
+			},
+			context: "spec",
+			expected: policiesCfg{
+				Context: ctx,
+				IngressMTLS: &version2.IngressMTLS{
+					ClientCert:     mTLSCertPath,
+					ClientCrlFiles: []string{"/etc/nginx/secrets/root-ca.crl", "/etc/nginx/secrets/intermediate-ca.crl"},
+					VerifyClient:   "off",
+					VerifyDepth:    1,
+				},
+			},
+			msg: "ingressMTLS reference with multiple crl files in policy",
+		},
 		{
 			policyRefs: []conf_v1.PolicyReference{
 				{
@@ -828,6 +1002,39 @@ func TestGeneratePolicies(t *testing.T) {
 			},
 			msg: "egressMTLS with crt and crl",
 		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "egress-mtls-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/egress-mtls-policy": {
+					Spec: conf_v1.PolicySpec{
+						EgressMTLS: &conf_v1.EgressMTLS{
+							TLSSecret: "egress-mtls-secret",
+							SSLName:   "${http_x_tenant}",
+						},
+					},
+				},
+			},
+			context: "route",
+			expected: policiesCfg{
+				Context: ctx,
+				EgressMTLS: &version2.EgressMTLS{
+					Certificate:    "/etc/nginx/secrets/default-egress-mtls-secret",
+					CertificateKey: "/etc/nginx/secrets/default-egress-mtls-secret",
+					Ciphers:        "DEFAULT",
+					Protocols:      "TLSv1 TLSv1.1 TLSv1.2",
+					ServerName:     true,
+					SessionReuse:   true,
+					VerifyDepth:    1,
+					SSLName:        "$http_x_tenant",
+				},
+			},
+			msg: "egressMTLS with ssl name from a request variable",
+		},
 		{
 			policyRefs: []conf_v1.PolicyReference{
 				{
@@ -1000,6 +1207,51 @@ func TestGeneratePolicies(t *testing.T) {
 			},
 			msg: "api key same secrets for different policies",
 		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "api-key-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/api-key-policy": {
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "api-key-policy",
+						Namespace: "default",
+					},
+					Spec: conf_v1.PolicySpec{
+						APIKey: &conf_v1.APIKey{
+							SuppliedIn: &conf_v1.SuppliedIn{
+								Header: []string{"X-API-Key"},
+							},
+							ClientSecret: "api-key-secret",
+							RejectCode:   new(429),
+						},
+					},
+				},
+			},
+			expected: policiesCfg{
+				Context: ctx,
+				APIKey: apiKeyAuth{
+					Key: &version2.APIKey{
+						Header:     []string{"X-API-Key"},
+						MapName:    "apikey_auth_client_name_default_test_vs_api_key_policy",
+						RejectCode: 429,
+					},
+					Enabled:           true,
+					RejectCodeEnabled: true,
+					ClientMap:         nil,
+					Clients: []apiKeyClient{
+						{
+							ClientID:  "client1",
+							HashedKey: "5e884898da28047151d0e56f8dc6292773603d0d6aabbdd62a11ef721d1542d8",
+						},
+					},
+				},
+			},
+			msg: "api key with custom reject code",
+		},
 		{
 			policyRefs: []conf_v1.PolicyReference{
 				{
@@ -1098,7 +1350,7 @@ func TestGeneratePolicies(t *testing.T) {
 								Sleep:     "50ms",
 								Threshold: "150ms",
 							},
-							CacheKey:              "$scheme$proxy_host$request_uri$is_args$args",
+							CacheKey:              "$scheme$proxy_host$request_uri$is_args$args$request_body",
 							CacheUseStale:         []string{"error", "timeout", "invalid_header", "updating", "http_500", "http_502", "http_503"},
 							CacheRevalidate:       true,
 							CacheBackgroundUpdate: true,
@@ -1134,7 +1386,7 @@ func TestGeneratePolicies(t *testing.T) {
 					ManagerFiles:          &[]int{1000}[0],
 					ManagerSleep:          "50ms",
 					ManagerThreshold:      "150ms",
-					CacheKey:              "$scheme$proxy_host$request_uri$is_args$args",
+					CacheKey:              "$scheme$proxy_host$request_uri$is_args$args$request_body",
 					CacheUseStale:         []string{"error", "timeout", "invalid_header", "updating", "http_500", "http_502", "http_503"},
 					CacheRevalidate:       true,
 					CacheBackgroundUpdate: true,
@@ -1317,45 +1569,241 @@ func TestGeneratePolicies(t *testing.T) {
 	}
 }
 
-func TestAddCORSConfig(t *testing.T) {
+func TestGeneratePolicies_IgnoresDuplicateReferences(t *testing.T) {
 	t.Parallel()
+	ctx := context.Background()
+	ownerDetails := policyOwnerDetails{
+		owner:           nil, // nil is OK for the unit test
+		ownerNamespace:  "default",
+		parentNamespace: "default",
+		parentName:      "test",
+		ownerName:       "test",
+		parentType:      "vs",
+	}
 
 	tests := []struct {
-		name     string
-		cors     *conf_v1.CORS
-		expected policiesCfg
+		policyRefs       []conf_v1.PolicyReference
+		policies         map[string]*conf_v1.Policy
+		expected         policiesCfg
+		expectedWarnings []string
+		msg              string
 	}{
 		{
-			name: "single origin CORS",
-			cors: &conf_v1.CORS{
-				AllowOrigin:  []string{"https://example.com"},
-				AllowMethods: []string{"GET", "POST"},
-				AllowHeaders: []string{"Content-Type", "Authorization"},
-				MaxAge:       new(3600),
-			},
-			expected: policiesCfg{
-				CORSHeaders: []version2.AddHeader{
-					{Header: version2.Header{Name: "Vary", Value: "Origin"}, Always: true},
-					{Header: version2.Header{Name: "Access-Control-Allow-Origin", Value: "https://example.com"}, Always: true},
-					{Header: version2.Header{Name: "Access-Control-Allow-Methods", Value: "GET, POST"}, Always: true},
-					{Header: version2.Header{Name: "Access-Control-Allow-Headers", Value: "Content-Type, Authorization"}, Always: true},
-					{Header: version2.Header{Name: "Access-Control-Max-Age", Value: "3600"}, Always: true},
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "rateLimit-policy",
+					Namespace: "default",
+				},
+				{
+					Name: "rateLimit-policy",
+				},
+				{
+					Name:      "rateLimit-policy",
+					Namespace: "default",
 				},
-				CORSMap: nil,
-			},
-		},
-		{
-			name: "wildcard CORS without credentials",
-			cors: &conf_v1.CORS{
-				AllowOrigin:  []string{"*"},
-				AllowMethods: []string{"GET", "POST", "OPTIONS"},
 			},
-			expected: policiesCfg{
-				CORSHeaders: []version2.AddHeader{
-					{Header: version2.Header{Name: "Access-Control-Allow-Origin", Value: "*"}, Always: true},
-					{Header: version2.Header{Name: "Access-Control-Allow-Methods", Value: "GET, POST, OPTIONS"}, Always: true},
+			policies: map[string]*conf_v1.Policy{
+				"default/rateLimit-policy": {
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "rateLimit-policy",
+						Namespace: "default",
+					},
+					Spec: conf_v1.PolicySpec{
+						RateLimit: &conf_v1.RateLimit{
+							Key:      "test",
+							ZoneSize: "10M",
+							Rate:     "10r/s",
+						},
+					},
 				},
-				CORSMap: nil,
+			},
+			expected: policiesCfg{
+				RateLimit: rateLimit{
+					Zones: []version2.LimitReqZone{
+						{
+							Key:      "test",
+							ZoneSize: "10M",
+							Rate:     "10r/s",
+							ZoneName: "pol_rl_default_rateLimit_policy_default_test_vs",
+						},
+					},
+					Options: version2.LimitReqOptions{
+						LogLevel:   "error",
+						RejectCode: 503,
+					},
+					Reqs: []version2.LimitReq{
+						{
+							ZoneName: "pol_rl_default_rateLimit_policy_default_test_vs",
+						},
+					},
+				},
+			},
+			expectedWarnings: []string{
+				"Policy default/rateLimit-policy is referenced more than once, the duplicate references are ignored",
+			},
+			msg: "duplicate rate limit references",
+		},
+		{
+			policyRefs: []conf_v1.PolicyReference{
+				{
+					Name:      "allow-policy",
+					Namespace: "default",
+				},
+				{
+					Name:      "allow-policy",
+					Namespace: "default",
+				},
+			},
+			policies: map[string]*conf_v1.Policy{
+				"default/allow-policy": {
+					Spec: conf_v1.PolicySpec{
+						AccessControl: &conf_v1.AccessControl{
+							Allow: []string{"127.0.0.1"},
+						},
+					},
+				},
+			},
+			expected: policiesCfg{
+				Allow: []string{"127.0.0.1"},
+			},
+			expectedWarnings: []string{
+				"Policy default/allow-policy is referenced more than once, the duplicate references are ignored",
+			},
+			msg: "duplicate access control references",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.msg, func(t *testing.T) {
+			t.Parallel()
+			result, warnings := generatePolicies(ctx, ownerDetails, tc.policyRefs, tc.policies, specContext, "/", policyOptions{replicas: 1}, &fakeBV)
+
+			result.BundleValidator = nil
+
+			if diff := cmp.Diff(tc.expected, result, cmpopts.IgnoreFields(policiesCfg{}, "Context")); diff != "" {
+				t.Errorf("generatePolicies() returned unexpected result (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedWarnings, warnings[nil]); diff != "" {
+				t.Errorf("generatePolicies() returned unexpected warnings (-want +got):\n%s", diff)
+			}
+
+			// the duplicates are detected per context, so the references in another context are deduplicated on their own
+			routeResult, routeWarnings := generatePolicies(ctx, ownerDetails, tc.policyRefs, tc.policies, routeContext, "/tea", policyOptions{replicas: 1}, &fakeBV)
+			if diff := cmp.Diff(tc.expectedWarnings, routeWarnings[nil]); diff != "" {
+				t.Errorf("generatePolicies() returned unexpected warnings for the route context (-want +got):\n%s", diff)
+			}
+			if routeResult.ErrorReturn != nil {
+				t.Errorf("generatePolicies() returned unexpected error return %v for the route context", routeResult.ErrorReturn)
+			}
+		})
+	}
+}
+
+func TestAddEgressMTLSConfigVerifyDepth(t *testing.T) {
+	t.Parallel()
+	secretRefs := map[string]*secrets.SecretReference{
+		"default/egress-mtls-secret": {
+			Secret: &api_v1.Secret{
+				Type: api_v1.SecretTypeTLS,
+			},
+			Path: "/etc/nginx/secrets/default-egress-mtls-secret",
+		},
+	}
+
+	tests := []struct {
+		verifyDepth  *int
+		want         int
+		wantWarnings []string
+		msg          string
+	}{
+		{
+			verifyDepth: nil,
+			want:        1,
+			msg:         "default depth",
+		},
+		{
+			verifyDepth: new(3),
+			want:        3,
+			msg:         "depth within the maximum",
+		},
+		{
+			verifyDepth: new(10),
+			want:        10,
+			msg:         "depth equal to the maximum",
+		},
+		{
+			verifyDepth: new(100),
+			want:        10,
+			wantWarnings: []string{
+				"EgressMTLS policy default/egress-mtls-policy verifyDepth 100 exceeds the maximum of 10, the depth is clamped to 10",
+			},
+			msg: "depth clamped to the maximum",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.msg, func(t *testing.T) {
+			t.Parallel()
+			egressMTLS := &conf_v1.EgressMTLS{
+				TLSSecret:   "egress-mtls-secret",
+				VerifyDepth: tc.verifyDepth,
+			}
+
+			cfg := newPoliciesConfig(nil)
+			res := cfg.addEgressMTLSConfig(egressMTLS, "default/egress-mtls-policy", "default", secretRefs, defaultUpstreamSSLMaxVerifyDepth)
+
+			if res.isError {
+				t.Fatalf("addEgressMTLSConfig() returned an error for the case of %s", tc.msg)
+			}
+			if cfg.EgressMTLS.VerifyDepth != tc.want {
+				t.Errorf("addEgressMTLSConfig() VerifyDepth = %d, want %d", cfg.EgressMTLS.VerifyDepth, tc.want)
+			}
+			if diff := cmp.Diff(tc.wantWarnings, res.warnings); diff != "" {
+				t.Errorf("addEgressMTLSConfig() warnings mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAddCORSConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		cors     *conf_v1.CORS
+		expected policiesCfg
+	}{
+		{
+			name: "single origin CORS",
+			cors: &conf_v1.CORS{
+				AllowOrigin:  []string{"https://example.com"},
+				AllowMethods: []string{"GET", "POST"},
+				AllowHeaders: []string{"Content-Type", "Authorization"},
+				MaxAge:       new(3600),
+			},
+			expected: policiesCfg{
+				CORSHeaders: []version2.AddHeader{
+					{Header: version2.Header{Name: "Vary", Value: "Origin"}, Always: true},
+					{Header: version2.Header{Name: "Access-Control-Allow-Origin", Value: "https://example.com"}, Always: true},
+					{Header: version2.Header{Name: "Access-Control-Allow-Methods", Value: "GET, POST"}, Always: true},
+					{Header: version2.Header{Name: "Access-Control-Allow-Headers", Value: "Content-Type, Authorization"}, Always: true},
+					{Header: version2.Header{Name: "Access-Control-Max-Age", Value: "3600"}, Always: true},
+				},
+				CORSMap: nil,
+			},
+		},
+		{
+			name: "wildcard CORS without credentials",
+			cors: &conf_v1.CORS{
+				AllowOrigin:  []string{"*"},
+				AllowMethods: []string{"GET", "POST", "OPTIONS"},
+			},
+			expected: policiesCfg{
+				CORSHeaders: []version2.AddHeader{
+					{Header: version2.Header{Name: "Access-Control-Allow-Origin", Value: "*"}, Always: true},
+					{Header: version2.Header{Name: "Access-Control-Allow-Methods", Value: "GET, POST, OPTIONS"}, Always: true},
+				},
+				CORSMap: nil,
 			},
 		},
 		{
@@ -4676,6 +5124,7 @@ func TestGenerateLRZPolicyGroupMap(t *testing.T) {
 				PolicyValue:   "rl_vsnamespace_vsname_match_gold",
 				GroupVariable: "$rl_vsnamespace_vsname_group_sub_spec",
 				PolicyResult:  "$jwt_claim_sub",
+				GroupSource:   "$jwt_vsnamespace_vsname_sub",
 			},
 			expected: &version2.Map{
 				Source:   "$rl_vsnamespace_vsname_group_sub_spec",
@@ -4690,6 +5139,31 @@ func TestGenerateLRZPolicyGroupMap(t *testing.T) {
 						Result: "Val$jwt_claim_sub",
 					},
 				},
+				Volatile: true,
+			},
+		},
+		{
+			lrz: version2.LimitReqZone{
+				ZoneName:      "pol_rl_polnamespace_my-zone_vsnamespace_vsname",
+				Key:           "$pol_rl_polnamespace_my_zone_vsnamespace_vsname",
+				PolicyValue:   "rl_vsnamespace_vsname_match_my_zone",
+				GroupVariable: "$rl_vsnamespace_vsname_variable_request_method_spec",
+				PolicyResult:  "$binary_remote_addr",
+				GroupSource:   "$request_method",
+			},
+			expected: &version2.Map{
+				Source:   "$rl_vsnamespace_vsname_variable_request_method_spec",
+				Variable: "$pol_rl_polnamespace_my_zone_vsnamespace_vsname",
+				Parameters: []version2.Parameter{
+					{
+						Value:  "default",
+						Result: "''",
+					},
+					{
+						Value:  "rl_vsnamespace_vsname_match_my_zone",
+						Result: "Val$binary_remote_addr",
+					},
+				},
 			},
 		},
 	}
@@ -4761,6 +5235,7 @@ func TestGenerateLRZGroupMaps(t *testing.T) {
 							Result: "rl_vsnamespace_vsname_match_bronze",
 						},
 					},
+					Volatile: true,
 				},
 			},
 		},
@@ -4845,6 +5320,7 @@ func TestGenerateLRZGroupMaps(t *testing.T) {
 							Result: "rl_vsnamespace_vsname_match_bronze",
 						},
 					},
+					Volatile: true,
 				},
 				"$rl_vsnamespace_vsname_group_sub_subroute": {
 					Source:   "$jwt_vsnamespace_vsname_sub",
@@ -4867,6 +5343,7 @@ func TestGenerateLRZGroupMaps(t *testing.T) {
 							Result: "rl_vsnamespace_vsname_match_bronze",
 						},
 					},
+					Volatile: true,
 				},
 			},
 		},
@@ -4910,6 +5387,7 @@ func TestGenerateLRZGroupMaps(t *testing.T) {
 							Result: "rl_vsnamespace_vsname_match_basic",
 						},
 					},
+					Volatile: true,
 				},
 			},
 		},
@@ -4948,6 +5426,577 @@ func TestRFC1123ToSnake(t *testing.T) {
 	}
 }
 
+func TestGenerateRateLimitZoneName(t *testing.T) {
+	t.Parallel()
+	ownerDetails := policyOwnerDetails{
+		parentNamespace: "default",
+		parentName:      "cafe",
+		parentType:      "vs",
+	}
+	tests := []struct {
+		name     string
+		shared   bool
+		zoneSync bool
+		expected string
+	}{
+		{
+			name:     "isolated",
+			expected: "pol_rl_default_rate_limit_policy_default_cafe_vs",
+		},
+		{
+			name:     "isolated with zone sync",
+			zoneSync: true,
+			expected: "pol_rl_default_rate_limit_policy_default_cafe_vs_sync",
+		},
+		{
+			name:     "shared",
+			shared:   true,
+			expected: "pol_rl_default_rate_limit_policy_shared",
+		},
+		{
+			name:     "shared with zone sync",
+			shared:   true,
+			zoneSync: true,
+			expected: "pol_rl_default_rate_limit_policy_shared_sync",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			policy := &conf_v1.Policy{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "rate-limit-policy",
+					Namespace: "default",
+				},
+				Spec: conf_v1.PolicySpec{
+					RateLimit: &conf_v1.RateLimit{
+						Shared: tt.shared,
+					},
+				},
+			}
+			result := generateRateLimitZoneName(policy, ownerDetails, tt.zoneSync)
+			if result != tt.expected {
+				t.Errorf("generateRateLimitZoneName() returned %q but expected %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGenerateLimitReqZoneCompositeKey(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		rateLimit *conf_v1.RateLimit
+		expected  version2.LimitReqZone
+	}{
+		{
+			name: "single key",
+			rateLimit: &conf_v1.RateLimit{
+				Rate:     "10r/s",
+				Key:      "${binary_remote_addr}",
+				ZoneSize: "10M",
+			},
+			expected: version2.LimitReqZone{
+				ZoneName: "zone",
+				Key:      "${binary_remote_addr}",
+				ZoneSize: "10M",
+				Rate:     "10r/s",
+			},
+		},
+		{
+			name: "two keys",
+			rateLimit: &conf_v1.RateLimit{
+				Rate:     "10r/s",
+				Keys:     []string{"${binary_remote_addr}", "${http_x_api_client}"},
+				ZoneSize: "10M",
+			},
+			expected: version2.LimitReqZone{
+				ZoneName: "zone",
+				Key:      "${binary_remote_addr}${http_x_api_client}",
+				ZoneSize: "10M",
+				Rate:     "10r/s",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			policy := &conf_v1.Policy{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "rate-limit-policy",
+					Namespace: "default",
+				},
+				Spec: conf_v1.PolicySpec{
+					RateLimit: tt.rateLimit,
+				},
+			}
+			result, warning := generateLimitReqZone("zone", policy, 1, false)
+			if !cmp.Equal(tt.expected, result) {
+				t.Error(cmp.Diff(tt.expected, result))
+			}
+			if warning != "" {
+				t.Errorf("generateLimitReqZone() returned unexpected warning %q", warning)
+			}
+		})
+	}
+}
+
+func TestAddRateLimitConfigZoneSize(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name             string
+		rateLimit        *conf_v1.RateLimit
+		expectedZoneSize string
+		expectedWarnings []string
+	}{
+		{
+			name: "large enough zone",
+			rateLimit: &conf_v1.RateLimit{
+				Key:      "${binary_remote_addr}",
+				ZoneSize: "1M",
+				Rate:     "10r/s",
+			},
+			expectedZoneSize: "1M",
+		},
+		{
+			name: "undersized zone",
+			rateLimit: &conf_v1.RateLimit{
+				Key:      "${binary_remote_addr}",
+				ZoneSize: "512k",
+				Rate:     "10r/s",
+			},
+			expectedZoneSize: "512k",
+			expectedWarnings: []string{
+				"RateLimit policy default/rate-limit-policy: the zoneSize 512k might be too small for the key ${binary_remote_addr}, the recommended size for 8192 keys is 1m, or enable autoZoneSize",
+			},
+		},
+		{
+			name: "undersized zone for a composite key",
+			rateLimit: &conf_v1.RateLimit{
+				Keys:     []string{"${binary_remote_addr}", "${http_x_api_client}"},
+				ZoneSize: "1m",
+				Rate:     "10r/s",
+			},
+			expectedZoneSize: "1m",
+			expectedWarnings: []string{
+				"RateLimit policy default/rate-limit-policy: the zoneSize 1m might be too small for the key ${binary_remote_addr}${http_x_api_client}, the recommended size for 8192 keys is 2m, or enable autoZoneSize",
+			},
+		},
+		{
+			name: "undersized zone with auto zone size",
+			rateLimit: &conf_v1.RateLimit{
+				Key:          "${http_x_api_client}",
+				ZoneSize:     "64k",
+				Rate:         "10r/s",
+				AutoZoneSize: true,
+			},
+			expectedZoneSize: "2m",
+		},
+		{
+			name: "low cardinality key",
+			rateLimit: &conf_v1.RateLimit{
+				Key:      "${request_method}",
+				ZoneSize: "32k",
+				Rate:     "10r/s",
+			},
+			expectedZoneSize: "32k",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			policy := &conf_v1.Policy{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "rate-limit-policy",
+					Namespace: "default",
+				},
+				Spec: conf_v1.PolicySpec{
+					RateLimit: tt.rateLimit,
+				},
+			}
+			cfg := newPoliciesConfig(&fakeBV)
+			cfg.Context = context.Background()
+			ownerDetails := policyOwnerDetails{
+				parentNamespace: "default",
+				parentName:      "cafe",
+				parentType:      "vs",
+			}
+
+			res := cfg.addRateLimitConfig(policy, ownerDetails, 1, false, specContext, "/")
+			if diff := cmp.Diff(tt.expectedWarnings, res.warnings); diff != "" {
+				t.Errorf("addRateLimitConfig() returned unexpected warnings (-want +got):\n%s", diff)
+			}
+			if len(cfg.RateLimit.Zones) != 1 {
+				t.Fatalf("addRateLimitConfig() returned %d zones but expected 1", len(cfg.RateLimit.Zones))
+			}
+			if cfg.RateLimit.Zones[0].ZoneSize != tt.expectedZoneSize {
+				t.Errorf("addRateLimitConfig() returned the zone size %q but expected %q", cfg.RateLimit.Zones[0].ZoneSize, tt.expectedZoneSize)
+			}
+		})
+	}
+}
+
+func TestAddRateLimitConfigShared(t *testing.T) {
+	t.Parallel()
+	policy := &conf_v1.Policy{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "rate-limit-policy",
+			Namespace: "default",
+		},
+		Spec: conf_v1.PolicySpec{
+			RateLimit: &conf_v1.RateLimit{
+				Key:      "$binary_remote_addr",
+				ZoneSize: "10M",
+				Rate:     "10r/s",
+				Shared:   true,
+			},
+		},
+	}
+
+	var zones []version2.LimitReqZone
+	for _, parentName := range []string{"cafe", "tea"} {
+		cfg := newPoliciesConfig(&fakeBV)
+		cfg.Context = context.Background()
+		ownerDetails := policyOwnerDetails{
+			parentNamespace: "default",
+			parentName:      parentName,
+			parentType:      "vs",
+		}
+
+		res := cfg.addRateLimitConfig(policy, ownerDetails, 1, false, specContext, "/")
+		expectedWarnings := []string{
+			"RateLimit policy default/rate-limit-policy is shared: the limit is enforced together for all resources that reference the policy",
+		}
+		if diff := cmp.Diff(expectedWarnings, res.warnings); diff != "" {
+			t.Errorf("addRateLimitConfig() returned unexpected warnings (-want +got):\n%s", diff)
+		}
+		if len(cfg.RateLimit.Zones) != 0 {
+			t.Errorf("addRateLimitConfig() returned isolated zones %v for a shared policy", cfg.RateLimit.Zones)
+		}
+		zones = append(zones, cfg.RateLimit.SharedZones...)
+	}
+
+	expectedZone := version2.LimitReqZone{
+		ZoneName: "pol_rl_default_rate_limit_policy_shared",
+		Key:      "$binary_remote_addr",
+		ZoneSize: "10M",
+		Rate:     "10r/s",
+	}
+	if diff := cmp.Diff([]version2.LimitReqZone{expectedZone, expectedZone}, zones); diff != "" {
+		t.Errorf("addRateLimitConfig() returned unexpected shared zones (-want +got):\n%s", diff)
+	}
+}
+
+func TestAddRateLimitConfigWithDifferentTierConditions(t *testing.T) {
+	t.Parallel()
+	newTieredPolicy := func(name string, variable string, match string) *conf_v1.Policy {
+		return &conf_v1.Policy{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: conf_v1.PolicySpec{
+				RateLimit: &conf_v1.RateLimit{
+					Key:      "$binary_remote_addr",
+					ZoneSize: "10M",
+					Rate:     "10r/s",
+					Condition: &conf_v1.RateLimitCondition{
+						Variables: &[]conf_v1.VariableCondition{
+							{
+								Name:  variable,
+								Match: match,
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	ownerDetails := policyOwnerDetails{
+		ownerNamespace:  "default",
+		parentNamespace: "default",
+		parentName:      "cafe",
+		ownerName:       "cafe",
+		parentType:      "vs",
+	}
+	cfg := newPoliciesConfig(&fakeBV)
+	cfg.Context = context.Background()
+
+	policies := []*conf_v1.Policy{
+		newTieredPolicy("basic", "$apikey_client_name", "basic"),
+		newTieredPolicy("premium", "$apikey_client_name", "premium"),
+		newTieredPolicy("gold", "$http_x_tier", "gold"),
+	}
+	var warnings []string
+	for _, policy := range policies {
+		res := cfg.addRateLimitConfig(policy, ownerDetails, 1, false, specContext, "/")
+		warnings = append(warnings, res.warnings...)
+	}
+
+	expectedWarnings := []string{
+		"RateLimit policy default/gold has a different condition than the first tiered RateLimit policy in this context: $rl_tier is only set to the tiers of the first condition",
+	}
+	if diff := cmp.Diff(expectedWarnings, warnings); diff != "" {
+		t.Errorf("addRateLimitConfig() returned unexpected warnings (-want +got):\n%s", diff)
+	}
+	expectedVariables := []version2.Variable{
+		{Name: "$rl_tier", Value: cfg.RateLimit.Zones[0].GroupVariable},
+	}
+	if diff := cmp.Diff(expectedVariables, cfg.Variables); diff != "" {
+		t.Errorf("addRateLimitConfig() returned unexpected variables (-want +got):\n%s", diff)
+	}
+}
+
+func TestAddRateLimitConfigDelayAndNoDelay(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name             string
+		delay            *int
+		noDelay          *bool
+		expectedReq      version2.LimitReq
+		expectedWarnings []string
+	}{
+		{
+			name:    "delay and noDelay",
+			delay:   new(5),
+			noDelay: new(true),
+			expectedReq: version2.LimitReq{
+				ZoneName: "pol_rl_default_rate_limit_policy_default_cafe_vs",
+				NoDelay:  true,
+			},
+			expectedWarnings: []string{
+				"RateLimit policy default/rate-limit-policy sets both noDelay and delay: noDelay takes precedence and the delay of 5 is ignored",
+			},
+		},
+		{
+			name:  "delay",
+			delay: new(5),
+			expectedReq: version2.LimitReq{
+				ZoneName: "pol_rl_default_rate_limit_policy_default_cafe_vs",
+				Delay:    5,
+			},
+		},
+		{
+			name:    "noDelay",
+			noDelay: new(true),
+			expectedReq: version2.LimitReq{
+				ZoneName: "pol_rl_default_rate_limit_policy_default_cafe_vs",
+				NoDelay:  true,
+			},
+		},
+		{
+			name:    "delay and disabled noDelay",
+			delay:   new(5),
+			noDelay: new(false),
+			expectedReq: version2.LimitReq{
+				ZoneName: "pol_rl_default_rate_limit_policy_default_cafe_vs",
+				Delay:    5,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			policy := &conf_v1.Policy{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "rate-limit-policy",
+					Namespace: "default",
+				},
+				Spec: conf_v1.PolicySpec{
+					RateLimit: &conf_v1.RateLimit{
+						Key:      "$binary_remote_addr",
+						ZoneSize: "10M",
+						Rate:     "10r/s",
+						Delay:    tt.delay,
+						NoDelay:  tt.noDelay,
+					},
+				},
+			}
+			cfg := newPoliciesConfig(&fakeBV)
+			cfg.Context = context.Background()
+			ownerDetails := policyOwnerDetails{
+				ownerNamespace:  "default",
+				parentNamespace: "default",
+				parentName:      "cafe",
+				ownerName:       "cafe",
+				parentType:      "vs",
+			}
+
+			res := cfg.addRateLimitConfig(policy, ownerDetails, 1, false, specContext, "/")
+			if diff := cmp.Diff(tt.expectedWarnings, res.warnings); diff != "" {
+				t.Errorf("addRateLimitConfig() returned unexpected warnings (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff([]version2.LimitReq{tt.expectedReq}, cfg.RateLimit.Reqs); diff != "" {
+				t.Errorf("addRateLimitConfig() returned unexpected limit requests (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGenerateLimitReqBurstFactor(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		rateLimit *conf_v1.RateLimit
+		expected  version2.LimitReq
+	}{
+		{
+			name: "burst computed from rate per second",
+			rateLimit: &conf_v1.RateLimit{
+				Rate:        "10r/s",
+				BurstFactor: new(2),
+			},
+			expected: version2.LimitReq{
+				ZoneName: "zone",
+				Burst:    20,
+			},
+		},
+		{
+			name: "burst computed from rate per minute",
+			rateLimit: &conf_v1.RateLimit{
+				Rate:        "30r/m",
+				BurstFactor: new(3),
+			},
+			expected: version2.LimitReq{
+				ZoneName: "zone",
+				Burst:    90,
+			},
+		},
+		{
+			name: "explicit burst takes precedence",
+			rateLimit: &conf_v1.RateLimit{
+				Rate:        "10r/s",
+				Burst:       new(5),
+				BurstFactor: new(2),
+			},
+			expected: version2.LimitReq{
+				ZoneName: "zone",
+				Burst:    5,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result := generateLimitReq("zone", tt.rateLimit)
+			if !cmp.Equal(tt.expected, result) {
+				t.Error(cmp.Diff(tt.expected, result))
+			}
+		})
+	}
+}
+
+func TestGenerateCacheConfigLock(t *testing.T) {
+	t.Parallel()
+	ownerDetails := policyOwnerDetails{
+		ownerNamespace:  "default",
+		parentNamespace: "default",
+		parentName:      "test",
+		ownerName:       "test",
+		parentType:      "vs",
+	}
+	tests := []struct {
+		name        string
+		lock        *conf_v1.CacheLock
+		wantLock    bool
+		wantTimeout string
+		wantAge     string
+	}{
+		{
+			name: "no lock",
+		},
+		{
+			name: "lock enabled",
+			lock: &conf_v1.CacheLock{
+				Enable:  true,
+				Timeout: "5s",
+				Age:     "10s",
+			},
+			wantLock:    true,
+			wantTimeout: "5s",
+			wantAge:     "10s",
+		},
+		{
+			name: "lock disabled with timeout and age",
+			lock: &conf_v1.CacheLock{
+				Enable:  false,
+				Timeout: "5s",
+				Age:     "10s",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cache := &conf_v1.Cache{
+				CacheZoneName: "cache",
+				CacheZoneSize: "10m",
+				Lock:          tt.lock,
+			}
+			got := generateCacheConfig(cache, ownerDetails)
+			if got.CacheLock != tt.wantLock {
+				t.Errorf("generateCacheConfig() returned CacheLock %v but expected %v", got.CacheLock, tt.wantLock)
+			}
+			if got.CacheLockTimeout != tt.wantTimeout {
+				t.Errorf("generateCacheConfig() returned CacheLockTimeout %q but expected %q", got.CacheLockTimeout, tt.wantTimeout)
+			}
+			if got.CacheLockAge != tt.wantAge {
+				t.Errorf("generateCacheConfig() returned CacheLockAge %q but expected %q", got.CacheLockAge, tt.wantAge)
+			}
+		})
+	}
+}
+
+func TestAddCacheConfigWarnsAboutPOSTWithoutRequestBody(t *testing.T) {
+	t.Parallel()
+	ownerDetails := policyOwnerDetails{
+		ownerNamespace:  "default",
+		parentNamespace: "default",
+		parentName:      "test",
+		ownerName:       "test",
+		parentType:      "vs",
+	}
+	tests := []struct {
+		name         string
+		methods      []string
+		cacheKey     string
+		wantWarnings int
+	}{
+		{
+			name:     "GET and HEAD only",
+			methods:  []string{"GET", "HEAD"},
+			cacheKey: "$scheme$proxy_host$request_uri",
+		},
+		{
+			name:         "POST without request body in cache key",
+			methods:      []string{"GET", "HEAD", "POST"},
+			cacheKey:     "$scheme$proxy_host$request_uri",
+			wantWarnings: 1,
+		},
+		{
+			name:     "POST with request body in cache key",
+			methods:  []string{"GET", "HEAD", "POST"},
+			cacheKey: "$scheme$proxy_host$request_uri$request_body",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cache := &conf_v1.Cache{
+				CacheZoneName:  "cache",
+				CacheZoneSize:  "10m",
+				AllowedMethods: tt.methods,
+				CacheKey:       tt.cacheKey,
+			}
+			res := (&policiesCfg{}).addCacheConfig(cache, "default/cache", ownerDetails)
+			if len(res.warnings) != tt.wantWarnings {
+				t.Errorf("addCacheConfig() returned warnings %v but expected %d warnings", res.warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
+
 func TestAddWafConfig(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -5182,3 +6231,40 @@ func TestAddWafConfig(t *testing.T) {
 		}
 	}
 }
+
+func TestGenerateJWTToken(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		jwtAuth  *conf_v1.JWTAuth
+		expected string
+		msg      string
+	}{
+		{
+			jwtAuth:  &conf_v1.JWTAuth{},
+			expected: "",
+			msg:      "default token source",
+		},
+		{
+			jwtAuth:  &conf_v1.JWTAuth{Token: "$http_x_auth_token"},
+			expected: "$http_x_auth_token",
+			msg:      "token in header",
+		},
+		{
+			jwtAuth:  &conf_v1.JWTAuth{Token: "$arg_token"},
+			expected: "$arg_token",
+			msg:      "token in query",
+		},
+		{
+			jwtAuth:  &conf_v1.JWTAuth{TokenCookie: "session"},
+			expected: "$cookie_session",
+			msg:      "token in cookie",
+		},
+	}
+
+	for _, test := range tests {
+		result := generateJWTToken(test.jwtAuth)
+		if result != test.expected {
+			t.Errorf("generateJWTToken() returned %q but expected %q for the case of %s", result, test.expected, test.msg)
+		}
+	}
+}
@@ -70,7 +70,7 @@ func generateTransportServerConfig(p transportServerConfigParams) (*version2.Tra
 
 	var proxyRequests, proxyResponses *int
 	var connectTimeout, nextUpstreamTimeout string
-	var nextUpstream bool
+	var nextUpstream, proxyProtocol bool
 	var nextUpstreamTries int
 	if p.transportServerEx.TransportServer.Spec.UpstreamParameters != nil {
 		proxyRequests = p.transportServerEx.TransportServer.Spec.UpstreamParameters.UDPRequests
@@ -83,6 +83,7 @@ func generateTransportServerConfig(p transportServerConfigParams) (*version2.Tra
 		}
 
 		connectTimeout = p.transportServerEx.TransportServer.Spec.UpstreamParameters.ConnectTimeout
+		proxyProtocol = p.transportServerEx.TransportServer.Spec.UpstreamParameters.ProxyProtocol
 	}
 
 	var proxyTimeout string
@@ -121,6 +122,7 @@ func generateTransportServerConfig(p transportServerConfigParams) (*version2.Tra
 			ProxyNextUpstream:        nextUpstream,
 			ProxyNextUpstreamTimeout: generateTimeWithDefault(nextUpstreamTimeout, "0s"),
 			ProxyNextUpstreamTries:   nextUpstreamTries,
+			ProxyProtocol:            proxyProtocol,
 			HealthCheck:              healthCheck,
 			ServerSnippets:           serverSnippets,
 			DisableIPV6:              p.transportServerEx.DisableIPV6,
@@ -353,6 +353,55 @@ func TestGenerateTransportServerConfigForTCP(t *testing.T) {
 	}
 }
 
+func TestGenerateTransportServerConfigForTCPWithProxyProtocol(t *testing.T) {
+	t.Parallel()
+	transportServerEx := TransportServerEx{
+		TransportServer: &conf_v1.TransportServer{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "tcp-server",
+				Namespace: "default",
+			},
+			Spec: conf_v1.TransportServerSpec{
+				Listener: conf_v1.TransportServerListener{
+					Name:     "tcp-listener",
+					Protocol: "TCP",
+				},
+				Upstreams: []conf_v1.TransportServerUpstream{
+					{
+						Name:    "tcp-app",
+						Service: "tcp-app-svc",
+						Port:    5001,
+					},
+				},
+				UpstreamParameters: &conf_v1.UpstreamParameters{
+					ProxyProtocol: true,
+				},
+				Action: &conf_v1.TransportServerAction{
+					Pass: "tcp-app",
+				},
+			},
+		},
+		Endpoints: map[string][]string{
+			"default/tcp-app-svc:5001": {
+				"10.0.0.20:5001",
+			},
+		},
+	}
+
+	result, warnings := generateTransportServerConfig(transportServerConfigParams{
+		transportServerEx: &transportServerEx,
+		listenerPort:      2020,
+		isPlus:            true,
+		staticSSLPath:     "/etc/nginx/secret",
+	})
+	if len(warnings) != 0 {
+		t.Errorf("want no warnings, got %v", warnings)
+	}
+	if !result.Server.ProxyProtocol {
+		t.Error("generateTransportServerConfig() returned a server without the PROXY protocol enabled for the upstream connections")
+	}
+}
+
 func TestGenerateTransportServerConfigForTCPMaxConnections(t *testing.T) {
 	t.Parallel()
 	transportServerEx := TransportServerEx{
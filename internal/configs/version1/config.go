@@ -102,6 +102,7 @@ type Server struct {
 	HasGRPCLocations       bool
 	StatusZone             string
 	HTTP2                  bool
+	HTTP3                  bool
 	RedirectToHTTPS        bool
 	SSLRedirect            bool
 	HTTPRedirectCode       int
@@ -375,6 +376,7 @@ type MainConfig struct {
 	ZoneSyncConfig                     ZoneSyncConfig
 	OIDC                               OIDCConfig
 	DynamicSSLReloadEnabled            bool
+	DynamicWeightChangesReloadEnabled  bool
 	StaticSSLPath                      string
 	NginxVersion                       nginx.Version
 }
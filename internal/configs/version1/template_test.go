@@ -398,6 +398,29 @@ func TestExecuteTemplate_ForIngressWithHeadersOnlyNoCORS(t *testing.T) {
 // This covers the ConfigMap "add-header" path: ConfigMap → MainAddHeaders →
 // MainConfig.AddHeaders → http {} block (global, applies to every server via NGINX
 // inheritance).
+func TestExecuteMainTemplateWithRateLimitedMarker(t *testing.T) {
+	t.Parallel()
+
+	want := "map $limit_req_status $rate_limited {\n" +
+		"        default          0;\n" +
+		"        DELAYED          1;\n" +
+		"        REJECTED         1;\n" +
+		"        DELAYED_DRY_RUN  1;\n" +
+		"        REJECTED_DRY_RUN 1;\n" +
+		"    }"
+
+	for _, tmpl := range []*template.Template{newNGINXMainTmpl(t), newNGINXPlusMainTmpl(t)} {
+		buf := &bytes.Buffer{}
+		err := tmpl.Execute(buf, mainCfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("want %q in generated config", want)
+		}
+	}
+}
+
 func TestExecuteMainTemplateWithAddHeaders(t *testing.T) {
 	t.Parallel()
 
@@ -1808,6 +1831,46 @@ func TestExecuteTemplate_ForMainForNGINXWithCustomTLSPassthroughPort(t *testing.
 	snaps.MatchSnapshot(t, buf.String())
 }
 
+func TestExecuteTemplate_ForMainForNGINXPlusWithSplitWeightsKeyValZone(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		dynamicReload bool
+		want          bool
+	}{
+		{
+			name:          "dynamic reload of weight changes enabled",
+			dynamicReload: true,
+			want:          true,
+		},
+		{
+			name:          "dynamic reload of weight changes disabled",
+			dynamicReload: false,
+			want:          false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			tmpl := newNGINXPlusMainTmpl(t)
+			buf := &bytes.Buffer{}
+
+			cfg := mainCfg
+			cfg.DynamicWeightChangesReloadEnabled = tt.dynamicReload
+			if err := tmpl.Execute(buf, cfg); err != nil {
+				t.Fatalf("Failed to write template %v", err)
+			}
+
+			zone := "keyval_zone zone=split_weights:100k state=/etc/nginx/state_files/split_weights.json;"
+			if got := strings.Contains(buf.String(), zone); got != tt.want {
+				t.Errorf("want %q in generated config: %v, got %v", zone, tt.want, got)
+			}
+		})
+	}
+}
+
 func TestExecuteTemplate_ForMainForNGINXPlusWithCustomTLSPassthroughPort(t *testing.T) {
 	t.Parallel()
 
@@ -2276,6 +2339,35 @@ func TestExecuteTemplate_ForDefaultServerForNGINXWithHTTP2On(t *testing.T) {
 	snaps.MatchSnapshot(t, buf.String())
 }
 
+func TestExecuteTemplate_ForDefaultServerWithHTTP3(t *testing.T) {
+	t.Parallel()
+
+	server := ingressCfgDefaultServerHTTP2On.Servers[0]
+	server.HTTP3 = true
+	ingressCfg := ingressCfgDefaultServerHTTP2On
+	ingressCfg.Servers = []Server{server}
+
+	wantDirectives := []string{
+		"listen 443 ssl default_server;",
+		"listen 443 quic default_server reuseport;",
+		"listen [::]:443 quic default_server reuseport;",
+	}
+
+	for _, tmpl := range []*template.Template{newNGINXIngressTmpl(t), newNGINXPlusIngressTmpl(t)} {
+		buf := &bytes.Buffer{}
+		if err := tmpl.Execute(buf, ingressCfg); err != nil {
+			t.Fatalf("Failed to write template %v", err)
+		}
+
+		mainConf := buf.String()
+		for _, want := range wantDirectives {
+			if !strings.Contains(mainConf, want) {
+				t.Errorf("want %q in generated config", want)
+			}
+		}
+	}
+}
+
 func TestExecuteTemplate_ForDefaultServerForNGINXPlusWithHTTP2On(t *testing.T) {
 	t.Parallel()
 
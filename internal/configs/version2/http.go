@@ -20,7 +20,9 @@ type VirtualServerConfig struct {
 	KeyValZones             []KeyValZone
 	KeyVals                 []KeyVal
 	LimitReqZones           []LimitReqZone
+	SharedLimitReqZones     []LimitReqZone
 	Maps                    []Map
+	Geos                    []Geo
 	AuthJWTClaimSets        []AuthJWTClaimSet
 	CacheZones              []CacheZone
 	Server                  Server
@@ -40,10 +42,12 @@ type AuthJWTClaimSet struct {
 // Upstream defines an upstream.
 type Upstream struct {
 	Name             string
+	FullName         string
 	Servers          []UpstreamServer
 	LBMethod         string
 	Resolve          bool
 	Keepalive        int
+	KeepaliveTime    string
 	MaxFails         int
 	MaxConns         int
 	SlowStart        string
@@ -54,11 +58,25 @@ type Upstream struct {
 	UpstreamLabels   UpstreamLabels
 	NTLM             bool
 	BackupServers    []UpstreamServer
+	Resolver         *UpstreamResolver
+}
+
+// UpstreamResolver defines the resolver of the hostnames of the servers of an Upstream.
+type UpstreamResolver struct {
+	Addresses []string
+	Valid     string
+	IPV6      bool
 }
 
 // UpstreamServer defines an upstream server.
 type UpstreamServer struct {
 	Address string
+	// Down marks the server as unavailable, for example, while its node is being drained.
+	Down bool
+	// Drain puts the server in the draining mode, so that only the requests bound to it by session persistence are passed to it.
+	Drain bool
+	// PodName is the name of the pod of the server, which is added as a comment for debugging.
+	PodName string
 }
 
 // Server defines a server.
@@ -72,6 +90,10 @@ type Server struct {
 	HTTPSIPv6                 string
 	HTTPPort                  int
 	HTTPSPort                 int
+	HTTPListenerOptions       *ListenerOptions
+	HTTPSListenerOptions      *ListenerOptions
+	HTTP3ReusePort            bool
+	AdditionalListeners       []AdditionalListener
 	ProxyProtocol             bool
 	SSL                       *SSL
 	ServerTokens              string
@@ -83,13 +105,18 @@ type Server struct {
 	Locations                 []Location
 	ErrorPageLocations        []ErrorPageLocation
 	ReturnLocations           []ReturnLocation
+	TarpitLocations           []TarpitLocation
+	GRPCErrorLocations        []GRPCErrorLocation
 	HealthChecks              []HealthCheck
 	TLSRedirect               *TLSRedirect
+	StrictHost                *StrictHost
 	TLSPassthrough            bool
 	Allow                     []string
 	Deny                      []string
+	Satisfy                   string
 	LimitReqOptions           LimitReqOptions
 	LimitReqs                 []LimitReq
+	Variables                 []Variable
 	JWTAuth                   *JWTAuth
 	JWTAuthList               map[string]*JWTAuth
 	JWKSAuthEnabled           bool
@@ -102,6 +129,7 @@ type Server struct {
 	OIDC                      *OIDC
 	APIKey                    *APIKey
 	APIKeyEnabled             bool
+	APIKeyRejectEnabled       bool
 	WAF                       *WAF
 	Dos                       *Dos
 	Cache                     *Cache
@@ -112,14 +140,29 @@ type Server struct {
 	Gunzip                    bool
 	NGINXDebugLevel           string
 	AddHeaderInherit          string
+	AddHeaders                []AddHeader
+	UnderscoresInHeaders      string
+	MergeSlashes              string
+	DefaultType               string
+	ErrorLog                  *ErrorLog
+	Tracing                   *Tracing
+}
+
+// ErrorLog defines the error log of a server.
+type ErrorLog struct {
+	Destination string
+	Level       string
 }
 
 // SSL defines SSL configuration for a server.
 type SSL struct {
 	HTTP2           bool
+	HTTP3           bool
 	Certificate     string
 	CertificateKey  string
 	RejectHandshake bool
+	Protocols       string
+	Ciphers         string
 }
 
 // IngressMTLS defines TLS configuration for a server. This is a subset of TLS specifically for clients auth.
@@ -128,6 +171,8 @@ type IngressMTLS struct {
 	ClientCrl    string
 	VerifyClient string
 	VerifyDepth  int
+	// ClientCrlFiles are the CRL files combined into the ClientCrl file.
+	ClientCrlFiles []string
 }
 
 // EgressMTLS defines upstream TLS configuration applied at server or location scope.
@@ -163,13 +208,16 @@ type OIDC struct {
 	VerifyDepth           int
 	CAFile                string
 	PolicyName            string
+	// Resolvers are the addresses of the DNS servers used to resolve the hosts of the IdP endpoints.
+	Resolvers []string
 }
 
 // APIKey holds API key configuration.
 type APIKey struct {
-	Header  []string
-	Query   []string
-	MapName string
+	Header     []string
+	Query      []string
+	MapName    string
+	RejectCode int
 }
 
 // WAF defines WAF configuration.
@@ -207,6 +255,8 @@ type Location struct {
 	ClientBodyBufferSize       string
 	ProxyMaxTempFileSize       string
 	ProxyBuffering             bool
+	ProxySocketKeepalive       bool
+	ProxyIgnoreClientAbort     string
 	ProxyBuffers               string
 	ProxyBufferSize            string
 	ProxyBusyBuffersSize       string
@@ -217,21 +267,30 @@ type Location struct {
 	ProxyInterceptErrors       bool
 	ProxyPassRequestHeaders    bool
 	ProxyPassRequestBody       string
+	ProxyMethod                string
 	ProxySetHeaders            []Header
 	ProxyHideHeaders           []string
 	ProxyPassHeaders           []string
 	ProxyIgnoreHeaders         string
 	ProxyPassRewrite           string
 	AddHeaders                 []AddHeader
+	Expires                    string
+	SubFilter                  *SubFilter
 	Rewrites                   []string
 	HasKeepalive               bool
 	ErrorPages                 []ErrorPage
 	ProxySSLName               string
+	ProxySSLServerName         bool
+	ProxySSLSessionReuse       string
 	InternalProxyPass          string
+	Root                       string
+	TryFiles                   []string
 	Allow                      []string
 	Deny                       []string
+	Satisfy                    string
 	LimitReqOptions            LimitReqOptions
 	LimitReqs                  []LimitReq
+	Variables                  []Variable
 	JWTAuth                    *JWTAuth
 	AuthRequestOff             bool
 	ExternalAuth               *ExternalAuth
@@ -245,23 +304,65 @@ type Location struct {
 	PoliciesErrorReturn        *Return
 	Cache                      *Cache
 	ServiceName                string
+	StatusZone                 string
 	IsVSR                      bool
 	VSRName                    string
 	VSRNamespace               string
 	GRPCPass                   string
+	GRPCErrorPages             map[string]string
 	CORSEnabled                bool
 	AddHeaderInherit           string
 	ProxySSLVerify             bool
 	ProxySSLVerifyDepth        int
 	ProxySSLTrustedCertificate string
+	Tarpit                     *Tarpit
+	ClientIPReturn             *ClientIPReturn
+	Tracing                    *Tracing
+	RouteRewrites              []string
 }
 
 // ReturnLocation defines a location for returning a fixed response.
 type ReturnLocation struct {
 	Name        string
 	DefaultType string
-	Return      Return
-	Headers     []Header
+	// Return.Code is the status code of the response. When it is 0, the code is set by the error_page directive.
+	Return  Return
+	Headers []Header
+	// Alternatives are the return locations for the other content types of the response.
+	Alternatives []ReturnLocation
+	// AcceptMap selects between the location and its Alternatives by the Accept header of the request.
+	AcceptMap *Map
+}
+
+// Tarpit defines the delaying of the responses in a Location.
+type Tarpit struct {
+	// Variable is non-empty and not "0" when the request matches the conditions of the tarpit.
+	Variable string
+	// Location is the path of the TarpitLocation the matching requests are rewritten to.
+	Location string
+}
+
+// Tracing defines the OpenTelemetry tracing of the requests of a Server or a Location.
+type Tracing struct {
+	Enable  bool
+	Context string
+	// Route is the value of the http.route attribute of the spans of a Location.
+	Route string
+}
+
+// ClientIPReturn defines a return for the requests from a list of client IP addresses in a Location.
+type ClientIPReturn struct {
+	// Variable is "1" when the client IP address of the request matches one of the ranges.
+	Variable string
+	Code     int
+}
+
+// TarpitLocation defines a location for returning a response after a delay.
+type TarpitLocation struct {
+	Path string
+	// Delay is the delay of the response in milliseconds.
+	Delay int64
+	Code  int
 }
 
 // SplitClient defines a split_clients.
@@ -277,10 +378,25 @@ type Return struct {
 	Text string
 }
 
+// SubFilter defines the sub_filter directives of a location.
+type SubFilter struct {
+	Rules []SubFilterRule
+	Once  bool
+	Types []string
+}
+
+// SubFilterRule defines a string to replace in the response bodies.
+type SubFilterRule struct {
+	From string
+	To   string
+}
+
 // ErrorPage defines an error_page of a location.
 type ErrorPage struct {
-	Name         string
-	Codes        string
+	Name  string
+	Codes string
+	// ResponseCode replaces the code of the response. When it is 0, the code of the error is kept,
+	// and when it is negative, the code of the response of the error page is used.
 	ResponseCode int
 }
 
@@ -292,6 +408,14 @@ type ErrorPageLocation struct {
 	Headers     []Header
 }
 
+// GRPCErrorLocation defines a named location for a custom response for a gRPC status.
+type GRPCErrorLocation struct {
+	Name    string
+	Status  int
+	Message string
+	Headers []Header
+}
+
 // Header defines a header to use with add_header directive.
 type Header struct {
 	Name  string
@@ -328,12 +452,36 @@ type HealthCheck struct {
 	IsGRPC              bool
 }
 
+// AdditionalListener defines a listener of a Server in addition to its HTTP and HTTPS listeners.
+type AdditionalListener struct {
+	Port    int
+	IPv4    string
+	IPv6    string
+	SSL     bool
+	Options *ListenerOptions
+}
+
+// ListenerOptions defines the options of the sockets of a listener.
+type ListenerOptions struct {
+	Deferred    bool
+	SoKeepalive string
+	Backlog     int
+}
+
 // TLSRedirect defines a redirect in a Server.
 type TLSRedirect struct {
 	Code    int
 	BasedOn string
 }
 
+// StrictHost defines the rejection of the requests for other hosts in a Server.
+// Host is a regular expression when Regex is true.
+type StrictHost struct {
+	Host  string
+	Regex bool
+	Code  int
+}
+
 // SessionCookie defines a session cookie for an upstream.
 type SessionCookie struct {
 	Enable   bool
@@ -363,6 +511,9 @@ type Map struct {
 	Source     string
 	Variable   string
 	Parameters []Parameter
+	// Volatile makes NGINX evaluate the map every time its variable is used instead of caching the value for the request,
+	// for the maps whose source is only resolved later in the processing of the request.
+	Volatile bool
 }
 
 func (m *Map) String() string {
@@ -375,7 +526,14 @@ func (m *Map) String() string {
 	return buf.String()
 }
 
-// Parameter defines a Parameter in a Map.
+// Geo defines a geo.
+type Geo struct {
+	Source     string
+	Variable   string
+	Parameters []Parameter
+}
+
+// Parameter defines a Parameter in a Map or a Geo.
 type Parameter struct {
 	Value  string
 	Result string
@@ -456,6 +614,8 @@ type JWTAuth struct {
 	Token    string
 	KeyCache string
 	JwksURI  JwksURI
+	// Require holds the variables that must be non-empty and not equal to "0" for the token to be accepted.
+	Require []string
 }
 
 // JwksURI defines the components of a JwksURI
@@ -469,6 +629,8 @@ type JwksURI struct {
 	SSLVerify      bool
 	TrustedCert    string
 	SSLVerifyDepth int
+	// Resolvers are the addresses of the DNS servers used to resolve the JwksHost.
+	Resolvers []string
 }
 
 // ExternalAuth holds external authentication configuration.
@@ -506,6 +668,9 @@ type AuthURI struct {
 type BasicAuth struct {
 	Secret string
 	Realm  string
+	// Users is the content of the htpasswd file generated for the users specified in the policy.
+	// The configurator writes it to a file and sets Secret to the path of the file.
+	Users string
 }
 
 // KeyValZone defines a keyval zone.
@@ -524,11 +689,11 @@ type KeyVal struct {
 
 // TwoWaySplitClients defines split clients for two way split
 type TwoWaySplitClients struct {
-	Key               string
-	Variable          string
-	ZoneName          string
-	Weights           []int
-	SplitClientsIndex int
+	Key            string
+	Variable       string
+	ZoneName       string
+	Weights        []int
+	SplitClientsID string
 }
 
 // Variable defines an nginx variable.
@@ -14,6 +14,7 @@ type protocol int
 const (
 	http protocol = iota
 	https
+	http3
 )
 
 type ipType int
@@ -29,7 +30,10 @@ type listen struct {
 	tls           bool
 	proxyProtocol bool
 	udp           bool
+	quic          bool
+	reusePort     bool
 	ipType        ipType
+	options       *ListenerOptions
 }
 
 const spacing = "    "
@@ -67,16 +71,70 @@ func buildDefaultListenerDirectives(listenerType protocol, s Server) string {
 }
 
 func buildCustomListenerDirectives(listenerType protocol, s Server) string {
-	if (listenerType == http && s.HTTPPort > 0) || (listenerType == https && s.HTTPSPort > 0) {
+	var directives string
+
+	if (listenerType == http && s.HTTPPort > 0) || (listenerType != http && s.HTTPSPort > 0) {
 		port := getCustomPort(listenerType, s)
-		return buildListenerDirectives(listenerType, s, port)
+		directives += buildListenerDirectives(listenerType, s, port)
+	}
+
+	if listenerType == http3 {
+		return directives
 	}
-	return ""
+
+	// The additional HTTPS listeners are only rendered with the HTTPS listener, when TLS is configured for the server.
+	for _, l := range s.AdditionalListeners {
+		if l.SSL != (listenerType == https) {
+			continue
+		}
+		if directives != "" {
+			directives += spacing
+		}
+		directives += buildAdditionalListenerDirectives(l, s)
+	}
+
+	return directives
+}
+
+func buildAdditionalListenerDirectives(l AdditionalListener, s Server) string {
+	port := strconv.Itoa(l.Port)
+
+	directives := buildListenDirective(listen{
+		ipAddress:     l.IPv4,
+		port:          port,
+		tls:           l.SSL,
+		proxyProtocol: s.ProxyProtocol,
+		ipType:        ipv4,
+		options:       l.Options,
+	})
+	if !s.DisableIPV6 {
+		directives += spacing
+		directives += buildListenDirective(listen{
+			ipAddress:     l.IPv6,
+			port:          port,
+			tls:           l.SSL,
+			proxyProtocol: s.ProxyProtocol,
+			ipType:        ipv6,
+			options:       l.Options,
+		})
+	}
+
+	return directives
 }
 
 func buildListenerDirectives(listenerType protocol, s Server, port string) string {
 	var directives string
 
+	// The options of the listeners are only configured for the custom listeners.
+	var options *ListenerOptions
+	if s.CustomListeners {
+		if listenerType == http {
+			options = s.HTTPListenerOptions
+		} else if listenerType == https {
+			options = s.HTTPSListenerOptions
+		}
+	}
+
 	if listenerType == http {
 		directives += buildListenDirective(listen{
 			ipAddress:     s.HTTPIPv4,
@@ -85,6 +143,7 @@ func buildListenerDirectives(listenerType protocol, s Server, port string) strin
 			proxyProtocol: s.ProxyProtocol,
 			udp:           false,
 			ipType:        ipv4,
+			options:       options,
 		})
 		if !s.DisableIPV6 {
 			directives += spacing
@@ -95,6 +154,28 @@ func buildListenerDirectives(listenerType protocol, s Server, port string) strin
 				proxyProtocol: s.ProxyProtocol,
 				udp:           false,
 				ipType:        ipv6,
+				options:       options,
+			})
+		}
+	} else if listenerType == http3 {
+		// QUIC listeners do not support the PROXY protocol. The default server sets reuseport for the default HTTPS port,
+		// so it is only set for the custom listeners.
+		reusePort := s.CustomListeners && s.HTTP3ReusePort
+		directives += buildListenDirective(listen{
+			ipAddress: s.HTTPSIPv4,
+			port:      port,
+			quic:      true,
+			reusePort: reusePort,
+			ipType:    ipv4,
+		})
+		if !s.DisableIPV6 {
+			directives += spacing
+			directives += buildListenDirective(listen{
+				ipAddress: s.HTTPSIPv6,
+				port:      port,
+				quic:      true,
+				reusePort: reusePort,
+				ipType:    ipv6,
 			})
 		}
 	} else {
@@ -105,6 +186,7 @@ func buildListenerDirectives(listenerType protocol, s Server, port string) strin
 			proxyProtocol: s.ProxyProtocol,
 			udp:           false,
 			ipType:        ipv4,
+			options:       options,
 		})
 		if !s.DisableIPV6 {
 			directives += spacing
@@ -115,6 +197,7 @@ func buildListenerDirectives(listenerType protocol, s Server, port string) strin
 				proxyProtocol: s.ProxyProtocol,
 				udp:           false,
 				ipType:        ipv6,
+				options:       options,
 			})
 		}
 	}
@@ -167,6 +250,26 @@ func buildListenDirective(l listen) string {
 		directive += " udp"
 	}
 
+	if l.quic {
+		directive += " quic"
+	}
+
+	if l.reusePort {
+		directive += " reuseport"
+	}
+
+	if l.options != nil {
+		if l.options.Deferred {
+			directive += " deferred"
+		}
+		if l.options.Backlog > 0 {
+			directive += fmt.Sprintf(" backlog=%d", l.options.Backlog)
+		}
+		if l.options.SoKeepalive != "" {
+			directive += fmt.Sprintf(" so_keepalive=%s", l.options.SoKeepalive)
+		}
+	}
+
 	directive += ";\n"
 	return directive
 }
@@ -179,6 +282,19 @@ func makeHTTPSListener(s Server) string {
 	return makeListener(https, s)
 }
 
+func makeHTTP3Listener(s Server) string {
+	return makeListener(http3, s)
+}
+
+// makeGRPCErrorPage returns the name of the location of the response for a gRPC status in lowercase,
+// which is either a custom response of the location or the default location of the gRPC status.
+func makeGRPCErrorPage(l Location, status string) string {
+	if name, ok := l.GRPCErrorPages[status]; ok {
+		return name
+	}
+	return "@grpc_" + status
+}
+
 func makeTransportListener(s StreamServer) string {
 	var directives string
 	port := strconv.Itoa(s.Port)
@@ -259,9 +375,11 @@ var helperFunctions = template.FuncMap{
 	"replaceAll":            strings.ReplaceAll,
 	"makeHTTPListener":      makeHTTPListener,
 	"makeHTTPSListener":     makeHTTPSListener,
+	"makeHTTP3Listener":     makeHTTP3Listener,
 	"makeSecretPath":        commonhelpers.MakeSecretPath,
 	"makeHeaderQueryValue":  makeHeaderQueryValue,
 	"makeTransportListener": makeTransportListener,
+	"makeGRPCErrorPage":     makeGRPCErrorPage,
 	"makeServerName":        makeServerName,
 	"boolToInteger":         boolToInteger,
 }
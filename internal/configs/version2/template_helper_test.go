@@ -208,6 +208,62 @@ func TestMakeHTTPListener(t *testing.T) {
 	}
 }
 
+func TestMakeListenersWithAdditionalListeners(t *testing.T) {
+	t.Parallel()
+
+	server := Server{
+		CustomListeners: true,
+		HTTPPort:        81,
+		HTTPSPort:       444,
+		AdditionalListeners: []AdditionalListener{
+			{Port: 8080},
+			{Port: 8443, SSL: true},
+		},
+	}
+
+	want := "listen 81;\n    listen [::]:81;\n    listen 8080;\n    listen [::]:8080;\n"
+	if got := makeHTTPListener(server); got != want {
+		t.Errorf("makeHTTPListener() generated wrong config, got %v but expected %v.", got, want)
+	}
+
+	want = "listen 444 ssl;\n    listen [::]:444 ssl;\n    listen 8443 ssl;\n    listen [::]:8443 ssl;\n"
+	if got := makeHTTPSListener(server); got != want {
+		t.Errorf("makeHTTPSListener() generated wrong config, got %v but expected %v.", got, want)
+	}
+}
+
+func TestMakeListenersWithListenerOptions(t *testing.T) {
+	t.Parallel()
+
+	server := Server{
+		CustomListeners:      true,
+		HTTPPort:             81,
+		HTTPSPort:            444,
+		HTTPListenerOptions:  &ListenerOptions{Deferred: true, Backlog: 1024},
+		HTTPSListenerOptions: &ListenerOptions{SoKeepalive: "30m::10"},
+		AdditionalListeners: []AdditionalListener{
+			{Port: 8080, Options: &ListenerOptions{SoKeepalive: "on"}},
+			{Port: 8443, SSL: true},
+		},
+	}
+
+	want := "listen 81 deferred backlog=1024;\n    listen [::]:81 deferred backlog=1024;\n    listen 8080 so_keepalive=on;\n    listen [::]:8080 so_keepalive=on;\n"
+	if got := makeHTTPListener(server); got != want {
+		t.Errorf("makeHTTPListener() generated wrong config, got %v but expected %v.", got, want)
+	}
+
+	want = "listen 444 ssl so_keepalive=30m::10;\n    listen [::]:444 ssl so_keepalive=30m::10;\n    listen 8443 ssl;\n    listen [::]:8443 ssl;\n"
+	if got := makeHTTPSListener(server); got != want {
+		t.Errorf("makeHTTPSListener() generated wrong config, got %v but expected %v.", got, want)
+	}
+
+	server.SSL = &SSL{HTTP3: true}
+	want = "listen 444 quic;\n    listen [::]:444 quic;\n"
+	if got := makeHTTP3Listener(server); got != want {
+		t.Errorf("makeHTTP3Listener() generated wrong config, got %v but expected %v.", got, want)
+	}
+}
+
 func TestMakeHTTPSListener(t *testing.T) {
 	t.Parallel()
 
@@ -473,6 +529,52 @@ func TestMakeHTTPSListenerWithCustomIPV6(t *testing.T) {
 	}
 }
 
+func TestMakeHTTP3Listener(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		server   Server
+		expected string
+	}{
+		{server: Server{
+			CustomListeners: false,
+			DisableIPV6:     false,
+			ProxyProtocol:   false,
+		}, expected: "listen 443 quic;\n    listen [::]:443 quic;\n"},
+		{server: Server{
+			CustomListeners: false,
+			DisableIPV6:     true,
+			ProxyProtocol:   true,
+		}, expected: "listen 443 quic;\n"},
+		{server: Server{
+			CustomListeners: true,
+			HTTPSPort:       8443,
+			HTTPSIPv4:       "127.0.0.1",
+			HTTPSIPv6:       "::1",
+		}, expected: "listen 127.0.0.1:8443 quic;\n    listen [::1]:8443 quic;\n"},
+		{server: Server{
+			CustomListeners: true,
+			HTTPSPort:       8443,
+			HTTP3ReusePort:  true,
+		}, expected: "listen 8443 quic reuseport;\n    listen [::]:8443 quic reuseport;\n"},
+		{server: Server{
+			CustomListeners: false,
+			HTTP3ReusePort:  true,
+		}, expected: "listen 443 quic;\n    listen [::]:443 quic;\n"},
+		{server: Server{
+			CustomListeners: true,
+			HTTPPort:        80,
+		}, expected: ""},
+	}
+
+	for _, tc := range testCases {
+		got := makeHTTP3Listener(tc.server)
+		if got != tc.expected {
+			t.Errorf("Function generated wrong config, got %v but expected %v.", got, tc.expected)
+		}
+	}
+}
+
 func TestMakeTransportListener(t *testing.T) {
 	t.Parallel()
 
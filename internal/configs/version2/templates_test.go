@@ -768,141 +768,1967 @@ func tsConfig() TransportServerConfig {
 	}
 }
 
+func TestExecuteTemplateForTransportServerWithProxyProtocol(t *testing.T) {
+	t.Parallel()
+
+	tsCfg := tsConfig()
+	tsCfg.Server.UDP = false
+	tsCfg.Server.ProxyRequests = nil
+	tsCfg.Server.ProxyResponses = nil
+	tsCfg.Server.ProxyProtocol = true
+
+	for _, e := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+		got, err := e.ExecuteTransportServerTemplate(&tsCfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Contains(got, []byte("proxy_connect_timeout 10s;\n    proxy_protocol on;")) {
+			t.Errorf("want proxy_protocol on for the upstream connections in the transport server config")
+		}
+	}
+}
+
+func TestExecuteTemplateForTransportServerWithoutProxyProtocol(t *testing.T) {
+	t.Parallel()
+
+	tsCfg := tsConfig()
+	for _, e := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+		got, err := e.ExecuteTransportServerTemplate(&tsCfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Contains(got, []byte("proxy_protocol on;")) {
+			t.Errorf("want no proxy_protocol in the transport server config")
+		}
+	}
+}
+
 func TestExecuteTemplateForTransportServerWithBackupServerForNGINXPlus(t *testing.T) {
 	t.Parallel()
 
-	tsCfg := tsConfig()
-	tsCfg.Upstreams[0].BackupServers = []StreamUpstreamBackupServer{
+	tsCfg := tsConfig()
+	tsCfg.Upstreams[0].BackupServers = []StreamUpstreamBackupServer{
+		{
+			Address: "clustertwo.corp.local:8080",
+		},
+	}
+	e := newTmplExecutorNGINXPlus(t)
+	got, err := e.ExecuteTransportServerTemplate(&tsCfg)
+	if err != nil {
+		t.Error(err)
+	}
+
+	want := fmt.Sprintf("server %s resolve backup;", tsCfg.Upstreams[0].BackupServers[0].Address)
+	if !bytes.Contains(got, []byte(want)) {
+		t.Errorf("want backup %q in the transport server config", want)
+	}
+	snaps.MatchSnapshot(t, string(got))
+	t.Log(string(got))
+}
+
+func TestTransportServerWithSSL(t *testing.T) {
+	t.Parallel()
+	executor := newTmplExecutorNGINXPlus(t)
+	data, err := executor.ExecuteTransportServerTemplate(&transportServerCfgWithSSL)
+	if err != nil {
+		t.Errorf("Failed to execute template: %v", err)
+	}
+	snaps.MatchSnapshot(t, string(data))
+	t.Log(string(data))
+}
+
+func TestTLSPassthroughHosts(t *testing.T) {
+	t.Parallel()
+	executor := newTmplExecutorNGINX(t)
+
+	unixSocketsCfg := TLSPassthroughHostsConfig{
+		"app.example.com": "unix:/var/lib/nginx/passthrough-default_secure-app.sock",
+	}
+
+	data, err := executor.ExecuteTLSPassthroughHostsTemplate(&unixSocketsCfg)
+	if err != nil {
+		t.Errorf("Failed to execute template: %v", err)
+	}
+	snaps.MatchSnapshot(t, string(data))
+	t.Log(string(data))
+}
+
+func TestSharedLimitReqZones(t *testing.T) {
+	t.Parallel()
+	executor := newTmplExecutorNGINXPlus(t)
+
+	zones := []LimitReqZone{
+		{
+			ZoneName: "pol_rl_default_rate_limit_policy_shared",
+			Key:      "$binary_remote_addr",
+			ZoneSize: "10M",
+			Rate:     "10r/s",
+		},
+		{
+			ZoneName: "pol_rl_default_sync_policy_shared_sync",
+			Key:      "$binary_remote_addr",
+			ZoneSize: "10M",
+			Rate:     "10r/s",
+			Sync:     true,
+		},
+	}
+
+	data, err := executor.ExecuteSharedLimitReqZonesTemplate(zones)
+	if err != nil {
+		t.Errorf("Failed to execute template: %v", err)
+	}
+	snaps.MatchSnapshot(t, string(data))
+	t.Log(string(data))
+}
+
+func TestExecuteVirtualServerTemplateWithJWKSWithToken(t *testing.T) {
+	t.Parallel()
+	executor := newTmplExecutorNGINXPlus(t)
+	got, err := executor.ExecuteVirtualServerTemplate(&virtualServerCfgWithJWTPolicyJWKSWithToken)
+	if err != nil {
+		t.Error(err)
+	}
+	if !bytes.Contains(got, []byte("token=$http_token")) {
+		t.Error("want `token=$http_token` in generated template")
+	}
+
+	if !bytes.Contains(got, []byte("proxy_ssl_server_name on;")) {
+		t.Error("want `proxy_ssl_server_name on;` in generated template")
+	}
+
+	if !bytes.Contains(got, []byte("proxy_ssl_name sni.idp.spec.example.com;")) {
+		t.Error("want `proxy_ssl_name sni.idp.spec.example.com;` in generated template")
+	}
+
+	snaps.MatchSnapshot(t, string(got))
+	t.Log(string(got))
+}
+
+func TestExecuteVirtualServerTemplateWithJWKSWithoutToken(t *testing.T) {
+	t.Parallel()
+	executor := newTmplExecutorNGINXPlus(t)
+	got, err := executor.ExecuteVirtualServerTemplate(&virtualServerCfgWithJWTPolicyJWKSWithoutToken)
+	if err != nil {
+		t.Error(err)
+	}
+	if bytes.Contains(got, []byte("token=$http_token")) {
+		t.Error("want no `token=$http_token` string in generated template")
+	}
+	snaps.MatchSnapshot(t, string(got))
+	t.Log(string(got))
+}
+
+func TestExecuteVirtualServerTemplateWithJWKSResolvers(t *testing.T) {
+	t.Parallel()
+
+	vscfg := VirtualServerConfig{
+		Server: Server{
+			ServerName: "cafe.example.com",
+			JWTAuthList: map[string]*JWTAuth{
+				"default/jwt-policy": {
+					Key:      "default/jwt-policy",
+					Realm:    "Spec Realm API",
+					KeyCache: "1h",
+					JwksURI: JwksURI{
+						JwksScheme: "https",
+						JwksHost:   "idp.example.com",
+						JwksPath:   "/keys",
+						Resolvers:  []string{"127.0.0.1:5353", "10.0.0.53"},
+					},
+				},
+			},
+		},
+	}
+
+	executor := newTmplExecutorNGINXPlus(t)
+	got, err := executor.ExecuteVirtualServerTemplate(&vscfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "resolver 127.0.0.1:5353 10.0.0.53;\n        set $idp_backend idp.example.com;"
+	if !bytes.Contains(got, []byte(want)) {
+		t.Errorf("want %q in generated template", want)
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithBackupServerNGINXPlus(t *testing.T) {
+	t.Parallel()
+
+	externalName := "clustertwo.corp.local:8080"
+	vscfg := vsConfig()
+	vscfg.Upstreams[0].BackupServers = []UpstreamServer{
+		{
+			Address: externalName,
+		},
+	}
+
+	e := newTmplExecutorNGINXPlus(t)
+	got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+	if err != nil {
+		t.Error(err)
+	}
+
+	want := fmt.Sprintf("server %s backup resolve;", externalName)
+	if !bytes.Contains(got, []byte(want)) {
+		t.Errorf("want %q in generated template", want)
+	}
+	snaps.MatchSnapshot(t, string(got))
+	t.Log(string(got))
+}
+
+func TestExecuteVirtualServerTemplateWithAPIKeyPolicyNGINXPlus(t *testing.T) {
+	t.Parallel()
+
+	vscfg := vsConfig()
+	vscfg.Server.APIKey = &APIKey{
+		Header:  []string{"X-header-name", "other-header"},
+		Query:   []string{"myQuery", "myOtherQuery"},
+		MapName: "vs_default_cafe_apikey_policy",
+	}
+
+	e := newTmplExecutorNGINXPlus(t)
+	got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+	if err != nil {
+		t.Error(err)
+	}
+
+	want := "js_var $header_query_value \"${http_x_header_name}${http_other_header}${arg_myQuery}${arg_myOtherQuery}\";"
+
+	if !bytes.Contains(got, []byte(want)) {
+		t.Errorf("want %q in generated template", want)
+	}
+	snaps.MatchSnapshot(t, string(got))
+	t.Log(string(got))
+}
+
+func TestExecuteVirtualServerTemplateWithAPIKeyPolicyRejectCode(t *testing.T) {
+	t.Parallel()
+
+	vscfg := vsConfig()
+	vscfg.Server.APIKeyEnabled = true
+	vscfg.Server.APIKeyRejectEnabled = true
+	vscfg.Server.APIKey = &APIKey{
+		Header:     []string{"X-header-name"},
+		MapName:    "vs_default_cafe_apikey_policy",
+		RejectCode: 429,
+	}
+
+	e := newTmplExecutorNGINX(t)
+	got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+	if err != nil {
+		t.Error(err)
+	}
+
+	wantDirectives := []string{
+		"error_page 401 403 =429 @apikey_auth_reject;",
+		"location @apikey_auth_reject {",
+	}
+	for _, want := range wantDirectives {
+		if !bytes.Contains(got, []byte(want)) {
+			t.Errorf("want %q in generated template", want)
+		}
+	}
+	t.Log(string(got))
+}
+
+func TestExecuteVirtualServerTemplateWithAPIKeyPolicyWithoutRejectCode(t *testing.T) {
+	t.Parallel()
+
+	vscfg := vsConfig()
+	vscfg.Server.APIKeyEnabled = true
+	vscfg.Server.APIKey = &APIKey{
+		Header:  []string{"X-header-name"},
+		MapName: "vs_default_cafe_apikey_policy",
+	}
+
+	for _, e := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+		got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+		if err != nil {
+			t.Error(err)
+		}
+		if bytes.Contains(got, []byte("@apikey_auth_reject")) {
+			t.Errorf("want no @apikey_auth_reject location without a reject code in generated template")
+		}
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithReturnBodies(t *testing.T) {
+	t.Parallel()
+
+	vscfg := VirtualServerConfig{
+		Maps: []Map{
+			{
+				Source:   "$http_accept",
+				Variable: "$vs_default_cafe_return_0",
+				Parameters: []Parameter{
+					{Value: "default", Result: "@return_0"},
+					{Value: `"~*application/json"`, Result: "@return_0"},
+					{Value: `"~*text/html"`, Result: "@return_0_1"},
+				},
+			},
+		},
+		Server: Server{
+			ServerName: "cafe.example.com",
+			Locations: []Location{
+				{
+					Path:                 "/hello",
+					ProxyInterceptErrors: true,
+					InternalProxyPass:    "http://unix:/var/lib/nginx/nginx-418-server.sock",
+					ErrorPages: []ErrorPage{
+						{Name: "$vs_default_cafe_return_0", Codes: "418", ResponseCode: 200},
+					},
+				},
+			},
+			ReturnLocations: []ReturnLocation{
+				{Name: "@return_0", DefaultType: "application/json", Return: Return{Text: `{\"hello\": true}`}},
+				{Name: "@return_0_1", DefaultType: "text/html", Return: Return{Text: "<p>hello</p>"}},
+			},
+		},
+	}
+
+	e := newTmplExecutorNGINX(t)
+	got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+	if err != nil {
+		t.Error(err)
+	}
+
+	wantDirectives := []string{
+		"map $http_accept $vs_default_cafe_return_0 {",
+		"default @return_0;",
+		`"~*application/json" @return_0;`,
+		`"~*text/html" @return_0_1;`,
+		`error_page 418 =200 "$vs_default_cafe_return_0";`,
+		"location @return_0 {",
+		`default_type "application/json";`,
+		"location @return_0_1 {",
+		`default_type "text/html";`,
+	}
+	for _, want := range wantDirectives {
+		if !bytes.Contains(got, []byte(want)) {
+			t.Errorf("want %q in generated template", want)
+		}
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithRouteRewrites(t *testing.T) {
+	t.Parallel()
+
+	vscfg := VirtualServerConfig{
+		Server: Server{
+			ServerName: "cafe.example.com",
+			Locations: []Location{
+				{
+					Path:          "/",
+					ProxyPass:     "http://test-upstream",
+					RouteRewrites: []string{`"^/old(.*)$" "/new$1" permanent`, `"^/tea$" "/coffee" last`},
+				},
+			},
+		},
+	}
+
+	want := []string{
+		`rewrite "^/old(.*)$" "/new$1" permanent;`,
+		`rewrite "^/tea$" "/coffee" last;`,
+	}
+	for _, e := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+		got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, w := range want {
+			if !bytes.Contains(got, []byte(w)) {
+				t.Errorf("want %q in generated template", w)
+			}
+		}
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithProxyIgnoreClientAbort(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		msg      string
+		location Location
+		want     string
+		notWant  string
+	}{
+		{
+			msg: "enabled",
+			location: Location{
+				Path:                   "/",
+				ProxyPass:              "http://test-upstream",
+				ProxyIgnoreClientAbort: "on",
+			},
+			want: "proxy_ignore_client_abort on;",
+		},
+		{
+			msg: "disabled",
+			location: Location{
+				Path:                   "/",
+				ProxyPass:              "http://test-upstream",
+				ProxyIgnoreClientAbort: "off",
+			},
+			want: "proxy_ignore_client_abort off;",
+		},
+		{
+			msg: "not set",
+			location: Location{
+				Path:      "/",
+				ProxyPass: "http://test-upstream",
+			},
+			notWant: "proxy_ignore_client_abort",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.msg, func(t *testing.T) {
+			t.Parallel()
+
+			vscfg := VirtualServerConfig{
+				Server: Server{
+					ServerName: "cafe.example.com",
+					Locations:  []Location{tc.location},
+				},
+			}
+
+			for _, e := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+				got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if tc.want != "" && !bytes.Contains(got, []byte(tc.want)) {
+					t.Errorf("want %q in generated template", tc.want)
+				}
+				if tc.notWant != "" && bytes.Contains(got, []byte(tc.notWant)) {
+					t.Errorf("did not want %q in generated template", tc.notWant)
+				}
+			}
+		})
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithProxySocketKeepalive(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		msg      string
+		location Location
+		want     string
+	}{
+		{
+			msg: "http upstream",
+			location: Location{
+				Path:                 "/",
+				ProxyPass:            "http://test-upstream",
+				ProxySocketKeepalive: true,
+			},
+			want: "proxy_socket_keepalive on;",
+		},
+		{
+			msg: "grpc upstream",
+			location: Location{
+				Path:                 "/",
+				GRPCPass:             "grpc://test-upstream",
+				ProxySocketKeepalive: true,
+			},
+			want: "grpc_socket_keepalive on;",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.msg, func(t *testing.T) {
+			t.Parallel()
+
+			vscfg := VirtualServerConfig{
+				Server: Server{
+					ServerName: "cafe.example.com",
+					Locations:  []Location{tc.location},
+				},
+			}
+
+			for _, e := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+				got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Contains(got, []byte(tc.want)) {
+					t.Errorf("want %q in generated template", tc.want)
+				}
+			}
+		})
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithExpires(t *testing.T) {
+	t.Parallel()
+
+	vscfg := VirtualServerConfig{
+		Server: Server{
+			ServerName: "cafe.example.com",
+			Locations: []Location{
+				{
+					Path:      "/static",
+					ProxyPass: "http://test-upstream",
+					Expires:   "30d",
+				},
+			},
+		},
+	}
+
+	for _, e := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+		got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Contains(got, []byte("expires 30d;")) {
+			t.Errorf("want %q in generated template", "expires 30d;")
+		}
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithSubFilter(t *testing.T) {
+	t.Parallel()
+
+	vscfg := VirtualServerConfig{
+		Server: Server{
+			ServerName: "cafe.example.com",
+			Locations: []Location{
+				{
+					Path:      "/legacy",
+					ProxyPass: "http://test-upstream",
+					SubFilter: &SubFilter{
+						Rules: []SubFilterRule{
+							{From: "http://legacy.internal", To: "https://cafe.example.com"},
+							{From: `href=\"/`, To: `href=\"/legacy/`},
+						},
+						Types: []string{"text/css", "application/javascript"},
+					},
+				},
+			},
+		},
+	}
+
+	want := []string{
+		`sub_filter "http://legacy.internal" "https://cafe.example.com";`,
+		`sub_filter "href=\"/" "href=\"/legacy/";`,
+		"sub_filter_once off;",
+		"sub_filter_types text/css application/javascript;",
+	}
+
+	for _, e := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+		got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, w := range want {
+			if !bytes.Contains(got, []byte(w)) {
+				t.Errorf("want %q in generated template", w)
+			}
+		}
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithUnderscoresInHeaders(t *testing.T) {
+	t.Parallel()
+
+	vscfg := vsConfig()
+	vscfg.Server.UnderscoresInHeaders = "on"
+
+	for _, e := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+		got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Contains(got, []byte("underscores_in_headers on;")) {
+			t.Errorf("want %q in generated template", "underscores_in_headers on;")
+		}
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithHTTP3(t *testing.T) {
+	t.Parallel()
+
+	vscfg := vsConfig()
+	vscfg.Server.SSL = &SSL{
+		HTTP2:          true,
+		HTTP3:          true,
+		Certificate:    "cafe-secret.pem",
+		CertificateKey: "cafe-secret.pem",
+	}
+
+	wantDirectives := []string{
+		"listen 443 quic;",
+		"listen [::]:443 quic;",
+		`add_header Alt-Svc 'h3=":$server_port"; ma=86400' always;`,
+	}
+
+	for _, e := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+		got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range wantDirectives {
+			if !bytes.Contains(got, []byte(want)) {
+				t.Errorf("want %q in generated template", want)
+			}
+		}
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithMergeSlashes(t *testing.T) {
+	t.Parallel()
+
+	vscfg := vsConfig()
+	vscfg.Server.MergeSlashes = "off"
+
+	for _, e := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+		got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Contains(got, []byte("merge_slashes off;")) {
+			t.Errorf("want %q in generated template", "merge_slashes off;")
+		}
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithDefaultType(t *testing.T) {
+	t.Parallel()
+
+	vscfg := vsConfig()
+	vscfg.Server.DefaultType = "application/json"
+
+	want := `default_type "application/json";`
+	for _, e := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+		got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Contains(got, []byte(want)) {
+			t.Errorf("want %q in generated template", want)
+		}
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithDownServers(t *testing.T) {
+	t.Parallel()
+
+	vscfg := VirtualServerConfig{
+		Upstreams: []Upstream{
+			{
+				Name: "test-upstream",
+				Servers: []UpstreamServer{
+					{
+						Address: "10.0.0.20:8001",
+					},
+					{
+						Address: "10.0.0.30:8001",
+						Down:    true,
+					},
+				},
+				MaxFails:    1,
+				MaxConns:    0,
+				FailTimeout: "10s",
+			},
+		},
+		Server: Server{
+			ServerName: "cafe.example.com",
+		},
+	}
+
+	for _, e := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+		got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Contains(got, []byte("server 10.0.0.30:8001 max_fails=1 fail_timeout=10s max_conns=0 down;")) {
+			t.Errorf("want the down server in generated template")
+		}
+		if !bytes.Contains(got, []byte("server 10.0.0.20:8001 max_fails=1 fail_timeout=10s max_conns=0;")) {
+			t.Errorf("want the up server without down in generated template")
+		}
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithDrainServers(t *testing.T) {
+	t.Parallel()
+
+	vscfg := VirtualServerConfig{
+		Upstreams: []Upstream{
+			{
+				Name: "test-upstream",
+				Servers: []UpstreamServer{
+					{
+						Address: "10.0.0.20:8001",
+					},
+					{
+						Address: "10.0.0.30:8001",
+						Drain:   true,
+					},
+				},
+				MaxFails:    1,
+				MaxConns:    0,
+				FailTimeout: "10s",
+			},
+		},
+		Server: Server{
+			ServerName: "cafe.example.com",
+		},
+	}
+
+	executor := newTmplExecutorNGINXPlus(t)
+	got, err := executor.ExecuteVirtualServerTemplate(&vscfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(got, []byte("server 10.0.0.30:8001 max_fails=1 fail_timeout=10s max_conns=0 drain;")) {
+		t.Errorf("want the draining server in generated template")
+	}
+	if !bytes.Contains(got, []byte("server 10.0.0.20:8001 max_fails=1 fail_timeout=10s max_conns=0;")) {
+		t.Errorf("want the up server without drain in generated template")
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithNextUpstream(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		location Location
+		want     []string
+		notWant  string
+		msg      string
+	}{
+		{
+			location: Location{
+				Path:                     "/",
+				GRPCPass:                 "grpc://vs_default_cafe_tea",
+				ProxyNextUpstream:        "error timeout non_idempotent",
+				ProxyNextUpstreamTimeout: "5s",
+				ProxyNextUpstreamTries:   3,
+			},
+			want: []string{
+				"grpc_next_upstream error timeout non_idempotent;",
+				"grpc_next_upstream_timeout 5s;",
+				"grpc_next_upstream_tries 3;",
+			},
+			notWant: "proxy_next_upstream",
+			msg:     "grpc",
+		},
+		{
+			location: Location{
+				Path:                     "/",
+				ProxyPass:                "http://vs_default_cafe_tea",
+				ProxyNextUpstream:        "error timeout",
+				ProxyNextUpstreamTimeout: "5s",
+				ProxyNextUpstreamTries:   3,
+			},
+			want: []string{
+				"proxy_next_upstream error timeout;",
+				"proxy_next_upstream_timeout 5s;",
+				"proxy_next_upstream_tries 3;",
+			},
+			notWant: "grpc_next_upstream",
+			msg:     "http",
+		},
+	}
+
+	for _, test := range tests {
+		vscfg := VirtualServerConfig{
+			Server: Server{
+				ServerName: "cafe.example.com",
+				Locations:  []Location{test.location},
+			},
+		}
+		for _, e := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+			got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range test.want {
+				if !bytes.Contains(got, []byte(want)) {
+					t.Errorf("want %q in generated template for the case of %s", want, test.msg)
+				}
+			}
+			if bytes.Contains(got, []byte(test.notWant)) {
+				t.Errorf("want no %q in generated template for the case of %s", test.notWant, test.msg)
+			}
+		}
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithProxySSLSessionReuse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		location Location
+		want     string
+		notWant  string
+		msg      string
+	}{
+		{
+			location: Location{Path: "/", ProxyPass: "https://vs_default_cafe_tea", ProxySSLSessionReuse: "off"},
+			want:     "proxy_ssl_session_reuse off;",
+			msg:      "off",
+		},
+		{
+			location: Location{Path: "/", GRPCPass: "grpcs://vs_default_cafe_tea", ProxySSLSessionReuse: "on"},
+			want:     "grpc_ssl_session_reuse on;",
+			msg:      "grpc",
+		},
+		{
+			location: Location{Path: "/", ProxyPass: "https://vs_default_cafe_tea"},
+			notWant:  "_ssl_session_reuse",
+			msg:      "unset",
+		},
+	}
+
+	for _, test := range tests {
+		vscfg := VirtualServerConfig{
+			Server: Server{
+				ServerName: "cafe.example.com",
+				Locations:  []Location{test.location},
+			},
+		}
+		for _, e := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+			got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if test.want != "" && !bytes.Contains(got, []byte(test.want)) {
+				t.Errorf("want %q in generated template for the case of %s", test.want, test.msg)
+			}
+			if test.notWant != "" && bytes.Contains(got, []byte(test.notWant)) {
+				t.Errorf("want no %q in generated template for the case of %s", test.notWant, test.msg)
+			}
+		}
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithFilesLocation(t *testing.T) {
+	t.Parallel()
+
+	vscfg := VirtualServerConfig{
+		Server: Server{
+			ServerName: "cafe.example.com",
+			Locations: []Location{
+				{
+					Path:     "/",
+					Root:     "/usr/share/nginx/html",
+					TryFiles: []string{"$uri", "$uri/", "/index.html"},
+				},
+			},
+		},
+	}
+
+	for _, e := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+		got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "root /usr/share/nginx/html;\n        try_files $uri $uri/ /index.html;"
+		if !bytes.Contains(got, []byte(want)) {
+			t.Errorf("want %q in generated template", want)
+		}
+		if bytes.Contains(got, []byte("proxy_pass ")) {
+			t.Error("want no proxy_pass in generated template")
+		}
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithServerAddHeaders(t *testing.T) {
+	t.Parallel()
+
+	vscfg := VirtualServerConfig{
+		Server: Server{
+			ServerName: "cafe.example.com",
+			AddHeaders: []AddHeader{
+				{
+					Header: Header{Name: "Server-Timing", Value: "total;dur=${request_time}"},
+					Always: true,
+				},
+				{
+					Header: Header{Name: "X-Frame-Options", Value: "DENY"},
+				},
+			},
+			Locations: []Location{
+				{
+					Path:      "/",
+					ProxyPass: "http://test-upstream",
+					AddHeaders: []AddHeader{
+						{
+							Header: Header{Name: "X-Location", Value: "tea"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, e := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+		got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Contains(got, []byte("\n    add_header Server-Timing \"total;dur=${request_time}\" always;")) {
+			t.Errorf("want the server level header with always in generated template")
+		}
+		if !bytes.Contains(got, []byte("\n    add_header X-Frame-Options \"DENY\" ;")) {
+			t.Errorf("want the server level header without always in generated template")
+		}
+		if !bytes.Contains(got, []byte("\n        add_header X-Location \"tea\" ;")) {
+			t.Errorf("want the location level header in generated template")
+		}
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithKeepaliveTime(t *testing.T) {
+	t.Parallel()
+
+	vscfg := VirtualServerConfig{
+		Upstreams: []Upstream{
+			{
+				Name: "test-upstream",
+				Servers: []UpstreamServer{
+					{
+						Address: "example.com",
+					},
+				},
+				Resolve:       true,
+				Keepalive:     32,
+				KeepaliveTime: "5m",
+				MaxFails:      1,
+				FailTimeout:   "10s",
+			},
+		},
+		Server: Server{
+			ServerName: "cafe.example.com",
+		},
+	}
+
+	for _, e := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+		got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Contains(got, []byte("keepalive 32;\n    keepalive_time 5m;")) {
+			t.Errorf("want keepalive_time after keepalive in generated template")
+		}
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithProxyMethod(t *testing.T) {
+	t.Parallel()
+
+	vscfg := VirtualServerConfig{
+		Server: Server{
+			ServerName: "cafe.example.com",
+			Locations: []Location{
+				{
+					Path:        "/api",
+					ProxyPass:   "http://test-upstream",
+					ProxyMethod: "POST",
+				},
+			},
+		},
+	}
+
+	for _, e := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+		got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Contains(got, []byte("proxy_method POST;")) {
+			t.Errorf("want proxy_method POST in generated template")
+		}
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithProxyPassRequestBodyOff(t *testing.T) {
+	t.Parallel()
+
+	vscfg := VirtualServerConfig{
+		Server: Server{
+			ServerName: "cafe.example.com",
+			Locations: []Location{
+				{
+					Path:                 "/auth",
+					ProxyPass:            "http://test-upstream",
+					ProxyPassRequestBody: "off",
+					ProxySetHeaders:      []Header{{Name: "Content-Length", Value: ""}},
+				},
+			},
+		},
+	}
+
+	for _, e := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+		got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range []string{"proxy_pass_request_body off;", `proxy_set_header Content-Length "";`} {
+			if !bytes.Contains(got, []byte(want)) {
+				t.Errorf("want %q in generated template", want)
+			}
+		}
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithErrorLog(t *testing.T) {
+	t.Parallel()
+
+	vscfg := VirtualServerConfig{
+		Server: Server{
+			ServerName: "cafe.example.com",
+			ErrorLog: &ErrorLog{
+				Destination: "stderr",
+				Level:       "debug",
+			},
+		},
+	}
+
+	for _, e := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+		got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Contains(got, []byte("error_log stderr debug;")) {
+			t.Errorf("want error_log stderr debug in generated template")
+		}
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithBodylessReturn(t *testing.T) {
+	t.Parallel()
+
+	vscfg := VirtualServerConfig{
+		Server: Server{
+			ServerName: "cafe.example.com",
+			Locations: []Location{
+				{
+					Path:                 "/empty",
+					ProxyInterceptErrors: true,
+					InternalProxyPass:    "http://unix:/var/lib/nginx/nginx-418-server.sock",
+					ErrorPages: []ErrorPage{
+						{Name: "@return_0", Codes: "418", ResponseCode: 204},
+					},
+				},
+			},
+			ReturnLocations: []ReturnLocation{
+				{Name: "@return_0"},
+			},
+		},
+	}
+
+	for _, e := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+		got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := `error_page 418 =204 "@return_0";`
+		if !bytes.Contains(got, []byte(want)) {
+			t.Errorf("want %q in generated template", want)
+		}
+		if bytes.Contains(got, []byte("default_type")) {
+			t.Errorf("did not want default_type in generated template")
+		}
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithClientIPReturn(t *testing.T) {
+	t.Parallel()
+
+	vscfg := VirtualServerConfig{
+		Geos: []Geo{
+			{
+				Source:   "$remote_addr",
+				Variable: "$vs_default_cafe_client_ip_return_0",
+				Parameters: []Parameter{
+					{Value: "default", Result: "0"},
+					{Value: "10.0.0.0/8", Result: "1"},
+				},
+			},
+		},
+		Server: Server{
+			ServerName: "cafe.example.com",
+			Locations: []Location{
+				{
+					Path:      "/tea",
+					ProxyPass: "http://test-upstream",
+					ClientIPReturn: &ClientIPReturn{
+						Variable: "$vs_default_cafe_client_ip_return_0",
+						Code:     403,
+					},
+				},
+				{
+					Path:      "/coffee",
+					ProxyPass: "http://test-upstream",
+				},
+			},
+		},
+	}
+
+	want := []string{
+		"geo $remote_addr $vs_default_cafe_client_ip_return_0 {\n    default 0;\n    10.0.0.0/8 1;\n}",
+		"if ($vs_default_cafe_client_ip_return_0) {\n            return 403;\n        }",
+	}
+
+	for _, e := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+		got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, w := range want {
+			if !bytes.Contains(got, []byte(w)) {
+				t.Errorf("want %q in generated template", w)
+			}
+		}
+		if n := bytes.Count(got, []byte("return 403;")); n != 1 {
+			t.Errorf("want the client IP return only in the location with the client IP return, got %d occurrences", n)
+		}
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithWebSocketUpstream(t *testing.T) {
+	t.Parallel()
+
+	vscfg := VirtualServerConfig{
+		Server: Server{
+			ServerName: "cafe.example.com",
+			Locations: []Location{
+				{
+					Path:             "/ws",
+					ProxyPass:        "http://test-upstream",
+					ProxyReadTimeout: "1h",
+				},
+			},
+		},
+	}
+
+	want := []string{
+		"proxy_read_timeout 1h;",
+		"proxy_http_version 1.1;",
+		"proxy_set_header Upgrade $http_upgrade;",
+		"proxy_set_header Connection $vs_connection_header;",
+	}
+
+	for _, e := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+		got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, w := range want {
+			if !bytes.Contains(got, []byte(w)) {
+				t.Errorf("want %q in generated template", w)
+			}
+		}
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithNextUpstreamOff(t *testing.T) {
+	t.Parallel()
+
+	vscfg := VirtualServerConfig{
+		Server: Server{
+			ServerName: "cafe.example.com",
+			Locations: []Location{
+				{
+					Path:              "/tea",
+					ProxyPass:         "http://test-upstream",
+					ProxyNextUpstream: "off",
+				},
+				{
+					Path:                     "/coffee",
+					ProxyPass:                "http://test-upstream",
+					ProxyNextUpstream:        "error timeout",
+					ProxyNextUpstreamTimeout: "10s",
+					ProxyNextUpstreamTries:   3,
+				},
+			},
+		},
+	}
+
+	for _, e := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+		got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Contains(got, []byte("proxy_next_upstream off;")) {
+			t.Errorf("want proxy_next_upstream off in generated template")
+		}
+		if !bytes.Contains(got, []byte("proxy_next_upstream_timeout 10s;\n        proxy_next_upstream_tries 3;")) {
+			t.Errorf("want proxy_next_upstream_timeout and proxy_next_upstream_tries for the bounded location in generated template")
+		}
+		if n := bytes.Count(got, []byte("proxy_next_upstream_timeout")); n != 1 {
+			t.Errorf("want proxy_next_upstream_timeout to be omitted for the location with next upstream off, got %d occurrences", n)
+		}
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithRateLimitTierVariable(t *testing.T) {
+	t.Parallel()
+
+	vscfg := VirtualServerConfig{
+		Server: Server{
+			ServerName: "cafe.example.com",
+			LimitReqs: []LimitReq{
+				{ZoneName: "pol_rl_default_premium_rate_limit_policy_default_cafe_vs"},
+			},
+			Variables: []Variable{
+				{Name: "$rl_tier", Value: "$rl_default_cafe_vs_group_user_type_tier_spec_Lw"},
+			},
+			Locations: []Location{
+				{
+					Path:      "/tea",
+					ProxyPass: "http://test-upstream",
+					LimitReqs: []LimitReq{
+						{ZoneName: "pol_rl_default_basic_rate_limit_policy_default_cafe_vs"},
+					},
+					Variables: []Variable{
+						{Name: "$rl_tier", Value: "$rl_default_cafe_vs_group_user_type_tier_route_L3RlYQ"},
+					},
+				},
+			},
+		},
+	}
+
+	for _, e := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+		got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Contains(got, []byte("\n    set $rl_tier $rl_default_cafe_vs_group_user_type_tier_spec_Lw;")) {
+			t.Errorf("want set $rl_tier for the server in generated template")
+		}
+		if !bytes.Contains(got, []byte("\n        set $rl_tier $rl_default_cafe_vs_group_user_type_tier_route_L3RlYQ;")) {
+			t.Errorf("want set $rl_tier for the location in generated template")
+		}
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithUpstreamResolverForPlus(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		resolver *UpstreamResolver
+		want     string
+		msg      string
+	}{
+		{
+			resolver: &UpstreamResolver{Addresses: []string{"kube-dns.kube-system.svc.cluster.local"}, Valid: "5s", IPV6: true},
+			want:     "resolver kube-dns.kube-system.svc.cluster.local valid=5s;",
+			msg:      "resolver with IPv6",
+		},
+		{
+			resolver: &UpstreamResolver{Addresses: []string{"10.0.0.10", "10.0.0.11"}, Valid: "1s"},
+			want:     "resolver 10.0.0.10 10.0.0.11 valid=1s ipv6=off;",
+			msg:      "resolver without IPv6",
+		},
+	}
+
+	executor := newTmplExecutorNGINXPlus(t)
+	for _, test := range tests {
+		vscfg := VirtualServerConfig{
+			Upstreams: []Upstream{
+				{
+					Name:     "vs_default_cafe_tea",
+					Servers:  []UpstreamServer{{Address: "tea.example.com:80"}},
+					Resolve:  true,
+					Resolver: test.resolver,
+				},
+			},
+			Server: Server{
+				ServerName: "cafe.example.com",
+			},
+		}
+
+		got, err := executor.ExecuteVirtualServerTemplate(&vscfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Contains(got, []byte(test.want)) {
+			t.Errorf("want %q in generated template for the case of %s", test.want, test.msg)
+		}
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithUpstreamFullName(t *testing.T) {
+	t.Parallel()
+
+	vscfg := VirtualServerConfig{
+		Upstreams: []Upstream{
+			{
+				Name:     "vs_1a2b3c4d_tea",
+				FullName: "vs_default_cafe_vsr_default_tea_tea",
+				Servers:  []UpstreamServer{{Address: "10.0.0.20:80"}},
+			},
+		},
+		Server: Server{
+			ServerName: "cafe.example.com",
+		},
+	}
+
+	want := "# vs_default_cafe_vsr_default_tea_tea\nupstream vs_1a2b3c4d_tea {"
+	for _, executor := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+		got, err := executor.ExecuteVirtualServerTemplate(&vscfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Contains(got, []byte(want)) {
+			t.Errorf("want %q in generated template", want)
+		}
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithDebugHeaders(t *testing.T) {
+	t.Parallel()
+
+	vscfg := VirtualServerConfig{
+		Server: Server{
+			ServerName: "cafe.example.com",
+			AddHeaders: []AddHeader{
+				{Header: Header{Name: "X-Upstream-Addr", Value: "${upstream_addr}"}, Always: true},
+			},
+			Locations: []Location{
+				{
+					Path:      "/tea",
+					ProxyPass: "http://vs_default_cafe_tea",
+					AddHeaders: []AddHeader{
+						{Header: Header{Name: "X-Upstream-Response-Time", Value: "${upstream_response_time}"}},
+					},
+				},
+			},
+		},
+	}
+
+	want := []string{
+		`add_header X-Upstream-Addr "${upstream_addr}" always;`,
+		`add_header X-Upstream-Response-Time "${upstream_response_time}" ;`,
+	}
+	for _, executor := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+		got, err := executor.ExecuteVirtualServerTemplate(&vscfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, w := range want {
+			if !bytes.Contains(got, []byte(w)) {
+				t.Errorf("want %q in generated template", w)
+			}
+		}
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithEgressMTLSSSLNameVariable(t *testing.T) {
+	t.Parallel()
+
+	vscfg := VirtualServerConfig{
+		Server: Server{
+			ServerName: "cafe.example.com",
+			EgressMTLS: &EgressMTLS{
+				Certificate:    "/etc/nginx/secrets/default-egress-mtls-secret",
+				CertificateKey: "/etc/nginx/secrets/default-egress-mtls-secret",
+				Ciphers:        "DEFAULT",
+				Protocols:      "TLSv1 TLSv1.1 TLSv1.2",
+				VerifyDepth:    1,
+				SessionReuse:   true,
+				ServerName:     true,
+				SSLName:        "$http_x_tenant",
+			},
+		},
+	}
+
+	want := "proxy_ssl_server_name on;\n    proxy_ssl_name $http_x_tenant;"
+	for _, executor := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+		got, err := executor.ExecuteVirtualServerTemplate(&vscfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Contains(got, []byte(want)) {
+			t.Errorf("want %q in generated template", want)
+		}
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithSatisfyAny(t *testing.T) {
+	t.Parallel()
+
+	vscfg := VirtualServerConfig{
+		Server: Server{
+			ServerName: "cafe.example.com",
+			Allow:      []string{"10.0.0.0/8"},
+			Satisfy:    "any",
+			BasicAuth: &BasicAuth{
+				Secret: "/etc/nginx/secrets/default-htpasswd-secret",
+				Realm:  "My Test API",
+			},
+			Locations: []Location{
+				{
+					Path:      "/tea",
+					ProxyPass: "http://vs_default_cafe_tea",
+				},
+				{
+					Path:      "/coffee",
+					ProxyPass: "http://vs_default_cafe_coffee",
+					Allow:     []string{"192.168.0.0/16"},
+				},
+			},
+		},
+	}
+
+	want := []string{
+		"allow 10.0.0.0/8;\n    deny all;\n    satisfy any;",
+		"auth_basic \"My Test API\";",
+		"allow 192.168.0.0/16;\n        deny all;\n        satisfy all;",
+	}
+	for _, executor := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+		got, err := executor.ExecuteVirtualServerTemplate(&vscfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, w := range want {
+			if !bytes.Contains(got, []byte(w)) {
+				t.Errorf("want %q in generated template", w)
+			}
+		}
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithHealthCheckHeaderVariables(t *testing.T) {
+	t.Parallel()
+
+	vscfg := VirtualServerConfig{
+		Server: Server{
+			ServerName: "cafe.example.com",
+			HealthChecks: []HealthCheck{
+				{
+					Name:          "coffee",
+					URI:           "/",
+					Interval:      "5s",
+					Jitter:        "0s",
+					Fails:         1,
+					Passes:        1,
+					ProxyPass:     "http://coffee-v2",
+					KeepaliveTime: "60s",
+					Headers: map[string]string{
+						"Host":           "${server_name}",
+						"X-Health-Check": "${hostname}-${time_iso8601}",
+					},
+				},
+			},
+		},
+	}
+
+	got, err := newTmplExecutorNGINXPlus(t).ExecuteVirtualServerTemplate(&vscfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		`proxy_set_header Host "${server_name}";`,
+		`proxy_set_header X-Health-Check "${hostname}-${time_iso8601}";`,
+	}
+	for _, w := range want {
+		if !bytes.Contains(got, []byte(w)) {
+			t.Errorf("want %q in generated template", w)
+		}
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithHealthCheckKeepaliveTime(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		keepaliveTime string
+		want          string
+		notWant       string
+		msg           string
+	}{
+		{
+			keepaliveTime: "60s",
+			want:          "passes=1 keepalive_time=60s;",
+			msg:           "keepalive time supported",
+		},
+		{
+			keepaliveTime: "",
+			want:          "passes=1;",
+			notWant:       "keepalive_time=",
+			msg:           "keepalive time not supported",
+		},
+	}
+
+	for _, test := range tests {
+		vscfg := VirtualServerConfig{
+			Server: Server{
+				ServerName: "cafe.example.com",
+				HealthChecks: []HealthCheck{
+					{
+						Name:          "coffee",
+						URI:           "/",
+						Interval:      "5s",
+						Jitter:        "0s",
+						Fails:         1,
+						Passes:        1,
+						ProxyPass:     "http://coffee-v2",
+						KeepaliveTime: test.keepaliveTime,
+					},
+				},
+			},
+		}
+
+		got, err := newTmplExecutorNGINXPlus(t).ExecuteVirtualServerTemplate(&vscfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Contains(got, []byte(test.want)) {
+			t.Errorf("want %q in generated template for the case of %s", test.want, test.msg)
+		}
+		if test.notWant != "" && bytes.Contains(got, []byte(test.notWant)) {
+			t.Errorf("did not want %q in generated template for the case of %s", test.notWant, test.msg)
+		}
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithHealthCheckKeepaliveDisabled(t *testing.T) {
+	t.Parallel()
+
+	vscfg := VirtualServerConfig{
+		Server: Server{
+			ServerName: "cafe.example.com",
+			HealthChecks: []HealthCheck{
+				{
+					Name:      "coffee",
+					URI:       "/",
+					Interval:  "5s",
+					Jitter:    "0s",
+					Fails:     1,
+					Passes:    1,
+					ProxyPass: "http://coffee-v2",
+					Headers: map[string]string{
+						"Connection": "close",
+					},
+				},
+			},
+		},
+	}
+
+	got, err := newTmplExecutorNGINXPlus(t).ExecuteVirtualServerTemplate(&vscfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `proxy_set_header Connection "close";`
+	if !bytes.Contains(got, []byte(want)) {
+		t.Errorf("want %q in generated template", want)
+	}
+	if bytes.Contains(got, []byte("keepalive_time=")) {
+		t.Errorf("did not want keepalive_time in generated template")
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithVolatileMap(t *testing.T) {
+	t.Parallel()
+
+	vscfg := VirtualServerConfig{
+		Maps: []Map{
+			{
+				Source:   "$jwt_claim_aud",
+				Variable: "$jwt_default_jwt_policy_virtualserver_default_cafe_aud",
+				Parameters: []Parameter{
+					{Value: "default", Result: "0"},
+					{Value: `"api"`, Result: "1"},
+				},
+				Volatile: true,
+			},
+			{
+				Source:   "$request_method",
+				Variable: "$vs_default_cafe_matches_0",
+				Parameters: []Parameter{
+					{Value: "default", Result: "0"},
+					{Value: `"GET"`, Result: "1"},
+				},
+			},
+		},
+		Server: Server{
+			ServerName: "cafe.example.com",
+		},
+	}
+
+	want := "map $jwt_claim_aud $jwt_default_jwt_policy_virtualserver_default_cafe_aud {\n    volatile;\n    default 0;"
+	notWant := "map $request_method $vs_default_cafe_matches_0 {\n    volatile;"
+	for _, executor := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+		got, err := executor.ExecuteVirtualServerTemplate(&vscfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Contains(got, []byte(want)) {
+			t.Errorf("want %q in generated template", want)
+		}
+		if bytes.Contains(got, []byte(notWant)) {
+			t.Errorf("did not want %q in generated template", notWant)
+		}
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithTracing(t *testing.T) {
+	t.Parallel()
+
+	vscfg := VirtualServerConfig{
+		Server: Server{
+			ServerName: "cafe.example.com",
+			Tracing:    &Tracing{Enable: false},
+			Locations: []Location{
+				{
+					Path:      "/tea",
+					ProxyPass: "http://vs_default_cafe_tea",
+					Tracing:   &Tracing{Enable: true, Context: "propagate", Route: "/tea"},
+				},
+			},
+		},
+	}
+
+	want := []string{
+		"otel_trace off;",
+		"otel_trace on;",
+		"otel_trace_context propagate;",
+		`otel_span_attr http.route "/tea";`,
+	}
+	for _, executor := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+		got, err := executor.ExecuteVirtualServerTemplate(&vscfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, w := range want {
+			if !bytes.Contains(got, []byte(w)) {
+				t.Errorf("want %q in generated template", w)
+			}
+		}
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithUpstreamServerPodNames(t *testing.T) {
+	t.Parallel()
+
+	vscfg := VirtualServerConfig{
+		Upstreams: []Upstream{
+			{
+				Name: "vs_default_cafe_tea",
+				Servers: []UpstreamServer{
+					{Address: "10.0.0.20:80", PodName: "tea-7d4f8b9c6-x2kzq"},
+					{Address: "10.0.0.21:80"},
+				},
+			},
+		},
+		Server: Server{
+			ServerName: "cafe.example.com",
+		},
+	}
+
+	for _, executor := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+		got, err := executor.ExecuteVirtualServerTemplate(&vscfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Contains(got, []byte("; # pod: tea-7d4f8b9c6-x2kzq\n")) {
+			t.Errorf("want the pod name comment of the server 10.0.0.20:80 in generated template")
+		}
+		if n := bytes.Count(got, []byte("# pod:")); n != 1 {
+			t.Errorf("want the pod name comment only for the server with the pod name, got %d comments", n)
+		}
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithTLSProfile(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		ssl     *SSL
+		want    []string
+		notWant []string
+		msg     string
+	}{
 		{
-			Address: "clustertwo.corp.local:8080",
+			ssl: &SSL{
+				Certificate:    "cafe-secret.pem",
+				CertificateKey: "cafe-secret.pem",
+				Protocols:      "TLSv1.3",
+			},
+			want:    []string{"ssl_protocols TLSv1.3;"},
+			notWant: []string{"ssl_ciphers"},
+			msg:     "modern profile",
+		},
+		{
+			ssl: &SSL{
+				Certificate:    "cafe-secret.pem",
+				CertificateKey: "cafe-secret.pem",
+				Protocols:      "TLSv1.2 TLSv1.3",
+				Ciphers:        "HIGH:!aNULL:!MD5",
+			},
+			want: []string{"ssl_protocols TLSv1.2 TLSv1.3;", "ssl_ciphers HIGH:!aNULL:!MD5;"},
+			msg:  "custom profile",
+		},
+		{
+			ssl: &SSL{
+				Certificate:    "cafe-secret.pem",
+				CertificateKey: "cafe-secret.pem",
+			},
+			notWant: []string{"ssl_protocols", "ssl_ciphers"},
+			msg:     "no profile",
 		},
-	}
-	e := newTmplExecutorNGINXPlus(t)
-	got, err := e.ExecuteTransportServerTemplate(&tsCfg)
-	if err != nil {
-		t.Error(err)
 	}
 
-	want := fmt.Sprintf("server %s resolve backup;", tsCfg.Upstreams[0].BackupServers[0].Address)
-	if !bytes.Contains(got, []byte(want)) {
-		t.Errorf("want backup %q in the transport server config", want)
+	for _, test := range tests {
+		vscfg := VirtualServerConfig{
+			Server: Server{
+				ServerName: "cafe.example.com",
+				SSL:        test.ssl,
+			},
+		}
+		for _, e := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+			got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range test.want {
+				if !bytes.Contains(got, []byte(want)) {
+					t.Errorf("want %q in generated template for the case of %s", want, test.msg)
+				}
+			}
+			for _, notWant := range test.notWant {
+				if bytes.Contains(got, []byte(notWant)) {
+					t.Errorf("want no %q in generated template for the case of %s", notWant, test.msg)
+				}
+			}
+		}
 	}
-	snaps.MatchSnapshot(t, string(got))
-	t.Log(string(got))
 }
 
-func TestTransportServerWithSSL(t *testing.T) {
+func TestExecuteVirtualServerTemplateWithStrictHost(t *testing.T) {
 	t.Parallel()
-	executor := newTmplExecutorNGINXPlus(t)
-	data, err := executor.ExecuteTransportServerTemplate(&transportServerCfgWithSSL)
-	if err != nil {
-		t.Errorf("Failed to execute template: %v", err)
+
+	tests := []struct {
+		strictHost *StrictHost
+		want       string
+		msg        string
+	}{
+		{
+			strictHost: &StrictHost{Host: "cafe.example.com", Code: 421},
+			want:       "if ($host != \"cafe.example.com\") {\n        return 421;\n    }",
+			msg:        "exact host",
+		},
+		{
+			strictHost: &StrictHost{Host: `^.+\.example\.com$`, Regex: true, Code: 400},
+			want:       "if ($host !~ \"^.+\\.example\\.com$\") {\n        return 400;\n    }",
+			msg:        "wildcard host",
+		},
+	}
+
+	for _, test := range tests {
+		vscfg := VirtualServerConfig{
+			Server: Server{
+				ServerName: "cafe.example.com",
+				StrictHost: test.strictHost,
+			},
+		}
+		for _, e := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+			got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Contains(got, []byte(test.want)) {
+				t.Errorf("want %q in generated template for the case of %s", test.want, test.msg)
+			}
+		}
 	}
-	snaps.MatchSnapshot(t, string(data))
-	t.Log(string(data))
 }
 
-func TestTLSPassthroughHosts(t *testing.T) {
+func TestExecuteVirtualServerTemplateWithoutStrictHost(t *testing.T) {
 	t.Parallel()
-	executor := newTmplExecutorNGINX(t)
 
-	unixSocketsCfg := TLSPassthroughHostsConfig{
-		"app.example.com": "unix:/var/lib/nginx/passthrough-default_secure-app.sock",
+	vscfg := VirtualServerConfig{
+		Server: Server{
+			ServerName: "cafe.example.com",
+		},
 	}
-
-	data, err := executor.ExecuteTLSPassthroughHostsTemplate(&unixSocketsCfg)
-	if err != nil {
-		t.Errorf("Failed to execute template: %v", err)
+	for _, e := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+		got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Contains(got, []byte("if ($host")) {
+			t.Errorf("want no host check in generated template")
+		}
 	}
-	snaps.MatchSnapshot(t, string(data))
-	t.Log(string(data))
 }
 
-func TestExecuteVirtualServerTemplateWithJWKSWithToken(t *testing.T) {
+func TestExecuteVirtualServerTemplateWithTarpit(t *testing.T) {
 	t.Parallel()
-	executor := newTmplExecutorNGINXPlus(t)
-	got, err := executor.ExecuteVirtualServerTemplate(&virtualServerCfgWithJWTPolicyJWKSWithToken)
-	if err != nil {
-		t.Error(err)
-	}
-	if !bytes.Contains(got, []byte("token=$http_token")) {
-		t.Error("want `token=$http_token` in generated template")
-	}
 
-	if !bytes.Contains(got, []byte("proxy_ssl_server_name on;")) {
-		t.Error("want `proxy_ssl_server_name on;` in generated template")
+	vscfg := VirtualServerConfig{
+		Server: Server{
+			ServerName: "cafe.example.com",
+			Locations: []Location{
+				{
+					Path:      "/coffee",
+					ProxyPass: "http://test-upstream",
+					Tarpit: &Tarpit{
+						Variable: "$vs_default_cafe_tarpit_0_cond_0",
+						Location: "/internal_location_tarpit_0",
+					},
+				},
+				{
+					Path:      "/tea",
+					ProxyPass: "http://test-upstream",
+				},
+			},
+			TarpitLocations: []TarpitLocation{
+				{
+					Path:  "/internal_location_tarpit_0",
+					Delay: 5000,
+					Code:  429,
+				},
+			},
+		},
 	}
 
-	if !bytes.Contains(got, []byte("proxy_ssl_name sni.idp.spec.example.com;")) {
-		t.Error("want `proxy_ssl_name sni.idp.spec.example.com;` in generated template")
+	want := []string{
+		"if ($vs_default_cafe_tarpit_0_cond_0) {",
+		"rewrite ^ /internal_location_tarpit_0 last;",
+		"location /internal_location_tarpit_0 {",
+		"set $tarpit_delay 5000;",
+		"set $tarpit_code 429;",
+		"js_content tarpit.delay;",
 	}
 
-	snaps.MatchSnapshot(t, string(got))
-	t.Log(string(got))
-}
-
-func TestExecuteVirtualServerTemplateWithJWKSWithoutToken(t *testing.T) {
-	t.Parallel()
-	executor := newTmplExecutorNGINXPlus(t)
-	got, err := executor.ExecuteVirtualServerTemplate(&virtualServerCfgWithJWTPolicyJWKSWithoutToken)
-	if err != nil {
-		t.Error(err)
-	}
-	if bytes.Contains(got, []byte("token=$http_token")) {
-		t.Error("want no `token=$http_token` string in generated template")
+	for _, e := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+		got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, w := range want {
+			if !bytes.Contains(got, []byte(w)) {
+				t.Errorf("want %q in generated template", w)
+			}
+		}
+		if n := bytes.Count(got, []byte("rewrite ^ /internal_location_tarpit_0 last;")); n != 1 {
+			t.Errorf("want the tarpit rewrite only in the location with the tarpit, got %d occurrences", n)
+		}
 	}
-	snaps.MatchSnapshot(t, string(got))
-	t.Log(string(got))
 }
 
-func TestExecuteVirtualServerTemplateWithBackupServerNGINXPlus(t *testing.T) {
+func TestExecuteVirtualServerTemplateWithGRPCErrorLocations(t *testing.T) {
 	t.Parallel()
 
-	externalName := "clustertwo.corp.local:8080"
-	vscfg := vsConfig()
-	vscfg.Upstreams[0].BackupServers = []UpstreamServer{
-		{
-			Address: externalName,
+	vscfg := VirtualServerConfig{
+		Server: Server{
+			ServerName: "cafe.example.com",
+			SSL: &SSL{
+				HTTP2:          true,
+				Certificate:    "cafe-secret.pem",
+				CertificateKey: "cafe-secret.pem",
+			},
+			Locations: []Location{
+				{
+					Path:           "/grpc",
+					GRPCPass:       "grpc://grpc-app",
+					GRPCErrorPages: map[string]string{"unavailable": "@grpc_error_0"},
+				},
+			},
+			GRPCErrorLocations: []GRPCErrorLocation{
+				{
+					Name:    "@grpc_error_0",
+					Status:  8,
+					Message: "try again later",
+					Headers: []Header{{Name: "retry-after", Value: "1"}},
+				},
+			},
 		},
 	}
 
-	e := newTmplExecutorNGINXPlus(t)
-	got, err := e.ExecuteVirtualServerTemplate(&vscfg)
-	if err != nil {
-		t.Error(err)
+	want := []string{
+		"error_page 502 = @grpc_error_0;",
+		"error_page 408 = @grpc_deadline_exceeded;",
+		"location @grpc_error_0 {",
+		"add_header retry-after \"1\";",
+		"add_header grpc-status 8;",
+		"add_header grpc-message \"try again later\";",
 	}
 
-	want := fmt.Sprintf("server %s backup resolve;", externalName)
-	if !bytes.Contains(got, []byte(want)) {
-		t.Errorf("want %q in generated template", want)
+	for _, e := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+		got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, w := range want {
+			if !bytes.Contains(got, []byte(w)) {
+				t.Errorf("want %q in generated template", w)
+			}
+		}
 	}
-	snaps.MatchSnapshot(t, string(got))
-	t.Log(string(got))
 }
 
-func TestExecuteVirtualServerTemplateWithAPIKeyPolicyNGINXPlus(t *testing.T) {
+func TestExecuteVirtualServerTemplate_RendersSetHeaderDirectiveForUpstreamType(t *testing.T) {
 	t.Parallel()
 
-	vscfg := vsConfig()
-	vscfg.Server.APIKey = &APIKey{
-		Header:  []string{"X-header-name", "other-header"},
-		Query:   []string{"myQuery", "myOtherQuery"},
-		MapName: "vs_default_cafe_apikey_policy",
+	vscfg := VirtualServerConfig{
+		Server: Server{
+			ServerName: "cafe.example.com",
+			SSL: &SSL{
+				HTTP2:          true,
+				Certificate:    "cafe-secret.pem",
+				CertificateKey: "cafe-secret.pem",
+			},
+			Locations: []Location{
+				{
+					Path:     "/grpc",
+					GRPCPass: "grpc://grpc-app",
+					ProxySetHeaders: []Header{
+						{Name: "X-Tenant", Value: "cafe"},
+						{Name: "Host", Value: "$host"},
+					},
+				},
+				{
+					Path:      "/http",
+					ProxyPass: "http://http-app",
+					ProxySetHeaders: []Header{
+						{Name: "X-Tenant", Value: "cafe"},
+						{Name: "Host", Value: "$host"},
+					},
+				},
+			},
+		},
 	}
 
-	e := newTmplExecutorNGINXPlus(t)
-	got, err := e.ExecuteVirtualServerTemplate(&vscfg)
-	if err != nil {
-		t.Error(err)
+	executors := map[string]*TemplateExecutor{
+		"nginx":      newTmplExecutorNGINX(t),
+		"nginx-plus": newTmplExecutorNGINXPlus(t),
 	}
+	for name, e := range executors {
+		got, err := e.ExecuteVirtualServerTemplate(&vscfg)
+		if err != nil {
+			t.Fatal(err)
+		}
 
-	want := "js_var $header_query_value \"${http_x_header_name}${http_other_header}${arg_myQuery}${arg_myOtherQuery}\";"
+		grpcLocation, httpLocation, found := bytes.Cut(got, []byte("location /http {"))
+		if !found {
+			t.Fatalf("%s: location /http not found in generated template", name)
+		}
 
-	if !bytes.Contains(got, []byte(want)) {
-		t.Errorf("want %q in generated template", want)
+		for _, want := range []string{`grpc_set_header X-Tenant "cafe";`, `grpc_set_header Host "$host";`} {
+			if !bytes.Contains(grpcLocation, []byte(want)) {
+				t.Errorf("%s: want %q in gRPC location", name, want)
+			}
+		}
+		if bytes.Contains(grpcLocation, []byte("proxy_set_header")) {
+			t.Errorf("%s: unexpected proxy_set_header in gRPC location", name)
+		}
+
+		for _, want := range []string{`proxy_set_header X-Tenant "cafe";`, `proxy_set_header Host "$host";`} {
+			if !bytes.Contains(httpLocation, []byte(want)) {
+				t.Errorf("%s: want %q in HTTP location", name, want)
+			}
+		}
+		if bytes.Contains(httpLocation, []byte("grpc_set_header")) {
+			t.Errorf("%s: unexpected grpc_set_header in HTTP location", name)
+		}
 	}
-	snaps.MatchSnapshot(t, string(got))
-	t.Log(string(got))
 }
 
 func TestExecuteVirtualServerTemplate_WithCustomOIDCRedirectLocation(t *testing.T) {
@@ -961,6 +2787,28 @@ func TestExecuteVirtualServerTemplate_WithOIDCTLSVerify(t *testing.T) {
 	t.Log(string(got))
 }
 
+func TestExecuteOIDCTemplateWithResolvers(t *testing.T) {
+	t.Parallel()
+	executor := newTmplExecutorNGINXPlus(t)
+
+	got, err := executor.ExecuteOIDCTemplate(&OIDC{
+		AuthEndpoint:  "https://idp.example.com/auth",
+		TokenEndpoint: "https://idp.example.com/token",
+		JwksURI:       "https://idp.example.com/keys",
+		RedirectURI:   "/_codexch",
+		Resolvers:     []string{"127.0.0.1:5353"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(got, []byte("    resolver 127.0.0.1:5353; # For DNS lookup of IdP endpoints;")) {
+		t.Error("want the resolver of the OIDC policy in generated template")
+	}
+	if bytes.Contains(got, []byte("# resolver 8.8.8.8;")) {
+		t.Error("want no commented out resolver in generated template")
+	}
+}
+
 func TestExecuteVirtualServerTemplateWithOIDCAndPKCEPolicyNGINXPlus(t *testing.T) {
 	t.Parallel()
 
@@ -1278,6 +3126,178 @@ func TestExecuteVirtualServerTemplateWithExtendedCachePolicy(t *testing.T) {
 	t.Log(string(got))
 }
 
+func TestExecuteVirtualServerTemplateWithCacheLockOnlyWhenCacheEnabled(t *testing.T) {
+	t.Parallel()
+
+	lockDirectives := []string{
+		"proxy_cache_lock on;",
+		"proxy_cache_lock_timeout 5s;",
+		"proxy_cache_lock_age 10s;",
+	}
+
+	withCache := vsConfig()
+	withCache.Server.Cache = &Cache{
+		ZoneName:         "cache",
+		ZoneSize:         "10m",
+		CacheLock:        true,
+		CacheLockTimeout: "5s",
+		CacheLockAge:     "10s",
+	}
+	withoutCache := vsConfig()
+	withoutCache.Server.Cache = nil
+
+	for _, e := range []*TemplateExecutor{newTmplExecutorNGINX(t), newTmplExecutorNGINXPlus(t)} {
+		got, err := e.ExecuteVirtualServerTemplate(&withCache)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range lockDirectives {
+			if !bytes.Contains(got, []byte(want)) {
+				t.Errorf("want %q in generated template", want)
+			}
+		}
+
+		got, err = e.ExecuteVirtualServerTemplate(&withoutCache)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Contains(got, []byte("proxy_cache_lock")) {
+			t.Error("want no proxy_cache_lock directives in generated template without cache")
+		}
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithJWTClaimsRejectsWrongAudience(t *testing.T) {
+	t.Parallel()
+
+	audVariable := "$jwt_default_cafe_vs_default_jwt_policy_aud"
+	conf := vsConfig()
+	conf.Maps = append(conf.Maps, Map{
+		Source:   "$jwt_claim_aud",
+		Variable: audVariable,
+		Parameters: []Parameter{
+			{Value: "default", Result: "0"},
+			{Value: `"~(^|,)my-api(,|$)"`, Result: "1"},
+		},
+	})
+	conf.Server.JWTAuth = &JWTAuth{
+		Realm:   "My Api",
+		Secret:  "/etc/nginx/secrets/default-jwt-secret",
+		Require: []string{audVariable},
+	}
+
+	executor := newTmplExecutorNGINXPlus(t)
+	got, err := executor.ExecuteVirtualServerTemplate(&conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a token without the my-api audience maps to 0, which fails auth_jwt_require with 401
+	wantDirectives := []string{
+		"map $jwt_claim_aud " + audVariable + " {",
+		`"~(^|,)my-api(,|$)" 1;`,
+		"default 0;",
+		"auth_jwt_require " + audVariable + " error=401;",
+	}
+	for _, want := range wantDirectives {
+		if !bytes.Contains(got, []byte(want)) {
+			t.Errorf("want %q in generated template", want)
+		}
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithRouteStatusZones(t *testing.T) {
+	t.Parallel()
+
+	conf := vsConfig()
+	conf.Server.Locations = []Location{
+		{
+			Path:        "/tea",
+			ProxyPass:   "http://vs_default_cafe_tea",
+			ServiceName: "tea-svc",
+			StatusZone:  "tea-api",
+		},
+		{
+			Path:        "/coffee",
+			ProxyPass:   "http://vs_default_cafe_coffee",
+			ServiceName: "coffee-svc",
+			StatusZone:  "cafe.example.com/coffee",
+		},
+		{
+			Path:        "/",
+			ProxyPass:   "http://vs_default_cafe_coffee",
+			ServiceName: "coffee-svc",
+		},
+	}
+
+	executor := newTmplExecutorNGINXPlus(t)
+	got, err := executor.ExecuteVirtualServerTemplate(&conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantDirectives := []string{
+		`status_zone "tea-api";`,
+		`status_zone "cafe.example.com/coffee";`,
+		`status_zone "coffee-svc";`,
+	}
+	for _, want := range wantDirectives {
+		if !bytes.Contains(got, []byte(want)) {
+			t.Errorf("want %q in generated template", want)
+		}
+	}
+	if bytes.Contains(got, []byte(`status_zone "tea-svc";`)) {
+		t.Error("want the status zone of the route instead of the service in generated template")
+	}
+}
+
+func TestExecuteVirtualServerTemplateWithSharedStatusMatch(t *testing.T) {
+	t.Parallel()
+
+	conf := vsConfig()
+	conf.StatusMatches = []StatusMatch{
+		{
+			Name: "vs_default_cafe_match_healthy",
+			Code: "200-299",
+		},
+	}
+	conf.Server.HealthChecks = []HealthCheck{
+		{
+			Name:      "vs_default_cafe_tea",
+			ProxyPass: "http://vs_default_cafe_tea",
+			URI:       "/",
+			Interval:  "5s",
+			Jitter:    "0s",
+			Fails:     1,
+			Passes:    1,
+			Match:     "vs_default_cafe_match_healthy",
+		},
+		{
+			Name:      "vs_default_cafe_coffee",
+			ProxyPass: "http://vs_default_cafe_coffee",
+			URI:       "/",
+			Interval:  "5s",
+			Jitter:    "0s",
+			Fails:     1,
+			Passes:    1,
+			Match:     "vs_default_cafe_match_healthy",
+		},
+	}
+
+	executor := newTmplExecutorNGINXPlus(t)
+	got, err := executor.ExecuteVirtualServerTemplate(&conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n := bytes.Count(got, []byte("match vs_default_cafe_match_healthy {")); n != 1 {
+		t.Errorf("want 1 shared match block in generated template, got %d", n)
+	}
+	if n := bytes.Count(got, []byte("match=vs_default_cafe_match_healthy")); n != 2 {
+		t.Errorf("want 2 health checks referencing the shared match in generated template, got %d", n)
+	}
+}
+
 func TestExecuteVirtualServerTemplateWithCachePolicyOSS(t *testing.T) {
 	t.Parallel()
 	executor := newTmplExecutorNGINX(t)
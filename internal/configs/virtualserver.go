@@ -1,8 +1,15 @@
 package configs
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"math"
+	"net"
+	"net/url"
+	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -30,8 +37,15 @@ const (
 	subRouteContext                                 = "subroute"
 	keyvalZoneBasePath                              = "/etc/nginx/state_files"
 	splitClientsKeyValZoneSize                      = "100k"
+	splitWeightsKeyValZoneName                      = "split_weights"
 	splitClientAmountWhenWeightChangesDynamicReload = 101
 	defaultLogOutput                                = "syslog:server=localhost:514"
+	defaultTarpitCode                               = 429
+	defaultClientIPReturnCode                       = 403
+	defaultRequestIDHeader                          = "X-Request-ID"
+	defaultWebSocketReadTimeout                     = "1h"
+	autoRouteStatusZone                             = "auto"
+	defaultTracingContext                           = "propagate"
 )
 
 var grpcConflictingErrors = map[int]bool{
@@ -90,12 +104,17 @@ type VirtualServerEx struct {
 	HTTPIPv6                    string
 	HTTPSIPv4                   string
 	HTTPSIPv6                   string
+	HTTPListenerOptions         *conf_v1.ListenerOptions
+	HTTPSListenerOptions        *conf_v1.ListenerOptions
+	HTTP3ReusePort              bool
+	AdditionalListeners         []conf_v1.Listener
 	Endpoints                   map[string][]string
 	VirtualServerRoutes         []*conf_v1.VirtualServerRoute
 	VirtualServerSelectorRoutes map[string][]string
 	ExternalNameSvcs            map[string]bool
 	Policies                    map[string]*conf_v1.Policy
 	PodsByIP                    map[string]PodInfo
+	TerminatingEndpoints        map[string][]string
 	SecretRefs                  map[string]*secrets.SecretReference
 	ApPolRefs                   map[string]*unstructured.Unstructured
 	LogConfRefs                 map[string]*unstructured.Unstructured
@@ -159,8 +178,9 @@ func ParseResourceReference(resourceRef, defaultNamespace string) (namespace, re
 }
 
 type upstreamNamer struct {
-	prefix    string
-	namespace string
+	prefix     string
+	fullPrefix string
+	namespace  string
 }
 
 // NewUpstreamNamerForVirtualServer creates a new namer.
@@ -191,7 +211,9 @@ func NewUpstreamNamerForVirtualServerRoute(virtualServer *conf_v1.VirtualServer,
 
 func (namer *upstreamNamer) GetNameForUpstreamFromAction(action *conf_v1.Action) string {
 	var upstream string
-	if action.Proxy != nil && action.Proxy.Upstream != "" {
+	if action.ProxyPassURL != "" {
+		upstream = generateProxyPassURLUpstreamName(action.ProxyPassURL)
+	} else if action.Proxy != nil && action.Proxy.Upstream != "" {
 		upstream = action.Proxy.Upstream
 	} else {
 		upstream = action.Pass
@@ -204,6 +226,45 @@ func (namer *upstreamNamer) GetNameForUpstream(upstream string) string {
 	return fmt.Sprintf("%s_%s", namer.prefix, upstream)
 }
 
+// GetFullNameForUpstream returns the name of the upstream with the full prefix, even if the prefix of the namer is shortened.
+func (namer *upstreamNamer) GetFullNameForUpstream(upstream string) string {
+	if namer.fullPrefix == "" {
+		return namer.GetNameForUpstream(upstream)
+	}
+	return fmt.Sprintf("%s_%s", namer.fullPrefix, upstream)
+}
+
+// shortenPrefix replaces the prefix of the namer with the kind of the resource followed by the first 8 characters
+// of the hash of the full prefix, so that the names of the upstreams don't grow with the names of the resources.
+func (namer *upstreamNamer) shortenPrefix() *upstreamNamer {
+	if namer.fullPrefix != "" {
+		return namer
+	}
+	kind, _, _ := strings.Cut(namer.prefix, "_")
+	hash := sha256.Sum256([]byte(namer.prefix))
+	namer.fullPrefix = namer.prefix
+	namer.prefix = fmt.Sprintf("%s_%s", kind, hex.EncodeToString(hash[:])[:8])
+	return namer
+}
+
+// newUpstreamNamerForVirtualServer creates a new namer, which shortens the prefix of the names if shortNames is true.
+func newUpstreamNamerForVirtualServer(virtualServer *conf_v1.VirtualServer, shortNames bool) *upstreamNamer {
+	namer := NewUpstreamNamerForVirtualServer(virtualServer)
+	if shortNames {
+		return namer.shortenPrefix()
+	}
+	return namer
+}
+
+// newUpstreamNamerForVirtualServerRoute creates a new namer, which shortens the prefix of the names if shortNames is true.
+func newUpstreamNamerForVirtualServerRoute(virtualServer *conf_v1.VirtualServer, virtualServerRoute *conf_v1.VirtualServerRoute, shortNames bool) *upstreamNamer {
+	namer := NewUpstreamNamerForVirtualServerRoute(virtualServer, virtualServerRoute)
+	if shortNames {
+		return namer.shortenPrefix()
+	}
+	return namer
+}
+
 // VariableNamer is a namer which generates unique variable names for a VirtualServer.
 type VariableNamer struct {
 	safeNsName string
@@ -217,9 +278,27 @@ func NewVSVariableNamer(virtualServer *conf_v1.VirtualServer) *VariableNamer {
 	}
 }
 
-// GetNameOfKeyvalZoneForSplitClientIndex returns a unique name for a keyval zone for split clients.
-func (namer *VariableNamer) GetNameOfKeyvalZoneForSplitClientIndex(index int) string {
-	return fmt.Sprintf("vs_%s_keyval_zone_split_clients_%d", namer.safeNsName, index)
+// GetSplitClientsID returns an identifier for the split clients of a two-way split. The identifier is derived from
+// the path, the match conditions and the actions of the splits, so that it does not change when the routes are
+// reordered or the weights of the splits change.
+func GetSplitClientsID(path string, conditions []conf_v1.Condition, splits []conf_v1.Split) string {
+	actions := make([]*conf_v1.Action, 0, len(splits))
+	for _, s := range splits {
+		actions = append(actions, s.Action)
+	}
+	// Safe to ignore the error since the definition contains no types that cannot be marshaled
+	data, _ := json.Marshal(struct {
+		Path       string
+		Conditions []conf_v1.Condition
+		Actions    []*conf_v1.Action
+	}{path, conditions, actions})
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])[:8]
+}
+
+// GetNameOfKeyvalZoneForSplitClients returns a unique name for a keyval zone for split clients with the given ID.
+func (namer *VariableNamer) GetNameOfKeyvalZoneForSplitClients(id string) string {
+	return fmt.Sprintf("vs_%s_keyval_zone_split_clients_%s", namer.safeNsName, id)
 }
 
 // GetNameOfKeyvalForSplitClientIndex returns a unique name for a keyval for split clients.
@@ -227,9 +306,14 @@ func (namer *VariableNamer) GetNameOfKeyvalForSplitClientIndex(index int) string
 	return fmt.Sprintf("$vs_%s_keyval_split_clients_%d", namer.safeNsName, index)
 }
 
-// GetNameOfKeyvalKeyForSplitClientIndex returns a unique name for a keyval key for split clients.
-func (namer *VariableNamer) GetNameOfKeyvalKeyForSplitClientIndex(index int) string {
-	return fmt.Sprintf("\"vs_%s_keyval_key_split_clients_%d\"", namer.safeNsName, index)
+// GetNameOfKeyvalKeyForSplitClients returns a unique name for a keyval key for split clients with the given ID.
+func (namer *VariableNamer) GetNameOfKeyvalKeyForSplitClients(id string) string {
+	return fmt.Sprintf("\"vs_%s_keyval_key_split_clients_%s\"", namer.safeNsName, id)
+}
+
+// GetNameOfKeyvalForSplitWeights returns a unique name for a keyval with the shared weights of split clients.
+func (namer *VariableNamer) GetNameOfKeyvalForSplitWeights(index int) string {
+	return fmt.Sprintf("$vs_%s_keyval_split_weights_%d", namer.safeNsName, index)
 }
 
 // GetNameOfMapForSplitClientIndex returns a unique name for a map for split clients.
@@ -237,9 +321,9 @@ func (namer *VariableNamer) GetNameOfMapForSplitClientIndex(index int) string {
 	return fmt.Sprintf("$vs_%s_map_split_clients_%d", namer.safeNsName, index)
 }
 
-// GetNameOfKeyOfMapForWeights returns a unique name for a key of a map for split clients.
-func (namer *VariableNamer) GetNameOfKeyOfMapForWeights(index int, i int, j int) string {
-	return fmt.Sprintf("\"vs_%s_split_clients_%d_%d_%d\"", namer.safeNsName, index, i, j)
+// GetNameOfKeyOfMapForWeights returns a unique name for a key of a map for split clients with the given ID.
+func (namer *VariableNamer) GetNameOfKeyOfMapForWeights(id string, i int, j int) string {
+	return fmt.Sprintf("\"vs_%s_split_clients_%s_%d_%d\"", namer.safeNsName, id, i, j)
 }
 
 // GetNameOfSplitClientsForWeights gets the name of the split clients for a particular combination of weights and scIndex.
@@ -252,6 +336,26 @@ func (namer *VariableNamer) GetNameForSplitClientVariable(index int) string {
 	return fmt.Sprintf("$vs_%s_splits_%d", namer.safeNsName, index)
 }
 
+// GetNameForSplitClientStickyKeyVariable gets the name of the variable that keys a split client on a sticky cookie for a particular scIndex.
+func (namer *VariableNamer) GetNameForSplitClientStickyKeyVariable(index int) string {
+	return fmt.Sprintf("$vs_%s_splits_%d_sticky_key", namer.safeNsName, index)
+}
+
+// GetNameForSplitClientSetCookieVariable gets the name of the variable with the Set-Cookie header value of a split client for a particular scIndex.
+func (namer *VariableNamer) GetNameForSplitClientSetCookieVariable(index int) string {
+	return fmt.Sprintf("$vs_%s_splits_%d_set_cookie", namer.safeNsName, index)
+}
+
+// GetNameForReturnLocationVariable gets the name of the variable with the return location selected by the Accept header for a particular return location index.
+func (namer *VariableNamer) GetNameForReturnLocationVariable(index int) string {
+	return fmt.Sprintf("$vs_%s_return_%d", namer.safeNsName, index)
+}
+
+// GetNameForNoEndpointsVariable gets the name of the variable with the error page location selected by $upstream_addr for the 502 errors.
+func (namer *VariableNamer) GetNameForNoEndpointsVariable() string {
+	return fmt.Sprintf("$vs_%s_no_endpoints", namer.safeNsName)
+}
+
 // GetNameForVariableForMatchesRouteMap gets the name of a matches route map
 func (namer *VariableNamer) GetNameForVariableForMatchesRouteMap(
 	matchesIndex int,
@@ -261,6 +365,41 @@ func (namer *VariableNamer) GetNameForVariableForMatchesRouteMap(
 	return fmt.Sprintf("$vs_%s_matches_%d_match_%d_cond_%d", namer.safeNsName, matchesIndex, matchIndex, conditionIndex)
 }
 
+// GetNameForVariableForTarpitMap gets the name of a tarpit condition map
+func (namer *VariableNamer) GetNameForVariableForTarpitMap(tarpitIndex int, conditionIndex int) string {
+	return fmt.Sprintf("$vs_%s_tarpit_%d_cond_%d", namer.safeNsName, tarpitIndex, conditionIndex)
+}
+
+// GetNameForRouteVariable gets the name of the variable with the index of the route of the request.
+func (namer *VariableNamer) GetNameForRouteVariable() string {
+	return fmt.Sprintf("$vs_%s_route", namer.safeNsName)
+}
+
+// GetNameForRouteRequestLengthVariable gets the name of the variable with the request length of a particular route index.
+func (namer *VariableNamer) GetNameForRouteRequestLengthVariable(index int) string {
+	return fmt.Sprintf("$vs_%s_route_%d_request_length", namer.safeNsName, index)
+}
+
+// GetNameForRouteBytesSentVariable gets the name of the variable with the bytes sent of a particular route index.
+func (namer *VariableNamer) GetNameForRouteBytesSentVariable(index int) string {
+	return fmt.Sprintf("$vs_%s_route_%d_bytes_sent", namer.safeNsName, index)
+}
+
+// GetNameForRequestIDVariable gets the name of the variable with the request ID passed to the upstream servers.
+func (namer *VariableNamer) GetNameForRequestIDVariable() string {
+	return fmt.Sprintf("$vs_%s_request_id", namer.safeNsName)
+}
+
+// GetNameForVariableForClientIPReturnGeo gets the name of a client IP return geo
+func (namer *VariableNamer) GetNameForVariableForClientIPReturnGeo(index int) string {
+	return fmt.Sprintf("$vs_%s_client_ip_return_%d", namer.safeNsName, index)
+}
+
+// GetNameForGeoVariable gets the name of the variable of a geo of the VirtualServer.
+func (namer *VariableNamer) GetNameForGeoVariable(name string) string {
+	return fmt.Sprintf("$vs_%s_geo_%s", namer.safeNsName, strings.ReplaceAll(name, "-", "_"))
+}
+
 // GetNameForVariableForMatchesRouteMainMap gets the name of a matches route main map
 func (namer *VariableNamer) GetNameForVariableForMatchesRouteMainMap(matchesIndex int) string {
 	return fmt.Sprintf("$vs_%s_matches_%d", namer.safeNsName, matchesIndex)
@@ -306,6 +445,11 @@ type virtualServerConfigurator struct {
 	DynamicWeightChangesReload bool
 	bundleValidator            bundleValidator
 	IngressControllerReplicas  int
+	isHTTP3Enabled             bool
+	isHTTP3Supported           bool
+	shortUpstreamNames         bool
+	upstreamServerPodComments  bool
+	nginxVersion               nginx.Version
 }
 
 func (vsc *virtualServerConfigurator) addWarningf(obj runtime.Object, msgFmt string, args ...interface{}) {
@@ -348,7 +492,26 @@ func newVirtualServerConfigurator(
 		CABundlePath:               staticParams.DefaultCABundle,
 		DynamicWeightChangesReload: staticParams.DynamicWeightChangesReload,
 		bundleValidator:            bundleValidator,
+		isHTTP3Enabled:             staticParams.EnableHTTP3,
+		isHTTP3Supported:           isHTTP3Supported(staticParams.NginxVersion),
+		shortUpstreamNames:         staticParams.ShortUpstreamNames,
+		upstreamServerPodComments:  staticParams.UpstreamServerPodComments,
+		nginxVersion:               staticParams.NginxVersion,
+	}
+}
+
+// checkHealthCheckKeepaliveTime removes the keepalive time from the health check of the upstream if the version of
+// NGINX doesn't support it, warning about it only if the keepalive time was set for the upstream.
+func (vsc *virtualServerConfigurator) checkHealthCheckKeepaliveTime(owner runtime.Object, upstream conf_v1.Upstream, hc *version2.HealthCheck) {
+	if hc.KeepaliveTime == "" || isHealthCheckKeepaliveTimeSupported(vsc.nginxVersion) {
+		return
+	}
+
+	nl.Debugf(nl.LoggerFromContext(vsc.cfgParams.Context), "Omitting keepalive_time of the health check of upstream %s, as it is not supported by NGINX %s", upstream.Name, vsc.nginxVersion.OSS)
+	if upstream.HealthCheck.KeepaliveTime != "" {
+		vsc.addWarningf(owner, "The keepalive-time of the health check of upstream %s is ignored: it requires NGINX %s or later", upstream.Name, minHealthCheckKeepaliveTimeNginxVersion)
 	}
+	hc.KeepaliveTime = ""
 }
 
 func (vsc *virtualServerConfigurator) generateEndpointsForUpstream(
@@ -364,6 +527,9 @@ func (vsc *virtualServerConfigurator) generateEndpointsForUpstream(
 	if endpoints != nil && len(endpoints) == 0 {
 		vsc.addWarningf(owner, "No endpoints found for service %v", upstream.Service)
 	}
+	if terminatingEndpoints := vsc.generateTerminatingEndpointsForUpstream(namespace, upstream, virtualServerEx); len(terminatingEndpoints) > 0 {
+		endpoints = slices.Concat(endpoints, terminatingEndpoints)
+	}
 	if !vsc.isPlus && len(endpoints) == 0 {
 		return []string{nginx502Server}
 	}
@@ -378,6 +544,20 @@ func (vsc *virtualServerConfigurator) generateEndpointsForUpstream(
 	return endpoints
 }
 
+// generateTerminatingEndpointsForUpstream returns the endpoints of the terminating pods of the upstream service
+// that are kept in the upstream marked down or draining, if the upstream enables it.
+func (vsc *virtualServerConfigurator) generateTerminatingEndpointsForUpstream(
+	namespace string,
+	upstream conf_v1.Upstream,
+	virtualServerEx *VirtualServerEx,
+) []string {
+	if !upstream.KeepTerminatingEndpoints && (!vsc.isPlus || !upstream.Drain) {
+		return nil
+	}
+	serviceNamespace, serviceName := ParseServiceReference(upstream.Service, namespace)
+	return virtualServerEx.TerminatingEndpoints[GenerateEndpointsKey(serviceNamespace, serviceName, upstream.Subselector, upstream.Port)]
+}
+
 func (vsc *virtualServerConfigurator) generateBackupEndpointsForUpstream(
 	owner runtime.Object,
 	namespace string,
@@ -419,7 +599,15 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 	}
 
 	sslConfig := vsc.generateSSLConfig(vsEx.VirtualServer, vsEx.VirtualServer.Spec.TLS, vsEx.VirtualServer.Namespace, vsEx.SecretRefs, vsc.cfgParams)
+	if sslConfig != nil {
+		// the http2 field of the VirtualServer overrides the http2 ConfigMap key
+		sslConfig.HTTP2 = generateBool(vsEx.VirtualServer.Spec.HTTP2, vsc.cfgParams.HTTP2)
+	}
+	if vsEx.VirtualServer.Spec.HTTP3 != nil && *vsEx.VirtualServer.Spec.HTTP3 {
+		vsc.generateHTTP3(vsEx.VirtualServer, sslConfig)
+	}
 	tlsRedirectConfig := generateTLSRedirectConfig(vsEx.VirtualServer.Spec.TLS)
+	serverTracing := vsc.generateTracing(vsEx.VirtualServer, vsEx.VirtualServer.Spec.Tracing)
 
 	policyOpts := policyOptions{
 		tls:             sslConfig != nil,
@@ -428,6 +616,7 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 		apResources:     apResources,
 		defaultCABundle: vsc.CABundlePath,
 		replicas:        vsc.IngressControllerReplicas,
+		maxVerifyDepth:  vsc.cfgParams.UpstreamSSLMaxVerifyDepth,
 	}
 
 	ownerDetails := policyOwnerDetails{
@@ -453,6 +642,10 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 		policiesCfg.JWTAuth.List = make(map[string]*version2.JWTAuth)
 		policiesCfg.JWTAuth.List[jwtAuthKey] = policiesCfg.JWTAuth.Auth
 	}
+	// the Configurator combines the CRL files into this file when the VirtualServer config is written
+	if mtls := policiesCfg.IngressMTLS; mtls != nil && len(mtls.ClientCrlFiles) > 0 {
+		mtls.ClientCrl = fmt.Sprintf("%s/%s", DefaultSecretPath, getFileNameForIngressMTLSCrlVirtualServer(vsEx.VirtualServer))
+	}
 
 	if policiesCfg.APIKey.Enabled {
 		apiMapName := policiesCfg.APIKey.Key.MapName
@@ -472,21 +665,25 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 		maps = append(maps, *policiesCfg.CORSMap)
 	}
 
+	maps = append(maps, policiesCfg.JWTAuth.ClaimMaps...)
+
 	dosCfg := generateDosCfg(dosResources[""])
 
 	// crUpstreams maps an UpstreamName to its conf_v1.Upstream as they are generated
 	// necessary for generateLocation to know what Upstream each Location references
 	crUpstreams := make(map[string]conf_v1.Upstream)
 
-	virtualServerUpstreamNamer := NewUpstreamNamerForVirtualServer(vsEx.VirtualServer)
+	virtualServerUpstreamNamer := newUpstreamNamerForVirtualServer(vsEx.VirtualServer, vsc.shortUpstreamNames)
 	var upstreams []version2.Upstream
 	var statusMatches []version2.StatusMatch
 	var healthChecks []version2.HealthCheck
 	var limitReqZones []version2.LimitReqZone
+	var sharedLimitReqZones []version2.LimitReqZone
 	var authJWTClaimSets []version2.AuthJWTClaimSet
 	var cacheZones []version2.CacheZone
 
 	limitReqZones = append(limitReqZones, policiesCfg.RateLimit.Zones...)
+	sharedLimitReqZones = append(sharedLimitReqZones, policiesCfg.RateLimit.SharedZones...)
 	authJWTClaimSets = append(authJWTClaimSets, policiesCfg.RateLimit.AuthJWTClaimSets...)
 
 	// Add cache zone from global policy if present
@@ -508,9 +705,10 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 			statusMatches,
 		)
 	}
+	upstreams = vsc.generateProxyPassURLUpstreams(vsEx.VirtualServer, vsEx.VirtualServer.Spec.Routes, virtualServerUpstreamNamer, upstreams, crUpstreams)
 	// generate upstreams for each VirtualServerRoute
 	for _, vsr := range vsEx.VirtualServerRoutes {
-		upstreamNamer := NewUpstreamNamerForVirtualServerRoute(vsEx.VirtualServer, vsr)
+		upstreamNamer := newUpstreamNamerForVirtualServerRoute(vsEx.VirtualServer, vsr, vsc.shortUpstreamNames)
 		for _, u := range vsr.Spec.Upstreams {
 			upstreams, healthChecks, statusMatches = generateUpstreams(
 				sslConfig,
@@ -526,11 +724,15 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 				statusMatches,
 			)
 		}
+		upstreams = vsc.generateProxyPassURLUpstreams(vsr, vsr.Spec.Subroutes, upstreamNamer, upstreams, crUpstreams)
 	}
 
 	var locations []version2.Location
 	var internalRedirectLocations []version2.InternalRedirectLocation
 	var returnLocations []version2.ReturnLocation
+	var tarpitLocations []version2.TarpitLocation
+	var grpcErrorLocations []version2.GRPCErrorLocation
+	var geos []version2.Geo
 	var splitClients []version2.SplitClient
 	var errorPageLocations []version2.ErrorPageLocation
 	var keyValZones []version2.KeyValZone
@@ -548,6 +750,15 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 
 	VariableNamer := NewVSVariableNamer(vsEx.VirtualServer)
 
+	serverErrorPages := getServerErrorPages(policiesCfg)
+	var noEndpointsErrorPage *version2.ErrorPage
+	if noEndpoints := vsEx.VirtualServer.Spec.NoEndpoints; noEndpoints != nil {
+		errorPage, returnLocs, upstreamMap := generateNoEndpointsErrorPage(noEndpoints, upstreams, len(returnLocations), VariableNamer, vsEx.VirtualServer, vsc.warnings)
+		noEndpointsErrorPage = &errorPage
+		returnLocations = append(returnLocations, returnLocs...)
+		maps = append(maps, upstreamMap)
+	}
+
 	// Track generated ExternalAuth proxy URLs to avoid duplicate upstream/location generation
 	generatedExternalAuthURLs := make(map[string]bool)
 	generatedOAuth2Location := false
@@ -597,10 +808,14 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 	// without allowing a route-level OIDC assignment to bleed into subsequent routes.
 	specHasOIDC := policiesCfg.OIDC != nil
 
+	routeSizeVariables := vsEx.VirtualServer.Spec.RouteSizeVariables
+	routeSizeIndex := 0
+
 	// generates config for VirtualServer routes
 	for _, r := range vsEx.VirtualServer.Spec.Routes {
 		errorPages := generateErrorPageDetails(r.ErrorPages, errorPageLocations, vsEx.VirtualServer)
 		errorPageLocations = append(errorPageLocations, generateErrorPageLocations(errorPages.index, errorPages.pages)...)
+		errorPages.pages = excludeErrorPageCodes(errorPages.pages, r.PassthroughErrorCodes)
 
 		// ignore routes that reference VirtualServerRoute
 		if r.Route != "" {
@@ -723,6 +938,7 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 		}
 		if routePoliciesCfg.APIKey.Enabled {
 			policiesCfg.APIKey.Enabled = routePoliciesCfg.APIKey.Enabled
+			policiesCfg.APIKey.RejectCodeEnabled = policiesCfg.APIKey.RejectCodeEnabled || routePoliciesCfg.APIKey.RejectCodeEnabled
 			apiMapName := routePoliciesCfg.APIKey.Key.MapName
 			if policiesCfg.APIKey.ClientMap == nil {
 				policiesCfg.APIKey.ClientMap = make(map[string][]apiKeyClient)
@@ -787,8 +1003,10 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 		if routePoliciesCfg.CORSMap != nil {
 			maps = append(maps, *routePoliciesCfg.CORSMap)
 		}
+		maps = append(maps, routePoliciesCfg.JWTAuth.ClaimMaps...)
 
 		limitReqZones = append(limitReqZones, routePoliciesCfg.RateLimit.Zones...)
+		sharedLimitReqZones = append(sharedLimitReqZones, routePoliciesCfg.RateLimit.SharedZones...)
 
 		authJWTClaimSets = append(authJWTClaimSets, routePoliciesCfg.RateLimit.AuthJWTClaimSets...)
 
@@ -797,7 +1015,33 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 
 		dosRouteCfg := generateDosCfg(dosResources[r.Path])
 
+		tarpitMaps, tarpit, tarpitLoc := generateTarpit(r.Tarpit, len(tarpitLocations), VariableNamer)
+		maps = append(maps, tarpitMaps...)
+		if tarpitLoc != nil {
+			tarpitLocations = append(tarpitLocations, *tarpitLoc)
+		}
+
+		clientIPReturnGeo, clientIPReturn := generateClientIPReturn(r.ClientIPReturn, len(geos), VariableNamer)
+		if clientIPReturnGeo != nil {
+			geos = append(geos, *clientIPReturnGeo)
+		}
+
+		grpcErrorLocs, grpcErrorPages := generateGRPCErrorLocations(r.GRPCErrorResponses, len(grpcErrorLocations))
+		grpcErrorLocations = append(grpcErrorLocations, grpcErrorLocs...)
+
+		statusZone := vsc.generateRouteStatusZone(r.StatusZone, vsEx.VirtualServer.Spec.Host, r.Path)
+		tracing := vsc.generateRouteTracing(vsEx.VirtualServer, r.Tracing, serverTracing, r.Path)
+
+		var routeVariable *version2.Variable
+		if routeSizeVariables {
+			var routeSizeMaps []version2.Map
+			routeVariable, routeSizeMaps = generateRouteSizeVariables(routeSizeIndex, VariableNamer)
+			maps = append(maps, routeSizeMaps...)
+			routeSizeIndex++
+		}
+
 		if len(r.Matches) > 0 {
+			vsc.checkSSLClientVerifyConditions(vsEx.VirtualServer, r.Path, r.Matches, policiesCfg.IngressMTLS)
 			cfg := generateMatchesConfig(
 				r,
 				virtualServerUpstreamNamer,
@@ -818,6 +1062,14 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 			addPoliciesCfgToLocations(routePoliciesCfg, cfg.Locations)
 			addDosConfigToLocations(dosRouteCfg, cfg.Locations)
 			addAddHeaderInheritToLocations(r.AddHeaderInherit, cfg.Locations)
+			addTarpitToLocations(tarpit, cfg.Locations)
+			addClientIPReturnToLocations(clientIPReturn, cfg.Locations)
+			vsc.addGRPCErrorPagesToLocations(vsEx.VirtualServer, r.Path, grpcErrorPages, cfg.Locations)
+			addPreserveHostHeaderToLocations(r.PreserveHostHeader, cfg.Locations)
+			addStatusZoneToLocations(statusZone, cfg.Locations)
+			addTracingToLocations(tracing, cfg.Locations)
+			addRouteVariableToLocations(routeVariable, cfg.Locations)
+			addNoEndpointsErrorPageToLocations(noEndpointsErrorPage, serverErrorPages, r.PassthroughErrorCodes, cfg.Locations)
 
 			maps = append(maps, cfg.Maps...)
 			locations = append(locations, cfg.Locations...)
@@ -834,6 +1086,14 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 			addPoliciesCfgToLocations(routePoliciesCfg, cfg.Locations)
 			addDosConfigToLocations(dosRouteCfg, cfg.Locations)
 			addAddHeaderInheritToLocations(r.AddHeaderInherit, cfg.Locations)
+			addTarpitToLocations(tarpit, cfg.Locations)
+			addClientIPReturnToLocations(clientIPReturn, cfg.Locations)
+			vsc.addGRPCErrorPagesToLocations(vsEx.VirtualServer, r.Path, grpcErrorPages, cfg.Locations)
+			addPreserveHostHeaderToLocations(r.PreserveHostHeader, cfg.Locations)
+			addStatusZoneToLocations(statusZone, cfg.Locations)
+			addTracingToLocations(tracing, cfg.Locations)
+			addRouteVariableToLocations(routeVariable, cfg.Locations)
+			addNoEndpointsErrorPageToLocations(noEndpointsErrorPage, serverErrorPages, r.PassthroughErrorCodes, cfg.Locations)
 			splitClients = append(splitClients, cfg.SplitClients...)
 			locations = append(locations, cfg.Locations...)
 			internalRedirectLocations = append(internalRedirectLocations, cfg.InternalRedirectLocation)
@@ -850,12 +1110,23 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 			proxySSLName := generateProxySSLName(serviceName, serviceNamespace)
 
 			loc, returnLoc := generateLocation(r.Path, upstreamName, upstream, r.Action, vsc.cfgParams, errorPages, false,
-				proxySSLName, r.Path, vsLocSnippets, vsc.enableSnippets, len(returnLocations), isVSR, "", "", vsc.warnings)
+				proxySSLName, r.Path, vsLocSnippets, vsc.enableSnippets, len(returnLocations), VariableNamer, isVSR, "", "", vsc.warnings)
 			addPoliciesCfgToLocation(routePoliciesCfg, &loc)
 			loc.Dos = dosRouteCfg
 			loc.AddHeaderInherit = r.AddHeaderInherit
+			loc.Tarpit = tarpit
+			loc.ClientIPReturn = clientIPReturn
+			loc.StatusZone = statusZone
+			loc.Tracing = tracing
+			loc.RouteRewrites = generateRouteRewrites(r.Rewrites)
+			if routeVariable != nil {
+				loc.Variables = append(loc.Variables, *routeVariable)
+			}
 
 			locations = append(locations, loc)
+			vsc.addGRPCErrorPagesToLocations(vsEx.VirtualServer, r.Path, grpcErrorPages, locations[len(locations)-1:])
+			addPreserveHostHeaderToLocations(r.PreserveHostHeader, locations[len(locations)-1:])
+			addNoEndpointsErrorPageToLocations(noEndpointsErrorPage, serverErrorPages, r.PassthroughErrorCodes, locations[len(locations)-1:])
 			if returnLoc != nil {
 				returnLocations = append(returnLocations, *returnLoc)
 			}
@@ -865,18 +1136,20 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 	// generate config for subroutes of each VirtualServerRoute
 	for _, vsr := range vsEx.VirtualServerRoutes {
 		isVSR := true
-		upstreamNamer := NewUpstreamNamerForVirtualServerRoute(vsEx.VirtualServer, vsr)
+		upstreamNamer := newUpstreamNamerForVirtualServerRoute(vsEx.VirtualServer, vsr, vsc.shortUpstreamNames)
+		vsrStatusZone := vsc.generateVirtualServerRouteStatusZone(vsr)
 		for _, r := range vsr.Spec.Subroutes {
 			errorPages := generateErrorPageDetails(r.ErrorPages, errorPageLocations, vsr)
 			errorPageLocations = append(errorPageLocations, generateErrorPageLocations(errorPages.index, errorPages.pages)...)
 			vsrNamespaceName := fmt.Sprintf("%v/%v", vsr.Namespace, vsr.Name)
-			// use the VirtualServer error pages if the route does not define any
-			if r.ErrorPages == nil {
+			// use the VirtualServer error pages if the route does not define any, unless the route opts out
+			if r.ErrorPages == nil && generateBool(r.InheritErrorPages, true) {
 				if vsErrorPages, ok := vsrErrorPagesFromVs[vsrNamespaceName]; ok {
 					errorPages.pages = vsErrorPages
 					errorPages.index = vsrErrorPagesRouteIndex[vsrNamespaceName]
 				}
 			}
+			errorPages.pages = excludeErrorPageCodes(errorPages.pages, r.PassthroughErrorCodes)
 
 			locSnippets := r.LocationSnippets
 			// use the VirtualServer location snippet if the route does not define any
@@ -956,6 +1229,7 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 			}
 			if routePoliciesCfg.APIKey.Enabled {
 				policiesCfg.APIKey.Enabled = routePoliciesCfg.APIKey.Enabled
+				policiesCfg.APIKey.RejectCodeEnabled = policiesCfg.APIKey.RejectCodeEnabled || routePoliciesCfg.APIKey.RejectCodeEnabled
 				apiMapName := routePoliciesCfg.APIKey.Key.MapName
 				if policiesCfg.APIKey.ClientMap == nil {
 					policiesCfg.APIKey.ClientMap = make(map[string][]apiKeyClient)
@@ -1019,8 +1293,10 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 			if routePoliciesCfg.CORSMap != nil {
 				maps = append(maps, *routePoliciesCfg.CORSMap)
 			}
+			maps = append(maps, routePoliciesCfg.JWTAuth.ClaimMaps...)
 
 			limitReqZones = append(limitReqZones, routePoliciesCfg.RateLimit.Zones...)
+			sharedLimitReqZones = append(sharedLimitReqZones, routePoliciesCfg.RateLimit.SharedZones...)
 
 			authJWTClaimSets = append(authJWTClaimSets, routePoliciesCfg.RateLimit.AuthJWTClaimSets...)
 
@@ -1029,7 +1305,37 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 
 			dosRouteCfg := generateDosCfg(dosResources[r.Path])
 
+			tarpitMaps, tarpit, tarpitLoc := generateTarpit(r.Tarpit, len(tarpitLocations), VariableNamer)
+			maps = append(maps, tarpitMaps...)
+			if tarpitLoc != nil {
+				tarpitLocations = append(tarpitLocations, *tarpitLoc)
+			}
+
+			clientIPReturnGeo, clientIPReturn := generateClientIPReturn(r.ClientIPReturn, len(geos), VariableNamer)
+			if clientIPReturnGeo != nil {
+				geos = append(geos, *clientIPReturnGeo)
+			}
+
+			grpcErrorLocs, grpcErrorPages := generateGRPCErrorLocations(r.GRPCErrorResponses, len(grpcErrorLocations))
+			grpcErrorLocations = append(grpcErrorLocations, grpcErrorLocs...)
+
+			statusZone := vsc.generateRouteStatusZone(r.StatusZone, vsEx.VirtualServer.Spec.Host, r.Path)
+			// use the status zone of the VirtualServerRoute if the subroute does not define any
+			if r.StatusZone == "" {
+				statusZone = vsrStatusZone
+			}
+			tracing := vsc.generateRouteTracing(vsr, r.Tracing, serverTracing, r.Path)
+
+			var routeVariable *version2.Variable
+			if routeSizeVariables {
+				var routeSizeMaps []version2.Map
+				routeVariable, routeSizeMaps = generateRouteSizeVariables(routeSizeIndex, VariableNamer)
+				maps = append(maps, routeSizeMaps...)
+				routeSizeIndex++
+			}
+
 			if len(r.Matches) > 0 {
+				vsc.checkSSLClientVerifyConditions(vsr, r.Path, r.Matches, policiesCfg.IngressMTLS)
 				cfg := generateMatchesConfig(
 					r,
 					upstreamNamer,
@@ -1051,6 +1357,14 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 				addPoliciesCfgToLocations(routePoliciesCfg, cfg.Locations)
 				addDosConfigToLocations(dosRouteCfg, cfg.Locations)
 				addAddHeaderInheritToLocations(addHeaderInherit, cfg.Locations)
+				addTarpitToLocations(tarpit, cfg.Locations)
+				addClientIPReturnToLocations(clientIPReturn, cfg.Locations)
+				vsc.addGRPCErrorPagesToLocations(vsr, r.Path, grpcErrorPages, cfg.Locations)
+				addPreserveHostHeaderToLocations(r.PreserveHostHeader, cfg.Locations)
+				addStatusZoneToLocations(statusZone, cfg.Locations)
+				addTracingToLocations(tracing, cfg.Locations)
+				addRouteVariableToLocations(routeVariable, cfg.Locations)
+				addNoEndpointsErrorPageToLocations(noEndpointsErrorPage, serverErrorPages, r.PassthroughErrorCodes, cfg.Locations)
 
 				maps = append(maps, cfg.Maps...)
 				locations = append(locations, cfg.Locations...)
@@ -1067,6 +1381,14 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 				addPoliciesCfgToLocations(routePoliciesCfg, cfg.Locations)
 				addDosConfigToLocations(dosRouteCfg, cfg.Locations)
 				addAddHeaderInheritToLocations(addHeaderInherit, cfg.Locations)
+				addTarpitToLocations(tarpit, cfg.Locations)
+				addClientIPReturnToLocations(clientIPReturn, cfg.Locations)
+				vsc.addGRPCErrorPagesToLocations(vsr, r.Path, grpcErrorPages, cfg.Locations)
+				addPreserveHostHeaderToLocations(r.PreserveHostHeader, cfg.Locations)
+				addStatusZoneToLocations(statusZone, cfg.Locations)
+				addTracingToLocations(tracing, cfg.Locations)
+				addRouteVariableToLocations(routeVariable, cfg.Locations)
+				addNoEndpointsErrorPageToLocations(noEndpointsErrorPage, serverErrorPages, r.PassthroughErrorCodes, cfg.Locations)
 
 				splitClients = append(splitClients, cfg.SplitClients...)
 				locations = append(locations, cfg.Locations...)
@@ -1083,12 +1405,24 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 				proxySSLName := generateProxySSLName(serviceName, serviceNamespace)
 
 				loc, returnLoc := generateLocation(r.Path, upstreamName, upstream, r.Action, vsc.cfgParams, errorPages, false,
-					proxySSLName, r.Path, locSnippets, vsc.enableSnippets, len(returnLocations), isVSR, vsr.Name, vsr.Namespace, vsc.warnings)
+					proxySSLName, r.Path, locSnippets, vsc.enableSnippets, len(returnLocations), VariableNamer, isVSR, vsr.Name, vsr.Namespace,
+					vsc.warnings)
 				addPoliciesCfgToLocation(routePoliciesCfg, &loc)
 				loc.Dos = dosRouteCfg
 				loc.AddHeaderInherit = addHeaderInherit
+				loc.Tarpit = tarpit
+				loc.ClientIPReturn = clientIPReturn
+				loc.StatusZone = statusZone
+				loc.Tracing = tracing
+				loc.RouteRewrites = generateRouteRewrites(r.Rewrites)
+				if routeVariable != nil {
+					loc.Variables = append(loc.Variables, *routeVariable)
+				}
 
 				locations = append(locations, loc)
+				vsc.addGRPCErrorPagesToLocations(vsr, r.Path, grpcErrorPages, locations[len(locations)-1:])
+				addPreserveHostHeaderToLocations(r.PreserveHostHeader, locations[len(locations)-1:])
+				addNoEndpointsErrorPageToLocations(noEndpointsErrorPage, serverErrorPages, r.PassthroughErrorCodes, locations[len(locations)-1:])
 				if returnLoc != nil {
 					returnLocations = append(returnLocations, *returnLoc)
 				}
@@ -1100,6 +1434,11 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 		maps = append(maps, *generateAPIKeyClientMap(mapName, apiKeyClients))
 	}
 
+	geos = append(geos, generateGeos(vsEx.VirtualServer.Spec.Geos, VariableNamer)...)
+
+	returnLocations, returnMaps := flattenReturnLocations(returnLocations)
+	maps = append(maps, returnMaps...)
+
 	httpSnippets := generateSnippets(vsc.enableSnippets, vsEx.VirtualServer.Spec.HTTPSnippets, []string{})
 	serverSnippets := generateSnippets(
 		vsc.enableSnippets,
@@ -1111,20 +1450,41 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 		return upstreams[i].Name < upstreams[j].Name
 	})
 
+	if requestID := vsEx.VirtualServer.Spec.RequestID; requestID != nil {
+		requestIDMap := generateRequestIDMap(requestID, VariableNamer)
+		maps = append(maps, requestIDMap)
+		addRequestIDToLocations(getRequestIDHeader(requestID), requestIDMap.Variable, locations)
+	}
+
 	addHSTSToLocationsWithAddHeaders(policiesCfg.HSTS, locations)
+	addHSTSHideHeaderToLocationsWithHideHeaders(policiesCfg.HSTS, locations)
+
+	errorLog := vsc.generateErrorLog(vsEx.VirtualServer)
+	nginxDebugLevel := vsc.cfgParams.MainErrorLogLevel
+	if errorLog != nil {
+		nginxDebugLevel = errorLog.Level
+	}
 
 	vsCfg := version2.VirtualServerConfig{
-		Upstreams:        upstreams,
-		Maps:             removeDuplicateMaps(maps),
-		StatusMatches:    statusMatches,
-		LimitReqZones:    removeDuplicateLimitReqZones(limitReqZones),
-		AuthJWTClaimSets: removeDuplicateAuthJWTClaimSets(authJWTClaimSets),
-		CacheZones:       cacheZones,
-		HTTPSnippets:     httpSnippets,
+		Upstreams:           upstreams,
+		Maps:                removeDuplicateMaps(maps),
+		Geos:                geos,
+		StatusMatches:       statusMatches,
+		LimitReqZones:       removeDuplicateLimitReqZones(limitReqZones),
+		SharedLimitReqZones: sharedLimitReqZones,
+		AuthJWTClaimSets:    removeDuplicateAuthJWTClaimSets(authJWTClaimSets),
+		CacheZones:          cacheZones,
+		HTTPSnippets:        httpSnippets,
 		Server: version2.Server{
 			ServerName:                vsEx.VirtualServer.Spec.Host,
 			Gunzip:                    vsEx.VirtualServer.Spec.Gunzip,
+			UnderscoresInHeaders:      vsc.generateUnderscoresInHeaders(vsEx.VirtualServer),
+			MergeSlashes:              vsc.generateMergeSlashes(vsEx.VirtualServer),
+			DefaultType:               vsEx.VirtualServer.Spec.DefaultType,
+			ErrorLog:                  errorLog,
+			Tracing:                   serverTracing,
 			AddHeaderInherit:          vsEx.VirtualServer.Spec.AddHeaderInherit,
+			AddHeaders:                generateServerAddHeaders(vsEx.VirtualServer.Spec.ResponseHeaders),
 			StatusZone:                vsEx.VirtualServer.Spec.Host,
 			HTTPPort:                  vsEx.HTTPPort,
 			HTTPSPort:                 vsEx.HTTPSPort,
@@ -1132,6 +1492,10 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 			HTTPIPv6:                  vsEx.HTTPIPv6,
 			HTTPSIPv4:                 vsEx.HTTPSIPv4,
 			HTTPSIPv6:                 vsEx.HTTPSIPv6,
+			HTTPListenerOptions:       generateListenerOptions(vsEx.HTTPListenerOptions),
+			HTTPSListenerOptions:      generateListenerOptions(vsEx.HTTPSListenerOptions),
+			HTTP3ReusePort:            vsEx.HTTP3ReusePort,
+			AdditionalListeners:       vsc.generateAdditionalListeners(vsEx.VirtualServer, vsEx.AdditionalListeners),
 			CustomListeners:           useCustomListeners,
 			ProxyProtocol:             vsc.cfgParams.ProxyProtocol,
 			SSL:                       sslConfig,
@@ -1143,17 +1507,22 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 			InternalRedirectLocations: internalRedirectLocations,
 			Locations:                 locations,
 			ReturnLocations:           returnLocations,
+			TarpitLocations:           tarpitLocations,
+			GRPCErrorLocations:        grpcErrorLocations,
 			HealthChecks:              healthChecks,
 			TLSRedirect:               tlsRedirectConfig,
+			StrictHost:                generateStrictHostConfig(vsEx.VirtualServer.Spec.Host, vsEx.VirtualServer.Spec.StrictHost),
 			ErrorPageLocations:        errorPageLocations,
 			TLSPassthrough:            vsc.isTLSPassthrough,
 			Allow:                     policiesCfg.Allow,
 			Deny:                      policiesCfg.Deny,
+			Satisfy:                   policiesCfg.Satisfy,
 			LimitReqOptions:           policiesCfg.RateLimit.Options,
 			LimitReqs:                 policiesCfg.RateLimit.Reqs,
+			Variables:                 generateServerVariables(policiesCfg.Variables, routeSizeVariables, VariableNamer),
 			JWTAuth:                   policiesCfg.JWTAuth.Auth,
 			ExternalAuth:              policiesCfg.ExternalAuth,
-			ErrorPages:                getServerErrorPages(policiesCfg),
+			ErrorPages:                serverErrorPages,
 			BasicAuth:                 policiesCfg.BasicAuth,
 			JWTAuthList:               policiesCfg.JWTAuth.List,
 			JWKSAuthEnabled:           policiesCfg.JWTAuth.JWKSEnabled,
@@ -1161,6 +1530,7 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 			EgressMTLS:                policiesCfg.EgressMTLS,
 			APIKey:                    policiesCfg.APIKey.Key,
 			APIKeyEnabled:             policiesCfg.APIKey.Enabled,
+			APIKeyRejectEnabled:       policiesCfg.APIKey.RejectCodeEnabled,
 			OIDC:                      policiesCfg.OIDC,
 			WAF:                       policiesCfg.WAF,
 			Dos:                       dosCfg,
@@ -1170,7 +1540,7 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 			VSNamespace:               vsEx.VirtualServer.Namespace,
 			VSName:                    vsEx.VirtualServer.Name,
 			DisableIPV6:               vsc.isIPV6Disabled,
-			NGINXDebugLevel:           vsc.cfgParams.MainErrorLogLevel,
+			NGINXDebugLevel:           nginxDebugLevel,
 		},
 		DynamicSSLReloadEnabled: vsc.DynamicSSLReloadEnabled,
 		StaticSSLPath:           vsc.StaticSSLPath,
@@ -1180,9 +1550,301 @@ func (vsc *virtualServerConfigurator) GenerateVirtualServerConfig(
 		TwoWaySplitClients:      twoWaySplitClients,
 	}
 
+	vsc.removeProxySetHeadersWithUndefinedVariables(vsEx.VirtualServer, &vsCfg)
+	vsc.removeProxySSLSessionReuseWithEgressMTLS(vsEx.VirtualServer, vsCfg.Server.Locations)
+	if !vsEx.VirtualServer.Spec.DebugHeaders {
+		vsc.removeDebugAddHeaders(vsEx.VirtualServer, &vsCfg)
+	}
+	addServerAddHeadersToLocations(vsCfg.Server.AddHeaders, vsCfg.Server.AddHeaderInherit, vsCfg.Server.Locations)
+	if vsCfg.Server.SSL != nil && vsCfg.Server.SSL.HTTP3 {
+		addServerAddHeadersToLocations([]version2.AddHeader{altSvcAddHeader}, vsCfg.Server.AddHeaderInherit, vsCfg.Server.Locations)
+	}
+	vsc.addUndefinedGeos(vsEx, &vsCfg)
+	vsc.checkVariablesHashSize(vsEx.VirtualServer, &vsCfg)
+	vsc.checkMapHashSize(vsEx.VirtualServer, &vsCfg)
+
 	return vsCfg, vsc.warnings
 }
 
+var generatedVariableRegexp = regexp.MustCompile(`\$\{?(vs_\w+)`)
+
+// removeProxySetHeadersWithUndefinedVariables removes the headers passed to the upstream servers that reference
+// generated variables, like the variables of the matches route maps or the route variable, that are not defined in the config,
+// as NGINX fails to reload with an unknown variable.
+func (vsc *virtualServerConfigurator) removeProxySetHeadersWithUndefinedVariables(owner runtime.Object, vsCfg *version2.VirtualServerConfig) {
+	defined := make(map[string]bool)
+	for _, m := range vsCfg.Maps {
+		defined[m.Variable] = true
+	}
+	for _, g := range vsCfg.Geos {
+		defined[g.Variable] = true
+	}
+	for _, sc := range vsCfg.SplitClients {
+		defined[sc.Variable] = true
+	}
+	for _, kv := range vsCfg.KeyVals {
+		defined[kv.Variable] = true
+	}
+	for _, v := range vsCfg.Server.Variables {
+		defined[v.Name] = true
+	}
+
+	for i := range vsCfg.Server.Locations {
+		loc := &vsCfg.Server.Locations[i]
+
+		loc.ProxySetHeaders = slices.DeleteFunc(loc.ProxySetHeaders, func(h version2.Header) bool {
+			for _, match := range generatedVariableRegexp.FindAllStringSubmatch(h.Value, -1) {
+				if !defined["$"+match[1]] {
+					vsc.addWarningf(owner, "header %s of location %s references the undefined variable $%s and is ignored", h.Name, loc.Path, match[1])
+					return true
+				}
+			}
+			return false
+		})
+	}
+}
+
+// removeProxySSLSessionReuseWithEgressMTLS removes the TLS session reuse of the upstreams from the locations with
+// an EgressMTLS policy, as the policy configures the session reuse of the location.
+func (vsc *virtualServerConfigurator) removeProxySSLSessionReuseWithEgressMTLS(owner runtime.Object, locations []version2.Location) {
+	for i := range locations {
+		loc := &locations[i]
+		if loc.EgressMTLS == nil || loc.ProxySSLSessionReuse == "" {
+			continue
+		}
+		vsc.addWarningf(owner, "TLS session reuse of the upstream of location %v is ignored because the EgressMTLS policy of the location configures the session reuse", loc.Path)
+		loc.ProxySSLSessionReuse = ""
+	}
+}
+
+// hashElementSize returns the size that the name takes in a bucket of a hash of NGINX, like the variables hash
+// or the hash of a map: the pointer to the value, the length of the name and the name, aligned to the size of a pointer.
+func hashElementSize(name string) uint64 {
+	const pointerSize = 8
+	return (pointerSize + 2 + uint64(len(name)) + pointerSize - 1) / pointerSize * pointerSize
+}
+
+// nextPowerOfTwo returns the smallest power of two that is greater than or equal to n.
+func nextPowerOfTwo(n uint64) uint64 {
+	p := uint64(1)
+	for p < n {
+		p <<= 1
+	}
+	return p
+}
+
+// checkVariablesHashSize warns when the variables generated for the VirtualServer, like the variables of the maps
+// of the matches of routes and of the split clients, don't fit in the variables hash configured by the
+// variables-hash-bucket-size and variables-hash-max-size ConfigMap keys, as NGINX fails to reload then.
+// The directives are only allowed in the http context, so the warnings recommend the values of the ConfigMap keys.
+func (vsc *virtualServerConfigurator) checkVariablesHashSize(owner runtime.Object, vsCfg *version2.VirtualServerConfig) {
+	variables := make(map[string]bool)
+	for _, m := range vsCfg.Maps {
+		variables[m.Variable] = true
+	}
+	for _, g := range vsCfg.Geos {
+		variables[g.Variable] = true
+	}
+	for _, sc := range vsCfg.SplitClients {
+		variables[sc.Variable] = true
+	}
+	for _, sc := range vsCfg.TwoWaySplitClients {
+		variables[sc.Variable] = true
+	}
+	for _, kv := range vsCfg.KeyVals {
+		variables[kv.Variable] = true
+	}
+	for _, cs := range vsCfg.AuthJWTClaimSets {
+		variables[cs.Variable] = true
+	}
+
+	var longest string
+	for v := range variables {
+		if len(v) > len(longest) || (len(v) == len(longest) && v < longest) {
+			longest = v
+		}
+	}
+
+	bucketSize := vsc.cfgParams.VariablesHashBucketSize
+	if bucketSize > 0 && longest != "" {
+		required := hashElementSize(strings.TrimPrefix(longest, "$")) + 8
+		if required > bucketSize {
+			vsc.addWarningf(owner, "The variable %s doesn't fit in the variables hash: set the variables-hash-bucket-size ConfigMap key to %d or more", longest, nextPowerOfTwo(required))
+		}
+	}
+
+	// the variables hash also holds the variables of NGINX and of the other resources, so the warning is
+	// reported when the variables of the VirtualServer alone take more than half of the hash
+	maxSize := vsc.cfgParams.VariablesHashMaxSize
+	if maxSize > 0 && uint64(len(variables))*2 > maxSize {
+		vsc.addWarningf(owner, "The VirtualServer generates %d variables, which can exceed the variables hash: set the variables-hash-max-size ConfigMap key to %d or more", len(variables), nextPowerOfTwo(uint64(len(variables))*4))
+	}
+}
+
+// checkMapHashSize warns when the keys of a map generated for the VirtualServer, like the map of the clients of
+// an API key policy, don't fit in the hash of the map configured by the map-hash-bucket-size and map-hash-max-size
+// ConfigMap keys, as NGINX fails to reload then. Like the variables hash, the hash of maps can only be configured
+// in the http context, so the warnings recommend the value of the map-hash-bucket-size ConfigMap key.
+func (vsc *virtualServerConfigurator) checkMapHashSize(owner runtime.Object, vsCfg *version2.VirtualServerConfig) {
+	bucketSize, err := strconv.ParseUint(vsc.cfgParams.MainMapHashBucketSize, 10, 64)
+	if err != nil || bucketSize == 0 {
+		return
+	}
+	maxSize, err := strconv.ParseUint(vsc.cfgParams.MainMapHashMaxSize, 10, 64)
+	if err != nil || maxSize == 0 {
+		return
+	}
+
+	for _, m := range vsCfg.Maps {
+		var keys, elementSize uint64
+		for _, p := range m.Parameters {
+			// the default value, the regular expressions and the special parameters are not stored in the hash
+			if p.Value == "default" || p.Value == "hostnames" || p.Value == "volatile" || strings.HasPrefix(p.Value, "~") {
+				continue
+			}
+			keys++
+			elementSize = max(elementSize, hashElementSize(strings.Trim(p.Value, `"`)))
+		}
+		if keys == 0 {
+			continue
+		}
+
+		// a bucket also holds the pointer that ends it
+		required := elementSize + 8
+		// the keys are not spread evenly between the buckets, so the warning is reported when the keys
+		// take more than half of the hash
+		if keys*elementSize*2 > maxSize*(bucketSize-min(bucketSize, 8)) {
+			required = max(required, (keys*elementSize*2+maxSize-1)/maxSize+8)
+		}
+		if required > bucketSize {
+			vsc.addWarningf(owner, "The map of the variable %s with %d keys doesn't fit in the hash of maps: set the map-hash-bucket-size ConfigMap key to %d or more", m.Variable, keys, nextPowerOfTwo(required))
+		}
+	}
+}
+
+// addUndefinedGeos adds an empty geo for each of the geos that the conditions of the subroutes of the
+// VirtualServerRoutes reference but the VirtualServer doesn't define, as NGINX fails to reload with an unknown
+// variable. The references of the routes of the VirtualServer are checked by the validation.
+func (vsc *virtualServerConfigurator) addUndefinedGeos(vsEx *VirtualServerEx, vsCfg *version2.VirtualServerConfig) {
+	variableNamer := NewVSVariableNamer(vsEx.VirtualServer)
+
+	defined := make(map[string]bool)
+	for _, g := range vsEx.VirtualServer.Spec.Geos {
+		defined[g.Name] = true
+	}
+	added := make(map[string]bool)
+
+	checkConditions := func(owner runtime.Object, conditions []conf_v1.Condition, path string) {
+		for _, c := range conditions {
+			if c.Geo == "" || defined[c.Geo] {
+				continue
+			}
+			vsc.addWarningf(owner, "The conditions of the route %s reference the geo %s, which is not defined in the geos of VirtualServer %s/%s, the geo has an empty value",
+				path, c.Geo, vsEx.VirtualServer.Namespace, vsEx.VirtualServer.Name)
+			if added[c.Geo] {
+				continue
+			}
+			added[c.Geo] = true
+			vsCfg.Geos = append(vsCfg.Geos, version2.Geo{
+				Source:     "$remote_addr",
+				Variable:   variableNamer.GetNameForGeoVariable(c.Geo),
+				Parameters: []version2.Parameter{{Value: "default", Result: `""`}},
+			})
+		}
+	}
+
+	for _, vsr := range vsEx.VirtualServerRoutes {
+		for _, r := range vsr.Spec.Subroutes {
+			for _, m := range r.Matches {
+				checkConditions(vsr, m.Conditions, r.Path)
+			}
+			if r.Tarpit != nil {
+				checkConditions(vsr, r.Tarpit.Conditions, r.Path)
+			}
+		}
+	}
+}
+
+var upstreamVariableRegexp = regexp.MustCompile(`\$\{?(upstream_\w+)`)
+
+// removeDebugAddHeaders removes the headers added to the responses that reference the variables of the upstream,
+// which are only allowed when debugHeaders of the VirtualServer is enabled.
+func (vsc *virtualServerConfigurator) removeDebugAddHeaders(owner runtime.Object, vsCfg *version2.VirtualServerConfig) {
+	isDebugHeader := func(context string) func(h version2.AddHeader) bool {
+		return func(h version2.AddHeader) bool {
+			if match := upstreamVariableRegexp.FindStringSubmatch(h.Value); match != nil {
+				vsc.addWarningf(owner, "header %s of %s references the variable $%s, which requires debugHeaders to be enabled, and is ignored", h.Name, context, match[1])
+				return true
+			}
+			return false
+		}
+	}
+
+	vsCfg.Server.AddHeaders = slices.DeleteFunc(vsCfg.Server.AddHeaders, isDebugHeader("the server"))
+	for i := range vsCfg.Server.Locations {
+		loc := &vsCfg.Server.Locations[i]
+		loc.AddHeaders = slices.DeleteFunc(loc.AddHeaders, isDebugHeader("location "+loc.Path))
+	}
+}
+
+const defaultErrorLogDestination = "stderr"
+
+// generateErrorLog returns the error log of the server, or nil to keep the error log inherited from the
+// main context.
+func (vsc *virtualServerConfigurator) generateErrorLog(vs *conf_v1.VirtualServer) *version2.ErrorLog {
+	level := vs.Spec.ErrorLogLevel
+	if level == "" {
+		return nil
+	}
+	if _, err := ParseErrorLogLevel(level); err != nil {
+		vsc.addWarningf(vs, "Invalid error log level %q: %v. The error log level of the main context is used", level, err)
+		return nil
+	}
+
+	destination := vs.Spec.ErrorLogDestination
+	if destination == "" {
+		destination = defaultErrorLogDestination
+	}
+
+	return &version2.ErrorLog{
+		Destination: destination,
+		Level:       level,
+	}
+}
+
+// generateUnderscoresInHeaders returns the value of the underscores_in_headers directive for the server,
+// or an empty string to keep the value inherited from the http context.
+// NGINX reads the request headers with the value of the default server of the listener, which is never a VirtualServer,
+// unless the server is selected by SNI before the request is read, so the value applies only to the HTTPS requests.
+func (vsc *virtualServerConfigurator) generateUnderscoresInHeaders(vs *conf_v1.VirtualServer) string {
+	underscoresInHeaders := vs.Spec.UnderscoresInHeaders
+	if underscoresInHeaders == nil {
+		return ""
+	}
+	if vs.Spec.TLS == nil {
+		vsc.addWarningf(vs, "underscoresInHeaders has no effect without TLS: NGINX applies it only to the HTTPS requests, for which the server is selected by SNI")
+	}
+	if *underscoresInHeaders {
+		return "on"
+	}
+	return "off"
+}
+
+// generateMergeSlashes returns the value of the merge_slashes directive for the server,
+// or an empty string to keep the value inherited from the http context.
+func (vsc *virtualServerConfigurator) generateMergeSlashes(vs *conf_v1.VirtualServer) string {
+	mergeSlashes := vs.Spec.MergeSlashes
+	if mergeSlashes == nil {
+		return ""
+	}
+	if *mergeSlashes {
+		return "on"
+	}
+
+	vsc.addWarningf(vs, "Merging of slashes is disabled: the URIs with adjacent slashes are not matched by the routes for the URIs with single slashes, so the policies of those routes are not applied to them")
+	return "off"
+}
+
 func (vsc *virtualServerConfigurator) generateExternalAuthLocation(policiesCfg policiesCfg, proxyURLUpstreamName string) version2.Location {
 	var svcName string
 	_, svcName = ParseServiceReference(policiesCfg.ExternalAuth.URI.Service, "")
@@ -1282,6 +1944,117 @@ func getServerErrorPages(cfg policiesCfg) []version2.ErrorPage {
 	return nil
 }
 
+// noEndpointsErrorCode is the status code of the error that NGINX generates when the upstream has no available servers.
+const noEndpointsErrorCode = 502
+
+// noEndpointsOtherErrorLocation is the named location for the 502 errors that are not caused by an upstream
+// without available endpoints. It keeps the default response of NGINX for them.
+const noEndpointsOtherErrorLocation = "@upstream_error_502"
+
+// generateNoEndpointsErrorPage generates the error page for the requests for an upstream without available endpoints,
+// its return locations and the map that selects the return location by $upstream_addr. NGINX sets $upstream_addr
+// to the name of the upstream when none of its servers is available, while the other 502 errors have the address
+// of a server there and get the location that returns the default 502 response.
+func generateNoEndpointsErrorPage(noEndpoints *conf_v1.NoEndpoints, upstreams []version2.Upstream, retLocIndex int,
+	variableNamer *VariableNamer, owner runtime.Object, vscWarnings Warnings,
+) (version2.ErrorPage, []version2.ReturnLocation, version2.Map) {
+	var returnLoc *version2.ReturnLocation
+	var returnLocName string
+
+	if noEndpoints.Return != nil {
+		loc, retLoc := generateLocationForReturn("", nil, noEndpoints.Return, retLocIndex, variableNamer, owner, vscWarnings)
+		// the error page uses the code of the response of the return location, as the other 502 errors must keep their code
+		code := loc.ErrorPages[0].ResponseCode
+		retLoc.Return.Code = code
+		for i := range retLoc.Alternatives {
+			retLoc.Alternatives[i].Return.Code = code
+		}
+		returnLoc = retLoc
+		returnLocName = loc.ErrorPages[0].Name
+	} else {
+		code := noEndpoints.Code
+		if code == 0 {
+			code = noEndpointsErrorCode
+		}
+
+		var headers []version2.Header
+		if noEndpoints.RetryAfter != nil {
+			headers = append(headers, version2.Header{
+				Name:  "Retry-After",
+				Value: strconv.Itoa(*noEndpoints.RetryAfter),
+			})
+		}
+
+		returnLocName = fmt.Sprintf("@return_%d", retLocIndex)
+		returnLoc = &version2.ReturnLocation{
+			Name:    returnLocName,
+			Return:  version2.Return{Code: code},
+			Headers: headers,
+		}
+	}
+
+	upstreamMap := version2.Map{
+		Source:   "$upstream_addr",
+		Variable: variableNamer.GetNameForNoEndpointsVariable(),
+		// $upstream_addr is only set after the request is passed to the upstream
+		Volatile: true,
+	}
+	for _, u := range upstreams {
+		upstreamMap.Parameters = append(upstreamMap.Parameters, version2.Parameter{
+			Value:  fmt.Sprintf("%q", u.Name),
+			Result: returnLocName,
+		})
+	}
+	upstreamMap.Parameters = append(upstreamMap.Parameters, version2.Parameter{
+		Value:  "default",
+		Result: noEndpointsOtherErrorLocation,
+	})
+
+	errorPage := version2.ErrorPage{
+		Name:         upstreamMap.Variable,
+		Codes:        strconv.Itoa(noEndpointsErrorCode),
+		ResponseCode: -1,
+	}
+	returnLocs := []version2.ReturnLocation{
+		*returnLoc,
+		{
+			Name:   noEndpointsOtherErrorLocation,
+			Return: version2.Return{Code: noEndpointsErrorCode},
+		},
+	}
+
+	return errorPage, returnLocs, upstreamMap
+}
+
+// addNoEndpointsErrorPageToLocations adds the error page for the requests for an upstream without available endpoints
+// to the locations of a route, unless the route passes the 502 errors through. The locations without error pages
+// get the error pages of the server too, as they no longer inherit them. The locations that intercept the errors
+// of the upstream servers or handle the 502 errors themselves are skipped, so that the 502 responses
+// of the upstream servers are not rewritten.
+func addNoEndpointsErrorPageToLocations(errorPage *version2.ErrorPage, serverErrorPages []version2.ErrorPage,
+	passthroughErrorCodes []int, locations []version2.Location,
+) {
+	if errorPage == nil || slices.Contains(passthroughErrorCodes, noEndpointsErrorCode) {
+		return
+	}
+
+	for i := range locations {
+		loc := &locations[i]
+		if loc.ProxyInterceptErrors || loc.GRPCPass != "" {
+			continue
+		}
+		if slices.ContainsFunc(loc.ErrorPages, func(e version2.ErrorPage) bool {
+			return slices.Contains(strings.Fields(e.Codes), errorPage.Codes)
+		}) {
+			continue
+		}
+		if len(loc.ErrorPages) == 0 {
+			loc.ErrorPages = slices.Clone(serverErrorPages)
+		}
+		loc.ErrorPages = append(loc.ErrorPages, *errorPage)
+	}
+}
+
 func (vsc *virtualServerConfigurator) mergeWarnings(routeWarnings Warnings) {
 	for obj, msgs := range routeWarnings {
 		vsc.addWarnings(obj, msgs)
@@ -1301,8 +2074,12 @@ func generateUpstreams(
 	healthChecks []version2.HealthCheck,
 	statusMatches []version2.StatusMatch,
 ) ([]version2.Upstream, []version2.HealthCheck, []version2.StatusMatch) {
-	if (sslConfig == nil || !vsc.cfgParams.HTTP2) && isGRPC(u.Type) {
-		vsc.addWarningf(owner, "gRPC cannot be configured for upstream %s. gRPC requires enabled HTTP/2 and TLS termination", u.Name)
+	if isGRPC(u.Type) {
+		if http2 := vsEx.VirtualServer.Spec.HTTP2; http2 != nil && !*http2 {
+			vsc.addWarningf(owner, "gRPC cannot be configured for upstream %s. gRPC requires HTTP/2, which is disabled for VirtualServer %s/%s", u.Name, vsEx.VirtualServer.Namespace, vsEx.VirtualServer.Name)
+		} else if sslConfig == nil || !sslConfig.HTTP2 {
+			vsc.addWarningf(owner, "gRPC cannot be configured for upstream %s. gRPC requires enabled HTTP/2 and TLS termination", u.Name)
+		}
 	}
 
 	upstreamName := upstreamNamer.GetNameForUpstream(u.Name)
@@ -1311,24 +2088,162 @@ func generateUpstreams(
 
 	// isExternalNameSvc is always false for OSS
 	_, isExternalNameSvc := vsEx.ExternalNameSvcs[GenerateExternalNameSvcKey(ownerNamespace, u.Service)]
-	ups := vsc.generateUpstream(owner, upstreamName, u, isExternalNameSvc, endpoints, backup)
+	downEndpoints := vsc.generateTerminatingEndpointsForUpstream(ownerNamespace, u, vsEx)
+	if vsEx.VirtualServer.Spec.NoEndpoints != nil && slices.Equal(endpoints, []string{nginx502Server}) {
+		// NGINX generates the 502 error itself when the only server is down, so that the error page of noEndpoints applies to it
+		downEndpoints = []string{nginx502Server}
+	}
+	ups := vsc.generateUpstream(owner, upstreamName, u, isExternalNameSvc, endpoints, backup, downEndpoints)
+	if fullName := upstreamNamer.GetFullNameForUpstream(u.Name); fullName != upstreamName {
+		ups.FullName = fullName
+	}
+	if vsc.upstreamServerPodComments {
+		addPodNamesToUpstreamServers(ups.Servers, vsEx.PodsByIP)
+	}
 	upstreams = append(upstreams, ups)
+	if u.TLS.Enable && len(u.TLS.Ports) > 0 && u.Backup != "" && u.BackupPort != nil &&
+		slices.Contains(u.TLS.Ports, *u.BackupPort) != slices.Contains(u.TLS.Ports, u.Port) {
+		vsc.addWarningf(owner, "The backup servers of upstream %s on port %d use %s like the servers on port %d, as NGINX uses the same protocol for all the servers of an upstream",
+			u.Name, *u.BackupPort, strings.ToUpper(generateProxyPassProtocol(isTLSEnabled(u))), u.Port)
+	}
 	u.TLS.Enable = isTLSEnabled(u)
+	if u.TimeoutProfile != "" {
+		if profile, ok := findTimeoutProfile(vsEx.VirtualServer, u.TimeoutProfile); ok {
+			u = applyTimeoutProfile(u, profile)
+		} else {
+			vsc.addWarningf(owner, "Upstream %s references the timeout profile %s, which is not defined in the timeoutProfiles of VirtualServer %s/%s, the default timeouts are used",
+				u.Name, u.TimeoutProfile, vsEx.VirtualServer.Namespace, vsEx.VirtualServer.Name)
+		}
+	}
+	if isZeroSize(u.ClientMaxBodySize) {
+		if u.AllowUnlimitedBodySize {
+			vsc.addWarningf(owner, "Upstream %s accepts the request bodies of unlimited size, as client-max-body-size is 0", u.Name)
+		} else {
+			vsc.addWarningf(owner, "The 0 value of client-max-body-size of upstream %s, which accepts the request bodies of unlimited size, is ignored without allow-unlimited-body-size, the default is used", u.Name)
+			u.ClientMaxBodySize = ""
+		}
+	}
+	if u.ProxyIgnoreClientAbort != nil && *u.ProxyIgnoreClientAbort {
+		vsc.addWarningf(owner, "Upstream %s ignores the aborts of clients: the connections to the upstream servers are kept until they respond, which can exhaust the connections when the upstream servers are slow", u.Name)
+	}
+	if u.Failover != nil {
+		if len(ups.BackupServers) > 0 {
+			u.ProxyNextUpstreamTries = generateFailoverTries(u.Failover, len(ups.Servers), len(ups.BackupServers))
+			u.ProxyNextUpstreamTimeout = u.Failover.Timeout
+		} else {
+			vsc.addWarningf(owner, "Upstream %s configures the failover, but its backup service %s has no servers, the failover is ignored", u.Name, u.Backup)
+		}
+	}
 	crUpstreams[upstreamName] = u
 
 	if hc := generateHealthCheck(u, upstreamName, vsc.cfgParams); hc != nil {
-		healthChecks = append(healthChecks, *hc)
+		vsc.checkHealthCheckKeepaliveTime(owner, u, hc)
 		if u.HealthCheck.StatusMatch != "" {
 			statusMatches = append(
 				statusMatches,
 				generateUpstreamStatusMatch(upstreamName, u.HealthCheck.StatusMatch),
 			)
 		}
+		if u.HealthCheck.Match != "" {
+			if statusMatch, ok := generateSharedStatusMatch(vsEx.VirtualServer, u.HealthCheck.Match); ok {
+				hc.Match = statusMatch.Name
+				if !slices.Contains(statusMatches, statusMatch) {
+					statusMatches = append(statusMatches, statusMatch)
+				}
+			} else {
+				vsc.addWarningf(owner, "The health check of upstream %s references the match %s, which is not defined in the healthCheckMatches of VirtualServer %s/%s, the default match is used",
+					u.Name, u.HealthCheck.Match, vsEx.VirtualServer.Namespace, vsEx.VirtualServer.Name)
+			}
+		}
+		healthChecks = append(healthChecks, *hc)
 	}
 	return upstreams, healthChecks, statusMatches
 }
 
-func generateAPIKeyClientMap(mapName string, apiKeyClients []apiKeyClient) *version2.Map {
+// generateProxyPassURLUpstreamName generates a stable upstream name for a literal proxyPassURL.
+func generateProxyPassURLUpstreamName(proxyPassURL string) string {
+	hash := sha256.Sum256([]byte(proxyPassURL))
+	return fmt.Sprintf("proxy_pass_url_%s", hex.EncodeToString(hash[:])[:8])
+}
+
+// getRouteActions returns all actions of a route, including the actions of its splits and matches.
+func getRouteActions(route conf_v1.Route) []*conf_v1.Action {
+	var actions []*conf_v1.Action
+	if route.Action != nil {
+		actions = append(actions, route.Action)
+	}
+	for _, s := range route.Splits {
+		if s.Action != nil {
+			actions = append(actions, s.Action)
+		}
+	}
+	for _, m := range route.Matches {
+		if m.Action != nil {
+			actions = append(actions, m.Action)
+		}
+		for _, s := range m.Splits {
+			if s.Action != nil {
+				actions = append(actions, s.Action)
+			}
+		}
+	}
+	return actions
+}
+
+// generateProxyPassURLUpstreams generates a synthetic upstream for every proxyPassURL action of the routes.
+// Hostnames are resolved at runtime when a resolver is configured.
+func (vsc *virtualServerConfigurator) generateProxyPassURLUpstreams(
+	owner runtime.Object,
+	routes []conf_v1.Route,
+	upstreamNamer *upstreamNamer,
+	upstreams []version2.Upstream,
+	crUpstreams map[string]conf_v1.Upstream,
+) []version2.Upstream {
+	for _, r := range routes {
+		for _, action := range getRouteActions(r) {
+			if action.ProxyPassURL == "" {
+				continue
+			}
+
+			upstreamName := upstreamNamer.GetNameForUpstreamFromAction(action)
+			if _, exists := crUpstreams[upstreamName]; exists {
+				continue
+			}
+
+			u, err := url.Parse(action.ProxyPassURL)
+			if err != nil {
+				vsc.addWarningf(owner, "Invalid proxyPassURL %s in route with path %s: %v", action.ProxyPassURL, r.Path, err)
+				continue
+			}
+
+			tlsEnabled := u.Scheme == "https"
+			port := u.Port()
+			if port == "" {
+				port = "80"
+				if tlsEnabled {
+					port = "443"
+				}
+			}
+
+			isHostname := net.ParseIP(u.Hostname()) == nil
+			if isHostname && !vsc.isResolverConfigured {
+				vsc.addWarningf(owner, "Hostname %s in proxyPassURL %s will only be resolved on reload. To resolve it at runtime, a resolver must be configured in the ConfigMap", u.Hostname(), action.ProxyPassURL)
+			}
+
+			upstream := conf_v1.Upstream{
+				Name: upstreamName,
+				TLS:  conf_v1.UpstreamTLS{Enable: tlsEnabled},
+			}
+			endpoints := []string{net.JoinHostPort(u.Hostname(), port)}
+			upstreams = append(upstreams, vsc.generateUpstream(owner, upstreamName, upstream, isHostname && vsc.isResolverConfigured, endpoints, nil, nil))
+			crUpstreams[upstreamName] = upstream
+		}
+	}
+
+	return upstreams
+}
+
+func generateAPIKeyClientMap(mapName string, apiKeyClients []apiKeyClient) *version2.Map {
 	defaultParam := version2.Parameter{
 		Value:  "default",
 		Result: "\"\"",
@@ -1446,8 +2361,10 @@ func hasDuplicateMapDefaults(m *version2.Map) bool {
 func addPoliciesCfgToLocation(cfg policiesCfg, location *version2.Location) {
 	location.Allow = cfg.Allow
 	location.Deny = cfg.Deny
+	location.Satisfy = cfg.Satisfy
 	location.LimitReqOptions = cfg.RateLimit.Options
 	location.LimitReqs = cfg.RateLimit.Reqs
+	location.Variables = cfg.Variables
 	location.JWTAuth = cfg.JWTAuth.Auth
 	location.ExternalAuth = cfg.ExternalAuth
 	location.BasicAuth = cfg.BasicAuth
@@ -1494,6 +2411,236 @@ func addAddHeaderInheritToLocations(addHeaderInherit string, locations []version
 	}
 }
 
+func addPreserveHostHeaderToLocations(preserveHostHeader bool, locations []version2.Location) {
+	if !preserveHostHeader {
+		return
+	}
+	for i := range locations {
+		for j, h := range locations[i].ProxySetHeaders {
+			if strings.EqualFold(h.Name, "Host") && h.Value == "$host" {
+				locations[i].ProxySetHeaders[j].Value = "$http_host"
+			}
+		}
+	}
+}
+
+func addTarpitToLocations(tarpit *version2.Tarpit, locations []version2.Location) {
+	for i := range locations {
+		locations[i].Tarpit = tarpit
+	}
+}
+
+func getRequestIDHeader(requestID *conf_v1.RequestID) string {
+	if requestID.Header == "" {
+		return defaultRequestIDHeader
+	}
+	return requestID.Header
+}
+
+// generateRequestIDMap generates a map that keeps the request ID sent by the client
+// and falls back to the request ID generated by NGINX when the client didn't send one.
+func generateRequestIDMap(requestID *conf_v1.RequestID, variableNamer *VariableNamer) version2.Map {
+	header := getRequestIDHeader(requestID)
+	source := "$http_" + strings.ReplaceAll(strings.ToLower(header), "-", "_")
+
+	return version2.Map{
+		Source:   source,
+		Variable: variableNamer.GetNameForRequestIDVariable(),
+		Parameters: []version2.Parameter{
+			{Value: `""`, Result: "$request_id"},
+			{Value: "default", Result: source},
+		},
+	}
+}
+
+// addRequestIDToLocations passes the request ID to the upstream servers and back to the client,
+// unless the location already sets the header.
+func addRequestIDToLocations(header string, variable string, locations []version2.Location) {
+	for i := range locations {
+		if !hasHeader(locations[i].ProxySetHeaders, header) {
+			locations[i].ProxySetHeaders = append(locations[i].ProxySetHeaders, version2.Header{Name: header, Value: variable})
+		}
+		if !hasAddHeader(locations[i].AddHeaders, header) {
+			locations[i].AddHeaders = append(locations[i].AddHeaders, version2.AddHeader{
+				Header: version2.Header{Name: header, Value: variable},
+				Always: true,
+			})
+		}
+	}
+}
+
+func hasHeader(headers []version2.Header, name string) bool {
+	for _, h := range headers {
+		if strings.EqualFold(h.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAddHeader(headers []version2.AddHeader, name string) bool {
+	for _, h := range headers {
+		if strings.EqualFold(h.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func addClientIPReturnToLocations(clientIPReturn *version2.ClientIPReturn, locations []version2.Location) {
+	for i := range locations {
+		locations[i].ClientIPReturn = clientIPReturn
+	}
+}
+
+// addGRPCErrorPagesToLocations adds the custom responses for the gRPC statuses of a route to its locations.
+// The responses are ignored with a warning when none of the locations pass the requests to a gRPC upstream.
+func (vsc *virtualServerConfigurator) addGRPCErrorPagesToLocations(owner runtime.Object, path string, grpcErrorPages map[string]string, locations []version2.Location) {
+	if len(grpcErrorPages) == 0 {
+		return
+	}
+	if !slices.ContainsFunc(locations, func(l version2.Location) bool { return l.GRPCPass != "" }) {
+		vsc.addWarningf(owner, "The gRPC error responses of the route with the path %s are ignored, as the route doesn't pass the requests to a gRPC upstream", path)
+		return
+	}
+	for i := range locations {
+		locations[i].GRPCErrorPages = grpcErrorPages
+	}
+}
+
+// checkSSLClientVerifyConditions warns about the matches conditions on the result of the client certificate verification
+// that cannot match anything but NONE, because the VirtualServer does not request the client certificates optionally.
+func (vsc *virtualServerConfigurator) checkSSLClientVerifyConditions(owner runtime.Object, path string, matches []conf_v1.Match, ingressMTLS *version2.IngressMTLS) {
+	if ingressMTLS != nil && (ingressMTLS.VerifyClient == "optional" || ingressMTLS.VerifyClient == "optional_no_ca") {
+		return
+	}
+	for _, m := range matches {
+		for _, c := range m.Conditions {
+			if c.Variable == "$ssl_client_verify" {
+				vsc.addWarningf(owner, "The matches of the route %s use the $ssl_client_verify variable, which requires an IngressMTLS policy with the verifyClient set to optional or optional_no_ca", path)
+				return
+			}
+		}
+	}
+}
+
+// generateRouteSizeVariables generates the variable that marks the locations of the route with the given index
+// and the maps of the variables with the request length and the bytes sent of the route for the access log.
+// Unlike the variables set in a location, the maps are evaluated when the access log is written,
+// so that the variables have the final values of the request.
+func generateRouteSizeVariables(index int, variableNamer *VariableNamer) (*version2.Variable, []version2.Map) {
+	routeVariable := variableNamer.GetNameForRouteVariable()
+	value := fmt.Sprintf(`"%d"`, index)
+
+	maps := []version2.Map{
+		{
+			Source:   routeVariable,
+			Variable: variableNamer.GetNameForRouteRequestLengthVariable(index),
+			Parameters: []version2.Parameter{
+				{Value: value, Result: "$request_length"},
+				{Value: "default", Result: `""`},
+			},
+		},
+		{
+			Source:   routeVariable,
+			Variable: variableNamer.GetNameForRouteBytesSentVariable(index),
+			Parameters: []version2.Parameter{
+				{Value: value, Result: "$bytes_sent"},
+				{Value: "default", Result: `""`},
+			},
+		},
+	}
+
+	return &version2.Variable{Name: routeVariable, Value: value}, maps
+}
+
+// generateServerVariables adds the variable that marks the locations of the routes to the variables of the server,
+// so that the variable is initialized for the requests that don't match any route.
+func generateServerVariables(variables []version2.Variable, routeSizeVariables bool, variableNamer *VariableNamer) []version2.Variable {
+	if !routeSizeVariables {
+		return variables
+	}
+	return append(slices.Clone(variables), version2.Variable{Name: variableNamer.GetNameForRouteVariable(), Value: `""`})
+}
+
+func addRouteVariableToLocations(variable *version2.Variable, locations []version2.Location) {
+	if variable == nil {
+		return
+	}
+	for i := range locations {
+		locations[i].Variables = append(locations[i].Variables, *variable)
+	}
+}
+
+func addStatusZoneToLocations(statusZone string, locations []version2.Location) {
+	for i := range locations {
+		locations[i].StatusZone = statusZone
+	}
+}
+
+// generateRouteStatusZone generates the status zone of the locations of a route.
+// The empty status zone keeps the default status zone named after the service of the location.
+func (vsc *virtualServerConfigurator) generateRouteStatusZone(statusZone string, host string, path string) string {
+	if !vsc.isPlus || statusZone == "" {
+		return ""
+	}
+	if statusZone == autoRouteStatusZone {
+		return escapeNginxString(host + path)
+	}
+	return statusZone
+}
+
+// generateVirtualServerRouteStatusZone generates the status zone of the locations of the subroutes of a VirtualServerRoute.
+// The value auto derives the name from the namespace and the name of the VirtualServerRoute.
+func (vsc *virtualServerConfigurator) generateVirtualServerRouteStatusZone(vsr *conf_v1.VirtualServerRoute) string {
+	if !vsc.isPlus || vsr.Spec.StatusZone == "" {
+		return ""
+	}
+	if vsr.Spec.StatusZone == autoRouteStatusZone {
+		return escapeNginxString(vsr.Namespace + "/" + vsr.Name)
+	}
+	return vsr.Spec.StatusZone
+}
+
+func addTracingToLocations(tracing *version2.Tracing, locations []version2.Location) {
+	for i := range locations {
+		locations[i].Tracing = tracing
+	}
+}
+
+// generateTracing generates the OpenTelemetry tracing of a VirtualServer or a route.
+// The tracing is ignored when the OpenTelemetry module is not loaded, as NGINX doesn't know the otel directives then.
+func (vsc *virtualServerConfigurator) generateTracing(owner runtime.Object, tracing *conf_v1.Tracing) *version2.Tracing {
+	if tracing == nil {
+		return nil
+	}
+	if !vsc.cfgParams.MainOtelLoadModule {
+		vsc.addWarningf(owner, "Tracing is ignored as the otel-exporter-endpoint ConfigMap key is not set")
+		return nil
+	}
+	return &version2.Tracing{
+		Enable:  generateBool(tracing.Enable, vsc.cfgParams.MainOtelTraceInHTTP),
+		Context: generateString(tracing.Context, defaultTracingContext),
+	}
+}
+
+// generateRouteTracing generates the tracing of the locations of a route. A route without tracing uses the tracing of the VirtualServer.
+// The spans of an enabled tracing are tagged with the path of the route, unless the path contains variables, like a regex path can.
+func (vsc *virtualServerConfigurator) generateRouteTracing(owner runtime.Object, tracing *conf_v1.Tracing, serverTracing *version2.Tracing, path string) *version2.Tracing {
+	routeTracing := serverTracing
+	if tracing != nil {
+		routeTracing = vsc.generateTracing(owner, tracing)
+	}
+	if routeTracing == nil || !routeTracing.Enable {
+		return routeTracing
+	}
+	t := *routeTracing
+	if !strings.Contains(path, "$") {
+		t.Route = escapeNginxString(path)
+	}
+	return &t
+}
+
 func addHSTSToLocationsWithAddHeaders(hsts *version2.HSTS, locations []version2.Location) {
 	if hsts == nil {
 		return
@@ -1507,6 +2654,48 @@ func addHSTSToLocationsWithAddHeaders(hsts *version2.HSTS, locations []version2.
 	}
 }
 
+// addHSTSHideHeaderToLocationsWithHideHeaders hides the Strict-Transport-Security header of the upstream in the locations
+// with their own proxy_hide_header directives, as NGINX doesn't inherit the proxy_hide_header directives of the server
+// in such locations. The locations with the HSTS config hide the header themselves.
+func addHSTSHideHeaderToLocationsWithHideHeaders(hsts *version2.HSTS, locations []version2.Location) {
+	if hsts == nil {
+		return
+	}
+	for i := range locations {
+		loc := &locations[i]
+		if len(loc.ProxyHideHeaders) == 0 || loc.HSTS != nil {
+			continue
+		}
+		if slices.ContainsFunc(loc.ProxyHideHeaders, func(h string) bool {
+			return strings.EqualFold(h, "Strict-Transport-Security")
+		}) {
+			continue
+		}
+		// the hidden headers can share their array with the default hidden headers
+		loc.ProxyHideHeaders = append(slices.Clip(loc.ProxyHideHeaders), "Strict-Transport-Security")
+	}
+}
+
+// addServerAddHeadersToLocations adds the headers of the server to the locations with their own add_header directives,
+// as NGINX doesn't inherit the add_header directives of the server in such locations. The locations and servers with
+// add_header_inherit are skipped, as the directive defines the inheritance itself.
+// The headers set by the location take precedence over the headers of the server with the same name.
+func addServerAddHeadersToLocations(addHeaders []version2.AddHeader, serverAddHeaderInherit string, locations []version2.Location) {
+	if serverAddHeaderInherit != "" {
+		return
+	}
+	for i := range locations {
+		if len(locations[i].AddHeaders) == 0 || locations[i].AddHeaderInherit != "" {
+			continue
+		}
+		for _, h := range addHeaders {
+			if !hasAddHeader(locations[i].AddHeaders, h.Name) {
+				locations[i].AddHeaders = append(locations[i].AddHeaders, h)
+			}
+		}
+	}
+}
+
 func getUpstreamResourceLabels(owner runtime.Object) version2.UpstreamLabels {
 	var resourceType, resourceName, resourceNamespace string
 
@@ -1535,11 +2724,15 @@ func (vsc *virtualServerConfigurator) generateUpstream(
 	isExternalNameSvc bool,
 	endpoints []string,
 	backupEndpoints []string,
+	downEndpoints []string,
 ) version2.Upstream {
+	drain := vsc.isPlus && upstream.Drain
 	var upsServers []version2.UpstreamServer
 	for _, e := range endpoints {
 		s := version2.UpstreamServer{
 			Address: e,
+			Down:    slices.Contains(downEndpoints, e) && !drain,
+			Drain:   slices.Contains(downEndpoints, e) && drain,
 		}
 		upsServers = append(upsServers, s)
 	}
@@ -1559,6 +2752,9 @@ func (vsc *virtualServerConfigurator) generateUpstream(
 	})
 
 	lbMethod := generateLBMethod(upstream.LBMethod, vsc.cfgParams.LBMethod)
+	if strings.HasPrefix(lbMethod, "hash") {
+		lbMethod = vsc.generateHashLBMethod(owner, upstream, lbMethod)
+	}
 
 	upstreamLabels := getUpstreamResourceLabels(owner)
 	upstreamLabels.Service = upstream.Service
@@ -1571,22 +2767,88 @@ func (vsc *virtualServerConfigurator) generateUpstream(
 		LBMethod:         lbMethod,
 		SessionCookie:    generateSessionCookie(upstream.SessionCookie),
 		Keepalive:        generateIntFromPointer(upstream.Keepalive, vsc.cfgParams.Keepalive),
+		KeepaliveTime:    generateTime(upstream.KeepaliveTime),
 		MaxFails:         generateIntFromPointer(upstream.MaxFails, vsc.cfgParams.MaxFails),
 		FailTimeout:      generateTimeWithDefault(upstream.FailTimeout, vsc.cfgParams.FailTimeout),
 		MaxConns:         generateIntFromPointer(upstream.MaxConns, vsc.cfgParams.MaxConns),
-		UpstreamZoneSize: vsc.cfgParams.UpstreamZoneSize,
+		UpstreamZoneSize: vsc.generateUpstreamZoneSize(isExternalNameSvc, len(upsServers)+len(upsBackupServers)),
 		BackupServers:    upsBackupServers,
 	}
 
+	if ups.Resolve && ups.Keepalive > 0 && ups.KeepaliveTime == "" {
+		msgFmt := "Keepalive connections of upstream %v to resolved servers are not bounded by keepalive-time and can be reused for up to 1h after the addresses change"
+		vsc.addWarningf(owner, msgFmt, upstream.Name)
+	}
+
+	if isGRPCNextUpstreamLimitedToUnsentRequests(upstream) {
+		msgFmt := "gRPC requests to upstream %v are passed to the next server in the cases of next-upstream only if they have not been sent to an upstream server yet, as gRPC uses the POST method. Add non_idempotent to next-upstream if the gRPC methods are idempotent"
+		vsc.addWarningf(owner, msgFmt, upstream.Name)
+	}
+
+	if upstream.TLS.SessionReuse != nil && !upstream.TLS.Enable {
+		vsc.addWarningf(owner, "TLS session reuse of upstream %v is ignored because TLS is not enabled for the upstream", upstream.Name)
+	}
+
+	if isNextUpstreamUnlimited(upstream) {
+		msgFmt := "Requests to upstream %v are passed to the next server in the cases of next-upstream until all servers have been tried, as neither next-upstream-timeout nor next-upstream-tries limits the retries"
+		vsc.addWarningf(owner, msgFmt, upstream.Name)
+	}
+
+	if isNextUpstreamOff(upstream) {
+		if len(strings.Fields(upstream.ProxyNextUpstream)) > 1 {
+			vsc.addWarningf(owner, "The cases of next-upstream of upstream %v are ignored, as next-upstream is off", upstream.Name)
+		}
+		if upstream.ProxyNextUpstreamTimeout != "" || upstream.ProxyNextUpstreamTries != 0 {
+			vsc.addWarningf(owner, "The next-upstream-timeout and next-upstream-tries of upstream %v are ignored, as next-upstream is off", upstream.Name)
+		}
+	}
+
 	if vsc.isPlus {
 		ups.SlowStart = vsc.generateSlowStartForPlus(owner, upstream, lbMethod)
 		ups.Queue = generateQueueForPlus(upstream.Queue, "60s")
 		ups.NTLM = upstream.NTLM
+		ups.Resolver = vsc.generateUpstreamResolverForPlus(owner, upstream, isExternalNameSvc)
+	} else if upstream.ResolveInterval != "" {
+		vsc.addWarningf(owner, "Resolve interval of upstream %v is ignored, because it is supported only in NGINX Plus", upstream.Name)
 	}
 
 	return ups
 }
 
+// generateUpstreamZoneSize returns the size of the shared memory zone of an upstream. The zone is omitted for
+// upstreams of NGINX with a small number of static servers, since the zone is required only for the resolving of
+// the servers and for the dynamic reconfiguration of the upstreams in NGINX Plus.
+func (vsc *virtualServerConfigurator) generateUpstreamZoneSize(isExternalNameSvc bool, serversCount int) string {
+	if vsc.isPlus || isExternalNameSvc || vsc.cfgParams.UpstreamZoneOmitMaxServers == 0 {
+		return vsc.cfgParams.UpstreamZoneSize
+	}
+	if serversCount <= vsc.cfgParams.UpstreamZoneOmitMaxServers {
+		return "0"
+	}
+	return vsc.cfgParams.UpstreamZoneSize
+}
+
+// generateHashLBMethod validates the key of the hash load balancing method. An invalid method is replaced with the
+// default round robin method, since NGINX would fail to reload with it.
+func (vsc *virtualServerConfigurator) generateHashLBMethod(
+	owner runtime.Object,
+	upstream conf_v1.Upstream,
+	lbMethod string,
+) string {
+	method, err := validateHashLBMethod(lbMethod)
+	if err != nil {
+		vsc.addWarningf(owner, "Upstream %v uses the round robin load balancing method instead: %v", upstream.Name, err)
+		return ""
+	}
+
+	if key := strings.Fields(method)[1]; !strings.Contains(key, "$") {
+		msgFmt := "The hash key %q of upstream %v contains no variables, so all requests are passed to the same server"
+		vsc.addWarningf(owner, msgFmt, key, upstream.Name)
+	}
+
+	return method
+}
+
 func (vsc *virtualServerConfigurator) generateSlowStartForPlus(
 	owner runtime.Object,
 	upstream conf_v1.Upstream,
@@ -1607,6 +2869,34 @@ func (vsc *virtualServerConfigurator) generateSlowStartForPlus(
 	return generateTime(upstream.SlowStart)
 }
 
+// generateUpstreamResolverForPlus generates the resolver of an upstream that re-resolves the hostname
+// of the ExternalName service with the resolve interval of the upstream instead of the valid time of the resolver in the http context.
+func (vsc *virtualServerConfigurator) generateUpstreamResolverForPlus(
+	owner runtime.Object,
+	upstream conf_v1.Upstream,
+	isExternalNameSvc bool,
+) *version2.UpstreamResolver {
+	if upstream.ResolveInterval == "" {
+		return nil
+	}
+
+	if !isExternalNameSvc {
+		vsc.addWarningf(owner, "Resolve interval of upstream %v is ignored, because the service %v is not of the type ExternalName", upstream.Name, upstream.Service)
+		return nil
+	}
+
+	if len(vsc.cfgParams.ResolverAddresses) == 0 {
+		vsc.addWarningf(owner, "Resolve interval of upstream %v is ignored, because the resolver-addresses ConfigMap key is not set", upstream.Name)
+		return nil
+	}
+
+	return &version2.UpstreamResolver{
+		Addresses: vsc.cfgParams.ResolverAddresses,
+		Valid:     generateTime(upstream.ResolveInterval),
+		IPV6:      vsc.cfgParams.ResolverIPV6,
+	}
+}
+
 func generateHealthCheck(
 	upstream conf_v1.Upstream,
 	upstreamName string,
@@ -1658,6 +2948,13 @@ func generateHealthCheck(
 		hc.Headers[h.Name] = h.Value
 	}
 
+	if upstream.HealthCheck.DisableKeepalive {
+		hc.KeepaliveTime = ""
+		if !hc.IsGRPC {
+			hc.Headers["Connection"] = "close"
+		}
+	}
+
 	if upstream.HealthCheck.TLS != nil {
 		hc.ProxyPass = fmt.Sprintf("%v://%v", generateProxyPassProtocol(upstream.HealthCheck.TLS.Enable), upstreamName)
 	}
@@ -1674,11 +2971,20 @@ func generateHealthCheck(
 
 	hc.GRPCStatus = upstream.HealthCheck.GRPCStatus
 
-	hc.GRPCService = upstream.HealthCheck.GRPCService
+	hc.GRPCService = generateHealthCheckGRPCService(upstream, cfgParams.HealthCheckGRPCService)
 
 	return hc
 }
 
+// generateHealthCheckGRPCService returns the gRPC service of the health check. For gRPC upstreams that
+// implement the gRPC health checking protocol, the service defaults to the service set in the ConfigMap.
+func generateHealthCheckGRPCService(upstream conf_v1.Upstream, defaultService string) string {
+	if upstream.HealthCheck.GRPCService != "" || !isGRPC(upstream.Type) || upstream.HealthCheck.GRPCStatus != nil {
+		return upstream.HealthCheck.GRPCService
+	}
+	return defaultService
+}
+
 func generateSessionCookie(sc *conf_v1.SessionCookie) *version2.SessionCookie {
 	if sc == nil || !sc.Enable {
 		return nil
@@ -1707,6 +3013,53 @@ func generateUpstreamStatusMatch(upstreamName string, status string) version2.St
 	}
 }
 
+// generateSharedStatusMatch generates the status match for a health check match of the VirtualServer,
+// which all the upstreams that reference the match share.
+func generateSharedStatusMatch(vs *conf_v1.VirtualServer, name string) (version2.StatusMatch, bool) {
+	for _, m := range vs.Spec.HealthCheckMatches {
+		if m.Name == name {
+			return version2.StatusMatch{
+				Name: fmt.Sprintf("vs_%s_%s_match_%s", vs.Namespace, vs.Name, m.Name),
+				Code: m.Status,
+			}, true
+		}
+	}
+	return version2.StatusMatch{}, false
+}
+
+// isZeroSize checks whether the size, like the client-max-body-size of an upstream, is 0 with any suffix.
+func isZeroSize(size string) bool {
+	n, err := strconv.ParseUint(strings.TrimRight(size, "kKmMgG"), 10, 64)
+	return err == nil && n == 0
+}
+
+// addPodNamesToUpstreamServers sets the names of the pods of the upstream servers, which are added as comments
+// to the servers in the generated configuration for debugging.
+func addPodNamesToUpstreamServers(servers []version2.UpstreamServer, podsByIP map[string]PodInfo) {
+	for i := range servers {
+		servers[i].PodName = podsByIP[servers[i].Address].Name
+	}
+}
+
+// findTimeoutProfile returns the timeout profile of the VirtualServer with the given name.
+func findTimeoutProfile(vs *conf_v1.VirtualServer, name string) (conf_v1.TimeoutProfile, bool) {
+	for _, p := range vs.Spec.TimeoutProfiles {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return conf_v1.TimeoutProfile{}, false
+}
+
+// applyTimeoutProfile sets the timeouts of the upstream that are not set explicitly to the timeouts of the profile,
+// so that the locations and the health check of the upstream use them.
+func applyTimeoutProfile(u conf_v1.Upstream, profile conf_v1.TimeoutProfile) conf_v1.Upstream {
+	u.ProxyConnectTimeout = generateString(u.ProxyConnectTimeout, profile.ConnectTimeout)
+	u.ProxyReadTimeout = generateString(u.ProxyReadTimeout, profile.ReadTimeout)
+	u.ProxySendTimeout = generateString(u.ProxySendTimeout, profile.SendTimeout)
+	return u
+}
+
 // GenerateExternalNameSvcKey returns the key to identify an ExternalName service.
 func GenerateExternalNameSvcKey(namespace string, service string) string {
 	return fmt.Sprintf("%v/%v", namespace, service)
@@ -1774,6 +3127,19 @@ func generateRewrites(path string, proxy *conf_v1.ActionProxy, internal bool, or
 	return rewrites
 }
 
+// generateRouteRewrites generates the arguments of the rewrite directives of the rewrites of a route.
+func generateRouteRewrites(rewrites []conf_v1.Rewrite) []string {
+	var result []string
+	for _, r := range rewrites {
+		rewrite := fmt.Sprintf(`"%v" "%v"`, r.Regex, r.Replacement)
+		if r.Flag != "" {
+			rewrite += " " + r.Flag
+		}
+		result = append(result, rewrite)
+	}
+	return result
+}
+
 func generateProxyPassRewrite(path string, proxy *conf_v1.ActionProxy, internal bool) string {
 	if proxy == nil || internal {
 		return ""
@@ -1905,8 +3271,8 @@ type errorPageDetails struct {
 
 func generateLocation(path string, upstreamName string, upstream conf_v1.Upstream, action *conf_v1.Action,
 	cfgParams *ConfigParams, errorPages errorPageDetails, internal bool, proxySSLName string,
-	originalPath string, locSnippets string, enableSnippets bool, retLocIndex int, isVSR bool, vsrName string,
-	vsrNamespace string, vscWarnings Warnings,
+	originalPath string, locSnippets string, enableSnippets bool, retLocIndex int, variableNamer *VariableNamer, isVSR bool,
+	vsrName string, vsrNamespace string, vscWarnings Warnings,
 ) (version2.Location, *version2.ReturnLocation) {
 	locationSnippets := generateSnippets(enableSnippets, locSnippets, cfgParams.LocationSnippets)
 
@@ -1915,21 +3281,56 @@ func generateLocation(path string, upstreamName string, upstream conf_v1.Upstrea
 	}
 
 	if action.Return != nil {
-		return generateLocationForReturn(path, cfgParams.LocationSnippets, action.Return, retLocIndex)
+		return generateLocationForReturn(path, cfgParams.LocationSnippets, action.Return, retLocIndex, variableNamer, errorPages.owner, vscWarnings)
+	}
+
+	if action.Files != nil {
+		return generateLocationForFiles(path, locationSnippets, action.Files), nil
 	}
 
 	checkGrpcErrorPageCodes(errorPages, isGRPC(upstream.Type), upstream.Name, vscWarnings)
 
 	_, serviceName := ParseServiceReference(upstream.Service, "")
 
-	return generateLocationForProxying(path, upstreamName, upstream, cfgParams, errorPages.pages, internal,
-		errorPages.index, proxySSLName, action.Proxy, originalPath, locationSnippets, isVSR, vsrName, vsrNamespace, serviceName), nil
+	loc := generateLocationForProxying(path, upstreamName, upstream, cfgParams, errorPages.pages, internal,
+		errorPages.index, proxySSLName, action.Proxy, originalPath, locationSnippets, isVSR, vsrName, vsrNamespace, serviceName)
+
+	if action.ProxyPassURL != "" {
+		if u, err := url.Parse(action.ProxyPassURL); err == nil {
+			loc.ProxySetHeaders = []version2.Header{{Name: "Host", Value: u.Host}}
+			// the external server is verified and selected by its own hostname instead of a service name
+			loc.ProxySSLName = u.Hostname()
+			loc.ProxySSLServerName = u.Scheme == "https"
+		}
+	}
+
+	if action.Expires != "" {
+		loc.Expires = action.Expires
+		checkExpiresCacheControlConflict(action, originalPath, errorPages.owner, vscWarnings)
+	}
+
+	if action.SubFilter != nil {
+		loc.SubFilter = generateSubFilter(action.SubFilter)
+		loc.ProxySetHeaders = addSubFilterAcceptEncoding(loc.ProxySetHeaders)
+		if !loc.ProxyBuffering {
+			vscWarnings.AddWarningf(errorPages.owner, "The sub filter of the action for the path %s requires the buffering of the responses, which is disabled for the upstream %s", originalPath, upstream.Name)
+		}
+	}
+
+	if action.Proxy != nil && action.Proxy.ReadTimeout != "" {
+		loc.ProxyReadTimeout = action.Proxy.ReadTimeout
+	}
+
+	checkProxyMethodRequestBody(action, originalPath, errorPages.owner, vscWarnings)
+
+	return loc, nil
 }
 
 func generateProxySetHeaders(proxy *conf_v1.ActionProxy) []version2.Header {
 	var headers []version2.Header
 
 	hasHostHeader := false
+	hasContentLengthHeader := false
 
 	if proxy != nil && proxy.RequestHeaders != nil {
 		for _, h := range proxy.RequestHeaders.Set {
@@ -1938,8 +3339,11 @@ func generateProxySetHeaders(proxy *conf_v1.ActionProxy) []version2.Header {
 				Value: h.Value,
 			})
 
-			if strings.ToLower(h.Name) == "host" {
+			switch strings.ToLower(h.Name) {
+			case "host":
 				hasHostHeader = true
+			case "content-length":
+				hasContentLengthHeader = true
 			}
 		}
 	}
@@ -1948,9 +3352,40 @@ func generateProxySetHeaders(proxy *conf_v1.ActionProxy) []version2.Header {
 		headers = append(headers, version2.Header{Name: "Host", Value: "$host"})
 	}
 
+	// Without the body, the Content-Length header of the client request would not match the request to the upstream.
+	if generateProxyPassRequestBody(proxy) == "off" && !hasContentLengthHeader {
+		headers = append(headers, version2.Header{Name: "Content-Length", Value: ""})
+	}
+
 	return headers
 }
 
+func generateProxyMethod(proxy *conf_v1.ActionProxy) string {
+	if proxy == nil {
+		return ""
+	}
+	return proxy.ProxyMethod
+}
+
+func generateProxyPassRequestBody(proxy *conf_v1.ActionProxy) string {
+	if proxy == nil || proxy.ProxyPassRequestBody == nil || *proxy.ProxyPassRequestBody {
+		return ""
+	}
+	return "off"
+}
+
+// generateProxyIgnoreClientAbort returns the value of the proxy_ignore_client_abort directive for the location,
+// or an empty string to keep the value inherited from the http context.
+func generateProxyIgnoreClientAbort(ignoreClientAbort *bool) string {
+	if ignoreClientAbort == nil {
+		return ""
+	}
+	if *ignoreClientAbort {
+		return "on"
+	}
+	return "off"
+}
+
 func generateProxyPassRequestHeaders(proxy *conf_v1.ActionProxy) bool {
 	if proxy == nil || proxy.RequestHeaders == nil {
 		return true
@@ -1963,12 +3398,38 @@ func generateProxyPassRequestHeaders(proxy *conf_v1.ActionProxy) bool {
 	return true
 }
 
-func generateProxyHideHeaders(proxy *conf_v1.ActionProxy) []string {
+// generateProxyHideHeaders merges the hidden headers of the proxy action with the default hidden headers,
+// unless the proxy action opts out of them. The default headers passed by the proxy action are not hidden.
+func generateProxyHideHeaders(proxy *conf_v1.ActionProxy, defaultHideHeaders []string) []string {
 	if proxy == nil || proxy.ResponseHeaders == nil {
-		return nil
+		if len(defaultHideHeaders) == 0 {
+			return nil
+		}
+		return defaultHideHeaders
+	}
+
+	if !generateBool(proxy.ResponseHeaders.HideDefaults, true) || len(defaultHideHeaders) == 0 {
+		return proxy.ResponseHeaders.Hide
+	}
+
+	skip := make(map[string]bool)
+	for _, h := range proxy.ResponseHeaders.Hide {
+		skip[strings.ToLower(h)] = true
+	}
+	for _, h := range proxy.ResponseHeaders.Pass {
+		skip[strings.ToLower(h)] = true
+	}
+
+	hideHeaders := slices.Clone(proxy.ResponseHeaders.Hide)
+	for _, h := range defaultHideHeaders {
+		if skip[strings.ToLower(h)] {
+			continue
+		}
+		skip[strings.ToLower(h)] = true
+		hideHeaders = append(hideHeaders, h)
 	}
 
-	return proxy.ResponseHeaders.Hide
+	return hideHeaders
 }
 
 func generateProxyPassHeaders(proxy *conf_v1.ActionProxy) []string {
@@ -1992,8 +3453,20 @@ func generateProxyAddHeaders(proxy *conf_v1.ActionProxy) []version2.AddHeader {
 		return nil
 	}
 
+	return generateAddHeaders(proxy.ResponseHeaders.Add)
+}
+
+func generateServerAddHeaders(responseHeaders *conf_v1.ResponseHeaders) []version2.AddHeader {
+	if responseHeaders == nil {
+		return nil
+	}
+
+	return generateAddHeaders(responseHeaders.Add)
+}
+
+func generateAddHeaders(headers []conf_v1.AddHeader) []version2.AddHeader {
 	var addHeaders []version2.AddHeader
-	for _, h := range proxy.ResponseHeaders.Add {
+	for _, h := range headers {
 		addHeaders = append(addHeaders, version2.AddHeader{
 			Header: version2.Header{
 				Name:  h.Name,
@@ -2015,23 +3488,27 @@ func generateLocationForProxying(path string, upstreamName string, upstream conf
 		Internal:                 internal,
 		Snippets:                 locationSnippets,
 		ProxyConnectTimeout:      generateTimeWithDefault(upstream.ProxyConnectTimeout, cfgParams.ProxyConnectTimeout),
-		ProxyReadTimeout:         generateTimeWithDefault(upstream.ProxyReadTimeout, cfgParams.ProxyReadTimeout),
+		ProxyReadTimeout:         generateProxyReadTimeout(upstream, cfgParams),
 		ProxySendTimeout:         generateTimeWithDefault(upstream.ProxySendTimeout, cfgParams.ProxySendTimeout),
 		ClientMaxBodySize:        generateString(upstream.ClientMaxBodySize, cfgParams.ClientMaxBodySize),
 		ClientBodyBufferSize:     generateString(upstream.ClientBodyBufferSize, cfgParams.ClientBodyBufferSize),
-		ProxyMaxTempFileSize:     cfgParams.ProxyMaxTempFileSize,
+		ProxyMaxTempFileSize:     generateString(upstream.ProxyMaxTempFileSize, cfgParams.ProxyMaxTempFileSize),
 		ProxyBuffering:           generateBool(upstream.ProxyBuffering, cfgParams.ProxyBuffering),
+		ProxySocketKeepalive:     generateBool(upstream.ProxySocketKeepalive, false),
+		ProxyIgnoreClientAbort:   generateProxyIgnoreClientAbort(upstream.ProxyIgnoreClientAbort),
 		ProxyBuffers:             generateBuffers(upstream.ProxyBuffers, cfgParams.ProxyBuffers),
 		ProxyBufferSize:          generateString(upstream.ProxyBufferSize, cfgParams.ProxyBufferSize),
 		ProxyBusyBuffersSize:     generateString(upstream.ProxyBusyBuffersSize, cfgParams.ProxyBusyBuffersSize),
 		ProxyPass:                generateProxyPass(upstream.TLS.Enable, upstreamName, internal, proxy),
-		ProxyNextUpstream:        generateString(upstream.ProxyNextUpstream, "error timeout"),
-		ProxyNextUpstreamTimeout: generateTimeWithDefault(upstream.ProxyNextUpstreamTimeout, "0s"),
+		ProxyNextUpstream:        generateProxyNextUpstream(upstream),
+		ProxyNextUpstreamTimeout: generateProxyNextUpstreamTimeout(upstream),
 		ProxyNextUpstreamTries:   upstream.ProxyNextUpstreamTries,
 		ProxyInterceptErrors:     generateProxyInterceptErrors(errorPages),
 		ProxyPassRequestHeaders:  generateProxyPassRequestHeaders(proxy),
+		ProxyPassRequestBody:     generateProxyPassRequestBody(proxy),
+		ProxyMethod:              generateProxyMethod(proxy),
 		ProxySetHeaders:          generateProxySetHeaders(proxy),
-		ProxyHideHeaders:         generateProxyHideHeaders(proxy),
+		ProxyHideHeaders:         generateProxyHideHeaders(proxy, cfgParams.DefaultProxyHideHeaders),
 		ProxyPassHeaders:         generateProxyPassHeaders(proxy),
 		ProxyIgnoreHeaders:       generateProxyIgnoreHeaders(proxy),
 		AddHeaders:               generateProxyAddHeaders(proxy),
@@ -2040,6 +3517,7 @@ func generateLocationForProxying(path string, upstreamName string, upstream conf
 		HasKeepalive:             upstreamHasKeepalive(upstream, cfgParams),
 		ErrorPages:               generateErrorPages(errPageIndex, errorPages),
 		ProxySSLName:             proxySSLName,
+		ProxySSLSessionReuse:     generateProxySSLSessionReuse(upstream.TLS),
 		ServiceName:              serviceName,
 		IsVSR:                    isVSR,
 		VSRName:                  vsrName,
@@ -2048,8 +3526,92 @@ func generateLocationForProxying(path string, upstreamName string, upstream conf
 	}
 }
 
+// generateProxySSLSessionReuse returns the value of the ssl_session_reuse directive for an upstream with TLS enabled,
+// or an empty string to keep the default of NGINX, which reuses the sessions.
+func generateProxySSLSessionReuse(tls conf_v1.UpstreamTLS) string {
+	if !tls.Enable || tls.SessionReuse == nil {
+		return ""
+	}
+	if *tls.SessionReuse {
+		return "on"
+	}
+	return "off"
+}
+
+// generateProxyNextUpstream returns the value of the next_upstream directive. The other cases are dropped when passing
+// requests to the next upstream server is turned off, as NGINX ignores them.
+func generateProxyNextUpstream(upstream conf_v1.Upstream) string {
+	if isNextUpstreamOff(upstream) {
+		return "off"
+	}
+	return generateString(upstream.ProxyNextUpstream, "error timeout")
+}
+
+// generateProxyNextUpstreamTimeout returns the value of the next_upstream_timeout directive, or an empty string
+// to omit the next_upstream_timeout and next_upstream_tries directives when passing requests to the next upstream
+// server is turned off.
+func generateProxyNextUpstreamTimeout(upstream conf_v1.Upstream) string {
+	if isNextUpstreamOff(upstream) {
+		return ""
+	}
+	return generateTimeWithDefault(upstream.ProxyNextUpstreamTimeout, "0s")
+}
+
+// generateFailoverTries returns the number of tries for passing a request to the next server of an upstream with
+// failover, so that the request is tried on the primary servers first and then on every backup server.
+// The backup servers are the hostnames of the ExternalName backup service, so a hostname resolving to several
+// addresses counts as one server and gets one try.
+// As the tries depend on the number of servers, NGINX Plus is reloaded when it changes instead of updating the servers via the API.
+func generateFailoverTries(failover *conf_v1.UpstreamFailover, primaryServers int, backupServers int) int {
+	return generateIntFromPointer(failover.PrimaryTries, primaryServers) + backupServers
+}
+
+// isNextUpstreamUnlimited checks if passing requests to the next upstream server is explicitly configured for the
+// upstream without limiting the time or the number of tries.
+func isNextUpstreamUnlimited(upstream conf_v1.Upstream) bool {
+	if upstream.ProxyNextUpstream == "" || isNextUpstreamOff(upstream) || upstream.ProxyNextUpstreamTries != 0 || upstream.Failover != nil {
+		return false
+	}
+	if upstream.ProxyNextUpstreamTimeout == "" {
+		return true
+	}
+	timeout, err := ParseTimeDuration(upstream.ProxyNextUpstreamTimeout)
+	return err == nil && timeout == 0
+}
+
+// isNextUpstreamOff checks if passing requests to the next upstream server is turned off for the upstream.
+func isNextUpstreamOff(upstream conf_v1.Upstream) bool {
+	return slices.Contains(strings.Fields(upstream.ProxyNextUpstream), "off")
+}
+
+// isGRPCNextUpstreamLimitedToUnsentRequests checks if passing requests to the next upstream server is configured for
+// a gRPC upstream without non_idempotent. NGINX does not pass the requests with non-idempotent methods, which include
+// the POST method of all gRPC requests, to the next server once they have been sent to an upstream server.
+func isGRPCNextUpstreamLimitedToUnsentRequests(upstream conf_v1.Upstream) bool {
+	if !isGRPC(upstream.Type) || upstream.ProxyNextUpstream == "" {
+		return false
+	}
+	params := strings.Fields(upstream.ProxyNextUpstream)
+	return !slices.Contains(params, "off") && !slices.Contains(params, "non_idempotent")
+}
+
+// generateProxyReadTimeout returns the read timeout of the locations of the upstream. For WebSocket upstreams,
+// the read timeout is increased so that idle WebSocket connections are not closed.
+func generateProxyReadTimeout(upstream conf_v1.Upstream, cfgParams *ConfigParams) string {
+	if upstream.WebSocket == nil || !upstream.WebSocket.Enable || isGRPC(upstream.Type) {
+		return generateTimeWithDefault(upstream.ProxyReadTimeout, cfgParams.ProxyReadTimeout)
+	}
+
+	return generateTime(generateString(upstream.WebSocket.ReadTimeout, defaultWebSocketReadTimeout))
+}
+
 func generateProxyInterceptErrors(errorPages []conf_v1.ErrorPage) bool {
-	return len(errorPages) > 0
+	for _, e := range errorPages {
+		if len(e.Codes) > 0 {
+			return true
+		}
+	}
+	return false
 }
 
 func generateLocationForRedirect(
@@ -2077,8 +3639,34 @@ func generateLocationForRedirect(
 	}
 }
 
+// generateLocationForFiles generates a location that serves the static files of the files action.
+// When none of the files exists, the fallback URI or status code is used.
+func generateLocationForFiles(path string, locationSnippets []string, files *conf_v1.ActionFiles) version2.Location {
+	tryFiles := slices.Clone(files.TryFiles)
+	if len(tryFiles) == 0 {
+		tryFiles = []string{"$uri"}
+	}
+
+	fallback := files.Fallback
+	if fallback == "" {
+		fallback = "=404"
+	}
+
+	return version2.Location{
+		Path:     generatePath(path),
+		Snippets: locationSnippets,
+		Root:     files.Root,
+		TryFiles: append(tryFiles, fallback),
+	}
+}
+
+// isBodylessStatusCode checks if the responses with the status code have no body.
+func isBodylessStatusCode(code int) bool {
+	return code == 204 || code == 304
+}
+
 func generateLocationForReturn(path string, locationSnippets []string, actionReturn *conf_v1.ActionReturn,
-	retLocIndex int,
+	retLocIndex int, variableNamer *VariableNamer, owner runtime.Object, vscWarnings Warnings,
 ) (version2.Location, *version2.ReturnLocation) {
 	defaultType := actionReturn.Type
 	if defaultType == "" {
@@ -2089,6 +3677,14 @@ func generateLocationForReturn(path string, locationSnippets []string, actionRet
 		code = 200
 	}
 
+	bodyless := isBodylessStatusCode(code)
+	if bodyless {
+		if actionReturn.Body != "" || len(actionReturn.Bodies) > 0 {
+			vscWarnings.AddWarningf(owner, "The body of the return of the action for the path %s is ignored, as the responses with the status code %d have no body", path, code)
+		}
+		defaultType = ""
+	}
+
 	var headers []version2.Header
 
 	for _, h := range actionReturn.Headers {
@@ -2100,27 +3696,114 @@ func generateLocationForReturn(path string, locationSnippets []string, actionRet
 
 	retLocName := fmt.Sprintf("@return_%d", retLocIndex)
 
+	returnLoc := &version2.ReturnLocation{
+		Name:        retLocName,
+		DefaultType: defaultType,
+		Headers:     headers,
+	}
+	if !bodyless {
+		returnLoc.Return.Text = actionReturn.Body
+	}
+
+	errorPageName := retLocName
+
+	if len(actionReturn.Bodies) > 0 && !bodyless {
+		returnLoc, errorPageName = generateReturnLocationForBodies(retLocName, actionReturn.Bodies, headers, retLocIndex, variableNamer)
+	}
+
 	return version2.Location{
-			Path:                 generatePath(path),
-			Snippets:             locationSnippets,
-			ProxyInterceptErrors: true,
-			InternalProxyPass:    fmt.Sprintf("http://%s", nginx418Server),
-			ErrorPages: []version2.ErrorPage{
+		Path:                 generatePath(path),
+		Snippets:             locationSnippets,
+		ProxyInterceptErrors: true,
+		InternalProxyPass:    fmt.Sprintf("http://%s", nginx418Server),
+		ErrorPages: []version2.ErrorPage{
+			{
+				Name:         errorPageName,
+				Codes:        "418",
+				ResponseCode: code,
+			},
+		},
+	}, returnLoc
+}
+
+// flattenReturnLocations returns the return locations together with their alternatives and the maps that select
+// between them.
+func flattenReturnLocations(returnLocations []version2.ReturnLocation) ([]version2.ReturnLocation, []version2.Map) {
+	var flattened []version2.ReturnLocation
+	var maps []version2.Map
+
+	for _, rl := range returnLocations {
+		alternatives := rl.Alternatives
+		acceptMap := rl.AcceptMap
+		rl.Alternatives = nil
+		rl.AcceptMap = nil
+
+		flattened = append(flattened, rl)
+		flattened = append(flattened, alternatives...)
+		if acceptMap != nil {
+			maps = append(maps, *acceptMap)
+		}
+	}
+
+	return flattened, maps
+}
+
+// acceptTypeRefusedFmt is a negative lookahead that rejects a media type of the Accept header with q=0,
+// which the client explicitly refuses, including when other parameters precede the q-value.
+const acceptTypeRefusedFmt = `(?![^,]*;\s*q=0(?:\.0*)?(?![.0-9]))`
+
+// generateAcceptTypeRegex generates the case-insensitive map regex that matches a media type in the Accept header.
+func generateAcceptTypeRegex(mediaType string) string {
+	return fmt.Sprintf(`"~*%s%s"`, regexp.QuoteMeta(mediaType), acceptTypeRefusedFmt)
+}
+
+// generateReturnLocationForBodies returns a return location for each of the bodies and the map variable that selects
+// one of them by the Accept header of the request. The first body is returned if none of the types match.
+// NGINX checks the regular expressions of a map in order, so the first matching body wins regardless of the q-values.
+func generateReturnLocationForBodies(retLocName string, bodies []conf_v1.ReturnBody, headers []version2.Header,
+	retLocIndex int, variableNamer *VariableNamer,
+) (*version2.ReturnLocation, string) {
+	variable := variableNamer.GetNameForReturnLocationVariable(retLocIndex)
+
+	returnLoc := &version2.ReturnLocation{
+		Name:        retLocName,
+		DefaultType: bodies[0].Type,
+		Return: version2.Return{
+			Text: bodies[0].Body,
+		},
+		Headers: headers,
+		AcceptMap: &version2.Map{
+			Source:   "$http_accept",
+			Variable: variable,
+			Parameters: []version2.Parameter{
 				{
-					Name:         retLocName,
-					Codes:        "418",
-					ResponseCode: code,
+					Value:  "default",
+					Result: retLocName,
 				},
 			},
 		},
-		&version2.ReturnLocation{
-			Name:        retLocName,
-			DefaultType: defaultType,
-			Return: version2.Return{
-				Text: actionReturn.Body,
-			},
-			Headers: headers,
+	}
+
+	for i, b := range bodies {
+		name := retLocName
+		if i > 0 {
+			name = fmt.Sprintf("%s_%d", retLocName, i)
+			returnLoc.Alternatives = append(returnLoc.Alternatives, version2.ReturnLocation{
+				Name:        name,
+				DefaultType: b.Type,
+				Return: version2.Return{
+					Text: b.Body,
+				},
+				Headers: headers,
+			})
 		}
+		returnLoc.AcceptMap.Parameters = append(returnLoc.AcceptMap.Parameters, version2.Parameter{
+			Value:  generateAcceptTypeRegex(b.Type),
+			Result: name,
+		})
+	}
+
+	return returnLoc, variable
 }
 
 type routingCfg struct {
@@ -2136,6 +3819,9 @@ type routingCfg struct {
 
 func generateSplits(
 	splits []conf_v1.Split,
+	conditions []conf_v1.Condition,
+	stickyCookie *conf_v1.SplitStickyCookie,
+	splitWeights *conf_v1.SplitWeights,
 	upstreamNamer *upstreamNamer,
 	crUpstreams map[string]conf_v1.Upstream,
 	VariableNamer *VariableNamer,
@@ -2159,6 +3845,16 @@ func generateSplits(
 	var keyVals []version2.KeyVal
 	var twoWaySplitClients []version2.TwoWaySplitClients
 
+	splits = PromoteSplit(splits)
+
+	source := "$request_id"
+	var stickyHeaders []version2.AddHeader
+	if stickyCookie != nil {
+		var stickyMaps []version2.Map
+		source, stickyMaps, stickyHeaders = generateSplitStickyCookie(stickyCookie, scIndex, VariableNamer)
+		maps = append(maps, stickyMaps...)
+	}
+
 	for i, s := range splits {
 		if s.Weight == 0 {
 			continue
@@ -2170,25 +3866,36 @@ func generateSplits(
 		distributions = append(distributions, d)
 	}
 
-	if WeightChangesDynamicReload && len(splits) == 2 {
-		scs, weightMap := generateSplitsForWeightChangesDynamicReload(splits, scIndex, VariableNamer)
-		kvZoneName := VariableNamer.GetNameOfKeyvalZoneForSplitClientIndex(scIndex)
+	if splitWeights != nil && len(splits) == 2 {
+		scs, weightMap := generateSplitsForSharedWeights(splits, source, scIndex, VariableNamer)
+		kv := version2.KeyVal{
+			Key:      fmt.Sprintf("%q", splitWeights.Key),
+			Variable: VariableNamer.GetNameOfKeyvalForSplitWeights(scIndex),
+			ZoneName: splitWeightsKeyValZoneName,
+		}
+		splitClients = append(splitClients, scs...)
+		maps = append(maps, weightMap)
+		keyVals = append(keyVals, kv)
+	} else if WeightChangesDynamicReload && len(splits) == 2 {
+		splitClientsID := GetSplitClientsID(originalPath, conditions, splits)
+		scs, weightMap := generateSplitsForWeightChangesDynamicReload(splits, source, scIndex, splitClientsID, VariableNamer)
+		kvZoneName := VariableNamer.GetNameOfKeyvalZoneForSplitClients(splitClientsID)
 		kvz := version2.KeyValZone{
 			Name:  kvZoneName,
 			Size:  splitClientsKeyValZoneSize,
 			State: fmt.Sprintf("%s/%s.json", keyvalZoneBasePath, kvZoneName),
 		}
 		kv := version2.KeyVal{
-			Key:      VariableNamer.GetNameOfKeyvalKeyForSplitClientIndex(scIndex),
+			Key:      VariableNamer.GetNameOfKeyvalKeyForSplitClients(splitClientsID),
 			Variable: VariableNamer.GetNameOfKeyvalForSplitClientIndex(scIndex),
 			ZoneName: kvZoneName,
 		}
 		scWithWeights := version2.TwoWaySplitClients{
-			Key:               VariableNamer.GetNameOfKeyvalKeyForSplitClientIndex(scIndex),
-			Variable:          VariableNamer.GetNameOfKeyvalForSplitClientIndex(scIndex),
-			ZoneName:          kvZoneName,
-			Weights:           []int{splits[0].Weight, splits[1].Weight},
-			SplitClientsIndex: scIndex,
+			Key:            VariableNamer.GetNameOfKeyvalKeyForSplitClients(splitClientsID),
+			Variable:       VariableNamer.GetNameOfKeyvalForSplitClientIndex(scIndex),
+			ZoneName:       kvZoneName,
+			Weights:        []int{splits[0].Weight, splits[1].Weight},
+			SplitClientsID: splitClientsID,
 		}
 		splitClients = append(splitClients, scs...)
 		maps = append(maps, weightMap)
@@ -2197,7 +3904,7 @@ func generateSplits(
 		twoWaySplitClients = append(twoWaySplitClients, scWithWeights)
 	} else {
 		splitClient := version2.SplitClient{
-			Source:        "$request_id",
+			Source:        source,
 			Variable:      VariableNamer.GetNameForSplitClientVariable(scIndex),
 			Distributions: distributions,
 		}
@@ -2215,7 +3922,8 @@ func generateSplits(
 		proxySSLName := generateProxySSLName(serviceName, serviceNamespace)
 		newRetLocIndex := retLocIndex + len(returnLocations)
 		loc, returnLoc := generateLocation(path, upstreamName, upstream, s.Action, cfgParams, errorPages, true,
-			proxySSLName, originalPath, locSnippets, enableSnippets, newRetLocIndex, isVSR, vsrName, vsrNamespace, vscWarnings)
+			proxySSLName, originalPath, locSnippets, enableSnippets, newRetLocIndex, VariableNamer, isVSR, vsrName, vsrNamespace, vscWarnings)
+		loc.AddHeaders = append(loc.AddHeaders, stickyHeaders...)
 		locations = append(locations, loc)
 		if returnLoc != nil {
 			returnLocations = append(returnLocations, *returnLoc)
@@ -2225,6 +3933,56 @@ func generateSplits(
 	return splitClients, locations, returnLocations, maps, keyValZones, keyVals, twoWaySplitClients
 }
 
+// generateSplitStickyCookie returns the source for the split clients, the maps and the headers
+// that key the split clients on the sticky cookie and set the cookie when it is missing from the request.
+// A client without the cookie, including one whose cookie has expired, is assigned to a split by $request_id.
+func generateSplitStickyCookie(cookie *conf_v1.SplitStickyCookie, scIndex int, VariableNamer *VariableNamer) (string, []version2.Map, []version2.AddHeader) {
+	cookieVariable := fmt.Sprintf("$cookie_%s", cookie.Name)
+	keyVariable := VariableNamer.GetNameForSplitClientStickyKeyVariable(scIndex)
+	setCookieVariable := VariableNamer.GetNameForSplitClientSetCookieVariable(scIndex)
+
+	path := cookie.Path
+	if path == "" {
+		path = "/"
+	}
+
+	setCookie := fmt.Sprintf("%s=$request_id; Path=%s", cookie.Name, path)
+	if cookie.TTL != nil {
+		setCookie = fmt.Sprintf("%s; Max-Age=%d", setCookie, *cookie.TTL)
+	}
+
+	maps := []version2.Map{
+		{
+			Source:   cookieVariable,
+			Variable: keyVariable,
+			Parameters: []version2.Parameter{
+				{Value: `""`, Result: "$request_id"},
+				{Value: "default", Result: cookieVariable},
+			},
+		},
+		{
+			Source:   cookieVariable,
+			Variable: setCookieVariable,
+			Parameters: []version2.Parameter{
+				{Value: `""`, Result: fmt.Sprintf(`"%s"`, setCookie)},
+				{Value: "default", Result: `""`},
+			},
+		},
+	}
+
+	headers := []version2.AddHeader{
+		{
+			Header: version2.Header{
+				Name:  "Set-Cookie",
+				Value: setCookieVariable,
+			},
+			Always: true,
+		},
+	}
+
+	return keyVariable, maps, headers
+}
+
 func generateDefaultSplitsConfig(
 	route conf_v1.Route,
 	upstreamNamer *upstreamNamer,
@@ -2243,10 +4001,10 @@ func generateDefaultSplitsConfig(
 	vscWarnings Warnings,
 	weightChangesDynamicReload bool,
 ) routingCfg {
-	scs, locs, returnLocs, maps, keyValZones, keyVals, twoWaySplitClients := generateSplits(route.Splits, upstreamNamer, crUpstreams, VariableNamer, scIndex, cfgParams, errorPages, originalPath, locSnippets, enableSnippets, retLocIndex, isVSR, vsrName, vsrNamespace, vscWarnings, weightChangesDynamicReload)
+	scs, locs, returnLocs, maps, keyValZones, keyVals, twoWaySplitClients := generateSplits(route.Splits, nil, route.StickyCookie, route.SplitWeights, upstreamNamer, crUpstreams, VariableNamer, scIndex, cfgParams, errorPages, originalPath, locSnippets, enableSnippets, retLocIndex, isVSR, vsrName, vsrNamespace, vscWarnings, weightChangesDynamicReload)
 
 	var irl version2.InternalRedirectLocation
-	if weightChangesDynamicReload && len(route.Splits) == 2 {
+	if usesSplitClientsMap(route.Splits, route.SplitWeights, weightChangesDynamicReload) {
 		irl = version2.InternalRedirectLocation{
 			Path:        route.Path,
 			Destination: VariableNamer.GetNameOfMapForSplitClientIndex(scIndex),
@@ -2270,9 +4028,10 @@ func generateDefaultSplitsConfig(
 	}
 }
 
-func generateSplitsForWeightChangesDynamicReload(splits []conf_v1.Split, scIndex int, VariableNamer *VariableNamer) ([]version2.SplitClient, version2.Map) {
+// generateSplitClientsForAllWeights returns the split clients of a two-way split for every combination of the weights,
+// so that a map can select the split clients for the current weights at request time.
+func generateSplitClientsForAllWeights(source string, scIndex int, VariableNamer *VariableNamer) []version2.SplitClient {
 	var splitClients []version2.SplitClient
-	var mapParameters []version2.Parameter
 	for i := 0; i <= 100; i++ {
 		j := 100 - i
 		var split version2.SplitClient
@@ -2293,16 +4052,24 @@ func generateSplitsForWeightChangesDynamicReload(splits []conf_v1.Split, scIndex
 			distributions = append(distributions, distribution)
 		}
 		split = version2.SplitClient{
-			Source:        "$request_id",
+			Source:        source,
 			Variable:      VariableNamer.GetNameOfSplitClientsForWeights(scIndex, i, j),
 			Distributions: distributions,
 		}
 		splitClients = append(splitClients, split)
+	}
+	return splitClients
+}
+
+func generateSplitsForWeightChangesDynamicReload(splits []conf_v1.Split, source string, scIndex int, splitClientsID string, VariableNamer *VariableNamer) ([]version2.SplitClient, version2.Map) {
+	splitClients := generateSplitClientsForAllWeights(source, scIndex, VariableNamer)
+	var mapParameters []version2.Parameter
+	for i := 0; i <= 100; i++ {
+		j := 100 - i
 		mapParameters = append(mapParameters, version2.Parameter{
-			Value:  VariableNamer.GetNameOfKeyOfMapForWeights(scIndex, i, j),
+			Value:  VariableNamer.GetNameOfKeyOfMapForWeights(splitClientsID, i, j),
 			Result: VariableNamer.GetNameOfSplitClientsForWeights(scIndex, i, j),
 		})
-
 	}
 
 	var mapDefault version2.Parameter
@@ -2325,6 +4092,56 @@ func generateSplitsForWeightChangesDynamicReload(splits []conf_v1.Split, scIndex
 	return splitClients, weightsToSplits
 }
 
+// generateSplitsForSharedWeights returns the split clients of a two-way split for every combination of the weights
+// and the map that selects the split clients by the weight of the first split read from the shared keyval.
+// The weights of the splits are used while the keyval is not set or has an invalid value.
+func generateSplitsForSharedWeights(splits []conf_v1.Split, source string, scIndex int, VariableNamer *VariableNamer) ([]version2.SplitClient, version2.Map) {
+	splitClients := generateSplitClientsForAllWeights(source, scIndex, VariableNamer)
+	var mapParameters []version2.Parameter
+	for i := 0; i <= 100; i++ {
+		mapParameters = append(mapParameters, version2.Parameter{
+			Value:  strconv.Itoa(i),
+			Result: VariableNamer.GetNameOfSplitClientsForWeights(scIndex, i, 100-i),
+		})
+	}
+	mapParameters = append(mapParameters, version2.Parameter{
+		Value:  "default",
+		Result: VariableNamer.GetNameOfSplitClientsForWeights(scIndex, splits[0].Weight, splits[1].Weight),
+	})
+
+	weightsToSplits := version2.Map{
+		Source:     VariableNamer.GetNameOfKeyvalForSplitWeights(scIndex),
+		Variable:   VariableNamer.GetNameOfMapForSplitClientIndex(scIndex),
+		Parameters: mapParameters,
+	}
+
+	return splitClients, weightsToSplits
+}
+
+// PromoteSplit returns a copy of the splits that sends all requests to the promoted split, if there is one.
+// Only the weights are changed, so that the split clients ID of the splits, and with it the keyval zone of
+// a two-way split, stays the same and the promotion is applied without a reload.
+func PromoteSplit(splits []conf_v1.Split) []conf_v1.Split {
+	idx := slices.IndexFunc(splits, func(s conf_v1.Split) bool { return s.Promoted })
+	if idx == -1 {
+		return splits
+	}
+
+	promoted := slices.Clone(splits)
+	for i := range promoted {
+		promoted[i].Weight = 0
+	}
+	promoted[idx].Weight = 100
+
+	return promoted
+}
+
+// usesSplitClientsMap checks whether the split clients of the splits are selected by a map of their weights
+// at request time rather than used directly.
+func usesSplitClientsMap(splits []conf_v1.Split, splitWeights *conf_v1.SplitWeights, weightChangesDynamicReload bool) bool {
+	return len(splits) == 2 && (splitWeights != nil || weightChangesDynamicReload)
+}
+
 func generateMatchesConfig(route conf_v1.Route, upstreamNamer *upstreamNamer, crUpstreams map[string]conf_v1.Upstream,
 	VariableNamer *VariableNamer, index int, scIndex int, cfgParams *ConfigParams, errorPages errorPageDetails,
 	locSnippets string, enableSnippets bool, retLocIndex int, isVSR bool, vsrName string, vsrNamespace string, vscWarnings Warnings, weightChangesDynamicReload bool,
@@ -2335,7 +4152,7 @@ func generateMatchesConfig(route conf_v1.Route, upstreamNamer *upstreamNamer, cr
 
 	for i, m := range route.Matches {
 		for j, c := range m.Conditions {
-			source := getNameForSourceForMatchesRouteMapFromCondition(c)
+			source := getNameForSourceForMatchesRouteMapFromCondition(c, VariableNamer)
 			variable := VariableNamer.GetNameForVariableForMatchesRouteMap(index, i, j)
 			successfulResult := "1"
 			if j < len(m.Conditions)-1 {
@@ -2382,7 +4199,7 @@ func generateMatchesConfig(route conf_v1.Route, upstreamNamer *upstreamNamer, cr
 
 	defaultResult := fmt.Sprintf("/%vmatches_%d_default", internalLocationPrefix, index)
 	if len(route.Splits) > 0 {
-		if weightChangesDynamicReload && len(route.Splits) == 2 {
+		if usesSplitClientsMap(route.Splits, route.SplitWeights, weightChangesDynamicReload) {
 			defaultResult = VariableNamer.GetNameOfMapForSplitClientIndex(scIndex + scLocalIndex)
 		} else {
 			defaultResult = VariableNamer.GetNameForSplitClientVariable(scIndex + scLocalIndex)
@@ -2417,6 +4234,9 @@ func generateMatchesConfig(route conf_v1.Route, upstreamNamer *upstreamNamer, cr
 			newRetLocIndex := retLocIndex + len(returnLocations)
 			scs, locs, returnLocs, mps, kvzs, kvs, twscs := generateSplits(
 				m.Splits,
+				m.Conditions,
+				route.StickyCookie,
+				nil,
 				upstreamNamer,
 				crUpstreams,
 				VariableNamer,
@@ -2449,7 +4269,7 @@ func generateMatchesConfig(route conf_v1.Route, upstreamNamer *upstreamNamer, cr
 			proxySSLName := generateProxySSLName(serviceName, serviceNamespace)
 			newRetLocIndex := retLocIndex + len(returnLocations)
 			loc, returnLoc := generateLocation(path, upstreamName, upstream, m.Action, cfgParams, errorPages, true,
-				proxySSLName, route.Path, locSnippets, enableSnippets, newRetLocIndex, isVSR, vsrName, vsrNamespace, vscWarnings)
+				proxySSLName, route.Path, locSnippets, enableSnippets, newRetLocIndex, VariableNamer, isVSR, vsrName, vsrNamespace, vscWarnings)
 			locations = append(locations, loc)
 			if returnLoc != nil {
 				returnLocations = append(returnLocations, *returnLoc)
@@ -2462,6 +4282,9 @@ func generateMatchesConfig(route conf_v1.Route, upstreamNamer *upstreamNamer, cr
 		newRetLocIndex := retLocIndex + len(returnLocations)
 		scs, locs, returnLocs, mps, kvzs, kvs, twscs := generateSplits(
 			route.Splits,
+			nil,
+			route.StickyCookie,
+			route.SplitWeights,
 			upstreamNamer,
 			crUpstreams,
 			VariableNamer,
@@ -2493,7 +4316,7 @@ func generateMatchesConfig(route conf_v1.Route, upstreamNamer *upstreamNamer, cr
 		proxySSLName := generateProxySSLName(serviceName, serviceNamespace)
 		newRetLocIndex := retLocIndex + len(returnLocations)
 		loc, returnLoc := generateLocation(path, upstreamName, upstream, route.Action, cfgParams, errorPages, true,
-			proxySSLName, route.Path, locSnippets, enableSnippets, newRetLocIndex, isVSR, vsrName, vsrNamespace, vscWarnings)
+			proxySSLName, route.Path, locSnippets, enableSnippets, newRetLocIndex, VariableNamer, isVSR, vsrName, vsrNamespace, vscWarnings)
 		locations = append(locations, loc)
 		if returnLoc != nil {
 			returnLocations = append(returnLocations, *returnLoc)
@@ -2542,6 +4365,179 @@ func generateValueForMatchesRouteMap(matchedValue string) (value string, isNegat
 	return fmt.Sprintf(`"%s"`, matchedValue), isNegative
 }
 
+// generateTarpit generates the maps that match the conditions of the tarpit, the tarpit of the locations of the route
+// and the location that returns the delayed response for the matching requests.
+func generateTarpit(tarpit *conf_v1.Tarpit, index int, variableNamer *VariableNamer) ([]version2.Map, *version2.Tarpit, *version2.TarpitLocation) {
+	if tarpit == nil || len(tarpit.Conditions) == 0 {
+		return nil, nil, nil
+	}
+
+	delay, err := ParseTimeDuration(tarpit.Delay)
+	if err != nil {
+		return nil, nil, nil
+	}
+
+	var maps []version2.Map
+	for j, c := range tarpit.Conditions {
+		successfulResult := "1"
+		if j < len(tarpit.Conditions)-1 {
+			successfulResult = variableNamer.GetNameForVariableForTarpitMap(index, j+1)
+		}
+
+		maps = append(maps, version2.Map{
+			Source:     getNameForSourceForMatchesRouteMapFromCondition(c, variableNamer),
+			Variable:   variableNamer.GetNameForVariableForTarpitMap(index, j),
+			Parameters: generateParametersForMatchesRouteMap(c.Value, successfulResult),
+		})
+	}
+
+	code := defaultTarpitCode
+	if tarpit.Code != 0 {
+		code = tarpit.Code
+	}
+
+	path := fmt.Sprintf("/%vtarpit_%d", internalLocationPrefix, index)
+
+	return maps,
+		&version2.Tarpit{
+			Variable: variableNamer.GetNameForVariableForTarpitMap(index, 0),
+			Location: path,
+		},
+		&version2.TarpitLocation{
+			Path:  path,
+			Delay: delay.Milliseconds(),
+			Code:  code,
+		}
+}
+
+// generateGeos generates the geos of the VirtualServer that select the value of their variables by the client IP
+// address of the requests.
+func generateGeos(geos []conf_v1.Geo, variableNamer *VariableNamer) []version2.Geo {
+	var result []version2.Geo
+
+	for _, g := range geos {
+		defaultValue := g.Default
+		if defaultValue == "" {
+			defaultValue = `""`
+		}
+		params := []version2.Parameter{
+			{
+				Value:  "default",
+				Result: defaultValue,
+			},
+		}
+		for _, r := range g.Ranges {
+			params = append(params, version2.Parameter{
+				Value:  r.CIDR,
+				Result: r.Value,
+			})
+		}
+
+		result = append(result, version2.Geo{
+			Source:     "$remote_addr",
+			Variable:   variableNamer.GetNameForGeoVariable(g.Name),
+			Parameters: params,
+		})
+	}
+
+	return result
+}
+
+// grpcStatusCodes are the codes of the gRPC statuses by their names.
+var grpcStatusCodes = map[string]int{
+	"CANCELLED":           1,
+	"UNKNOWN":             2,
+	"INVALID_ARGUMENT":    3,
+	"DEADLINE_EXCEEDED":   4,
+	"NOT_FOUND":           5,
+	"ALREADY_EXISTS":      6,
+	"PERMISSION_DENIED":   7,
+	"RESOURCE_EXHAUSTED":  8,
+	"FAILED_PRECONDITION": 9,
+	"ABORTED":             10,
+	"OUT_OF_RANGE":        11,
+	"UNIMPLEMENTED":       12,
+	"INTERNAL":            13,
+	"UNAVAILABLE":         14,
+	"DATA_LOSS":           15,
+	"UNAUTHENTICATED":     16,
+}
+
+// generateGRPCErrorLocations generates the locations of the custom responses for the gRPC statuses of a route.
+// It also returns the names of the locations by the gRPC statuses in lowercase, which replace the default locations
+// of the gRPC statuses, like @grpc_unavailable, in the error_page directives of the locations of the route.
+func generateGRPCErrorLocations(responses []conf_v1.GRPCErrorResponse, index int) ([]version2.GRPCErrorLocation, map[string]string) {
+	if len(responses) == 0 {
+		return nil, nil
+	}
+
+	var locations []version2.GRPCErrorLocation
+	grpcErrorPages := make(map[string]string)
+	for i, r := range responses {
+		status := r.Status
+		if r.Return.Status != "" {
+			status = r.Return.Status
+		}
+		message := r.Return.Message
+		if message == "" {
+			message = strings.ToLower(strings.ReplaceAll(status, "_", " "))
+		}
+		var headers []version2.Header
+		for _, h := range r.Return.Headers {
+			headers = append(headers, version2.Header{Name: h.Name, Value: h.Value})
+		}
+
+		name := fmt.Sprintf("@grpc_error_%d", index+i)
+		locations = append(locations, version2.GRPCErrorLocation{
+			Name:    name,
+			Status:  grpcStatusCodes[status],
+			Message: message,
+			Headers: headers,
+		})
+		grpcErrorPages[strings.ToLower(r.Status)] = name
+	}
+
+	return locations, grpcErrorPages
+}
+
+// generateClientIPReturn generates the geo that matches the client IP address of the requests against the ranges
+// of the client IP return and the client IP return of the locations of the route.
+func generateClientIPReturn(clientIPReturn *conf_v1.ClientIPReturn, index int, variableNamer *VariableNamer) (*version2.Geo, *version2.ClientIPReturn) {
+	if clientIPReturn == nil || len(clientIPReturn.Ranges) == 0 {
+		return nil, nil
+	}
+
+	params := []version2.Parameter{
+		{
+			Value:  "default",
+			Result: "0",
+		},
+	}
+	for _, r := range clientIPReturn.Ranges {
+		params = append(params, version2.Parameter{
+			Value:  r,
+			Result: "1",
+		})
+	}
+
+	code := defaultClientIPReturnCode
+	if clientIPReturn.Code != 0 {
+		code = clientIPReturn.Code
+	}
+
+	variable := variableNamer.GetNameForVariableForClientIPReturnGeo(index)
+
+	return &version2.Geo{
+			Source:     "$remote_addr",
+			Variable:   variable,
+			Parameters: params,
+		},
+		&version2.ClientIPReturn{
+			Variable: variable,
+			Code:     code,
+		}
+}
+
 func generateParametersForMatchesRouteMap(matchedValue string, successfulResult string) []version2.Parameter {
 	value, isNegative := generateValueForMatchesRouteMap(matchedValue)
 
@@ -2566,7 +4562,11 @@ func generateParametersForMatchesRouteMap(matchedValue string, successfulResult
 	return params
 }
 
-func getNameForSourceForMatchesRouteMapFromCondition(condition conf_v1.Condition) string {
+func getNameForSourceForMatchesRouteMapFromCondition(condition conf_v1.Condition, variableNamer *VariableNamer) string {
+	if condition.Geo != "" {
+		return variableNamer.GetNameForGeoVariable(condition.Geo)
+	}
+
 	if condition.Header != "" {
 		return fmt.Sprintf("$http_%s", strings.ReplaceAll(condition.Header, "-", "_"))
 	}
@@ -2589,6 +4589,8 @@ func (vsc *virtualServerConfigurator) generateSSLConfig(owner runtime.Object, tl
 		return nil
 	}
 
+	protocols, ciphers := generateTLSProfile(tls.Profile)
+
 	if tls.Secret == "" {
 		if vsc.isWildcardEnabled {
 			ssl := version2.SSL{
@@ -2596,9 +4598,14 @@ func (vsc *virtualServerConfigurator) generateSSLConfig(owner runtime.Object, tl
 				Certificate:     pemFileNameForWildcardTLSSecret,
 				CertificateKey:  pemFileNameForWildcardTLSSecret,
 				RejectHandshake: false,
+				Protocols:       protocols,
+				Ciphers:         ciphers,
 			}
 			return &ssl
 		}
+		if tls.Profile != nil {
+			vsc.addWarningf(owner, "TLS profile is ignored, because the TLS secret is not specified and the wildcard TLS secret is not configured")
+		}
 		return nil
 	}
 
@@ -2624,11 +4631,99 @@ func (vsc *virtualServerConfigurator) generateSSLConfig(owner runtime.Object, tl
 		Certificate:     name,
 		CertificateKey:  name,
 		RejectHandshake: rejectHandshake,
+		Protocols:       protocols,
+		Ciphers:         ciphers,
 	}
 
 	return &ssl
 }
 
+// tlsProfiles are the protocols and ciphers of the predefined TLS profiles.
+// The ciphers of TLSv1.3 are not configured by the ssl_ciphers directive, so the modern profile doesn't set the ciphers.
+var tlsProfiles = map[string]struct {
+	protocols string
+	ciphers   string
+}{
+	"modern": {
+		protocols: "TLSv1.3",
+	},
+	"intermediate": {
+		protocols: "TLSv1.2 TLSv1.3",
+		ciphers: "ECDHE-ECDSA-AES128-GCM-SHA256:ECDHE-RSA-AES128-GCM-SHA256:ECDHE-ECDSA-AES256-GCM-SHA384:ECDHE-RSA-AES256-GCM-SHA384:" +
+			"ECDHE-ECDSA-CHACHA20-POLY1305:ECDHE-RSA-CHACHA20-POLY1305:DHE-RSA-AES128-GCM-SHA256:DHE-RSA-AES256-GCM-SHA384:DHE-RSA-CHACHA20-POLY1305",
+	},
+}
+
+// generateTLSProfile returns the protocols and ciphers of the predefined profile, overridden by the protocols and ciphers of the TLS profile.
+func generateTLSProfile(profile *conf_v1.TLSProfile) (protocols string, ciphers string) {
+	if profile == nil {
+		return "", ""
+	}
+
+	predefined := tlsProfiles[profile.Name]
+	return generateString(profile.Protocols, predefined.protocols), generateString(profile.Ciphers, predefined.ciphers)
+}
+
+// altSvcAddHeader advertises HTTP/3 on the port of the server. The server of the VirtualServer adds it in the template,
+// so it is only added to the locations with their own add_header directives, which do not inherit it.
+var altSvcAddHeader = version2.AddHeader{
+	Header: version2.Header{
+		Name:  "Alt-Svc",
+		Value: `h3=":$server_port"; ma=86400`,
+	},
+	Always: true,
+}
+
+// generateHTTP3 enables HTTP/3 for the TLS listener of the VirtualServer,
+// if HTTP/3 is enabled in the Ingress Controller and supported by NGINX.
+func (vsc *virtualServerConfigurator) generateHTTP3(vs *conf_v1.VirtualServer, sslConfig *version2.SSL) {
+	if sslConfig == nil {
+		vsc.addWarningf(vs, "HTTP/3 cannot be enabled for VirtualServer %s/%s: HTTP/3 requires TLS termination", vs.Namespace, vs.Name)
+		return
+	}
+	if !vsc.isHTTP3Enabled {
+		vsc.addWarningf(vs, "HTTP/3 cannot be enabled for VirtualServer %s/%s: HTTP/3 is not enabled in the Ingress Controller, use the -enable-http3 command-line argument", vs.Namespace, vs.Name)
+		return
+	}
+	if !vsc.isHTTP3Supported {
+		vsc.addWarningf(vs, "HTTP/3 cannot be enabled for VirtualServer %s/%s: HTTP/3 requires NGINX %s or later", vs.Namespace, vs.Name, minHTTP3NginxVersion)
+		return
+	}
+
+	sslConfig.HTTP3 = true
+}
+
+// generateAdditionalListeners generates the additional listeners of the server. The HTTPS listeners are dropped
+// when TLS passthrough is enabled, as the HTTPS requests are then received from the TLS passthrough socket.
+func (vsc *virtualServerConfigurator) generateAdditionalListeners(owner runtime.Object, listeners []conf_v1.Listener) []version2.AdditionalListener {
+	var additionalListeners []version2.AdditionalListener
+	for _, l := range listeners {
+		if l.Ssl && vsc.isTLSPassthrough {
+			vsc.addWarningf(owner, "HTTPS listener %s in `listener.additional` is ignored because TLS passthrough is enabled", l.Name)
+			continue
+		}
+		additionalListeners = append(additionalListeners, version2.AdditionalListener{
+			Port:    l.Port,
+			IPv4:    l.IPv4,
+			IPv6:    l.IPv6,
+			SSL:     l.Ssl,
+			Options: generateListenerOptions(l.Options),
+		})
+	}
+	return additionalListeners
+}
+
+func generateListenerOptions(options *conf_v1.ListenerOptions) *version2.ListenerOptions {
+	if options == nil {
+		return nil
+	}
+	return &version2.ListenerOptions{
+		Deferred:    options.Deferred,
+		SoKeepalive: options.SoKeepalive,
+		Backlog:     options.Backlog,
+	}
+}
+
 func generateTLSRedirectConfig(tls *conf_v1.TLS) *version2.TLSRedirect {
 	if tls == nil || tls.Redirect == nil || !tls.Redirect.Enable {
 		return nil
@@ -2642,6 +4737,25 @@ func generateTLSRedirectConfig(tls *conf_v1.TLS) *version2.TLSRedirect {
 	return redirect
 }
 
+// generateStrictHostConfig generates the check of the Host header of the requests against the host of a VirtualServer.
+// A wildcard host like *.example.com is matched with a regular expression, like the server_name does.
+func generateStrictHostConfig(host string, strictHost *conf_v1.StrictHost) *version2.StrictHost {
+	if strictHost == nil || !strictHost.Enable {
+		return nil
+	}
+
+	cfg := &version2.StrictHost{
+		Host: host,
+		Code: generateIntFromPointer(strictHost.Code, 421),
+	}
+	if suffix, ok := strings.CutPrefix(host, "*."); ok {
+		cfg.Host = `^.+\.` + regexp.QuoteMeta(suffix) + "$"
+		cfg.Regex = true
+	}
+
+	return cfg
+}
+
 func generateTLSRedirectBasedOn(basedOn string) string {
 	if basedOn == "x-forwarded-proto" {
 		return "$http_x_forwarded_proto"
@@ -2653,6 +4767,11 @@ func createEndpointsFromUpstream(upstream version2.Upstream) []string {
 	var endpoints []string
 
 	for _, server := range upstream.Servers {
+		// The API doesn't keep servers marked down, they are only added to the upstream on a reload.
+		// Draining servers are kept, see createUpstreamServersConfigForPlus.
+		if server.Down {
+			continue
+		}
 		endpoints = append(endpoints, server.Address)
 	}
 
@@ -2668,8 +4787,8 @@ func createUpstreamsForPlus(
 	var upstreams []version2.Upstream
 
 	isPlus := true
-	upstreamNamer := NewUpstreamNamerForVirtualServer(virtualServerEx.VirtualServer)
 	vsc := newVirtualServerConfigurator(baseCfgParams, isPlus, false, staticParams, false, nil)
+	upstreamNamer := newUpstreamNamerForVirtualServer(virtualServerEx.VirtualServer, vsc.shortUpstreamNames)
 
 	for _, u := range virtualServerEx.VirtualServer.Spec.Upstreams {
 		isExternalNameSvc := virtualServerEx.ExternalNameSvcs[GenerateExternalNameSvcKey(virtualServerEx.VirtualServer.Namespace, u.Service)]
@@ -2683,18 +4802,22 @@ func createUpstreamsForPlus(
 
 		endpointsKey := GenerateEndpointsKey(upstreamNamespace, upstreamServiceName, u.Subselector, u.Port)
 		endpoints := virtualServerEx.Endpoints[endpointsKey]
+		terminatingEndpoints := vsc.generateTerminatingEndpointsForUpstream(virtualServerEx.VirtualServer.Namespace, u, virtualServerEx)
+		if len(terminatingEndpoints) > 0 {
+			endpoints = slices.Concat(endpoints, terminatingEndpoints)
+		}
 
 		backupEndpoints := []string{}
 		if u.Backup != "" {
 			backupEndpointsKey := GenerateEndpointsKey(upstreamNamespace, u.Backup, u.Subselector, *u.BackupPort)
 			backupEndpoints = virtualServerEx.Endpoints[backupEndpointsKey]
 		}
-		ups := vsc.generateUpstream(virtualServerEx.VirtualServer, upstreamName, u, isExternalNameSvc, endpoints, backupEndpoints)
+		ups := vsc.generateUpstream(virtualServerEx.VirtualServer, upstreamName, u, isExternalNameSvc, endpoints, backupEndpoints, terminatingEndpoints)
 		upstreams = append(upstreams, ups)
 	}
 
 	for _, vsr := range virtualServerEx.VirtualServerRoutes {
-		upstreamNamer = NewUpstreamNamerForVirtualServerRoute(virtualServerEx.VirtualServer, vsr)
+		upstreamNamer = newUpstreamNamerForVirtualServerRoute(virtualServerEx.VirtualServer, vsr, vsc.shortUpstreamNames)
 		for _, u := range vsr.Spec.Upstreams {
 			isExternalNameSvc := virtualServerEx.ExternalNameSvcs[GenerateExternalNameSvcKey(vsr.Namespace, u.Service)]
 			if isExternalNameSvc {
@@ -2707,6 +4830,10 @@ func createUpstreamsForPlus(
 
 			endpointsKey := GenerateEndpointsKey(serviceNamespace, serviceName, u.Subselector, u.Port)
 			endpoints := virtualServerEx.Endpoints[endpointsKey]
+			terminatingEndpoints := vsc.generateTerminatingEndpointsForUpstream(vsr.Namespace, u, virtualServerEx)
+			if len(terminatingEndpoints) > 0 {
+				endpoints = slices.Concat(endpoints, terminatingEndpoints)
+			}
 
 			// BackupService
 			backupEndpoints := []string{}
@@ -2714,7 +4841,7 @@ func createUpstreamsForPlus(
 				backupEndpointsKey := GenerateEndpointsKey(vsr.Namespace, u.Backup, u.Subselector, *u.BackupPort)
 				backupEndpoints = virtualServerEx.Endpoints[backupEndpointsKey]
 			}
-			ups := vsc.generateUpstream(vsr, upstreamName, u, isExternalNameSvc, endpoints, backupEndpoints)
+			ups := vsc.generateUpstream(vsr, upstreamName, u, isExternalNameSvc, endpoints, backupEndpoints, terminatingEndpoints)
 			upstreams = append(upstreams, ups)
 		}
 	}
@@ -2726,11 +4853,23 @@ func createUpstreamServersConfigForPlus(upstream version2.Upstream) nginx.Server
 	if len(upstream.Servers) == 0 {
 		return nginx.ServerConfig{}
 	}
+
+	var drainServers map[string]bool
+	for _, server := range upstream.Servers {
+		if server.Drain {
+			if drainServers == nil {
+				drainServers = make(map[string]bool)
+			}
+			drainServers[server.Address] = true
+		}
+	}
+
 	return nginx.ServerConfig{
-		MaxFails:    upstream.MaxFails,
-		FailTimeout: upstream.FailTimeout,
-		MaxConns:    upstream.MaxConns,
-		SlowStart:   upstream.SlowStart,
+		MaxFails:     upstream.MaxFails,
+		FailTimeout:  upstream.FailTimeout,
+		MaxConns:     upstream.MaxConns,
+		SlowStart:    upstream.SlowStart,
+		DrainServers: drainServers,
 	}
 }
 
@@ -2749,6 +4888,53 @@ func generateErrorPageName(errPageIndex int, index int) string {
 	return fmt.Sprintf("@error_page_%v_%v", errPageIndex, index)
 }
 
+// checkProxyMethodRequestBody warns when the proxy action overrides the request method, since the
+// request body of the client request is still passed to the upstream as is.
+func checkProxyMethodRequestBody(action *conf_v1.Action, path string, owner runtime.Object, vscWarnings Warnings) {
+	if action.Proxy == nil || action.Proxy.ProxyMethod == "" || generateProxyPassRequestBody(action.Proxy) == "off" {
+		return
+	}
+
+	vscWarnings.AddWarningf(owner, "The proxy action for the path %s changes the request method to %s: the request body of the client request is passed to the upstream unchanged", path, action.Proxy.ProxyMethod)
+}
+
+func generateSubFilter(subFilter *conf_v1.ActionSubFilter) *version2.SubFilter {
+	sf := &version2.SubFilter{
+		Once:  subFilter.Once,
+		Types: subFilter.Types,
+	}
+	for _, r := range subFilter.Rules {
+		sf.Rules = append(sf.Rules, version2.SubFilterRule{From: r.From, To: r.To})
+	}
+	return sf
+}
+
+// addSubFilterAcceptEncoding clears the Accept-Encoding header of the requests to the upstream, unless the
+// action sets it, since the strings in the compressed responses of the upstream can't be replaced.
+func addSubFilterAcceptEncoding(headers []version2.Header) []version2.Header {
+	for _, h := range headers {
+		if strings.EqualFold(h.Name, "Accept-Encoding") {
+			return headers
+		}
+	}
+	return append(headers, version2.Header{Name: "Accept-Encoding", Value: ""})
+}
+
+// checkExpiresCacheControlConflict warns when the action sets the expires directive and also adds
+// the Cache-Control response header, since the response would get two Cache-Control headers.
+func checkExpiresCacheControlConflict(action *conf_v1.Action, path string, owner runtime.Object, vscWarnings Warnings) {
+	if action.Proxy == nil || action.Proxy.ResponseHeaders == nil {
+		return
+	}
+
+	for _, h := range action.Proxy.ResponseHeaders.Add {
+		if strings.EqualFold(h.Name, "Cache-Control") {
+			vscWarnings.AddWarningf(owner, "The expires of the action for the path %s conflicts with the Cache-Control response header added by the proxy action", path)
+			return
+		}
+	}
+}
+
 func checkGrpcErrorPageCodes(errorPages errorPageDetails, isGRPC bool, uName string, vscWarnings Warnings) {
 	if errorPages.pages == nil || !isGRPC {
 		return
@@ -2779,6 +4965,11 @@ func generateErrorPages(errPageIndex int, errorPages []conf_v1.ErrorPage) []vers
 	var ePages []version2.ErrorPage
 
 	for i, e := range errorPages {
+		if len(e.Codes) == 0 {
+			// All the codes of the error page are passed through.
+			continue
+		}
+
 		var code int
 		var name string
 
@@ -2805,6 +4996,25 @@ func generateErrorPages(errPageIndex int, errorPages []conf_v1.ErrorPage) []vers
 	return ePages
 }
 
+// excludeErrorPageCodes returns a copy of the error pages without the given codes.
+// The error pages keep their positions, which the names of their locations are based on,
+// and the error pages left without codes are skipped when the error_page directives are generated.
+func excludeErrorPageCodes(errorPages []conf_v1.ErrorPage, codes []int) []conf_v1.ErrorPage {
+	if len(codes) == 0 || len(errorPages) == 0 {
+		return errorPages
+	}
+
+	pages := make([]conf_v1.ErrorPage, 0, len(errorPages))
+	for _, e := range errorPages {
+		e.Codes = slices.DeleteFunc(slices.Clone(e.Codes), func(c int) bool {
+			return slices.Contains(codes, c)
+		})
+		pages = append(pages, e)
+	}
+
+	return pages
+}
+
 func generateErrorPageDetails(errorPages []conf_v1.ErrorPage, errorPageLocations []version2.ErrorPageLocation, owner runtime.Object) errorPageDetails {
 	return errorPageDetails{
 		pages: errorPages,
@@ -2853,9 +5063,14 @@ func generateProxySSLName(svcName, ns string) string {
 }
 
 // isTLSEnabled checks whether TLS is enabled for the given upstream.
+// When the TLS ports are set, TLS is enabled only when the port of the upstream is one of them. The protocol
+// doesn't depend on the servers of the upstream, which NGINX Plus updates without a reload.
 func isTLSEnabled(upstream conf_v1.Upstream) bool {
 	// TLS is enabled if explicitly configured for the upstream.
-	return upstream.TLS.Enable
+	if !upstream.TLS.Enable || len(upstream.TLS.Ports) == 0 {
+		return upstream.TLS.Enable
+	}
+	return slices.Contains(upstream.TLS.Ports, upstream.Port)
 }
 
 func isGRPC(protocolType string) bool {
@@ -2879,3 +5094,22 @@ func generateDosCfg(dosResource *appProtectDosResource) *version2.Dos {
 	dos.ApDosLogConf = dosResource.AppProtectDosLogConfFile
 	return dos
 }
+
+// VirtualServerConfigHash returns a hash of the VirtualServer config, which can be used to detect changes
+// in the generated config. The hash is deterministic: the keys of the maps in the config are sorted before hashing.
+func VirtualServerConfigHash(cfg version2.VirtualServerConfig) string {
+	// Safe to ignore the error since the config contains no types that cannot be marshaled
+	data, _ := json.Marshal(cfg)
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// RenderVirtualServerConfig runs the VirtualServer config through the template executor and returns
+// the rendered NGINX config, so the exact config produced for a VirtualServer can be inspected.
+func RenderVirtualServerConfig(templateExecutor *version2.TemplateExecutor, cfg *version2.VirtualServerConfig) (string, error) {
+	content, err := templateExecutor.ExecuteVirtualServerTemplate(cfg)
+	if err != nil {
+		return "", fmt.Errorf("error rendering VirtualServer config for %s: %w", cfg.Server.ServerName, err)
+	}
+	return string(content), nil
+}
@@ -2,12 +2,15 @@ package configs
 
 import (
 	"context"
+	"fmt"
 	"reflect"
+	"slices"
 	"sort"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/nginx/kubernetes-ingress/internal/configs/version2"
+	"github.com/nginx/kubernetes-ingress/internal/nginx"
 	conf_v1 "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -3507,6 +3510,2157 @@ func TestGenerateVirtualServerConfigGrpcErrorPageWarning(t *testing.T) {
 	}
 }
 
+func TestGenerateVirtualServerConfigHTTP2Override(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		cfgHTTP2  bool
+		vsHTTP2   *bool
+		wantHTTP2 bool
+	}{
+		{
+			name:      "configmap enabled, not overridden",
+			cfgHTTP2:  true,
+			vsHTTP2:   nil,
+			wantHTTP2: true,
+		},
+		{
+			name:      "configmap enabled, disabled by virtualserver",
+			cfgHTTP2:  true,
+			vsHTTP2:   new(false),
+			wantHTTP2: false,
+		},
+		{
+			name:      "configmap disabled, enabled by virtualserver",
+			cfgHTTP2:  false,
+			vsHTTP2:   new(true),
+			wantHTTP2: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			virtualServerEx := VirtualServerEx{
+				VirtualServer: &conf_v1.VirtualServer{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "cafe",
+						Namespace: "default",
+					},
+					Spec: conf_v1.VirtualServerSpec{
+						Host:  "cafe.example.com",
+						TLS:   &conf_v1.TLS{},
+						HTTP2: test.vsHTTP2,
+					},
+				},
+			}
+			cfgParams := ConfigParams{
+				Context: context.Background(),
+				HTTP2:   test.cfgHTTP2,
+			}
+			vsc := newVirtualServerConfigurator(&cfgParams, false, false, &StaticConfigParams{}, true, &fakeBV)
+
+			result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+			if result.Server.SSL == nil {
+				t.Fatal("GenerateVirtualServerConfig() returned no SSL config")
+			}
+			if result.Server.SSL.HTTP2 != test.wantHTTP2 {
+				t.Errorf("GenerateVirtualServerConfig() returned SSL.HTTP2 %v but expected %v", result.Server.SSL.HTTP2, test.wantHTTP2)
+			}
+			if len(warnings) != 0 {
+				t.Errorf("GenerateVirtualServerConfig() returned unexpected warnings: %v", warnings)
+			}
+		})
+	}
+}
+
+func TestGenerateVirtualServerConfigDefaultType(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name            string
+		vsDefaultType   string
+		wantDefaultType string
+	}{
+		{
+			name:            "not set, inherited from the http context",
+			vsDefaultType:   "",
+			wantDefaultType: "",
+		},
+		{
+			name:            "set by virtualserver",
+			vsDefaultType:   "application/json",
+			wantDefaultType: "application/json",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			virtualServerEx := VirtualServerEx{
+				VirtualServer: &conf_v1.VirtualServer{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "cafe",
+						Namespace: "default",
+					},
+					Spec: conf_v1.VirtualServerSpec{
+						Host:        "cafe.example.com",
+						DefaultType: test.vsDefaultType,
+					},
+				},
+			}
+			cfgParams := ConfigParams{Context: context.Background()}
+			vsc := newVirtualServerConfigurator(&cfgParams, false, false, &StaticConfigParams{}, true, &fakeBV)
+
+			result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+			if result.Server.DefaultType != test.wantDefaultType {
+				t.Errorf("GenerateVirtualServerConfig() returned DefaultType %q but expected %q",
+					result.Server.DefaultType, test.wantDefaultType)
+			}
+			if len(warnings) != 0 {
+				t.Errorf("GenerateVirtualServerConfig() returned unexpected warnings: %v", warnings)
+			}
+		})
+	}
+}
+
+func TestGenerateVirtualServerConfigUnderscoresInHeaders(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name                     string
+		vsUnderscoresInHeaders   *bool
+		tls                      *conf_v1.TLS
+		wantUnderscoresInHeaders string
+		wantWarnings             int
+	}{
+		{
+			name:                     "not set, inherited from the http context",
+			vsUnderscoresInHeaders:   nil,
+			wantUnderscoresInHeaders: "",
+		},
+		{
+			name:                     "enabled by virtualserver",
+			vsUnderscoresInHeaders:   new(true),
+			tls:                      &conf_v1.TLS{},
+			wantUnderscoresInHeaders: "on",
+		},
+		{
+			name:                     "disabled by virtualserver",
+			vsUnderscoresInHeaders:   new(false),
+			tls:                      &conf_v1.TLS{},
+			wantUnderscoresInHeaders: "off",
+		},
+		{
+			name:                     "enabled by virtualserver without tls",
+			vsUnderscoresInHeaders:   new(true),
+			wantUnderscoresInHeaders: "on",
+			wantWarnings:             1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			virtualServerEx := VirtualServerEx{
+				VirtualServer: &conf_v1.VirtualServer{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "cafe",
+						Namespace: "default",
+					},
+					Spec: conf_v1.VirtualServerSpec{
+						Host:                 "cafe.example.com",
+						TLS:                  test.tls,
+						UnderscoresInHeaders: test.vsUnderscoresInHeaders,
+					},
+				},
+			}
+			cfgParams := ConfigParams{Context: context.Background()}
+			vsc := newVirtualServerConfigurator(&cfgParams, false, false, &StaticConfigParams{}, true, &fakeBV)
+
+			result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+			if result.Server.UnderscoresInHeaders != test.wantUnderscoresInHeaders {
+				t.Errorf("GenerateVirtualServerConfig() returned UnderscoresInHeaders %q but expected %q",
+					result.Server.UnderscoresInHeaders, test.wantUnderscoresInHeaders)
+			}
+			if got := len(warnings[virtualServerEx.VirtualServer]); got != test.wantWarnings {
+				t.Errorf("GenerateVirtualServerConfig() returned %d warnings but expected %d: %v", got, test.wantWarnings, warnings)
+			}
+		})
+	}
+}
+
+func TestGenerateVirtualServerConfigHTTP3(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		tls          *conf_v1.TLS
+		enableHTTP3  bool
+		nginxVersion string
+		wantHTTP3    bool
+		wantWarnings []string
+	}{
+		{
+			name:         "enabled",
+			tls:          &conf_v1.TLS{},
+			enableHTTP3:  true,
+			nginxVersion: "nginx version: nginx/1.27.4",
+			wantHTTP3:    true,
+		},
+		{
+			name:         "not enabled in the Ingress Controller",
+			tls:          &conf_v1.TLS{},
+			enableHTTP3:  false,
+			nginxVersion: "nginx version: nginx/1.27.4",
+			wantWarnings: []string{
+				"HTTP/3 cannot be enabled for VirtualServer default/cafe: HTTP/3 is not enabled in the Ingress Controller, use the -enable-http3 command-line argument",
+			},
+		},
+		{
+			name:         "not supported by NGINX",
+			tls:          &conf_v1.TLS{},
+			enableHTTP3:  true,
+			nginxVersion: "nginx version: nginx/1.23.4",
+			wantWarnings: []string{
+				"HTTP/3 cannot be enabled for VirtualServer default/cafe: HTTP/3 requires NGINX 1.25.0 or later",
+			},
+		},
+		{
+			name:         "no TLS termination",
+			enableHTTP3:  true,
+			nginxVersion: "nginx version: nginx/1.27.4",
+			wantWarnings: []string{
+				"HTTP/3 cannot be enabled for VirtualServer default/cafe: HTTP/3 requires TLS termination",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			virtualServerEx := VirtualServerEx{
+				VirtualServer: &conf_v1.VirtualServer{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "cafe",
+						Namespace: "default",
+					},
+					Spec: conf_v1.VirtualServerSpec{
+						Host:  "cafe.example.com",
+						TLS:   test.tls,
+						HTTP3: new(true),
+					},
+				},
+			}
+			cfgParams := ConfigParams{Context: context.Background()}
+			staticParams := StaticConfigParams{
+				EnableHTTP3:  test.enableHTTP3,
+				NginxVersion: nginx.NewVersion(test.nginxVersion),
+			}
+			vsc := newVirtualServerConfigurator(&cfgParams, false, false, &staticParams, true, &fakeBV)
+
+			result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+			gotHTTP3 := result.Server.SSL != nil && result.Server.SSL.HTTP3
+			if gotHTTP3 != test.wantHTTP3 {
+				t.Errorf("GenerateVirtualServerConfig() returned HTTP3 %v but expected %v", gotHTTP3, test.wantHTTP3)
+			}
+			if !cmp.Equal(test.wantWarnings, warnings[virtualServerEx.VirtualServer]) {
+				t.Error(cmp.Diff(test.wantWarnings, warnings[virtualServerEx.VirtualServer]))
+			}
+		})
+	}
+}
+
+func TestGenerateVirtualServerConfigAdditionalListeners(t *testing.T) {
+	t.Parallel()
+	listeners := []conf_v1.Listener{
+		{Name: "http-8080", Port: 8080, Protocol: "HTTP"},
+		{Name: "https-8443", Port: 8443, Protocol: "HTTP", Ssl: true},
+	}
+	tests := []struct {
+		name           string
+		tlsPassthrough bool
+		want           []version2.AdditionalListener
+		wantWarnings   []string
+	}{
+		{
+			name: "http and https listeners",
+			want: []version2.AdditionalListener{
+				{Port: 8080},
+				{Port: 8443, SSL: true},
+			},
+		},
+		{
+			name:           "https listener with tls passthrough",
+			tlsPassthrough: true,
+			want: []version2.AdditionalListener{
+				{Port: 8080},
+			},
+			wantWarnings: []string{
+				"HTTPS listener https-8443 in `listener.additional` is ignored because TLS passthrough is enabled",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			virtualServerEx := VirtualServerEx{
+				VirtualServer: &conf_v1.VirtualServer{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "cafe",
+						Namespace: "default",
+					},
+					Spec: conf_v1.VirtualServerSpec{
+						Host: "cafe.example.com",
+						TLS:  &conf_v1.TLS{},
+					},
+				},
+				AdditionalListeners: listeners,
+			}
+			cfgParams := ConfigParams{Context: context.Background()}
+			vsc := newVirtualServerConfigurator(&cfgParams, false, false, &StaticConfigParams{TLSPassthrough: test.tlsPassthrough}, false, &fakeBV)
+
+			result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+			if diff := cmp.Diff(test.want, result.Server.AdditionalListeners); diff != "" {
+				t.Errorf("GenerateVirtualServerConfig() returned unexpected additional listeners (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(test.wantWarnings, warnings[virtualServerEx.VirtualServer]); diff != "" {
+				t.Errorf("GenerateVirtualServerConfig() returned unexpected warnings (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGenerateVirtualServerConfigHealthCheckKeepaliveTime(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name              string
+		nginxVersion      string
+		keepaliveTime     string
+		wantKeepaliveTime string
+		wantWarnings      []string
+	}{
+		{
+			name:              "default supported by NGINX",
+			nginxVersion:      "nginx version: nginx/1.27.4 (nginx-plus-r34)",
+			wantKeepaliveTime: "60s",
+		},
+		{
+			name:              "set and supported by NGINX",
+			nginxVersion:      "nginx version: nginx/1.21.7 (nginx-plus-r27)",
+			keepaliveTime:     "30s",
+			wantKeepaliveTime: "30s",
+		},
+		{
+			name:              "default not supported by NGINX",
+			nginxVersion:      "nginx version: nginx/1.21.6 (nginx-plus-r26)",
+			wantKeepaliveTime: "",
+		},
+		{
+			name:              "set and not supported by NGINX",
+			nginxVersion:      "nginx version: nginx/1.21.6 (nginx-plus-r26)",
+			keepaliveTime:     "30s",
+			wantKeepaliveTime: "",
+			wantWarnings: []string{
+				"The keepalive-time of the health check of upstream tea is ignored: it requires NGINX 1.21.7 or later",
+			},
+		},
+		{
+			name:              "unknown version of NGINX",
+			wantKeepaliveTime: "60s",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			virtualServerEx := VirtualServerEx{
+				VirtualServer: &conf_v1.VirtualServer{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "cafe",
+						Namespace: "default",
+					},
+					Spec: conf_v1.VirtualServerSpec{
+						Host: "cafe.example.com",
+						Upstreams: []conf_v1.Upstream{
+							{
+								Name:    "tea",
+								Service: "tea-svc",
+								Port:    80,
+								HealthCheck: &conf_v1.HealthCheck{
+									Enable:        true,
+									KeepaliveTime: test.keepaliveTime,
+								},
+							},
+						},
+						Routes: []conf_v1.Route{
+							{
+								Path:   "/tea",
+								Action: &conf_v1.Action{Pass: "tea"},
+							},
+						},
+					},
+				},
+			}
+			cfgParams := ConfigParams{Context: context.Background()}
+			staticParams := StaticConfigParams{
+				NginxVersion: nginx.NewVersion(test.nginxVersion),
+			}
+			vsc := newVirtualServerConfigurator(&cfgParams, true, false, &staticParams, false, &fakeBV)
+
+			result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+			if len(result.Server.HealthChecks) != 1 {
+				t.Fatalf("GenerateVirtualServerConfig() returned %d health checks but expected 1", len(result.Server.HealthChecks))
+			}
+			if got := result.Server.HealthChecks[0].KeepaliveTime; got != test.wantKeepaliveTime {
+				t.Errorf("GenerateVirtualServerConfig() returned health check keepalive time %q but expected %q", got, test.wantKeepaliveTime)
+			}
+			if !cmp.Equal(test.wantWarnings, warnings[virtualServerEx.VirtualServer]) {
+				t.Error(cmp.Diff(test.wantWarnings, warnings[virtualServerEx.VirtualServer]))
+			}
+		})
+	}
+}
+
+func TestGenerateVirtualServerConfigVariablesHashSize(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		bucketSize   uint64
+		maxSize      uint64
+		wantWarnings []string
+	}{
+		{
+			name:       "variables fit in the hash",
+			bucketSize: 256,
+			maxSize:    1024,
+		},
+		{
+			name:       "variable name longer than the bucket",
+			bucketSize: 32,
+			maxSize:    1024,
+			wantWarnings: []string{
+				"The variable $vs_default_cafe_matches_0_match_0_cond_0 doesn't fit in the variables hash: set the variables-hash-bucket-size ConfigMap key to 64 or more",
+			},
+		},
+		{
+			name:       "high variable count",
+			bucketSize: 256,
+			maxSize:    8,
+			wantWarnings: []string{
+				"The VirtualServer generates 6 variables, which can exceed the variables hash: set the variables-hash-max-size ConfigMap key to 32 or more",
+			},
+		},
+		{
+			name: "sizes not set",
+		},
+	}
+
+	var routes []conf_v1.Route
+	for _, path := range []string{"/tea", "/coffee", "/juice"} {
+		routes = append(routes, conf_v1.Route{
+			Path: path,
+			Matches: []conf_v1.Match{
+				{
+					Conditions: []conf_v1.Condition{{Header: "x-version", Value: "v2"}},
+					Action:     &conf_v1.Action{Pass: "tea"},
+				},
+			},
+			Action: &conf_v1.Action{Pass: "tea"},
+		})
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			virtualServerEx := VirtualServerEx{
+				VirtualServer: &conf_v1.VirtualServer{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "cafe",
+						Namespace: "default",
+					},
+					Spec: conf_v1.VirtualServerSpec{
+						Host: "cafe.example.com",
+						Upstreams: []conf_v1.Upstream{
+							{
+								Name:    "tea",
+								Service: "tea-svc",
+								Port:    80,
+							},
+						},
+						Routes: routes,
+					},
+				},
+			}
+			cfgParams := ConfigParams{
+				Context:                 context.Background(),
+				VariablesHashBucketSize: test.bucketSize,
+				VariablesHashMaxSize:    test.maxSize,
+			}
+			vsc := newVirtualServerConfigurator(&cfgParams, false, false, &StaticConfigParams{}, false, &fakeBV)
+
+			_, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+			if !cmp.Equal(test.wantWarnings, warnings[virtualServerEx.VirtualServer]) {
+				t.Error(cmp.Diff(test.wantWarnings, warnings[virtualServerEx.VirtualServer]))
+			}
+		})
+	}
+}
+
+func TestGenerateVirtualServerConfigUpstreamTLSPorts(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name              string
+		port              uint16
+		backupPort        *uint16
+		endpoints         []string
+		expectedProxyPass string
+		wantWarnings      []string
+	}{
+		{
+			name:              "upstream on the TLS port",
+			port:              8443,
+			endpoints:         []string{"10.0.0.1:8443", "10.0.0.2:8443"},
+			expectedProxyPass: "https://vs_default_cafe_tea",
+		},
+		{
+			name:              "upstream on the TLS port without endpoints",
+			port:              8443,
+			expectedProxyPass: "https://vs_default_cafe_tea",
+		},
+		{
+			name:              "upstream on the plaintext port",
+			port:              8080,
+			endpoints:         []string{"10.0.0.1:8080", "10.0.0.2:8080"},
+			expectedProxyPass: "http://vs_default_cafe_tea",
+		},
+		{
+			name:              "upstream on the plaintext port without endpoints",
+			port:              8080,
+			expectedProxyPass: "http://vs_default_cafe_tea",
+		},
+		{
+			name:              "backup servers on the plaintext port",
+			port:              8443,
+			backupPort:        new(uint16(8080)),
+			endpoints:         []string{"10.0.0.1:8443"},
+			expectedProxyPass: "https://vs_default_cafe_tea",
+			wantWarnings: []string{
+				"The backup servers of upstream tea on port 8080 use HTTPS like the servers on port 8443, as NGINX uses the same protocol for all the servers of an upstream",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			upstream := conf_v1.Upstream{
+				Name:    "tea",
+				Service: "tea-svc",
+				Port:    test.port,
+				TLS: conf_v1.UpstreamTLS{
+					Enable: true,
+					Ports:  []uint16{8443},
+				},
+			}
+			if test.backupPort != nil {
+				upstream.Backup = "backup-svc"
+				upstream.BackupPort = test.backupPort
+			}
+			virtualServerEx := VirtualServerEx{
+				VirtualServer: &conf_v1.VirtualServer{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "cafe",
+						Namespace: "default",
+					},
+					Spec: conf_v1.VirtualServerSpec{
+						Host:      "cafe.example.com",
+						Upstreams: []conf_v1.Upstream{upstream},
+						Routes: []conf_v1.Route{
+							{
+								Path:   "/tea",
+								Action: &conf_v1.Action{Pass: "tea"},
+							},
+						},
+					},
+				},
+				Endpoints: map[string][]string{fmt.Sprintf("default/tea-svc:%d", test.port): test.endpoints},
+			}
+			cfgParams := ConfigParams{Context: context.Background()}
+			vsc := newVirtualServerConfigurator(&cfgParams, false, false, &StaticConfigParams{}, false, &fakeBV)
+
+			result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+			if got := result.Server.Locations[0].ProxyPass; got != test.expectedProxyPass {
+				t.Errorf("GenerateVirtualServerConfig() returned proxy pass %q but expected %q", got, test.expectedProxyPass)
+			}
+			if !cmp.Equal(test.wantWarnings, warnings[virtualServerEx.VirtualServer]) {
+				t.Error(cmp.Diff(test.wantWarnings, warnings[virtualServerEx.VirtualServer]))
+			}
+		})
+	}
+}
+
+func TestGenerateVirtualServerConfigLongPollReadTimeout(t *testing.T) {
+	t.Parallel()
+	virtualServerEx := VirtualServerEx{
+		VirtualServer: &conf_v1.VirtualServer{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "cafe",
+				Namespace: "default",
+			},
+			Spec: conf_v1.VirtualServerSpec{
+				Host: "cafe.example.com",
+				Upstreams: []conf_v1.Upstream{
+					{
+						Name:    "events",
+						Service: "events-svc",
+						Port:    80,
+					},
+				},
+				Routes: []conf_v1.Route{
+					{
+						Path: "/events",
+						Matches: []conf_v1.Match{
+							{
+								Conditions: []conf_v1.Condition{{Argument: "long-poll", Value: "true"}},
+								Action: &conf_v1.Action{
+									Proxy: &conf_v1.ActionProxy{
+										Upstream:    "events",
+										ReadTimeout: "1h",
+									},
+								},
+							},
+						},
+						Action: &conf_v1.Action{Pass: "events"},
+					},
+				},
+			},
+		},
+	}
+	cfgParams := ConfigParams{Context: context.Background(), ProxyReadTimeout: "60s"}
+	vsc := newVirtualServerConfigurator(&cfgParams, false, false, &StaticConfigParams{}, false, &fakeBV)
+
+	result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+	if len(warnings) != 0 {
+		t.Errorf("GenerateVirtualServerConfig() returned unexpected warnings: %v", warnings)
+	}
+
+	expected := map[string]string{
+		"/internal_location_matches_0_match_0": "1h",
+		"/internal_location_matches_0_default": "60s",
+	}
+	got := make(map[string]string)
+	for _, l := range result.Server.Locations {
+		got[l.Path] = l.ProxyReadTimeout
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("GenerateVirtualServerConfig() returned unexpected read timeouts of the locations (-want +got):\n%s", diff)
+	}
+}
+
+func TestGenerateVirtualServerConfigPreserveHostHeader(t *testing.T) {
+	t.Parallel()
+	virtualServerEx := VirtualServerEx{
+		VirtualServer: &conf_v1.VirtualServer{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "cafe",
+				Namespace: "default",
+			},
+			Spec: conf_v1.VirtualServerSpec{
+				Host: "cafe.example.com",
+				Upstreams: []conf_v1.Upstream{
+					{
+						Name:    "legacy",
+						Service: "legacy-svc",
+						Port:    80,
+					},
+					{
+						Name:    "tea",
+						Service: "tea-svc",
+						Port:    80,
+					},
+				},
+				Routes: []conf_v1.Route{
+					{
+						Path:               "/legacy",
+						Action:             &conf_v1.Action{Pass: "legacy"},
+						PreserveHostHeader: true,
+					},
+					{
+						Path: "/api",
+						Matches: []conf_v1.Match{
+							{
+								Conditions: []conf_v1.Condition{{Header: "x-version", Value: "v2"}},
+								Action:     &conf_v1.Action{Pass: "tea"},
+							},
+						},
+						Action:             &conf_v1.Action{Pass: "legacy"},
+						PreserveHostHeader: true,
+					},
+					{
+						Path: "/custom",
+						Action: &conf_v1.Action{
+							Proxy: &conf_v1.ActionProxy{
+								Upstream: "legacy",
+								RequestHeaders: &conf_v1.ProxyRequestHeaders{
+									Set: []conf_v1.Header{{Name: "Host", Value: "legacy.example.com"}},
+								},
+							},
+						},
+						PreserveHostHeader: true,
+					},
+					{
+						Path:   "/tea",
+						Action: &conf_v1.Action{Pass: "tea"},
+					},
+				},
+			},
+		},
+	}
+	vsc := newVirtualServerConfigurator(&baseCfgParams, false, false, &StaticConfigParams{}, false, &fakeBV)
+
+	result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+	if len(warnings) != 0 {
+		t.Errorf("GenerateVirtualServerConfig() returned unexpected warnings: %v", warnings)
+	}
+
+	expected := map[string]string{
+		"/legacy":                              "$http_host",
+		"/internal_location_matches_0_match_0": "$http_host",
+		"/internal_location_matches_0_default": "$http_host",
+		"/custom":                              "legacy.example.com",
+		"/tea":                                 "$host",
+	}
+	got := make(map[string]string)
+	for _, l := range result.Server.Locations {
+		for _, h := range l.ProxySetHeaders {
+			if h.Name == "Host" {
+				got[l.Path] = h.Value
+			}
+		}
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("GenerateVirtualServerConfig() returned unexpected Host headers of the locations (-want +got):\n%s", diff)
+	}
+}
+
+func TestGenerateVirtualServerConfigUpstreamServerPodComments(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name            string
+		podComments     bool
+		expectedServers []version2.UpstreamServer
+	}{
+		{
+			name:        "pod comments enabled",
+			podComments: true,
+			expectedServers: []version2.UpstreamServer{
+				{Address: "10.0.0.20:80", PodName: "tea-7d4f8b9c6-x2kzq"},
+				{Address: "10.0.0.21:80", PodName: "tea-7d4f8b9c6-m8wpl"},
+				{Address: "10.0.0.22:80"},
+			},
+		},
+		{
+			name:        "pod comments disabled",
+			podComments: false,
+			expectedServers: []version2.UpstreamServer{
+				{Address: "10.0.0.20:80"},
+				{Address: "10.0.0.21:80"},
+				{Address: "10.0.0.22:80"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			virtualServerEx := VirtualServerEx{
+				VirtualServer: &conf_v1.VirtualServer{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "cafe",
+						Namespace: "default",
+					},
+					Spec: conf_v1.VirtualServerSpec{
+						Host: "cafe.example.com",
+						Upstreams: []conf_v1.Upstream{
+							{
+								Name:    "tea",
+								Service: "tea-svc",
+								Port:    80,
+							},
+						},
+						Routes: []conf_v1.Route{
+							{
+								Path:   "/tea",
+								Action: &conf_v1.Action{Pass: "tea"},
+							},
+						},
+					},
+				},
+				Endpoints: map[string][]string{"default/tea-svc:80": {"10.0.0.21:80", "10.0.0.22:80", "10.0.0.20:80"}},
+				PodsByIP: map[string]PodInfo{
+					"10.0.0.20:80": {Name: "tea-7d4f8b9c6-x2kzq"},
+					"10.0.0.21:80": {Name: "tea-7d4f8b9c6-m8wpl"},
+				},
+			}
+			cfgParams := ConfigParams{Context: context.Background()}
+			staticParams := StaticConfigParams{UpstreamServerPodComments: test.podComments}
+			vsc := newVirtualServerConfigurator(&cfgParams, false, false, &staticParams, false, &fakeBV)
+
+			result, _ := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+			if diff := cmp.Diff(test.expectedServers, result.Upstreams[0].Servers); diff != "" {
+				t.Errorf("GenerateVirtualServerConfig() returned unexpected upstream servers (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGenerateVirtualServerConfigUnlimitedClientMaxBodySize(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name                      string
+		clientMaxBodySize         string
+		allowUnlimitedBodySize    bool
+		expectedClientMaxBodySize string
+		wantWarnings              []string
+	}{
+		{
+			name:                      "unlimited size allowed",
+			clientMaxBodySize:         "0",
+			allowUnlimitedBodySize:    true,
+			expectedClientMaxBodySize: "0",
+			wantWarnings: []string{
+				"Upstream tea accepts the request bodies of unlimited size, as client-max-body-size is 0",
+			},
+		},
+		{
+			name:                      "unlimited size not allowed",
+			clientMaxBodySize:         "0",
+			expectedClientMaxBodySize: "1m",
+			wantWarnings: []string{
+				"The 0 value of client-max-body-size of upstream tea, which accepts the request bodies of unlimited size, is ignored without allow-unlimited-body-size, the default is used",
+			},
+		},
+		{
+			name:                      "unlimited size with suffix not allowed",
+			clientMaxBodySize:         "0m",
+			expectedClientMaxBodySize: "1m",
+			wantWarnings: []string{
+				"The 0 value of client-max-body-size of upstream tea, which accepts the request bodies of unlimited size, is ignored without allow-unlimited-body-size, the default is used",
+			},
+		},
+		{
+			name:                      "limited size",
+			clientMaxBodySize:         "10m",
+			expectedClientMaxBodySize: "10m",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			virtualServerEx := VirtualServerEx{
+				VirtualServer: &conf_v1.VirtualServer{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "cafe",
+						Namespace: "default",
+					},
+					Spec: conf_v1.VirtualServerSpec{
+						Host: "cafe.example.com",
+						Upstreams: []conf_v1.Upstream{
+							{
+								Name:                   "tea",
+								Service:                "tea-svc",
+								Port:                   80,
+								ClientMaxBodySize:      test.clientMaxBodySize,
+								AllowUnlimitedBodySize: test.allowUnlimitedBodySize,
+							},
+						},
+						Routes: []conf_v1.Route{
+							{
+								Path:   "/tea",
+								Action: &conf_v1.Action{Pass: "tea"},
+							},
+						},
+					},
+				},
+			}
+			cfgParams := ConfigParams{Context: context.Background(), ClientMaxBodySize: "1m"}
+			vsc := newVirtualServerConfigurator(&cfgParams, false, false, &StaticConfigParams{}, false, &fakeBV)
+
+			result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+			if got := result.Server.Locations[0].ClientMaxBodySize; got != test.expectedClientMaxBodySize {
+				t.Errorf("GenerateVirtualServerConfig() returned client max body size %q but expected %q", got, test.expectedClientMaxBodySize)
+			}
+			if !cmp.Equal(test.wantWarnings, warnings[virtualServerEx.VirtualServer]) {
+				t.Error(cmp.Diff(test.wantWarnings, warnings[virtualServerEx.VirtualServer]))
+			}
+		})
+	}
+}
+
+func TestCheckMapHashSize(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		clients      int
+		bucketSize   string
+		maxSize      string
+		wantWarnings []string
+	}{
+		{
+			name:       "map fits in the hash",
+			clients:    100,
+			bucketSize: "256",
+			maxSize:    "2048",
+		},
+		{
+			name:       "many clients of an API key policy",
+			clients:    4000,
+			bucketSize: "256",
+			maxSize:    "2048",
+			wantWarnings: []string{
+				"The map of the variable $apikey_auth_client_name_default_cafe_api_key_policy with 4000 keys doesn't fit in the hash of maps: set the map-hash-bucket-size ConfigMap key to 512 or more",
+			},
+		},
+		{
+			name:       "key longer than the bucket",
+			clients:    2,
+			bucketSize: "64",
+			maxSize:    "2048",
+			wantWarnings: []string{
+				"The map of the variable $apikey_auth_client_name_default_cafe_api_key_policy with 2 keys doesn't fit in the hash of maps: set the map-hash-bucket-size ConfigMap key to 128 or more",
+			},
+		},
+		{
+			name:    "sizes not set",
+			clients: 4000,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			var clients []apiKeyClient
+			for i := range test.clients {
+				clients = append(clients, apiKeyClient{
+					ClientID:  fmt.Sprintf("client%d", i),
+					HashedKey: fmt.Sprintf("%064x", i),
+				})
+			}
+			vsCfg := version2.VirtualServerConfig{
+				Maps: []version2.Map{*generateAPIKeyClientMap("apikey_auth_client_name_default_cafe_api_key_policy", clients)},
+			}
+			vs := &conf_v1.VirtualServer{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "cafe",
+					Namespace: "default",
+				},
+			}
+			cfgParams := ConfigParams{
+				Context:               context.Background(),
+				MainMapHashBucketSize: test.bucketSize,
+				MainMapHashMaxSize:    test.maxSize,
+			}
+			vsc := newVirtualServerConfigurator(&cfgParams, false, false, &StaticConfigParams{}, false, &fakeBV)
+
+			vsc.checkMapHashSize(vs, &vsCfg)
+			if !cmp.Equal(test.wantWarnings, vsc.warnings[vs]) {
+				t.Error(cmp.Diff(test.wantWarnings, vsc.warnings[vs]))
+			}
+		})
+	}
+}
+
+func TestGenerateVirtualServerConfigWithGeoRouting(t *testing.T) {
+	t.Parallel()
+
+	virtualServerEx := VirtualServerEx{
+		VirtualServer: &conf_v1.VirtualServer{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "cafe",
+				Namespace: "default",
+			},
+			Spec: conf_v1.VirtualServerSpec{
+				Host: "cafe.example.com",
+				Geos: []conf_v1.Geo{
+					{
+						Name:    "region",
+						Default: "us",
+						Ranges: []conf_v1.GeoRange{
+							{CIDR: "10.1.0.0/16", Value: "eu"},
+							{CIDR: "10.2.0.0/16", Value: "us"},
+						},
+					},
+				},
+				Upstreams: []conf_v1.Upstream{
+					{Name: "tea-eu", Service: "tea-eu-svc", Port: 80},
+					{Name: "tea-us", Service: "tea-us-svc", Port: 80},
+				},
+				Routes: []conf_v1.Route{
+					{
+						Path: "/tea",
+						Matches: []conf_v1.Match{
+							{
+								Conditions: []conf_v1.Condition{{Geo: "region", Value: "eu"}},
+								Action:     &conf_v1.Action{Pass: "tea-eu"},
+							},
+						},
+						Action: &conf_v1.Action{Pass: "tea-us"},
+					},
+				},
+			},
+		},
+	}
+
+	vsc := newVirtualServerConfigurator(&baseCfgParams, false, false, &StaticConfigParams{}, false, &fakeBV)
+	result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+	if len(warnings) != 0 {
+		t.Errorf("GenerateVirtualServerConfig() returned unexpected warnings: %v", warnings)
+	}
+
+	expectedGeos := []version2.Geo{
+		{
+			Source:   "$remote_addr",
+			Variable: "$vs_default_cafe_geo_region",
+			Parameters: []version2.Parameter{
+				{Value: "default", Result: "us"},
+				{Value: "10.1.0.0/16", Result: "eu"},
+				{Value: "10.2.0.0/16", Result: "us"},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedGeos, result.Geos); diff != "" {
+		t.Errorf("GenerateVirtualServerConfig() returned unexpected geos (-want +got):\n%s", diff)
+	}
+
+	expectedMap := version2.Map{
+		Source:   "$vs_default_cafe_geo_region",
+		Variable: "$vs_default_cafe_matches_0_match_0_cond_0",
+		Parameters: []version2.Parameter{
+			{Value: `"eu"`, Result: "1"},
+			{Value: "default", Result: "0"},
+		},
+	}
+	if !slices.ContainsFunc(result.Maps, func(m version2.Map) bool { return cmp.Equal(m, expectedMap) }) {
+		t.Errorf("GenerateVirtualServerConfig() returned maps %+v without the map of the geo condition %+v", result.Maps, expectedMap)
+	}
+}
+
+func TestGenerateVirtualServerConfigWithUndefinedGeo(t *testing.T) {
+	t.Parallel()
+
+	vsr := &conf_v1.VirtualServerRoute{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "coffee",
+			Namespace: "default",
+		},
+		Spec: conf_v1.VirtualServerRouteSpec{
+			Host: "cafe.example.com",
+			Upstreams: []conf_v1.Upstream{
+				{Name: "coffee-eu", Service: "coffee-eu-svc", Port: 80},
+				{Name: "coffee-us", Service: "coffee-us-svc", Port: 80},
+			},
+			Subroutes: []conf_v1.Route{
+				{
+					Path: "/coffee",
+					Matches: []conf_v1.Match{
+						{
+							Conditions: []conf_v1.Condition{{Geo: "region", Value: "eu"}},
+							Action:     &conf_v1.Action{Pass: "coffee-eu"},
+						},
+					},
+					Action: &conf_v1.Action{Pass: "coffee-us"},
+				},
+			},
+		},
+	}
+	virtualServerEx := VirtualServerEx{
+		VirtualServer: &conf_v1.VirtualServer{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "cafe",
+				Namespace: "default",
+			},
+			Spec: conf_v1.VirtualServerSpec{
+				Host: "cafe.example.com",
+				Routes: []conf_v1.Route{
+					{
+						Path:  "/coffee",
+						Route: "default/coffee",
+					},
+				},
+			},
+		},
+		VirtualServerRoutes: []*conf_v1.VirtualServerRoute{vsr},
+	}
+
+	vsc := newVirtualServerConfigurator(&baseCfgParams, false, false, &StaticConfigParams{}, false, &fakeBV)
+	result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+
+	expectedGeos := []version2.Geo{
+		{
+			Source:     "$remote_addr",
+			Variable:   "$vs_default_cafe_geo_region",
+			Parameters: []version2.Parameter{{Value: "default", Result: `""`}},
+		},
+	}
+	if diff := cmp.Diff(expectedGeos, result.Geos); diff != "" {
+		t.Errorf("GenerateVirtualServerConfig() returned unexpected geos (-want +got):\n%s", diff)
+	}
+
+	expectedWarnings := []string{
+		"The conditions of the route /coffee reference the geo region, which is not defined in the geos of VirtualServer default/cafe, the geo has an empty value",
+	}
+	if diff := cmp.Diff(expectedWarnings, warnings[vsr]); diff != "" {
+		t.Errorf("GenerateVirtualServerConfig() returned unexpected warnings (-want +got):\n%s", diff)
+	}
+}
+
+func TestGenerateVirtualServerConfigWithGRPCErrorResponses(t *testing.T) {
+	t.Parallel()
+
+	virtualServerEx := VirtualServerEx{
+		VirtualServer: &conf_v1.VirtualServer{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "cafe",
+				Namespace: "default",
+			},
+			Spec: conf_v1.VirtualServerSpec{
+				Host: "cafe.example.com",
+				TLS: &conf_v1.TLS{
+					Secret: "",
+				},
+				Upstreams: []conf_v1.Upstream{
+					{Name: "tea", Service: "tea-svc", Port: 50051, Type: "grpc"},
+					{Name: "coffee", Service: "coffee-svc", Port: 80},
+				},
+				Routes: []conf_v1.Route{
+					{
+						Path:   "/tea",
+						Action: &conf_v1.Action{Pass: "tea"},
+						GRPCErrorResponses: []conf_v1.GRPCErrorResponse{
+							{
+								Status: "UNAVAILABLE",
+								Return: conf_v1.GRPCErrorReturn{
+									Status:  "RESOURCE_EXHAUSTED",
+									Message: "try again later",
+									Headers: []conf_v1.Header{{Name: "retry-after", Value: "30"}},
+								},
+							},
+							{Status: "DEADLINE_EXCEEDED"},
+						},
+					},
+					{
+						Path:   "/coffee",
+						Action: &conf_v1.Action{Pass: "coffee"},
+						GRPCErrorResponses: []conf_v1.GRPCErrorResponse{
+							{Status: "UNAVAILABLE"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	cfgParams := ConfigParams{
+		Context: context.Background(),
+		HTTP2:   true,
+	}
+
+	vsc := newVirtualServerConfigurator(&cfgParams, false, false, &StaticConfigParams{}, true, &fakeBV)
+	result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+
+	expectedGRPCErrorLocations := []version2.GRPCErrorLocation{
+		{
+			Name:    "@grpc_error_0",
+			Status:  8,
+			Message: "try again later",
+			Headers: []version2.Header{{Name: "retry-after", Value: "30"}},
+		},
+		{
+			Name:    "@grpc_error_1",
+			Status:  4,
+			Message: "deadline exceeded",
+		},
+		{
+			Name:    "@grpc_error_2",
+			Status:  14,
+			Message: "unavailable",
+		},
+	}
+	if diff := cmp.Diff(expectedGRPCErrorLocations, result.Server.GRPCErrorLocations); diff != "" {
+		t.Errorf("GenerateVirtualServerConfig() returned unexpected gRPC error locations (-want +got):\n%s", diff)
+	}
+
+	expectedGRPCErrorPages := map[string]string{
+		"unavailable":       "@grpc_error_0",
+		"deadline_exceeded": "@grpc_error_1",
+	}
+	if diff := cmp.Diff(expectedGRPCErrorPages, result.Server.Locations[0].GRPCErrorPages); diff != "" {
+		t.Errorf("GenerateVirtualServerConfig() returned unexpected gRPC error pages (-want +got):\n%s", diff)
+	}
+	if result.Server.Locations[1].GRPCErrorPages != nil {
+		t.Errorf("GenerateVirtualServerConfig() returned gRPC error pages %v for the route without a gRPC upstream", result.Server.Locations[1].GRPCErrorPages)
+	}
+
+	expectedWarnings := []string{
+		"The gRPC error responses of the route with the path /coffee are ignored, as the route doesn't pass the requests to a gRPC upstream",
+	}
+	if diff := cmp.Diff(expectedWarnings, warnings[virtualServerEx.VirtualServer]); diff != "" {
+		t.Errorf("GenerateVirtualServerConfig() returned unexpected warnings (-want +got):\n%s", diff)
+	}
+}
+
+func TestGenerateVirtualServerConfigTracing(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name              string
+		loadModule        bool
+		traceInHTTP       bool
+		vsTracing         *conf_v1.Tracing
+		routeTracing      *conf_v1.Tracing
+		wantServerTracing *version2.Tracing
+		wantRouteTracing  *version2.Tracing
+		wantWarnings      []string
+	}{
+		{
+			name:       "not set",
+			loadModule: true,
+		},
+		{
+			name:              "set for the virtualserver",
+			loadModule:        true,
+			vsTracing:         &conf_v1.Tracing{Enable: new(true)},
+			wantServerTracing: &version2.Tracing{Enable: true, Context: "propagate"},
+			wantRouteTracing:  &version2.Tracing{Enable: true, Context: "propagate", Route: "/tea"},
+		},
+		{
+			name:             "set for the route with the default of the configmap",
+			loadModule:       true,
+			traceInHTTP:      true,
+			routeTracing:     &conf_v1.Tracing{Context: "extract"},
+			wantRouteTracing: &version2.Tracing{Enable: true, Context: "extract", Route: "/tea"},
+		},
+		{
+			name:              "disabled for the route",
+			loadModule:        true,
+			vsTracing:         &conf_v1.Tracing{Enable: new(true), Context: "inject"},
+			routeTracing:      &conf_v1.Tracing{Enable: new(false)},
+			wantServerTracing: &version2.Tracing{Enable: true, Context: "inject"},
+			wantRouteTracing:  &version2.Tracing{Enable: false, Context: "propagate"},
+		},
+		{
+			name:         "module not loaded",
+			vsTracing:    &conf_v1.Tracing{Enable: new(true)},
+			routeTracing: &conf_v1.Tracing{Enable: new(true)},
+			wantWarnings: []string{
+				"Tracing is ignored as the otel-exporter-endpoint ConfigMap key is not set",
+				"Tracing is ignored as the otel-exporter-endpoint ConfigMap key is not set",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			virtualServerEx := VirtualServerEx{
+				VirtualServer: &conf_v1.VirtualServer{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "cafe",
+						Namespace: "default",
+					},
+					Spec: conf_v1.VirtualServerSpec{
+						Host:    "cafe.example.com",
+						Tracing: test.vsTracing,
+						Upstreams: []conf_v1.Upstream{
+							{
+								Name:    "tea",
+								Service: "tea-svc",
+								Port:    80,
+							},
+						},
+						Routes: []conf_v1.Route{
+							{
+								Path:    "/tea",
+								Action:  &conf_v1.Action{Pass: "tea"},
+								Tracing: test.routeTracing,
+							},
+						},
+					},
+				},
+			}
+			cfgParams := ConfigParams{
+				Context:             context.Background(),
+				MainOtelLoadModule:  test.loadModule,
+				MainOtelTraceInHTTP: test.traceInHTTP,
+			}
+			vsc := newVirtualServerConfigurator(&cfgParams, false, false, &StaticConfigParams{}, false, &fakeBV)
+
+			result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+			if !cmp.Equal(test.wantServerTracing, result.Server.Tracing) {
+				t.Error(cmp.Diff(test.wantServerTracing, result.Server.Tracing))
+			}
+			if len(result.Server.Locations) != 1 {
+				t.Fatalf("GenerateVirtualServerConfig() returned %d locations but expected 1", len(result.Server.Locations))
+			}
+			if !cmp.Equal(test.wantRouteTracing, result.Server.Locations[0].Tracing) {
+				t.Error(cmp.Diff(test.wantRouteTracing, result.Server.Locations[0].Tracing))
+			}
+			if !cmp.Equal(test.wantWarnings, warnings[virtualServerEx.VirtualServer]) {
+				t.Error(cmp.Diff(test.wantWarnings, warnings[virtualServerEx.VirtualServer]))
+			}
+		})
+	}
+}
+
+func TestGenerateVirtualServerConfigIgnoreClientAbort(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name                  string
+		ignoreClientAbort     *bool
+		wantIgnoreClientAbort string
+		wantWarnings          []string
+	}{
+		{
+			name:                  "not set",
+			wantIgnoreClientAbort: "",
+		},
+		{
+			name:                  "enabled",
+			ignoreClientAbort:     new(true),
+			wantIgnoreClientAbort: "on",
+			wantWarnings: []string{
+				"Upstream tea ignores the aborts of clients: the connections to the upstream servers are kept until they respond, which can exhaust the connections when the upstream servers are slow",
+			},
+		},
+		{
+			name:                  "disabled",
+			ignoreClientAbort:     new(false),
+			wantIgnoreClientAbort: "off",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			virtualServerEx := VirtualServerEx{
+				VirtualServer: &conf_v1.VirtualServer{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "cafe",
+						Namespace: "default",
+					},
+					Spec: conf_v1.VirtualServerSpec{
+						Host: "cafe.example.com",
+						Upstreams: []conf_v1.Upstream{
+							{
+								Name:                   "tea",
+								Service:                "tea-svc",
+								Port:                   80,
+								ProxyIgnoreClientAbort: test.ignoreClientAbort,
+							},
+						},
+						Routes: []conf_v1.Route{
+							{
+								Path:   "/tea",
+								Action: &conf_v1.Action{Pass: "tea"},
+							},
+						},
+					},
+				},
+			}
+			cfgParams := ConfigParams{Context: context.Background()}
+			vsc := newVirtualServerConfigurator(&cfgParams, false, false, &StaticConfigParams{}, false, &fakeBV)
+
+			result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+			if len(result.Server.Locations) != 1 {
+				t.Fatalf("GenerateVirtualServerConfig() returned %d locations but expected 1", len(result.Server.Locations))
+			}
+			if got := result.Server.Locations[0].ProxyIgnoreClientAbort; got != test.wantIgnoreClientAbort {
+				t.Errorf("GenerateVirtualServerConfig() returned ProxyIgnoreClientAbort %q but expected %q", got, test.wantIgnoreClientAbort)
+			}
+			if !cmp.Equal(test.wantWarnings, warnings[virtualServerEx.VirtualServer]) {
+				t.Error(cmp.Diff(test.wantWarnings, warnings[virtualServerEx.VirtualServer]))
+			}
+		})
+	}
+}
+
+func TestGenerateVirtualServerConfigHTTP3AltSvcHeaderInLocations(t *testing.T) {
+	t.Parallel()
+	altSvc := version2.AddHeader{
+		Header: version2.Header{Name: "Alt-Svc", Value: `h3=":$server_port"; ma=86400`},
+		Always: true,
+	}
+	tests := []struct {
+		name        string
+		enableHTTP3 bool
+		addHeaders  []conf_v1.AddHeader
+		want        []version2.AddHeader
+	}{
+		{
+			name:        "location with its own headers",
+			enableHTTP3: true,
+			addHeaders:  []conf_v1.AddHeader{{Header: conf_v1.Header{Name: "X-Tea", Value: "green"}}},
+			want: []version2.AddHeader{
+				{Header: version2.Header{Name: "X-Tea", Value: "green"}},
+				altSvc,
+			},
+		},
+		{
+			name:        "location that sets Alt-Svc",
+			enableHTTP3: true,
+			addHeaders:  []conf_v1.AddHeader{{Header: conf_v1.Header{Name: "alt-svc", Value: "clear"}}},
+			want: []version2.AddHeader{
+				{Header: version2.Header{Name: "alt-svc", Value: "clear"}},
+			},
+		},
+		{
+			name:        "location without its own headers",
+			enableHTTP3: true,
+			want:        nil,
+		},
+		{
+			name:        "HTTP/3 not enabled",
+			enableHTTP3: false,
+			addHeaders:  []conf_v1.AddHeader{{Header: conf_v1.Header{Name: "X-Tea", Value: "green"}}},
+			want: []version2.AddHeader{
+				{Header: version2.Header{Name: "X-Tea", Value: "green"}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			action := &conf_v1.ActionProxy{Upstream: "tea"}
+			if test.addHeaders != nil {
+				action.ResponseHeaders = &conf_v1.ProxyResponseHeaders{Add: test.addHeaders}
+			}
+			virtualServerEx := VirtualServerEx{
+				VirtualServer: &conf_v1.VirtualServer{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "cafe",
+						Namespace: "default",
+					},
+					Spec: conf_v1.VirtualServerSpec{
+						Host:  "cafe.example.com",
+						TLS:   &conf_v1.TLS{},
+						HTTP3: new(true),
+						Upstreams: []conf_v1.Upstream{
+							{Name: "tea", Service: "tea-svc", Port: 80},
+						},
+						Routes: []conf_v1.Route{
+							{
+								Path:   "/tea",
+								Action: &conf_v1.Action{Proxy: action},
+							},
+						},
+					},
+				},
+			}
+			cfgParams := ConfigParams{Context: context.Background()}
+			staticParams := StaticConfigParams{
+				EnableHTTP3:  test.enableHTTP3,
+				NginxVersion: nginx.NewVersion("nginx version: nginx/1.27.4"),
+			}
+			vsc := newVirtualServerConfigurator(&cfgParams, false, false, &staticParams, true, &fakeBV)
+
+			result, _ := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+			if diff := cmp.Diff(test.want, result.Server.Locations[0].AddHeaders); diff != "" {
+				t.Errorf("GenerateVirtualServerConfig() returned unexpected location headers (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGenerateVirtualServerConfigMergeSlashes(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name             string
+		vsMergeSlashes   *bool
+		wantMergeSlashes string
+		wantWarnings     []string
+	}{
+		{
+			name:             "not set, inherited from the http context",
+			vsMergeSlashes:   nil,
+			wantMergeSlashes: "",
+		},
+		{
+			name:             "enabled by virtualserver",
+			vsMergeSlashes:   new(true),
+			wantMergeSlashes: "on",
+		},
+		{
+			name:             "disabled by virtualserver",
+			vsMergeSlashes:   new(false),
+			wantMergeSlashes: "off",
+			wantWarnings: []string{
+				"Merging of slashes is disabled: the URIs with adjacent slashes are not matched by the routes for the URIs with single slashes, so the policies of those routes are not applied to them",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			virtualServerEx := VirtualServerEx{
+				VirtualServer: &conf_v1.VirtualServer{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "cafe",
+						Namespace: "default",
+					},
+					Spec: conf_v1.VirtualServerSpec{
+						Host:         "cafe.example.com",
+						MergeSlashes: test.vsMergeSlashes,
+					},
+				},
+			}
+			cfgParams := ConfigParams{Context: context.Background()}
+			vsc := newVirtualServerConfigurator(&cfgParams, false, false, &StaticConfigParams{}, true, &fakeBV)
+
+			result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+			if result.Server.MergeSlashes != test.wantMergeSlashes {
+				t.Errorf("GenerateVirtualServerConfig() returned MergeSlashes %q but expected %q",
+					result.Server.MergeSlashes, test.wantMergeSlashes)
+			}
+			if !cmp.Equal(test.wantWarnings, warnings[virtualServerEx.VirtualServer]) {
+				t.Error(cmp.Diff(test.wantWarnings, warnings[virtualServerEx.VirtualServer]))
+			}
+		})
+	}
+}
+
+func TestGenerateVirtualServerConfigRouteStatusZones(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name            string
+		isPlus          bool
+		wantStatusZones []string
+	}{
+		{
+			name:            "plus",
+			isPlus:          true,
+			wantStatusZones: []string{"tea-api", "cafe.example.com/coffee", ""},
+		},
+		{
+			name:            "oss",
+			isPlus:          false,
+			wantStatusZones: []string{"", "", ""},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			virtualServerEx := VirtualServerEx{
+				VirtualServer: &conf_v1.VirtualServer{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "cafe",
+						Namespace: "default",
+					},
+					Spec: conf_v1.VirtualServerSpec{
+						Host: "cafe.example.com",
+						Upstreams: []conf_v1.Upstream{
+							{
+								Name:    "tea",
+								Service: "tea-svc",
+								Port:    80,
+							},
+							{
+								Name:    "coffee",
+								Service: "coffee-svc",
+								Port:    80,
+							},
+						},
+						Routes: []conf_v1.Route{
+							{
+								Path:       "/tea",
+								Action:     &conf_v1.Action{Pass: "tea"},
+								StatusZone: "tea-api",
+							},
+							{
+								Path:       "/coffee",
+								Action:     &conf_v1.Action{Pass: "coffee"},
+								StatusZone: "auto",
+							},
+							{
+								Path:   "/",
+								Action: &conf_v1.Action{Pass: "coffee"},
+							},
+						},
+					},
+				},
+			}
+			cfgParams := ConfigParams{Context: context.Background()}
+			vsc := newVirtualServerConfigurator(&cfgParams, test.isPlus, false, &StaticConfigParams{}, false, &fakeBV)
+
+			result, _ := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+
+			var gotStatusZones []string
+			for _, loc := range result.Server.Locations {
+				gotStatusZones = append(gotStatusZones, loc.StatusZone)
+			}
+			if !cmp.Equal(test.wantStatusZones, gotStatusZones) {
+				t.Errorf("GenerateVirtualServerConfig() returned unexpected status zones of the locations: %v", cmp.Diff(test.wantStatusZones, gotStatusZones))
+			}
+		})
+	}
+}
+
+func TestGenerateVirtualServerConfigVirtualServerRouteStatusZones(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name            string
+		isPlus          bool
+		wantStatusZones map[string]string
+	}{
+		{
+			name:   "plus",
+			isPlus: true,
+			wantStatusZones: map[string]string{
+				"/":             "",
+				"/tea":          "tea/tea-vsr",
+				"/tea/green":    "green-tea",
+				"/coffee":       "coffee-team",
+				"/coffee/latte": "coffee-team",
+			},
+		},
+		{
+			name:   "oss",
+			isPlus: false,
+			wantStatusZones: map[string]string{
+				"/":             "",
+				"/tea":          "",
+				"/tea/green":    "",
+				"/coffee":       "",
+				"/coffee/latte": "",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			virtualServerEx := VirtualServerEx{
+				VirtualServer: &conf_v1.VirtualServer{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "cafe",
+						Namespace: "default",
+					},
+					Spec: conf_v1.VirtualServerSpec{
+						Host: "cafe.example.com",
+						Upstreams: []conf_v1.Upstream{
+							{
+								Name:    "cafe",
+								Service: "cafe-svc",
+								Port:    80,
+							},
+						},
+						Routes: []conf_v1.Route{
+							{
+								Path:   "/",
+								Action: &conf_v1.Action{Pass: "cafe"},
+							},
+							{
+								Path:  "/tea",
+								Route: "tea/tea-vsr",
+							},
+							{
+								Path:  "/coffee",
+								Route: "coffee/coffee-vsr",
+							},
+						},
+					},
+				},
+				VirtualServerRoutes: []*conf_v1.VirtualServerRoute{
+					{
+						ObjectMeta: meta_v1.ObjectMeta{
+							Name:      "tea-vsr",
+							Namespace: "tea",
+						},
+						Spec: conf_v1.VirtualServerRouteSpec{
+							Host: "cafe.example.com",
+							Upstreams: []conf_v1.Upstream{
+								{
+									Name:    "tea",
+									Service: "tea-svc",
+									Port:    80,
+								},
+							},
+							Subroutes: []conf_v1.Route{
+								{
+									Path:   "/tea",
+									Action: &conf_v1.Action{Pass: "tea"},
+								},
+								{
+									Path:       "/tea/green",
+									Action:     &conf_v1.Action{Pass: "tea"},
+									StatusZone: "green-tea",
+								},
+							},
+							StatusZone: "auto",
+						},
+					},
+					{
+						ObjectMeta: meta_v1.ObjectMeta{
+							Name:      "coffee-vsr",
+							Namespace: "coffee",
+						},
+						Spec: conf_v1.VirtualServerRouteSpec{
+							Host: "cafe.example.com",
+							Upstreams: []conf_v1.Upstream{
+								{
+									Name:    "coffee",
+									Service: "coffee-svc",
+									Port:    80,
+								},
+							},
+							Subroutes: []conf_v1.Route{
+								{
+									Path:   "/coffee",
+									Action: &conf_v1.Action{Pass: "coffee"},
+								},
+								{
+									Path:   "/coffee/latte",
+									Action: &conf_v1.Action{Pass: "coffee"},
+								},
+							},
+							StatusZone: "coffee-team",
+						},
+					},
+				},
+			}
+			cfgParams := ConfigParams{Context: context.Background()}
+			vsc := newVirtualServerConfigurator(&cfgParams, test.isPlus, false, &StaticConfigParams{}, false, &fakeBV)
+
+			result, _ := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+
+			gotStatusZones := make(map[string]string)
+			for _, loc := range result.Server.Locations {
+				gotStatusZones[loc.Path] = loc.StatusZone
+			}
+			if diff := cmp.Diff(test.wantStatusZones, gotStatusZones); diff != "" {
+				t.Errorf("GenerateVirtualServerConfig() returned unexpected status zones of the locations (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGenerateVirtualServerConfigSharedHealthCheckMatch(t *testing.T) {
+	t.Parallel()
+	virtualServerEx := VirtualServerEx{
+		VirtualServer: &conf_v1.VirtualServer{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "cafe",
+				Namespace: "default",
+			},
+			Spec: conf_v1.VirtualServerSpec{
+				Host: "cafe.example.com",
+				HealthCheckMatches: []conf_v1.HealthCheckMatch{
+					{
+						Name:   "healthy",
+						Status: "200-299",
+					},
+				},
+				Upstreams: []conf_v1.Upstream{
+					{
+						Name:        "tea",
+						Service:     "tea-svc",
+						Port:        80,
+						HealthCheck: &conf_v1.HealthCheck{Enable: true, Match: "healthy"},
+					},
+					{
+						Name:        "coffee",
+						Service:     "coffee-svc",
+						Port:        80,
+						HealthCheck: &conf_v1.HealthCheck{Enable: true, Match: "healthy"},
+					},
+					{
+						Name:        "juice",
+						Service:     "juice-svc",
+						Port:        80,
+						HealthCheck: &conf_v1.HealthCheck{Enable: true, Match: "missing"},
+					},
+				},
+				Routes: []conf_v1.Route{
+					{
+						Path:   "/tea",
+						Action: &conf_v1.Action{Pass: "tea"},
+					},
+					{
+						Path:   "/coffee",
+						Action: &conf_v1.Action{Pass: "coffee"},
+					},
+					{
+						Path:   "/juice",
+						Action: &conf_v1.Action{Pass: "juice"},
+					},
+				},
+			},
+		},
+	}
+	cfgParams := ConfigParams{Context: context.Background()}
+	vsc := newVirtualServerConfigurator(&cfgParams, true, false, &StaticConfigParams{}, false, &fakeBV)
+
+	result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+
+	expectedStatusMatches := []version2.StatusMatch{
+		{
+			Name: "vs_default_cafe_match_healthy",
+			Code: "200-299",
+		},
+	}
+	if diff := cmp.Diff(expectedStatusMatches, result.StatusMatches); diff != "" {
+		t.Errorf("GenerateVirtualServerConfig() returned unexpected status matches (-want +got):\n%s", diff)
+	}
+
+	expectedMatches := map[string]string{
+		"vs_default_cafe_tea":    "vs_default_cafe_match_healthy",
+		"vs_default_cafe_coffee": "vs_default_cafe_match_healthy",
+		"vs_default_cafe_juice":  "",
+	}
+	for _, hc := range result.Server.HealthChecks {
+		if hc.Match != expectedMatches[hc.Name] {
+			t.Errorf("GenerateVirtualServerConfig() returned match %q for the health check of %s but expected %q", hc.Match, hc.Name, expectedMatches[hc.Name])
+		}
+	}
+	if len(result.Server.HealthChecks) != len(expectedMatches) {
+		t.Errorf("GenerateVirtualServerConfig() returned %d health checks but expected %d", len(result.Server.HealthChecks), len(expectedMatches))
+	}
+
+	expectedWarnings := []string{
+		"The health check of upstream juice references the match missing, which is not defined in the healthCheckMatches of VirtualServer default/cafe, the default match is used",
+	}
+	if diff := cmp.Diff(expectedWarnings, warnings[virtualServerEx.VirtualServer]); diff != "" {
+		t.Errorf("GenerateVirtualServerConfig() returned unexpected warnings (-want +got):\n%s", diff)
+	}
+}
+
+func TestGenerateVirtualServerConfigTimeoutProfiles(t *testing.T) {
+	t.Parallel()
+	virtualServerEx := VirtualServerEx{
+		VirtualServer: &conf_v1.VirtualServer{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "cafe",
+				Namespace: "default",
+			},
+			Spec: conf_v1.VirtualServerSpec{
+				Host: "cafe.example.com",
+				TimeoutProfiles: []conf_v1.TimeoutProfile{
+					{
+						Name:           "fast",
+						ConnectTimeout: "1s",
+						ReadTimeout:    "5s",
+						SendTimeout:    "5s",
+					},
+					{
+						Name:        "streaming",
+						ReadTimeout: "1h",
+						SendTimeout: "1h",
+					},
+				},
+				Upstreams: []conf_v1.Upstream{
+					{
+						Name:           "tea",
+						Service:        "tea-svc",
+						Port:           80,
+						TimeoutProfile: "fast",
+					},
+					{
+						Name:             "coffee",
+						Service:          "coffee-svc",
+						Port:             80,
+						TimeoutProfile:   "streaming",
+						ProxySendTimeout: "30s",
+					},
+					{
+						Name:           "juice",
+						Service:        "juice-svc",
+						Port:           80,
+						TimeoutProfile: "missing",
+					},
+				},
+				Routes: []conf_v1.Route{
+					{
+						Path:   "/tea",
+						Action: &conf_v1.Action{Pass: "tea"},
+					},
+					{
+						Path:   "/coffee",
+						Action: &conf_v1.Action{Pass: "coffee"},
+					},
+					{
+						Path:   "/juice",
+						Action: &conf_v1.Action{Pass: "juice"},
+					},
+				},
+			},
+		},
+	}
+	cfgParams := ConfigParams{
+		Context:             context.Background(),
+		ProxyConnectTimeout: "30s",
+		ProxyReadTimeout:    "60s",
+		ProxySendTimeout:    "60s",
+	}
+	vsc := newVirtualServerConfigurator(&cfgParams, false, false, &StaticConfigParams{}, false, &fakeBV)
+
+	result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+
+	type timeouts struct {
+		Connect string
+		Read    string
+		Send    string
+	}
+	expectedTimeouts := map[string]timeouts{
+		"/tea":    {Connect: "1s", Read: "5s", Send: "5s"},
+		"/coffee": {Connect: "30s", Read: "1h", Send: "30s"},
+		"/juice":  {Connect: "30s", Read: "60s", Send: "60s"},
+	}
+	gotTimeouts := make(map[string]timeouts)
+	for _, loc := range result.Server.Locations {
+		gotTimeouts[loc.Path] = timeouts{Connect: loc.ProxyConnectTimeout, Read: loc.ProxyReadTimeout, Send: loc.ProxySendTimeout}
+	}
+	if diff := cmp.Diff(expectedTimeouts, gotTimeouts); diff != "" {
+		t.Errorf("GenerateVirtualServerConfig() returned unexpected timeouts of the locations (-want +got):\n%s", diff)
+	}
+
+	expectedWarnings := []string{
+		"Upstream juice references the timeout profile missing, which is not defined in the timeoutProfiles of VirtualServer default/cafe, the default timeouts are used",
+	}
+	if diff := cmp.Diff(expectedWarnings, warnings[virtualServerEx.VirtualServer]); diff != "" {
+		t.Errorf("GenerateVirtualServerConfig() returned unexpected warnings (-want +got):\n%s", diff)
+	}
+}
+
+func TestGenerateVirtualServerConfigWithFailover(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name              string
+		failover          *conf_v1.UpstreamFailover
+		backupEndpoints   []string
+		wantTries         int
+		wantTimeout       string
+		wantBackupServers int
+		wantWarning       bool
+	}{
+		{
+			name:              "all primary servers are tried before the backup servers",
+			failover:          &conf_v1.UpstreamFailover{},
+			backupEndpoints:   []string{"backup-one.corp.local:8090", "backup-two.corp.local:8090"},
+			wantTries:         5,
+			wantTimeout:       "0s",
+			wantBackupServers: 2,
+		},
+		{
+			name: "primary tries and timeout",
+			failover: &conf_v1.UpstreamFailover{
+				PrimaryTries: new(2),
+				Timeout:      "10s",
+			},
+			backupEndpoints:   []string{"backup-one.corp.local:8090"},
+			wantTries:         3,
+			wantTimeout:       "10s",
+			wantBackupServers: 1,
+		},
+		{
+			name: "no backup servers",
+			failover: &conf_v1.UpstreamFailover{
+				PrimaryTries: new(2),
+				Timeout:      "10s",
+			},
+			wantTries:   0,
+			wantTimeout: "0s",
+			wantWarning: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			virtualServerEx := VirtualServerEx{
+				VirtualServer: &conf_v1.VirtualServer{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "cafe",
+						Namespace: "default",
+					},
+					Spec: conf_v1.VirtualServerSpec{
+						Host: "cafe.example.com",
+						Upstreams: []conf_v1.Upstream{
+							{
+								Name:       "tea",
+								Service:    "tea-svc",
+								Port:       80,
+								Backup:     "backup-svc",
+								BackupPort: new(uint16(8090)),
+								Failover:   test.failover,
+							},
+						},
+						Routes: []conf_v1.Route{
+							{
+								Path:   "/tea",
+								Action: &conf_v1.Action{Pass: "tea"},
+							},
+						},
+					},
+				},
+				Endpoints: map[string][]string{
+					"default/tea-svc:80": {
+						"10.0.0.20:80",
+						"10.0.0.21:80",
+						"10.0.0.22:80",
+					},
+					"default/backup-svc:8090": test.backupEndpoints,
+				},
+			}
+			cfgParams := ConfigParams{Context: context.Background()}
+			vsc := newVirtualServerConfigurator(&cfgParams, true, false, &StaticConfigParams{}, false, &fakeBV)
+
+			result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+
+			if gotWarning := len(warnings) > 0; gotWarning != test.wantWarning {
+				t.Errorf("GenerateVirtualServerConfig() returned warnings %v, expected a warning: %v", warnings, test.wantWarning)
+			}
+			if got := len(result.Upstreams[0].BackupServers); got != test.wantBackupServers {
+				t.Errorf("GenerateVirtualServerConfig() returned %d backup servers but expected %d", got, test.wantBackupServers)
+			}
+			loc := result.Server.Locations[0]
+			if loc.ProxyNextUpstreamTries != test.wantTries {
+				t.Errorf("GenerateVirtualServerConfig() returned next upstream tries %d but expected %d", loc.ProxyNextUpstreamTries, test.wantTries)
+			}
+			if loc.ProxyNextUpstreamTimeout != test.wantTimeout {
+				t.Errorf("GenerateVirtualServerConfig() returned next upstream timeout %q but expected %q", loc.ProxyNextUpstreamTimeout, test.wantTimeout)
+			}
+		})
+	}
+}
+
+func TestGenerateVirtualServerConfigGrpcWithHTTP2DisabledWarning(t *testing.T) {
+	t.Parallel()
+	virtualServerEx := VirtualServerEx{
+		VirtualServer: &conf_v1.VirtualServer{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "cafe",
+				Namespace: "default",
+			},
+			Spec: conf_v1.VirtualServerSpec{
+				Host:  "cafe.example.com",
+				TLS:   &conf_v1.TLS{},
+				HTTP2: new(false),
+				Upstreams: []conf_v1.Upstream{
+					{
+						Name:    "grpc-app",
+						Service: "grpc-svc",
+						Port:    50051,
+						Type:    "grpc",
+					},
+				},
+				Routes: []conf_v1.Route{
+					{
+						Path: "/",
+						Action: &conf_v1.Action{
+							Pass: "grpc-app",
+						},
+					},
+				},
+			},
+		},
+		Endpoints: map[string][]string{
+			"default/grpc-svc:50051": {
+				"10.0.0.20:80",
+			},
+		},
+	}
+	cfgParams := ConfigParams{
+		Context: context.Background(),
+		HTTP2:   true,
+	}
+	vsc := newVirtualServerConfigurator(&cfgParams, false, false, &StaticConfigParams{}, true, &fakeBV)
+
+	expectedWarnings := Warnings{
+		virtualServerEx.VirtualServer: {
+			"gRPC cannot be configured for upstream grpc-app. gRPC requires HTTP/2, which is disabled for VirtualServer default/cafe",
+		},
+	}
+
+	_, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+	if !reflect.DeepEqual(warnings, expectedWarnings) {
+		t.Errorf("GenerateVirtualServerConfig() returned warnings of \n%v but expected \n%v", warnings, expectedWarnings)
+	}
+}
+
+func TestGenerateVirtualServerConfigGrpcHealthCheckDefaultService(t *testing.T) {
+	t.Parallel()
+	virtualServerEx := VirtualServerEx{
+		VirtualServer: &conf_v1.VirtualServer{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "cafe",
+				Namespace: "default",
+			},
+			Spec: conf_v1.VirtualServerSpec{
+				Host: "cafe.example.com",
+				TLS:  &conf_v1.TLS{},
+				Upstreams: []conf_v1.Upstream{
+					{
+						Name:    "grpc-app",
+						Service: "grpc-svc",
+						Port:    50051,
+						Type:    "grpc",
+						HealthCheck: &conf_v1.HealthCheck{
+							Enable: true,
+						},
+					},
+				},
+				Routes: []conf_v1.Route{
+					{
+						Path: "/",
+						Action: &conf_v1.Action{
+							Pass: "grpc-app",
+						},
+					},
+				},
+			},
+		},
+		Endpoints: map[string][]string{
+			"default/grpc-svc:50051": {
+				"10.0.0.20:80",
+			},
+		},
+	}
+	tests := []struct {
+		grpcService string
+		expected    string
+		msg         string
+	}{
+		{
+			grpcService: "",
+			expected:    "",
+			msg:         "no default service in the ConfigMap",
+		},
+		{
+			grpcService: "grpc.health.v1.Health",
+			expected:    "grpc.health.v1.Health",
+			msg:         "default service in the ConfigMap",
+		},
+	}
+
+	for _, test := range tests {
+		cfgParams := ConfigParams{
+			Context:                context.Background(),
+			HTTP2:                  true,
+			HealthCheckGRPCService: test.grpcService,
+		}
+		vsc := newVirtualServerConfigurator(&cfgParams, true, false, &StaticConfigParams{}, true, &fakeBV)
+
+		result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+		if len(warnings) != 0 {
+			t.Errorf("GenerateVirtualServerConfig() returned unexpected warnings %v for the case of %s", warnings, test.msg)
+		}
+		if len(result.Server.HealthChecks) != 1 || result.Server.HealthChecks[0].GRPCService != test.expected {
+			t.Errorf("GenerateVirtualServerConfig() returned health checks %+v, expected the gRPC service %q for the case of %s", result.Server.HealthChecks, test.expected, test.msg)
+		}
+	}
+}
+
 func TestGenerateVirtualServerConfigWithForeignNamespaceService(t *testing.T) {
 	t.Parallel()
 	virtualServerEx := VirtualServerEx{
@@ -3516,221 +5670,1424 @@ func TestGenerateVirtualServerConfigWithForeignNamespaceService(t *testing.T) {
 				Namespace: "default",
 			},
 			Spec: conf_v1.VirtualServerSpec{
-				Host: "cafe.example.com",
+				Host: "cafe.example.com",
+				Upstreams: []conf_v1.Upstream{
+					{
+						Name:    "coffee",
+						Service: "coffee/coffee-svc",
+						Port:    80,
+					},
+				},
+				Routes: []conf_v1.Route{
+					{
+						Path: "/coffee",
+						Action: &conf_v1.Action{
+							Pass: "coffee",
+						},
+					},
+				},
+			},
+		},
+		Endpoints: map[string][]string{
+			"coffee/coffee-svc:80": {
+				"10.0.0.20:80",
+			},
+		},
+		VirtualServerRoutes: []*conf_v1.VirtualServerRoute{},
+	}
+
+	vsc := newVirtualServerConfigurator(&baseCfgParams, false, false, &StaticConfigParams{}, false, nil)
+	result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+	if len(warnings) != 0 {
+		t.Errorf("GenerateVirtualServerConfig returned warnings: %v", warnings)
+	}
+
+	expected := version2.VirtualServerConfig{
+		Upstreams: []version2.Upstream{
+			{
+				UpstreamLabels: version2.UpstreamLabels{
+					Service:           "coffee/coffee-svc",
+					ResourceType:      "virtualserver",
+					ResourceName:      "cafe",
+					ResourceNamespace: "default",
+				},
+				Name: "vs_default_cafe_coffee",
+				Servers: []version2.UpstreamServer{
+					{
+						Address: "10.0.0.20:80",
+					},
+				},
+				Keepalive: 16,
+			},
+		},
+		HTTPSnippets:  []string{},
+		LimitReqZones: []version2.LimitReqZone{},
+		Server: version2.Server{
+			ServerName:      "cafe.example.com",
+			StatusZone:      "cafe.example.com",
+			VSNamespace:     "default",
+			VSName:          "cafe",
+			ProxyProtocol:   true,
+			ServerTokens:    "off",
+			SetRealIPFrom:   []string{"0.0.0.0/0"},
+			RealIPHeader:    "X-Real-IP",
+			RealIPRecursive: true,
+			Snippets:        []string{"# server snippet"},
+			Locations: []version2.Location{
+				{
+					Path:                     "/coffee",
+					ProxyPass:                "http://vs_default_cafe_coffee",
+					ProxyNextUpstream:        "error timeout",
+					ProxyNextUpstreamTimeout: "0s",
+					ProxyPassRequestHeaders:  true,
+					ProxySetHeaders: []version2.Header{
+						{
+							Name:  "Host",
+							Value: "$host",
+						},
+					},
+					HasKeepalive: true,
+					ProxySSLName: "coffee-svc.coffee.svc",
+					ServiceName:  "coffee-svc",
+				},
+			},
+		},
+	}
+
+	if !cmp.Equal(expected, result) {
+		t.Error(cmp.Diff(expected, result))
+	}
+}
+
+func findLocationByPath(t *testing.T, locations []version2.Location, path string) version2.Location {
+	t.Helper()
+
+	for _, location := range locations {
+		if location.Path == path {
+			return location
+		}
+	}
+
+	t.Fatalf("expected location with path %q", path)
+	return version2.Location{}
+}
+
+func TestGenerateVirtualServerConfigWithForeignNamespaceServiceInVSR(t *testing.T) {
+	t.Parallel()
+	virtualServerEx := VirtualServerEx{
+		VirtualServer: &conf_v1.VirtualServer{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "cafe",
+				Namespace: "default",
+			},
+			Spec: conf_v1.VirtualServerSpec{
+				Host: "cafe.example.com",
+				Routes: []conf_v1.Route{
+					{
+						Path:  "/tea",
+						Route: "default/tea",
+					},
+				},
+			},
+		},
+		Endpoints: map[string][]string{
+			"tea/tea-svc:80": {
+				"10.0.0.30:80",
+			},
+		},
+		VirtualServerRoutes: []*conf_v1.VirtualServerRoute{
+			{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "tea",
+					Namespace: "default",
+				},
+				Spec: conf_v1.VirtualServerRouteSpec{
+					Host: "cafe.example.com",
+					Upstreams: []conf_v1.Upstream{
+						{
+							Name:    "tea",
+							Service: "tea/tea-svc",
+							Port:    80,
+						},
+					},
+					Subroutes: []conf_v1.Route{
+						{
+							Path: "/tea",
+							Action: &conf_v1.Action{
+								Pass: "tea",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	vsc := newVirtualServerConfigurator(&baseCfgParams, false, false, &StaticConfigParams{}, false, nil)
+	result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+	if len(warnings) != 0 {
+		t.Errorf("GenerateVirtualServerConfig returned warnings: %v", warnings)
+	}
+
+	expected := version2.VirtualServerConfig{
+		Upstreams: []version2.Upstream{
+			{
+				UpstreamLabels: version2.UpstreamLabels{
+					Service:           "tea/tea-svc",
+					ResourceType:      "virtualserverroute",
+					ResourceName:      "tea",
+					ResourceNamespace: "default",
+				},
+				Name: "vs_default_cafe_vsr_default_tea_tea",
+				Servers: []version2.UpstreamServer{
+					{
+						Address: "10.0.0.30:80",
+					},
+				},
+				Keepalive: 16,
+			},
+		},
+		HTTPSnippets:  []string{},
+		LimitReqZones: []version2.LimitReqZone{},
+		Server: version2.Server{
+			ServerName:      "cafe.example.com",
+			StatusZone:      "cafe.example.com",
+			VSNamespace:     "default",
+			VSName:          "cafe",
+			ProxyProtocol:   true,
+			ServerTokens:    "off",
+			SetRealIPFrom:   []string{"0.0.0.0/0"},
+			RealIPHeader:    "X-Real-IP",
+			RealIPRecursive: true,
+			Snippets:        []string{"# server snippet"},
+			Locations: []version2.Location{
+				{
+					Path:                     "/tea",
+					ProxyPass:                "http://vs_default_cafe_vsr_default_tea_tea",
+					ProxyNextUpstream:        "error timeout",
+					ProxyNextUpstreamTimeout: "0s",
+					ProxyPassRequestHeaders:  true,
+					ProxySetHeaders: []version2.Header{
+						{
+							Name:  "Host",
+							Value: "$host",
+						},
+					},
+					HasKeepalive: true,
+					ProxySSLName: "tea-svc.tea.svc",
+					ServiceName:  "tea-svc",
+					IsVSR:        true,
+					VSRName:      "tea",
+					VSRNamespace: "default",
+				},
+			},
+		},
+	}
+
+	if !cmp.Equal(expected, result) {
+		t.Error(cmp.Diff(expected, result))
+	}
+}
+
+func TestGenerateRequestIDMap(t *testing.T) {
+	t.Parallel()
+	variableNamer := &VariableNamer{safeNsName: "default_cafe"}
+
+	tests := []struct {
+		msg       string
+		requestID *conf_v1.RequestID
+		expected  version2.Map
+	}{
+		{
+			msg:       "default header",
+			requestID: &conf_v1.RequestID{},
+			expected: version2.Map{
+				Source:   "$http_x_request_id",
+				Variable: "$vs_default_cafe_request_id",
+				Parameters: []version2.Parameter{
+					{Value: `""`, Result: "$request_id"},
+					{Value: "default", Result: "$http_x_request_id"},
+				},
+			},
+		},
+		{
+			msg:       "custom header",
+			requestID: &conf_v1.RequestID{Header: "X-Correlation-ID"},
+			expected: version2.Map{
+				Source:   "$http_x_correlation_id",
+				Variable: "$vs_default_cafe_request_id",
+				Parameters: []version2.Parameter{
+					{Value: `""`, Result: "$request_id"},
+					{Value: "default", Result: "$http_x_correlation_id"},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		result := generateRequestIDMap(test.requestID, variableNamer)
+		if diff := cmp.Diff(test.expected, result); diff != "" {
+			t.Errorf("generateRequestIDMap() mismatch for %s (-want +got):\n%s", test.msg, diff)
+		}
+	}
+}
+
+func TestAddRequestIDToLocations(t *testing.T) {
+	t.Parallel()
+	locations := []version2.Location{
+		{
+			Path: "/tea",
+		},
+		{
+			Path:            "/coffee",
+			ProxySetHeaders: []version2.Header{{Name: "x-request-id", Value: "$connection"}},
+			AddHeaders:      []version2.AddHeader{{Header: version2.Header{Name: "X-Request-Id", Value: "$connection"}}},
+		},
+	}
+
+	addRequestIDToLocations("X-Request-ID", "$vs_default_cafe_request_id", locations)
+
+	expected := []version2.Location{
+		{
+			Path:            "/tea",
+			ProxySetHeaders: []version2.Header{{Name: "X-Request-ID", Value: "$vs_default_cafe_request_id"}},
+			AddHeaders: []version2.AddHeader{
+				{
+					Header: version2.Header{Name: "X-Request-ID", Value: "$vs_default_cafe_request_id"},
+					Always: true,
+				},
+			},
+		},
+		{
+			Path:            "/coffee",
+			ProxySetHeaders: []version2.Header{{Name: "x-request-id", Value: "$connection"}},
+			AddHeaders:      []version2.AddHeader{{Header: version2.Header{Name: "X-Request-Id", Value: "$connection"}}},
+		},
+	}
+	if diff := cmp.Diff(expected, locations); diff != "" {
+		t.Errorf("addRequestIDToLocations() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGenerateVirtualServerConfigWithRequestID(t *testing.T) {
+	t.Parallel()
+	virtualServerEx := VirtualServerEx{
+		VirtualServer: &conf_v1.VirtualServer{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "cafe",
+				Namespace: "default",
+			},
+			Spec: conf_v1.VirtualServerSpec{
+				Host:      "cafe.example.com",
+				RequestID: &conf_v1.RequestID{},
+				Upstreams: []conf_v1.Upstream{
+					{
+						Name:    "tea",
+						Service: "tea-svc",
+						Port:    80,
+					},
+				},
+				Routes: []conf_v1.Route{
+					{
+						Path: "/tea",
+						Action: &conf_v1.Action{
+							Pass: "tea",
+						},
+					},
+				},
+			},
+		},
+		Endpoints: map[string][]string{
+			"default/tea-svc:80": {
+				"10.0.0.20:80",
+			},
+		},
+	}
+
+	vsc := newVirtualServerConfigurator(&ConfigParams{Context: context.Background()}, false, false, &StaticConfigParams{}, false, &fakeBV)
+	result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+	if len(warnings) != 0 {
+		t.Errorf("GenerateVirtualServerConfig() returned unexpected warnings: %v", warnings)
+	}
+
+	expectedMaps := []version2.Map{
+		{
+			Source:   "$http_x_request_id",
+			Variable: "$vs_default_cafe_request_id",
+			Parameters: []version2.Parameter{
+				{Value: `""`, Result: "$request_id"},
+				{Value: "default", Result: "$http_x_request_id"},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedMaps, result.Maps); diff != "" {
+		t.Errorf("GenerateVirtualServerConfig() returned unexpected maps (-want +got):\n%s", diff)
+	}
+
+	expectedProxySetHeaders := []version2.Header{
+		{Name: "Host", Value: "$host"},
+		{Name: "X-Request-ID", Value: "$vs_default_cafe_request_id"},
+	}
+	if diff := cmp.Diff(expectedProxySetHeaders, result.Server.Locations[0].ProxySetHeaders); diff != "" {
+		t.Errorf("GenerateVirtualServerConfig() returned unexpected proxy set headers (-want +got):\n%s", diff)
+	}
+
+	expectedAddHeaders := []version2.AddHeader{
+		{
+			Header: version2.Header{Name: "X-Request-ID", Value: "$vs_default_cafe_request_id"},
+			Always: true,
+		},
+	}
+	if diff := cmp.Diff(expectedAddHeaders, result.Server.Locations[0].AddHeaders); diff != "" {
+		t.Errorf("GenerateVirtualServerConfig() returned unexpected add headers (-want +got):\n%s", diff)
+	}
+}
+
+func TestGenerateVirtualServerConfigWithRouteSizeVariables(t *testing.T) {
+	t.Parallel()
+	virtualServerEx := VirtualServerEx{
+		VirtualServer: &conf_v1.VirtualServer{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "cafe",
+				Namespace: "default",
+			},
+			Spec: conf_v1.VirtualServerSpec{
+				Host:               "cafe.example.com",
+				RouteSizeVariables: true,
 				Upstreams: []conf_v1.Upstream{
+					{
+						Name:    "tea",
+						Service: "tea-svc",
+						Port:    80,
+					},
 					{
 						Name:    "coffee",
-						Service: "coffee/coffee-svc",
+						Service: "coffee-svc",
+						Port:    80,
+					},
+				},
+				Routes: []conf_v1.Route{
+					{
+						Path: "/tea",
+						Action: &conf_v1.Action{
+							Pass: "tea",
+						},
+					},
+					{
+						Path: "/coffee",
+						Action: &conf_v1.Action{
+							Pass: "coffee",
+						},
+					},
+				},
+			},
+		},
+		Endpoints: map[string][]string{
+			"default/tea-svc:80": {
+				"10.0.0.20:80",
+			},
+			"default/coffee-svc:80": {
+				"10.0.0.30:80",
+			},
+		},
+	}
+
+	vsc := newVirtualServerConfigurator(&ConfigParams{Context: context.Background()}, false, false, &StaticConfigParams{}, false, &fakeBV)
+	result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+	if len(warnings) != 0 {
+		t.Errorf("GenerateVirtualServerConfig() returned unexpected warnings: %v", warnings)
+	}
+
+	expectedMaps := []version2.Map{
+		{
+			Source:   "$vs_default_cafe_route",
+			Variable: "$vs_default_cafe_route_0_request_length",
+			Parameters: []version2.Parameter{
+				{Value: `"0"`, Result: "$request_length"},
+				{Value: "default", Result: `""`},
+			},
+		},
+		{
+			Source:   "$vs_default_cafe_route",
+			Variable: "$vs_default_cafe_route_0_bytes_sent",
+			Parameters: []version2.Parameter{
+				{Value: `"0"`, Result: "$bytes_sent"},
+				{Value: "default", Result: `""`},
+			},
+		},
+		{
+			Source:   "$vs_default_cafe_route",
+			Variable: "$vs_default_cafe_route_1_request_length",
+			Parameters: []version2.Parameter{
+				{Value: `"1"`, Result: "$request_length"},
+				{Value: "default", Result: `""`},
+			},
+		},
+		{
+			Source:   "$vs_default_cafe_route",
+			Variable: "$vs_default_cafe_route_1_bytes_sent",
+			Parameters: []version2.Parameter{
+				{Value: `"1"`, Result: "$bytes_sent"},
+				{Value: "default", Result: `""`},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedMaps, result.Maps); diff != "" {
+		t.Errorf("GenerateVirtualServerConfig() returned unexpected maps (-want +got):\n%s", diff)
+	}
+
+	expectedServerVariables := []version2.Variable{
+		{Name: "$vs_default_cafe_route", Value: `""`},
+	}
+	if diff := cmp.Diff(expectedServerVariables, result.Server.Variables); diff != "" {
+		t.Errorf("GenerateVirtualServerConfig() returned unexpected server variables (-want +got):\n%s", diff)
+	}
+
+	expectedLocationVariables := [][]version2.Variable{
+		{{Name: "$vs_default_cafe_route", Value: `"0"`}},
+		{{Name: "$vs_default_cafe_route", Value: `"1"`}},
+	}
+	var locationVariables [][]version2.Variable
+	for _, loc := range result.Server.Locations {
+		locationVariables = append(locationVariables, loc.Variables)
+	}
+	if diff := cmp.Diff(expectedLocationVariables, locationVariables); diff != "" {
+		t.Errorf("GenerateVirtualServerConfig() returned unexpected variables of locations (-want +got):\n%s", diff)
+	}
+}
+
+func TestGenerateVirtualServerConfigWithDebugHeaders(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		debugHeaders          bool
+		wantServerAddHeaders  []version2.AddHeader
+		wantLocationAddHeader []version2.AddHeader
+		wantWarnings          int
+		msg                   string
+	}{
+		{
+			debugHeaders: true,
+			wantServerAddHeaders: []version2.AddHeader{
+				{Header: version2.Header{Name: "X-Server", Value: "cafe"}},
+				{Header: version2.Header{Name: "X-Upstream-Addr", Value: "${upstream_addr}"}, Always: true},
+			},
+			wantLocationAddHeader: []version2.AddHeader{
+				{Header: version2.Header{Name: "X-Upstream-Response-Time", Value: "${upstream_response_time}"}},
+				{Header: version2.Header{Name: "X-Server", Value: "cafe"}},
+				{Header: version2.Header{Name: "X-Upstream-Addr", Value: "${upstream_addr}"}, Always: true},
+			},
+			msg: "debug headers enabled",
+		},
+		{
+			debugHeaders: false,
+			wantServerAddHeaders: []version2.AddHeader{
+				{Header: version2.Header{Name: "X-Server", Value: "cafe"}},
+			},
+			wantLocationAddHeader: []version2.AddHeader{},
+			wantWarnings:          2,
+			msg:                   "debug headers disabled",
+		},
+	}
+
+	for _, test := range tests {
+		virtualServerEx := VirtualServerEx{
+			VirtualServer: &conf_v1.VirtualServer{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "cafe",
+					Namespace: "default",
+				},
+				Spec: conf_v1.VirtualServerSpec{
+					Host:         "cafe.example.com",
+					DebugHeaders: test.debugHeaders,
+					ResponseHeaders: &conf_v1.ResponseHeaders{
+						Add: []conf_v1.AddHeader{
+							{Header: conf_v1.Header{Name: "X-Server", Value: "cafe"}},
+							{Header: conf_v1.Header{Name: "X-Upstream-Addr", Value: "${upstream_addr}"}, Always: true},
+						},
+					},
+					Upstreams: []conf_v1.Upstream{
+						{
+							Name:    "tea",
+							Service: "tea-svc",
+							Port:    80,
+						},
+					},
+					Routes: []conf_v1.Route{
+						{
+							Path: "/tea",
+							Action: &conf_v1.Action{
+								Proxy: &conf_v1.ActionProxy{
+									Upstream: "tea",
+									ResponseHeaders: &conf_v1.ProxyResponseHeaders{
+										Add: []conf_v1.AddHeader{
+											{Header: conf_v1.Header{Name: "X-Upstream-Response-Time", Value: "${upstream_response_time}"}},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			Endpoints: map[string][]string{
+				"default/tea-svc:80": {
+					"10.0.0.20:80",
+				},
+			},
+		}
+
+		vsc := newVirtualServerConfigurator(&ConfigParams{Context: context.Background()}, false, false, &StaticConfigParams{}, false, &fakeBV)
+		result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+		if got := len(warnings[virtualServerEx.VirtualServer]); got != test.wantWarnings {
+			t.Errorf("GenerateVirtualServerConfig() returned warnings %v for the case of %s", warnings, test.msg)
+		}
+		if diff := cmp.Diff(test.wantServerAddHeaders, result.Server.AddHeaders); diff != "" {
+			t.Errorf("GenerateVirtualServerConfig() returned unexpected headers of the server for the case of %s (-want +got):\n%s", test.msg, diff)
+		}
+		if diff := cmp.Diff(test.wantLocationAddHeader, result.Server.Locations[0].AddHeaders); diff != "" {
+			t.Errorf("GenerateVirtualServerConfig() returned unexpected headers of the location for the case of %s (-want +got):\n%s", test.msg, diff)
+		}
+	}
+}
+
+func TestGenerateVirtualServerConfigWithPassthroughErrorCodes(t *testing.T) {
+	t.Parallel()
+
+	errorPageReturn := &conf_v1.ErrorPageReturn{ActionReturn: conf_v1.ActionReturn{Code: 200, Body: "Error"}}
+	virtualServerEx := VirtualServerEx{
+		VirtualServer: &conf_v1.VirtualServer{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "cafe",
+				Namespace: "default",
+			},
+			Spec: conf_v1.VirtualServerSpec{
+				Host: "cafe.example.com",
+				Upstreams: []conf_v1.Upstream{
+					{
+						Name:    "tea",
+						Service: "tea-svc",
 						Port:    80,
 					},
-				},
-				Routes: []conf_v1.Route{
+				},
+				Routes: []conf_v1.Route{
+					{
+						Path: "/tea",
+						ErrorPages: []conf_v1.ErrorPage{
+							{Codes: []int{404, 500}, Return: errorPageReturn},
+							{Codes: []int{502, 503, 504}, Return: errorPageReturn},
+						},
+						PassthroughErrorCodes: []int{502, 503, 504},
+						Action: &conf_v1.Action{
+							Pass: "tea",
+						},
+					},
 					{
 						Path: "/coffee",
+						ErrorPages: []conf_v1.ErrorPage{
+							{Codes: []int{502}, Return: errorPageReturn},
+						},
+						PassthroughErrorCodes: []int{502},
 						Action: &conf_v1.Action{
-							Pass: "coffee",
+							Pass: "tea",
 						},
 					},
 				},
 			},
 		},
 		Endpoints: map[string][]string{
-			"coffee/coffee-svc:80": {
-				"10.0.0.20:80",
-			},
+			"default/tea-svc:80": {"10.0.0.20:80"},
 		},
-		VirtualServerRoutes: []*conf_v1.VirtualServerRoute{},
 	}
 
-	vsc := newVirtualServerConfigurator(&baseCfgParams, false, false, &StaticConfigParams{}, false, nil)
+	vsc := newVirtualServerConfigurator(&ConfigParams{Context: context.Background()}, false, false, &StaticConfigParams{}, false, &fakeBV)
 	result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
 	if len(warnings) != 0 {
-		t.Errorf("GenerateVirtualServerConfig returned warnings: %v", warnings)
+		t.Errorf("GenerateVirtualServerConfig() returned unexpected warnings: %v", warnings)
 	}
 
-	expected := version2.VirtualServerConfig{
-		Upstreams: []version2.Upstream{
-			{
-				UpstreamLabels: version2.UpstreamLabels{
-					Service:           "coffee/coffee-svc",
-					ResourceType:      "virtualserver",
-					ResourceName:      "cafe",
-					ResourceNamespace: "default",
+	tea := result.Server.Locations[0]
+	if !tea.ProxyInterceptErrors {
+		t.Errorf("GenerateVirtualServerConfig() did not intercept the errors of the location with the remaining error pages")
+	}
+	wantErrorPages := []version2.ErrorPage{{Name: "@error_page_0_0", Codes: "404 500", ResponseCode: 200}}
+	if diff := cmp.Diff(wantErrorPages, tea.ErrorPages); diff != "" {
+		t.Errorf("GenerateVirtualServerConfig() returned unexpected error pages (-want +got):\n%s", diff)
+	}
+
+	coffee := result.Server.Locations[1]
+	if coffee.ProxyInterceptErrors {
+		t.Errorf("GenerateVirtualServerConfig() intercepted the errors of the location with all error codes passed through")
+	}
+	if len(coffee.ErrorPages) != 0 {
+		t.Errorf("GenerateVirtualServerConfig() returned error pages %v for the location with all error codes passed through", coffee.ErrorPages)
+	}
+}
+
+func TestExcludeErrorPageCodes(t *testing.T) {
+	t.Parallel()
+
+	errorPages := []conf_v1.ErrorPage{
+		{Codes: []int{404, 502}},
+		{Codes: []int{503}},
+	}
+
+	got := excludeErrorPageCodes(errorPages, []int{502, 503})
+
+	want := []conf_v1.ErrorPage{
+		{Codes: []int{404}},
+		{Codes: []int{}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("excludeErrorPageCodes() returned unexpected result (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]int{404, 502}, errorPages[0].Codes); diff != "" {
+		t.Errorf("excludeErrorPageCodes() modified the original error pages (-want +got):\n%s", diff)
+	}
+}
+
+func TestGenerateVirtualServerConfigWithInheritedErrorPages(t *testing.T) {
+	t.Parallel()
+
+	errorPageReturn := &conf_v1.ErrorPageReturn{ActionReturn: conf_v1.ActionReturn{Code: 200, Body: "Error"}}
+	tests := []struct {
+		name           string
+		subroute       conf_v1.Route
+		wantErrorCodes []string
+	}{
+		{
+			name: "inherited from the virtualserver route",
+			subroute: conf_v1.Route{
+				Path:   "/tea",
+				Action: &conf_v1.Action{Pass: "tea"},
+			},
+			wantErrorCodes: []string{"404 500"},
+		},
+		{
+			name: "inherited explicitly from the virtualserver route",
+			subroute: conf_v1.Route{
+				Path:              "/tea",
+				Action:            &conf_v1.Action{Pass: "tea"},
+				InheritErrorPages: new(true),
+			},
+			wantErrorCodes: []string{"404 500"},
+		},
+		{
+			name: "overridden by the subroute",
+			subroute: conf_v1.Route{
+				Path:   "/tea",
+				Action: &conf_v1.Action{Pass: "tea"},
+				ErrorPages: []conf_v1.ErrorPage{
+					{Codes: []int{502}, Return: errorPageReturn},
 				},
-				Name: "vs_default_cafe_coffee",
-				Servers: []version2.UpstreamServer{
+			},
+			wantErrorCodes: []string{"502"},
+		},
+		{
+			name: "not inherited by the subroute",
+			subroute: conf_v1.Route{
+				Path:              "/tea",
+				Action:            &conf_v1.Action{Pass: "tea"},
+				InheritErrorPages: new(false),
+			},
+			wantErrorCodes: nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			virtualServerEx := VirtualServerEx{
+				VirtualServer: &conf_v1.VirtualServer{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "cafe",
+						Namespace: "default",
+					},
+					Spec: conf_v1.VirtualServerSpec{
+						Host: "cafe.example.com",
+						Routes: []conf_v1.Route{
+							{
+								Path:  "/tea",
+								Route: "default/tea",
+								ErrorPages: []conf_v1.ErrorPage{
+									{Codes: []int{404, 500}, Return: errorPageReturn},
+								},
+							},
+						},
+					},
+				},
+				Endpoints: map[string][]string{"default/tea-svc:80": {"10.0.0.20:80"}},
+				VirtualServerRoutes: []*conf_v1.VirtualServerRoute{
 					{
-						Address: "10.0.0.20:80",
+						ObjectMeta: meta_v1.ObjectMeta{
+							Name:      "tea",
+							Namespace: "default",
+						},
+						Spec: conf_v1.VirtualServerRouteSpec{
+							Host:      "cafe.example.com",
+							Upstreams: []conf_v1.Upstream{{Name: "tea", Service: "tea-svc", Port: 80}},
+							Subroutes: []conf_v1.Route{test.subroute},
+						},
 					},
 				},
-				Keepalive: 16,
+			}
+
+			vsc := newVirtualServerConfigurator(&ConfigParams{Context: context.Background()}, false, false, &StaticConfigParams{}, false, &fakeBV)
+			result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+			if len(warnings) != 0 {
+				t.Errorf("GenerateVirtualServerConfig() returned unexpected warnings: %v", warnings)
+			}
+
+			location := findLocationByPath(t, result.Server.Locations, "/tea")
+			var errorCodes []string
+			for _, e := range location.ErrorPages {
+				errorCodes = append(errorCodes, e.Codes)
+			}
+			if diff := cmp.Diff(test.wantErrorCodes, errorCodes); diff != "" {
+				t.Errorf("GenerateVirtualServerConfig() returned unexpected error pages (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGenerateVirtualServerConfigWithNoEndpoints(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		noEndpoints        *conf_v1.NoEndpoints
+		wantReturnLocation version2.ReturnLocation
+		msg                string
+	}{
+		{
+			noEndpoints: &conf_v1.NoEndpoints{Code: 503, RetryAfter: new(30)},
+			wantReturnLocation: version2.ReturnLocation{
+				Name:    "@return_0",
+				Return:  version2.Return{Code: 503},
+				Headers: []version2.Header{{Name: "Retry-After", Value: "30"}},
 			},
+			msg: "503 with retry after",
 		},
-		HTTPSnippets:  []string{},
-		LimitReqZones: []version2.LimitReqZone{},
-		Server: version2.Server{
-			ServerName:      "cafe.example.com",
-			StatusZone:      "cafe.example.com",
-			VSNamespace:     "default",
-			VSName:          "cafe",
-			ProxyProtocol:   true,
-			ServerTokens:    "off",
-			SetRealIPFrom:   []string{"0.0.0.0/0"},
-			RealIPHeader:    "X-Real-IP",
-			RealIPRecursive: true,
-			Snippets:        []string{"# server snippet"},
-			Locations: []version2.Location{
-				{
-					Path:                     "/coffee",
-					ProxyPass:                "http://vs_default_cafe_coffee",
-					ProxyNextUpstream:        "error timeout",
-					ProxyNextUpstreamTimeout: "0s",
-					ProxyPassRequestHeaders:  true,
-					ProxySetHeaders: []version2.Header{
+		{
+			noEndpoints: &conf_v1.NoEndpoints{
+				Return: &conf_v1.ActionReturn{
+					Code:    503,
+					Type:    "text/html",
+					Body:    "<p>Down for maintenance</p>",
+					Headers: []conf_v1.Header{{Name: "Cache-Control", Value: "no-store"}},
+				},
+			},
+			wantReturnLocation: version2.ReturnLocation{
+				Name:        "@return_0",
+				DefaultType: "text/html",
+				Return:      version2.Return{Code: 503, Text: "<p>Down for maintenance</p>"},
+				Headers:     []version2.Header{{Name: "Cache-Control", Value: "no-store"}},
+			},
+			msg: "custom return",
+		},
+	}
+
+	wantErrorPage := version2.ErrorPage{
+		Name:         "$vs_default_cafe_no_endpoints",
+		Codes:        "502",
+		ResponseCode: -1,
+	}
+	wantMap := version2.Map{
+		Source:   "$upstream_addr",
+		Variable: "$vs_default_cafe_no_endpoints",
+		Parameters: []version2.Parameter{
+			{
+				Value:  `"vs_default_cafe_tea"`,
+				Result: "@return_0",
+			},
+			{
+				Value:  "default",
+				Result: "@upstream_error_502",
+			},
+		},
+		Volatile: true,
+	}
+	wantOtherErrorLocation := version2.ReturnLocation{
+		Name:   "@upstream_error_502",
+		Return: version2.Return{Code: 502},
+	}
+
+	for _, test := range tests {
+		virtualServerEx := VirtualServerEx{
+			VirtualServer: &conf_v1.VirtualServer{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "cafe",
+					Namespace: "default",
+				},
+				Spec: conf_v1.VirtualServerSpec{
+					Host:        "cafe.example.com",
+					NoEndpoints: test.noEndpoints,
+					Upstreams: []conf_v1.Upstream{
 						{
-							Name:  "Host",
-							Value: "$host",
+							Name:    "tea",
+							Service: "tea-svc",
+							Port:    80,
+						},
+					},
+					Routes: []conf_v1.Route{
+						{
+							Path: "/tea",
+							Action: &conf_v1.Action{
+								Pass: "tea",
+							},
+						},
+						{
+							Path: "/coffee",
+							ErrorPages: []conf_v1.ErrorPage{
+								{
+									Codes:  []int{404},
+									Return: &conf_v1.ErrorPageReturn{ActionReturn: conf_v1.ActionReturn{Body: "Not Found"}},
+								},
+							},
+							Action: &conf_v1.Action{
+								Pass: "tea",
+							},
+						},
+						{
+							Path:                  "/juice",
+							PassthroughErrorCodes: []int{502},
+							Action: &conf_v1.Action{
+								Pass: "tea",
+							},
+						},
+					},
+				},
+			},
+			Endpoints: map[string][]string{
+				"default/tea-svc:80": {},
+			},
+		}
+
+		vsc := newVirtualServerConfigurator(&ConfigParams{Context: context.Background()}, false, false, &StaticConfigParams{}, false, &fakeBV)
+		result, _ := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+
+		wantServers := []version2.UpstreamServer{{Address: nginx502Server, Down: true}}
+		if diff := cmp.Diff(wantServers, result.Upstreams[0].Servers); diff != "" {
+			t.Errorf("GenerateVirtualServerConfig() returned unexpected upstream servers for the case of %s (-want +got):\n%s", test.msg, diff)
+		}
+		if len(result.Server.ErrorPages) != 0 {
+			t.Errorf("GenerateVirtualServerConfig() added error pages %v to the server for the case of %s", result.Server.ErrorPages, test.msg)
+		}
+		if diff := cmp.Diff([]version2.ReturnLocation{test.wantReturnLocation, wantOtherErrorLocation}, result.Server.ReturnLocations); diff != "" {
+			t.Errorf("GenerateVirtualServerConfig() returned unexpected return locations for the case of %s (-want +got):\n%s", test.msg, diff)
+		}
+		if diff := cmp.Diff([]version2.Map{wantMap}, result.Maps); diff != "" {
+			t.Errorf("GenerateVirtualServerConfig() returned unexpected maps for the case of %s (-want +got):\n%s", test.msg, diff)
+		}
+		if diff := cmp.Diff([]version2.ErrorPage{wantErrorPage}, result.Server.Locations[0].ErrorPages); diff != "" {
+			t.Errorf("GenerateVirtualServerConfig() returned unexpected error pages of the location without error pages for the case of %s (-want +got):\n%s", test.msg, diff)
+		}
+		// the location intercepts the errors of the upstream, which must not be rewritten
+		if locErrorPages := result.Server.Locations[1].ErrorPages; len(locErrorPages) != 1 {
+			t.Errorf("GenerateVirtualServerConfig() returned error pages %v of the location with error pages for the case of %s", locErrorPages, test.msg)
+		}
+		if locErrorPages := result.Server.Locations[2].ErrorPages; len(locErrorPages) != 0 {
+			t.Errorf("GenerateVirtualServerConfig() added error pages %v to the location that passes 502 through for the case of %s", locErrorPages, test.msg)
+		}
+	}
+}
+
+func TestAddNoEndpointsErrorPageToLocations(t *testing.T) {
+	t.Parallel()
+	errorPage := version2.ErrorPage{
+		Name:         "$vs_default_cafe_no_endpoints",
+		Codes:        "502",
+		ResponseCode: -1,
+	}
+	serverErrorPages := []version2.ErrorPage{
+		{
+			Name:  "@login_url_jwt-policy",
+			Codes: "401",
+		},
+	}
+
+	tests := []struct {
+		locations             []version2.Location
+		passthroughErrorCodes []int
+		expected              []version2.Location
+		msg                   string
+	}{
+		{
+			locations: []version2.Location{{Path: "/"}},
+			expected: []version2.Location{
+				{
+					Path:       "/",
+					ErrorPages: []version2.ErrorPage{serverErrorPages[0], errorPage},
+				},
+			},
+			msg: "location without error pages gets the error pages of the server",
+		},
+		{
+			locations: []version2.Location{
+				{
+					Path:       "/",
+					ErrorPages: []version2.ErrorPage{{Name: "@login_url_route", Codes: "401"}},
+				},
+			},
+			expected: []version2.Location{
+				{
+					Path:       "/",
+					ErrorPages: []version2.ErrorPage{{Name: "@login_url_route", Codes: "401"}, errorPage},
+				},
+			},
+			msg: "location with its own error pages",
+		},
+		{
+			locations: []version2.Location{
+				{
+					Path:                 "/",
+					ErrorPages:           []version2.ErrorPage{{Name: "@error_page_0_0", Codes: "404"}},
+					ProxyInterceptErrors: true,
+				},
+			},
+			expected: []version2.Location{
+				{
+					Path:                 "/",
+					ErrorPages:           []version2.ErrorPage{{Name: "@error_page_0_0", Codes: "404"}},
+					ProxyInterceptErrors: true,
+				},
+			},
+			msg: "location that intercepts the errors of the upstream",
+		},
+		{
+			locations: []version2.Location{
+				{
+					Path:       "/",
+					ErrorPages: []version2.ErrorPage{{Name: "@error_page_0_0", Codes: "500 502"}},
+				},
+			},
+			expected: []version2.Location{
+				{
+					Path:       "/",
+					ErrorPages: []version2.ErrorPage{{Name: "@error_page_0_0", Codes: "500 502"}},
+				},
+			},
+			msg: "location that handles 502",
+		},
+		{
+			locations: []version2.Location{{Path: "/", GRPCPass: "grpc://backend"}},
+			expected:  []version2.Location{{Path: "/", GRPCPass: "grpc://backend"}},
+			msg:       "gRPC location",
+		},
+		{
+			locations:             []version2.Location{{Path: "/"}},
+			passthroughErrorCodes: []int{502},
+			expected:              []version2.Location{{Path: "/"}},
+			msg:                   "route that passes 502 through",
+		},
+	}
+
+	for _, test := range tests {
+		addNoEndpointsErrorPageToLocations(&errorPage, serverErrorPages, test.passthroughErrorCodes, test.locations)
+		if diff := cmp.Diff(test.expected, test.locations); diff != "" {
+			t.Errorf("addNoEndpointsErrorPageToLocations() mismatch for the case of %s (-want +got):\n%s", test.msg, diff)
+		}
+	}
+}
+
+func TestGenerateVirtualServerConfigWithMatchesVariableInProxySetHeaders(t *testing.T) {
+	t.Parallel()
+	virtualServerEx := VirtualServerEx{
+		VirtualServer: &conf_v1.VirtualServer{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "cafe",
+				Namespace: "default",
+			},
+			Spec: conf_v1.VirtualServerSpec{
+				Host: "cafe.example.com",
+				Upstreams: []conf_v1.Upstream{
+					{
+						Name:    "tea",
+						Service: "tea-svc",
+						Port:    80,
+					},
+				},
+				Routes: []conf_v1.Route{
+					{
+						Path: "/tea",
+						Matches: []conf_v1.Match{
+							{
+								Conditions: []conf_v1.Condition{
+									{
+										Header: "x-region",
+										Value:  "eu",
+									},
+								},
+								Action: &conf_v1.Action{
+									Proxy: &conf_v1.ActionProxy{
+										Upstream: "tea",
+										RequestHeaders: &conf_v1.ProxyRequestHeaders{
+											Set: []conf_v1.Header{
+												{Name: "X-Region-Match", Value: "${vs_default_cafe_matches_0_match_0_cond_0}"},
+												{Name: "X-Unknown", Value: "${vs_default_cafe_matches_1_match_0_cond_0}"},
+											},
+										},
+									},
+								},
+							},
+						},
+						Action: &conf_v1.Action{
+							Pass: "tea",
 						},
 					},
-					HasKeepalive: true,
-					ProxySSLName: "coffee-svc.coffee.svc",
-					ServiceName:  "coffee-svc",
 				},
 			},
 		},
+		Endpoints: map[string][]string{
+			"default/tea-svc:80": {
+				"10.0.0.20:80",
+			},
+		},
 	}
 
-	if !cmp.Equal(expected, result) {
-		t.Error(cmp.Diff(expected, result))
-	}
-}
-
-func findLocationByPath(t *testing.T, locations []version2.Location, path string) version2.Location {
-	t.Helper()
+	vsc := newVirtualServerConfigurator(&ConfigParams{Context: context.Background()}, false, false, &StaticConfigParams{}, false, &fakeBV)
+	result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
 
-	for _, location := range locations {
-		if location.Path == path {
-			return location
-		}
+	expectedWarnings := Warnings{
+		virtualServerEx.VirtualServer: {
+			"header X-Unknown of location /internal_location_matches_0_match_0 references the undefined variable $vs_default_cafe_matches_1_match_0_cond_0 and is ignored",
+		},
+	}
+	if diff := cmp.Diff(expectedWarnings, warnings); diff != "" {
+		t.Errorf("GenerateVirtualServerConfig() returned unexpected warnings (-want +got):\n%s", diff)
 	}
 
-	t.Fatalf("expected location with path %q", path)
-	return version2.Location{}
+	expectedProxySetHeaders := []version2.Header{
+		{Name: "X-Region-Match", Value: "${vs_default_cafe_matches_0_match_0_cond_0}"},
+		{Name: "Host", Value: "$host"},
+	}
+	if diff := cmp.Diff(expectedProxySetHeaders, result.Server.Locations[0].ProxySetHeaders); diff != "" {
+		t.Errorf("GenerateVirtualServerConfig() returned unexpected proxy set headers (-want +got):\n%s", diff)
+	}
 }
 
-func TestGenerateVirtualServerConfigWithForeignNamespaceServiceInVSR(t *testing.T) {
+func TestGenerateVirtualServerConfigWithRouteVariableInProxySetHeaders(t *testing.T) {
 	t.Parallel()
-	virtualServerEx := VirtualServerEx{
-		VirtualServer: &conf_v1.VirtualServer{
-			ObjectMeta: meta_v1.ObjectMeta{
-				Name:      "cafe",
-				Namespace: "default",
+	tests := []struct {
+		routeSizeVariables      bool
+		wantWarning             bool
+		expectedProxySetHeaders []version2.Header
+		msg                     string
+	}{
+		{
+			routeSizeVariables: true,
+			expectedProxySetHeaders: []version2.Header{
+				{Name: "X-Route", Value: "${vs_default_cafe_route}"},
+				{Name: "Host", Value: "$host"},
 			},
-			Spec: conf_v1.VirtualServerSpec{
-				Host: "cafe.example.com",
-				Routes: []conf_v1.Route{
-					{
-						Path:  "/tea",
-						Route: "default/tea",
+			msg: "route size variables enabled",
+		},
+		{
+			routeSizeVariables: false,
+			wantWarning:        true,
+			expectedProxySetHeaders: []version2.Header{
+				{Name: "Host", Value: "$host"},
+			},
+			msg: "route size variables disabled",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			t.Parallel()
+			virtualServerEx := VirtualServerEx{
+				VirtualServer: &conf_v1.VirtualServer{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "cafe",
+						Namespace: "default",
+					},
+					Spec: conf_v1.VirtualServerSpec{
+						Host:               "cafe.example.com",
+						RouteSizeVariables: test.routeSizeVariables,
+						Upstreams: []conf_v1.Upstream{
+							{
+								Name:    "tea",
+								Service: "tea-svc",
+								Port:    80,
+							},
+						},
+						Routes: []conf_v1.Route{
+							{
+								Path: "/tea",
+								Action: &conf_v1.Action{
+									Proxy: &conf_v1.ActionProxy{
+										Upstream: "tea",
+										RequestHeaders: &conf_v1.ProxyRequestHeaders{
+											Set: []conf_v1.Header{
+												{Name: "X-Route", Value: "${vs_default_cafe_route}"},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				Endpoints: map[string][]string{
+					"default/tea-svc:80": {
+						"10.0.0.20:80",
 					},
 				},
+			}
+
+			vsc := newVirtualServerConfigurator(&ConfigParams{Context: context.Background()}, false, false, &StaticConfigParams{}, false, &fakeBV)
+			result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+
+			if gotWarning := len(warnings) > 0; gotWarning != test.wantWarning {
+				t.Errorf("GenerateVirtualServerConfig() returned warnings %v, expected a warning: %v", warnings, test.wantWarning)
+			}
+			if diff := cmp.Diff(test.expectedProxySetHeaders, result.Server.Locations[0].ProxySetHeaders); diff != "" {
+				t.Errorf("GenerateVirtualServerConfig() returned unexpected proxy set headers (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGenerateVirtualServerConfigWithServerResponseHeaders(t *testing.T) {
+	t.Parallel()
+
+	serverHeaders := &conf_v1.ResponseHeaders{
+		Add: []conf_v1.AddHeader{
+			{
+				Header: conf_v1.Header{Name: "Server-Timing", Value: "total;dur=${request_time}"},
+				Always: true,
 			},
 		},
-		Endpoints: map[string][]string{
-			"tea/tea-svc:80": {
-				"10.0.0.30:80",
+	}
+	expectedServerAddHeaders := []version2.AddHeader{
+		{
+			Header: version2.Header{Name: "Server-Timing", Value: "total;dur=${request_time}"},
+			Always: true,
+		},
+	}
+
+	tests := []struct {
+		msg                        string
+		proxy                      *conf_v1.ActionProxy
+		addHeaderInherit           string
+		expectedLocationAddHeaders []version2.AddHeader
+	}{
+		{
+			msg: "server headers only",
+			proxy: &conf_v1.ActionProxy{
+				Upstream: "tea",
 			},
+			expectedLocationAddHeaders: nil,
 		},
-		VirtualServerRoutes: []*conf_v1.VirtualServerRoute{
-			{
-				ObjectMeta: meta_v1.ObjectMeta{
-					Name:      "tea",
-					Namespace: "default",
+		{
+			msg: "server and location headers",
+			proxy: &conf_v1.ActionProxy{
+				Upstream: "tea",
+				ResponseHeaders: &conf_v1.ProxyResponseHeaders{
+					Add: []conf_v1.AddHeader{
+						{
+							Header: conf_v1.Header{Name: "X-Frame-Options", Value: "DENY"},
+						},
+					},
 				},
-				Spec: conf_v1.VirtualServerRouteSpec{
-					Host: "cafe.example.com",
-					Upstreams: []conf_v1.Upstream{
+			},
+			expectedLocationAddHeaders: []version2.AddHeader{
+				{
+					Header: version2.Header{Name: "X-Frame-Options", Value: "DENY"},
+				},
+				{
+					Header: version2.Header{Name: "Server-Timing", Value: "total;dur=${request_time}"},
+					Always: true,
+				},
+			},
+		},
+		{
+			msg: "location header overrides server header",
+			proxy: &conf_v1.ActionProxy{
+				Upstream: "tea",
+				ResponseHeaders: &conf_v1.ProxyResponseHeaders{
+					Add: []conf_v1.AddHeader{
 						{
-							Name:    "tea",
-							Service: "tea/tea-svc",
-							Port:    80,
+							Header: conf_v1.Header{Name: "server-timing", Value: "app"},
 						},
 					},
-					Subroutes: []conf_v1.Route{
+				},
+			},
+			expectedLocationAddHeaders: []version2.AddHeader{
+				{
+					Header: version2.Header{Name: "server-timing", Value: "app"},
+				},
+			},
+		},
+		{
+			msg: "location headers with add-header-inherit",
+			proxy: &conf_v1.ActionProxy{
+				Upstream: "tea",
+				ResponseHeaders: &conf_v1.ProxyResponseHeaders{
+					Add: []conf_v1.AddHeader{
 						{
-							Path: "/tea",
-							Action: &conf_v1.Action{
-								Pass: "tea",
-							},
+							Header: conf_v1.Header{Name: "X-Frame-Options", Value: "DENY"},
 						},
 					},
 				},
 			},
+			addHeaderInherit: "merge",
+			expectedLocationAddHeaders: []version2.AddHeader{
+				{
+					Header: version2.Header{Name: "X-Frame-Options", Value: "DENY"},
+				},
+			},
 		},
 	}
 
-	vsc := newVirtualServerConfigurator(&baseCfgParams, false, false, &StaticConfigParams{}, false, nil)
-	result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
-	if len(warnings) != 0 {
-		t.Errorf("GenerateVirtualServerConfig returned warnings: %v", warnings)
-	}
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			t.Parallel()
 
-	expected := version2.VirtualServerConfig{
-		Upstreams: []version2.Upstream{
-			{
-				UpstreamLabels: version2.UpstreamLabels{
-					Service:           "tea/tea-svc",
-					ResourceType:      "virtualserverroute",
-					ResourceName:      "tea",
-					ResourceNamespace: "default",
+			virtualServerEx := VirtualServerEx{
+				VirtualServer: &conf_v1.VirtualServer{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "cafe",
+						Namespace: "default",
+					},
+					Spec: conf_v1.VirtualServerSpec{
+						Host:            "cafe.example.com",
+						ResponseHeaders: serverHeaders,
+						Upstreams: []conf_v1.Upstream{
+							{
+								Name:    "tea",
+								Service: "tea-svc",
+								Port:    80,
+							},
+						},
+						Routes: []conf_v1.Route{
+							{
+								Path: "/tea",
+								Action: &conf_v1.Action{
+									Proxy: test.proxy,
+								},
+								AddHeaderInherit: test.addHeaderInherit,
+							},
+						},
+					},
 				},
-				Name: "vs_default_cafe_vsr_default_tea_tea",
-				Servers: []version2.UpstreamServer{
-					{
-						Address: "10.0.0.30:80",
+				Endpoints: map[string][]string{
+					"default/tea-svc:80": {
+						"10.0.0.20:80",
 					},
 				},
-				Keepalive: 16,
+			}
+
+			vsc := newVirtualServerConfigurator(&ConfigParams{Context: context.Background()}, false, false, &StaticConfigParams{}, false, &fakeBV)
+			result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+			if len(warnings) != 0 {
+				t.Errorf("GenerateVirtualServerConfig() returned unexpected warnings: %v", warnings)
+			}
+
+			if diff := cmp.Diff(expectedServerAddHeaders, result.Server.AddHeaders); diff != "" {
+				t.Errorf("GenerateVirtualServerConfig() returned unexpected server add headers (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(test.expectedLocationAddHeaders, result.Server.Locations[0].AddHeaders); diff != "" {
+				t.Errorf("GenerateVirtualServerConfig() returned unexpected location add headers (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGenerateVirtualServerConfigWithErrorLog(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		msg                     string
+		level                   string
+		destination             string
+		expectedErrorLog        *version2.ErrorLog
+		expectedNGINXDebugLevel string
+		expectedWarnings        []string
+	}{
+		{
+			msg:                     "no error log level",
+			expectedErrorLog:        nil,
+			expectedNGINXDebugLevel: "notice",
+		},
+		{
+			msg:   "debug level",
+			level: "debug",
+			expectedErrorLog: &version2.ErrorLog{
+				Destination: "stderr",
+				Level:       "debug",
 			},
+			expectedNGINXDebugLevel: "debug",
 		},
-		HTTPSnippets:  []string{},
-		LimitReqZones: []version2.LimitReqZone{},
-		Server: version2.Server{
-			ServerName:      "cafe.example.com",
-			StatusZone:      "cafe.example.com",
-			VSNamespace:     "default",
-			VSName:          "cafe",
-			ProxyProtocol:   true,
-			ServerTokens:    "off",
-			SetRealIPFrom:   []string{"0.0.0.0/0"},
-			RealIPHeader:    "X-Real-IP",
-			RealIPRecursive: true,
-			Snippets:        []string{"# server snippet"},
-			Locations: []version2.Location{
-				{
-					Path:                     "/tea",
-					ProxyPass:                "http://vs_default_cafe_vsr_default_tea_tea",
-					ProxyNextUpstream:        "error timeout",
-					ProxyNextUpstreamTimeout: "0s",
-					ProxyPassRequestHeaders:  true,
-					ProxySetHeaders: []version2.Header{
-						{
-							Name:  "Host",
-							Value: "$host",
-						},
-					},
-					HasKeepalive: true,
-					ProxySSLName: "tea-svc.tea.svc",
-					ServiceName:  "tea-svc",
-					IsVSR:        true,
-					VSRName:      "tea",
-					VSRNamespace: "default",
-				},
+		{
+			msg:         "warn level with syslog destination",
+			level:       "warn",
+			destination: "syslog:server=syslog-svc.default:514",
+			expectedErrorLog: &version2.ErrorLog{
+				Destination: "syslog:server=syslog-svc.default:514",
+				Level:       "warn",
+			},
+			expectedNGINXDebugLevel: "warn",
+		},
+		{
+			msg:                     "invalid level",
+			level:                   "verbose",
+			expectedErrorLog:        nil,
+			expectedNGINXDebugLevel: "notice",
+			expectedWarnings: []string{
+				`Invalid error log level "verbose": must be one of: debug, info, notice, warn, error, crit, alert, emerg. The error log level of the main context is used`,
 			},
 		},
 	}
 
-	if !cmp.Equal(expected, result) {
-		t.Error(cmp.Diff(expected, result))
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			t.Parallel()
+
+			virtualServerEx := VirtualServerEx{
+				VirtualServer: &conf_v1.VirtualServer{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "cafe",
+						Namespace: "default",
+					},
+					Spec: conf_v1.VirtualServerSpec{
+						Host:                "cafe.example.com",
+						ErrorLogLevel:       test.level,
+						ErrorLogDestination: test.destination,
+						Upstreams: []conf_v1.Upstream{
+							{
+								Name:    "tea",
+								Service: "tea-svc",
+								Port:    80,
+							},
+						},
+						Routes: []conf_v1.Route{
+							{
+								Path: "/tea",
+								Action: &conf_v1.Action{
+									Pass: "tea",
+								},
+							},
+						},
+					},
+				},
+				Endpoints: map[string][]string{
+					"default/tea-svc:80": {
+						"10.0.0.20:80",
+					},
+				},
+			}
+
+			cfgParams := &ConfigParams{Context: context.Background(), MainErrorLogLevel: "notice"}
+			vsc := newVirtualServerConfigurator(cfgParams, false, false, &StaticConfigParams{}, false, &fakeBV)
+			result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+
+			if diff := cmp.Diff(test.expectedErrorLog, result.Server.ErrorLog); diff != "" {
+				t.Errorf("GenerateVirtualServerConfig() returned unexpected error log (-want +got):\n%s", diff)
+			}
+			if result.Server.NGINXDebugLevel != test.expectedNGINXDebugLevel {
+				t.Errorf("GenerateVirtualServerConfig() returned NGINXDebugLevel %q but expected %q", result.Server.NGINXDebugLevel, test.expectedNGINXDebugLevel)
+			}
+			if diff := cmp.Diff(test.expectedWarnings, warnings[virtualServerEx.VirtualServer]); diff != "" {
+				t.Errorf("GenerateVirtualServerConfig() returned unexpected warnings (-want +got):\n%s", diff)
+			}
+		})
 	}
 }
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -242,6 +244,54 @@ func TestUpstreamNamerForVirtualServerRoute(t *testing.T) {
 	}
 }
 
+func TestUpstreamNamerWithShortNames(t *testing.T) {
+	t.Parallel()
+	longName := strings.Repeat("long-name", 7)
+	virtualServer := conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      longName,
+			Namespace: longName,
+		},
+	}
+	virtualServerRoute := conf_v1.VirtualServerRoute{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      longName,
+			Namespace: longName,
+		},
+	}
+	upstream := "test"
+
+	vsNamer := newUpstreamNamerForVirtualServer(&virtualServer, true)
+	vsrNamer := newUpstreamNamerForVirtualServerRoute(&virtualServer, &virtualServerRoute, true)
+
+	for _, namer := range []*upstreamNamer{vsNamer, vsrNamer} {
+		result := namer.GetNameForUpstream(upstream)
+		if !regexp.MustCompile(`^vs_[0-9a-f]{8}_test$`).MatchString(result) {
+			t.Errorf("GetNameForUpstream() returned %q but expected a short name", result)
+		}
+	}
+
+	if vsNamer.GetNameForUpstream(upstream) == vsrNamer.GetNameForUpstream(upstream) {
+		t.Errorf("GetNameForUpstream() returned the same name %q for the VirtualServer and the VirtualServerRoute", vsNamer.GetNameForUpstream(upstream))
+	}
+
+	otherVirtualServer := virtualServer
+	otherVirtualServer.Name = longName + "-other"
+	otherNamer := newUpstreamNamerForVirtualServer(&otherVirtualServer, true)
+	if vsNamer.GetNameForUpstream(upstream) == otherNamer.GetNameForUpstream(upstream) {
+		t.Errorf("GetNameForUpstream() returned the same name %q for different VirtualServers", vsNamer.GetNameForUpstream(upstream))
+	}
+
+	expectedFullName := NewUpstreamNamerForVirtualServerRoute(&virtualServer, &virtualServerRoute).GetNameForUpstream(upstream)
+	if result := vsrNamer.GetFullNameForUpstream(upstream); result != expectedFullName {
+		t.Errorf("GetFullNameForUpstream() returned %q but expected %q", result, expectedFullName)
+	}
+
+	if result, expected := newUpstreamNamerForVirtualServer(&virtualServer, false).GetFullNameForUpstream(upstream), NewUpstreamNamerForVirtualServer(&virtualServer).GetNameForUpstream(upstream); result != expected {
+		t.Errorf("GetFullNameForUpstream() returned %q but expected %q", result, expected)
+	}
+}
+
 func TestVariableNamerSafeNsName(t *testing.T) {
 	t.Parallel()
 	virtualServer := conf_v1.VirtualServer{
@@ -630,7 +680,7 @@ func TestGenerateUpstream(t *testing.T) {
 	}
 
 	vsc := newVirtualServerConfigurator(&cfgParams, false, false, &StaticConfigParams{}, false, &fakeBV)
-	result := vsc.generateUpstream(nil, name, upstream, false, endpoints, backupEndpoints)
+	result := vsc.generateUpstream(nil, name, upstream, false, endpoints, backupEndpoints, nil)
 	if !reflect.DeepEqual(result, expected) {
 		t.Errorf("generateUpstream() returned %v but expected %v", result, expected)
 	}
@@ -640,6 +690,170 @@ func TestGenerateUpstream(t *testing.T) {
 	}
 }
 
+func TestGenerateUpstreamWithDownEndpoints(t *testing.T) {
+	t.Parallel()
+	name := "test-upstream"
+	upstream := conf_v1.Upstream{Service: name, Port: 80}
+	endpoints := []string{
+		"192.168.10.30:8080",
+		"192.168.10.10:8080",
+		"192.168.10.20:8080",
+	}
+	downEndpoints := []string{
+		"192.168.10.20:8080",
+		"192.168.10.40:8080",
+	}
+	cfgParams := ConfigParams{
+		Context:          context.Background(),
+		LBMethod:         "random",
+		MaxFails:         1,
+		FailTimeout:      "10s",
+		UpstreamZoneSize: "256k",
+	}
+
+	expected := []version2.UpstreamServer{
+		{
+			Address: "192.168.10.10:8080",
+		},
+		{
+			Address: "192.168.10.20:8080",
+			Down:    true,
+		},
+		{
+			Address: "192.168.10.30:8080",
+		},
+	}
+
+	vsc := newVirtualServerConfigurator(&cfgParams, false, false, &StaticConfigParams{}, false, &fakeBV)
+	result := vsc.generateUpstream(nil, name, upstream, false, endpoints, nil, downEndpoints)
+	if diff := cmp.Diff(expected, result.Servers); diff != "" {
+		t.Errorf("generateUpstream() returned unexpected servers (-want +got):\n%s", diff)
+	}
+}
+
+func TestGenerateTerminatingEndpointsForUpstream(t *testing.T) {
+	t.Parallel()
+	virtualServerEx := &VirtualServerEx{
+		TerminatingEndpoints: map[string][]string{
+			"default/tea-svc:80": {
+				"10.0.0.21:80",
+			},
+		},
+	}
+
+	tests := []struct {
+		upstream conf_v1.Upstream
+		isPlus   bool
+		expected []string
+		msg      string
+	}{
+		{
+			upstream: conf_v1.Upstream{Service: "tea-svc", Port: 80},
+			isPlus:   true,
+			expected: nil,
+			msg:      "terminating endpoints not kept",
+		},
+		{
+			upstream: conf_v1.Upstream{Service: "tea-svc", Port: 80, KeepTerminatingEndpoints: true},
+			expected: []string{"10.0.0.21:80"},
+			msg:      "keep terminating endpoints",
+		},
+		{
+			upstream: conf_v1.Upstream{Service: "tea-svc", Port: 80, Drain: true},
+			isPlus:   true,
+			expected: []string{"10.0.0.21:80"},
+			msg:      "drain in plus",
+		},
+		{
+			upstream: conf_v1.Upstream{Service: "tea-svc", Port: 80, Drain: true},
+			expected: nil,
+			msg:      "drain in oss",
+		},
+		{
+			upstream: conf_v1.Upstream{Service: "coffee-svc", Port: 80, KeepTerminatingEndpoints: true},
+			expected: nil,
+			msg:      "terminating endpoints of another service",
+		},
+		{
+			upstream: conf_v1.Upstream{Service: "tea-svc", Port: 8080, KeepTerminatingEndpoints: true},
+			expected: nil,
+			msg:      "terminating endpoints of another port",
+		},
+	}
+
+	for _, test := range tests {
+		vsc := newVirtualServerConfigurator(&ConfigParams{Context: context.Background()}, test.isPlus, false, &StaticConfigParams{}, false, &fakeBV)
+		result := vsc.generateTerminatingEndpointsForUpstream("default", test.upstream, virtualServerEx)
+		if diff := cmp.Diff(test.expected, result); diff != "" {
+			t.Errorf("generateTerminatingEndpointsForUpstream() returned unexpected result for the case of %s (-want +got):\n%s", test.msg, diff)
+		}
+	}
+}
+
+func TestGenerateUpstreamWithDrain(t *testing.T) {
+	t.Parallel()
+	name := "test-upstream"
+	upstream := conf_v1.Upstream{Service: name, Port: 80, Drain: true}
+	endpoints := []string{
+		"192.168.10.10:8080",
+		"192.168.10.20:8080",
+	}
+	downEndpoints := []string{
+		"192.168.10.20:8080",
+	}
+	cfgParams := ConfigParams{
+		Context:          context.Background(),
+		LBMethod:         "random",
+		MaxFails:         1,
+		FailTimeout:      "10s",
+		UpstreamZoneSize: "256k",
+	}
+
+	tests := []struct {
+		isPlus   bool
+		expected []version2.UpstreamServer
+		msg      string
+	}{
+		{
+			isPlus: true,
+			expected: []version2.UpstreamServer{
+				{
+					Address: "192.168.10.10:8080",
+				},
+				{
+					Address: "192.168.10.20:8080",
+					Drain:   true,
+				},
+			},
+			msg: "plus",
+		},
+		{
+			isPlus: false,
+			expected: []version2.UpstreamServer{
+				{
+					Address: "192.168.10.10:8080",
+				},
+				{
+					Address: "192.168.10.20:8080",
+					Down:    true,
+				},
+			},
+			msg: "oss",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			t.Parallel()
+			vsc := newVirtualServerConfigurator(&cfgParams, test.isPlus, false, &StaticConfigParams{}, false, &fakeBV)
+			result := vsc.generateUpstream(nil, name, upstream, false, endpoints, nil, downEndpoints)
+			if diff := cmp.Diff(test.expected, result.Servers); diff != "" {
+				t.Errorf("generateUpstream() returned unexpected servers (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestGenerateUpstreamWithKeepalive(t *testing.T) {
 	t.Parallel()
 	name := "test-upstream"
@@ -707,7 +921,7 @@ func TestGenerateUpstreamWithKeepalive(t *testing.T) {
 
 	for _, test := range tests {
 		vsc := newVirtualServerConfigurator(test.cfgParams, false, false, &StaticConfigParams{}, false, &fakeBV)
-		result := vsc.generateUpstream(nil, name, test.upstream, false, endpoints, nil)
+		result := vsc.generateUpstream(nil, name, test.upstream, false, endpoints, nil, nil)
 		if !reflect.DeepEqual(result, test.expected) {
 			t.Errorf("generateUpstream() returned %v but expected %v for the case of %v", result, test.expected, test.msg)
 		}
@@ -739,7 +953,7 @@ func TestGenerateUpstreamForExternalNameService(t *testing.T) {
 	}
 
 	vsc := newVirtualServerConfigurator(&cfgParams, true, true, &StaticConfigParams{}, false, &fakeBV)
-	result := vsc.generateUpstream(nil, name, upstream, true, endpoints, nil)
+	result := vsc.generateUpstream(nil, name, upstream, true, endpoints, nil, nil)
 	if !reflect.DeepEqual(result, expected) {
 		t.Errorf("generateUpstream() returned %v but expected %v", result, expected)
 	}
@@ -749,133 +963,755 @@ func TestGenerateUpstreamForExternalNameService(t *testing.T) {
 	}
 }
 
-func TestGenerateUpstreamWithNTLM(t *testing.T) {
+func TestGenerateUpstreamForExternalNameServiceWithKeepalive(t *testing.T) {
 	t.Parallel()
 	name := "test-upstream"
-	upstream := conf_v1.Upstream{Service: name, Port: 80, NTLM: true}
-	endpoints := []string{
-		"192.168.10.10:8080",
-	}
-	cfgParams := ConfigParams{
-		Context:          context.Background(),
-		LBMethod:         "random",
-		MaxFails:         1,
-		MaxConns:         0,
-		FailTimeout:      "10s",
-		Keepalive:        21,
-		UpstreamZoneSize: "256k",
+	endpoints := []string{"example.com"}
+	owner := &conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
 	}
 
-	expected := version2.Upstream{
-		Name: "test-upstream",
-		UpstreamLabels: version2.UpstreamLabels{
-			Service: "test-upstream",
-		},
-		Servers: []version2.UpstreamServer{
-			{
-				Address: "192.168.10.10:8080",
+	tests := []struct {
+		upstream              conf_v1.Upstream
+		expectedKeepaliveTime string
+		expectedWarnings      []string
+		msg                   string
+	}{
+		{
+			upstream:              conf_v1.Upstream{Name: "tea", Service: name, Keepalive: new(32)},
+			expectedKeepaliveTime: "",
+			expectedWarnings: []string{
+				"Keepalive connections of upstream tea to resolved servers are not bounded by keepalive-time and can be reused for up to 1h after the addresses change",
 			},
+			msg: "keepalive without keepalive-time",
+		},
+		{
+			upstream:              conf_v1.Upstream{Name: "tea", Service: name, Keepalive: new(32), KeepaliveTime: "5m"},
+			expectedKeepaliveTime: "5m",
+			expectedWarnings:      nil,
+			msg:                   "keepalive with keepalive-time",
+		},
+		{
+			upstream:              conf_v1.Upstream{Name: "tea", Service: name, Keepalive: new(0)},
+			expectedKeepaliveTime: "",
+			expectedWarnings:      nil,
+			msg:                   "keepalive disabled",
 		},
-		MaxFails:         1,
-		MaxConns:         0,
-		FailTimeout:      "10s",
-		LBMethod:         "random",
-		Keepalive:        21,
-		UpstreamZoneSize: "256k",
-		NTLM:             true,
-	}
-
-	vsc := newVirtualServerConfigurator(&cfgParams, true, false, &StaticConfigParams{}, false, &fakeBV)
-	result := vsc.generateUpstream(nil, name, upstream, false, endpoints, nil)
-	if !reflect.DeepEqual(result, expected) {
-		t.Errorf("generateUpstream() returned %v but expected %v", result, expected)
 	}
 
-	if len(vsc.warnings) != 0 {
-		t.Errorf("generateUpstream returned warnings for %v", upstream)
+	for _, test := range tests {
+		vsc := newVirtualServerConfigurator(&ConfigParams{Context: context.Background()}, true, true, &StaticConfigParams{}, false, &fakeBV)
+		result := vsc.generateUpstream(owner, name, test.upstream, true, endpoints, nil, nil)
+		if result.KeepaliveTime != test.expectedKeepaliveTime {
+			t.Errorf("generateUpstream() returned keepalive time %q but expected %q for the case of %v", result.KeepaliveTime, test.expectedKeepaliveTime, test.msg)
+		}
+		if diff := cmp.Diff(test.expectedWarnings, vsc.warnings[owner]); diff != "" {
+			t.Errorf("generateUpstream() returned unexpected warnings for the case of %v (-want +got):\n%s", test.msg, diff)
+		}
 	}
 }
 
-func TestGenerateProxyPass(t *testing.T) {
+func TestGenerateUpstreamForExternalNameServiceWithResolveInterval(t *testing.T) {
 	t.Parallel()
+	name := "test-upstream"
+	owner := &conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
+	}
+	upstream := conf_v1.Upstream{Name: "tea", Service: "tea-svc", ResolveInterval: "5s"}
+
 	tests := []struct {
-		tlsEnabled   bool
-		upstreamName string
-		internal     bool
-		expected     string
+		isPlus            bool
+		isExternalNameSvc bool
+		resolverAddresses []string
+		expectedResolver  *version2.UpstreamResolver
+		expectedWarnings  []string
+		msg               string
 	}{
 		{
-			tlsEnabled:   false,
-			upstreamName: "test-upstream",
-			internal:     false,
-			expected:     "http://test-upstream",
+			isPlus:            true,
+			isExternalNameSvc: true,
+			resolverAddresses: []string{"kube-dns.kube-system.svc.cluster.local"},
+			expectedResolver: &version2.UpstreamResolver{
+				Addresses: []string{"kube-dns.kube-system.svc.cluster.local"},
+				Valid:     "5s",
+			},
+			expectedWarnings: nil,
+			msg:              "ExternalName service in NGINX Plus",
 		},
 		{
-			tlsEnabled:   true,
-			upstreamName: "test-upstream",
-			internal:     false,
-			expected:     "https://test-upstream",
+			isPlus:            true,
+			isExternalNameSvc: true,
+			resolverAddresses: nil,
+			expectedResolver:  nil,
+			expectedWarnings: []string{
+				"Resolve interval of upstream tea is ignored, because the resolver-addresses ConfigMap key is not set",
+			},
+			msg: "ExternalName service in NGINX Plus without resolver addresses",
 		},
 		{
-			tlsEnabled:   false,
-			upstreamName: "test-upstream",
-			internal:     true,
-			expected:     "http://test-upstream$request_uri",
+			isPlus:            true,
+			isExternalNameSvc: false,
+			resolverAddresses: []string{"kube-dns.kube-system.svc.cluster.local"},
+			expectedResolver:  nil,
+			expectedWarnings: []string{
+				"Resolve interval of upstream tea is ignored, because the service tea-svc is not of the type ExternalName",
+			},
+			msg: "ClusterIP service in NGINX Plus",
 		},
 		{
-			tlsEnabled:   true,
-			upstreamName: "test-upstream",
-			internal:     true,
-			expected:     "https://test-upstream$request_uri",
+			isPlus:            false,
+			isExternalNameSvc: true,
+			resolverAddresses: []string{"kube-dns.kube-system.svc.cluster.local"},
+			expectedResolver:  nil,
+			expectedWarnings: []string{
+				"Resolve interval of upstream tea is ignored, because it is supported only in NGINX Plus",
+			},
+			msg: "ExternalName service in NGINX",
 		},
 	}
 
 	for _, test := range tests {
-		result := generateProxyPass(test.tlsEnabled, test.upstreamName, test.internal, nil)
-		if result != test.expected {
-			t.Errorf("generateProxyPass(%v, %v, %v) returned %v but expected %v", test.tlsEnabled, test.upstreamName, test.internal, result, test.expected)
+		cfgParams := &ConfigParams{Context: context.Background(), ResolverAddresses: test.resolverAddresses}
+		vsc := newVirtualServerConfigurator(cfgParams, test.isPlus, true, &StaticConfigParams{}, false, &fakeBV)
+		result := vsc.generateUpstream(owner, name, upstream, test.isExternalNameSvc, []string{"example.com:80"}, nil, nil)
+		if diff := cmp.Diff(test.expectedResolver, result.Resolver); diff != "" {
+			t.Errorf("generateUpstream() returned unexpected resolver for the case of %v (-want +got):\n%s", test.msg, diff)
+		}
+		if diff := cmp.Diff(test.expectedWarnings, vsc.warnings[owner]); diff != "" {
+			t.Errorf("generateUpstream() returned unexpected warnings for the case of %v (-want +got):\n%s", test.msg, diff)
 		}
 	}
 }
 
-func TestGenerateProxyPassProtocol(t *testing.T) {
+func TestGenerateUpstreamWithHashLBMethod(t *testing.T) {
 	t.Parallel()
+	name := "test-upstream"
+	endpoints := []string{"192.168.10.10:8080"}
+	owner := &conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
+	}
+
 	tests := []struct {
-		upstream conf_v1.Upstream
-		expected string
+		upstream         conf_v1.Upstream
+		defaultLBMethod  string
+		expectedLBMethod string
+		expectedWarnings []string
+		msg              string
 	}{
 		{
-			upstream: conf_v1.Upstream{},
-			expected: "http",
+			upstream:         conf_v1.Upstream{Name: "cache", Service: name, LBMethod: "hash $arg_key consistent"},
+			expectedLBMethod: "hash $arg_key consistent",
+			expectedWarnings: nil,
+			msg:              "consistent hash",
 		},
 		{
-			upstream: conf_v1.Upstream{
-				TLS: conf_v1.UpstreamTLS{
-					Enable: true,
-				},
+			upstream:         conf_v1.Upstream{Name: "cache", Service: name},
+			defaultLBMethod:  "hash $request_uri",
+			expectedLBMethod: "hash $request_uri",
+			expectedWarnings: nil,
+			msg:              "default hash",
+		},
+		{
+			upstream:         conf_v1.Upstream{Name: "cache", Service: name, LBMethod: "hash consistent"},
+			expectedLBMethod: "",
+			expectedWarnings: []string{
+				`Upstream cache uses the round robin load balancing method instead: invalid load balancing method: "hash consistent": the hash key is missing`,
 			},
-			expected: "https",
+			msg: "missing hash key",
+		},
+		{
+			upstream:         conf_v1.Upstream{Name: "cache", Service: name, LBMethod: "hash $arg_key; consistent"},
+			expectedLBMethod: "",
+			expectedWarnings: []string{
+				`Upstream cache uses the round robin load balancing method instead: invalid load balancing method: "hash $arg_key; consistent": invalid hash key "$arg_key;"`,
+			},
+			msg: "invalid hash key",
+		},
+		{
+			upstream:         conf_v1.Upstream{Name: "cache", Service: name, LBMethod: "hash key"},
+			expectedLBMethod: "hash key",
+			expectedWarnings: []string{
+				`The hash key "key" of upstream cache contains no variables, so all requests are passed to the same server`,
+			},
+			msg: "constant hash key",
 		},
 	}
 
 	for _, test := range tests {
-		result := generateProxyPassProtocol(test.upstream.TLS.Enable)
-		if result != test.expected {
-			t.Errorf("generateProxyPassProtocol(%v) returned %v but expected %v", test.upstream.TLS.Enable, result, test.expected)
+		cfgParams := ConfigParams{Context: context.Background(), LBMethod: test.defaultLBMethod}
+		vsc := newVirtualServerConfigurator(&cfgParams, false, false, &StaticConfigParams{}, false, &fakeBV)
+		result := vsc.generateUpstream(owner, name, test.upstream, false, endpoints, nil, nil)
+		if result.LBMethod != test.expectedLBMethod {
+			t.Errorf("generateUpstream() returned lb method %q but expected %q for the case of %v", result.LBMethod, test.expectedLBMethod, test.msg)
+		}
+		if diff := cmp.Diff(test.expectedWarnings, vsc.warnings[owner]); diff != "" {
+			t.Errorf("generateUpstream() returned unexpected warnings for the case of %v (-want +got):\n%s", test.msg, diff)
 		}
 	}
 }
 
-func TestGenerateGRPCPass(t *testing.T) {
+func TestGenerateUpstreamWithTLSSessionReuseWithoutTLS(t *testing.T) {
 	t.Parallel()
-	tests := []struct {
-		grpcEnabled  bool
-		tlsEnabled   bool
-		upstreamName string
-		expected     string
-	}{
-		{
-			grpcEnabled:  false,
+	name := "test-upstream"
+	endpoints := []string{"192.168.10.10:8080"}
+	owner := &conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
+	}
+	upstream := conf_v1.Upstream{Name: "tea", Service: name, TLS: conf_v1.UpstreamTLS{SessionReuse: new(false)}}
+	expectedWarnings := []string{
+		"TLS session reuse of upstream tea is ignored because TLS is not enabled for the upstream",
+	}
+
+	vsc := newVirtualServerConfigurator(&ConfigParams{Context: context.Background()}, false, false, &StaticConfigParams{}, false, &fakeBV)
+	vsc.generateUpstream(owner, name, upstream, false, endpoints, nil, nil)
+	if diff := cmp.Diff(expectedWarnings, vsc.warnings[owner]); diff != "" {
+		t.Errorf("generateUpstream() returned unexpected warnings (-want +got):\n%s", diff)
+	}
+}
+
+func TestRemoveProxySSLSessionReuseWithEgressMTLS(t *testing.T) {
+	t.Parallel()
+	owner := &conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
+	}
+	locations := []version2.Location{
+		{Path: "/tea", ProxySSLSessionReuse: "off", EgressMTLS: &version2.EgressMTLS{SessionReuse: true}},
+		{Path: "/coffee", ProxySSLSessionReuse: "off"},
+		{Path: "/juice", EgressMTLS: &version2.EgressMTLS{SessionReuse: true}},
+	}
+	expectedLocations := []version2.Location{
+		{Path: "/tea", EgressMTLS: &version2.EgressMTLS{SessionReuse: true}},
+		{Path: "/coffee", ProxySSLSessionReuse: "off"},
+		{Path: "/juice", EgressMTLS: &version2.EgressMTLS{SessionReuse: true}},
+	}
+	expectedWarnings := []string{
+		"TLS session reuse of the upstream of location /tea is ignored because the EgressMTLS policy of the location configures the session reuse",
+	}
+
+	vsc := newVirtualServerConfigurator(&ConfigParams{Context: context.Background()}, false, false, &StaticConfigParams{}, false, &fakeBV)
+	vsc.removeProxySSLSessionReuseWithEgressMTLS(owner, locations)
+	if diff := cmp.Diff(expectedLocations, locations); diff != "" {
+		t.Errorf("removeProxySSLSessionReuseWithEgressMTLS() returned unexpected locations (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(expectedWarnings, vsc.warnings[owner]); diff != "" {
+		t.Errorf("removeProxySSLSessionReuseWithEgressMTLS() returned unexpected warnings (-want +got):\n%s", diff)
+	}
+}
+
+func TestGenerateUpstreamWithUpstreamZoneOmitMaxServers(t *testing.T) {
+	t.Parallel()
+	owner := &conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
+	}
+	upstream := conf_v1.Upstream{Name: "tea", Service: "tea-svc"}
+
+	tests := []struct {
+		isPlus            bool
+		isExternalNameSvc bool
+		endpoints         []string
+		omitMaxServers    int
+		expectedZoneSize  string
+		msg               string
+	}{
+		{
+			endpoints:        []string{"10.0.0.20:80"},
+			omitMaxServers:   1,
+			expectedZoneSize: "0",
+			msg:              "OSS upstream with a single server",
+		},
+		{
+			endpoints:        []string{"10.0.0.20:80", "10.0.0.21:80"},
+			omitMaxServers:   1,
+			expectedZoneSize: "256k",
+			msg:              "OSS upstream with servers above the threshold",
+		},
+		{
+			endpoints:        []string{"10.0.0.20:80"},
+			omitMaxServers:   0,
+			expectedZoneSize: "256k",
+			msg:              "OSS upstream without a threshold",
+		},
+		{
+			isExternalNameSvc: true,
+			endpoints:         []string{"example.com"},
+			omitMaxServers:    1,
+			expectedZoneSize:  "256k",
+			msg:               "OSS upstream for an ExternalName service",
+		},
+		{
+			isPlus:           true,
+			endpoints:        []string{"10.0.0.20:80"},
+			omitMaxServers:   1,
+			expectedZoneSize: "256k",
+			msg:              "Plus upstream with a single server",
+		},
+	}
+
+	for _, test := range tests {
+		cfgParams := &ConfigParams{Context: context.Background(), UpstreamZoneSize: "256k", UpstreamZoneOmitMaxServers: test.omitMaxServers}
+		vsc := newVirtualServerConfigurator(cfgParams, test.isPlus, test.isExternalNameSvc, &StaticConfigParams{}, false, &fakeBV)
+		result := vsc.generateUpstream(owner, "vs_default_cafe_tea", upstream, test.isExternalNameSvc, test.endpoints, nil, nil)
+		if result.UpstreamZoneSize != test.expectedZoneSize {
+			t.Errorf("generateUpstream() returned zone size %q but expected %q for the case of %v", result.UpstreamZoneSize, test.expectedZoneSize, test.msg)
+		}
+	}
+}
+
+func TestGenerateUpstreamWithUnlimitedNextUpstream(t *testing.T) {
+	t.Parallel()
+	name := "test-upstream"
+	endpoints := []string{"10.0.0.20:80"}
+	owner := &conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
+	}
+
+	tests := []struct {
+		upstream         conf_v1.Upstream
+		expectedWarnings []string
+		msg              string
+	}{
+		{
+			upstream: conf_v1.Upstream{Name: "tea", Service: name, ProxyNextUpstream: "error timeout http_502"},
+			expectedWarnings: []string{
+				"Requests to upstream tea are passed to the next server in the cases of next-upstream until all servers have been tried, as neither next-upstream-timeout nor next-upstream-tries limits the retries",
+			},
+			msg: "next-upstream without limits",
+		},
+		{
+			upstream: conf_v1.Upstream{Name: "tea", Service: name, ProxyNextUpstream: "error timeout", ProxyNextUpstreamTimeout: "0s"},
+			expectedWarnings: []string{
+				"Requests to upstream tea are passed to the next server in the cases of next-upstream until all servers have been tried, as neither next-upstream-timeout nor next-upstream-tries limits the retries",
+			},
+			msg: "next-upstream with zero next-upstream-timeout",
+		},
+		{
+			upstream:         conf_v1.Upstream{Name: "tea", Service: name, ProxyNextUpstream: "error timeout", ProxyNextUpstreamTimeout: "10s"},
+			expectedWarnings: nil,
+			msg:              "next-upstream bounded by next-upstream-timeout",
+		},
+		{
+			upstream:         conf_v1.Upstream{Name: "tea", Service: name, ProxyNextUpstream: "error timeout", ProxyNextUpstreamTries: 3},
+			expectedWarnings: nil,
+			msg:              "next-upstream bounded by next-upstream-tries",
+		},
+		{
+			upstream:         conf_v1.Upstream{Name: "tea", Service: name, ProxyNextUpstream: "off"},
+			expectedWarnings: nil,
+			msg:              "next-upstream off",
+		},
+		{
+			upstream: conf_v1.Upstream{Name: "tea", Service: name, ProxyNextUpstream: "error off"},
+			expectedWarnings: []string{
+				"The cases of next-upstream of upstream tea are ignored, as next-upstream is off",
+			},
+			msg: "next-upstream off with other cases",
+		},
+		{
+			upstream: conf_v1.Upstream{Name: "tea", Service: name, ProxyNextUpstream: "off", ProxyNextUpstreamTimeout: "10s", ProxyNextUpstreamTries: 3},
+			expectedWarnings: []string{
+				"The next-upstream-timeout and next-upstream-tries of upstream tea are ignored, as next-upstream is off",
+			},
+			msg: "next-upstream off with limits",
+		},
+		{
+			upstream:         conf_v1.Upstream{Name: "tea", Service: name},
+			expectedWarnings: nil,
+			msg:              "next-upstream not set",
+		},
+	}
+
+	for _, test := range tests {
+		vsc := newVirtualServerConfigurator(&ConfigParams{Context: context.Background()}, false, false, &StaticConfigParams{}, false, &fakeBV)
+		vsc.generateUpstream(owner, name, test.upstream, false, endpoints, nil, nil)
+		if diff := cmp.Diff(test.expectedWarnings, vsc.warnings[owner]); diff != "" {
+			t.Errorf("generateUpstream() returned unexpected warnings for the case of %v (-want +got):\n%s", test.msg, diff)
+		}
+	}
+}
+
+func TestGenerateUpstreamWithGRPCNextUpstream(t *testing.T) {
+	t.Parallel()
+	name := "test-upstream"
+	endpoints := []string{"10.0.0.20:80"}
+	owner := &conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
+	}
+
+	tests := []struct {
+		upstream         conf_v1.Upstream
+		expectedWarnings []string
+		msg              string
+	}{
+		{
+			upstream: conf_v1.Upstream{Name: "tea", Service: name, Type: "grpc", ProxyNextUpstream: "error timeout", ProxyNextUpstreamTries: 3},
+			expectedWarnings: []string{
+				"gRPC requests to upstream tea are passed to the next server in the cases of next-upstream only if they have not been sent to an upstream server yet, as gRPC uses the POST method. Add non_idempotent to next-upstream if the gRPC methods are idempotent",
+			},
+			msg: "gRPC next-upstream without non_idempotent",
+		},
+		{
+			upstream:         conf_v1.Upstream{Name: "tea", Service: name, Type: "grpc", ProxyNextUpstream: "error timeout non_idempotent", ProxyNextUpstreamTries: 3},
+			expectedWarnings: nil,
+			msg:              "gRPC next-upstream with non_idempotent",
+		},
+		{
+			upstream:         conf_v1.Upstream{Name: "tea", Service: name, Type: "grpc", ProxyNextUpstream: "off"},
+			expectedWarnings: nil,
+			msg:              "gRPC next-upstream off",
+		},
+		{
+			upstream:         conf_v1.Upstream{Name: "tea", Service: name, Type: "grpc"},
+			expectedWarnings: nil,
+			msg:              "gRPC next-upstream not set",
+		},
+		{
+			upstream:         conf_v1.Upstream{Name: "tea", Service: name, ProxyNextUpstream: "error timeout", ProxyNextUpstreamTries: 3},
+			expectedWarnings: nil,
+			msg:              "HTTP next-upstream",
+		},
+	}
+
+	for _, test := range tests {
+		vsc := newVirtualServerConfigurator(&ConfigParams{Context: context.Background()}, false, false, &StaticConfigParams{}, false, &fakeBV)
+		vsc.generateUpstream(owner, name, test.upstream, false, endpoints, nil, nil)
+		if diff := cmp.Diff(test.expectedWarnings, vsc.warnings[owner]); diff != "" {
+			t.Errorf("generateUpstream() returned unexpected warnings for the case of %v (-want +got):\n%s", test.msg, diff)
+		}
+	}
+}
+
+func TestGenerateLocationForProxyingGRPCNextUpstream(t *testing.T) {
+	t.Parallel()
+	cfgParams := ConfigParams{Context: context.Background()}
+	upstream := conf_v1.Upstream{
+		Type:                     "grpc",
+		ProxyNextUpstream:        "error timeout non_idempotent",
+		ProxyNextUpstreamTimeout: "5s",
+		ProxyNextUpstreamTries:   3,
+	}
+
+	result := generateLocationForProxying("/", "test-upstream", upstream, &cfgParams, nil, false, 0, "", nil, "", nil, false, "", "", "")
+	if result.GRPCPass != "grpc://test-upstream" {
+		t.Errorf("generateLocationForProxying() returned GRPCPass %q but expected %q", result.GRPCPass, "grpc://test-upstream")
+	}
+	if result.ProxyNextUpstream != "error timeout non_idempotent" {
+		t.Errorf("generateLocationForProxying() returned ProxyNextUpstream %q but expected %q", result.ProxyNextUpstream, "error timeout non_idempotent")
+	}
+	if result.ProxyNextUpstreamTimeout != "5s" {
+		t.Errorf("generateLocationForProxying() returned ProxyNextUpstreamTimeout %q but expected %q", result.ProxyNextUpstreamTimeout, "5s")
+	}
+	if result.ProxyNextUpstreamTries != 3 {
+		t.Errorf("generateLocationForProxying() returned ProxyNextUpstreamTries %d but expected %d", result.ProxyNextUpstreamTries, 3)
+	}
+}
+
+func TestGenerateLocationForProxyingNextUpstreamOff(t *testing.T) {
+	t.Parallel()
+	cfgParams := ConfigParams{Context: context.Background()}
+
+	tests := []struct {
+		upstream conf_v1.Upstream
+		msg      string
+	}{
+		{
+			upstream: conf_v1.Upstream{ProxyNextUpstream: "off"},
+			msg:      "next-upstream off",
+		},
+		{
+			upstream: conf_v1.Upstream{ProxyNextUpstream: "error off http_502"},
+			msg:      "next-upstream off with other cases",
+		},
+		{
+			upstream: conf_v1.Upstream{ProxyNextUpstream: "off", ProxyNextUpstreamTimeout: "10s", ProxyNextUpstreamTries: 3},
+			msg:      "next-upstream off with limits",
+		},
+	}
+
+	for _, test := range tests {
+		result := generateLocationForProxying("/", "test-upstream", test.upstream, &cfgParams, nil, false, 0, "", nil, "", nil, false, "", "", "")
+		if result.ProxyNextUpstream != "off" {
+			t.Errorf("generateLocationForProxying() returned ProxyNextUpstream %q but expected %q for the case of %v", result.ProxyNextUpstream, "off", test.msg)
+		}
+		if result.ProxyNextUpstreamTimeout != "" {
+			t.Errorf("generateLocationForProxying() returned ProxyNextUpstreamTimeout %q but expected it to be omitted for the case of %v", result.ProxyNextUpstreamTimeout, test.msg)
+		}
+	}
+}
+
+func TestGenerateProxyPassURLUpstreams(t *testing.T) {
+	t.Parallel()
+	namer := &upstreamNamer{prefix: "vs_default_cafe", namespace: "default"}
+	cfgParams := ConfigParams{Context: context.Background(), UpstreamZoneSize: "256k"}
+
+	tests := []struct {
+		proxyPassURL       string
+		isResolverEnabled  bool
+		expectedUpstream   version2.Upstream
+		expectedTLSEnabled bool
+		msg                string
+	}{
+		{
+			proxyPassURL:      "https://example.com",
+			isResolverEnabled: true,
+			expectedUpstream: version2.Upstream{
+				Name: "vs_default_cafe_" + generateProxyPassURLUpstreamName("https://example.com"),
+				Servers: []version2.UpstreamServer{
+					{
+						Address: "example.com:443",
+					},
+				},
+				Resolve:          true,
+				UpstreamZoneSize: "256k",
+			},
+			expectedTLSEnabled: true,
+			msg:                "external hostname with resolver",
+		},
+		{
+			proxyPassURL:      "http://203.0.113.10:8080",
+			isResolverEnabled: false,
+			expectedUpstream: version2.Upstream{
+				Name: "vs_default_cafe_" + generateProxyPassURLUpstreamName("http://203.0.113.10:8080"),
+				Servers: []version2.UpstreamServer{
+					{
+						Address: "203.0.113.10:8080",
+					},
+				},
+				UpstreamZoneSize: "256k",
+			},
+			expectedTLSEnabled: false,
+			msg:                "ip literal",
+		},
+	}
+
+	for _, test := range tests {
+		routes := []conf_v1.Route{
+			{
+				Path: "/",
+				Action: &conf_v1.Action{
+					ProxyPassURL: test.proxyPassURL,
+				},
+			},
+		}
+		crUpstreams := make(map[string]conf_v1.Upstream)
+
+		vsc := newVirtualServerConfigurator(&cfgParams, false, test.isResolverEnabled, &StaticConfigParams{}, false, &fakeBV)
+		result := vsc.generateProxyPassURLUpstreams(nil, routes, namer, nil, crUpstreams)
+
+		if !cmp.Equal([]version2.Upstream{test.expectedUpstream}, result) {
+			t.Errorf("generateProxyPassURLUpstreams() mismatch for the case of %s (-want +got):\n%s", test.msg, cmp.Diff([]version2.Upstream{test.expectedUpstream}, result))
+		}
+		if crUpstreams[test.expectedUpstream.Name].TLS.Enable != test.expectedTLSEnabled {
+			t.Errorf("generateProxyPassURLUpstreams() returned TLS enabled %v but expected %v for the case of %s",
+				crUpstreams[test.expectedUpstream.Name].TLS.Enable, test.expectedTLSEnabled, test.msg)
+		}
+		if len(vsc.warnings) != 0 {
+			t.Errorf("generateProxyPassURLUpstreams() returned unexpected warnings %v for the case of %s", vsc.warnings, test.msg)
+		}
+	}
+}
+
+func TestGenerateProxyPassURLUpstreamsWarnsWithoutResolver(t *testing.T) {
+	t.Parallel()
+	namer := &upstreamNamer{prefix: "vs_default_cafe", namespace: "default"}
+	routes := []conf_v1.Route{
+		{
+			Path: "/",
+			Action: &conf_v1.Action{
+				ProxyPassURL: "https://example.com",
+			},
+		},
+	}
+
+	vsc := newVirtualServerConfigurator(&ConfigParams{Context: context.Background()}, false, false, &StaticConfigParams{}, false, &fakeBV)
+	result := vsc.generateProxyPassURLUpstreams(nil, routes, namer, nil, make(map[string]conf_v1.Upstream))
+
+	if len(result) != 1 || result[0].Resolve {
+		t.Errorf("generateProxyPassURLUpstreams() returned %v but expected a single upstream without resolve", result)
+	}
+	if len(vsc.warnings) != 1 {
+		t.Errorf("generateProxyPassURLUpstreams() returned %d warnings but expected 1", len(vsc.warnings))
+	}
+}
+
+func TestGenerateLocationForProxyPassURL(t *testing.T) {
+	t.Parallel()
+	action := &conf_v1.Action{
+		ProxyPassURL: "https://example.com:8443",
+	}
+	upstreamName := "vs_default_cafe_" + generateProxyPassURLUpstreamName(action.ProxyPassURL)
+	upstream := conf_v1.Upstream{
+		Name: upstreamName,
+		TLS:  conf_v1.UpstreamTLS{Enable: true},
+	}
+	cfgParams := ConfigParams{Context: context.Background()}
+
+	loc, _ := generateLocation("/", upstreamName, upstream, action, &cfgParams, errorPageDetails{}, false,
+		"", "", "", false, 0, nil, false, "", "", Warnings{})
+
+	expectedProxyPass := "https://" + upstreamName
+	if loc.ProxyPass != expectedProxyPass {
+		t.Errorf("generateLocation() returned proxy pass %q but expected %q", loc.ProxyPass, expectedProxyPass)
+	}
+	expectedHeaders := []version2.Header{{Name: "Host", Value: "example.com:8443"}}
+	if !cmp.Equal(expectedHeaders, loc.ProxySetHeaders) {
+		t.Error(cmp.Diff(expectedHeaders, loc.ProxySetHeaders))
+	}
+	if loc.ProxySSLName != "example.com" || !loc.ProxySSLServerName {
+		t.Errorf("generateLocation() returned proxy SSL name %q with SNI %v but expected %q with SNI", loc.ProxySSLName, loc.ProxySSLServerName, "example.com")
+	}
+}
+
+func TestGenerateUpstreamWithNTLM(t *testing.T) {
+	t.Parallel()
+	name := "test-upstream"
+	upstream := conf_v1.Upstream{Service: name, Port: 80, NTLM: true}
+	endpoints := []string{
+		"192.168.10.10:8080",
+	}
+	cfgParams := ConfigParams{
+		Context:          context.Background(),
+		LBMethod:         "random",
+		MaxFails:         1,
+		MaxConns:         0,
+		FailTimeout:      "10s",
+		Keepalive:        21,
+		UpstreamZoneSize: "256k",
+	}
+
+	expected := version2.Upstream{
+		Name: "test-upstream",
+		UpstreamLabels: version2.UpstreamLabels{
+			Service: "test-upstream",
+		},
+		Servers: []version2.UpstreamServer{
+			{
+				Address: "192.168.10.10:8080",
+			},
+		},
+		MaxFails:         1,
+		MaxConns:         0,
+		FailTimeout:      "10s",
+		LBMethod:         "random",
+		Keepalive:        21,
+		UpstreamZoneSize: "256k",
+		NTLM:             true,
+	}
+
+	vsc := newVirtualServerConfigurator(&cfgParams, true, false, &StaticConfigParams{}, false, &fakeBV)
+	result := vsc.generateUpstream(nil, name, upstream, false, endpoints, nil, nil)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("generateUpstream() returned %v but expected %v", result, expected)
+	}
+
+	if len(vsc.warnings) != 0 {
+		t.Errorf("generateUpstream returned warnings for %v", upstream)
+	}
+}
+
+func TestGenerateProxyPass(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		tlsEnabled   bool
+		upstreamName string
+		internal     bool
+		expected     string
+	}{
+		{
+			tlsEnabled:   false,
+			upstreamName: "test-upstream",
+			internal:     false,
+			expected:     "http://test-upstream",
+		},
+		{
+			tlsEnabled:   true,
+			upstreamName: "test-upstream",
+			internal:     false,
+			expected:     "https://test-upstream",
+		},
+		{
+			tlsEnabled:   false,
+			upstreamName: "test-upstream",
+			internal:     true,
+			expected:     "http://test-upstream$request_uri",
+		},
+		{
+			tlsEnabled:   true,
+			upstreamName: "test-upstream",
+			internal:     true,
+			expected:     "https://test-upstream$request_uri",
+		},
+	}
+
+	for _, test := range tests {
+		result := generateProxyPass(test.tlsEnabled, test.upstreamName, test.internal, nil)
+		if result != test.expected {
+			t.Errorf("generateProxyPass(%v, %v, %v) returned %v but expected %v", test.tlsEnabled, test.upstreamName, test.internal, result, test.expected)
+		}
+	}
+}
+
+func TestGenerateProxyPassProtocol(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		upstream conf_v1.Upstream
+		expected string
+	}{
+		{
+			upstream: conf_v1.Upstream{},
+			expected: "http",
+		},
+		{
+			upstream: conf_v1.Upstream{
+				TLS: conf_v1.UpstreamTLS{
+					Enable: true,
+				},
+			},
+			expected: "https",
+		},
+	}
+
+	for _, test := range tests {
+		result := generateProxyPassProtocol(test.upstream.TLS.Enable)
+		if result != test.expected {
+			t.Errorf("generateProxyPassProtocol(%v) returned %v but expected %v", test.upstream.TLS.Enable, result, test.expected)
+		}
+	}
+}
+
+func TestGenerateGRPCPass(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		grpcEnabled  bool
+		tlsEnabled   bool
+		upstreamName string
+		expected     string
+	}{
+		{
+			grpcEnabled:  false,
 			tlsEnabled:   false,
 			upstreamName: "test-upstream",
 			expected:     "",
@@ -1077,27 +1913,377 @@ func TestGenerateSnippets(t *testing.T) {
 	}
 }
 
-func TestGenerateBuffer(t *testing.T) {
+func TestGenerateBuffer(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		inputS   *conf_v1.UpstreamBuffers
+		expected string
+	}{
+		{
+			inputS:   nil,
+			expected: "8 4k",
+		},
+		{
+			inputS:   &conf_v1.UpstreamBuffers{Number: 8, Size: "16K"},
+			expected: "8 16K",
+		},
+	}
+
+	for _, test := range tests {
+		result := generateBuffers(test.inputS, "8 4k")
+		if result != test.expected {
+			t.Errorf("generateBuffer() return %v but expected %v", result, test.expected)
+		}
+	}
+}
+
+func TestGenerateLocationWithExpires(t *testing.T) {
+	t.Parallel()
+	virtualServer := &conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
+	}
+	tests := []struct {
+		msg             string
+		action          *conf_v1.Action
+		expectedExpires string
+		expectedWarning []string
+	}{
+		{
+			msg:             "expires time",
+			action:          &conf_v1.Action{Pass: "tea", Expires: "30d"},
+			expectedExpires: "30d",
+		},
+		{
+			msg:             "expires max",
+			action:          &conf_v1.Action{Pass: "tea", Expires: "max"},
+			expectedExpires: "max",
+		},
+		{
+			msg: "expires with Cache-Control add header",
+			action: &conf_v1.Action{
+				Proxy: &conf_v1.ActionProxy{
+					Upstream: "tea",
+					ResponseHeaders: &conf_v1.ProxyResponseHeaders{
+						Add: []conf_v1.AddHeader{
+							{Header: conf_v1.Header{Name: "cache-control", Value: "no-store"}},
+						},
+					},
+				},
+				Expires: "30d",
+			},
+			expectedExpires: "30d",
+			expectedWarning: []string{"The expires of the action for the path /static conflicts with the Cache-Control response header added by the proxy action"},
+		},
+	}
+	cfgParams := ConfigParams{Context: context.Background()}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			t.Parallel()
+			warnings := newWarnings()
+			errorPages := errorPageDetails{owner: virtualServer}
+			loc, _ := generateLocation("/static", "vs_default_cafe_tea", conf_v1.Upstream{Name: "tea"}, test.action, &cfgParams, errorPages, false,
+				"", "/static", "", false, 0, nil, false, "", "", warnings)
+
+			if loc.Expires != test.expectedExpires {
+				t.Errorf("generateLocation() returned expires %q but expected %q", loc.Expires, test.expectedExpires)
+			}
+			if diff := cmp.Diff(test.expectedWarning, warnings[virtualServer]); diff != "" {
+				t.Errorf("generateLocation() returned unexpected warnings (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGenerateLocationWithSubFilter(t *testing.T) {
+	t.Parallel()
+	disabled := false
+	virtualServer := &conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
+	}
+	tests := []struct {
+		msg                     string
+		upstream                conf_v1.Upstream
+		action                  *conf_v1.Action
+		expectedSubFilter       *version2.SubFilter
+		expectedProxySetHeaders []version2.Header
+		expectedWarning         []string
+	}{
+		{
+			msg:      "single rule",
+			upstream: conf_v1.Upstream{Name: "legacy"},
+			action: &conf_v1.Action{
+				Pass: "legacy",
+				SubFilter: &conf_v1.ActionSubFilter{
+					Rules: []conf_v1.SubFilterRule{{From: "http://legacy.internal:8080", To: "https://cafe.example.com"}},
+				},
+			},
+			expectedSubFilter: &version2.SubFilter{
+				Rules: []version2.SubFilterRule{{From: "http://legacy.internal:8080", To: "https://cafe.example.com"}},
+			},
+			expectedProxySetHeaders: []version2.Header{
+				{Name: "Host", Value: "$host"},
+				{Name: "Accept-Encoding", Value: ""},
+			},
+		},
+		{
+			msg:      "multiple rules",
+			upstream: conf_v1.Upstream{Name: "legacy"},
+			action: &conf_v1.Action{
+				Proxy: &conf_v1.ActionProxy{
+					Upstream: "legacy",
+					RequestHeaders: &conf_v1.ProxyRequestHeaders{
+						Set: []conf_v1.Header{{Name: "accept-encoding", Value: "identity"}},
+					},
+				},
+				SubFilter: &conf_v1.ActionSubFilter{
+					Rules: []conf_v1.SubFilterRule{
+						{From: "http://legacy.internal", To: "https://cafe.example.com"},
+						{From: "/legacy-static/", To: "/static/"},
+					},
+					Once:  true,
+					Types: []string{"text/css", "application/javascript"},
+				},
+			},
+			expectedSubFilter: &version2.SubFilter{
+				Rules: []version2.SubFilterRule{
+					{From: "http://legacy.internal", To: "https://cafe.example.com"},
+					{From: "/legacy-static/", To: "/static/"},
+				},
+				Once:  true,
+				Types: []string{"text/css", "application/javascript"},
+			},
+			expectedProxySetHeaders: []version2.Header{
+				{Name: "accept-encoding", Value: "identity"},
+				{Name: "Host", Value: "$host"},
+			},
+		},
+		{
+			msg:      "buffering disabled",
+			upstream: conf_v1.Upstream{Name: "legacy", ProxyBuffering: &disabled},
+			action: &conf_v1.Action{
+				Pass: "legacy",
+				SubFilter: &conf_v1.ActionSubFilter{
+					Rules: []conf_v1.SubFilterRule{{From: "http://legacy.internal", To: "https://cafe.example.com"}},
+				},
+			},
+			expectedSubFilter: &version2.SubFilter{
+				Rules: []version2.SubFilterRule{{From: "http://legacy.internal", To: "https://cafe.example.com"}},
+			},
+			expectedProxySetHeaders: []version2.Header{
+				{Name: "Host", Value: "$host"},
+				{Name: "Accept-Encoding", Value: ""},
+			},
+			expectedWarning: []string{"The sub filter of the action for the path /legacy requires the buffering of the responses, which is disabled for the upstream legacy"},
+		},
+	}
+	cfgParams := ConfigParams{Context: context.Background(), ProxyBuffering: true}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			t.Parallel()
+			warnings := newWarnings()
+			errorPages := errorPageDetails{owner: virtualServer}
+			loc, _ := generateLocation("/legacy", "vs_default_cafe_legacy", test.upstream, test.action, &cfgParams, errorPages, false,
+				"", "/legacy", "", false, 0, nil, false, "", "", warnings)
+
+			if diff := cmp.Diff(test.expectedSubFilter, loc.SubFilter); diff != "" {
+				t.Errorf("generateLocation() returned unexpected sub filter (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(test.expectedProxySetHeaders, loc.ProxySetHeaders); diff != "" {
+				t.Errorf("generateLocation() returned unexpected proxy set headers (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(test.expectedWarning, warnings[virtualServer]); diff != "" {
+				t.Errorf("generateLocation() returned unexpected warnings (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGenerateLocationWithProxyMethod(t *testing.T) {
+	t.Parallel()
+	virtualServer := &conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
+	}
+	tests := []struct {
+		msg                 string
+		action              *conf_v1.Action
+		expectedProxyMethod string
+		expectedWarning     []string
+	}{
+		{
+			msg:    "no method override",
+			action: &conf_v1.Action{Proxy: &conf_v1.ActionProxy{Upstream: "tea"}},
+		},
+		{
+			msg: "GET rewritten to POST",
+			action: &conf_v1.Action{
+				Proxy: &conf_v1.ActionProxy{
+					Upstream:    "tea",
+					ProxyMethod: "POST",
+				},
+			},
+			expectedProxyMethod: "POST",
+			expectedWarning:     []string{"The proxy action for the path /api changes the request method to POST: the request body of the client request is passed to the upstream unchanged"},
+		},
+	}
+	cfgParams := ConfigParams{Context: context.Background()}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			t.Parallel()
+			warnings := newWarnings()
+			errorPages := errorPageDetails{owner: virtualServer}
+			loc, _ := generateLocation("/api", "vs_default_cafe_tea", conf_v1.Upstream{Name: "tea"}, test.action, &cfgParams, errorPages, false,
+				"", "/api", "", false, 0, nil, false, "", "", warnings)
+
+			if loc.ProxyMethod != test.expectedProxyMethod {
+				t.Errorf("generateLocation() returned proxy method %q but expected %q", loc.ProxyMethod, test.expectedProxyMethod)
+			}
+			if diff := cmp.Diff(test.expectedWarning, warnings[virtualServer]); diff != "" {
+				t.Errorf("generateLocation() returned unexpected warnings (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGenerateLocationWithProxyPassRequestBody(t *testing.T) {
+	t.Parallel()
+	virtualServer := &conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
+	}
+	tests := []struct {
+		msg                          string
+		proxy                        *conf_v1.ActionProxy
+		expectedProxyPassRequestBody string
+		expectedProxySetHeaders      []version2.Header
+		expectedWarning              []string
+	}{
+		{
+			msg:                     "default",
+			proxy:                   &conf_v1.ActionProxy{Upstream: "tea"},
+			expectedProxySetHeaders: []version2.Header{{Name: "Host", Value: "$host"}},
+		},
+		{
+			msg: "body passed",
+			proxy: &conf_v1.ActionProxy{
+				Upstream:             "tea",
+				ProxyPassRequestBody: new(true),
+			},
+			expectedProxySetHeaders: []version2.Header{{Name: "Host", Value: "$host"}},
+		},
+		{
+			msg: "body off",
+			proxy: &conf_v1.ActionProxy{
+				Upstream:             "tea",
+				ProxyPassRequestBody: new(false),
+			},
+			expectedProxyPassRequestBody: "off",
+			expectedProxySetHeaders: []version2.Header{
+				{Name: "Host", Value: "$host"},
+				{Name: "Content-Length", Value: ""},
+			},
+		},
+		{
+			msg: "body off with Content-Length set by the user",
+			proxy: &conf_v1.ActionProxy{
+				Upstream:             "tea",
+				ProxyPassRequestBody: new(false),
+				RequestHeaders: &conf_v1.ProxyRequestHeaders{
+					Set: []conf_v1.Header{{Name: "content-length", Value: "0"}},
+				},
+			},
+			expectedProxyPassRequestBody: "off",
+			expectedProxySetHeaders: []version2.Header{
+				{Name: "content-length", Value: "0"},
+				{Name: "Host", Value: "$host"},
+			},
+		},
+		{
+			msg: "body off with method override",
+			proxy: &conf_v1.ActionProxy{
+				Upstream:             "tea",
+				ProxyMethod:          "GET",
+				ProxyPassRequestBody: new(false),
+			},
+			expectedProxyPassRequestBody: "off",
+			expectedProxySetHeaders: []version2.Header{
+				{Name: "Host", Value: "$host"},
+				{Name: "Content-Length", Value: ""},
+			},
+		},
+	}
+	cfgParams := ConfigParams{Context: context.Background()}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			t.Parallel()
+			warnings := newWarnings()
+			errorPages := errorPageDetails{owner: virtualServer}
+			loc, _ := generateLocation("/auth", "vs_default_cafe_tea", conf_v1.Upstream{Name: "tea"}, &conf_v1.Action{Proxy: test.proxy}, &cfgParams, errorPages, false,
+				"", "/auth", "", false, 0, nil, false, "", "", warnings)
+
+			if loc.ProxyPassRequestBody != test.expectedProxyPassRequestBody {
+				t.Errorf("generateLocation() returned proxy pass request body %q but expected %q", loc.ProxyPassRequestBody, test.expectedProxyPassRequestBody)
+			}
+			if diff := cmp.Diff(test.expectedProxySetHeaders, loc.ProxySetHeaders); diff != "" {
+				t.Errorf("generateLocation() returned unexpected proxy set headers (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(test.expectedWarning, warnings[virtualServer]); diff != "" {
+				t.Errorf("generateLocation() returned unexpected warnings (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGenerateLocationForProxyingWithProxyMaxTempFileSize(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
-		inputS   *conf_v1.UpstreamBuffers
+		msg      string
+		upstream conf_v1.Upstream
 		expected string
 	}{
 		{
-			inputS:   nil,
-			expected: "8 4k",
+			msg:      "default from the ConfigMap",
+			upstream: conf_v1.Upstream{Name: "tea"},
+			expected: "1024m",
 		},
 		{
-			inputS:   &conf_v1.UpstreamBuffers{Number: 8, Size: "16K"},
-			expected: "8 16K",
+			msg:      "upstream override",
+			upstream: conf_v1.Upstream{Name: "tea", ProxyMaxTempFileSize: "2048m"},
+			expected: "2048m",
+		},
+		{
+			msg:      "temporary files disabled by the upstream",
+			upstream: conf_v1.Upstream{Name: "tea", ProxyMaxTempFileSize: "0"},
+			expected: "0",
 		},
 	}
+	cfgParams := ConfigParams{
+		Context:              context.Background(),
+		ProxyMaxTempFileSize: "1024m",
+	}
 
 	for _, test := range tests {
-		result := generateBuffers(test.inputS, "8 4k")
-		if result != test.expected {
-			t.Errorf("generateBuffer() return %v but expected %v", result, test.expected)
-		}
+		t.Run(test.msg, func(t *testing.T) {
+			t.Parallel()
+			loc := generateLocationForProxying("/", "vs_default_cafe_tea", test.upstream, &cfgParams, nil, false, 0, "", nil, "", nil, false, "", "", "")
+			if loc.ProxyMaxTempFileSize != test.expected {
+				t.Errorf("generateLocationForProxying() returned proxy max temp file size %q but expected %q", loc.ProxyMaxTempFileSize, test.expected)
+			}
+		})
 	}
 }
 
@@ -1200,6 +2386,164 @@ func TestGenerateLocationForGrpcProxying(t *testing.T) {
 	}
 }
 
+func TestGenerateLocationForProxyingClientBodyBufferSize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		upstream conf_v1.Upstream
+		expected string
+		msg      string
+	}{
+		{
+			upstream: conf_v1.Upstream{},
+			expected: "16k",
+			msg:      "default",
+		},
+		{
+			upstream: conf_v1.Upstream{ClientBodyBufferSize: "1m"},
+			expected: "1m",
+			msg:      "upstream override",
+		},
+	}
+
+	cfgParams := ConfigParams{
+		Context:              context.Background(),
+		ClientMaxBodySize:    "10m",
+		ClientBodyBufferSize: "16k",
+	}
+
+	for _, test := range tests {
+		result := generateLocationForProxying("/", "test-upstream", test.upstream, &cfgParams, nil, false, 0, "", nil, "", nil, false, "", "", "")
+		if result.ClientBodyBufferSize != test.expected {
+			t.Errorf("generateLocationForProxying() returned client body buffer size %q but expected %q for the case of %s", result.ClientBodyBufferSize, test.expected, test.msg)
+		}
+	}
+}
+
+func TestGenerateLocationForProxyingSocketKeepalive(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		msg      string
+		upstream conf_v1.Upstream
+		expected bool
+	}{
+		{
+			msg:      "http upstream with socket keepalive",
+			upstream: conf_v1.Upstream{ProxySocketKeepalive: new(true)},
+			expected: true,
+		},
+		{
+			msg:      "grpc upstream with socket keepalive",
+			upstream: conf_v1.Upstream{Type: "grpc", ProxySocketKeepalive: new(true)},
+			expected: true,
+		},
+		{
+			msg:      "socket keepalive disabled",
+			upstream: conf_v1.Upstream{ProxySocketKeepalive: new(false)},
+			expected: false,
+		},
+		{
+			msg:      "socket keepalive not set",
+			upstream: conf_v1.Upstream{},
+			expected: false,
+		},
+	}
+	cfgParams := ConfigParams{Context: context.Background()}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			t.Parallel()
+			result := generateLocationForProxying("/", "test-upstream", test.upstream, &cfgParams, nil, false, 0, "", nil, "", nil, false, "", "", "")
+			if result.ProxySocketKeepalive != test.expected {
+				t.Errorf("generateLocationForProxying() returned ProxySocketKeepalive %v but expected %v", result.ProxySocketKeepalive, test.expected)
+			}
+		})
+	}
+}
+
+func TestGenerateLocationForProxyingIgnoreClientAbort(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		msg      string
+		upstream conf_v1.Upstream
+		expected string
+	}{
+		{
+			msg:      "ignore client abort enabled",
+			upstream: conf_v1.Upstream{ProxyIgnoreClientAbort: new(true)},
+			expected: "on",
+		},
+		{
+			msg:      "ignore client abort disabled",
+			upstream: conf_v1.Upstream{ProxyIgnoreClientAbort: new(false)},
+			expected: "off",
+		},
+		{
+			msg:      "ignore client abort not set",
+			upstream: conf_v1.Upstream{},
+			expected: "",
+		},
+	}
+	cfgParams := ConfigParams{Context: context.Background()}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			t.Parallel()
+			result := generateLocationForProxying("/", "test-upstream", test.upstream, &cfgParams, nil, false, 0, "", nil, "", nil, false, "", "", "")
+			if result.ProxyIgnoreClientAbort != test.expected {
+				t.Errorf("generateLocationForProxying() returned ProxyIgnoreClientAbort %q but expected %q", result.ProxyIgnoreClientAbort, test.expected)
+			}
+		})
+	}
+}
+
+func TestGenerateLocationForProxyingWebSocket(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		msg      string
+		upstream conf_v1.Upstream
+		expected string
+	}{
+		{
+			msg:      "websocket upstream with default read timeout",
+			upstream: conf_v1.Upstream{WebSocket: &conf_v1.UpstreamWebSocket{Enable: true}},
+			expected: "1h",
+		},
+		{
+			msg: "websocket upstream with read timeout",
+			upstream: conf_v1.Upstream{
+				ProxyReadTimeout: "30s",
+				WebSocket:        &conf_v1.UpstreamWebSocket{Enable: true, ReadTimeout: "2h"},
+			},
+			expected: "2h",
+		},
+		{
+			msg: "websocket disabled",
+			upstream: conf_v1.Upstream{
+				ProxyReadTimeout: "30s",
+				WebSocket:        &conf_v1.UpstreamWebSocket{Enable: false},
+			},
+			expected: "30s",
+		},
+		{
+			msg:      "websocket not set",
+			upstream: conf_v1.Upstream{},
+			expected: "60s",
+		},
+	}
+	cfgParams := ConfigParams{Context: context.Background(), ProxyReadTimeout: "60s"}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			t.Parallel()
+			result := generateLocationForProxying("/", "test-upstream", test.upstream, &cfgParams, nil, false, 0, "", nil, "", nil, false, "", "", "")
+			if result.ProxyReadTimeout != test.expected {
+				t.Errorf("generateLocationForProxying() returned ProxyReadTimeout %q but expected %q", result.ProxyReadTimeout, test.expected)
+			}
+		})
+	}
+}
+
 func TestGenerateReturnBlock(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -1273,49 +2617,244 @@ func TestGenerateLocationForReturn(t *testing.T) {
 			msg: "return without code and type",
 		},
 		{
-			actionReturn: &conf_v1.ActionReturn{
-				Code: 400,
-				Type: "text/html",
-				Body: "hello",
-			},
+			actionReturn: &conf_v1.ActionReturn{
+				Code: 400,
+				Type: "text/html",
+				Body: "hello",
+			},
+
+			expectedLocation: version2.Location{
+				Path:     "/",
+				Snippets: []string{"# location snippet"},
+				ErrorPages: []version2.ErrorPage{
+					{
+						Name:         "@return_1",
+						Codes:        "418",
+						ResponseCode: 400,
+					},
+				},
+				ProxyInterceptErrors: true,
+				InternalProxyPass:    "http://unix:/var/lib/nginx/nginx-418-server.sock",
+			},
+			expectedReturnLocation: &version2.ReturnLocation{
+				Name:        "@return_1",
+				DefaultType: "text/html",
+				Return: version2.Return{
+					Code: 0,
+					Text: "hello",
+				},
+			},
+			msg: "return with all fields defined",
+		},
+		{
+			actionReturn: &conf_v1.ActionReturn{
+				Code: 200,
+				Bodies: []conf_v1.ReturnBody{
+					{Type: "application/json", Body: `{\"message\": \"hello\"}`},
+					{Type: "text/html", Body: "<p>hello</p>"},
+				},
+				Headers: []conf_v1.Header{{Name: "X-Return", Value: "true"}},
+			},
+
+			expectedLocation: version2.Location{
+				Path:     "/",
+				Snippets: []string{"# location snippet"},
+				ErrorPages: []version2.ErrorPage{
+					{
+						Name:         "$vs_default_cafe_return_1",
+						Codes:        "418",
+						ResponseCode: 200,
+					},
+				},
+				ProxyInterceptErrors: true,
+				InternalProxyPass:    "http://unix:/var/lib/nginx/nginx-418-server.sock",
+			},
+			expectedReturnLocation: &version2.ReturnLocation{
+				Name:        "@return_1",
+				DefaultType: "application/json",
+				Return: version2.Return{
+					Text: `{\"message\": \"hello\"}`,
+				},
+				Headers: []version2.Header{{Name: "X-Return", Value: "true"}},
+				Alternatives: []version2.ReturnLocation{
+					{
+						Name:        "@return_1_1",
+						DefaultType: "text/html",
+						Return: version2.Return{
+							Text: "<p>hello</p>",
+						},
+						Headers: []version2.Header{{Name: "X-Return", Value: "true"}},
+					},
+				},
+				AcceptMap: &version2.Map{
+					Source:   "$http_accept",
+					Variable: "$vs_default_cafe_return_1",
+					Parameters: []version2.Parameter{
+						{Value: "default", Result: "@return_1"},
+						{Value: `"~*application/json(?![^,]*;\s*q=0(?:\.0*)?(?![.0-9]))"`, Result: "@return_1"},
+						{Value: `"~*text/html(?![^,]*;\s*q=0(?:\.0*)?(?![.0-9]))"`, Result: "@return_1_1"},
+					},
+				},
+			},
+			msg: "return with bodies for json and html",
+		},
+	}
+	path := "/"
+	snippets := []string{"# location snippet"}
+	returnLocationIndex := 1
+	variableNamer := &VariableNamer{safeNsName: "default_cafe"}
+
+	for _, test := range tests {
+		location, returnLocation := generateLocationForReturn(path, snippets, test.actionReturn, returnLocationIndex, variableNamer, nil, newWarnings())
+		if !reflect.DeepEqual(location, test.expectedLocation) {
+			t.Errorf("generateLocationForReturn() returned  \n%+v but expected \n%+v for the case of %s",
+				location, test.expectedLocation, test.msg)
+		}
+		if !reflect.DeepEqual(returnLocation, test.expectedReturnLocation) {
+			t.Errorf("generateLocationForReturn() returned  \n%+v but expected \n%+v for the case of %s",
+				returnLocation, test.expectedReturnLocation, test.msg)
+		}
+	}
+}
+
+func TestGenerateLocationForReturnWithBodylessStatusCode(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		actionReturn *conf_v1.ActionReturn
+		code         int
+		wantWarnings []string
+		msg          string
+	}{
+		{
+			actionReturn: &conf_v1.ActionReturn{Code: 204},
+			code:         204,
+			msg:          "204 without a body",
+		},
+		{
+			actionReturn: &conf_v1.ActionReturn{Code: 204, Type: "application/json", Body: "{}"},
+			code:         204,
+			wantWarnings: []string{
+				"The body of the return of the action for the path /tea is ignored, as the responses with the status code 204 have no body",
+			},
+			msg: "204 with a body",
+		},
+		{
+			actionReturn: &conf_v1.ActionReturn{Code: 304},
+			code:         304,
+			msg:          "304 without a body",
+		},
+		{
+			actionReturn: &conf_v1.ActionReturn{
+				Code:   304,
+				Bodies: []conf_v1.ReturnBody{{Type: "application/json", Body: "{}"}},
+			},
+			code: 304,
+			wantWarnings: []string{
+				"The body of the return of the action for the path /tea is ignored, as the responses with the status code 304 have no body",
+			},
+			msg: "304 with bodies",
+		},
+	}
+
+	owner := &conf_v1.VirtualServer{}
+	for _, test := range tests {
+		warnings := newWarnings()
+		location, returnLocation := generateLocationForReturn("/tea", nil, test.actionReturn, 1, &VariableNamer{safeNsName: "default_cafe"}, owner, warnings)
+
+		expectedReturnLocation := &version2.ReturnLocation{Name: "@return_1"}
+		if !reflect.DeepEqual(returnLocation, expectedReturnLocation) {
+			t.Errorf("generateLocationForReturn() returned \n%+v but expected \n%+v for the case of %s",
+				returnLocation, expectedReturnLocation, test.msg)
+		}
+		if location.ErrorPages[0].ResponseCode != test.code || location.ErrorPages[0].Name != "@return_1" {
+			t.Errorf("generateLocationForReturn() returned error page %+v for the case of %s", location.ErrorPages[0], test.msg)
+		}
+		if !cmp.Equal(test.wantWarnings, warnings[owner]) {
+			t.Errorf("generateLocationForReturn() returned unexpected warnings for the case of %s: %s", test.msg, cmp.Diff(test.wantWarnings, warnings[owner]))
+		}
+	}
+}
+
+func TestFlattenReturnLocations(t *testing.T) {
+	t.Parallel()
+	acceptMap := version2.Map{
+		Source:   "$http_accept",
+		Variable: "$vs_default_cafe_return_1",
+		Parameters: []version2.Parameter{
+			{Value: "default", Result: "@return_1"},
+			{Value: `"~*application/json(?![^,]*;\s*q=0(?:\.0*)?(?![.0-9]))"`, Result: "@return_1"},
+			{Value: `"~*text/html(?![^,]*;\s*q=0(?:\.0*)?(?![.0-9]))"`, Result: "@return_1_1"},
+		},
+	}
+	returnLocations := []version2.ReturnLocation{
+		{
+			Name:        "@return_0",
+			DefaultType: "text/plain",
+			Return:      version2.Return{Text: "hello"},
+		},
+		{
+			Name:        "@return_1",
+			DefaultType: "application/json",
+			Return:      version2.Return{Text: `{}`},
+			Alternatives: []version2.ReturnLocation{
+				{
+					Name:        "@return_1_1",
+					DefaultType: "text/html",
+					Return:      version2.Return{Text: "<p></p>"},
+				},
+			},
+			AcceptMap: &acceptMap,
+		},
+	}
+
+	expectedReturnLocations := []version2.ReturnLocation{
+		{
+			Name:        "@return_0",
+			DefaultType: "text/plain",
+			Return:      version2.Return{Text: "hello"},
+		},
+		{
+			Name:        "@return_1",
+			DefaultType: "application/json",
+			Return:      version2.Return{Text: `{}`},
+		},
+		{
+			Name:        "@return_1_1",
+			DefaultType: "text/html",
+			Return:      version2.Return{Text: "<p></p>"},
+		},
+	}
+	expectedMaps := []version2.Map{acceptMap}
 
-			expectedLocation: version2.Location{
-				Path:     "/",
-				Snippets: []string{"# location snippet"},
-				ErrorPages: []version2.ErrorPage{
-					{
-						Name:         "@return_1",
-						Codes:        "418",
-						ResponseCode: 400,
-					},
-				},
-				ProxyInterceptErrors: true,
-				InternalProxyPass:    "http://unix:/var/lib/nginx/nginx-418-server.sock",
-			},
-			expectedReturnLocation: &version2.ReturnLocation{
-				Name:        "@return_1",
-				DefaultType: "text/html",
-				Return: version2.Return{
-					Code: 0,
-					Text: "hello",
-				},
-			},
-			msg: "return with all fields defined",
+	flattened, maps := flattenReturnLocations(returnLocations)
+	if diff := cmp.Diff(expectedReturnLocations, flattened); diff != "" {
+		t.Errorf("flattenReturnLocations() returned unexpected return locations (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(expectedMaps, maps); diff != "" {
+		t.Errorf("flattenReturnLocations() returned unexpected maps (-want +got):\n%s", diff)
+	}
+}
+
+func TestGenerateAcceptTypeRegex(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		mediaType string
+		expected  string
+	}{
+		{
+			mediaType: "text/html",
+			expected:  `"~*text/html(?![^,]*;\s*q=0(?:\.0*)?(?![.0-9]))"`,
+		},
+		{
+			mediaType: "application/ld+json",
+			expected:  `"~*application/ld\+json(?![^,]*;\s*q=0(?:\.0*)?(?![.0-9]))"`,
 		},
 	}
-	path := "/"
-	snippets := []string{"# location snippet"}
-	returnLocationIndex := 1
 
 	for _, test := range tests {
-		location, returnLocation := generateLocationForReturn(path, snippets, test.actionReturn, returnLocationIndex)
-		if !reflect.DeepEqual(location, test.expectedLocation) {
-			t.Errorf("generateLocationForReturn() returned  \n%+v but expected \n%+v for the case of %s",
-				location, test.expectedLocation, test.msg)
-		}
-		if !reflect.DeepEqual(returnLocation, test.expectedReturnLocation) {
-			t.Errorf("generateLocationForReturn() returned  \n%+v but expected \n%+v for the case of %s",
-				returnLocation, test.expectedReturnLocation, test.msg)
+		result := generateAcceptTypeRegex(test.mediaType)
+		if result != test.expected {
+			t.Errorf("generateAcceptTypeRegex(%q) returned %s but expected %s", test.mediaType, result, test.expected)
 		}
 	}
 }
@@ -1379,6 +2918,92 @@ func TestGenerateLocationForRedirect(t *testing.T) {
 	}
 }
 
+func TestGenerateProxySSLSessionReuse(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		tls      conf_v1.UpstreamTLS
+		expected string
+		msg      string
+	}{
+		{
+			tls:      conf_v1.UpstreamTLS{Enable: true, SessionReuse: new(true)},
+			expected: "on",
+			msg:      "on",
+		},
+		{
+			tls:      conf_v1.UpstreamTLS{Enable: true, SessionReuse: new(false)},
+			expected: "off",
+			msg:      "off",
+		},
+		{
+			tls:      conf_v1.UpstreamTLS{Enable: true},
+			expected: "",
+			msg:      "unset",
+		},
+		{
+			tls:      conf_v1.UpstreamTLS{Enable: false, SessionReuse: new(false)},
+			expected: "",
+			msg:      "TLS disabled",
+		},
+	}
+
+	for _, test := range tests {
+		result := generateProxySSLSessionReuse(test.tls)
+		if result != test.expected {
+			t.Errorf("generateProxySSLSessionReuse() returned %q but expected %q for the case of %s", result, test.expected, test.msg)
+		}
+	}
+}
+
+func TestGenerateLocationForFiles(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		files    *conf_v1.ActionFiles
+		expected version2.Location
+		msg      string
+	}{
+		{
+			files: &conf_v1.ActionFiles{
+				Root:     "/usr/share/nginx/html",
+				TryFiles: []string{"$uri", "$uri/"},
+				Fallback: "/index.html",
+			},
+			expected: version2.Location{
+				Path:     "/",
+				Snippets: []string{"# location snippet"},
+				Root:     "/usr/share/nginx/html",
+				TryFiles: []string{"$uri", "$uri/", "/index.html"},
+			},
+			msg: "single-page application fallback",
+		},
+		{
+			files: &conf_v1.ActionFiles{
+				Root: "/usr/share/nginx/html",
+			},
+			expected: version2.Location{
+				Path:     "/",
+				Snippets: []string{"# location snippet"},
+				Root:     "/usr/share/nginx/html",
+				TryFiles: []string{"$uri", "=404"},
+			},
+			msg: "defaults",
+		},
+	}
+
+	for _, test := range tests {
+		cfgParams := ConfigParams{Context: context.Background(), LocationSnippets: []string{"# location snippet"}}
+		action := &conf_v1.Action{Files: test.files}
+		result, returnLocation := generateLocation("/", "", conf_v1.Upstream{}, action, &cfgParams, errorPageDetails{}, false,
+			"", "/", "", false, 0, nil, false, "", "", Warnings{})
+		if returnLocation != nil {
+			t.Errorf("generateLocation() returned a return location %v for the case of %s", returnLocation, test.msg)
+		}
+		if diff := cmp.Diff(test.expected, result); diff != "" {
+			t.Errorf("generateLocation() mismatch for the case of %s (-want +got):\n%s", test.msg, diff)
+		}
+	}
+}
+
 func TestGenerateLocationForReturnRegexPath(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -1395,7 +3020,7 @@ func TestGenerateLocationForReturnRegexPath(t *testing.T) {
 	actionReturn := &conf_v1.ActionReturn{Body: "ok"}
 
 	for _, test := range tests {
-		location, _ := generateLocationForReturn(test.path, snippets, actionReturn, 1)
+		location, _ := generateLocationForReturn(test.path, snippets, actionReturn, 1, nil, nil, newWarnings())
 		if location.Path != test.expectedPath {
 			t.Errorf("generateLocationForReturn() path = %q, want %q (%s)", location.Path, test.expectedPath, test.msg)
 		}
@@ -1536,6 +3161,68 @@ func TestGenerateSSLConfig(t *testing.T) {
 			expectedWarnings: Warnings{},
 			msg:              "normal case with HTTPS",
 		},
+		{
+			inputTLS: &conf_v1.TLS{
+				Secret: "secret",
+				Profile: &conf_v1.TLSProfile{
+					Name: "modern",
+				},
+			},
+			inputSecretRefs: map[string]*secrets.SecretReference{
+				"default/secret": {
+					Secret: &api_v1.Secret{
+						Type: api_v1.SecretTypeTLS,
+					},
+					Path: "secret.pem",
+				},
+			},
+			inputCfgParams: &ConfigParams{Context: context.Background()},
+			wildcard:       false,
+			expectedSSL: &version2.SSL{
+				Certificate:    "secret.pem",
+				CertificateKey: "secret.pem",
+				Protocols:      "TLSv1.3",
+			},
+			expectedWarnings: Warnings{},
+			msg:              "modern TLS profile",
+		},
+		{
+			inputTLS: &conf_v1.TLS{
+				Secret: "",
+				Profile: &conf_v1.TLSProfile{
+					Name:      "intermediate",
+					Protocols: "TLSv1.2",
+					Ciphers:   "HIGH:!aNULL:!MD5",
+				},
+			},
+			inputSecretRefs: map[string]*secrets.SecretReference{},
+			inputCfgParams:  &ConfigParams{Context: context.Background()},
+			wildcard:        true,
+			expectedSSL: &version2.SSL{
+				Certificate:    pemFileNameForWildcardTLSSecret,
+				CertificateKey: pemFileNameForWildcardTLSSecret,
+				Protocols:      "TLSv1.2",
+				Ciphers:        "HIGH:!aNULL:!MD5",
+			},
+			expectedWarnings: Warnings{},
+			msg:              "intermediate TLS profile with custom protocols and ciphers and wildcard cert",
+		},
+		{
+			inputTLS: &conf_v1.TLS{
+				Secret: "",
+				Profile: &conf_v1.TLSProfile{
+					Protocols: "TLSv1.2 TLSv1.3",
+				},
+			},
+			inputSecretRefs: map[string]*secrets.SecretReference{},
+			inputCfgParams:  &ConfigParams{Context: context.Background()},
+			wildcard:        false,
+			expectedSSL:     nil,
+			expectedWarnings: Warnings{
+				nil: []string{"TLS profile is ignored, because the TLS secret is not specified and the wildcard TLS secret is not configured"},
+			},
+			msg: "custom TLS profile with empty secret and wildcard cert disabled",
+		},
 	}
 
 	namespace := "default"
@@ -1646,6 +3333,73 @@ func TestGenerateTLSRedirectBasedOn(t *testing.T) {
 	}
 }
 
+func TestGenerateStrictHostConfig(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		host       string
+		strictHost *conf_v1.StrictHost
+		expected   *version2.StrictHost
+		msg        string
+	}{
+		{
+			host:       "cafe.example.com",
+			strictHost: nil,
+			expected:   nil,
+			msg:        "no strict host",
+		},
+		{
+			host:       "cafe.example.com",
+			strictHost: &conf_v1.StrictHost{Enable: false, Code: new(400)},
+			expected:   nil,
+			msg:        "strict host disabled",
+		},
+		{
+			host:       "cafe.example.com",
+			strictHost: &conf_v1.StrictHost{Enable: true},
+			expected: &version2.StrictHost{
+				Host: "cafe.example.com",
+				Code: 421,
+			},
+			msg: "default code",
+		},
+		{
+			host:       "*.example.com",
+			strictHost: &conf_v1.StrictHost{Enable: true, Code: new(400)},
+			expected: &version2.StrictHost{
+				Host:  `^.+\.example\.com$`,
+				Regex: true,
+				Code:  400,
+			},
+			msg: "wildcard host",
+		},
+	}
+
+	for _, test := range tests {
+		result := generateStrictHostConfig(test.host, test.strictHost)
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("generateStrictHostConfig() returned %v but expected %v for the case of %s", result, test.expected, test.msg)
+		}
+	}
+}
+
+func TestGenerateStrictHostConfigForWildcardHostMatchesSubdomainsOnly(t *testing.T) {
+	t.Parallel()
+
+	cfg := generateStrictHostConfig("*.example.com", &conf_v1.StrictHost{Enable: true})
+	re := regexp.MustCompile(cfg.Host)
+
+	for _, host := range []string{"cafe.example.com", "tea.cafe.example.com"} {
+		if !re.MatchString(host) {
+			t.Errorf("strict host %q rejects the matching host %q", cfg.Host, host)
+		}
+	}
+	for _, host := range []string{"example.com", "cafe.example.org", "cafe-example.com", "cafe.example.com.evil.org"} {
+		if re.MatchString(host) {
+			t.Errorf("strict host %q accepts the mismatched host %q", cfg.Host, host)
+		}
+	}
+}
+
 func TestCreateUpstreamsForPlus(t *testing.T) {
 	t.Parallel()
 	virtualServerEx := VirtualServerEx{
@@ -1835,6 +3589,84 @@ func TestCreateUpstreamsForPlus(t *testing.T) {
 	}
 }
 
+func TestCreateUpstreamsForPlusDrainsRemovedEndpoints(t *testing.T) {
+	t.Parallel()
+	virtualServerEx := VirtualServerEx{
+		VirtualServer: &conf_v1.VirtualServer{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "cafe",
+				Namespace: "default",
+			},
+			Spec: conf_v1.VirtualServerSpec{
+				Host: "cafe.example.com",
+				Upstreams: []conf_v1.Upstream{
+					{
+						Name:    "tea",
+						Service: "tea-svc",
+						Port:    80,
+						Drain:   true,
+					},
+					{
+						Name:    "coffee",
+						Service: "coffee-svc",
+						Port:    80,
+					},
+				},
+			},
+		},
+		Endpoints: map[string][]string{
+			"default/tea-svc:80": {
+				"10.0.0.20:80",
+			},
+			"default/coffee-svc:80": {
+				"10.0.0.30:80",
+			},
+		},
+		TerminatingEndpoints: map[string][]string{
+			"default/tea-svc:80": {
+				"10.0.0.21:80",
+			},
+			"default/coffee-svc:80": {
+				"10.0.0.31:80",
+			},
+		},
+	}
+
+	upstreams := createUpstreamsForPlus(&virtualServerEx, &ConfigParams{Context: context.Background()}, &StaticConfigParams{})
+	if len(upstreams) != 2 {
+		t.Fatalf("createUpstreamsForPlus returned %d upstreams, expected 2", len(upstreams))
+	}
+
+	tests := []struct {
+		upstream          version2.Upstream
+		expectedEndpoints []string
+		expectedDrain     map[string]bool
+	}{
+		{
+			upstream:          upstreams[0],
+			expectedEndpoints: []string{"10.0.0.20:80", "10.0.0.21:80"},
+			expectedDrain:     map[string]bool{"10.0.0.21:80": true},
+		},
+		{
+			upstream:          upstreams[1],
+			expectedEndpoints: []string{"10.0.0.30:80"},
+			expectedDrain:     nil,
+		},
+	}
+
+	for _, test := range tests {
+		endpoints := createEndpointsFromUpstream(test.upstream)
+		if !reflect.DeepEqual(endpoints, test.expectedEndpoints) {
+			t.Errorf("createEndpointsFromUpstream returned %v for upstream %s, but expected %v", endpoints, test.upstream.Name, test.expectedEndpoints)
+		}
+
+		serverCfg := createUpstreamServersConfigForPlus(test.upstream)
+		if !reflect.DeepEqual(serverCfg.DrainServers, test.expectedDrain) {
+			t.Errorf("createUpstreamServersConfigForPlus returned drain servers %v for upstream %s, but expected %v", serverCfg.DrainServers, test.upstream.Name, test.expectedDrain)
+		}
+	}
+}
+
 func TestCreateUpstreamServersConfigForPlus(t *testing.T) {
 	t.Parallel()
 	upstream := version2.Upstream{
@@ -2134,14 +3966,46 @@ func TestGenerateHealthCheck(t *testing.T) {
 				URI:                 "/",
 				Interval:            "5s",
 				Jitter:              "0s",
-				KeepaliveTime:       "60s",
+				KeepaliveTime:       "60s",
+				Fails:               1,
+				Passes:              1,
+				Headers:             make(map[string]string),
+				Mandatory:           true,
+				Persistent:          true,
+			},
+			msg: "HealthCheck with mandatory and persistent set",
+		},
+		{
+			upstream: conf_v1.Upstream{
+				HealthCheck: &conf_v1.HealthCheck{
+					Enable:           true,
+					DisableKeepalive: true,
+					Headers: []conf_v1.Header{
+						{
+							Name:  "Host",
+							Value: "my.service",
+						},
+					},
+				},
+			},
+			upstreamName: upstreamName,
+			expected: &version2.HealthCheck{
+				Name:                upstreamName,
+				ProxyConnectTimeout: "5s",
+				ProxyReadTimeout:    "5s",
+				ProxySendTimeout:    "5s",
+				ProxyPass:           fmt.Sprintf("http://%v", upstreamName),
+				URI:                 "/",
+				Interval:            "5s",
+				Jitter:              "0s",
 				Fails:               1,
 				Passes:              1,
-				Headers:             make(map[string]string),
-				Mandatory:           true,
-				Persistent:          true,
+				Headers: map[string]string{
+					"Host":       "my.service",
+					"Connection": "close",
+				},
 			},
-			msg: "HealthCheck with mandatory and persistent set",
+			msg: "HealthCheck with keepalive disabled",
 		},
 	}
 
@@ -2248,6 +4112,58 @@ func TestGenerateGrpcHealthCheck(t *testing.T) {
 			},
 			msg: "HealthCheck with default parameters from Upstream",
 		},
+		{
+			upstream: conf_v1.Upstream{
+				HealthCheck: &conf_v1.HealthCheck{
+					Enable:     true,
+					GRPCStatus: new(12),
+				},
+				Type: "grpc",
+			},
+			upstreamName: upstreamName,
+			expected: &version2.HealthCheck{
+				Name:                upstreamName,
+				ProxyConnectTimeout: "5s",
+				ProxyReadTimeout:    "5s",
+				ProxySendTimeout:    "5s",
+				ProxyPass:           fmt.Sprintf("http://%v", upstreamName),
+				GRPCPass:            fmt.Sprintf("grpc://%v", upstreamName),
+				Interval:            "5s",
+				Jitter:              "0s",
+				KeepaliveTime:       "60s",
+				Fails:               1,
+				Passes:              1,
+				GRPCStatus:          new(12),
+				Headers:             make(map[string]string),
+				IsGRPC:              true,
+			},
+			msg: "HealthCheck with grpcStatus and without grpcService",
+		},
+		{
+			upstream: conf_v1.Upstream{
+				HealthCheck: &conf_v1.HealthCheck{
+					Enable:           true,
+					DisableKeepalive: true,
+				},
+				Type: "grpc",
+			},
+			upstreamName: upstreamName,
+			expected: &version2.HealthCheck{
+				Name:                upstreamName,
+				ProxyConnectTimeout: "5s",
+				ProxyReadTimeout:    "5s",
+				ProxySendTimeout:    "5s",
+				ProxyPass:           fmt.Sprintf("http://%v", upstreamName),
+				GRPCPass:            fmt.Sprintf("grpc://%v", upstreamName),
+				Interval:            "5s",
+				Jitter:              "0s",
+				Fails:               1,
+				Passes:              1,
+				Headers:             make(map[string]string),
+				IsGRPC:              true,
+			},
+			msg: "HealthCheck with keepalive disabled",
+		},
 	}
 
 	baseCfgParams := &ConfigParams{
@@ -2578,6 +4494,30 @@ func TestCreateEndpointsFromUpstream(t *testing.T) {
 	}
 }
 
+func TestCreateEndpointsFromUpstreamSkipsDownServers(t *testing.T) {
+	t.Parallel()
+	ups := version2.Upstream{
+		Servers: []version2.UpstreamServer{
+			{
+				Address: "10.0.0.20:80",
+			},
+			{
+				Address: "10.0.0.30:80",
+				Down:    true,
+			},
+		},
+	}
+
+	expected := []string{
+		"10.0.0.20:80",
+	}
+
+	endpoints := createEndpointsFromUpstream(ups)
+	if !reflect.DeepEqual(endpoints, expected) {
+		t.Errorf("createEndpointsFromUpstream returned %v, but expected %v", endpoints, expected)
+	}
+}
+
 func TestGenerateUpstreamWithQueue(t *testing.T) {
 	t.Parallel()
 	serviceName := "test-queue"
@@ -2644,7 +4584,7 @@ func TestGenerateUpstreamWithQueue(t *testing.T) {
 
 	for _, test := range tests {
 		vsc := newVirtualServerConfigurator(&ConfigParams{Context: context.Background()}, test.isPlus, false, &StaticConfigParams{}, false, &fakeBV)
-		result := vsc.generateUpstream(nil, test.name, test.upstream, false, []string{}, []string{})
+		result := vsc.generateUpstream(nil, test.name, test.upstream, false, []string{}, []string{}, nil)
 		if !reflect.DeepEqual(result, test.expected) {
 			t.Errorf("generateUpstream() returned %v but expected %v for the case of %v", result, test.expected, test.msg)
 		}
@@ -3074,6 +5014,26 @@ func TestIsTLSEnabled(t *testing.T) {
 			},
 			expected: true,
 		},
+		{
+			upstream: conf_v1.Upstream{
+				Port: 8443,
+				TLS: conf_v1.UpstreamTLS{
+					Enable: true,
+					Ports:  []uint16{443, 8443},
+				},
+			},
+			expected: true,
+		},
+		{
+			upstream: conf_v1.Upstream{
+				Port: 8080,
+				TLS: conf_v1.UpstreamTLS{
+					Enable: true,
+					Ports:  []uint16{443, 8443},
+				},
+			},
+			expected: false,
+		},
 	}
 
 	for _, test := range tests {
@@ -3173,6 +5133,61 @@ func TestGenerateRewrites(t *testing.T) {
 	}
 }
 
+func TestGenerateRouteRewrites(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		rewrites []conf_v1.Rewrite
+		expected []string
+		msg      string
+	}{
+		{
+			rewrites: nil,
+			expected: nil,
+			msg:      "no rewrites",
+		},
+		{
+			rewrites: []conf_v1.Rewrite{{Regex: "^/old(.*)$", Replacement: "/new$1"}},
+			expected: []string{`"^/old(.*)$" "/new$1"`},
+			msg:      "rewrite without flag",
+		},
+		{
+			rewrites: []conf_v1.Rewrite{{Regex: "^/old(.*)$", Replacement: "/new$1", Flag: "last"}},
+			expected: []string{`"^/old(.*)$" "/new$1" last`},
+			msg:      "rewrite with last flag",
+		},
+		{
+			rewrites: []conf_v1.Rewrite{{Regex: "^/old(.*)$", Replacement: "/new$1", Flag: "break"}},
+			expected: []string{`"^/old(.*)$" "/new$1" break`},
+			msg:      "rewrite with break flag",
+		},
+		{
+			rewrites: []conf_v1.Rewrite{{Regex: "^/old(.*)$", Replacement: "/new$1", Flag: "redirect"}},
+			expected: []string{`"^/old(.*)$" "/new$1" redirect`},
+			msg:      "rewrite with redirect flag",
+		},
+		{
+			rewrites: []conf_v1.Rewrite{{Regex: "^/old(.*)$", Replacement: "/new$1", Flag: "permanent"}},
+			expected: []string{`"^/old(.*)$" "/new$1" permanent`},
+			msg:      "rewrite with permanent flag",
+		},
+		{
+			rewrites: []conf_v1.Rewrite{
+				{Regex: "^/tea/(.*)$", Replacement: "/green-tea/$1"},
+				{Regex: "^/green-tea/(.*)$", Replacement: "https://tea.example.com/$1", Flag: "permanent"},
+			},
+			expected: []string{`"^/tea/(.*)$" "/green-tea/$1"`, `"^/green-tea/(.*)$" "https://tea.example.com/$1" permanent`},
+			msg:      "multiple rewrites",
+		},
+	}
+
+	for _, test := range tests {
+		result := generateRouteRewrites(test.rewrites)
+		if diff := cmp.Diff(test.expected, result); diff != "" {
+			t.Errorf("generateRouteRewrites() '%v' mismatch (-want +got):\n%s", test.msg, diff)
+		}
+	}
+}
+
 func TestGenerateProxyPassRewrite(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -3443,13 +5458,64 @@ func TestGenerateProxyHideHeaders(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		result := generateProxyHideHeaders(test.proxy)
+		result := generateProxyHideHeaders(test.proxy, nil)
 		if !reflect.DeepEqual(result, test.expected) {
 			t.Errorf("generateProxyHideHeaders(%v) returned %v but expected %v", test.proxy, result, test.expected)
 		}
 	}
 }
 
+func TestGenerateProxyHideHeadersWithDefaults(t *testing.T) {
+	t.Parallel()
+	defaultHideHeaders := []string{"Server", "X-Powered-By"}
+	tests := []struct {
+		proxy    *conf_v1.ActionProxy
+		expected []string
+		msg      string
+	}{
+		{
+			proxy:    nil,
+			expected: []string{"Server", "X-Powered-By"},
+			msg:      "default headers only",
+		},
+		{
+			proxy: &conf_v1.ActionProxy{
+				ResponseHeaders: &conf_v1.ProxyResponseHeaders{
+					Hide: []string{"X-Internal-Id", "server"},
+				},
+			},
+			expected: []string{"X-Internal-Id", "server", "X-Powered-By"},
+			msg:      "merged and deduped with the hidden headers",
+		},
+		{
+			proxy: &conf_v1.ActionProxy{
+				ResponseHeaders: &conf_v1.ProxyResponseHeaders{
+					Pass: []string{"X-Powered-By"},
+				},
+			},
+			expected: []string{"Server"},
+			msg:      "passed default header is not hidden",
+		},
+		{
+			proxy: &conf_v1.ActionProxy{
+				ResponseHeaders: &conf_v1.ProxyResponseHeaders{
+					Hide:         []string{"X-Internal-Id"},
+					HideDefaults: new(false),
+				},
+			},
+			expected: []string{"X-Internal-Id"},
+			msg:      "opt-out of the default headers",
+		},
+	}
+
+	for _, test := range tests {
+		result := generateProxyHideHeaders(test.proxy, defaultHideHeaders)
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("generateProxyHideHeaders() returned %v but expected %v for the case of %s", result, test.expected, test.msg)
+		}
+	}
+}
+
 func TestGenerateProxyPassHeaders(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -3760,6 +5826,68 @@ func TestGetExAuthServicePort(t *testing.T) {
 	}
 }
 
+func TestAddHSTSHideHeaderToLocationsWithHideHeaders(t *testing.T) {
+	t.Parallel()
+	hsts := &version2.HSTS{MaxAge: 2592000}
+	// the spare capacity would let an append overwrite the default hidden headers shared by the locations
+	defaultHideHeaders := append(make([]string, 0, 2), "X-Powered-By")
+	tests := []struct {
+		name      string
+		hsts      *version2.HSTS
+		locations []version2.Location
+		expected  []version2.Location
+	}{
+		{
+			name:      "nil HSTS — no locations modified",
+			hsts:      nil,
+			locations: []version2.Location{{Path: "/", ProxyHideHeaders: defaultHideHeaders}},
+			expected:  []version2.Location{{Path: "/", ProxyHideHeaders: []string{"X-Powered-By"}}},
+		},
+		{
+			name: "locations with and without hidden headers",
+			hsts: hsts,
+			locations: []version2.Location{
+				{Path: "/tea", ProxyHideHeaders: defaultHideHeaders},
+				{Path: "/coffee"},
+			},
+			expected: []version2.Location{
+				{Path: "/tea", ProxyHideHeaders: []string{"X-Powered-By", "Strict-Transport-Security"}},
+				{Path: "/coffee"},
+			},
+		},
+		{
+			name: "location that already hides the header",
+			hsts: hsts,
+			locations: []version2.Location{
+				{Path: "/", ProxyHideHeaders: []string{"strict-transport-security"}},
+			},
+			expected: []version2.Location{
+				{Path: "/", ProxyHideHeaders: []string{"strict-transport-security"}},
+			},
+		},
+		{
+			name: "location with its own HSTS",
+			hsts: hsts,
+			locations: []version2.Location{
+				{Path: "/", ProxyHideHeaders: defaultHideHeaders, HSTS: hsts},
+			},
+			expected: []version2.Location{
+				{Path: "/", ProxyHideHeaders: []string{"X-Powered-By"}, HSTS: hsts},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		addHSTSHideHeaderToLocationsWithHideHeaders(test.hsts, test.locations)
+		if diff := cmp.Diff(test.expected, test.locations); diff != "" {
+			t.Errorf("addHSTSHideHeaderToLocationsWithHideHeaders() mismatch for the case of %s (-want +got):\n%s", test.name, diff)
+		}
+	}
+	if extra := defaultHideHeaders[:2][1]; extra != "" {
+		t.Errorf("addHSTSHideHeaderToLocationsWithHideHeaders() wrote %q into the array of the default hidden headers", extra)
+	}
+}
+
 func TestAddHSTSToLocationsWithAddHeaders(t *testing.T) {
 	t.Parallel()
 	hsts := &version2.HSTS{MaxAge: 2592000}
@@ -3877,3 +6005,98 @@ func TestAddHSTSToLocationsWithAddHeaders(t *testing.T) {
 		})
 	}
 }
+
+func TestVirtualServerConfigHash(t *testing.T) {
+	t.Parallel()
+	newConfig := func(upstreamServer string, jwtAuthKeys ...string) version2.VirtualServerConfig {
+		jwtAuthList := make(map[string]*version2.JWTAuth)
+		for _, key := range jwtAuthKeys {
+			jwtAuthList[key] = &version2.JWTAuth{Key: key, Realm: "My API"}
+		}
+		return version2.VirtualServerConfig{
+			Upstreams: []version2.Upstream{
+				{
+					Name:    "vs_default_cafe_tea",
+					Servers: []version2.UpstreamServer{{Address: upstreamServer}},
+				},
+			},
+			Server: version2.Server{
+				ServerName:  "cafe.example.com",
+				JWTAuthList: jwtAuthList,
+			},
+		}
+	}
+
+	hash := VirtualServerConfigHash(newConfig("10.0.0.20:80", "default/jwt-policy-1", "default/jwt-policy-2", "default/jwt-policy-3"))
+
+	for range 10 {
+		got := VirtualServerConfigHash(newConfig("10.0.0.20:80", "default/jwt-policy-3", "default/jwt-policy-1", "default/jwt-policy-2"))
+		if got != hash {
+			t.Errorf("VirtualServerConfigHash() returned %q for an identical config, expected %q", got, hash)
+		}
+	}
+
+	got := VirtualServerConfigHash(newConfig("10.0.0.21:80", "default/jwt-policy-1", "default/jwt-policy-2", "default/jwt-policy-3"))
+	if got == hash {
+		t.Errorf("VirtualServerConfigHash() returned the same hash %q for a config with a changed upstream", got)
+	}
+}
+
+func TestRenderVirtualServerConfig(t *testing.T) {
+	t.Parallel()
+	virtualServerEx := VirtualServerEx{
+		VirtualServer: &conf_v1.VirtualServer{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "cafe",
+				Namespace: "default",
+			},
+			Spec: conf_v1.VirtualServerSpec{
+				Host: "cafe.example.com",
+				Upstreams: []conf_v1.Upstream{
+					{
+						Name:    "tea",
+						Service: "tea-svc",
+						Port:    80,
+					},
+				},
+				Routes: []conf_v1.Route{
+					{
+						Path: "/tea",
+						Action: &conf_v1.Action{
+							Pass: "tea",
+						},
+					},
+				},
+			},
+		},
+		Endpoints: map[string][]string{
+			"default/tea-svc:80": {"10.0.0.20:80"},
+		},
+	}
+	cfgParams := ConfigParams{Context: context.Background()}
+	vsc := newVirtualServerConfigurator(&cfgParams, false, false, &StaticConfigParams{}, false, &fakeBV)
+	vsCfg, _ := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+
+	templateExecutor, err := version2.NewTemplateExecutor("version2/nginx.virtualserver.tmpl", "version2/nginx.transportserver.tmpl", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := RenderVirtualServerConfig(templateExecutor, &vsCfg)
+	if err != nil {
+		t.Fatalf("RenderVirtualServerConfig() returned unexpected error: %v", err)
+	}
+
+	wantDirectives := []string{
+		"upstream vs_default_cafe_tea {",
+		"server 10.0.0.20:80 ",
+		"server_name cafe.example.com;",
+		"location /tea {",
+		"proxy_pass http://vs_default_cafe_tea;",
+	}
+	for _, want := range wantDirectives {
+		if !strings.Contains(got, want) {
+			t.Errorf("RenderVirtualServerConfig() returned config without %q", want)
+		}
+	}
+}
@@ -4875,3 +4875,86 @@ func TestGenerateVirtualServerConfigExternalAuthPolicy(t *testing.T) {
 		t.Errorf("GenerateVirtualServerConfig returned warnings: %v", vsc.warnings)
 	}
 }
+
+func TestGenerateVirtualServerConfigIngressMTLSCrlFiles(t *testing.T) {
+	t.Parallel()
+
+	virtualServerEx := VirtualServerEx{
+		VirtualServer: &conf_v1.VirtualServer{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "cafe",
+				Namespace: "default",
+			},
+			Spec: conf_v1.VirtualServerSpec{
+				Host: "cafe.example.com",
+				TLS: &conf_v1.TLS{
+					Secret: "cafe-secret",
+				},
+				Policies: []conf_v1.PolicyReference{
+					{
+						Name: "ingress-mtls-policy",
+					},
+				},
+				Upstreams: []conf_v1.Upstream{
+					{
+						Name:    "tea",
+						Service: "tea-svc",
+						Port:    80,
+					},
+				},
+				Routes: []conf_v1.Route{
+					{
+						Path:   "/",
+						Action: &conf_v1.Action{Pass: "tea"},
+					},
+				},
+			},
+		},
+		Policies: map[string]*conf_v1.Policy{
+			"default/ingress-mtls-policy": {
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "ingress-mtls-policy",
+					Namespace: "default",
+				},
+				Spec: conf_v1.PolicySpec{
+					IngressMTLS: &conf_v1.IngressMTLS{
+						ClientCertSecret: "ingress-mtls-secret",
+						CrlFileNames:     []string{"root-ca.crl", "intermediate-ca.crl"},
+					},
+				},
+			},
+		},
+		SecretRefs: map[string]*secrets.SecretReference{
+			"default/cafe-secret": {
+				Secret: &api_v1.Secret{
+					Type: api_v1.SecretTypeTLS,
+				},
+				Path: "/etc/nginx/secrets/default-cafe-secret",
+			},
+			"default/ingress-mtls-secret": {
+				Secret: &api_v1.Secret{
+					Type: secrets.SecretTypeCA,
+				},
+				Path: "/etc/nginx/secrets/default-ingress-mtls-secret-ca.crt",
+			},
+		},
+	}
+
+	vsc := newVirtualServerConfigurator(&ConfigParams{Context: context.Background()}, false, false, &StaticConfigParams{}, false, &fakeBV)
+	result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+	if len(warnings) != 0 {
+		t.Errorf("GenerateVirtualServerConfig() returned unexpected warnings: %v", warnings)
+	}
+
+	// the config rendered without the Configurator, for example, for a preview, must include the combined CRL file
+	expected := &version2.IngressMTLS{
+		ClientCert:     "/etc/nginx/secrets/default-ingress-mtls-secret-ca.crt",
+		ClientCrl:      "/etc/nginx/secrets/ingress_mtls_crl_default_cafe",
+		VerifyClient:   "on",
+		VerifyDepth:    1,
+		ClientCrlFiles: []string{"/etc/nginx/secrets/root-ca.crl", "/etc/nginx/secrets/intermediate-ca.crl"},
+	}
+	if diff := cmp.Diff(expected, result.Server.IngressMTLS); diff != "" {
+		t.Errorf("GenerateVirtualServerConfig() returned unexpected IngressMTLS (-want +got):\n%s", diff)
+	}
+}
@@ -132,6 +132,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 								Result: "rl_default_cafe_vs_match_premium",
 							},
 						},
+						Volatile: true,
 					},
 					{
 						Source:   "$rl_default_cafe_vs_group_user_type_tier_spec_Lw",
@@ -146,6 +147,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 								Result: "Val$jwt_claim_sub",
 							},
 						},
+						Volatile: true,
 					},
 					{
 						Source:   "$rl_default_cafe_vs_group_user_type_tier_spec_Lw",
@@ -160,6 +162,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 								Result: "Val$jwt_claim_sub",
 							},
 						},
+						Volatile: true,
 					},
 				},
 				AuthJWTClaimSets: []version2.AuthJWTClaimSet{{Variable: "$jwt_default_cafe_vs_user_type_tier", Claim: "user_type tier"}},
@@ -230,6 +233,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 						{ZoneName: "pol_rl_default_premium_rate_limit_policy_default_cafe_vs_sync", Burst: 0, NoDelay: false, Delay: 0},
 						{ZoneName: "pol_rl_default_basic_rate_limit_policy_default_cafe_vs_sync", Burst: 0, NoDelay: false, Delay: 0},
 					},
+					Variables: []version2.Variable{{Name: "$rl_tier", Value: "$rl_default_cafe_vs_group_user_type_tier_spec_Lw"}},
 					LimitReqOptions: version2.LimitReqOptions{
 						DryRun:     false,
 						LogLevel:   "error",
@@ -372,6 +376,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 								Result: "rl_default_cafe_vs_match_premium",
 							},
 						},
+						Volatile: true,
 					},
 					{
 						Source:   "$rl_default_cafe_vs_group_user_type_tier_spec_Lw",
@@ -386,6 +391,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 								Result: "Val$jwt_claim_sub",
 							},
 						},
+						Volatile: true,
 					},
 					{
 						Source:   "$rl_default_cafe_vs_group_user_type_tier_spec_Lw",
@@ -400,6 +406,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 								Result: "Val$jwt_claim_sub",
 							},
 						},
+						Volatile: true,
 					},
 				},
 				AuthJWTClaimSets: []version2.AuthJWTClaimSet{{Variable: "$jwt_default_cafe_vs_user_type_tier", Claim: "user_type tier"}},
@@ -468,6 +475,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 						{ZoneName: "pol_rl_default_premium_rate_limit_policy_default_cafe_vs", Burst: 0, NoDelay: false, Delay: 0},
 						{ZoneName: "pol_rl_default_basic_rate_limit_policy_default_cafe_vs", Burst: 0, NoDelay: false, Delay: 0},
 					},
+					Variables: []version2.Variable{{Name: "$rl_tier", Value: "$rl_default_cafe_vs_group_user_type_tier_spec_Lw"}},
 					LimitReqOptions: version2.LimitReqOptions{
 						DryRun:     false,
 						LogLevel:   "error",
@@ -615,6 +623,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 								Result: "rl_default_cafe_vs_match_premium",
 							},
 						},
+						Volatile: true,
 					},
 					{
 						Source:   "$rl_default_cafe_vs_group_user_type_tier_spec_Lw",
@@ -629,6 +638,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 								Result: "Val$jwt_claim_sub",
 							},
 						},
+						Volatile: true,
 					},
 					{
 						Source:   "$rl_default_cafe_vs_group_user_type_tier_spec_Lw",
@@ -643,6 +653,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 								Result: "Val$jwt_claim_sub",
 							},
 						},
+						Volatile: true,
 					},
 				},
 				AuthJWTClaimSets: []version2.AuthJWTClaimSet{{Variable: "$jwt_default_cafe_vs_user_type_tier", Claim: "user_type tier"}},
@@ -712,6 +723,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 						{ZoneName: "pol_rl_default_premium_rate_limit_policy_default_cafe_vs", Burst: 0, NoDelay: false, Delay: 0},
 						{ZoneName: "pol_rl_default_basic_rate_limit_policy_default_cafe_vs", Burst: 0, NoDelay: false, Delay: 0},
 					},
+					Variables: []version2.Variable{{Name: "$rl_tier", Value: "$rl_default_cafe_vs_group_user_type_tier_spec_Lw"}},
 					LimitReqOptions: version2.LimitReqOptions{
 						DryRun:     false,
 						LogLevel:   "error",
@@ -859,6 +871,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 								Result: "rl_default_cafe_vs_match_premium",
 							},
 						},
+						Volatile: true,
 					},
 					{
 						Source:   "$rl_default_cafe_vs_group_user_type_tier_route_L3RlYQ",
@@ -873,6 +886,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 								Result: "Val$jwt_claim_sub",
 							},
 						},
+						Volatile: true,
 					},
 					{
 						Source:   "$rl_default_cafe_vs_group_user_type_tier_route_L3RlYQ",
@@ -887,6 +901,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 								Result: "Val$jwt_claim_sub",
 							},
 						},
+						Volatile: true,
 					},
 				},
 				AuthJWTClaimSets: []version2.AuthJWTClaimSet{{Variable: "$jwt_default_cafe_vs_user_type_tier", Claim: "user_type tier"}},
@@ -967,6 +982,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 								{ZoneName: "pol_rl_default_premium_rate_limit_policy_default_cafe_vs", Burst: 0, NoDelay: false, Delay: 0},
 								{ZoneName: "pol_rl_default_basic_rate_limit_policy_default_cafe_vs", Burst: 0, NoDelay: false, Delay: 0},
 							},
+							Variables: []version2.Variable{{Name: "$rl_tier", Value: "$rl_default_cafe_vs_group_user_type_tier_route_L3RlYQ"}},
 							LimitReqOptions: version2.LimitReqOptions{
 								DryRun:     false,
 								LogLevel:   "error",
@@ -1111,6 +1127,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 								Result: "rl_default_cafe_vs_match_premium",
 							},
 						},
+						Volatile: true,
 					},
 					{
 						Source:   "$rl_default_cafe_vs_group_user_type_tier_route_L3RlYQ",
@@ -1125,6 +1142,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 								Result: "Val$jwt_claim_sub",
 							},
 						},
+						Volatile: true,
 					},
 					{
 						Source:   "$rl_default_cafe_vs_group_user_type_tier_route_L3RlYQ",
@@ -1139,6 +1157,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 								Result: "Val$jwt_claim_sub",
 							},
 						},
+						Volatile: true,
 					},
 					{
 						Source:   "$jwt_default_cafe_vs_user_type_tier",
@@ -1157,6 +1176,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 								Result: "rl_default_cafe_vs_match_premium",
 							},
 						},
+						Volatile: true,
 					},
 					{
 						Source:   "$rl_default_cafe_vs_group_user_type_tier_route_L2NvZmZlZQ",
@@ -1171,6 +1191,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 								Result: "Val$jwt_claim_sub",
 							},
 						},
+						Volatile: true,
 					},
 					{
 						Source:   "$rl_default_cafe_vs_group_user_type_tier_route_L2NvZmZlZQ",
@@ -1185,6 +1206,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 								Result: "Val$jwt_claim_sub",
 							},
 						},
+						Volatile: true,
 					},
 				},
 				AuthJWTClaimSets: []version2.AuthJWTClaimSet{{Variable: "$jwt_default_cafe_vs_user_type_tier", Claim: "user_type tier"}},
@@ -1265,6 +1287,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 								{ZoneName: "pol_rl_default_premium_rate_limit_policy_default_cafe_vs", Burst: 0, NoDelay: false, Delay: 0},
 								{ZoneName: "pol_rl_default_basic_rate_limit_policy_default_cafe_vs", Burst: 0, NoDelay: false, Delay: 0},
 							},
+							Variables: []version2.Variable{{Name: "$rl_tier", Value: "$rl_default_cafe_vs_group_user_type_tier_route_L3RlYQ"}},
 							LimitReqOptions: version2.LimitReqOptions{
 								DryRun:     false,
 								LogLevel:   "error",
@@ -1285,6 +1308,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 								{ZoneName: "pol_rl_default_premium_rate_limit_policy_default_cafe_vs", Burst: 0, NoDelay: false, Delay: 0},
 								{ZoneName: "pol_rl_default_basic_rate_limit_policy_default_cafe_vs", Burst: 0, NoDelay: false, Delay: 0},
 							},
+							Variables: []version2.Variable{{Name: "$rl_tier", Value: "$rl_default_cafe_vs_group_user_type_tier_route_L2NvZmZlZQ"}},
 							LimitReqOptions: version2.LimitReqOptions{
 								DryRun:     false,
 								LogLevel:   "error",
@@ -1429,6 +1453,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 								Result: "rl_default_cafe_vs_match_premium",
 							},
 						},
+						Volatile: true,
 					},
 					{
 						Source:   "$rl_default_cafe_vs_group_user_type_tier_subroute_L3RlYQ",
@@ -1443,6 +1468,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 								Result: "Val$jwt_claim_sub",
 							},
 						},
+						Volatile: true,
 					},
 					{
 						Source:   "$rl_default_cafe_vs_group_user_type_tier_subroute_L3RlYQ",
@@ -1457,6 +1483,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 								Result: "Val$jwt_claim_sub",
 							},
 						},
+						Volatile: true,
 					},
 				},
 				AuthJWTClaimSets: []version2.AuthJWTClaimSet{{Variable: "$jwt_default_cafe_vs_user_type_tier", Claim: "user_type tier"}},
@@ -1551,6 +1578,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 								{ZoneName: "pol_rl_default_premium_rate_limit_policy_default_cafe_vs", Burst: 0, NoDelay: false, Delay: 0},
 								{ZoneName: "pol_rl_default_basic_rate_limit_policy_default_cafe_vs", Burst: 0, NoDelay: false, Delay: 0},
 							},
+							Variables: []version2.Variable{{Name: "$rl_tier", Value: "$rl_default_cafe_vs_group_user_type_tier_subroute_L3RlYQ"}},
 							LimitReqOptions: version2.LimitReqOptions{
 								DryRun:     false,
 								LogLevel:   "error",
@@ -1764,6 +1792,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 								Result: "rl_default_cafe_vs_match_premium",
 							},
 						},
+						Volatile: true,
 					},
 					{
 						Source:   "$rl_default_cafe_vs_group_user_type_tier_spec_Lw",
@@ -1778,6 +1807,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 								Result: "Val$jwt_claim_sub",
 							},
 						},
+						Volatile: true,
 					},
 					{
 						Source:   "$rl_default_cafe_vs_group_user_type_tier_spec_Lw",
@@ -1792,6 +1822,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 								Result: "Val$jwt_claim_sub",
 							},
 						},
+						Volatile: true,
 					},
 					{
 						Source:   "$jwt_default_cafe_vs_user_type_tier",
@@ -1814,6 +1845,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 								Result: "rl_default_cafe_vs_match_gold",
 							},
 						},
+						Volatile: true,
 					},
 					{
 						Source:   "$rl_default_cafe_vs_group_user_type_tier_subroute_L3RlYQ",
@@ -1828,6 +1860,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 								Result: "Val$jwt_claim_sub",
 							},
 						},
+						Volatile: true,
 					},
 					{
 						Source:   "$rl_default_cafe_vs_group_user_type_tier_subroute_L3RlYQ",
@@ -1842,6 +1875,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 								Result: "Val$jwt_claim_sub",
 							},
 						},
+						Volatile: true,
 					},
 					{
 						Source:   "$rl_default_cafe_vs_group_user_type_tier_subroute_L3RlYQ",
@@ -1856,6 +1890,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 								Result: "Val$jwt_claim_sub",
 							},
 						},
+						Volatile: true,
 					},
 				},
 				AuthJWTClaimSets: []version2.AuthJWTClaimSet{{Variable: "$jwt_default_cafe_vs_user_type_tier", Claim: "user_type tier"}},
@@ -1959,6 +1994,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 						{ZoneName: "pol_rl_default_premium_rate_limit_policy_default_cafe_vs", Burst: 0, NoDelay: false, Delay: 0},
 						{ZoneName: "pol_rl_default_basic_rate_limit_policy_default_cafe_vs", Burst: 0, NoDelay: false, Delay: 0},
 					},
+					Variables: []version2.Variable{{Name: "$rl_tier", Value: "$rl_default_cafe_vs_group_user_type_tier_spec_Lw"}},
 					LimitReqOptions: version2.LimitReqOptions{
 						DryRun:     false,
 						LogLevel:   "error",
@@ -1994,6 +2030,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 								{ZoneName: "pol_rl_default_silver_rate_limit_policy_default_cafe_vs", Burst: 0, NoDelay: false, Delay: 0},
 								{ZoneName: "pol_rl_default_bronze_rate_limit_policy_default_cafe_vs", Burst: 0, NoDelay: false, Delay: 0},
 							},
+							Variables: []version2.Variable{{Name: "$rl_tier", Value: "$rl_default_cafe_vs_group_user_type_tier_subroute_L3RlYQ"}},
 							LimitReqOptions: version2.LimitReqOptions{
 								DryRun:     false,
 								LogLevel:   "error",
@@ -2263,6 +2300,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 						{ZoneName: "pol_rl_default_premium_rate_limit_policy_default_cafe_vs_sync", Burst: 0, NoDelay: false, Delay: 0},
 						{ZoneName: "pol_rl_default_basic_rate_limit_policy_default_cafe_vs_sync", Burst: 0, NoDelay: false, Delay: 0},
 					},
+					Variables: []version2.Variable{{Name: "$rl_tier", Value: "$rl_default_cafe_vs_variable_apikey_client_name_spec_Lw"}},
 					LimitReqOptions: version2.LimitReqOptions{
 						DryRun:     false,
 						LogLevel:   "error",
@@ -2557,6 +2595,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 						{ZoneName: "pol_rl_default_premium_rate_limit_policy_default_cafe_vs", Burst: 0, NoDelay: false, Delay: 0},
 						{ZoneName: "pol_rl_default_basic_rate_limit_policy_default_cafe_vs", Burst: 0, NoDelay: false, Delay: 0},
 					},
+					Variables: []version2.Variable{{Name: "$rl_tier", Value: "$rl_default_cafe_vs_variable_apikey_client_name_spec_Lw"}},
 					LimitReqOptions: version2.LimitReqOptions{
 						DryRun:     false,
 						LogLevel:   "error",
@@ -2857,6 +2896,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 						{ZoneName: "pol_rl_default_premium_rate_limit_policy_default_cafe_vs", Burst: 0, NoDelay: false, Delay: 0},
 						{ZoneName: "pol_rl_default_basic_rate_limit_policy_default_cafe_vs", Burst: 0, NoDelay: false, Delay: 0},
 					},
+					Variables: []version2.Variable{{Name: "$rl_tier", Value: "$rl_default_cafe_vs_variable_apikey_client_name_spec_Lw"}},
 					LimitReqOptions: version2.LimitReqOptions{
 						DryRun:     false,
 						LogLevel:   "error",
@@ -3171,6 +3211,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 								{ZoneName: "pol_rl_default_premium_rate_limit_policy_default_cafe_vs", Burst: 0, NoDelay: false, Delay: 0},
 								{ZoneName: "pol_rl_default_basic_rate_limit_policy_default_cafe_vs", Burst: 0, NoDelay: false, Delay: 0},
 							},
+							Variables: []version2.Variable{{Name: "$rl_tier", Value: "$rl_default_cafe_vs_variable_apikey_client_name_route_L3RlYQ"}},
 							LimitReqOptions: version2.LimitReqOptions{
 								DryRun:     false,
 								LogLevel:   "error",
@@ -3528,6 +3569,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 								{ZoneName: "pol_rl_default_premium_rate_limit_policy_default_cafe_vs", Burst: 0, NoDelay: false, Delay: 0},
 								{ZoneName: "pol_rl_default_basic_rate_limit_policy_default_cafe_vs", Burst: 0, NoDelay: false, Delay: 0},
 							},
+							Variables: []version2.Variable{{Name: "$rl_tier", Value: "$rl_default_cafe_vs_variable_apikey_client_name_route_L3RlYQ"}},
 							LimitReqOptions: version2.LimitReqOptions{
 								DryRun:     false,
 								LogLevel:   "error",
@@ -3548,6 +3590,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 								{ZoneName: "pol_rl_default_premium_rate_limit_policy_default_cafe_vs", Burst: 0, NoDelay: false, Delay: 0},
 								{ZoneName: "pol_rl_default_basic_rate_limit_policy_default_cafe_vs", Burst: 0, NoDelay: false, Delay: 0},
 							},
+							Variables: []version2.Variable{{Name: "$rl_tier", Value: "$rl_default_cafe_vs_variable_apikey_client_name_route_L2NvZmZlZQ"}},
 							LimitReqOptions: version2.LimitReqOptions{
 								DryRun:     false,
 								LogLevel:   "error",
@@ -3870,6 +3913,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 								{ZoneName: "pol_rl_default_premium_rate_limit_policy_default_cafe_vs", Burst: 0, NoDelay: false, Delay: 0},
 								{ZoneName: "pol_rl_default_basic_rate_limit_policy_default_cafe_vs", Burst: 0, NoDelay: false, Delay: 0},
 							},
+							Variables: []version2.Variable{{Name: "$rl_tier", Value: "$rl_default_cafe_vs_variable_apikey_client_name_subroute_L3RlYQ"}},
 							LimitReqOptions: version2.LimitReqOptions{
 								DryRun:     false,
 								LogLevel:   "error",
@@ -4238,6 +4282,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 								{ZoneName: "pol_rl_default_premium_rate_limit_policy_default_cafe_vs", Burst: 0, NoDelay: false, Delay: 0},
 								{ZoneName: "pol_rl_default_basic_rate_limit_policy_default_cafe_vs", Burst: 0, NoDelay: false, Delay: 0},
 							},
+							Variables: []version2.Variable{{Name: "$rl_tier", Value: "$rl_default_cafe_vs_variable_apikey_client_name_route_L2NvZmZlZQ"}},
 							LimitReqOptions: version2.LimitReqOptions{
 								DryRun:     false,
 								LogLevel:   "error",
@@ -4258,6 +4303,7 @@ func TestGenerateVirtualServerConfigRateLimitGroups(t *testing.T) {
 								{ZoneName: "pol_rl_default_premium_rate_limit_policy_default_cafe_vs", Burst: 0, NoDelay: false, Delay: 0},
 								{ZoneName: "pol_rl_default_basic_rate_limit_policy_default_cafe_vs", Burst: 0, NoDelay: false, Delay: 0},
 							},
+							Variables: []version2.Variable{{Name: "$rl_tier", Value: "$rl_default_cafe_vs_variable_apikey_client_name_subroute_L3RlYQ"}},
 							LimitReqOptions: version2.LimitReqOptions{
 								DryRun:     false,
 								LogLevel:   "error",
@@ -4434,6 +4480,7 @@ func TestGenerateVirtualServerConfigWithRateLimitGroupsWarning(t *testing.T) {
 						Result: "Val$jwt_claim_sub",
 					},
 				},
+				Volatile: true,
 			},
 			{
 				Source:   "$rl_default_cafe_vs_group_user_type_tier",
@@ -4448,6 +4495,7 @@ func TestGenerateVirtualServerConfigWithRateLimitGroupsWarning(t *testing.T) {
 						Result: "Val$jwt_claim_sub",
 					},
 				},
+				Volatile: true,
 			},
 			{
 				Source:   "$jwt_default_cafe_vs_user_type_tier",
@@ -4462,6 +4510,7 @@ func TestGenerateVirtualServerConfigWithRateLimitGroupsWarning(t *testing.T) {
 						Result: "rl_default_cafe_vs_match_premium",
 					},
 				},
+				Volatile: true,
 			},
 		},
 		AuthJWTClaimSets: []version2.AuthJWTClaimSet{{Variable: "$jwt_default_cafe_vs_user_type_tier", Claim: "user_type tier"}},
@@ -4,11 +4,14 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"slices"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/nginx/kubernetes-ingress/internal/configs/version2"
+	"github.com/nginx/kubernetes-ingress/internal/k8s/secrets"
 	conf_v1 "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
+	api_v1 "k8s.io/api/core/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -1755,6 +1758,9 @@ func TestGenerateSplits(t *testing.T) {
 		t.Run(test.msg, func(t *testing.T) {
 			resultSplitClients, resultLocations, resultReturnLocations, _, _, _, _ := generateSplits(
 				test.splits,
+				nil,
+				nil,
+				nil,
 				upstreamNamer,
 				crUpstreams,
 				variableNamer,
@@ -1785,6 +1791,287 @@ func TestGenerateSplits(t *testing.T) {
 	}
 }
 
+func TestGenerateSplitsWithStickyCookie(t *testing.T) {
+	t.Parallel()
+	splits := []conf_v1.Split{
+		{
+			Weight: 90,
+			Action: &conf_v1.Action{
+				Pass: "coffee-v1",
+			},
+		},
+		{
+			Weight: 10,
+			Action: &conf_v1.Action{
+				Pass: "coffee-v2",
+			},
+		},
+	}
+	stickyCookie := &conf_v1.SplitStickyCookie{
+		Name: "canary",
+	}
+
+	virtualServer := conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
+	}
+	upstreamNamer := NewUpstreamNamerForVirtualServer(&virtualServer)
+	variableNamer := NewVSVariableNamer(&virtualServer)
+	cfgParams := ConfigParams{Context: context.Background()}
+	crUpstreams := map[string]conf_v1.Upstream{
+		"vs_default_cafe_coffee-v1": {
+			Service: "coffee-v1",
+		},
+		"vs_default_cafe_coffee-v2": {
+			Service: "coffee-v2",
+		},
+	}
+
+	expectedSplitClients := []version2.SplitClient{
+		{
+			Source:   "$vs_default_cafe_splits_1_sticky_key",
+			Variable: "$vs_default_cafe_splits_1",
+			Distributions: []version2.Distribution{
+				{
+					Weight: "90%",
+					Value:  "/internal_location_splits_1_split_0",
+				},
+				{
+					Weight: "10%",
+					Value:  "/internal_location_splits_1_split_1",
+				},
+			},
+		},
+	}
+	expectedMaps := []version2.Map{
+		{
+			Source:   "$cookie_canary",
+			Variable: "$vs_default_cafe_splits_1_sticky_key",
+			Parameters: []version2.Parameter{
+				{Value: `""`, Result: "$request_id"},
+				{Value: "default", Result: "$cookie_canary"},
+			},
+		},
+		{
+			Source:   "$cookie_canary",
+			Variable: "$vs_default_cafe_splits_1_set_cookie",
+			Parameters: []version2.Parameter{
+				{Value: `""`, Result: `"canary=$request_id; Path=/"`},
+				{Value: "default", Result: `""`},
+			},
+		},
+	}
+	expectedHeader := version2.AddHeader{
+		Header: version2.Header{
+			Name:  "Set-Cookie",
+			Value: "$vs_default_cafe_splits_1_set_cookie",
+		},
+		Always: true,
+	}
+
+	splitClients, locations, _, maps, _, _, _ := generateSplits(
+		splits,
+		nil,
+		stickyCookie,
+		nil,
+		upstreamNamer,
+		crUpstreams,
+		variableNamer,
+		1,
+		&cfgParams,
+		errorPageDetails{},
+		"/path",
+		"",
+		false,
+		0,
+		false,
+		"",
+		"",
+		Warnings{},
+		false,
+	)
+
+	if diff := cmp.Diff(expectedSplitClients, splitClients); diff != "" {
+		t.Errorf("generateSplits() split clients mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(expectedMaps, maps); diff != "" {
+		t.Errorf("generateSplits() maps mismatch (-want +got):\n%s", diff)
+	}
+	for _, loc := range locations {
+		if !slices.Contains(loc.AddHeaders, expectedHeader) {
+			t.Errorf("generateSplits() location %s returned add headers %v, expected to contain %v", loc.Path, loc.AddHeaders, expectedHeader)
+		}
+	}
+}
+
+func TestGenerateSplitsWithSharedWeights(t *testing.T) {
+	t.Parallel()
+	splits := []conf_v1.Split{
+		{
+			Weight: 90,
+			Action: &conf_v1.Action{
+				Pass: "coffee-v1",
+			},
+		},
+		{
+			Weight: 10,
+			Action: &conf_v1.Action{
+				Pass: "coffee-v2",
+			},
+		},
+	}
+	splitWeights := &conf_v1.SplitWeights{
+		Key: "canary",
+	}
+
+	virtualServer := conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
+	}
+	upstreamNamer := NewUpstreamNamerForVirtualServer(&virtualServer)
+	variableNamer := NewVSVariableNamer(&virtualServer)
+	cfgParams := ConfigParams{Context: context.Background()}
+	crUpstreams := map[string]conf_v1.Upstream{
+		"vs_default_cafe_coffee-v1": {
+			Service: "coffee-v1",
+		},
+		"vs_default_cafe_coffee-v2": {
+			Service: "coffee-v2",
+		},
+	}
+
+	expectedKeyVals := []version2.KeyVal{
+		{
+			Key:      `"canary"`,
+			Variable: "$vs_default_cafe_keyval_split_weights_1",
+			ZoneName: "split_weights",
+		},
+	}
+
+	// The weights are read from the shared keyval even when the weight changes are applied dynamically.
+	for _, weightChangesDynamicReload := range []bool{false, true} {
+		splitClients, _, _, maps, keyValZones, keyVals, twoWaySplitClients := generateSplits(
+			splits,
+			nil,
+			nil,
+			splitWeights,
+			upstreamNamer,
+			crUpstreams,
+			variableNamer,
+			1,
+			&cfgParams,
+			errorPageDetails{},
+			"/path",
+			"",
+			false,
+			0,
+			false,
+			"",
+			"",
+			Warnings{},
+			weightChangesDynamicReload,
+		)
+
+		if len(splitClients) != splitClientAmountWhenWeightChangesDynamicReload {
+			t.Errorf("generateSplits() returned %d split clients, expected %d", len(splitClients), splitClientAmountWhenWeightChangesDynamicReload)
+		}
+		if diff := cmp.Diff(expectedKeyVals, keyVals); diff != "" {
+			t.Errorf("generateSplits() keyvals mismatch (-want +got):\n%s", diff)
+		}
+		if len(keyValZones) != 0 {
+			t.Errorf("generateSplits() returned keyval zones %v, expected none for the shared zone", keyValZones)
+		}
+		if len(twoWaySplitClients) != 0 {
+			t.Errorf("generateSplits() returned two-way split clients %v, expected none for shared weights", twoWaySplitClients)
+		}
+		if len(maps) != 1 {
+			t.Fatalf("generateSplits() returned %d maps, expected 1", len(maps))
+		}
+
+		weightsMap := maps[0]
+		if weightsMap.Source != "$vs_default_cafe_keyval_split_weights_1" {
+			t.Errorf("generateSplits() returned map with source %q, expected the shared keyval", weightsMap.Source)
+		}
+		if weightsMap.Variable != "$vs_default_cafe_map_split_clients_1" {
+			t.Errorf("generateSplits() returned map with variable %q", weightsMap.Variable)
+		}
+		expectedParams := []version2.Parameter{
+			{Value: "25", Result: "$vs_default_cafe_split_clients_1_25_75"},
+			{Value: "default", Result: "$vs_default_cafe_split_clients_1_90_10"},
+		}
+		for _, p := range expectedParams {
+			if !slices.Contains(weightsMap.Parameters, p) {
+				t.Errorf("generateSplits() returned map parameters %v, expected to contain %v", weightsMap.Parameters, p)
+			}
+		}
+	}
+}
+
+func TestGenerateSplitStickyCookieWithTTL(t *testing.T) {
+	t.Parallel()
+	virtualServer := conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
+	}
+	tests := []struct {
+		cookie            *conf_v1.SplitStickyCookie
+		expectedSetCookie string
+		msg               string
+	}{
+		{
+			cookie:            &conf_v1.SplitStickyCookie{Name: "canary"},
+			expectedSetCookie: `"canary=$request_id; Path=/"`,
+			msg:               "no ttl",
+		},
+		{
+			cookie:            &conf_v1.SplitStickyCookie{Name: "canary", TTL: new(3600)},
+			expectedSetCookie: `"canary=$request_id; Path=/; Max-Age=3600"`,
+			msg:               "ttl",
+		},
+		{
+			cookie:            &conf_v1.SplitStickyCookie{Name: "canary", Path: "/coffee", TTL: new(60)},
+			expectedSetCookie: `"canary=$request_id; Path=/coffee; Max-Age=60"`,
+			msg:               "ttl with path",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			t.Parallel()
+			source, maps, _ := generateSplitStickyCookie(test.cookie, 1, NewVSVariableNamer(&virtualServer))
+
+			// a client without the cookie, including one whose cookie has expired, is re-bucketed by $request_id
+			expectedMaps := []version2.Map{
+				{
+					Source:   "$cookie_canary",
+					Variable: source,
+					Parameters: []version2.Parameter{
+						{Value: `""`, Result: "$request_id"},
+						{Value: "default", Result: "$cookie_canary"},
+					},
+				},
+				{
+					Source:   "$cookie_canary",
+					Variable: "$vs_default_cafe_splits_1_set_cookie",
+					Parameters: []version2.Parameter{
+						{Value: `""`, Result: test.expectedSetCookie},
+						{Value: "default", Result: `""`},
+					},
+				},
+			}
+			if diff := cmp.Diff(expectedMaps, maps); diff != "" {
+				t.Errorf("generateSplitStickyCookie() maps mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestGenerateSplitsWeightChangesDynamicReload(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -3284,107 +3571,107 @@ func TestGenerateSplitsWeightChangesDynamicReload(t *testing.T) {
 			Source:   "$vs_default_cafe_keyval_split_clients_1",
 			Variable: "$vs_default_cafe_map_split_clients_1",
 			Parameters: []version2.Parameter{
-				{Value: `"vs_default_cafe_split_clients_1_0_100"`, Result: "$vs_default_cafe_split_clients_1_0_100"},
-				{Value: `"vs_default_cafe_split_clients_1_1_99"`, Result: "$vs_default_cafe_split_clients_1_1_99"},
-				{Value: `"vs_default_cafe_split_clients_1_2_98"`, Result: "$vs_default_cafe_split_clients_1_2_98"},
-				{Value: `"vs_default_cafe_split_clients_1_3_97"`, Result: "$vs_default_cafe_split_clients_1_3_97"},
-				{Value: `"vs_default_cafe_split_clients_1_4_96"`, Result: "$vs_default_cafe_split_clients_1_4_96"},
-				{Value: `"vs_default_cafe_split_clients_1_5_95"`, Result: "$vs_default_cafe_split_clients_1_5_95"},
-				{Value: `"vs_default_cafe_split_clients_1_6_94"`, Result: "$vs_default_cafe_split_clients_1_6_94"},
-				{Value: `"vs_default_cafe_split_clients_1_7_93"`, Result: "$vs_default_cafe_split_clients_1_7_93"},
-				{Value: `"vs_default_cafe_split_clients_1_8_92"`, Result: "$vs_default_cafe_split_clients_1_8_92"},
-				{Value: `"vs_default_cafe_split_clients_1_9_91"`, Result: "$vs_default_cafe_split_clients_1_9_91"},
-				{Value: `"vs_default_cafe_split_clients_1_10_90"`, Result: "$vs_default_cafe_split_clients_1_10_90"},
-				{Value: `"vs_default_cafe_split_clients_1_11_89"`, Result: "$vs_default_cafe_split_clients_1_11_89"},
-				{Value: `"vs_default_cafe_split_clients_1_12_88"`, Result: "$vs_default_cafe_split_clients_1_12_88"},
-				{Value: `"vs_default_cafe_split_clients_1_13_87"`, Result: "$vs_default_cafe_split_clients_1_13_87"},
-				{Value: `"vs_default_cafe_split_clients_1_14_86"`, Result: "$vs_default_cafe_split_clients_1_14_86"},
-				{Value: `"vs_default_cafe_split_clients_1_15_85"`, Result: "$vs_default_cafe_split_clients_1_15_85"},
-				{Value: `"vs_default_cafe_split_clients_1_16_84"`, Result: "$vs_default_cafe_split_clients_1_16_84"},
-				{Value: `"vs_default_cafe_split_clients_1_17_83"`, Result: "$vs_default_cafe_split_clients_1_17_83"},
-				{Value: `"vs_default_cafe_split_clients_1_18_82"`, Result: "$vs_default_cafe_split_clients_1_18_82"},
-				{Value: `"vs_default_cafe_split_clients_1_19_81"`, Result: "$vs_default_cafe_split_clients_1_19_81"},
-				{Value: `"vs_default_cafe_split_clients_1_20_80"`, Result: "$vs_default_cafe_split_clients_1_20_80"},
-				{Value: `"vs_default_cafe_split_clients_1_21_79"`, Result: "$vs_default_cafe_split_clients_1_21_79"},
-				{Value: `"vs_default_cafe_split_clients_1_22_78"`, Result: "$vs_default_cafe_split_clients_1_22_78"},
-				{Value: `"vs_default_cafe_split_clients_1_23_77"`, Result: "$vs_default_cafe_split_clients_1_23_77"},
-				{Value: `"vs_default_cafe_split_clients_1_24_76"`, Result: "$vs_default_cafe_split_clients_1_24_76"},
-				{Value: `"vs_default_cafe_split_clients_1_25_75"`, Result: "$vs_default_cafe_split_clients_1_25_75"},
-				{Value: `"vs_default_cafe_split_clients_1_26_74"`, Result: "$vs_default_cafe_split_clients_1_26_74"},
-				{Value: `"vs_default_cafe_split_clients_1_27_73"`, Result: "$vs_default_cafe_split_clients_1_27_73"},
-				{Value: `"vs_default_cafe_split_clients_1_28_72"`, Result: "$vs_default_cafe_split_clients_1_28_72"},
-				{Value: `"vs_default_cafe_split_clients_1_29_71"`, Result: "$vs_default_cafe_split_clients_1_29_71"},
-				{Value: `"vs_default_cafe_split_clients_1_30_70"`, Result: "$vs_default_cafe_split_clients_1_30_70"},
-				{Value: `"vs_default_cafe_split_clients_1_31_69"`, Result: "$vs_default_cafe_split_clients_1_31_69"},
-				{Value: `"vs_default_cafe_split_clients_1_32_68"`, Result: "$vs_default_cafe_split_clients_1_32_68"},
-				{Value: `"vs_default_cafe_split_clients_1_33_67"`, Result: "$vs_default_cafe_split_clients_1_33_67"},
-				{Value: `"vs_default_cafe_split_clients_1_34_66"`, Result: "$vs_default_cafe_split_clients_1_34_66"},
-				{Value: `"vs_default_cafe_split_clients_1_35_65"`, Result: "$vs_default_cafe_split_clients_1_35_65"},
-				{Value: `"vs_default_cafe_split_clients_1_36_64"`, Result: "$vs_default_cafe_split_clients_1_36_64"},
-				{Value: `"vs_default_cafe_split_clients_1_37_63"`, Result: "$vs_default_cafe_split_clients_1_37_63"},
-				{Value: `"vs_default_cafe_split_clients_1_38_62"`, Result: "$vs_default_cafe_split_clients_1_38_62"},
-				{Value: `"vs_default_cafe_split_clients_1_39_61"`, Result: "$vs_default_cafe_split_clients_1_39_61"},
-				{Value: `"vs_default_cafe_split_clients_1_40_60"`, Result: "$vs_default_cafe_split_clients_1_40_60"},
-				{Value: `"vs_default_cafe_split_clients_1_41_59"`, Result: "$vs_default_cafe_split_clients_1_41_59"},
-				{Value: `"vs_default_cafe_split_clients_1_42_58"`, Result: "$vs_default_cafe_split_clients_1_42_58"},
-				{Value: `"vs_default_cafe_split_clients_1_43_57"`, Result: "$vs_default_cafe_split_clients_1_43_57"},
-				{Value: `"vs_default_cafe_split_clients_1_44_56"`, Result: "$vs_default_cafe_split_clients_1_44_56"},
-				{Value: `"vs_default_cafe_split_clients_1_45_55"`, Result: "$vs_default_cafe_split_clients_1_45_55"},
-				{Value: `"vs_default_cafe_split_clients_1_46_54"`, Result: "$vs_default_cafe_split_clients_1_46_54"},
-				{Value: `"vs_default_cafe_split_clients_1_47_53"`, Result: "$vs_default_cafe_split_clients_1_47_53"},
-				{Value: `"vs_default_cafe_split_clients_1_48_52"`, Result: "$vs_default_cafe_split_clients_1_48_52"},
-				{Value: `"vs_default_cafe_split_clients_1_49_51"`, Result: "$vs_default_cafe_split_clients_1_49_51"},
-				{Value: `"vs_default_cafe_split_clients_1_50_50"`, Result: "$vs_default_cafe_split_clients_1_50_50"},
-				{Value: `"vs_default_cafe_split_clients_1_51_49"`, Result: "$vs_default_cafe_split_clients_1_51_49"},
-				{Value: `"vs_default_cafe_split_clients_1_52_48"`, Result: "$vs_default_cafe_split_clients_1_52_48"},
-				{Value: `"vs_default_cafe_split_clients_1_53_47"`, Result: "$vs_default_cafe_split_clients_1_53_47"},
-				{Value: `"vs_default_cafe_split_clients_1_54_46"`, Result: "$vs_default_cafe_split_clients_1_54_46"},
-				{Value: `"vs_default_cafe_split_clients_1_55_45"`, Result: "$vs_default_cafe_split_clients_1_55_45"},
-				{Value: `"vs_default_cafe_split_clients_1_56_44"`, Result: "$vs_default_cafe_split_clients_1_56_44"},
-				{Value: `"vs_default_cafe_split_clients_1_57_43"`, Result: "$vs_default_cafe_split_clients_1_57_43"},
-				{Value: `"vs_default_cafe_split_clients_1_58_42"`, Result: "$vs_default_cafe_split_clients_1_58_42"},
-				{Value: `"vs_default_cafe_split_clients_1_59_41"`, Result: "$vs_default_cafe_split_clients_1_59_41"},
-				{Value: `"vs_default_cafe_split_clients_1_60_40"`, Result: "$vs_default_cafe_split_clients_1_60_40"},
-				{Value: `"vs_default_cafe_split_clients_1_61_39"`, Result: "$vs_default_cafe_split_clients_1_61_39"},
-				{Value: `"vs_default_cafe_split_clients_1_62_38"`, Result: "$vs_default_cafe_split_clients_1_62_38"},
-				{Value: `"vs_default_cafe_split_clients_1_63_37"`, Result: "$vs_default_cafe_split_clients_1_63_37"},
-				{Value: `"vs_default_cafe_split_clients_1_64_36"`, Result: "$vs_default_cafe_split_clients_1_64_36"},
-				{Value: `"vs_default_cafe_split_clients_1_65_35"`, Result: "$vs_default_cafe_split_clients_1_65_35"},
-				{Value: `"vs_default_cafe_split_clients_1_66_34"`, Result: "$vs_default_cafe_split_clients_1_66_34"},
-				{Value: `"vs_default_cafe_split_clients_1_67_33"`, Result: "$vs_default_cafe_split_clients_1_67_33"},
-				{Value: `"vs_default_cafe_split_clients_1_68_32"`, Result: "$vs_default_cafe_split_clients_1_68_32"},
-				{Value: `"vs_default_cafe_split_clients_1_69_31"`, Result: "$vs_default_cafe_split_clients_1_69_31"},
-				{Value: `"vs_default_cafe_split_clients_1_70_30"`, Result: "$vs_default_cafe_split_clients_1_70_30"},
-				{Value: `"vs_default_cafe_split_clients_1_71_29"`, Result: "$vs_default_cafe_split_clients_1_71_29"},
-				{Value: `"vs_default_cafe_split_clients_1_72_28"`, Result: "$vs_default_cafe_split_clients_1_72_28"},
-				{Value: `"vs_default_cafe_split_clients_1_73_27"`, Result: "$vs_default_cafe_split_clients_1_73_27"},
-				{Value: `"vs_default_cafe_split_clients_1_74_26"`, Result: "$vs_default_cafe_split_clients_1_74_26"},
-				{Value: `"vs_default_cafe_split_clients_1_75_25"`, Result: "$vs_default_cafe_split_clients_1_75_25"},
-				{Value: `"vs_default_cafe_split_clients_1_76_24"`, Result: "$vs_default_cafe_split_clients_1_76_24"},
-				{Value: `"vs_default_cafe_split_clients_1_77_23"`, Result: "$vs_default_cafe_split_clients_1_77_23"},
-				{Value: `"vs_default_cafe_split_clients_1_78_22"`, Result: "$vs_default_cafe_split_clients_1_78_22"},
-				{Value: `"vs_default_cafe_split_clients_1_79_21"`, Result: "$vs_default_cafe_split_clients_1_79_21"},
-				{Value: `"vs_default_cafe_split_clients_1_80_20"`, Result: "$vs_default_cafe_split_clients_1_80_20"},
-				{Value: `"vs_default_cafe_split_clients_1_81_19"`, Result: "$vs_default_cafe_split_clients_1_81_19"},
-				{Value: `"vs_default_cafe_split_clients_1_82_18"`, Result: "$vs_default_cafe_split_clients_1_82_18"},
-				{Value: `"vs_default_cafe_split_clients_1_83_17"`, Result: "$vs_default_cafe_split_clients_1_83_17"},
-				{Value: `"vs_default_cafe_split_clients_1_84_16"`, Result: "$vs_default_cafe_split_clients_1_84_16"},
-				{Value: `"vs_default_cafe_split_clients_1_85_15"`, Result: "$vs_default_cafe_split_clients_1_85_15"},
-				{Value: `"vs_default_cafe_split_clients_1_86_14"`, Result: "$vs_default_cafe_split_clients_1_86_14"},
-				{Value: `"vs_default_cafe_split_clients_1_87_13"`, Result: "$vs_default_cafe_split_clients_1_87_13"},
-				{Value: `"vs_default_cafe_split_clients_1_88_12"`, Result: "$vs_default_cafe_split_clients_1_88_12"},
-				{Value: `"vs_default_cafe_split_clients_1_89_11"`, Result: "$vs_default_cafe_split_clients_1_89_11"},
-				{Value: `"vs_default_cafe_split_clients_1_90_10"`, Result: "$vs_default_cafe_split_clients_1_90_10"},
-				{Value: `"vs_default_cafe_split_clients_1_91_9"`, Result: "$vs_default_cafe_split_clients_1_91_9"},
-				{Value: `"vs_default_cafe_split_clients_1_92_8"`, Result: "$vs_default_cafe_split_clients_1_92_8"},
-				{Value: `"vs_default_cafe_split_clients_1_93_7"`, Result: "$vs_default_cafe_split_clients_1_93_7"},
-				{Value: `"vs_default_cafe_split_clients_1_94_6"`, Result: "$vs_default_cafe_split_clients_1_94_6"},
-				{Value: `"vs_default_cafe_split_clients_1_95_5"`, Result: "$vs_default_cafe_split_clients_1_95_5"},
-				{Value: `"vs_default_cafe_split_clients_1_96_4"`, Result: "$vs_default_cafe_split_clients_1_96_4"},
-				{Value: `"vs_default_cafe_split_clients_1_97_3"`, Result: "$vs_default_cafe_split_clients_1_97_3"},
-				{Value: `"vs_default_cafe_split_clients_1_98_2"`, Result: "$vs_default_cafe_split_clients_1_98_2"},
-				{Value: `"vs_default_cafe_split_clients_1_99_1"`, Result: "$vs_default_cafe_split_clients_1_99_1"},
-				{Value: `"vs_default_cafe_split_clients_1_100_0"`, Result: "$vs_default_cafe_split_clients_1_100_0"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_0_100"`, Result: "$vs_default_cafe_split_clients_1_0_100"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_1_99"`, Result: "$vs_default_cafe_split_clients_1_1_99"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_2_98"`, Result: "$vs_default_cafe_split_clients_1_2_98"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_3_97"`, Result: "$vs_default_cafe_split_clients_1_3_97"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_4_96"`, Result: "$vs_default_cafe_split_clients_1_4_96"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_5_95"`, Result: "$vs_default_cafe_split_clients_1_5_95"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_6_94"`, Result: "$vs_default_cafe_split_clients_1_6_94"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_7_93"`, Result: "$vs_default_cafe_split_clients_1_7_93"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_8_92"`, Result: "$vs_default_cafe_split_clients_1_8_92"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_9_91"`, Result: "$vs_default_cafe_split_clients_1_9_91"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_10_90"`, Result: "$vs_default_cafe_split_clients_1_10_90"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_11_89"`, Result: "$vs_default_cafe_split_clients_1_11_89"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_12_88"`, Result: "$vs_default_cafe_split_clients_1_12_88"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_13_87"`, Result: "$vs_default_cafe_split_clients_1_13_87"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_14_86"`, Result: "$vs_default_cafe_split_clients_1_14_86"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_15_85"`, Result: "$vs_default_cafe_split_clients_1_15_85"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_16_84"`, Result: "$vs_default_cafe_split_clients_1_16_84"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_17_83"`, Result: "$vs_default_cafe_split_clients_1_17_83"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_18_82"`, Result: "$vs_default_cafe_split_clients_1_18_82"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_19_81"`, Result: "$vs_default_cafe_split_clients_1_19_81"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_20_80"`, Result: "$vs_default_cafe_split_clients_1_20_80"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_21_79"`, Result: "$vs_default_cafe_split_clients_1_21_79"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_22_78"`, Result: "$vs_default_cafe_split_clients_1_22_78"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_23_77"`, Result: "$vs_default_cafe_split_clients_1_23_77"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_24_76"`, Result: "$vs_default_cafe_split_clients_1_24_76"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_25_75"`, Result: "$vs_default_cafe_split_clients_1_25_75"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_26_74"`, Result: "$vs_default_cafe_split_clients_1_26_74"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_27_73"`, Result: "$vs_default_cafe_split_clients_1_27_73"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_28_72"`, Result: "$vs_default_cafe_split_clients_1_28_72"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_29_71"`, Result: "$vs_default_cafe_split_clients_1_29_71"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_30_70"`, Result: "$vs_default_cafe_split_clients_1_30_70"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_31_69"`, Result: "$vs_default_cafe_split_clients_1_31_69"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_32_68"`, Result: "$vs_default_cafe_split_clients_1_32_68"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_33_67"`, Result: "$vs_default_cafe_split_clients_1_33_67"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_34_66"`, Result: "$vs_default_cafe_split_clients_1_34_66"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_35_65"`, Result: "$vs_default_cafe_split_clients_1_35_65"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_36_64"`, Result: "$vs_default_cafe_split_clients_1_36_64"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_37_63"`, Result: "$vs_default_cafe_split_clients_1_37_63"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_38_62"`, Result: "$vs_default_cafe_split_clients_1_38_62"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_39_61"`, Result: "$vs_default_cafe_split_clients_1_39_61"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_40_60"`, Result: "$vs_default_cafe_split_clients_1_40_60"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_41_59"`, Result: "$vs_default_cafe_split_clients_1_41_59"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_42_58"`, Result: "$vs_default_cafe_split_clients_1_42_58"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_43_57"`, Result: "$vs_default_cafe_split_clients_1_43_57"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_44_56"`, Result: "$vs_default_cafe_split_clients_1_44_56"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_45_55"`, Result: "$vs_default_cafe_split_clients_1_45_55"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_46_54"`, Result: "$vs_default_cafe_split_clients_1_46_54"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_47_53"`, Result: "$vs_default_cafe_split_clients_1_47_53"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_48_52"`, Result: "$vs_default_cafe_split_clients_1_48_52"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_49_51"`, Result: "$vs_default_cafe_split_clients_1_49_51"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_50_50"`, Result: "$vs_default_cafe_split_clients_1_50_50"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_51_49"`, Result: "$vs_default_cafe_split_clients_1_51_49"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_52_48"`, Result: "$vs_default_cafe_split_clients_1_52_48"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_53_47"`, Result: "$vs_default_cafe_split_clients_1_53_47"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_54_46"`, Result: "$vs_default_cafe_split_clients_1_54_46"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_55_45"`, Result: "$vs_default_cafe_split_clients_1_55_45"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_56_44"`, Result: "$vs_default_cafe_split_clients_1_56_44"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_57_43"`, Result: "$vs_default_cafe_split_clients_1_57_43"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_58_42"`, Result: "$vs_default_cafe_split_clients_1_58_42"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_59_41"`, Result: "$vs_default_cafe_split_clients_1_59_41"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_60_40"`, Result: "$vs_default_cafe_split_clients_1_60_40"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_61_39"`, Result: "$vs_default_cafe_split_clients_1_61_39"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_62_38"`, Result: "$vs_default_cafe_split_clients_1_62_38"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_63_37"`, Result: "$vs_default_cafe_split_clients_1_63_37"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_64_36"`, Result: "$vs_default_cafe_split_clients_1_64_36"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_65_35"`, Result: "$vs_default_cafe_split_clients_1_65_35"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_66_34"`, Result: "$vs_default_cafe_split_clients_1_66_34"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_67_33"`, Result: "$vs_default_cafe_split_clients_1_67_33"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_68_32"`, Result: "$vs_default_cafe_split_clients_1_68_32"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_69_31"`, Result: "$vs_default_cafe_split_clients_1_69_31"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_70_30"`, Result: "$vs_default_cafe_split_clients_1_70_30"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_71_29"`, Result: "$vs_default_cafe_split_clients_1_71_29"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_72_28"`, Result: "$vs_default_cafe_split_clients_1_72_28"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_73_27"`, Result: "$vs_default_cafe_split_clients_1_73_27"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_74_26"`, Result: "$vs_default_cafe_split_clients_1_74_26"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_75_25"`, Result: "$vs_default_cafe_split_clients_1_75_25"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_76_24"`, Result: "$vs_default_cafe_split_clients_1_76_24"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_77_23"`, Result: "$vs_default_cafe_split_clients_1_77_23"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_78_22"`, Result: "$vs_default_cafe_split_clients_1_78_22"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_79_21"`, Result: "$vs_default_cafe_split_clients_1_79_21"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_80_20"`, Result: "$vs_default_cafe_split_clients_1_80_20"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_81_19"`, Result: "$vs_default_cafe_split_clients_1_81_19"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_82_18"`, Result: "$vs_default_cafe_split_clients_1_82_18"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_83_17"`, Result: "$vs_default_cafe_split_clients_1_83_17"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_84_16"`, Result: "$vs_default_cafe_split_clients_1_84_16"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_85_15"`, Result: "$vs_default_cafe_split_clients_1_85_15"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_86_14"`, Result: "$vs_default_cafe_split_clients_1_86_14"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_87_13"`, Result: "$vs_default_cafe_split_clients_1_87_13"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_88_12"`, Result: "$vs_default_cafe_split_clients_1_88_12"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_89_11"`, Result: "$vs_default_cafe_split_clients_1_89_11"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_90_10"`, Result: "$vs_default_cafe_split_clients_1_90_10"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_91_9"`, Result: "$vs_default_cafe_split_clients_1_91_9"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_92_8"`, Result: "$vs_default_cafe_split_clients_1_92_8"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_93_7"`, Result: "$vs_default_cafe_split_clients_1_93_7"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_94_6"`, Result: "$vs_default_cafe_split_clients_1_94_6"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_95_5"`, Result: "$vs_default_cafe_split_clients_1_95_5"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_96_4"`, Result: "$vs_default_cafe_split_clients_1_96_4"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_97_3"`, Result: "$vs_default_cafe_split_clients_1_97_3"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_98_2"`, Result: "$vs_default_cafe_split_clients_1_98_2"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_99_1"`, Result: "$vs_default_cafe_split_clients_1_99_1"},
+				{Value: `"vs_default_cafe_split_clients_d4e1477b_100_0"`, Result: "$vs_default_cafe_split_clients_1_100_0"},
 				{Value: "default", Result: "$vs_default_cafe_split_clients_1_100_0"},
 			},
 		},
@@ -3392,27 +3679,27 @@ func TestGenerateSplitsWeightChangesDynamicReload(t *testing.T) {
 
 	expectedKeyValZones := []version2.KeyValZone{
 		{
-			Name:  "vs_default_cafe_keyval_zone_split_clients_1",
+			Name:  "vs_default_cafe_keyval_zone_split_clients_d4e1477b",
 			Size:  "100k",
-			State: "/etc/nginx/state_files/vs_default_cafe_keyval_zone_split_clients_1.json",
+			State: "/etc/nginx/state_files/vs_default_cafe_keyval_zone_split_clients_d4e1477b.json",
 		},
 	}
 
 	expectedKeyVals := []version2.KeyVal{
 		{
-			Key:      `"vs_default_cafe_keyval_key_split_clients_1"`,
+			Key:      `"vs_default_cafe_keyval_key_split_clients_d4e1477b"`,
 			Variable: "$vs_default_cafe_keyval_split_clients_1",
-			ZoneName: "vs_default_cafe_keyval_zone_split_clients_1",
+			ZoneName: "vs_default_cafe_keyval_zone_split_clients_d4e1477b",
 		},
 	}
 
 	expectedTwoWaySplitClients := []version2.TwoWaySplitClients{
 		{
-			Key:               `"vs_default_cafe_keyval_key_split_clients_1"`,
-			Variable:          "$vs_default_cafe_keyval_split_clients_1",
-			ZoneName:          "vs_default_cafe_keyval_zone_split_clients_1",
-			SplitClientsIndex: 1,
-			Weights:           []int{90, 10},
+			Key:            `"vs_default_cafe_keyval_key_split_clients_d4e1477b"`,
+			Variable:       "$vs_default_cafe_keyval_split_clients_1",
+			ZoneName:       "vs_default_cafe_keyval_zone_split_clients_d4e1477b",
+			SplitClientsID: "d4e1477b",
+			Weights:        []int{90, 10},
 		},
 	}
 	returnLocationIndex := 1
@@ -3426,6 +3713,9 @@ func TestGenerateSplitsWeightChangesDynamicReload(t *testing.T) {
 		t.Run(test.msg, func(t *testing.T) {
 			resultSplitClients, resultLocations, _, resultMaps, resultKeyValZones, resultKeyVals, resultTwoWaySplitClients := generateSplits(
 				test.splits,
+				nil,
+				nil,
+				nil,
 				upstreamNamer,
 				crUpstreams,
 				variableNamer,
@@ -3469,6 +3759,83 @@ func TestGenerateSplitsWeightChangesDynamicReload(t *testing.T) {
 	}
 }
 
+func TestGenerateSplitsWeightChangesDynamicReloadWithPromotedSplit(t *testing.T) {
+	t.Parallel()
+
+	virtualServer := conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
+	}
+	upstreamNamer := NewUpstreamNamerForVirtualServer(&virtualServer)
+	variableNamer := NewVSVariableNamer(&virtualServer)
+	cfgParams := ConfigParams{Context: context.Background()}
+	crUpstreams := map[string]conf_v1.Upstream{
+		"vs_default_cafe_coffee-v1": {
+			Service: "coffee-v1",
+		},
+		"vs_default_cafe_coffee-v2": {
+			Service: "coffee-v2",
+		},
+	}
+	splits := []conf_v1.Split{
+		{
+			Weight: 90,
+			Action: &conf_v1.Action{Pass: "coffee-v1"},
+		},
+		{
+			Weight: 10,
+			Action: &conf_v1.Action{Pass: "coffee-v2"},
+		},
+	}
+	promotedSplits := []conf_v1.Split{splits[0], splits[1]}
+	promotedSplits[1].Promoted = true
+
+	generate := func(splits []conf_v1.Split) ([]version2.SplitClient, []version2.KeyValZone, []version2.KeyVal, []version2.TwoWaySplitClients) {
+		scs, _, _, _, keyValZones, keyVals, twoWaySplitClients := generateSplits(splits, nil, nil, nil, upstreamNamer, crUpstreams,
+			variableNamer, 1, &cfgParams, errorPageDetails{}, "/path", "", false, 0, false, "", "", Warnings{}, true)
+		return scs, keyValZones, keyVals, twoWaySplitClients
+	}
+
+	scs, keyValZones, keyVals, twoWaySplitClients := generate(splits)
+	promotedScs, promotedKeyValZones, promotedKeyVals, promotedTwoWaySplitClients := generate(promotedSplits)
+
+	if diff := cmp.Diff(scs, promotedScs); diff != "" {
+		t.Errorf("generateSplits() returned different split clients for the promoted split (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(keyValZones, promotedKeyValZones); diff != "" {
+		t.Errorf("generateSplits() returned a different keyval zone for the promoted split (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(keyVals, promotedKeyVals); diff != "" {
+		t.Errorf("generateSplits() returned different keyvals for the promoted split (-want +got):\n%s", diff)
+	}
+
+	want := twoWaySplitClients
+	want[0].Weights = []int{0, 100}
+	if diff := cmp.Diff(want, promotedTwoWaySplitClients); diff != "" {
+		t.Errorf("generateSplits() returned unexpected two-way split clients for the promoted split (-want +got):\n%s", diff)
+	}
+}
+
+func TestPromoteSplit(t *testing.T) {
+	t.Parallel()
+
+	splits := []conf_v1.Split{
+		{Weight: 90, Action: &conf_v1.Action{Pass: "coffee-v1"}},
+		{Weight: 10, Action: &conf_v1.Action{Pass: "coffee-v2"}, Promoted: true},
+	}
+
+	got := PromoteSplit(splits)
+
+	if got[0].Weight != 0 || got[1].Weight != 100 {
+		t.Errorf("PromoteSplit() returned the weights %d and %d, want 0 and 100", got[0].Weight, got[1].Weight)
+	}
+	if splits[0].Weight != 90 || splits[1].Weight != 10 {
+		t.Errorf("PromoteSplit() modified the original splits: %v", splits)
+	}
+}
+
 func TestGenerateDefaultSplitsConfig(t *testing.T) {
 	t.Parallel()
 	route := conf_v1.Route{
@@ -4546,22 +4913,116 @@ func TestGetNameForSourceForMatchesRouteMapFromCondition(t *testing.T) {
 			},
 			expected: "$request_method",
 		},
+		{
+			input: conf_v1.Condition{
+				Geo: "client-region",
+			},
+			expected: "$vs_default_cafe_geo_client_region",
+		},
 	}
 
+	variableNamer := &VariableNamer{safeNsName: "default_cafe"}
 	for _, test := range tests {
-		result := getNameForSourceForMatchesRouteMapFromCondition(test.input)
+		result := getNameForSourceForMatchesRouteMapFromCondition(test.input, variableNamer)
 		if result != test.expected {
 			t.Errorf("getNameForSourceForMatchesRouteMapFromCondition() returned %q but expected %q for input %v", result, test.expected, test.input)
 		}
 	}
 }
 
-// TestGenerateVirtualServerConfigForVSRWithMultipleRegexSubroutes verifies that when a single
-// VirtualServerRoute is referenced by multiple VS regex routes, each subroute produces a
-// separate nginx location block with the correct regex path format.
-func TestGenerateVirtualServerConfigForVSRWithMultipleRegexSubroutes(t *testing.T) {
+func TestGenerateTarpit(t *testing.T) {
+	t.Parallel()
+	variableNamer := NewVSVariableNamer(&conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
+	})
+
+	tarpit := &conf_v1.Tarpit{
+		Conditions: []conf_v1.Condition{
+			{
+				Header: "User-Agent",
+				Value:  "badbot",
+			},
+			{
+				Argument: "debug",
+				Value:    "!",
+			},
+		},
+		Delay: "5s",
+		Code:  403,
+	}
+
+	expectedMaps := []version2.Map{
+		{
+			Source:   "$http_User_Agent",
+			Variable: "$vs_default_cafe_tarpit_1_cond_0",
+			Parameters: []version2.Parameter{
+				{
+					Value:  `"badbot"`,
+					Result: "$vs_default_cafe_tarpit_1_cond_1",
+				},
+				{
+					Value:  "default",
+					Result: "0",
+				},
+			},
+		},
+		{
+			Source:   "$arg_debug",
+			Variable: "$vs_default_cafe_tarpit_1_cond_1",
+			Parameters: []version2.Parameter{
+				{
+					Value:  `""`,
+					Result: "0",
+				},
+				{
+					Value:  "default",
+					Result: "1",
+				},
+			},
+		},
+	}
+	expectedTarpit := &version2.Tarpit{
+		Variable: "$vs_default_cafe_tarpit_1_cond_0",
+		Location: "/internal_location_tarpit_1",
+	}
+	expectedLocation := &version2.TarpitLocation{
+		Path:  "/internal_location_tarpit_1",
+		Delay: 5000,
+		Code:  403,
+	}
+
+	maps, result, location := generateTarpit(tarpit, 1, variableNamer)
+	if diff := cmp.Diff(expectedMaps, maps); diff != "" {
+		t.Errorf("generateTarpit() returned unexpected maps (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(expectedTarpit, result); diff != "" {
+		t.Errorf("generateTarpit() returned unexpected tarpit (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(expectedLocation, location); diff != "" {
+		t.Errorf("generateTarpit() returned unexpected location (-want +got):\n%s", diff)
+	}
+}
+
+func TestGenerateTarpitWithoutTarpit(t *testing.T) {
 	t.Parallel()
+	variableNamer := NewVSVariableNamer(&conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
+	})
+
+	maps, result, location := generateTarpit(nil, 0, variableNamer)
+	if maps != nil || result != nil || location != nil {
+		t.Errorf("generateTarpit(nil) returned %v, %v, %v but expected no tarpit", maps, result, location)
+	}
+}
 
+func TestGenerateVirtualServerConfigWithTarpit(t *testing.T) {
+	t.Parallel()
 	virtualServerEx := VirtualServerEx{
 		VirtualServer: &conf_v1.VirtualServer{
 			ObjectMeta: meta_v1.ObjectMeta{
@@ -4570,9 +5031,427 @@ func TestGenerateVirtualServerConfigForVSRWithMultipleRegexSubroutes(t *testing.
 			},
 			Spec: conf_v1.VirtualServerSpec{
 				Host: "cafe.example.com",
-				Routes: []conf_v1.Route{
+				Upstreams: []conf_v1.Upstream{
 					{
-						Path:  "~/api/v1",
+						Name:    "tea",
+						Service: "tea-svc",
+						Port:    80,
+					},
+				},
+				Routes: []conf_v1.Route{
+					{
+						Path: "/tea",
+						Action: &conf_v1.Action{
+							Pass: "tea",
+						},
+						Tarpit: &conf_v1.Tarpit{
+							Conditions: []conf_v1.Condition{
+								{
+									Header: "User-Agent",
+									Value:  "badbot",
+								},
+							},
+							Delay: "10s",
+						},
+					},
+					{
+						Path: "/coffee",
+						Action: &conf_v1.Action{
+							Pass: "tea",
+						},
+					},
+				},
+			},
+		},
+		Endpoints: map[string][]string{
+			"default/tea-svc:80": {
+				"10.0.0.20:80",
+			},
+		},
+	}
+
+	vsc := newVirtualServerConfigurator(&ConfigParams{Context: context.Background()}, false, false, &StaticConfigParams{}, false, &fakeBV)
+	result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+	if len(warnings) != 0 {
+		t.Errorf("GenerateVirtualServerConfig() returned unexpected warnings: %v", warnings)
+	}
+
+	expectedTarpit := &version2.Tarpit{
+		Variable: "$vs_default_cafe_tarpit_0_cond_0",
+		Location: "/internal_location_tarpit_0",
+	}
+	if diff := cmp.Diff(expectedTarpit, result.Server.Locations[0].Tarpit); diff != "" {
+		t.Errorf("GenerateVirtualServerConfig() returned unexpected tarpit for the matched route (-want +got):\n%s", diff)
+	}
+	if result.Server.Locations[1].Tarpit != nil {
+		t.Errorf("GenerateVirtualServerConfig() returned tarpit %v for the route without tarpit", result.Server.Locations[1].Tarpit)
+	}
+
+	expectedTarpitLocations := []version2.TarpitLocation{
+		{
+			Path:  "/internal_location_tarpit_0",
+			Delay: 10000,
+			Code:  429,
+		},
+	}
+	if diff := cmp.Diff(expectedTarpitLocations, result.Server.TarpitLocations); diff != "" {
+		t.Errorf("GenerateVirtualServerConfig() returned unexpected tarpit locations (-want +got):\n%s", diff)
+	}
+
+	expectedMap := version2.Map{
+		Source:   "$http_User_Agent",
+		Variable: "$vs_default_cafe_tarpit_0_cond_0",
+		Parameters: []version2.Parameter{
+			{
+				Value:  `"badbot"`,
+				Result: "1",
+			},
+			{
+				Value:  "default",
+				Result: "0",
+			},
+		},
+	}
+	if !slices.ContainsFunc(result.Maps, func(m version2.Map) bool { return cmp.Equal(m, expectedMap) }) {
+		t.Errorf("GenerateVirtualServerConfig() returned maps %v without the tarpit map %v", result.Maps, expectedMap)
+	}
+}
+
+func TestGenerateClientIPReturn(t *testing.T) {
+	t.Parallel()
+	variableNamer := NewVSVariableNamer(&conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
+	})
+
+	tests := []struct {
+		msg              string
+		clientIPReturn   *conf_v1.ClientIPReturn
+		expectedGeo      *version2.Geo
+		expectedIPReturn *version2.ClientIPReturn
+	}{
+		{
+			msg:              "no client IP return",
+			clientIPReturn:   nil,
+			expectedGeo:      nil,
+			expectedIPReturn: nil,
+		},
+		{
+			msg: "denied ranges with default code",
+			clientIPReturn: &conf_v1.ClientIPReturn{
+				Ranges: []string{"10.0.0.0/8", "192.168.1.1"},
+			},
+			expectedGeo: &version2.Geo{
+				Source:   "$remote_addr",
+				Variable: "$vs_default_cafe_client_ip_return_1",
+				Parameters: []version2.Parameter{
+					{
+						Value:  "default",
+						Result: "0",
+					},
+					{
+						Value:  "10.0.0.0/8",
+						Result: "1",
+					},
+					{
+						Value:  "192.168.1.1",
+						Result: "1",
+					},
+				},
+			},
+			expectedIPReturn: &version2.ClientIPReturn{
+				Variable: "$vs_default_cafe_client_ip_return_1",
+				Code:     403,
+			},
+		},
+		{
+			msg: "denied range with custom code",
+			clientIPReturn: &conf_v1.ClientIPReturn{
+				Ranges: []string{"2001:db8::/32"},
+				Code:   451,
+			},
+			expectedGeo: &version2.Geo{
+				Source:   "$remote_addr",
+				Variable: "$vs_default_cafe_client_ip_return_1",
+				Parameters: []version2.Parameter{
+					{
+						Value:  "default",
+						Result: "0",
+					},
+					{
+						Value:  "2001:db8::/32",
+						Result: "1",
+					},
+				},
+			},
+			expectedIPReturn: &version2.ClientIPReturn{
+				Variable: "$vs_default_cafe_client_ip_return_1",
+				Code:     451,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			t.Parallel()
+			geo, result := generateClientIPReturn(test.clientIPReturn, 1, variableNamer)
+			if diff := cmp.Diff(test.expectedGeo, geo); diff != "" {
+				t.Errorf("generateClientIPReturn() returned unexpected geo (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(test.expectedIPReturn, result); diff != "" {
+				t.Errorf("generateClientIPReturn() returned unexpected client IP return (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGenerateVirtualServerConfigWithClientIPReturn(t *testing.T) {
+	t.Parallel()
+	virtualServerEx := VirtualServerEx{
+		VirtualServer: &conf_v1.VirtualServer{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "cafe",
+				Namespace: "default",
+			},
+			Spec: conf_v1.VirtualServerSpec{
+				Host: "cafe.example.com",
+				Upstreams: []conf_v1.Upstream{
+					{
+						Name:    "tea",
+						Service: "tea-svc",
+						Port:    80,
+					},
+				},
+				Routes: []conf_v1.Route{
+					{
+						Path: "/tea",
+						Action: &conf_v1.Action{
+							Pass: "tea",
+						},
+						ClientIPReturn: &conf_v1.ClientIPReturn{
+							Ranges: []string{"10.0.0.0/8"},
+						},
+					},
+					{
+						Path: "/coffee",
+						Action: &conf_v1.Action{
+							Pass: "tea",
+						},
+					},
+				},
+			},
+		},
+		Endpoints: map[string][]string{
+			"default/tea-svc:80": {
+				"10.0.0.20:80",
+			},
+		},
+	}
+
+	vsc := newVirtualServerConfigurator(&ConfigParams{Context: context.Background()}, false, false, &StaticConfigParams{}, false, &fakeBV)
+	result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+	if len(warnings) != 0 {
+		t.Errorf("GenerateVirtualServerConfig() returned unexpected warnings: %v", warnings)
+	}
+
+	expectedClientIPReturn := &version2.ClientIPReturn{
+		Variable: "$vs_default_cafe_client_ip_return_0",
+		Code:     403,
+	}
+	if diff := cmp.Diff(expectedClientIPReturn, result.Server.Locations[0].ClientIPReturn); diff != "" {
+		t.Errorf("GenerateVirtualServerConfig() returned unexpected client IP return for the denied route (-want +got):\n%s", diff)
+	}
+	if result.Server.Locations[1].ClientIPReturn != nil {
+		t.Errorf("GenerateVirtualServerConfig() returned client IP return %v for the route without client IP return", result.Server.Locations[1].ClientIPReturn)
+	}
+
+	expectedGeos := []version2.Geo{
+		{
+			Source:   "$remote_addr",
+			Variable: "$vs_default_cafe_client_ip_return_0",
+			Parameters: []version2.Parameter{
+				{
+					Value:  "default",
+					Result: "0",
+				},
+				{
+					Value:  "10.0.0.0/8",
+					Result: "1",
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedGeos, result.Geos); diff != "" {
+		t.Errorf("GenerateVirtualServerConfig() returned unexpected geos (-want +got):\n%s", diff)
+	}
+}
+
+func TestGenerateVirtualServerConfigWithSSLClientVerifyMatches(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		msg          string
+		policies     []conf_v1.PolicyReference
+		wantWarnings []string
+	}{
+		{
+			msg: "optional client certificates",
+			policies: []conf_v1.PolicyReference{
+				{
+					Name: "ingress-mtls-policy",
+				},
+			},
+		},
+		{
+			msg: "no client certificates",
+			wantWarnings: []string{
+				"The matches of the route / use the $ssl_client_verify variable, which requires an IngressMTLS policy with the verifyClient set to optional or optional_no_ca",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			t.Parallel()
+			virtualServerEx := VirtualServerEx{
+				VirtualServer: &conf_v1.VirtualServer{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name:      "cafe",
+						Namespace: "default",
+					},
+					Spec: conf_v1.VirtualServerSpec{
+						Host: "cafe.example.com",
+						TLS: &conf_v1.TLS{
+							Secret: "cafe-secret",
+						},
+						Policies: test.policies,
+						Upstreams: []conf_v1.Upstream{
+							{
+								Name:    "verified",
+								Service: "verified-svc",
+								Port:    80,
+							},
+							{
+								Name:    "anonymous",
+								Service: "anonymous-svc",
+								Port:    80,
+							},
+						},
+						Routes: []conf_v1.Route{
+							{
+								Path: "/",
+								Matches: []conf_v1.Match{
+									{
+										Conditions: []conf_v1.Condition{
+											{
+												Variable: "$ssl_client_verify",
+												Value:    "SUCCESS",
+											},
+										},
+										Action: &conf_v1.Action{
+											Pass: "verified",
+										},
+									},
+								},
+								Action: &conf_v1.Action{
+									Pass: "anonymous",
+								},
+							},
+						},
+					},
+				},
+				Policies: map[string]*conf_v1.Policy{
+					"default/ingress-mtls-policy": {
+						ObjectMeta: meta_v1.ObjectMeta{
+							Name:      "ingress-mtls-policy",
+							Namespace: "default",
+						},
+						Spec: conf_v1.PolicySpec{
+							IngressMTLS: &conf_v1.IngressMTLS{
+								ClientCertSecret: "ingress-mtls-secret",
+								VerifyClient:     "optional",
+							},
+						},
+					},
+				},
+				SecretRefs: map[string]*secrets.SecretReference{
+					"default/cafe-secret": {
+						Secret: &api_v1.Secret{
+							Type: api_v1.SecretTypeTLS,
+						},
+						Path: "/etc/nginx/secrets/default-cafe-secret",
+					},
+					"default/ingress-mtls-secret": {
+						Secret: &api_v1.Secret{
+							Type: secrets.SecretTypeCA,
+						},
+						Path: "/etc/nginx/secrets/default-ingress-mtls-secret-ca.crt",
+					},
+				},
+			}
+
+			vsc := newVirtualServerConfigurator(&ConfigParams{Context: context.Background()}, false, false, &StaticConfigParams{}, false, &fakeBV)
+			result, warnings := vsc.GenerateVirtualServerConfig(&virtualServerEx, nil, nil)
+			if diff := cmp.Diff(test.wantWarnings, warnings[virtualServerEx.VirtualServer]); diff != "" {
+				t.Errorf("GenerateVirtualServerConfig() returned unexpected warnings (-want +got):\n%s", diff)
+			}
+
+			// the clients with a verified certificate are routed to the first upstream, the anonymous clients to the second one
+			expectedMaps := []version2.Map{
+				{
+					Source:   "$ssl_client_verify",
+					Variable: "$vs_default_cafe_matches_0_match_0_cond_0",
+					Parameters: []version2.Parameter{
+						{
+							Value:  `"SUCCESS"`,
+							Result: "1",
+						},
+						{
+							Value:  "default",
+							Result: "0",
+						},
+					},
+				},
+				{
+					Source:   "$vs_default_cafe_matches_0_match_0_cond_0",
+					Variable: "$vs_default_cafe_matches_0",
+					Parameters: []version2.Parameter{
+						{
+							Value:  "~^1",
+							Result: "/internal_location_matches_0_match_0",
+						},
+						{
+							Value:  "default",
+							Result: "/internal_location_matches_0_default",
+						},
+					},
+				},
+			}
+			if diff := cmp.Diff(expectedMaps, result.Maps); diff != "" {
+				t.Errorf("GenerateVirtualServerConfig() returned unexpected maps (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestGenerateVirtualServerConfigForVSRWithMultipleRegexSubroutes verifies that when a single
+// VirtualServerRoute is referenced by multiple VS regex routes, each subroute produces a
+// separate nginx location block with the correct regex path format.
+func TestGenerateVirtualServerConfigForVSRWithMultipleRegexSubroutes(t *testing.T) {
+	t.Parallel()
+
+	virtualServerEx := VirtualServerEx{
+		VirtualServer: &conf_v1.VirtualServer{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "cafe",
+				Namespace: "default",
+			},
+			Spec: conf_v1.VirtualServerSpec{
+				Host: "cafe.example.com",
+				Routes: []conf_v1.Route{
+					{
+						Path:  "~/api/v1",
 						Route: "default/api",
 					},
 					{
@@ -4669,3 +5548,94 @@ func TestGenerateVirtualServerConfigForVSRWithMultipleRegexSubroutes(t *testing.
 		t.Errorf("GenerateVirtualServerConfig returned unexpected warnings: %v", warnings)
 	}
 }
+
+func TestGenerateVirtualServerConfigKeyValZoneNamesStableOnRouteReordering(t *testing.T) {
+	t.Parallel()
+	teaRoute := conf_v1.Route{
+		Path: "/tea",
+		Splits: []conf_v1.Split{
+			{Weight: 90, Action: &conf_v1.Action{Pass: "tea-v1"}},
+			{Weight: 10, Action: &conf_v1.Action{Pass: "tea-v2"}},
+		},
+	}
+	coffeeRoute := conf_v1.Route{
+		Path: "/coffee",
+		Matches: []conf_v1.Match{
+			{
+				Conditions: []conf_v1.Condition{{Header: "x-version", Value: "v2"}},
+				Splits: []conf_v1.Split{
+					{Weight: 80, Action: &conf_v1.Action{Pass: "coffee-v1"}},
+					{Weight: 20, Action: &conf_v1.Action{Pass: "coffee-v2"}},
+				},
+			},
+		},
+		Action: &conf_v1.Action{Pass: "coffee-v1"},
+	}
+	newVirtualServerEx := func(routes ...conf_v1.Route) *VirtualServerEx {
+		return &VirtualServerEx{
+			VirtualServer: &conf_v1.VirtualServer{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      "cafe",
+					Namespace: "default",
+				},
+				Spec: conf_v1.VirtualServerSpec{
+					Host: "cafe.example.com",
+					Upstreams: []conf_v1.Upstream{
+						{Name: "tea-v1", Service: "tea-svc-v1", Port: 80},
+						{Name: "tea-v2", Service: "tea-svc-v2", Port: 80},
+						{Name: "coffee-v1", Service: "coffee-svc-v1", Port: 80},
+						{Name: "coffee-v2", Service: "coffee-svc-v2", Port: 80},
+					},
+					Routes: routes,
+				},
+			},
+		}
+	}
+	zoneNames := func(cfg version2.VirtualServerConfig) map[string]string {
+		names := make(map[string]string)
+		for _, sc := range cfg.TwoWaySplitClients {
+			names[sc.ZoneName] = sc.Key
+		}
+		return names
+	}
+
+	cfgParams := ConfigParams{Context: context.Background()}
+	staticConfigParams := &StaticConfigParams{DynamicWeightChangesReload: true}
+
+	vsc := newVirtualServerConfigurator(&cfgParams, true, false, staticConfigParams, false, &fakeBV)
+	before, _ := vsc.GenerateVirtualServerConfig(newVirtualServerEx(teaRoute, coffeeRoute), nil, nil)
+
+	vsc = newVirtualServerConfigurator(&cfgParams, true, false, staticConfigParams, false, &fakeBV)
+	after, _ := vsc.GenerateVirtualServerConfig(newVirtualServerEx(coffeeRoute, teaRoute), nil, nil)
+
+	if len(before.TwoWaySplitClients) != 2 {
+		t.Fatalf("GenerateVirtualServerConfig() returned %d two-way split clients, expected 2", len(before.TwoWaySplitClients))
+	}
+	if diff := cmp.Diff(zoneNames(before), zoneNames(after)); diff != "" {
+		t.Errorf("GenerateVirtualServerConfig() keyval zones changed after reordering routes (-before +after):\n%s", diff)
+	}
+}
+
+func TestGetSplitClientsID(t *testing.T) {
+	t.Parallel()
+	splits := []conf_v1.Split{
+		{Weight: 90, Action: &conf_v1.Action{Pass: "tea-v1"}},
+		{Weight: 10, Action: &conf_v1.Action{Pass: "tea-v2"}},
+	}
+	changedWeights := []conf_v1.Split{
+		{Weight: 30, Action: &conf_v1.Action{Pass: "tea-v1"}},
+		{Weight: 70, Action: &conf_v1.Action{Pass: "tea-v2"}},
+	}
+	conditions := []conf_v1.Condition{{Header: "x-version", Value: "v2"}}
+
+	id := GetSplitClientsID("/tea", nil, splits)
+	if got := GetSplitClientsID("/tea", nil, changedWeights); got != id {
+		t.Errorf("GetSplitClientsID() returned %q for splits with changed weights, expected %q", got, id)
+	}
+	if got := GetSplitClientsID("/coffee", nil, splits); got == id {
+		t.Errorf("GetSplitClientsID() returned the same ID %q for a different path", got)
+	}
+	if got := GetSplitClientsID("/tea", conditions, splits); got == id {
+		t.Errorf("GetSplitClientsID() returned the same ID %q for splits with match conditions", got)
+	}
+}
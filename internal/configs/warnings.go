@@ -2,7 +2,11 @@ package configs
 
 import (
 	"fmt"
+	"slices"
 
+	conf_v1 "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
+	networking "k8s.io/api/networking/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -42,3 +46,37 @@ func (w Warnings) AddWarningf(obj runtime.Object, msgFmt string, args ...interfa
 func (w Warnings) AddWarning(obj runtime.Object, msg string) {
 	w[obj] = append(w[obj], msg)
 }
+
+// FlattenWarnings returns the warnings keyed by their objects in the form "namespace/name/kind",
+// with the warnings of each object sorted, so that they can be reported in the status of the objects in a stable order.
+func FlattenWarnings(w Warnings) map[string][]string {
+	flattened := make(map[string][]string, len(w))
+	for obj, msgs := range w {
+		o, ok := obj.(meta_v1.Object)
+		if !ok || len(msgs) == 0 {
+			continue
+		}
+		key := fmt.Sprintf("%s/%s/%s", o.GetNamespace(), o.GetName(), getWarningsObjectKind(obj))
+		flattened[key] = append(flattened[key], msgs...)
+	}
+	for _, msgs := range flattened {
+		slices.Sort(msgs)
+	}
+	return flattened
+}
+
+// getWarningsObjectKind returns the kind of the object of warnings.
+// The kind of the typed objects is not set in their TypeMeta, so it is derived from their type.
+func getWarningsObjectKind(obj runtime.Object) string {
+	switch obj.(type) {
+	case *networking.Ingress:
+		return "Ingress"
+	case *conf_v1.VirtualServer:
+		return "VirtualServer"
+	case *conf_v1.VirtualServerRoute:
+		return "VirtualServerRoute"
+	case *conf_v1.TransportServer:
+		return "TransportServer"
+	}
+	return obj.GetObjectKind().GroupVersionKind().Kind
+}
@@ -0,0 +1,100 @@
+package configs
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	conf_v1 "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFlattenWarnings(t *testing.T) {
+	t.Parallel()
+
+	vs := &conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
+	}
+	vsr := &conf_v1.VirtualServerRoute{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
+	}
+	otherVSR := &conf_v1.VirtualServerRoute{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "tea",
+			Namespace: "tea",
+		},
+	}
+
+	warnings := newWarnings()
+	warnings.AddWarning(vs, "Upstream tea has no endpoints")
+	warnings.AddWarning(vs, "Policy default/jwt is missing or invalid")
+	warnings.AddWarning(vsr, "Upstream coffee has no endpoints")
+	warnings.AddWarning(otherVSR, "Tracing is ignored as the otel-exporter-endpoint ConfigMap key is not set")
+	warnings.AddWarning(otherVSR, "Invalid routeSelector in route with path /tea")
+
+	expected := map[string][]string{
+		"default/cafe/VirtualServer": {
+			"Policy default/jwt is missing or invalid",
+			"Upstream tea has no endpoints",
+		},
+		"default/cafe/VirtualServerRoute": {
+			"Upstream coffee has no endpoints",
+		},
+		"tea/tea/VirtualServerRoute": {
+			"Invalid routeSelector in route with path /tea",
+			"Tracing is ignored as the otel-exporter-endpoint ConfigMap key is not set",
+		},
+	}
+
+	result := FlattenWarnings(warnings)
+	if diff := cmp.Diff(expected, result); diff != "" {
+		t.Errorf("FlattenWarnings() mismatch (-want +got):\n%s", diff)
+	}
+
+	if warnings[vs][0] != "Upstream tea has no endpoints" {
+		t.Errorf("FlattenWarnings() changed the order of the warnings of the object to %v", warnings[vs])
+	}
+}
+
+func TestFlattenWarningsIsStable(t *testing.T) {
+	t.Parallel()
+
+	// the warnings of the copies of the same object are merged in the random order of the map of the warnings
+	warnings := newWarnings()
+	for _, msg := range []string{"c", "a", "d", "b"} {
+		vs := &conf_v1.VirtualServer{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "cafe",
+				Namespace: "default",
+			},
+		}
+		warnings.AddWarning(vs, msg)
+	}
+
+	expected := map[string][]string{
+		"default/cafe/VirtualServer": {"a", "b", "c", "d"},
+	}
+	for range 10 {
+		result := FlattenWarnings(warnings)
+		if diff := cmp.Diff(expected, result); diff != "" {
+			t.Errorf("FlattenWarnings() mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func TestFlattenWarningsSkipsObjectsWithoutWarnings(t *testing.T) {
+	t.Parallel()
+
+	warnings := newWarnings()
+	warnings[&conf_v1.VirtualServer{ObjectMeta: meta_v1.ObjectMeta{Name: "cafe", Namespace: "default"}}] = nil
+
+	result := FlattenWarnings(warnings)
+	if len(result) != 0 {
+		t.Errorf("FlattenWarnings() returned %v but expected no warnings", result)
+	}
+}
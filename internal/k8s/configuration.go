@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"maps"
 	"reflect"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -220,6 +221,10 @@ type VirtualServerConfiguration struct {
 	HTTPIPv6                    string
 	HTTPSIPv4                   string
 	HTTPSIPv6                   string
+	HTTPListenerOptions         *conf_v1.ListenerOptions
+	HTTPSListenerOptions        *conf_v1.ListenerOptions
+	HTTP3ReusePort              bool
+	AdditionalListeners         []conf_v1.Listener
 }
 
 // NewVirtualServerConfiguration creates a VirtualServerConfiguration.
@@ -1004,6 +1009,63 @@ func (c *Configuration) buildListenersForVSConfiguration(vsc *VirtualServerConfi
 
 	assignListener(vs.Spec.Listener.HTTP, false, &vsc.HTTPPort, &vsc.HTTPIPv4, &vsc.HTTPIPv6)
 	assignListener(vs.Spec.Listener.HTTPS, true, &vsc.HTTPSPort, &vsc.HTTPSIPv4, &vsc.HTTPSIPv6)
+
+	// The listeners that are missing, aren't HTTP listeners or are referenced more than once are skipped,
+	// see addWarningsForVirtualServersWithMissConfiguredListeners.
+	used := usedListeners(vs.Spec.Listener)
+	for _, listenerName := range vs.Spec.Listener.Additional {
+		gcListener, ok := c.listenerMap[listenerName]
+		if !ok || gcListener.Protocol != conf_v1.HTTPProtocol || used[listenerName] {
+			continue
+		}
+		used[listenerName] = true
+		vsc.AdditionalListeners = append(vsc.AdditionalListeners, gcListener)
+	}
+}
+
+// assignListenerOptions assigns the options of the listeners to the VirtualServers of the hosts.
+// NGINX allows the options of a listen directive only once per address and port, so the options
+// of a listener are assigned only to the first VirtualServer, in the order of the hosts, that uses the listener.
+// The HTTPS listeners are only rendered when TLS is configured for the VirtualServer.
+// Likewise, the reuseport parameter of the QUIC listen directive of an HTTPS listener is assigned only
+// to the first VirtualServer that enables HTTP/3 on the listener.
+func (c *Configuration) assignListenerOptions(hosts map[string]Resource) {
+	assigned := make(map[string]bool)
+	assignedReusePort := make(map[string]bool)
+	takeOptions := func(listenerName string) *conf_v1.ListenerOptions {
+		gcListener, ok := c.listenerMap[listenerName]
+		if !ok || gcListener.Options == nil || assigned[listenerName] {
+			return nil
+		}
+		assigned[listenerName] = true
+		return gcListener.Options
+	}
+
+	for _, h := range getSortedResourceKeys(hosts) {
+		vsc, ok := hosts[h].(*VirtualServerConfiguration)
+		if !ok || vsc.VirtualServer.Spec.Listener == nil {
+			continue
+		}
+		if vsc.HTTPPort != 0 {
+			vsc.HTTPListenerOptions = takeOptions(vsc.VirtualServer.Spec.Listener.HTTP)
+		}
+		hasTLS := vsc.VirtualServer.Spec.TLS != nil
+		if vsc.HTTPSPort != 0 && hasTLS {
+			vsc.HTTPSListenerOptions = takeOptions(vsc.VirtualServer.Spec.Listener.HTTPS)
+		}
+		vsc.HTTP3ReusePort = false
+		if vsc.HTTPSPort != 0 && hasTLS && vsc.VirtualServer.Spec.HTTP3 != nil && *vsc.VirtualServer.Spec.HTTP3 && !assignedReusePort[vsc.VirtualServer.Spec.Listener.HTTPS] {
+			assignedReusePort[vsc.VirtualServer.Spec.Listener.HTTPS] = true
+			vsc.HTTP3ReusePort = true
+		}
+		for i, l := range vsc.AdditionalListeners {
+			if l.Ssl && !hasTLS {
+				vsc.AdditionalListeners[i].Options = nil
+				continue
+			}
+			vsc.AdditionalListeners[i].Options = takeOptions(l.Name)
+		}
+	}
 }
 
 // GetResources returns all configuration resources.
@@ -1402,8 +1464,47 @@ func (c *Configuration) addWarningsForVirtualServersWithMissConfiguredListeners(
 					continue
 				}
 			}
+
+			for _, warningMsg := range c.getWarningsForAdditionalListeners(vsc.VirtualServer.Spec.Listener) {
+				c.hosts[vsc.VirtualServer.Spec.Host].AddWarning(warningMsg)
+			}
+		}
+	}
+}
+
+func (c *Configuration) getWarningsForAdditionalListeners(listener *conf_v1.VirtualServerListener) []string {
+	var warnings []string
+
+	used := usedListeners(listener)
+	for _, listenerName := range listener.Additional {
+		if used[listenerName] {
+			warnings = append(warnings, fmt.Sprintf("Listener %s is referenced more than once and is ignored in `listener.additional`", listenerName))
+			continue
+		}
+		used[listenerName] = true
+
+		gcListener, exists := c.listenerMap[listenerName]
+		if !exists {
+			warnings = append(warnings, fmt.Sprintf("Listener %s is not defined in GlobalConfiguration", listenerName))
+			continue
+		}
+		if gcListener.Protocol != conf_v1.HTTPProtocol {
+			warnings = append(warnings, fmt.Sprintf("Listener %s can't be used in `listener.additional` context as it is not an HTTP listener", listenerName))
+		}
+	}
+
+	return warnings
+}
+
+// usedListeners returns the names of the http and https listeners of the VirtualServer that are set.
+func usedListeners(listener *conf_v1.VirtualServerListener) map[string]bool {
+	used := make(map[string]bool)
+	for _, name := range []string{listener.HTTP, listener.HTTPS} {
+		if name != "" {
+			used[name] = true
 		}
 	}
+	return used
 }
 
 func (c *Configuration) isListenerInCorrectBlock(listenerName string, expectedSsl bool) bool {
@@ -1703,6 +1804,8 @@ func (c *Configuration) buildHostsAndResources() (newHosts map[string]Resource,
 		}
 	}
 
+	c.assignListenerOptions(newHosts)
+
 	// Step - 3 - Build hosts from TransportServer resources if TLS Passthrough is enabled
 
 	if c.isTLSPassthroughEnabled {
@@ -2323,6 +2426,10 @@ func detectChangesInHosts(oldHosts map[string]Resource, newHosts map[string]Reso
 			updatedHosts = append(updatedHosts, h)
 		}
 
+		if !slices.Equal(newVsc.AdditionalListeners, oldVsc.AdditionalListeners) {
+			updatedHosts = append(updatedHosts, h)
+		}
+
 		if newVsc.HTTPIPv4 != oldVsc.HTTPIPv4 {
 			updatedHosts = append(updatedHosts, h)
 		}
@@ -2331,6 +2438,14 @@ func detectChangesInHosts(oldHosts map[string]Resource, newHosts map[string]Reso
 			updatedHosts = append(updatedHosts, h)
 		}
 
+		if !reflect.DeepEqual(newVsc.HTTPListenerOptions, oldVsc.HTTPListenerOptions) || !reflect.DeepEqual(newVsc.HTTPSListenerOptions, oldVsc.HTTPSListenerOptions) {
+			updatedHosts = append(updatedHosts, h)
+		}
+
+		if newVsc.HTTP3ReusePort != oldVsc.HTTP3ReusePort {
+			updatedHosts = append(updatedHosts, h)
+		}
+
 	}
 
 	return removedHosts, updatedHosts, addedHosts
@@ -3132,6 +3132,301 @@ func TestAddVirtualServerWithCustomHttpsListenerThatDoNotExistInGlobalConfigurat
 	addOrUpdateVirtualServer(t, configuration, virtualServer, expectedChanges, noProblems)
 }
 
+func TestAddVirtualServerWithAdditionalListeners(t *testing.T) {
+	t.Parallel()
+	configuration := createTestConfiguration()
+
+	listeners := []conf_v1.Listener{
+		{Name: "http-8082", Port: 8082, Protocol: "HTTP"},
+		{Name: "https-8442", Port: 8442, Protocol: "HTTP", Ssl: true},
+		{Name: "http-8080", Port: 8080, Protocol: "HTTP"},
+		{Name: "https-8443", Port: 8443, Protocol: "HTTP", Ssl: true},
+	}
+	addOrUpdateGlobalConfiguration(t, configuration, listeners, noChanges, noProblems)
+
+	virtualServer := createTestVirtualServerWithListeners(
+		"cafe",
+		"cafe.example.com",
+		"http-8082",
+		"https-8442",
+	)
+	virtualServer.Spec.Listener.Additional = []string{"http-8080", "https-8443"}
+
+	expectedChanges := []ResourceChange{
+		{
+			Op: AddOrUpdate,
+			Resource: &VirtualServerConfiguration{
+				VirtualServer:               virtualServer,
+				VirtualServerRouteSelectors: map[string][]string{},
+				HTTPPort:                    8082,
+				HTTPSPort:                   8442,
+				AdditionalListeners: []conf_v1.Listener{
+					{Name: "http-8080", Port: 8080, Protocol: "HTTP"},
+					{Name: "https-8443", Port: 8443, Protocol: "HTTP", Ssl: true},
+				},
+			},
+		},
+	}
+
+	addOrUpdateVirtualServer(t, configuration, virtualServer, expectedChanges, noProblems)
+}
+
+func TestAddVirtualServersWithListenerOptions(t *testing.T) {
+	t.Parallel()
+	configuration := createTestConfiguration()
+
+	options := &conf_v1.ListenerOptions{Deferred: true, Backlog: 1024}
+	listeners := []conf_v1.Listener{
+		{Name: "http-8082", Port: 8082, Protocol: "HTTP", Options: options},
+		{Name: "https-8442", Port: 8442, Protocol: "HTTP", Ssl: true, Options: options},
+		{Name: "http-8080", Port: 8080, Protocol: "HTTP", Options: options},
+	}
+	addOrUpdateGlobalConfiguration(t, configuration, listeners, noChanges, noProblems)
+
+	teaVirtualServer := createTestVirtualServerWithListeners(
+		"tea",
+		"tea.example.com",
+		"http-8082",
+		"https-8442",
+	)
+	teaVirtualServer.Spec.Listener.Additional = []string{"http-8080"}
+
+	// the HTTPS listener isn't rendered without TLS, so its options aren't assigned
+	expectedChanges := []ResourceChange{
+		{
+			Op: AddOrUpdate,
+			Resource: &VirtualServerConfiguration{
+				VirtualServer:               teaVirtualServer,
+				VirtualServerRouteSelectors: map[string][]string{},
+				HTTPPort:                    8082,
+				HTTPSPort:                   8442,
+				HTTPListenerOptions:         options,
+				AdditionalListeners: []conf_v1.Listener{
+					{Name: "http-8080", Port: 8080, Protocol: "HTTP", Options: options},
+				},
+			},
+		},
+	}
+
+	addOrUpdateVirtualServer(t, configuration, teaVirtualServer, expectedChanges, noProblems)
+
+	// the options are moved to the VirtualServer with the first host
+	cafeVirtualServer := createTestVirtualServerWithListeners(
+		"cafe",
+		"cafe.example.com",
+		"http-8082",
+		"https-8442",
+	)
+	cafeVirtualServer.Spec.TLS = &conf_v1.TLS{Secret: "cafe-secret"}
+	cafeVirtualServer.Spec.Listener.Additional = []string{"http-8080"}
+
+	expectedChanges = []ResourceChange{
+		{
+			Op: AddOrUpdate,
+			Resource: &VirtualServerConfiguration{
+				VirtualServer:               teaVirtualServer,
+				VirtualServerRouteSelectors: map[string][]string{},
+				HTTPPort:                    8082,
+				HTTPSPort:                   8442,
+				AdditionalListeners: []conf_v1.Listener{
+					{Name: "http-8080", Port: 8080, Protocol: "HTTP"},
+				},
+			},
+		},
+		{
+			Op: AddOrUpdate,
+			Resource: &VirtualServerConfiguration{
+				VirtualServer:               cafeVirtualServer,
+				VirtualServerRouteSelectors: map[string][]string{},
+				HTTPPort:                    8082,
+				HTTPSPort:                   8442,
+				HTTPListenerOptions:         options,
+				HTTPSListenerOptions:        options,
+				AdditionalListeners: []conf_v1.Listener{
+					{Name: "http-8080", Port: 8080, Protocol: "HTTP", Options: options},
+				},
+			},
+		},
+	}
+
+	addOrUpdateVirtualServer(t, configuration, cafeVirtualServer, expectedChanges, noProblems)
+}
+
+func TestAddAndDeleteVirtualServersWithHTTP3ReusePort(t *testing.T) {
+	t.Parallel()
+	configuration := createTestConfiguration()
+
+	listeners := []conf_v1.Listener{
+		{Name: "http-8082", Port: 8082, Protocol: "HTTP"},
+		{Name: "https-8442", Port: 8442, Protocol: "HTTP", Ssl: true},
+	}
+	addOrUpdateGlobalConfiguration(t, configuration, listeners, noChanges, noProblems)
+
+	teaVirtualServer := createTestVirtualServerWithListeners(
+		"tea",
+		"tea.example.com",
+		"http-8082",
+		"https-8442",
+	)
+	teaVirtualServer.Spec.TLS = &conf_v1.TLS{Secret: "tea-secret"}
+	teaVirtualServer.Spec.HTTP3 = new(true)
+
+	expectedChanges := []ResourceChange{
+		{
+			Op: AddOrUpdate,
+			Resource: &VirtualServerConfiguration{
+				VirtualServer:               teaVirtualServer,
+				VirtualServerRouteSelectors: map[string][]string{},
+				HTTPPort:                    8082,
+				HTTPSPort:                   8442,
+				HTTP3ReusePort:              true,
+			},
+		},
+	}
+
+	addOrUpdateVirtualServer(t, configuration, teaVirtualServer, expectedChanges, noProblems)
+
+	// reuseport is moved to the VirtualServer with the first host
+	cafeVirtualServer := createTestVirtualServerWithListeners(
+		"cafe",
+		"cafe.example.com",
+		"http-8082",
+		"https-8442",
+	)
+	cafeVirtualServer.Spec.TLS = &conf_v1.TLS{Secret: "cafe-secret"}
+	cafeVirtualServer.Spec.HTTP3 = new(true)
+
+	expectedChanges = []ResourceChange{
+		{
+			Op: AddOrUpdate,
+			Resource: &VirtualServerConfiguration{
+				VirtualServer:               teaVirtualServer,
+				VirtualServerRouteSelectors: map[string][]string{},
+				HTTPPort:                    8082,
+				HTTPSPort:                   8442,
+			},
+		},
+		{
+			Op: AddOrUpdate,
+			Resource: &VirtualServerConfiguration{
+				VirtualServer:               cafeVirtualServer,
+				VirtualServerRouteSelectors: map[string][]string{},
+				HTTPPort:                    8082,
+				HTTPSPort:                   8442,
+				HTTP3ReusePort:              true,
+			},
+		},
+	}
+
+	addOrUpdateVirtualServer(t, configuration, cafeVirtualServer, expectedChanges, noProblems)
+
+	// reuseport is moved back when the VirtualServer with the first host is deleted
+	expectedChanges = []ResourceChange{
+		{
+			Op: Delete,
+			Resource: &VirtualServerConfiguration{
+				VirtualServer:               cafeVirtualServer,
+				VirtualServerRouteSelectors: map[string][]string{},
+				HTTPPort:                    8082,
+				HTTPSPort:                   8442,
+				HTTP3ReusePort:              true,
+			},
+		},
+		{
+			Op: AddOrUpdate,
+			Resource: &VirtualServerConfiguration{
+				VirtualServer:               teaVirtualServer,
+				VirtualServerRouteSelectors: map[string][]string{},
+				HTTPPort:                    8082,
+				HTTPSPort:                   8442,
+				HTTP3ReusePort:              true,
+			},
+		},
+	}
+
+	changes, problems := configuration.DeleteVirtualServer("default/cafe")
+	if diff := cmp.Diff(expectedChanges, changes); diff != "" {
+		t.Errorf("DeleteVirtualServer() returned unexpected result (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(noProblems, problems); diff != "" {
+		t.Errorf("DeleteVirtualServer() returned unexpected problems (-want +got):\n%s", diff)
+	}
+}
+
+func TestAddVirtualServerWithMisconfiguredAdditionalListeners(t *testing.T) {
+	t.Parallel()
+	configuration := createTestConfiguration()
+
+	listeners := []conf_v1.Listener{
+		{Name: "http-8082", Port: 8082, Protocol: "HTTP"},
+		{Name: "http-8080", Port: 8080, Protocol: "HTTP"},
+		{Name: "tcp-5353", Port: 5353, Protocol: "TCP"},
+	}
+	addOrUpdateGlobalConfiguration(t, configuration, listeners, noChanges, noProblems)
+
+	virtualServer := createTestVirtualServerWithListeners(
+		"cafe",
+		"cafe.example.com",
+		"http-8082",
+		"",
+	)
+	virtualServer.Spec.Listener.Additional = []string{"http-8080", "http-8082", "http-8080", "http-bogus", "tcp-5353"}
+
+	expectedChanges := []ResourceChange{
+		{
+			Op: AddOrUpdate,
+			Resource: &VirtualServerConfiguration{
+				VirtualServer:               virtualServer,
+				VirtualServerRouteSelectors: map[string][]string{},
+				HTTPPort:                    8082,
+				AdditionalListeners: []conf_v1.Listener{
+					{Name: "http-8080", Port: 8080, Protocol: "HTTP"},
+				},
+				Warnings: []string{
+					"Listener http-8082 is referenced more than once and is ignored in `listener.additional`",
+					"Listener http-8080 is referenced more than once and is ignored in `listener.additional`",
+					"Listener http-bogus is not defined in GlobalConfiguration",
+					"Listener tcp-5353 can't be used in `listener.additional` context as it is not an HTTP listener",
+				},
+			},
+		},
+	}
+
+	addOrUpdateVirtualServer(t, configuration, virtualServer, expectedChanges, noProblems)
+}
+
+func TestUsedListeners(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		listener *conf_v1.VirtualServerListener
+		expected map[string]bool
+		msg      string
+	}{
+		{
+			listener: &conf_v1.VirtualServerListener{HTTP: "http-8082", HTTPS: "https-8442"},
+			expected: map[string]bool{"http-8082": true, "https-8442": true},
+			msg:      "http and https listeners",
+		},
+		{
+			listener: &conf_v1.VirtualServerListener{HTTP: "http-8082"},
+			expected: map[string]bool{"http-8082": true},
+			msg:      "http listener only",
+		},
+		{
+			listener: &conf_v1.VirtualServerListener{HTTPS: "https-8442"},
+			expected: map[string]bool{"https-8442": true},
+			msg:      "https listener only",
+		},
+	}
+
+	for _, test := range tests {
+		result := usedListeners(test.listener)
+		if diff := cmp.Diff(test.expected, result); diff != "" {
+			t.Errorf("usedListeners() returned unexpected result for the case of %s (-want +got):\n%s", test.msg, diff)
+		}
+	}
+}
+
 func TestDeleteHttpListenerFromExistingGlobalConfigurationWithVirtualServerDeployedWithValidCustomListeners(t *testing.T) {
 	t.Parallel()
 	configuration := createTestConfiguration()
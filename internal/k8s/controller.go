@@ -77,9 +77,8 @@ const (
 	// IngressControllerName holds Ingress Controller name
 	IngressControllerName = "nginx.org/ingress-controller"
 
-	typeKeyword                                     = "type"
-	helmReleaseType                                 = "helm.sh/release.v1"
-	splitClientAmountWhenWeightChangesDynamicReload = 101
+	typeKeyword     = "type"
+	helmReleaseType = "helm.sh/release.v1"
 )
 
 var (
@@ -208,6 +207,7 @@ type LoadBalancerController struct {
 	isNginxReady                  bool
 	isPrometheusEnabled           bool
 	isLatencyMetricsEnabled       bool
+	upstreamServerPodComments     bool
 	configuration                 *Configuration
 	secretStore                   secrets.SecretStore
 	appProtectConfiguration       appprotect.Configuration
@@ -291,6 +291,7 @@ type NewLoadBalancerControllerInput struct {
 	VirtualServerValidator       *validation.VirtualServerValidator
 	IsPrometheusEnabled          bool
 	IsLatencyMetricsEnabled      bool
+	UpstreamServerPodComments    bool
 	IsTLSPassthroughEnabled      bool
 	TLSPassthroughPort           int
 	SnippetsEnabled              bool
@@ -347,6 +348,7 @@ func NewLoadBalancerController(input NewLoadBalancerControllerInput) *LoadBalanc
 		transportServerValidator:     input.TransportServerValidator,
 		isPrometheusEnabled:          input.IsPrometheusEnabled,
 		isLatencyMetricsEnabled:      input.IsLatencyMetricsEnabled,
+		upstreamServerPodComments:    input.UpstreamServerPodComments,
 		isIPV6Disabled:               input.IsIPV6Disabled,
 		weightChangesDynamicReload:   input.DynamicWeightChangesReload,
 		nginxConfigMapName:           input.ConfigMaps,
@@ -3015,6 +3017,10 @@ func (lbc *LoadBalancerController) createVirtualServerEx(virtualServer *conf_v1.
 		virtualServerEx.HTTPIPv6 = vsc.HTTPIPv6
 		virtualServerEx.HTTPSIPv4 = vsc.HTTPSIPv4
 		virtualServerEx.HTTPSIPv6 = vsc.HTTPSIPv6
+		virtualServerEx.HTTPListenerOptions = vsc.HTTPListenerOptions
+		virtualServerEx.HTTPSListenerOptions = vsc.HTTPSListenerOptions
+		virtualServerEx.HTTP3ReusePort = vsc.HTTP3ReusePort
+		virtualServerEx.AdditionalListeners = vsc.AdditionalListeners
 	}
 
 	if virtualServer.Spec.TLS != nil && virtualServer.Spec.TLS.Secret != "" {
@@ -3088,6 +3094,7 @@ func (lbc *LoadBalancerController) createVirtualServerEx(virtualServer *conf_v1.
 	endpoints := make(map[string][]string)
 	externalNameSvcs := make(map[string]bool)
 	podsByIP := make(map[string]configs.PodInfo)
+	terminatingEndpoints := make(map[string][]string)
 
 	// generateBackupEndpoints takes the Upstream, determines if backup and backup port are defined.
 	// If backup and backup port are defined it generates a backup server entry for the upstream.
@@ -3142,7 +3149,13 @@ func (lbc *LoadBalancerController) createVirtualServerEx(virtualServer *conf_v1.
 
 			endps = getIPAddressesFromEndpoints(podEndps)
 
-			if (lbc.isNginxPlus && lbc.isPrometheusEnabled) || lbc.isLatencyMetricsEnabled {
+			if len(u.Subselector) == 0 && (u.KeepTerminatingEndpoints || (lbc.isNginxPlus && u.Drain)) {
+				if terminatingEndps := lbc.getTerminatingEndpointsForUpstream(endps, serviceNamespace, serviceName, u.Port); len(terminatingEndps) > 0 {
+					terminatingEndpoints[endpointsKey] = terminatingEndps
+				}
+			}
+
+			if (lbc.isNginxPlus && lbc.isPrometheusEnabled) || lbc.isLatencyMetricsEnabled || lbc.upstreamServerPodComments {
 				for _, endpoint := range podEndps {
 					podsByIP[endpoint.Address] = configs.PodInfo{
 						Name:         endpoint.PodName,
@@ -3308,7 +3321,13 @@ func (lbc *LoadBalancerController) createVirtualServerEx(virtualServer *conf_v1.
 
 				endps = getIPAddressesFromEndpoints(podEndps)
 
-				if lbc.isNginxPlus || lbc.isLatencyMetricsEnabled {
+				if len(u.Subselector) == 0 && (u.KeepTerminatingEndpoints || (lbc.isNginxPlus && u.Drain)) {
+					if terminatingEndps := lbc.getTerminatingEndpointsForUpstream(endps, serviceNamespace, serviceName, u.Port); len(terminatingEndps) > 0 {
+						terminatingEndpoints[endpointsKey] = terminatingEndps
+					}
+				}
+
+				if lbc.isNginxPlus || lbc.isLatencyMetricsEnabled || lbc.upstreamServerPodComments {
 					for _, endpoint := range podEndps {
 						podsByIP[endpoint.Address] = configs.PodInfo{
 							Name:         endpoint.PodName,
@@ -3330,6 +3349,7 @@ func (lbc *LoadBalancerController) createVirtualServerEx(virtualServer *conf_v1.
 	virtualServerEx.ExternalNameSvcs = externalNameSvcs
 	virtualServerEx.Policies = createPolicyMap(policies)
 	virtualServerEx.PodsByIP = podsByIP
+	virtualServerEx.TerminatingEndpoints = terminatingEndpoints
 
 	return &virtualServerEx
 }
@@ -3541,7 +3561,7 @@ func (lbc *LoadBalancerController) addJWTSecretRefs(secretRefs map[string]*secre
 
 func (lbc *LoadBalancerController) addBasicSecretRefs(secretRefs map[string]*secrets.SecretReference, policies []*conf_v1.Policy) error {
 	for _, pol := range policies {
-		if pol.Spec.BasicAuth == nil {
+		if pol.Spec.BasicAuth == nil || pol.Spec.BasicAuth.Secret == "" {
 			continue
 		}
 
@@ -3885,6 +3905,69 @@ func filterReadyEndpointsFrom(esx []discovery_v1.EndpointSlice) []discovery_v1.E
 	return epx
 }
 
+// filterDrainingEndpointsFrom returns Endpoints that are terminating but still serving from given EndpointSlices.
+func filterDrainingEndpointsFrom(esx []discovery_v1.EndpointSlice) []discovery_v1.Endpoint {
+	epx := make([]discovery_v1.Endpoint, 0, len(esx))
+	for _, es := range esx {
+		for _, e := range es.Endpoints {
+			if e.Conditions.Ready != nil && *e.Conditions.Ready {
+				continue
+			}
+			if e.Conditions.Serving == nil || !*e.Conditions.Serving {
+				continue
+			}
+			if e.Conditions.Terminating != nil && *e.Conditions.Terminating {
+				epx = append(epx, e)
+			}
+		}
+	}
+	return epx
+}
+
+// getDrainingEndpointsFromEndpointSlices returns the addresses of the draining endpoints for given targetPort.
+func getDrainingEndpointsFromEndpointSlices(targetPort int32, svcEndpointSlices []discovery_v1.EndpointSlice) []string {
+	endpointSet := make(map[string]struct{})
+	for _, endpoint := range filterDrainingEndpointsFrom(selectEndpointSlicesForPort(targetPort, svcEndpointSlices)) {
+		for _, address := range endpoint.Addresses {
+			endpointSet[ipv6SafeAddrPort(address, targetPort)] = struct{}{}
+		}
+	}
+	return slices.Sorted(maps.Keys(endpointSet))
+}
+
+// getTerminatingEndpointsForUpstream returns the draining endpoints of the upstream service that are not in endps,
+// so that NGINX can keep them in the upstream without sending them new traffic while their nodes are drained.
+func (lbc *LoadBalancerController) getTerminatingEndpointsForUpstream(endps []string, namespace string, upstreamService string, upstreamPort uint16) []string {
+	svc, err := lbc.getServiceForUpstream(namespace, upstreamService, upstreamPort)
+	if err != nil || svc.Spec.Type == api_v1.ServiceTypeExternalName {
+		return nil
+	}
+
+	var targetPort int32
+	for _, port := range svc.Spec.Ports {
+		if port.Port == int32(upstreamPort) {
+			targetPort, err = lbc.getTargetPort(port, svc)
+			if err != nil {
+				return nil
+			}
+			break
+		}
+	}
+
+	svcEndpointSlices, err := lbc.getNamespacedInformer(svc.Namespace).endpointSliceLister.GetServiceEndpointSlices(svc)
+	if err != nil {
+		return nil
+	}
+
+	var terminatingEndps []string
+	for _, endpoint := range getDrainingEndpointsFromEndpointSlices(targetPort, svcEndpointSlices) {
+		if !slices.Contains(endps, endpoint) {
+			terminatingEndps = append(terminatingEndps, endpoint)
+		}
+	}
+	return terminatingEndps
+}
+
 func getEndpointsFromEndpointSlicesForSubselectedPods(targetPort int32, pods []*api_v1.Pod, svcEndpointSlices []discovery_v1.EndpointSlice) (podEndpoints []podEndpoint) {
 	// Match ready endpoints IP ddresses with Pod's IP. If they match create a new podEnpoint.
 	makePodEndpoints := func(pods []*api_v1.Pod, endpoints []discovery_v1.Endpoint) []podEndpoint {
@@ -4230,41 +4313,8 @@ func (lbc *LoadBalancerController) IsNginxReady() bool {
 }
 
 func (lbc *LoadBalancerController) processVSWeightChangesDynamicReload(vsOld *conf_v1.VirtualServer, vsNew *conf_v1.VirtualServer) {
-	var weightUpdates []configs.WeightUpdate
-	var splitClientsIndex int
 	variableNamer := configs.NewVSVariableNamer(vsNew)
-
-	for i, routeNew := range vsNew.Spec.Routes {
-		routeOld := vsOld.Spec.Routes[i]
-		for j, matchNew := range routeNew.Matches {
-			matchOld := routeOld.Matches[j]
-			if len(matchNew.Splits) == 2 {
-				if matchNew.Splits[0].Weight != matchOld.Splits[0].Weight || matchNew.Splits[1].Weight != matchOld.Splits[1].Weight {
-					weightUpdates = append(weightUpdates, configs.WeightUpdate{
-						Zone:  variableNamer.GetNameOfKeyvalZoneForSplitClientIndex(splitClientsIndex),
-						Key:   variableNamer.GetNameOfKeyvalKeyForSplitClientIndex(splitClientsIndex),
-						Value: variableNamer.GetNameOfKeyOfMapForWeights(splitClientsIndex, matchNew.Splits[0].Weight, matchNew.Splits[1].Weight),
-					})
-				}
-				splitClientsIndex += splitClientAmountWhenWeightChangesDynamicReload
-			} else if len(matchNew.Splits) > 0 {
-				splitClientsIndex++
-			}
-		}
-		if len(routeNew.Splits) == 2 {
-			if routeNew.Splits[0].Weight != routeOld.Splits[0].Weight || routeNew.Splits[1].Weight != routeOld.Splits[1].Weight {
-				weightUpdates = append(weightUpdates, configs.WeightUpdate{
-					Zone:  variableNamer.GetNameOfKeyvalZoneForSplitClientIndex(splitClientsIndex),
-					Key:   variableNamer.GetNameOfKeyvalKeyForSplitClientIndex(splitClientsIndex),
-					Value: variableNamer.GetNameOfKeyOfMapForWeights(splitClientsIndex, routeNew.Splits[0].Weight, routeNew.Splits[1].Weight),
-				})
-				splitClientsIndex += splitClientAmountWhenWeightChangesDynamicReload
-			}
-			splitClientsIndex += splitClientAmountWhenWeightChangesDynamicReload
-		} else if len(routeNew.Splits) > 0 {
-			splitClientsIndex++
-		}
-	}
+	weightUpdates := getRoutesWeightUpdates(variableNamer, vsOld.Spec.Routes, vsNew.Spec.Routes)
 
 	if len(weightUpdates) == 0 {
 		return
@@ -4301,42 +4351,8 @@ func (lbc *LoadBalancerController) processVSRWeightChangesDynamicReload(vsrOld *
 		return
 	}
 
-	var weightUpdates []configs.WeightUpdate
-
-	splitClientsIndex := lbc.getStartingSplitClientsIndex(vsrNew, vsEx)
-
 	variableNamer := configs.NewVSVariableNamer(vsEx.VirtualServer)
-
-	for i, routeNew := range vsrNew.Spec.Subroutes {
-		routeOld := vsrOld.Spec.Subroutes[i]
-		for j, matchNew := range routeNew.Matches {
-			matchOld := routeOld.Matches[j]
-			if len(matchNew.Splits) == 2 {
-				if matchNew.Splits[0].Weight != matchOld.Splits[0].Weight || matchNew.Splits[1].Weight != matchOld.Splits[1].Weight {
-					weightUpdates = append(weightUpdates, configs.WeightUpdate{
-						Zone:  variableNamer.GetNameOfKeyvalZoneForSplitClientIndex(splitClientsIndex),
-						Key:   variableNamer.GetNameOfKeyvalKeyForSplitClientIndex(splitClientsIndex),
-						Value: variableNamer.GetNameOfKeyOfMapForWeights(splitClientsIndex, matchNew.Splits[0].Weight, matchNew.Splits[1].Weight),
-					})
-				}
-				splitClientsIndex += splitClientAmountWhenWeightChangesDynamicReload
-			} else if len(matchNew.Splits) > 0 {
-				splitClientsIndex++
-			}
-		}
-		if len(routeNew.Splits) == 2 {
-			if routeNew.Splits[0].Weight != routeOld.Splits[0].Weight || routeNew.Splits[1].Weight != routeOld.Splits[1].Weight {
-				weightUpdates = append(weightUpdates, configs.WeightUpdate{
-					Zone:  variableNamer.GetNameOfKeyvalZoneForSplitClientIndex(splitClientsIndex),
-					Key:   variableNamer.GetNameOfKeyvalKeyForSplitClientIndex(splitClientsIndex),
-					Value: variableNamer.GetNameOfKeyOfMapForWeights(splitClientsIndex, routeNew.Splits[0].Weight, routeNew.Splits[1].Weight),
-				})
-			}
-			splitClientsIndex += splitClientAmountWhenWeightChangesDynamicReload
-		} else if len(routeNew.Splits) > 0 {
-			splitClientsIndex++
-		}
-	}
+	weightUpdates := getRoutesWeightUpdates(variableNamer, vsrOld.Spec.Subroutes, vsrNew.Spec.Subroutes)
 
 	if halt {
 		return
@@ -4347,46 +4363,42 @@ func (lbc *LoadBalancerController) processVSRWeightChangesDynamicReload(vsrOld *
 	}
 }
 
-func (lbc *LoadBalancerController) getStartingSplitClientsIndex(vsr *conf_v1.VirtualServerRoute, vsEx *configs.VirtualServerEx) int {
-	var startingSplitClientsIndex int
+// getRoutesWeightUpdates returns the keyval updates for the two-way splits whose weights changed between the old and the new routes.
+func getRoutesWeightUpdates(variableNamer *configs.VariableNamer, routesOld []conf_v1.Route, routesNew []conf_v1.Route) []configs.WeightUpdate {
+	var weightUpdates []configs.WeightUpdate
 
-	for _, r := range vsEx.VirtualServer.Spec.Routes {
-		for _, match := range r.Matches {
-			if len(match.Splits) == 2 {
-				startingSplitClientsIndex += splitClientAmountWhenWeightChangesDynamicReload
-			} else if len(match.Splits) > 0 {
-				startingSplitClientsIndex++
+	for i, routeNew := range routesNew {
+		routeOld := routesOld[i]
+		for j, matchNew := range routeNew.Matches {
+			matchOld := routeOld.Matches[j]
+			if weightUpdate, changed := getSplitsWeightUpdate(variableNamer, routeNew.Path, matchNew.Conditions, matchOld.Splits, matchNew.Splits); changed {
+				weightUpdates = append(weightUpdates, weightUpdate)
 			}
 		}
-		if len(r.Splits) == 2 {
-			startingSplitClientsIndex += splitClientAmountWhenWeightChangesDynamicReload
-		} else if len(r.Splits) > 0 {
-			startingSplitClientsIndex++
+		if weightUpdate, changed := getSplitsWeightUpdate(variableNamer, routeNew.Path, nil, routeOld.Splits, routeNew.Splits); changed {
+			weightUpdates = append(weightUpdates, weightUpdate)
 		}
-
 	}
 
-	for _, vsRoute := range vsEx.VirtualServerRoutes {
-		if vsRoute.Name == vsr.Name {
-			return startingSplitClientsIndex
-		}
-		for _, r := range vsRoute.Spec.Subroutes {
-			for _, match := range r.Matches {
-				if len(match.Splits) == 2 {
-					startingSplitClientsIndex += splitClientAmountWhenWeightChangesDynamicReload
-				} else if len(match.Splits) > 0 {
-					startingSplitClientsIndex++
-				}
-			}
-			if len(r.Splits) == 2 {
-				startingSplitClientsIndex += splitClientAmountWhenWeightChangesDynamicReload
-			} else if len(r.Splits) > 0 {
-				startingSplitClientsIndex++
-			}
-		}
+	return weightUpdates
+}
+
+func getSplitsWeightUpdate(variableNamer *configs.VariableNamer, path string, conditions []conf_v1.Condition, splitsOld []conf_v1.Split, splitsNew []conf_v1.Split) (configs.WeightUpdate, bool) {
+	if len(splitsNew) != 2 {
+		return configs.WeightUpdate{}, false
+	}
+	splitsOld = configs.PromoteSplit(splitsOld)
+	splitsNew = configs.PromoteSplit(splitsNew)
+	if splitsNew[0].Weight == splitsOld[0].Weight && splitsNew[1].Weight == splitsOld[1].Weight {
+		return configs.WeightUpdate{}, false
 	}
 
-	return startingSplitClientsIndex
+	id := configs.GetSplitClientsID(path, conditions, splitsNew)
+	return configs.WeightUpdate{
+		Zone:  variableNamer.GetNameOfKeyvalZoneForSplitClients(id),
+		Key:   variableNamer.GetNameOfKeyvalKeyForSplitClients(id),
+		Value: variableNamer.GetNameOfKeyOfMapForWeights(id, splitsNew[0].Weight, splitsNew[1].Weight),
+	}, true
 }
 
 func (lbc *LoadBalancerController) haltIfVSConfigInvalid(vsNew *conf_v1.VirtualServer) bool {
@@ -4510,17 +4522,27 @@ func (lbc *LoadBalancerController) vsrHasWeightChanges(vsrOld *conf_v1.VirtualSe
 		routeOld := vsrOld.Spec.Subroutes[i]
 		for j, matchNew := range routeNew.Matches {
 			matchOld := routeOld.Matches[j]
-			if len(matchNew.Splits) == 2 && (matchNew.Splits[0].Weight != matchOld.Splits[0].Weight || matchNew.Splits[1].Weight != matchOld.Splits[1].Weight) {
+			if haveSplitWeightsChanged(matchOld.Splits, matchNew.Splits) {
 				return true
 			}
 		}
-		if len(routeNew.Splits) == 2 && (routeNew.Splits[0].Weight != routeOld.Splits[0].Weight || routeNew.Splits[1].Weight != routeOld.Splits[1].Weight) {
+		if haveSplitWeightsChanged(routeOld.Splits, routeNew.Splits) {
 			return true
 		}
 	}
 	return false
 }
 
+// haveSplitWeightsChanged checks whether the weights of a two-way split changed, including by a promotion of one of the splits.
+func haveSplitWeightsChanged(splitsOld []conf_v1.Split, splitsNew []conf_v1.Split) bool {
+	if len(splitsNew) != 2 {
+		return false
+	}
+	splitsOld = configs.PromoteSplit(splitsOld)
+	splitsNew = configs.PromoteSplit(splitsNew)
+	return splitsNew[0].Weight != splitsOld[0].Weight || splitsNew[1].Weight != splitsOld[1].Weight
+}
+
 func (lbc *LoadBalancerController) createCombinedDeploymentHeadlessServiceName() string {
 	owner := lbc.metadata.pod.ObjectMeta.OwnerReferences[0]
 	name := owner.Name
@@ -1009,6 +1009,82 @@ func TestGetEndpointsFromEndpointSlices_TwoDifferentEndpointsInOnEndpointSliceOn
 	}
 }
 
+func TestGetDrainingEndpointsFromEndpointSlices(t *testing.T) {
+	t.Parallel()
+	svcEndpointSlices := []discovery_v1.EndpointSlice{
+		{
+			Ports: []discovery_v1.EndpointPort{
+				{
+					Port: new(int32(8080)),
+				},
+			},
+			Endpoints: []discovery_v1.Endpoint{
+				{
+					Addresses: []string{
+						"1.2.3.4",
+					},
+					Conditions: discovery_v1.EndpointConditions{
+						Ready: new(true),
+					},
+				},
+				{
+					Addresses: []string{
+						"5.6.7.8",
+					},
+					Conditions: discovery_v1.EndpointConditions{
+						Ready:       new(false),
+						Serving:     new(true),
+						Terminating: new(true),
+					},
+				},
+				{
+					Addresses: []string{
+						"9.10.11.12",
+					},
+					Conditions: discovery_v1.EndpointConditions{
+						Ready:       new(false),
+						Serving:     new(false),
+						Terminating: new(true),
+					},
+				},
+				{
+					Addresses: []string{
+						"13.14.15.16",
+					},
+					Conditions: discovery_v1.EndpointConditions{
+						Ready: new(false),
+					},
+				},
+			},
+		},
+		{
+			Ports: []discovery_v1.EndpointPort{
+				{
+					Port: new(int32(9090)),
+				},
+			},
+			Endpoints: []discovery_v1.Endpoint{
+				{
+					Addresses: []string{
+						"17.18.19.20",
+					},
+					Conditions: discovery_v1.EndpointConditions{
+						Ready:       new(false),
+						Serving:     new(true),
+						Terminating: new(true),
+					},
+				},
+			},
+		},
+	}
+
+	want := []string{"5.6.7.8:8080"}
+	got := getDrainingEndpointsFromEndpointSlices(8080, svcEndpointSlices)
+	if !cmp.Equal(want, got) {
+		t.Errorf("getDrainingEndpointsFromEndpointSlices() got %v, want %v", got, want)
+	}
+}
+
 func TestGetEndpointsFromEndpointSlices_TwoDifferentEndpointsAcrossTwoEndpointSlicesOneEndpointNotReady(t *testing.T) {
 	t.Parallel()
 	endpointPort := int32(8080)
@@ -4911,3 +4987,42 @@ func TestGenerateExternalAuthEndpoints(t *testing.T) {
 		})
 	}
 }
+
+func TestGetSplitsWeightUpdateWithPromotedSplit(t *testing.T) {
+	t.Parallel()
+
+	vs := &conf_v1.VirtualServer{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "cafe",
+			Namespace: "default",
+		},
+	}
+	variableNamer := configs.NewVSVariableNamer(vs)
+	splitsOld := []conf_v1.Split{
+		{Weight: 90, Action: &conf_v1.Action{Pass: "coffee-v1"}},
+		{Weight: 10, Action: &conf_v1.Action{Pass: "coffee-v2"}},
+	}
+	splitsNew := []conf_v1.Split{
+		{Weight: 90, Action: &conf_v1.Action{Pass: "coffee-v1"}},
+		{Weight: 10, Action: &conf_v1.Action{Pass: "coffee-v2"}, Promoted: true},
+	}
+
+	got, changed := getSplitsWeightUpdate(variableNamer, "/coffee", nil, splitsOld, splitsNew)
+	if !changed {
+		t.Fatal("getSplitsWeightUpdate() returned no update for the promotion of a split")
+	}
+
+	id := configs.GetSplitClientsID("/coffee", nil, splitsOld)
+	want := configs.WeightUpdate{
+		Zone:  variableNamer.GetNameOfKeyvalZoneForSplitClients(id),
+		Key:   variableNamer.GetNameOfKeyvalKeyForSplitClients(id),
+		Value: variableNamer.GetNameOfKeyOfMapForWeights(id, 0, 100),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("getSplitsWeightUpdate() returned unexpected result (-want +got):\n%s", diff)
+	}
+
+	if !haveSplitWeightsChanged(splitsOld, splitsNew) {
+		t.Error("haveSplitWeightsChanged() returned false for the promotion of a split")
+	}
+}
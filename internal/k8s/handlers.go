@@ -252,12 +252,17 @@ func zeroOutVirtualServerSplitWeights(vs *conf_v1.VirtualServer) {
 			if len(match.Splits) == 2 {
 				match.Splits[0].Weight = 0
 				match.Splits[1].Weight = 0
+				match.Splits[0].Promoted = false
+				match.Splits[1].Promoted = false
 			}
 		}
 
-		if len(route.Splits) == 2 {
+		// The weights of the splits with shared weights are compiled into the configuration and require a reload.
+		if len(route.Splits) == 2 && route.SplitWeights == nil {
 			route.Splits[0].Weight = 0
 			route.Splits[1].Weight = 0
+			route.Splits[0].Promoted = false
+			route.Splits[1].Promoted = false
 		}
 	}
 }
@@ -268,12 +273,17 @@ func zeroOutVirtualServerRouteSplitWeights(vs *conf_v1.VirtualServerRoute) {
 			if len(match.Splits) == 2 {
 				match.Splits[0].Weight = 0
 				match.Splits[1].Weight = 0
+				match.Splits[0].Promoted = false
+				match.Splits[1].Promoted = false
 			}
 		}
 
-		if len(route.Splits) == 2 {
+		// The weights of the splits with shared weights are compiled into the configuration and require a reload.
+		if len(route.Splits) == 2 && route.SplitWeights == nil {
 			route.Splits[0].Weight = 0
 			route.Splits[1].Weight = 0
+			route.Splits[0].Promoted = false
+			route.Splits[1].Promoted = false
 		}
 	}
 }
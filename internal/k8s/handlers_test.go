@@ -8,6 +8,7 @@ import (
 
 	nic_glog "github.com/nginx/kubernetes-ingress/internal/logger/glog"
 	"github.com/nginx/kubernetes-ingress/internal/logger/levels"
+	conf_v1 "github.com/nginx/kubernetes-ingress/pkg/apis/configuration/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
@@ -135,3 +136,42 @@ func TestAreResourcesDifferent(t *testing.T) {
 		}
 	}
 }
+
+func TestZeroOutVirtualServerSplitWeights(t *testing.T) {
+	t.Parallel()
+	vs := &conf_v1.VirtualServer{
+		Spec: conf_v1.VirtualServerSpec{
+			Routes: []conf_v1.Route{
+				{
+					Path: "/tea",
+					Splits: []conf_v1.Split{
+						{Weight: 90, Action: &conf_v1.Action{Pass: "tea-v1"}},
+						{Weight: 10, Action: &conf_v1.Action{Pass: "tea-v2"}, Promoted: true},
+					},
+				},
+				{
+					Path: "/coffee",
+					Splits: []conf_v1.Split{
+						{Weight: 90, Action: &conf_v1.Action{Pass: "coffee-v1"}},
+						{Weight: 10, Action: &conf_v1.Action{Pass: "coffee-v2"}},
+					},
+					SplitWeights: &conf_v1.SplitWeights{Key: "canary"},
+				},
+			},
+		},
+	}
+
+	zeroOutVirtualServerSplitWeights(vs)
+
+	for _, s := range vs.Spec.Routes[0].Splits {
+		if s.Weight != 0 {
+			t.Errorf("zeroOutVirtualServerSplitWeights() kept the weight %d of a split without shared weights", s.Weight)
+		}
+		if s.Promoted {
+			t.Errorf("zeroOutVirtualServerSplitWeights() kept the promotion of a split without shared weights")
+		}
+	}
+	if vs.Spec.Routes[1].Splits[0].Weight != 90 || vs.Spec.Routes[1].Splits[1].Weight != 10 {
+		t.Errorf("zeroOutVirtualServerSplitWeights() changed the weights of the splits with shared weights: %v", vs.Spec.Routes[1].Splits)
+	}
+}
@@ -69,6 +69,8 @@ type ServerConfig struct {
 	MaxConns    int
 	FailTimeout string
 	SlowStart   string
+	// DrainServers are the servers that are put in the draining mode instead of being removed.
+	DrainServers map[string]bool
 }
 
 // The Manager interface updates NGINX configuration, starts, reloads and quits NGINX,
@@ -452,6 +454,7 @@ func (lm *LocalManager) UpdateServersInPlus(upstream string, servers []string, c
 			MaxConns:    &config.MaxConns,
 			FailTimeout: config.FailTimeout,
 			SlowStart:   config.SlowStart,
+			Drain:       config.DrainServers[s],
 		})
 	}
 
@@ -74,6 +74,47 @@ func (v Version) PlusGreaterThanOrEqualTo(target string) (bool, error) {
 	return (r > tr || (r == tr && p >= tp)), nil
 }
 
+// OSSGreaterThanOrEqualTo compares the supplied nginx version string, for example 1.25.0, with the Version{} struct.
+func (v Version) OSSGreaterThanOrEqualTo(target string) (bool, error) {
+	major, minor, patch, err := extractOSSVersionValues(v.OSS)
+	if err != nil {
+		return false, err
+	}
+	tMajor, tMinor, tPatch, err := extractOSSVersionValues(target)
+	if err != nil {
+		return false, err
+	}
+
+	if major != tMajor {
+		return major > tMajor, nil
+	}
+	if minor != tMinor {
+		return minor > tMinor, nil
+	}
+	return patch >= tPatch, nil
+}
+
+var reOSS = regexp.MustCompile(`^(\d+)\.(\d+)\.(\d+)`)
+
+// extractOSSVersionValues splits the nginx version string into major, minor, and patch values.
+func extractOSSVersionValues(input string) (int, int, int, error) {
+	matches := reOSS.FindStringSubmatch(input)
+	if len(matches) == 0 {
+		return 0, 0, 0, fmt.Errorf("no matches found in the input string")
+	}
+
+	values := make([]int, 3)
+	for i := range values {
+		value, err := strconv.Atoi(matches[i+1])
+		if err != nil {
+			return 0, 0, 0, fmt.Errorf("failed to convert version value to integer: %w", err)
+		}
+		values[i] = value
+	}
+
+	return values[0], values[1], values[2], nil
+}
+
 var rePlus = regexp.MustCompile(`-r(\d+)(?:-p(\d+))?`)
 
 // extractPlusVersionValues
@@ -127,6 +127,71 @@ func TestNginxVersionPlusGreaterThanOrEqualTo(t *testing.T) {
 	}
 }
 
+func TestNginxVersionOSSGreaterThanOrEqualTo(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		version  nginx.Version
+		input    string
+		expected bool
+	}{
+		{
+			version:  nginx.NewVersion("nginx version: nginx/1.25.0"),
+			input:    "1.25.0",
+			expected: true,
+		},
+		{
+			version:  nginx.NewVersion("nginx version: nginx/1.27.4"),
+			input:    "1.25.0",
+			expected: true,
+		},
+		{
+			version:  nginx.NewVersion("nginx version: nginx/2.0.0"),
+			input:    "1.25.0",
+			expected: true,
+		},
+		{
+			version:  nginx.NewVersion("nginx version: nginx/1.25.3 (nginx-plus-r31)"),
+			input:    "1.25.0",
+			expected: true,
+		},
+		{
+			version:  nginx.NewVersion("nginx version: nginx/1.23.4"),
+			input:    "1.25.0",
+			expected: false,
+		},
+		{
+			version:  nginx.NewVersion("nginx version: nginx/1.25.1"),
+			input:    "1.25.2",
+			expected: false,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.version.String(), func(t *testing.T) {
+			t.Parallel()
+			actual, err := tc.version.OSSGreaterThanOrEqualTo(tc.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual != tc.expected {
+				t.Errorf("expected %v but got %v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestNginxVersionOSSGreaterThanOrEqualToFailsOnInvalidInput(t *testing.T) {
+	t.Parallel()
+	version := nginx.NewVersion("nginx version: nginx/1.25.0")
+	for _, input := range []string{"", "nginx-plus-r30", "1.25"} {
+		if _, err := version.OSSGreaterThanOrEqualTo(input); err == nil {
+			t.Errorf("OSSGreaterThanOrEqualTo(%q) returned no error", input)
+		}
+	}
+	if _, err := (nginx.Version{}).OSSGreaterThanOrEqualTo("1.25.0"); err == nil {
+		t.Error("OSSGreaterThanOrEqualTo() returned no error for the empty version")
+	}
+}
+
 func TestNginxVersionPlusGreaterThanOrEqualToFailsOnInalidInput(t *testing.T) {
 	t.Parallel()
 
@@ -22,6 +22,7 @@ var configMapFilteredKeys = []string{
 	"proxy-read-timeout",
 	"proxy-send-timeout",
 	"proxy-hide-headers",
+	"default-proxy-hide-headers",
 	"proxy-pass-headers",
 	"client-max-body-size",
 	"server-names-hash-bucket-size",
@@ -69,7 +70,10 @@ var configMapFilteredKeys = []string{
 	"keepalive",
 	"max-fails",
 	"upstream-zone-size",
+	"upstream-zone-omit-max-servers",
+	"upstream-ssl-max-verify-depth",
 	"fail-timeout",
+	"health-check-grpc-service",
 	"main-template",
 	"ingress-template",
 	"virtualserver-template",
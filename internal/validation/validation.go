@@ -18,6 +18,11 @@ var (
 	validHostnameRegex = regexp.MustCompile(`^[a-z][A-Za-z0-9-]{1,62}(?::\d{1,5})?$`)
 )
 
+// GRPCServiceFmt is the pattern a gRPC health check service name must match.
+const GRPCServiceFmt = `[^\s{};]*`
+
+var validGRPCServiceRegex = regexp.MustCompile("^" + GRPCServiceFmt + "$")
+
 // ValidatePort ensure port matches rfc6335 https://www.rfc-editor.org/rfc/rfc6335.html
 func ValidatePort(value int) error {
 	if value > 65535 || value < 1 {
@@ -57,6 +62,14 @@ func ValidateHost(host string) error {
 	return fmt.Errorf("error parsing host: %s not a valid host", host)
 }
 
+// ValidateGRPCService ensures the gRPC service name does not include any whitespace character, `{`, `}`, or `;`
+func ValidateGRPCService(service string) error {
+	if !validGRPCServiceRegex.MatchString(service) {
+		return fmt.Errorf("error parsing gRPC service: %q must not include any whitespace character, `{`, `}`, or `;`", service)
+	}
+	return nil
+}
+
 // URIValidationOption defines a functional option pattern for configuring the
 // unexported uriValidator that gets used in ValidateURI.
 type URIValidationOption func(u *uriValidator)
@@ -205,3 +205,20 @@ func TestValidateURI(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateGRPCService(t *testing.T) {
+	t.Parallel()
+	validInput := []string{"", "grpc.health.v1.Health", "my-service"}
+	for _, input := range validInput {
+		if err := ValidateGRPCService(input); err != nil {
+			t.Errorf("ValidateGRPCService(%q) returned an error for valid input: %v", input, err)
+		}
+	}
+
+	invalidInput := []string{"my service", "service;", "service{", "service}"}
+	for _, input := range invalidInput {
+		if err := ValidateGRPCService(input); err == nil {
+			t.Errorf("ValidateGRPCService(%q) returned no error for invalid input", input)
+		}
+	}
+}
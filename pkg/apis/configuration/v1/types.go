@@ -51,12 +51,50 @@ type VirtualServerSpec struct {
 	Listener *VirtualServerListener `json:"listener"`
 	// The TLS termination configuration.
 	TLS *TLS `json:"tls"`
+	// Enables or disables HTTP/2 for the TLS listener of the VirtualServer. Overrides the http2 ConfigMap key.
+	// +kubebuilder:validation:Optional
+	HTTP2 *bool `json:"http2,omitempty"`
+	// Enables or disables HTTP/3 for the TLS listener of the VirtualServer. Requires the -enable-http3 command-line argument and NGINX 1.25.0 or NGINX Plus R30 or later. The support of HTTP/3 is advertised to the clients with the Alt-Svc response header.
+	// +kubebuilder:validation:Optional
+	HTTP3 *bool `json:"http3,omitempty"`
 	// Enables or disables decompression of gzipped responses for clients. Allowed values “on”/“off”, “true”/“false” or “yes”/“no”. If the gunzip value is not set, it defaults to off.
 	Gunzip bool `json:"gunzip"`
+	// Enables or disables the use of underscores in client request header fields for the VirtualServer. Applies only to the HTTPS requests, as NGINX reads the headers of the HTTP requests with the value of the default server of the listener. If not set, the value of the underscores_in_headers directive in the http context is used, which is off by default.
+	// +kubebuilder:validation:Optional
+	UnderscoresInHeaders *bool `json:"underscoresInHeaders,omitempty"`
+	// Enables or disables the compression of two or more adjacent slashes in the URI of a request into a single slash for the VirtualServer. If not set, the value of the merge_slashes directive in the http context is used, which is on by default. Note: when disabled, the URIs with adjacent slashes, for example, //path, are not matched by the routes for /path, so the policies of those routes are not applied to them.
+	// +kubebuilder:validation:Optional
+	MergeSlashes *bool `json:"mergeSlashes,omitempty"`
+	// Sets the MIME type of the responses generated by NGINX for the VirtualServer whose type is not otherwise determined, for example, the files with an unknown extension. It has no effect on the responses proxied from an upstream server, which are passed with the Content-Type header of the upstream server, if any. If not set, the default_type of the http context is used, which is application/octet-stream.
+	DefaultType string `json:"defaultType,omitempty"`
+	// Sets the level of the error log of the VirtualServer, overriding the error-log-level ConfigMap key for the server. Allowed values are debug, info, notice, warn, error, crit, alert and emerg. The debug level requires the nginx-debug binary.
+	ErrorLogLevel string `json:"errorLogLevel,omitempty"`
+	// Sets the destination of the error log of the VirtualServer. Allowed values are stderr and syslog:server=<address>[,parameters]. Requires errorLogLevel to be set. If not set, the default is stderr.
+	ErrorLogDestination string `json:"errorLogDestination,omitempty"`
+	// Rejects the requests whose Host header doesn't match the host of the VirtualServer, for example, the requests for another host sent over a TLS connection established for the VirtualServer.
+	StrictHost *StrictHost `json:"strictHost,omitempty"`
+	// Enables the variables with the request length and the bytes sent of each route for a custom access log format. The variables are named $vs_<namespace>_<name>_route_<index>_request_length and $vs_<namespace>_<name>_route_<index>_bytes_sent, where the dashes in the namespace and the name are replaced with underscores and the index is the position of the route among the routes of the VirtualServer followed by the subroutes of the VirtualServerRoutes. The variables are empty for the requests of the other routes. The default is false.
+	RouteSizeVariables bool `json:"routeSizeVariables,omitempty"`
+	// Allows the response headers added by the VirtualServer and its routes to reference the variables of the upstream, like $upstream_addr and $upstream_response_time, to show which upstream server processed a request. When disabled, the response headers that reference those variables are ignored, so that they are not exposed in production. The default is false.
+	DebugHeaders bool `json:"debugHeaders,omitempty"`
+	// Configures the response to the requests for an upstream without available endpoints, which is a 502 response by default. It doesn't apply to the routes with error pages for the responses of the upstream servers, nor to the routes that pass the 502 errors through.
+	NoEndpoints *NoEndpoints `json:"noEndpoints,omitempty"`
+	// Passes the request ID sent by the client, or a request ID generated by NGINX if the client didn't send one, to the upstream servers and back to the client.
+	RequestID *RequestID `json:"requestID,omitempty"`
+	// The response headers added to every response of the VirtualServer. The headers are also added to the responses of the routes that add their own response headers, unless the route adds a header with the same name, which takes precedence, or add-header-inherit is set.
+	ResponseHeaders *ResponseHeaders `json:"responseHeaders,omitempty"`
+	// Configures the OpenTelemetry tracing of the requests of the VirtualServer. Requires the otel-exporter-endpoint ConfigMap key.
+	Tracing *Tracing `json:"tracing,omitempty"`
 	// A list of policies.
 	Policies []PolicyReference `json:"policies"`
 	// A list of upstreams.
 	Upstreams []Upstream `json:"upstreams"`
+	// A list of health check matches that the health checks of the upstreams of the VirtualServer and its VirtualServerRoutes can reference by name. Each match is generated once, no matter how many upstreams reference it.
+	HealthCheckMatches []HealthCheckMatch `json:"healthCheckMatches,omitempty"`
+	// A list of timeout profiles that the upstreams of the VirtualServer and its VirtualServerRoutes can reference by name, so that the same connect, read and send timeouts are not repeated in every upstream.
+	TimeoutProfiles []TimeoutProfile `json:"timeoutProfiles,omitempty"`
+	// A list of geos that the conditions of the routes of the VirtualServer and its VirtualServerRoutes can reference by name to route the requests by the client IP address, for example, to regional upstreams.
+	Geos []Geo `json:"geos,omitempty"`
 	// A list of routes.
 	Routes []Route `json:"routes"`
 	// Sets a custom snippet in the http context.
@@ -72,12 +110,53 @@ type VirtualServerSpec struct {
 	ExternalDNS ExternalDNS `json:"externalDNS"`
 }
 
+// StrictHost defines the rejection of the requests for other hosts.
+type StrictHost struct {
+	// Enables the rejection of the requests whose Host header doesn't match the host of the VirtualServer. The default is false.
+	Enable bool `json:"enable"`
+	// The status code of the response to the rejected requests. The allowed values are: 400, 403, 404, 421 or 444. The default is 421.
+	Code *int `json:"code,omitempty"`
+}
+
+// NoEndpoints defines the response to the requests for an upstream without available endpoints.
+type NoEndpoints struct {
+	// The status code of the response. The allowed values are 502 and 503. The default is 502.
+	Code int `json:"code,omitempty"`
+	// The value of the Retry-After header of the response in seconds, so that the clients back off. Requires the code 503.
+	RetryAfter *int `json:"retryAfter,omitempty"`
+	// A custom response, for example, a maintenance page. Cannot be used together with code and retryAfter.
+	Return *ActionReturn `json:"return,omitempty"`
+}
+
+// RequestID defines the propagation of the request ID.
+type RequestID struct {
+	// The name of the header that carries the request ID. The default is X-Request-ID.
+	Header string `json:"header,omitempty"`
+}
+
+// Tracing defines the OpenTelemetry tracing of the requests.
+type Tracing struct {
+	// Enables or disables the tracing of the requests. The default is the value of the otel-trace-in-http ConfigMap key.
+	Enable *bool `json:"enable,omitempty"`
+	// Sets how the trace context is propagated in the traceparent and tracestate headers of the requests: extract uses the context of the incoming request, inject adds a new context to the request to the upstream, propagate does both and ignore does neither. The default is propagate.
+	// +kubebuilder:validation:Enum=extract;inject;propagate;ignore
+	Context string `json:"context,omitempty"`
+}
+
+// ResponseHeaders defines the response headers added at the server level of a VirtualServer.
+type ResponseHeaders struct {
+	// Adds headers to the response to the client.
+	Add []AddHeader `json:"add"`
+}
+
 // VirtualServerListener references a custom http and/or https listener defined in GlobalConfiguration.
 type VirtualServerListener struct {
 	// The name of an HTTP listener defined in a GlobalConfiguration resource.
 	HTTP string `json:"http"`
 	// The name of an HTTPS listener defined in a GlobalConfiguration resource.
 	HTTPS string `json:"https"`
+	// The names of additional HTTP or HTTPS listeners defined in a GlobalConfiguration resource. The VirtualServer is served on the ports of these listeners in addition to the ports of the http and https listeners. The HTTPS listeners are ignored when TLS passthrough is enabled.
+	Additional []string `json:"additional,omitempty"`
 }
 
 // ExternalDNS defines externaldns sub-resource of a virtual server.
@@ -136,18 +215,28 @@ type Upstream struct {
 	MaxConns *int `json:"max-conns"`
 	// Configures the cache for connections to upstream servers. The value 0 disables the cache. The default is set in the keepalive ConfigMap key.
 	Keepalive *int `json:"keepalive"`
+	// Limits the maximum time during which requests can be processed through one keepalive connection to an upstream server. After this time is reached, the connection is closed following the subsequent request processing. Setting it is recommended for services of type ExternalName, as the keepalive connections to the previously resolved addresses are otherwise reused for up to one hour. The default is 1h.
+	KeepaliveTime string `json:"keepalive-time"`
 	// The timeout for establishing a connection with an upstream server. The default is specified in the proxy-connect-timeout ConfigMap key.
 	ProxyConnectTimeout string `json:"connect-timeout"`
 	// The timeout for reading a response from an upstream server. The default is specified in the proxy-read-timeout ConfigMap key.
 	ProxyReadTimeout string `json:"read-timeout"`
 	// The timeout for transmitting a request to an upstream server. The default is specified in the proxy-send-timeout ConfigMap key.
 	ProxySendTimeout string `json:"send-timeout"`
-	// Specifies in which cases a request should be passed to the next upstream server. The default is error timeout.
+	// The name of a timeout profile defined in the timeoutProfiles of the VirtualServer. The profile sets the connect, read and send timeouts of the upstream, unless they are set in the connect-timeout, read-timeout and send-timeout fields of the upstream.
+	TimeoutProfile string `json:"timeout-profile,omitempty"`
+	// Specifies in which cases a request should be passed to the next upstream server. For gRPC upstreams, grpc_next_upstream is configured instead of proxy_next_upstream, along with the next-upstream-timeout and next-upstream-tries. The default is error timeout.
 	ProxyNextUpstream string `json:"next-upstream"`
 	// The time during which a request can be passed to the next upstream server. The 0 value turns off the time limit. The default is 0.
 	ProxyNextUpstreamTimeout string `json:"next-upstream-timeout"`
 	// The number of possible tries for passing a request to the next upstream server. The 0 value turns off this limit. The default is 0.
 	ProxyNextUpstreamTries int `json:"next-upstream-tries"`
+	// Enables TCP keepalive for the connections to the upstream servers. For gRPC upstreams, grpc_socket_keepalive is configured instead of proxy_socket_keepalive. The default is false.
+	ProxySocketKeepalive *bool `json:"socket-keepalive,omitempty"`
+	// Keeps the connection to the upstream server open when the client closes the connection without waiting for the response, so that the upstream server completes the request. Use it for the requests with side effects that must not be interrupted. The connections of the aborted requests are kept until the upstream server responds, which can exhaust the connections when the upstream server is slow. Not supported for upstreams of type grpc. The default is false.
+	ProxyIgnoreClientAbort *bool `json:"ignore-client-abort,omitempty"`
+	// Configures the proxying of WebSocket connections to the upstream servers.
+	WebSocket *UpstreamWebSocket `json:"websocket,omitempty"`
 	// Enables buffering of responses from the upstream server.  The default is set in the proxy-buffering ConfigMap key.
 	ProxyBuffering *bool `json:"buffering"`
 	// Configures the buffers used for reading a response from the upstream server for a single connection.
@@ -156,13 +245,18 @@ type Upstream struct {
 	ProxyBufferSize string `json:"buffer-size"`
 	// Sets the size of the buffers used for reading a response from the upstream server when the proxy_buffering is enabled. The default is set in the proxy-busy-buffers-size ConfigMap key.'
 	ProxyBusyBuffersSize string `json:"busy-buffers-size"`
-	// Sets the maximum allowed size of the client request body. The default is set in the client-max-body-size ConfigMap key.
+	// Sets the maximum size of the temporary file for buffering a response from the upstream server. The 0 value disables the buffering of responses to temporary files. The default is set in the proxy-max-temp-file-size ConfigMap key.
+	ProxyMaxTempFileSize string `json:"max-temp-file-size,omitempty"`
+	// Sets the maximum allowed size of the client request body. The default is set in the client-max-body-size ConfigMap key. The 0 value, which disables the checking of the size, requires allow-unlimited-body-size, otherwise the default is used.
 	ClientMaxBodySize string `json:"client-max-body-size"`
+	// Allows the 0 value of client-max-body-size, which accepts the client request bodies of unlimited size. The default is false.
+	AllowUnlimitedBodySize bool `json:"allow-unlimited-body-size,omitempty"`
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:validation:Pattern=`^\d+[kKmM]?$`
 	// ClientBodyBufferSize sets the size of the buffer used for reading the client request body. Must be specified as a number followed by:
 	// 'k' for kilobytes or 'm' for megabytes.
 	// Examples: "10m" or "512k".
+	// A request body larger than the buffer is written to a temporary file. Sizes above client-max-body-size have no effect, as such request bodies are rejected.
 	ClientBodyBufferSize string `json:"client-body-buffer-size"`
 	// The TLS configuration for the Upstream.
 	TLS UpstreamTLS `json:"tls"`
@@ -178,12 +272,20 @@ type Upstream struct {
 	UseClusterIP bool `json:"use-cluster-ip"`
 	// Allows proxying requests with NTLM Authentication. In order for NTLM authentication to work, it is necessary to enable keepalive connections to upstream servers using the keepalive field. Note: this feature is supported only in NGINX Plus.
 	NTLM bool `json:"ntlm"`
+	// Keeps the endpoints of the terminating pods of the service that are still serving in the upstream marked down, so that NGINX stops sending them traffic while they are still shown in the upstream. The endpoints are removed once the pods finish terminating. If drain is enabled, the endpoints are kept in the draining mode instead. The default is false. Note: this feature is not applied to upstreams with a subselector.
+	KeepTerminatingEndpoints bool `json:"keep-terminating-endpoints"`
+	// Keeps the endpoints of the terminating pods of the service in the upstream in the draining mode, so that the requests bound to them by session persistence can still be completed. The endpoints are removed once the pods finish terminating. The default is false. Note: this feature is supported only in NGINX Plus and is not applied to upstreams with a subselector.
+	Drain bool `json:"drain"`
+	// The interval of re-resolving the hostname of the ExternalName service of the upstream, for example, 5s. Overrides the valid time of the resolver configured in the ConfigMap for the upstream. Note: this feature is supported only in NGINX Plus and requires the resolver-addresses ConfigMap key.
+	ResolveInterval string `json:"resolve-interval,omitempty"`
 	// The type of the upstream. Supported values are http and grpc. The default is http. For gRPC, it is necessary to enable HTTP/2 in the ConfigMap and configure TLS termination in the VirtualServer.
 	Type string `json:"type"`
 	// The name of the backup service of type ExternalName. This will be used when the primary servers are unavailable. Note: The parameter cannot be used along with the random, hash or ip_hash load balancing methods.
 	Backup string `json:"backup"`
 	// The port of the backup service. The backup port is required if the backup service name is provided. The port must fall into the range 1..65535.
 	BackupPort *uint16 `json:"backupPort"`
+	// Configures the failover from the servers of the upstream to the servers of the backup service. Requires the backup service. Every hostname of the backup service gets one try, even if it resolves to several addresses. The failover sets the next-upstream-tries and next-upstream-timeout of the upstream, so they cannot be used together with it. Note: this feature is supported only in NGINX Plus.
+	Failover *UpstreamFailover `json:"failover,omitempty"`
 }
 
 // UpstreamBuffers defines Buffer Configuration for an Upstream.
@@ -194,10 +296,22 @@ type UpstreamBuffers struct {
 	Size string `json:"size"`
 }
 
+// UpstreamWebSocket defines the proxying of WebSocket connections to an Upstream.
+type UpstreamWebSocket struct {
+	// Enables the proxying of WebSocket connections to the upstream servers. The read timeout of the upstream is increased so that idle WebSocket connections are not closed. The Upgrade and Connection request headers are always passed to the upstream servers. The default is false.
+	Enable bool `json:"enable"`
+	// The timeout for reading from an upstream server. A WebSocket connection is closed if the upstream server does not transmit anything within this time. Overrides the read-timeout of the upstream. The default is 1h.
+	ReadTimeout string `json:"read-timeout"`
+}
+
 // UpstreamTLS defines a TLS configuration for an Upstream.
 type UpstreamTLS struct {
 	// Enables HTTPS for requests to upstream servers. The default is False , meaning that HTTP will be used. Note: by default, NGINX will not verify the upstream server certificate. To enable the verification, configure an EgressMTLS Policy.
 	Enable bool `json:"enable"`
+	// Enables the reuse of TLS sessions when connecting to upstream servers. The default is true. Disable it for upstream servers that rotate their session ticket keys and fail to resume the sessions. Not supported in health checks. Ignored in the routes with an EgressMTLS policy, which configures the session reuse.
+	SessionReuse *bool `json:"sessionReuse,omitempty"`
+	// The ports that serve TLS, for example, 8443, for the services that serve plaintext and TLS on different ports. When set, HTTPS is used only when the port of the upstream is one of these ports, and HTTP otherwise. The backup servers use the same protocol as the port of the upstream. Requires enable.
+	Ports []uint16 `json:"ports,omitempty"`
 }
 
 // HealthCheck defines the parameters for active Upstream HealthChecks.
@@ -224,13 +338,15 @@ type HealthCheck struct {
 	ReadTimeout string `json:"read-timeout"`
 	// The timeout for transmitting a request to an upstream server. By default, the send-timeout of the upstream is used.
 	SendTimeout string `json:"send-timeout"`
-	// The request headers used for health check requests. NGINX Plus always sets the Host, User-Agent and Connection headers for health check requests.
+	// The request headers used for health check requests. NGINX Plus always sets the Host, User-Agent and Connection headers for health check requests. The values can contain the variables ${host}, ${server_name}, ${hostname}, ${proxy_host}, ${nginx_version}, ${pid}, ${msec} and ${time_iso8601}. The values cannot reference Secrets, as they are written to the NGINX configuration in plain text.
 	Headers []Header `json:"headers"`
 	// The expected response status codes of a health check. By default, the response should have status code 2xx or 3xx. Examples: "200", "! 500", "301-303 307". This not supported for gRPC type upstreams.
 	StatusMatch string `json:"statusMatch"`
+	// The name of a health check match defined in the healthCheckMatches of the VirtualServer. Cannot be used together with statusMatch. This not supported for gRPC type upstreams.
+	Match string `json:"match,omitempty"`
 	// The expected gRPC status code of the upstream server response to the Check method. Configure this field only if your gRPC services do not implement the gRPC health checking protocol. For example, configure 12 if the upstream server responds with 12 (UNIMPLEMENTED) status code. Only valid on gRPC type upstreams.
 	GRPCStatus *int `json:"grpcStatus"`
-	// The gRPC service to be monitored on the upstream server. Only valid on gRPC type upstreams.
+	// The gRPC service to be monitored on the upstream server. Only valid on gRPC type upstreams. If not set and grpcStatus is not set, the service set by the health-check-grpc-service ConfigMap key is used. By default, the service is not set.
 	GRPCService string `json:"grpcService"`
 	// Require every newly added server to pass all configured health checks before NGINX Plus sends traffic to it. If this is not specified, or is set to false, the server will be initially considered healthy. When combined with slow-start, it gives a new server more time to connect to databases and “warm up” before being asked to handle their full share of traffic.
 	Mandatory bool `json:"mandatory"`
@@ -238,6 +354,46 @@ type HealthCheck struct {
 	Persistent bool `json:"persistent"`
 	// Enables keepalive connections for health checks and specifies the time during which requests can be processed through one keepalive connection. The default is 60s.
 	KeepaliveTime string `json:"keepalive-time"`
+	// Disables keepalive connections for health checks, so that the connection of every probe is closed. Use it for upstream servers that leak the connections of probes. Can't be used together with keepalive-time. The default is false.
+	DisableKeepalive bool `json:"disable-keepalive,omitempty"`
+}
+
+// HealthCheckMatch defines a named health check match shared by the health checks of upstreams.
+type HealthCheckMatch struct {
+	// The name of the match. Must be unique among the health check matches of the VirtualServer.
+	Name string `json:"name"`
+	// The expected response status codes of a health check. Examples: "200", "! 500", "301-303 307".
+	Status string `json:"status"`
+}
+
+// TimeoutProfile defines a named set of timeouts shared by upstreams.
+type TimeoutProfile struct {
+	// The name of the profile. Must be unique among the timeout profiles of the VirtualServer.
+	Name string `json:"name"`
+	// The timeout for establishing a connection with an upstream server. The default is specified in the proxy-connect-timeout ConfigMap key.
+	ConnectTimeout string `json:"connect-timeout,omitempty"`
+	// The timeout for reading a response from an upstream server. The default is specified in the proxy-read-timeout ConfigMap key.
+	ReadTimeout string `json:"read-timeout,omitempty"`
+	// The timeout for transmitting a request to an upstream server. The default is specified in the proxy-send-timeout ConfigMap key.
+	SendTimeout string `json:"send-timeout,omitempty"`
+}
+
+// Geo defines a variable whose value is selected by the client IP address of a request, for example, the region of the client.
+type Geo struct {
+	// The name of the geo. Must be unique among the geos of the VirtualServer.
+	Name string `json:"name"`
+	// The value of the geo for the requests from the client IP addresses that are not in any of the ranges. The default is an empty value.
+	Default string `json:"default,omitempty"`
+	// A list of client IP address ranges and the values of the geo for them.
+	Ranges []GeoRange `json:"ranges"`
+}
+
+// GeoRange defines the value of a geo for a range of client IP addresses.
+type GeoRange struct {
+	// The client IP address or range in CIDR notation, for example, 192.168.1.1 or 10.0.0.0/8.
+	CIDR string `json:"cidr"`
+	// The value of the geo for the requests from the range, for example, eu-west. Must consist of alphanumeric characters, -, _ or .
+	Value string `json:"value"`
 }
 
 // Header defines an HTTP Header.
@@ -268,6 +424,22 @@ type SessionCookie struct {
 	SameSite string `json:"samesite"`
 }
 
+// SplitStickyCookie defines a cookie used to keep a client on the same split.
+type SplitStickyCookie struct {
+	// The name of the cookie.
+	Name string `json:"name"`
+	// The path for which the cookie is set. The default is /.
+	Path string `json:"path"`
+	// The time (in seconds) for which a browser should keep the cookie. When the cookie expires, the client is assigned to a split again. By default, the cookie is kept until the browser session ends.
+	TTL *int `json:"ttl,omitempty"`
+}
+
+// SplitWeights defines a key of the split_weights keyval zone with the weights of a two-way split.
+type SplitWeights struct {
+	// The key in the split_weights keyval zone, which is shared by all VirtualServers and VirtualServerRoutes and is updated through the NGINX Plus API. The value of the key is the weight of the first split in percent, from 0 to 100, and the second split receives the rest of the traffic. The weights of the splits are used while the key is not set or has an invalid value.
+	Key string `json:"key"`
+}
+
 // Route defines a route.
 type Route struct {
 	// The path of the route. NGINX will match it against the URI of a request. Possible values are: a prefix ( / , /path ), a longest prefix match ( ^~/images/ ), an exact match ( =/exact/match ), a case-insensitive regular expression ( ~*^/Bar.*\.jpg ) or a case-sensitive regular expression ( ~^/foo.*\.jpg ). In the case of a prefix match (must start with / ), a longest prefix match (must start with ^~ ) or an exact match (must start with = ), the path must not include any whitespace characters, { , } or ;. In the case of the regex matches, all double quotes " must be escaped and the match can’t end in an unescaped backslash \. The path must be unique among the paths of all routes of the VirtualServer. Check the location directive for more information.
@@ -282,10 +454,20 @@ type Route struct {
 	Action *Action `json:"action"`
 	// The default splits configuration for traffic splitting. Must include at least 2 splits.
 	Splits []Split `json:"splits"`
+	// Enables session persistence for the splits of the route. Traffic is split based on the value of the cookie, which NGINX sets if it is not present in the request.
+	StickyCookie *SplitStickyCookie `json:"stickyCookie"`
+	// Reads the weights of the two default splits of the route from a shared key at request time, so that the traffic of many routes can be shifted in one place. Requires the -weight-changes-dynamic-reload command-line argument. Note: this feature is supported only in NGINX Plus.
+	SplitWeights *SplitWeights `json:"splitWeights,omitempty"`
 	// The matching rules for advanced content-based routing. Requires the default Action or Splits. Unmatched requests will be handled by the default Action or Splits.
 	Matches []Match `json:"matches"`
 	// The custom responses for error codes. NGINX will use those responses instead of returning the error responses from the upstream servers or the default responses generated by NGINX. A custom response can be a redirect or a canned response. For example, a redirect to another URL if an upstream server responded with a 404 status code.
 	ErrorPages []ErrorPage `json:"errorPages"`
+	// A list of status codes that are passed to the client as they are, even if the error pages of the route, including the error pages inherited from the VirtualServer, include them. For example, to return the original 502, 503 and 504 responses of the upstream servers.
+	PassthroughErrorCodes []int `json:"passthroughErrorCodes,omitempty"`
+	// Uses the error pages of the route of the VirtualServer that references the VirtualServerRoute when the subroute doesn't define its own error pages. Set to false for the subroute to use no error pages. Supported in the subroutes of VirtualServerRoutes only. The default is true.
+	InheritErrorPages *bool `json:"inheritErrorPages,omitempty"`
+	// The custom responses for the gRPC statuses that NGINX returns for the failed requests to gRPC upstreams, for example, UNAVAILABLE when the upstream servers are unavailable. The gRPC statuses that the upstream servers return in the trailers of their responses are passed to the clients as they are. Not supported for routes that reference VirtualServerRoutes.
+	GRPCErrorResponses []GRPCErrorResponse `json:"grpcErrorResponses,omitempty"`
 	// Sets a custom snippet in the location context. Overrides the location-snippets ConfigMap key.
 	LocationSnippets string `json:"location-snippets"`
 	// Controls header inheritance behavior at the location level. Allowed values are: on, off, merge. When set to "merge", headers from this context are merged with headers in child contexts. When set to "on", standard NGINX inheritance applies. When set to "off", no headers are inherited from parent contexts.
@@ -293,6 +475,66 @@ type Route struct {
 	AddHeaderInherit string `json:"add-header-inherit"`
 	// A reference to a DosProtectedResource, setting this enables DOS protection of the VirtualServer route.
 	Dos string `json:"dos"`
+	// Deliberately delays the responses to the requests that match the conditions of the tarpit.
+	Tarpit *Tarpit `json:"tarpit,omitempty"`
+	// Returns a response with the configured status code to the requests from the listed client IP addresses before any other processing of the requests. The requests from other client IP addresses are handled by the route as usual.
+	ClientIPReturn *ClientIPReturn `json:"clientIPReturn,omitempty"`
+	// The name of the status zone that collects the metrics of the requests handled by the route. The value auto derives the name from the host of the VirtualServer and the path of the route. By default, the metrics are collected in the status zone named after the service of the route. Supported in NGINX Plus only.
+	StatusZone string `json:"statusZone,omitempty"`
+	// Configures the OpenTelemetry tracing of the requests handled by the route, which overrides the tracing of the VirtualServer. The spans of the route are tagged with the path of the route.
+	Tracing *Tracing `json:"tracing,omitempty"`
+	// A list of rewrites of the URI of the requests handled by the route. The rewrites are applied in order before the action of the route. Not supported for routes with matches or splits, or with return or redirect actions.
+	Rewrites []Rewrite `json:"rewrites,omitempty"`
+	// Passes the Host header of the client request to the upstream servers as it is ($http_host) instead of the host of the request ($host), which is the default. For example, for the upstream servers that expect the port or the original case of the Host header. A Host header set to another value in the requestHeaders of the proxy action is not changed.
+	PreserveHostHeader bool `json:"preserveHostHeader,omitempty"`
+}
+
+// GRPCErrorResponse defines a custom response for a gRPC status that NGINX returns for the failed requests to gRPC upstreams.
+type GRPCErrorResponse struct {
+	// The gRPC status that NGINX returns for the failed requests: DEADLINE_EXCEEDED for 408, PERMISSION_DENIED for 403, RESOURCE_EXHAUSTED for 413 and 414, UNIMPLEMENTED for 404, INTERNAL for 400, 405, 415, 426, 497, 500 and 501, UNAVAILABLE for 429, 502, 503 and 504, or UNAUTHENTICATED for 401, 495 and 496.
+	// +kubebuilder:validation:Enum=DEADLINE_EXCEEDED;PERMISSION_DENIED;RESOURCE_EXHAUSTED;UNIMPLEMENTED;INTERNAL;UNAVAILABLE;UNAUTHENTICATED
+	Status string `json:"status"`
+	// The custom response.
+	Return GRPCErrorReturn `json:"return"`
+}
+
+// GRPCErrorReturn defines a custom gRPC response.
+type GRPCErrorReturn struct {
+	// The gRPC status of the response, for example, RESOURCE_EXHAUSTED. Allowed values are the names of the gRPC statuses other than OK. The default is the status of the GRPCErrorResponse.
+	Status string `json:"status,omitempty"`
+	// The message of the response, returned in the grpc-message header. The default is the name of the status of the response in lowercase.
+	Message string `json:"message,omitempty"`
+	// The headers of the response.
+	Headers []Header `json:"headers,omitempty"`
+}
+
+// ClientIPReturn defines a return for the requests from a list of client IP addresses.
+type ClientIPReturn struct {
+	// The client IP addresses or ranges in CIDR notation, for example, 192.168.1.1 or 10.0.0.0/8.
+	Ranges []string `json:"ranges"`
+	// The status code of the response. The allowed values are: 2XX, 4XX or 5XX. The default is 403.
+	Code int `json:"code,omitempty"`
+}
+
+// Rewrite defines a rewrite of the URI of the requests of a route.
+type Rewrite struct {
+	// The regular expression that the URI of a request is matched against.
+	Regex string `json:"regex"`
+	// The replacement of the URI of a request that matches the regex. The replacement can reference the captures of the regex, for example, $1. A replacement that starts with http:// or https:// redirects the client.
+	Replacement string `json:"replacement"`
+	// The flag of the rewrite: last starts a search for the route of the new URI, break uses the new URI in the route, redirect returns a temporary redirect with the 302 code and permanent returns a permanent redirect with the 301 code. If not set, the next rewrite is applied.
+	// +kubebuilder:validation:Enum=last;break;redirect;permanent
+	Flag string `json:"flag,omitempty"`
+}
+
+// Tarpit defines a deliberate delay of the responses for suspected abusive requests.
+type Tarpit struct {
+	// The list of conditions. All conditions must be satisfied for the response to be delayed.
+	Conditions []Condition `json:"conditions"`
+	// The time to delay the response for, for example, 5s or 500ms. Must not exceed 60s.
+	Delay string `json:"delay"`
+	// The status code of the response returned after the delay. The allowed values are: 2XX, 4XX or 5XX. The default is 429.
+	Code int `json:"code,omitempty"`
 }
 
 // Action defines an action.
@@ -305,6 +547,42 @@ type Action struct {
 	Return *ActionReturn `json:"return"`
 	// Passes requests to an upstream with the ability to modify the request/response (for example, rewrite the URI or modify the headers).
 	Proxy *ActionProxy `json:"proxy"`
+	// Passes requests to a literal URL without the need for a Service. For example, https://example.com. The URL must not include a path, query or fragment. Cluster-internal addresses are rejected unless allowed with the -allow-internal-proxy-pass-url command-line argument.
+	ProxyPassURL string `json:"proxyPassURL,omitempty"`
+	// Serves static files from a directory mounted into the NGINX Ingress Controller pods. For example, it can be used to host a single-page application with a fallback to /index.html.
+	Files *ActionFiles `json:"files,omitempty"`
+	// Sets the expires directive which adds the Expires and Cache-Control response headers. The allowed values are a time, for example, 30d, or one of max, epoch or off. Applies to the pass, proxy and proxyPassURL actions.
+	Expires string `json:"expires,omitempty"`
+	// Replaces strings in the bodies of the responses, for example, the internal URLs of a legacy application with the public host. Applies to the pass, proxy and proxyPassURL actions.
+	SubFilter *ActionSubFilter `json:"subFilter,omitempty"`
+}
+
+// ActionSubFilter defines the replacing of strings in the response bodies in an Action.
+type ActionSubFilter struct {
+	// The strings to replace. The strings are matched case-insensitively.
+	Rules []SubFilterRule `json:"rules"`
+	// Replaces only the first occurrence of each string. The default is false, which replaces all occurrences.
+	Once bool `json:"once,omitempty"`
+	// The MIME types of the responses, in addition to text/html, in which the strings are replaced, for example, text/css. The * value matches any type.
+	Types []string `json:"types,omitempty"`
+}
+
+// SubFilterRule defines a string to replace in the response bodies.
+type SubFilterRule struct {
+	// The string to replace.
+	From string `json:"from"`
+	// The replacement string.
+	To string `json:"to"`
+}
+
+// ActionFiles defines the serving of static files in an Action.
+type ActionFiles struct {
+	// The directory from which the files are served. Must be an absolute path to one of the directories set by the -files-action-roots command-line argument or their subdirectory, for example, /usr/share/nginx/html. The files action is not allowed if the command-line argument is not set.
+	Root string `json:"root"`
+	// The files to check, in the specified order, relative to the root. A file must start with $uri or /, for example, $uri, $uri/ or /default.html. The first file that exists is served. The default is $uri.
+	TryFiles []string `json:"tryFiles,omitempty"`
+	// The URI to serve when none of the files exists, for example, /index.html, or a status code to return, for example, =404. The default is =404.
+	Fallback string `json:"fallback,omitempty"`
 }
 
 // ActionRedirect defines a redirect in an Action.
@@ -317,14 +595,24 @@ type ActionRedirect struct {
 
 // ActionReturn defines a return in an Action.
 type ActionReturn struct {
-	// The status code of the response. The allowed values are: 2XX, 4XX or 5XX. The default is 200.
+	// The status code of the response. The allowed values are: 2XX, 304, 4XX or 5XX. The default is 200. The responses with the 204 and 304 status codes have no body and type.
 	Code int `json:"code"`
 	// The MIME type of the response. The default is text/plain.
 	Type string `json:"type"`
-	// The body of the response. Supports NGINX variables*. Variables must be enclosed in curly brackets. For example: Request is ${request_uri}\n.
+	// The body of the response. Supports NGINX variables*. Variables must be enclosed in curly brackets. For example: Request is ${request_uri}\n. Required unless the code is 204 or 304.
 	Body string `json:"body"`
 	// The custom headers of the response.
 	Headers []Header `json:"headers"`
+	// The bodies of the response for different content types. The first body, in the order of the list, whose type is in the Accept header of the request is returned, or the first body if none of the types are. The q-values of the Accept header are ignored, except that the types with q=0 are not selected. Cannot be used together with type and body. Supported in the return action and the return of noEndpoints only, not in the returns of error pages.
+	Bodies []ReturnBody `json:"bodies,omitempty"`
+}
+
+// ReturnBody defines a body of a return for a content type.
+type ReturnBody struct {
+	// The MIME type of the body. For example, application/json.
+	Type string `json:"type"`
+	// The body of the response. Supports NGINX variables*. Variables must be enclosed in curly brackets.
+	Body string `json:"body"`
 }
 
 // ActionProxy defines a proxy in an Action.
@@ -337,6 +625,13 @@ type ActionProxy struct {
 	RequestHeaders *ProxyRequestHeaders `json:"requestHeaders"`
 	// The response headers modifications.
 	ResponseHeaders *ProxyResponseHeaders `json:"responseHeaders"`
+	// The HTTP method used for the requests passed to the upstream instead of the method of the client request. Allowed values are GET, HEAD, POST, PUT, DELETE, OPTIONS, TRACE and PATCH. Note: the request body of the client request is passed to the upstream unchanged, unless passRequestBody is false.
+	ProxyMethod string `json:"method,omitempty"`
+	// Enables or disables passing the request body of the client request to the upstream. When disabled, the Content-Length request header is cleared, unless it is set in requestHeaders. Default is true.
+	// +kubebuilder:validation:Optional
+	ProxyPassRequestBody *bool `json:"passRequestBody,omitempty"`
+	// The timeout for reading a response from the upstream server, which overrides the read-timeout of the upstream for the requests of the action. For example, a longer timeout for the long polling requests selected by a match. The default is the read-timeout of the upstream.
+	ReadTimeout string `json:"readTimeout,omitempty"`
 }
 
 // ProxyRequestHeaders defines the request headers manipulation in an ActionProxy.
@@ -357,6 +652,8 @@ type ProxyResponseHeaders struct {
 	Ignore []string `json:"ignore"`
 	// Adds headers to the response to the client.
 	Add []AddHeader `json:"add"`
+	// Hides the headers of the default-proxy-hide-headers ConfigMap key in addition to the headers of the hide field. The default is true.
+	HideDefaults *bool `json:"hideDefaults,omitempty"`
 }
 
 // AddHeader defines an HTTP Header with an optional Always field to use with the add_header NGINX directive.
@@ -372,6 +669,8 @@ type Split struct {
 	Weight int `json:"weight"`
 	// The action to perform for a request.
 	Action *Action `json:"action"`
+	// Sends all requests to the action of the split, regardless of the weights of the splits. Only one split can be promoted. When the dynamic weight changes reload is enabled, promoting a split of a two-way split does not reload NGINX.
+	Promoted bool `json:"promoted,omitempty"`
 }
 
 // Condition defines a condition in a MatchRule.
@@ -384,6 +683,8 @@ type Condition struct {
 	Argument string `json:"argument"`
 	// The name of an NGINX variable. Must start with $.
 	Variable string `json:"variable"`
+	// The name of a geo of the VirtualServer. The condition is matched against the value of the geo for the client IP address of the request.
+	Geo string `json:"geo,omitempty"`
 	// The value to match the condition against.
 	Value string `json:"value"`
 }
@@ -426,6 +727,18 @@ type TLS struct {
 	Redirect *TLSRedirect `json:"redirect"`
 	// The cert-manager configuration of the TLS for a VirtualServer.
 	CertManager *CertManager `json:"cert-manager"`
+	// The TLS profile of a VirtualServer that sets the SSL protocols and ciphers of the TLS listener.
+	Profile *TLSProfile `json:"profile,omitempty"`
+}
+
+// TLSProfile defines the SSL protocols and ciphers of a TLS.
+type TLSProfile struct {
+	// The name of a predefined profile. The allowed values are modern (TLSv1.3) and intermediate (TLSv1.2 and TLSv1.3 with the ciphers that support forward secrecy). If not set, the protocols of the profile must be set.
+	Name string `json:"name,omitempty"`
+	// The SSL protocols, for example, TLSv1.2 TLSv1.3. Overrides the protocols of the predefined profile. The allowed protocols are TLSv1, TLSv1.1, TLSv1.2 and TLSv1.3.
+	Protocols string `json:"protocols,omitempty"`
+	// The SSL ciphers in the OpenSSL format, for example, ECDHE-RSA-AES128-GCM-SHA256:ECDHE-RSA-AES256-GCM-SHA384. Overrides the ciphers of the predefined profile.
+	Ciphers string `json:"ciphers,omitempty"`
 }
 
 // TLSRedirect defines a redirect for a TLS.
@@ -516,6 +829,8 @@ type VirtualServerRouteSpec struct {
 	Upstreams []Upstream `json:"upstreams"`
 	// A list of subroutes.
 	Subroutes []Route `json:"subroutes"`
+	// The name of the status zone that collects the metrics of the requests handled by the subroutes that don't set their own statusZone, so that the metrics of the VirtualServerRoute can be told apart from the metrics of the VirtualServer. The value auto derives the name from the namespace and the name of the VirtualServerRoute, for example, tea/tea-vsr. Supported in NGINX Plus only.
+	StatusZone string `json:"statusZone,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -536,6 +851,14 @@ type UpstreamQueue struct {
 	Timeout string `json:"timeout"`
 }
 
+// UpstreamFailover defines the failover from the primary servers of an upstream to its backup servers.
+type UpstreamFailover struct {
+	// The number of tries of the primary servers before a request is passed to the backup servers. As NGINX passes a request to the backup servers only after all the available primary servers were tried, a number less than the number of primary servers prevents the failover unless some primary servers are unavailable. The default is the number of primary servers.
+	PrimaryTries *int `json:"primaryTries,omitempty"`
+	// The time during which a request can be passed to the next server, including the backup servers, for example, 10s. The 0 value turns off the time limit. The default is 0.
+	Timeout string `json:"timeout,omitempty"`
+}
+
 // VirtualServerRouteStatus defines the status for the VirtualServerRoute resource.
 type VirtualServerRouteStatus struct {
 	// Represents the current state of the resource. There are three possible values: Valid, Invalid and Warning. Valid indicates that the resource has been validated and accepted by the Ingress Controller. Invalid means the resource failed validation or NGINX
@@ -583,6 +906,18 @@ type Listener struct {
 	IPv6 string `json:"ipv6"`
 	// Whether the listener will be listening for SSL connections
 	Ssl bool `json:"ssl"`
+	// The options of the sockets of the listener. Only supported for HTTP listeners.
+	Options *ListenerOptions `json:"options,omitempty"`
+}
+
+// ListenerOptions defines the options of the sockets of a listener.
+type ListenerOptions struct {
+	// Defers accepting the connections until the client sends data.
+	Deferred bool `json:"deferred,omitempty"`
+	// Configures the TCP keepalive of the connections: on, off or keepidle:keepintvl:keepcnt, where the values can be omitted, for example, 30m::10. The default is the TCP keepalive of the operating system.
+	SoKeepalive string `json:"soKeepalive,omitempty"`
+	// The maximum length of the queue of the pending connections. The default is the default of NGINX for the operating system.
+	Backlog int `json:"backlog,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -718,6 +1053,8 @@ type UpstreamParameters struct {
 	NextUpstreamTimeout string `json:"nextUpstreamTimeout"`
 	// The number of tries for passing a connection to the next server. The default is 0.
 	NextUpstreamTries int `json:"nextUpstreamTries"`
+	// Enables the PROXY protocol for connections to the proxied server, for upstreams that expect the original client address in a PROXY protocol header. Not supported for UDP TransportServers. The default is false.
+	ProxyProtocol bool `json:"proxyProtocol"`
 }
 
 // SessionParameters defines session parameters.
@@ -829,6 +1166,9 @@ type PolicyList struct {
 type AccessControl struct {
 	Allow []string `json:"allow"`
 	Deny  []string `json:"deny"`
+	// Sets how the access control combines with the authentication policies applied in the same context. With any, a request from an allowed IP address bypasses authentication, while requests from other addresses must still authenticate. Requires allow. The default is all.
+	// +kubebuilder:validation:Enum=all;any
+	Satisfy string `json:"satisfy,omitempty"`
 }
 
 // RateLimit defines a rate limit policy.
@@ -839,12 +1179,18 @@ type RateLimit struct {
 	// Variables must be surrounded by ${}. For example: ${binary_remote_addr}. Accepted variables are
 	// $binary_remote_addr, $request_uri, $request_method, $url, $http_, $args, $arg_, $cookie_,$jwt_claim_ .
 	Key string `json:"key"`
+	// A list of keys that are concatenated into a composite key to which the rate limit is applied, for example, ${binary_remote_addr} and ${http_x_api_client}. Every key follows the same rules as key. Cannot be used together with key.
+	// +kubebuilder:validation:Optional
+	Keys []string `json:"keys,omitempty"`
 	// The delay parameter specifies a limit at which excessive requests become delayed. If not set all excessive requests are delayed.
 	Delay *int `json:"delay"`
 	// Disables the delaying of excessive requests while requests are being limited. Overrides delay if both are set.
 	NoDelay *bool `json:"noDelay"`
 	// Excessive requests are delayed until their number exceeds the burst size, in which case the request is terminated with an error.
 	Burst *int `json:"burst"`
+	// Sets the burst size as a multiple of the rate window. For example, a rate of 10r/s with a burstFactor of 2 results in a burst of 20. Cannot be used together with burst.
+	// +kubebuilder:validation:Optional
+	BurstFactor *int `json:"burstFactor,omitempty"`
 	// Size of the shared memory zone. Only positive values are allowed. Allowed suffixes are k or m, if none are present k is assumed.
 	ZoneSize string `json:"zoneSize"`
 	// Enables the dry run mode. In this mode, the rate limit is not actually applied, but the number of excessive requests is accounted as usual in the shared memory zone.
@@ -855,6 +1201,12 @@ type RateLimit struct {
 	RejectCode *int `json:"rejectCode"`
 	// Enables a constant rate-limit by dividing the configured rate by the number of nginx-ingress pods currently serving traffic. This adjustment ensures that the rate-limit remains consistent, even as the number of nginx-pods fluctuates due to autoscaling. This will not work properly if requests from a client are not evenly distributed across all ingress pods (Such as with sticky sessions, long lived TCP Connections with many requests, and so forth). In such cases using zone-sync instead would give better results. Enabling zone-sync will suppress this setting.
 	Scale bool `json:"scale"`
+	// Shares the zone of the rate limit among all VirtualServers that reference the policy, so that one limit is enforced for the requests to all of them. By default, every VirtualServer gets its own zone. Cannot be used together with condition or scale.
+	// +kubebuilder:validation:Optional
+	Shared bool `json:"shared,omitempty"`
+	// Increases the size of the zone to the size recommended for the key, when the zoneSize is smaller. The recommended size is estimated for 8192 distinct keys, for example, clients, and only for the keys with variables other than $request_method.
+	// +kubebuilder:validation:Optional
+	AutoZoneSize bool `json:"autoZoneSize,omitempty"`
 	// Add a condition to a rate-limit policy.
 	// +kubebuilder:validation:Optional
 	Condition *RateLimitCondition `json:"condition"`
@@ -904,6 +1256,8 @@ type JWTAuth struct {
 	Secret string `json:"secret"`
 	// The token specifies a variable that contains the JSON Web Token. By default the JWT is passed in the Authorization header as a Bearer Token. JWT may be also passed as a cookie or a part of a query string, for example: $cookie_auth_token. Accepted variables are $http_, $arg_, $cookie_.
 	Token string `json:"token"`
+	// The name of the cookie that contains the JSON Web Token. For example, session. Cannot be used together with token.
+	TokenCookie string `json:"tokenCookie"`
 	// The remote URI where the request will be sent to retrieve JSON Web Key set
 	JwksURI string `json:"jwksURI"`
 	// Enables in-memory caching of JWKS (JSON Web Key Sets) that are obtained from the jwksURI and sets a valid time for expiration.
@@ -922,6 +1276,15 @@ type JWTAuth struct {
 	// +kubebuilder:validation:Minimum=0
 	// +kubebuilder:default:=1
 	SSLVerifyDepth *int `json:"sslVerifyDepth"`
+	// The list of audiences of the JWT. The token is rejected with the 401 status code if its aud claim does not contain any of them. Requires NGINX Plus.
+	// +kubebuilder:validation:Optional
+	Audiences []string `json:"audiences,omitempty"`
+	// The issuer of the JWT. The token is rejected with the 401 status code if its iss claim does not match it. Requires NGINX Plus.
+	// +kubebuilder:validation:Optional
+	Issuer string `json:"issuer,omitempty"`
+	// The addresses of the DNS servers used to resolve the host of the jwksURI, for example, a local forwarder that sends the queries to the upstream DNS servers over TLS. An address may include a port. By default, the resolver configured with the resolver-addresses ConfigMap key is used. Requires NGINX Plus.
+	// +kubebuilder:validation:Optional
+	Resolvers []string `json:"resolvers,omitempty"`
 }
 
 // BasicAuth holds HTTP Basic authentication configuration
@@ -930,6 +1293,16 @@ type BasicAuth struct {
 	Realm string `json:"realm"`
 	// The name of the Kubernetes secret that stores the Htpasswd configuration. It must be in the same namespace as the Policy resource. The secret must be of the type nginx.org/htpasswd, and the config must be stored in the secret under the key htpasswd, otherwise the secret will be rejected as invalid.
 	Secret string `json:"secret"`
+	// A list of users that can authenticate, specified instead of the secret. Intended for a small number of users; for more users, use the secret.
+	Users []BasicAuthUser `json:"users,omitempty"`
+}
+
+// BasicAuthUser defines a user of the basic authentication.
+type BasicAuthUser struct {
+	// The name of the user. It must not contain a colon.
+	Name string `json:"name"`
+	// The hash of the password of the user in a format supported by NGINX: $apr1$, $1$, $5$, $6$, {SHA} or {SSHA}. For example, the output of openssl passwd -apr1.
+	Hash string `json:"hash"`
 }
 
 // The IngressMTLS policy configures client certificate verification.
@@ -938,6 +1311,8 @@ type IngressMTLS struct {
 	ClientCertSecret string `json:"clientCertSecret"`
 	// The file name of the Certificate Revocation List. NGINX Ingress Controller will look for this file in /etc/nginx/secrets
 	CrlFileName string `json:"crlFileName"`
+	// The file names of the Certificate Revocation Lists. NGINX Ingress Controller will look for these files in /etc/nginx/secrets and combine them into a single file, because NGINX supports only one CRL file. Cannot be used together with crlFileName.
+	CrlFileNames []string `json:"crlFileNames,omitempty"`
 	// Verification for the client. Possible values are "on", "off", "optional", "optional_no_ca". The default is "on".
 	VerifyClient string `json:"verifyClient"`
 	// Sets the verification depth in the client certificates chain. The default is 1.
@@ -962,7 +1337,7 @@ type EgressMTLS struct {
 	TrustedCertSecret string `json:"trustedCertSecret"`
 	// Enables passing of the server name through Server Name Indication extension.
 	ServerName bool `json:"serverName"`
-	// Allows overriding the server name used to verify the certificate of the upstream HTTPS server.
+	// Allows overriding the server name used to verify the certificate of the upstream HTTPS server. Can be a variable of the request, for example ${http_x_tenant}, to choose the server name per request, in which case the server name is also passed through Server Name Indication extension. Accepted variables are $host, $ssl_server_name, $http_, $arg_ and $cookie_.
 	SSLName string `json:"sslName"`
 }
 
@@ -1004,6 +1379,9 @@ type OIDC struct {
 	// +kubebuilder:validation:Minimum=0
 	// +kubebuilder:default:=1
 	SSLVerifyDepth *int `json:"sslVerifyDepth"`
+	// The addresses of the DNS servers used to resolve the hosts of the IDP endpoints, for example, a local forwarder that sends the queries to the upstream DNS servers over TLS. An address may include a port. By default, the resolver configured with the resolver-addresses ConfigMap key is used.
+	// +kubebuilder:validation:Optional
+	Resolvers []string `json:"resolvers,omitempty"`
 }
 
 // BundleSourceType specifies the remote source backend for a WAF bundle.
@@ -1138,6 +1516,9 @@ type APIKey struct {
 	SuppliedIn *SuppliedIn `json:"suppliedIn"`
 	// The key to which the API key is applied. Can contain text, variables, or a combination of them. Accepted variables are $http_, $arg_, $cookie_.
 	ClientSecret string `json:"clientSecret"`
+	// Sets the status code to return in response to requests with a missing or invalid API Key. Must fall into the range 400..499. By default, 401 is returned for a missing API Key and 403 for an invalid one.
+	// +kubebuilder:validation:Optional
+	RejectCode *int `json:"rejectCode,omitempty"`
 }
 
 // SuppliedIn defines the locations API Key should be supplied in.
@@ -1233,6 +1614,7 @@ type Cache struct {
 	// GET and HEAD are always cached by default even if not specified.
 	// Maximum of 3 items allowed. Examples: ["GET"], ["GET", "HEAD", "POST"].
 	// Invalid methods: PUT, DELETE, PATCH, etc.
+	// When POST is allowed, the cacheKey should include $request_body, so that POST requests with different bodies do not share the cached responses.
 	AllowedMethods []string `json:"allowedMethods,omitempty"`
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:validation:Pattern=`^[0-9]+[smhd]$`
@@ -1296,7 +1678,7 @@ type Cache struct {
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:validation:MaxItems=11
 	// CacheUseStale determines in which cases a stale cached response can be used (proxy_cache_use_stale).
-	// Valid parameters: error, timeout, invalid_header, updating, http_500, http_502, http_503, http_504, http_403, http_404, http_429, off.
+	// Valid parameters: error, timeout, invalid_header, updating, http_500, http_502, http_503, http_504, http_403, http_404, http_429, off. The off parameter cannot be combined with other parameters.
 	CacheUseStale []string `json:"cacheUseStale,omitempty"`
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:default=false
@@ -19,6 +19,11 @@ func (in *APIKey) DeepCopyInto(out *APIKey) {
 		*out = new(SuppliedIn)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.RejectCode != nil {
+		in, out := &in.RejectCode, &out.RejectCode
+		*out = new(int)
+		**out = **in
+	}
 	return
 }
 
@@ -76,6 +81,16 @@ func (in *Action) DeepCopyInto(out *Action) {
 		*out = new(ActionProxy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Files != nil {
+		in, out := &in.Files, &out.Files
+		*out = new(ActionFiles)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SubFilter != nil {
+		in, out := &in.SubFilter, &out.SubFilter
+		*out = new(ActionSubFilter)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -89,6 +104,27 @@ func (in *Action) DeepCopy() *Action {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActionFiles) DeepCopyInto(out *ActionFiles) {
+	*out = *in
+	if in.TryFiles != nil {
+		in, out := &in.TryFiles, &out.TryFiles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActionFiles.
+func (in *ActionFiles) DeepCopy() *ActionFiles {
+	if in == nil {
+		return nil
+	}
+	out := new(ActionFiles)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ActionProxy) DeepCopyInto(out *ActionProxy) {
 	*out = *in
@@ -102,6 +138,11 @@ func (in *ActionProxy) DeepCopyInto(out *ActionProxy) {
 		*out = new(ProxyResponseHeaders)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ProxyPassRequestBody != nil {
+		in, out := &in.ProxyPassRequestBody, &out.ProxyPassRequestBody
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
@@ -139,6 +180,11 @@ func (in *ActionReturn) DeepCopyInto(out *ActionReturn) {
 		*out = make([]Header, len(*in))
 		copy(*out, *in)
 	}
+	if in.Bodies != nil {
+		in, out := &in.Bodies, &out.Bodies
+		*out = make([]ReturnBody, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -152,6 +198,32 @@ func (in *ActionReturn) DeepCopy() *ActionReturn {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActionSubFilter) DeepCopyInto(out *ActionSubFilter) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]SubFilterRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.Types != nil {
+		in, out := &in.Types, &out.Types
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActionSubFilter.
+func (in *ActionSubFilter) DeepCopy() *ActionSubFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(ActionSubFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AddHeader) DeepCopyInto(out *AddHeader) {
 	*out = *in
@@ -172,6 +244,11 @@ func (in *AddHeader) DeepCopy() *AddHeader {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BasicAuth) DeepCopyInto(out *BasicAuth) {
 	*out = *in
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]BasicAuthUser, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -185,6 +262,22 @@ func (in *BasicAuth) DeepCopy() *BasicAuth {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BasicAuthUser) DeepCopyInto(out *BasicAuthUser) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BasicAuthUser.
+func (in *BasicAuthUser) DeepCopy() *BasicAuthUser {
+	if in == nil {
+		return nil
+	}
+	out := new(BasicAuthUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BundleSource) DeepCopyInto(out *BundleSource) {
 	*out = *in
@@ -397,6 +490,27 @@ func (in *CertManager) DeepCopy() *CertManager {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientIPReturn) DeepCopyInto(out *ClientIPReturn) {
+	*out = *in
+	if in.Ranges != nil {
+		in, out := &in.Ranges, &out.Ranges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientIPReturn.
+func (in *ClientIPReturn) DeepCopy() *ClientIPReturn {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientIPReturn)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Condition) DeepCopyInto(out *Condition) {
 	*out = *in
@@ -574,6 +688,81 @@ func (in *ExternalEndpoint) DeepCopy() *ExternalEndpoint {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GRPCErrorResponse) DeepCopyInto(out *GRPCErrorResponse) {
+	*out = *in
+	in.Return.DeepCopyInto(&out.Return)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GRPCErrorResponse.
+func (in *GRPCErrorResponse) DeepCopy() *GRPCErrorResponse {
+	if in == nil {
+		return nil
+	}
+	out := new(GRPCErrorResponse)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GRPCErrorReturn) DeepCopyInto(out *GRPCErrorReturn) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]Header, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GRPCErrorReturn.
+func (in *GRPCErrorReturn) DeepCopy() *GRPCErrorReturn {
+	if in == nil {
+		return nil
+	}
+	out := new(GRPCErrorReturn)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Geo) DeepCopyInto(out *Geo) {
+	*out = *in
+	if in.Ranges != nil {
+		in, out := &in.Ranges, &out.Ranges
+		*out = make([]GeoRange, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Geo.
+func (in *Geo) DeepCopy() *Geo {
+	if in == nil {
+		return nil
+	}
+	out := new(Geo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GeoRange) DeepCopyInto(out *GeoRange) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GeoRange.
+func (in *GeoRange) DeepCopy() *GeoRange {
+	if in == nil {
+		return nil
+	}
+	out := new(GeoRange)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GlobalConfiguration) DeepCopyInto(out *GlobalConfiguration) {
 	*out = *in
@@ -640,7 +829,9 @@ func (in *GlobalConfigurationSpec) DeepCopyInto(out *GlobalConfigurationSpec) {
 	if in.Listeners != nil {
 		in, out := &in.Listeners, &out.Listeners
 		*out = make([]Listener, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	return
 }
@@ -698,7 +889,7 @@ func (in *HealthCheck) DeepCopyInto(out *HealthCheck) {
 	if in.TLS != nil {
 		in, out := &in.TLS, &out.TLS
 		*out = new(UpstreamTLS)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Headers != nil {
 		in, out := &in.Headers, &out.Headers
@@ -723,9 +914,30 @@ func (in *HealthCheck) DeepCopy() *HealthCheck {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthCheckMatch) DeepCopyInto(out *HealthCheckMatch) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthCheckMatch.
+func (in *HealthCheckMatch) DeepCopy() *HealthCheckMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthCheckMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IngressMTLS) DeepCopyInto(out *IngressMTLS) {
 	*out = *in
+	if in.CrlFileNames != nil {
+		in, out := &in.CrlFileNames, &out.CrlFileNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.VerifyDepth != nil {
 		in, out := &in.VerifyDepth, &out.VerifyDepth
 		*out = new(int)
@@ -752,6 +964,16 @@ func (in *JWTAuth) DeepCopyInto(out *JWTAuth) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.Audiences != nil {
+		in, out := &in.Audiences, &out.Audiences
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Resolvers != nil {
+		in, out := &in.Resolvers, &out.Resolvers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -784,6 +1006,11 @@ func (in *JWTCondition) DeepCopy() *JWTCondition {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Listener) DeepCopyInto(out *Listener) {
 	*out = *in
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = new(ListenerOptions)
+		**out = **in
+	}
 	return
 }
 
@@ -797,6 +1024,22 @@ func (in *Listener) DeepCopy() *Listener {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ListenerOptions) DeepCopyInto(out *ListenerOptions) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ListenerOptions.
+func (in *ListenerOptions) DeepCopy() *ListenerOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(ListenerOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Match) DeepCopyInto(out *Match) {
 	*out = *in
@@ -830,6 +1073,32 @@ func (in *Match) DeepCopy() *Match {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NoEndpoints) DeepCopyInto(out *NoEndpoints) {
+	*out = *in
+	if in.RetryAfter != nil {
+		in, out := &in.RetryAfter, &out.RetryAfter
+		*out = new(int)
+		**out = **in
+	}
+	if in.Return != nil {
+		in, out := &in.Return, &out.Return
+		*out = new(ActionReturn)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NoEndpoints.
+func (in *NoEndpoints) DeepCopy() *NoEndpoints {
+	if in == nil {
+		return nil
+	}
+	out := new(NoEndpoints)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OIDC) DeepCopyInto(out *OIDC) {
 	*out = *in
@@ -848,6 +1117,11 @@ func (in *OIDC) DeepCopyInto(out *OIDC) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.Resolvers != nil {
+		in, out := &in.Resolvers, &out.Resolvers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -959,7 +1233,7 @@ func (in *PolicySpec) DeepCopyInto(out *PolicySpec) {
 	if in.BasicAuth != nil {
 		in, out := &in.BasicAuth, &out.BasicAuth
 		*out = new(BasicAuth)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.IngressMTLS != nil {
 		in, out := &in.IngressMTLS, &out.IngressMTLS
@@ -1120,6 +1394,11 @@ func (in *ProxyResponseHeaders) DeepCopyInto(out *ProxyResponseHeaders) {
 		*out = make([]AddHeader, len(*in))
 		copy(*out, *in)
 	}
+	if in.HideDefaults != nil {
+		in, out := &in.HideDefaults, &out.HideDefaults
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
@@ -1136,6 +1415,11 @@ func (in *ProxyResponseHeaders) DeepCopy() *ProxyResponseHeaders {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RateLimit) DeepCopyInto(out *RateLimit) {
 	*out = *in
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Delay != nil {
 		in, out := &in.Delay, &out.Delay
 		*out = new(int)
@@ -1151,6 +1435,11 @@ func (in *RateLimit) DeepCopyInto(out *RateLimit) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.BurstFactor != nil {
+		in, out := &in.BurstFactor, &out.BurstFactor
+		*out = new(int)
+		**out = **in
+	}
 	if in.DryRun != nil {
 		in, out := &in.DryRun, &out.DryRun
 		*out = new(bool)
@@ -1209,6 +1498,75 @@ func (in *RateLimitCondition) DeepCopy() *RateLimitCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RequestID) DeepCopyInto(out *RequestID) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequestID.
+func (in *RequestID) DeepCopy() *RequestID {
+	if in == nil {
+		return nil
+	}
+	out := new(RequestID)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResponseHeaders) DeepCopyInto(out *ResponseHeaders) {
+	*out = *in
+	if in.Add != nil {
+		in, out := &in.Add, &out.Add
+		*out = make([]AddHeader, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResponseHeaders.
+func (in *ResponseHeaders) DeepCopy() *ResponseHeaders {
+	if in == nil {
+		return nil
+	}
+	out := new(ResponseHeaders)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReturnBody) DeepCopyInto(out *ReturnBody) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReturnBody.
+func (in *ReturnBody) DeepCopy() *ReturnBody {
+	if in == nil {
+		return nil
+	}
+	out := new(ReturnBody)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Rewrite) DeepCopyInto(out *Rewrite) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Rewrite.
+func (in *Rewrite) DeepCopy() *Rewrite {
+	if in == nil {
+		return nil
+	}
+	out := new(Rewrite)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Route) DeepCopyInto(out *Route) {
 	*out = *in
@@ -1234,6 +1592,16 @@ func (in *Route) DeepCopyInto(out *Route) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.StickyCookie != nil {
+		in, out := &in.StickyCookie, &out.StickyCookie
+		*out = new(SplitStickyCookie)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SplitWeights != nil {
+		in, out := &in.SplitWeights, &out.SplitWeights
+		*out = new(SplitWeights)
+		**out = **in
+	}
 	if in.Matches != nil {
 		in, out := &in.Matches, &out.Matches
 		*out = make([]Match, len(*in))
@@ -1248,6 +1616,43 @@ func (in *Route) DeepCopyInto(out *Route) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.PassthroughErrorCodes != nil {
+		in, out := &in.PassthroughErrorCodes, &out.PassthroughErrorCodes
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	if in.InheritErrorPages != nil {
+		in, out := &in.InheritErrorPages, &out.InheritErrorPages
+		*out = new(bool)
+		**out = **in
+	}
+	if in.GRPCErrorResponses != nil {
+		in, out := &in.GRPCErrorResponses, &out.GRPCErrorResponses
+		*out = make([]GRPCErrorResponse, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Tarpit != nil {
+		in, out := &in.Tarpit, &out.Tarpit
+		*out = new(Tarpit)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClientIPReturn != nil {
+		in, out := &in.ClientIPReturn, &out.ClientIPReturn
+		*out = new(ClientIPReturn)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tracing != nil {
+		in, out := &in.Tracing, &out.Tracing
+		*out = new(Tracing)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rewrites != nil {
+		in, out := &in.Rewrites, &out.Rewrites
+		*out = make([]Rewrite, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -1335,6 +1740,80 @@ func (in *Split) DeepCopy() *Split {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SplitStickyCookie) DeepCopyInto(out *SplitStickyCookie) {
+	*out = *in
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SplitStickyCookie.
+func (in *SplitStickyCookie) DeepCopy() *SplitStickyCookie {
+	if in == nil {
+		return nil
+	}
+	out := new(SplitStickyCookie)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SplitWeights) DeepCopyInto(out *SplitWeights) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SplitWeights.
+func (in *SplitWeights) DeepCopy() *SplitWeights {
+	if in == nil {
+		return nil
+	}
+	out := new(SplitWeights)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StrictHost) DeepCopyInto(out *StrictHost) {
+	*out = *in
+	if in.Code != nil {
+		in, out := &in.Code, &out.Code
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StrictHost.
+func (in *StrictHost) DeepCopy() *StrictHost {
+	if in == nil {
+		return nil
+	}
+	out := new(StrictHost)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubFilterRule) DeepCopyInto(out *SubFilterRule) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubFilterRule.
+func (in *SubFilterRule) DeepCopy() *SubFilterRule {
+	if in == nil {
+		return nil
+	}
+	out := new(SubFilterRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SuppliedIn) DeepCopyInto(out *SuppliedIn) {
 	*out = *in
@@ -1374,6 +1853,11 @@ func (in *TLS) DeepCopyInto(out *TLS) {
 		*out = new(CertManager)
 		**out = **in
 	}
+	if in.Profile != nil {
+		in, out := &in.Profile, &out.Profile
+		*out = new(TLSProfile)
+		**out = **in
+	}
 	return
 }
 
@@ -1387,6 +1871,22 @@ func (in *TLS) DeepCopy() *TLS {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSProfile) DeepCopyInto(out *TLSProfile) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSProfile.
+func (in *TLSProfile) DeepCopy() *TLSProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TLSRedirect) DeepCopyInto(out *TLSRedirect) {
 	*out = *in
@@ -1408,6 +1908,64 @@ func (in *TLSRedirect) DeepCopy() *TLSRedirect {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Tarpit) DeepCopyInto(out *Tarpit) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Tarpit.
+func (in *Tarpit) DeepCopy() *Tarpit {
+	if in == nil {
+		return nil
+	}
+	out := new(Tarpit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimeoutProfile) DeepCopyInto(out *TimeoutProfile) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimeoutProfile.
+func (in *TimeoutProfile) DeepCopy() *TimeoutProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(TimeoutProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Tracing) DeepCopyInto(out *Tracing) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Tracing.
+func (in *Tracing) DeepCopy() *Tracing {
+	if in == nil {
+		return nil
+	}
+	out := new(Tracing)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TransportServer) DeepCopyInto(out *TransportServer) {
 	*out = *in
@@ -1675,6 +2233,21 @@ func (in *Upstream) DeepCopyInto(out *Upstream) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.ProxySocketKeepalive != nil {
+		in, out := &in.ProxySocketKeepalive, &out.ProxySocketKeepalive
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ProxyIgnoreClientAbort != nil {
+		in, out := &in.ProxyIgnoreClientAbort, &out.ProxyIgnoreClientAbort
+		*out = new(bool)
+		**out = **in
+	}
+	if in.WebSocket != nil {
+		in, out := &in.WebSocket, &out.WebSocket
+		*out = new(UpstreamWebSocket)
+		**out = **in
+	}
 	if in.ProxyBuffering != nil {
 		in, out := &in.ProxyBuffering, &out.ProxyBuffering
 		*out = new(bool)
@@ -1685,7 +2258,7 @@ func (in *Upstream) DeepCopyInto(out *Upstream) {
 		*out = new(UpstreamBuffers)
 		**out = **in
 	}
-	out.TLS = in.TLS
+	in.TLS.DeepCopyInto(&out.TLS)
 	if in.HealthCheck != nil {
 		in, out := &in.HealthCheck, &out.HealthCheck
 		*out = new(HealthCheck)
@@ -1706,6 +2279,11 @@ func (in *Upstream) DeepCopyInto(out *Upstream) {
 		*out = new(uint16)
 		**out = **in
 	}
+	if in.Failover != nil {
+		in, out := &in.Failover, &out.Failover
+		*out = new(UpstreamFailover)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1761,6 +2339,27 @@ func (in *UpstreamParameters) DeepCopy() *UpstreamParameters {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpstreamFailover) DeepCopyInto(out *UpstreamFailover) {
+	*out = *in
+	if in.PrimaryTries != nil {
+		in, out := &in.PrimaryTries, &out.PrimaryTries
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpstreamFailover.
+func (in *UpstreamFailover) DeepCopy() *UpstreamFailover {
+	if in == nil {
+		return nil
+	}
+	out := new(UpstreamFailover)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UpstreamQueue) DeepCopyInto(out *UpstreamQueue) {
 	*out = *in
@@ -1780,6 +2379,16 @@ func (in *UpstreamQueue) DeepCopy() *UpstreamQueue {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UpstreamTLS) DeepCopyInto(out *UpstreamTLS) {
 	*out = *in
+	if in.SessionReuse != nil {
+		in, out := &in.SessionReuse, &out.SessionReuse
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]uint16, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -1793,6 +2402,22 @@ func (in *UpstreamTLS) DeepCopy() *UpstreamTLS {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpstreamWebSocket) DeepCopyInto(out *UpstreamWebSocket) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpstreamWebSocket.
+func (in *UpstreamWebSocket) DeepCopy() *UpstreamWebSocket {
+	if in == nil {
+		return nil
+	}
+	out := new(UpstreamWebSocket)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VariableCondition) DeepCopyInto(out *VariableCondition) {
 	*out = *in
@@ -1873,6 +2498,11 @@ func (in *VirtualServerList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualServerListener) DeepCopyInto(out *VirtualServerListener) {
 	*out = *in
+	if in.Additional != nil {
+		in, out := &in.Additional, &out.Additional
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -2004,13 +2634,58 @@ func (in *VirtualServerSpec) DeepCopyInto(out *VirtualServerSpec) {
 	if in.Listener != nil {
 		in, out := &in.Listener, &out.Listener
 		*out = new(VirtualServerListener)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.TLS != nil {
 		in, out := &in.TLS, &out.TLS
 		*out = new(TLS)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.HTTP2 != nil {
+		in, out := &in.HTTP2, &out.HTTP2
+		*out = new(bool)
+		**out = **in
+	}
+	if in.HTTP3 != nil {
+		in, out := &in.HTTP3, &out.HTTP3
+		*out = new(bool)
+		**out = **in
+	}
+	if in.UnderscoresInHeaders != nil {
+		in, out := &in.UnderscoresInHeaders, &out.UnderscoresInHeaders
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MergeSlashes != nil {
+		in, out := &in.MergeSlashes, &out.MergeSlashes
+		*out = new(bool)
+		**out = **in
+	}
+	if in.StrictHost != nil {
+		in, out := &in.StrictHost, &out.StrictHost
+		*out = new(StrictHost)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NoEndpoints != nil {
+		in, out := &in.NoEndpoints, &out.NoEndpoints
+		*out = new(NoEndpoints)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RequestID != nil {
+		in, out := &in.RequestID, &out.RequestID
+		*out = new(RequestID)
+		**out = **in
+	}
+	if in.ResponseHeaders != nil {
+		in, out := &in.ResponseHeaders, &out.ResponseHeaders
+		*out = new(ResponseHeaders)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tracing != nil {
+		in, out := &in.Tracing, &out.Tracing
+		*out = new(Tracing)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Policies != nil {
 		in, out := &in.Policies, &out.Policies
 		*out = make([]PolicyReference, len(*in))
@@ -2023,6 +2698,23 @@ func (in *VirtualServerSpec) DeepCopyInto(out *VirtualServerSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.HealthCheckMatches != nil {
+		in, out := &in.HealthCheckMatches, &out.HealthCheckMatches
+		*out = make([]HealthCheckMatch, len(*in))
+		copy(*out, *in)
+	}
+	if in.TimeoutProfiles != nil {
+		in, out := &in.TimeoutProfiles, &out.TimeoutProfiles
+		*out = make([]TimeoutProfile, len(*in))
+		copy(*out, *in)
+	}
+	if in.Geos != nil {
+		in, out := &in.Geos, &out.Geos
+		*out = make([]Geo, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Routes != nil {
 		in, out := &in.Routes, &out.Routes
 		*out = make([]Route, len(*in))
@@ -18,6 +18,15 @@ const (
 
 var escapedStringsFmtRegexp = regexp.MustCompile("^" + escapedStringsFmt + "$")
 
+// nginxTokenFmt matches a value that can be used in the NGINX config without quoting or escaping
+// and without the evaluation of variables.
+const (
+	nginxTokenFmt    = `[^\s"'{};$\\]+`
+	nginxTokenErrMsg = "must not contain whitespace, quotes, curly braces, semicolons, dollar signs or backslashes"
+)
+
+var nginxTokenRegexp = regexp.MustCompile("^" + nginxTokenFmt + "$")
+
 // ValidateEscapedString validates an escaped string.
 func ValidateEscapedString(body string, examples ...string) error {
 	if !escapedStringsFmtRegexp.MatchString(body) {
@@ -96,6 +105,8 @@ func validateSpecialVariable(nVar string, fieldPath *field.Path, isPlus bool) fi
 		}
 	case "apikey":
 		addErrors(isValidSpecialHeaderLikeVariable(value))
+	case "vs":
+		addErrors(isArgumentName(value))
 	default:
 		allErrs = append(allErrs, field.Invalid(fieldPath, nVar, "unknown special variable"))
 	}
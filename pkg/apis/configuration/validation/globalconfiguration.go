@@ -2,6 +2,7 @@ package validation
 
 import (
 	"fmt"
+	"regexp"
 	"sort"
 	"strings"
 
@@ -150,6 +151,7 @@ func (gcv *GlobalConfigurationValidator) validateListener(listener conf_v1.Liste
 	allErrs = append(allErrs, validateListenerProtocol(listener.Protocol, fieldPath.Child("protocol"))...)
 	allErrs = append(allErrs, validateListenerIPv4(listener.IPv4, fieldPath.Child("ipv4"))...)
 	allErrs = append(allErrs, validateListenerIPv6(listener.IPv6, fieldPath.Child("ipv6"))...)
+	allErrs = append(allErrs, validateListenerOptions(listener.Options, listener.Protocol, fieldPath.Child("options"))...)
 
 	return allErrs
 }
@@ -209,6 +211,28 @@ func validateListenerIPv6(ipv6 string, fieldPath *field.Path) field.ErrorList {
 	return allErrors
 }
 
+// soKeepaliveRegexp matches the keepidle:keepintvl:keepcnt parameters of the so_keepalive parameter of the listen directive.
+var soKeepaliveRegexp = regexp.MustCompile(`^(\d+[smh]?)?:(\d+[smh]?)?:(\d+)?$`)
+
+func validateListenerOptions(options *conf_v1.ListenerOptions, protocol string, fieldPath *field.Path) field.ErrorList {
+	if options == nil {
+		return nil
+	}
+	if protocol != "HTTP" {
+		return field.ErrorList{field.Forbidden(fieldPath, "is only supported for HTTP listeners")}
+	}
+
+	allErrs := field.ErrorList{}
+	if options.Backlog < 0 {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("backlog"), options.Backlog, "must not be negative"))
+	}
+	if options.SoKeepalive != "" && options.SoKeepalive != "on" && options.SoKeepalive != "off" && !soKeepaliveRegexp.MatchString(options.SoKeepalive) {
+		msg := "must be on, off or keepidle:keepintvl:keepcnt, for example, 30m::10"
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("soKeepalive"), options.SoKeepalive, msg))
+	}
+	return allErrs
+}
+
 func getProtocolsFromMap(p map[string]bool) []string {
 	var keys []string
 
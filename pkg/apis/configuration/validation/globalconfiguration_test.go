@@ -423,6 +423,30 @@ func TestValidateListener(t *testing.T) {
 	}
 }
 
+func TestValidateListenerOptions(t *testing.T) {
+	t.Parallel()
+	tests := []*conf_v1.ListenerOptions{
+		nil,
+		{},
+		{
+			Deferred:    true,
+			SoKeepalive: "on",
+			Backlog:     1024,
+		},
+		{SoKeepalive: "off"},
+		{SoKeepalive: "30m::10"},
+		{SoKeepalive: "1h:30s:5"},
+		{SoKeepalive: "::"},
+	}
+
+	for _, test := range tests {
+		allErrs := validateListenerOptions(test, "HTTP", field.NewPath("options"))
+		if len(allErrs) > 0 {
+			t.Errorf("validateListenerOptions() returned errors %v for valid input %+v", allErrs, test)
+		}
+	}
+}
+
 func TestValidateListenerFails(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -461,6 +485,33 @@ func TestValidateListenerFails(t *testing.T) {
 			},
 			msg: "name of a built-in listener",
 		},
+		{
+			Listener: conf_v1.Listener{
+				Name:     "tcp-listener",
+				Port:     2201,
+				Protocol: "TCP",
+				Options:  &conf_v1.ListenerOptions{Deferred: true},
+			},
+			msg: "options of a TCP listener",
+		},
+		{
+			Listener: conf_v1.Listener{
+				Name:     "http-listener",
+				Port:     8080,
+				Protocol: "HTTP",
+				Options:  &conf_v1.ListenerOptions{Backlog: -1},
+			},
+			msg: "invalid backlog",
+		},
+		{
+			Listener: conf_v1.Listener{
+				Name:     "http-listener",
+				Port:     8080,
+				Protocol: "HTTP",
+				Options:  &conf_v1.ListenerOptions{SoKeepalive: "30m:10"},
+			},
+			msg: "invalid soKeepalive",
+		},
 	}
 
 	gcv := createGlobalConfigurationValidator()
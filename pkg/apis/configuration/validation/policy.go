@@ -230,13 +230,23 @@ func validateAccessControl(accessControl *v1.AccessControl, fieldPath *field.Pat
 		allErrs = append(allErrs, field.Invalid(fieldPath, "", "must specify exactly one of: `allow` or `deny`"))
 	}
 
+	switch accessControl.Satisfy {
+	case "", "all":
+	case "any":
+		if accessControl.Allow == nil {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("satisfy"), "`any` requires `allow`"))
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(fieldPath.Child("satisfy"), accessControl.Satisfy, []string{"all", "any"}))
+	}
+
 	return allErrs
 }
 
 func validateRateLimit(rateLimit *v1.RateLimit, fieldPath *field.Path, isPlus bool) field.ErrorList {
 	allErrs := validateRateLimitZoneSize(rateLimit.ZoneSize, fieldPath.Child("zoneSize"))
 	allErrs = append(allErrs, validateRate(rateLimit.Rate, fieldPath.Child("rate"))...)
-	allErrs = append(allErrs, validateRateLimitKey(rateLimit.Key, fieldPath.Child("key"), isPlus)...)
+	allErrs = append(allErrs, validateRateLimitKeys(rateLimit, fieldPath, isPlus)...)
 
 	if rateLimit.Delay != nil {
 		allErrs = append(allErrs, validatePositiveInt(*rateLimit.Delay, fieldPath.Child("delay"))...)
@@ -246,6 +256,13 @@ func validateRateLimit(rateLimit *v1.RateLimit, fieldPath *field.Path, isPlus bo
 		allErrs = append(allErrs, validatePositiveInt(*rateLimit.Burst, fieldPath.Child("burst"))...)
 	}
 
+	if rateLimit.BurstFactor != nil {
+		allErrs = append(allErrs, validatePositiveInt(*rateLimit.BurstFactor, fieldPath.Child("burstFactor"))...)
+		if rateLimit.Burst != nil {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("burstFactor"), "cannot be used together with burst"))
+		}
+	}
+
 	if rateLimit.LogLevel != "" {
 		allErrs = append(allErrs, validateRateLimitLogLevel(rateLimit.LogLevel, fieldPath.Child("logLevel"))...)
 	}
@@ -257,6 +274,14 @@ func validateRateLimit(rateLimit *v1.RateLimit, fieldPath *field.Path, isPlus bo
 		}
 	}
 
+	if rateLimit.Shared && rateLimit.Condition != nil {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("shared"), "cannot be used together with condition"))
+	}
+
+	if rateLimit.Shared && rateLimit.Scale {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("shared"), "cannot be used together with scale"))
+	}
+
 	if rateLimit.Condition != nil && (rateLimit.Condition.JWT == nil && rateLimit.Condition.Variables == nil) {
 		allErrs = append(allErrs, field.Required(fieldPath.Child("condition"), "must specify either jwt or variable conditions"))
 	}
@@ -283,6 +308,7 @@ func validateJWT(jwt *v1.JWTAuth, fieldPath *field.Path) field.ErrorList {
 		return field.ErrorList{field.Required(fieldPath.Child("realm"), "realm field must be present")}
 	}
 	allErrs := validateRealm(jwt.Realm, fieldPath.Child("realm"))
+	allErrs = append(allErrs, validateJWTClaims(jwt, fieldPath)...)
 
 	// Use either JWT Secret or JWKS URI, they are mutually exclusive.
 	if jwt.Secret == "" && jwt.JwksURI == "" {
@@ -295,16 +321,17 @@ func validateJWT(jwt *v1.JWTAuth, fieldPath *field.Path) field.ErrorList {
 	// Verify a case when using JWT Secret
 	if jwt.Secret != "" {
 		allErrs = append(allErrs, validateSecretName(jwt.Secret, fieldPath.Child("secret"))...)
-		// jwt.Token is not required field. Verify it when provided.
-		if jwt.Token != "" {
-			allErrs = append(allErrs, validateJWTToken(jwt.Token, fieldPath.Child("token"))...)
-		}
+		allErrs = append(allErrs, validateJWTTokenSource(jwt, fieldPath)...)
 
 		// keyCache must not be present when using Secret
 		if jwt.KeyCache != "" {
 			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("keyCache"), "key cache must not be used when using Secret"))
 		}
 
+		if len(jwt.Resolvers) > 0 {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("resolvers"), "resolvers can only be set when JwksURI is set"))
+		}
+
 		// If JwksURI is not set, then none of the SNI fields should be set.
 		if jwt.SNIEnabled {
 			return append(allErrs, field.Forbidden(fieldPath.Child("sniEnabled"), "sniEnabled can only be set when JwksURI is set"))
@@ -321,10 +348,8 @@ func validateJWT(jwt *v1.JWTAuth, fieldPath *field.Path) field.ErrorList {
 	if jwt.JwksURI != "" {
 		allErrs = append(allErrs, validateURL(jwt.JwksURI, fieldPath.Child("JwksURI"))...)
 		allErrs = append(allErrs, validateTime(jwt.KeyCache, fieldPath.Child("keyCache"))...)
-		// jwt.Token is not required field. Verify it if it's provided.
-		if jwt.Token != "" {
-			allErrs = append(allErrs, validateJWTToken(jwt.Token, fieldPath.Child("token"))...)
-		}
+		allErrs = append(allErrs, validateJWTTokenSource(jwt, fieldPath)...)
+		allErrs = append(allErrs, validateResolvers(jwt.Resolvers, fieldPath.Child("resolvers"))...)
 		// keyCache must be present when using JWKS
 		if jwt.KeyCache == "" {
 			allErrs = append(allErrs, field.Required(fieldPath.Child("keyCache"), "key cache must be set, example value: 1h"))
@@ -357,18 +382,111 @@ func validateJWT(jwt *v1.JWTAuth, fieldPath *field.Path) field.ErrorList {
 	return allErrs
 }
 
+// validateJWTClaims validates the expected audiences and issuer of the token.
+func validateJWTClaims(jwt *v1.JWTAuth, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	seen := make(map[string]bool)
+	for i, aud := range jwt.Audiences {
+		idxPath := fieldPath.Child("audiences").Index(i)
+		if aud == "" {
+			allErrs = append(allErrs, field.Required(idxPath, "must not be empty"))
+			continue
+		}
+		if !nginxTokenRegexp.MatchString(aud) {
+			allErrs = append(allErrs, field.Invalid(idxPath, aud, validation.RegexError(nginxTokenErrMsg, nginxTokenFmt, "my-api", "https://api.example.com")))
+			continue
+		}
+		if seen[aud] {
+			allErrs = append(allErrs, field.Duplicate(idxPath, aud))
+		}
+		seen[aud] = true
+	}
+
+	if jwt.Issuer != "" && !nginxTokenRegexp.MatchString(jwt.Issuer) {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("issuer"), jwt.Issuer, validation.RegexError(nginxTokenErrMsg, nginxTokenFmt, "https://issuer.example.com")))
+	}
+
+	return allErrs
+}
+
+// validateResolvers validates the addresses of the DNS servers used to resolve the hosts of an IdP.
+func validateResolvers(resolvers []string, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	seen := make(map[string]bool)
+	for i, r := range resolvers {
+		idxPath := fieldPath.Index(i)
+		if r == "" {
+			allErrs = append(allErrs, field.Required(idxPath, "must not be empty"))
+			continue
+		}
+		if err := validation2.ValidateHost(r); err != nil {
+			allErrs = append(allErrs, field.Invalid(idxPath, r, err.Error()))
+			continue
+		}
+		if seen[r] {
+			allErrs = append(allErrs, field.Duplicate(idxPath, r))
+		}
+		seen[r] = true
+	}
+
+	return allErrs
+}
+
 func validateBasic(basic *v1.BasicAuth, fieldPath *field.Path) field.ErrorList {
-	if basic.Secret == "" {
-		return field.ErrorList{field.Required(fieldPath.Child("secret"), "")}
+	if basic.Secret == "" && len(basic.Users) == 0 {
+		return field.ErrorList{field.Required(fieldPath.Child("secret"), "secret or users must be specified")}
+	}
+	if basic.Secret != "" && len(basic.Users) > 0 {
+		return field.ErrorList{field.Forbidden(fieldPath.Child("users"), "users cannot be specified together with secret")}
 	}
 
 	allErrs := field.ErrorList{}
 	if basic.Realm != "" {
 		allErrs = append(allErrs, validateRealm(basic.Realm, fieldPath.Child("realm"))...)
 	}
+	if len(basic.Users) > 0 {
+		return append(allErrs, validateBasicAuthUsers(basic.Users, fieldPath.Child("users"))...)
+	}
 	return append(allErrs, validateSecretName(basic.Secret, fieldPath.Child("secret"))...)
 }
 
+const basicAuthUserNameErrMsg = "must not be empty or contain a colon or whitespace"
+
+var (
+	basicAuthUserNameRegexp = regexp.MustCompile(`^[^:\s]+$`)
+	// basicAuthHashRegexp matches the password hashes supported by NGINX: the MD5-based ($apr1$ and $1$),
+	// SHA-256-based ($5$) and SHA-512-based ($6$) crypt hashes, and the {SHA} and {SSHA} hashes.
+	basicAuthHashRegexp = regexp.MustCompile(`^(\$(apr1|1)\$[./0-9A-Za-z]{1,8}\$[./0-9A-Za-z]{22}` +
+		`|\$5\$(rounds=[0-9]+\$)?[./0-9A-Za-z]{1,16}\$[./0-9A-Za-z]{43}` +
+		`|\$6\$(rounds=[0-9]+\$)?[./0-9A-Za-z]{1,16}\$[./0-9A-Za-z]{86}` +
+		`|\{SHA\}[+/0-9A-Za-z]{27}=` +
+		`|\{SSHA\}[+/0-9A-Za-z]{28,}={0,2})$`)
+)
+
+func validateBasicAuthUsers(users []v1.BasicAuthUser, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	seen := make(map[string]bool)
+
+	for i, u := range users {
+		idxPath := fieldPath.Index(i)
+		if !basicAuthUserNameRegexp.MatchString(u.Name) {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("name"), u.Name, basicAuthUserNameErrMsg))
+		} else if seen[u.Name] {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), u.Name))
+		}
+		seen[u.Name] = true
+
+		if !basicAuthHashRegexp.MatchString(u.Hash) {
+			// the hash is not included in the error to avoid exposing it
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("hash"), "", "must be a password hash in a format supported by NGINX: $apr1$, $1$, $5$, $6$, {SHA} or {SSHA}"))
+		}
+	}
+
+	return allErrs
+}
+
 func validateIngressMTLS(ingressMTLS *v1.IngressMTLS, fieldPath *field.Path) field.ErrorList {
 	if ingressMTLS.ClientCertSecret == "" {
 		return field.ErrorList{field.Required(fieldPath.Child("clientCertSecret"), "")}
@@ -378,6 +496,32 @@ func validateIngressMTLS(ingressMTLS *v1.IngressMTLS, fieldPath *field.Path) fie
 	if ingressMTLS.VerifyDepth != nil {
 		allErrs = append(allErrs, validatePositiveIntOrZero(*ingressMTLS.VerifyDepth, fieldPath.Child("verifyDepth"))...)
 	}
+	if len(ingressMTLS.CrlFileNames) > 0 {
+		allErrs = append(allErrs, validateIngressMTLSCrlFileNames(ingressMTLS, fieldPath.Child("crlFileNames"))...)
+	}
+	return allErrs
+}
+
+func validateIngressMTLSCrlFileNames(ingressMTLS *v1.IngressMTLS, fieldPath *field.Path) field.ErrorList {
+	if ingressMTLS.CrlFileName != "" {
+		return field.ErrorList{field.Forbidden(fieldPath, "cannot be used together with crlFileName")}
+	}
+
+	allErrs := field.ErrorList{}
+	fileNames := sets.Set[string]{}
+	for i, name := range ingressMTLS.CrlFileNames {
+		idxPath := fieldPath.Index(i)
+		switch {
+		case name == "":
+			allErrs = append(allErrs, field.Required(idxPath, ""))
+		case name == "." || name == ".." || strings.ContainsAny(name, "/\\"):
+			allErrs = append(allErrs, field.Invalid(idxPath, name, "must be a file name in /etc/nginx/secrets"))
+		case fileNames.Has(name):
+			allErrs = append(allErrs, field.Duplicate(idxPath, name))
+		default:
+			fileNames.Insert(name)
+		}
+	}
 	return allErrs
 }
 
@@ -392,7 +536,27 @@ func validateEgressMTLS(egressMTLS *v1.EgressMTLS, fieldPath *field.Path) field.
 	if egressMTLS.VerifyDepth != nil {
 		allErrs = append(allErrs, validatePositiveIntOrZero(*egressMTLS.VerifyDepth, fieldPath.Child("verifyDepth"))...)
 	}
-	return append(allErrs, validateSSLName(egressMTLS.SSLName, fieldPath.Child("sslName"))...)
+	return append(allErrs, validateEgressMTLSSSLName(egressMTLS.SSLName, fieldPath.Child("sslName"))...)
+}
+
+var (
+	egressMTLSSSLNameVariableRegexp   = regexp.MustCompile(`^\$\{[a-z0-9_]+\}$`)
+	egressMTLSSSLNameSpecialVariables = []string{"http_", "arg_", "cookie_"}
+	egressMTLSSSLNameVariables        = map[string]bool{"host": true, "ssl_server_name": true}
+)
+
+// validateEgressMTLSSSLName validates the server name of an EgressMTLS policy, which is either a host name
+// or a variable of the request, so that the server name can be chosen per request.
+func validateEgressMTLSSSLName(name string, fieldPath *field.Path) field.ErrorList {
+	if !strings.Contains(name, "$") {
+		return validateSSLName(name, fieldPath)
+	}
+
+	if !egressMTLSSSLNameVariableRegexp.MatchString(name) {
+		return field.ErrorList{field.Invalid(fieldPath, name, "must be a host name or a single variable, for example ${http_x_tenant}")}
+	}
+
+	return validateStringWithVariables(name, fieldPath, egressMTLSSSLNameSpecialVariables, egressMTLSSSLNameVariables, false)
 }
 
 func validateOIDC(oidc *v1.OIDC, fieldPath *field.Path) field.ErrorList {
@@ -435,6 +599,7 @@ func validateOIDC(oidc *v1.OIDC, fieldPath *field.Path) field.ErrorList {
 	if oidc.AuthExtraArgs != nil {
 		allErrs = append(allErrs, validateQueryString(strings.Join(oidc.AuthExtraArgs, "&"), fieldPath.Child("authExtraArgs"))...)
 	}
+	allErrs = append(allErrs, validateResolvers(oidc.Resolvers, fieldPath.Child("resolvers"))...)
 
 	allErrs = append(allErrs, validateURL(oidc.AuthEndpoint, fieldPath.Child("authEndpoint"))...)
 	allErrs = append(allErrs, validateURL(oidc.TokenEndpoint, fieldPath.Child("tokenEndpoint"))...)
@@ -483,6 +648,13 @@ func validateAPIKey(apiKey *v1.APIKey, fieldPath *field.Path) field.ErrorList {
 		allErrs = append(allErrs, validateSecretName(apiKey.ClientSecret, fieldPath.Child("clientSecret"))...)
 	}
 
+	if apiKey.RejectCode != nil {
+		if *apiKey.RejectCode < 400 || *apiKey.RejectCode > 499 {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("rejectCode"), apiKey.RejectCode,
+				"must be within the range [400-499]"))
+		}
+	}
+
 	return allErrs
 }
 
@@ -666,6 +838,7 @@ func validateCache(cache *v1.Cache, fieldPath *field.Path, isPlus bool) field.Er
 	allErrs := field.ErrorList{}
 
 	allErrs = append(allErrs, validateCacheAllowedCodes(cache, fieldPath)...)
+	allErrs = append(allErrs, validateCacheAllowedMethods(cache.AllowedMethods, fieldPath.Child("allowedMethods"))...)
 
 	// Validate NGINX Plus features
 	allErrs = append(allErrs, validateCachePlusFeatures(cache, fieldPath, isPlus)...)
@@ -750,6 +923,31 @@ func validateCache(cache *v1.Cache, fieldPath *field.Path, isPlus bool) field.Er
 	return allErrs
 }
 
+var validCacheAllowedMethods = map[string]bool{
+	"GET":  true,
+	"HEAD": true,
+	"POST": true,
+}
+
+// validateCacheAllowedMethods validates the methods against the methods supported by the proxy_cache_methods directive
+func validateCacheAllowedMethods(methods []string, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	seen := make(map[string]bool)
+	for i, m := range methods {
+		if !validCacheAllowedMethods[m] {
+			allErrs = append(allErrs, field.NotSupported(fieldPath.Index(i), m, []string{"GET", "HEAD", "POST"}))
+			continue
+		}
+		if seen[m] {
+			allErrs = append(allErrs, field.Duplicate(fieldPath.Index(i), m))
+		}
+		seen[m] = true
+	}
+
+	return allErrs
+}
+
 // validateCacheAllowedCodes validates the allowedCodes field
 func validateCacheAllowedCodes(cache *v1.Cache, fieldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
@@ -819,8 +1017,24 @@ func validateCachePlusFeatures(cache *v1.Cache, fieldPath *field.Path, isPlus bo
 	return allErrs
 }
 
+// validCacheUseStaleParams are the parameters of the proxy_cache_use_stale directive.
+// Unlike proxy_next_upstream, the directive accepts "updating" but not "non_idempotent".
+var validCacheUseStaleParams = map[string]bool{
+	"error":          true,
+	"timeout":        true,
+	"invalid_header": true,
+	"updating":       true,
+	"http_500":       true,
+	"http_502":       true,
+	"http_503":       true,
+	"http_504":       true,
+	"http_403":       true,
+	"http_404":       true,
+	"http_429":       true,
+	"off":            true,
+}
+
 // validateCacheUseStale validates the cacheUseStale field values
-// The directive's parameters match the parameters of the proxy_next_upstream directive plus "updating"
 func validateCacheUseStale(cache *v1.Cache, fieldPath *field.Path) field.ErrorList {
 	if len(cache.CacheUseStale) == 0 {
 		return nil
@@ -830,8 +1044,7 @@ func validateCacheUseStale(cache *v1.Cache, fieldPath *field.Path) field.ErrorLi
 	allParams := sets.Set[string]{}
 
 	for _, para := range cache.CacheUseStale {
-		// Check if parameter is valid (either from validNextUpstreamParams or "updating" which is specific to cache)
-		if !validNextUpstreamParams[para] && para != "updating" {
+		if !validCacheUseStaleParams[para] {
 			allErrs = append(allErrs, field.Invalid(fieldPath.Child("cacheUseStale"), para, "not a valid parameter"))
 		}
 		if allParams.Has(para) {
@@ -840,6 +1053,10 @@ func validateCacheUseStale(cache *v1.Cache, fieldPath *field.Path) field.ErrorLi
 			allParams.Insert(para)
 		}
 	}
+
+	if allParams.Has("off") && len(allParams) > 1 {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("cacheUseStale"), cache.CacheUseStale, "off can not be combined with other parameters"))
+	}
 	return allErrs
 }
 
@@ -1087,6 +1304,23 @@ var rateLimitKeyVariables = map[string]bool{
 	"request_method":     true,
 }
 
+// validateRateLimitKeys validates either the single key or the list of keys of the composite key.
+func validateRateLimitKeys(rateLimit *v1.RateLimit, fieldPath *field.Path, isPlus bool) field.ErrorList {
+	if len(rateLimit.Keys) == 0 {
+		return validateRateLimitKey(rateLimit.Key, fieldPath.Child("key"), isPlus)
+	}
+
+	if rateLimit.Key != "" {
+		return field.ErrorList{field.Forbidden(fieldPath.Child("keys"), "cannot be used together with key")}
+	}
+
+	allErrs := field.ErrorList{}
+	for i, key := range rateLimit.Keys {
+		allErrs = append(allErrs, validateRateLimitKey(key, fieldPath.Child("keys").Index(i), isPlus)...)
+	}
+	return allErrs
+}
+
 func validateRateLimitKey(key string, fieldPath *field.Path, isPlus bool) field.ErrorList {
 	if key == "" {
 		return field.ErrorList{field.Required(fieldPath, "")}
@@ -1100,6 +1334,23 @@ func validateRateLimitKey(key string, fieldPath *field.Path, isPlus bool) field.
 
 var jwtTokenSpecialVariables = []string{"arg_", "http_", "cookie_"}
 
+// validateJWTTokenSource validates the optional token and tokenCookie fields, which are mutually exclusive.
+func validateJWTTokenSource(jwt *v1.JWTAuth, fieldPath *field.Path) field.ErrorList {
+	if jwt.TokenCookie == "" {
+		return validateJWTToken(jwt.Token, fieldPath.Child("token"))
+	}
+
+	if jwt.Token != "" {
+		return field.ErrorList{field.Forbidden(fieldPath.Child("tokenCookie"), "cannot be used together with token")}
+	}
+
+	allErrs := field.ErrorList{}
+	for _, msg := range isCookieName(jwt.TokenCookie) {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("tokenCookie"), jwt.TokenCookie, msg))
+	}
+	return allErrs
+}
+
 func validateJWTToken(token string, fieldPath *field.Path) field.ErrorList {
 	if token == "" {
 		return nil
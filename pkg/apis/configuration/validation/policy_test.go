@@ -715,6 +715,14 @@ func TestValidateAccessControl_PassesOnValidInput(t *testing.T) {
 		{
 			Deny: []string{"127.0.0.1"},
 		},
+		{
+			Allow:   []string{"10.0.0.0/8"},
+			Satisfy: "any",
+		},
+		{
+			Deny:    []string{"127.0.0.1"},
+			Satisfy: "all",
+		},
 	}
 
 	for _, input := range validInput {
@@ -757,6 +765,20 @@ func TestValidateAccessControl_FailsOnInvalidInput(t *testing.T) {
 			},
 			msg: "invalid deny",
 		},
+		{
+			accessControl: &v1.AccessControl{
+				Deny:    []string{"127.0.0.1"},
+				Satisfy: "any",
+			},
+			msg: "satisfy any with deny",
+		},
+		{
+			accessControl: &v1.AccessControl{
+				Allow:   []string{"127.0.0.1"},
+				Satisfy: "some",
+			},
+			msg: "invalid satisfy",
+		},
 	}
 
 	for _, test := range tests {
@@ -814,6 +836,35 @@ func TestValidateRateLimit_PassesOnValidInput(t *testing.T) {
 			isPlus: true,
 			msg:    "ratelimit JWT Condition",
 		},
+		{
+			rateLimit: &v1.RateLimit{
+				Rate:        "10r/s",
+				Key:         "${request_uri}",
+				ZoneSize:    "10M",
+				BurstFactor: new(2),
+			},
+			isPlus: false,
+			msg:    "ratelimit burstFactor",
+		},
+		{
+			rateLimit: &v1.RateLimit{
+				Rate:     "10r/s",
+				Key:      "${binary_remote_addr}",
+				ZoneSize: "10M",
+				Shared:   true,
+			},
+			isPlus: false,
+			msg:    "ratelimit shared",
+		},
+		{
+			rateLimit: &v1.RateLimit{
+				Rate:     "10r/s",
+				Keys:     []string{"${binary_remote_addr}", "${http_x_api_client}"},
+				ZoneSize: "10M",
+			},
+			isPlus: false,
+			msg:    "ratelimit composite key",
+		},
 	}
 
 	for _, test := range tests {
@@ -869,6 +920,21 @@ func TestValidateRateLimit_FailsOnInvalidInput(t *testing.T) {
 			isPlus: false,
 			msg:    "invalid rateLimit burst",
 		},
+		{
+			rateLimit: createInvalidRateLimit(func(r *v1.RateLimit) {
+				r.BurstFactor = new(0)
+			}),
+			isPlus: false,
+			msg:    "invalid rateLimit burstFactor",
+		},
+		{
+			rateLimit: createInvalidRateLimit(func(r *v1.RateLimit) {
+				r.Burst = new(10)
+				r.BurstFactor = new(2)
+			}),
+			isPlus: false,
+			msg:    "both rateLimit burst and burstFactor set",
+		},
 		{
 			rateLimit: createInvalidRateLimit(func(r *v1.RateLimit) {
 				r.ZoneSize = "31k"
@@ -911,6 +977,50 @@ func TestValidateRateLimit_FailsOnInvalidInput(t *testing.T) {
 			isPlus: true,
 			msg:    "missing JWTCondition",
 		},
+		{
+			rateLimit: createInvalidRateLimit(func(r *v1.RateLimit) {
+				r.Shared = true
+				r.Condition = &v1.RateLimitCondition{
+					JWT: &v1.JWTCondition{
+						Claim: "sub",
+						Match: "Gold",
+					},
+				}
+			}),
+			isPlus: true,
+			msg:    "both rateLimit shared and condition set",
+		},
+		{
+			rateLimit: createInvalidRateLimit(func(r *v1.RateLimit) {
+				r.Shared = true
+				r.Scale = true
+			}),
+			isPlus: true,
+			msg:    "both rateLimit shared and scale set",
+		},
+		{
+			rateLimit: createInvalidRateLimit(func(r *v1.RateLimit) {
+				r.Keys = []string{"${binary_remote_addr}", "${http_x_api_client}"}
+			}),
+			isPlus: false,
+			msg:    "both rateLimit key and keys set",
+		},
+		{
+			rateLimit: createInvalidRateLimit(func(r *v1.RateLimit) {
+				r.Key = ""
+				r.Keys = []string{"${binary_remote_addr}", "${fail}"}
+			}),
+			isPlus: false,
+			msg:    "invalid rateLimit keys variable use",
+		},
+		{
+			rateLimit: createInvalidRateLimit(func(r *v1.RateLimit) {
+				r.Key = ""
+				r.Keys = []string{"${binary_remote_addr}", ""}
+			}),
+			isPlus: false,
+			msg:    "empty rateLimit keys item",
+		},
 	}
 
 	for _, test := range tests {
@@ -977,6 +1087,39 @@ func TestValidateJWT_PassesOnValidInput(t *testing.T) {
 			},
 			msg: "jwt with token",
 		},
+		{
+			jwt: &v1.JWTAuth{
+				Realm:  "My Product API",
+				Secret: "my-jwk",
+				Token:  "$http_x_auth_token",
+			},
+			msg: "jwt with token in header",
+		},
+		{
+			jwt: &v1.JWTAuth{
+				Realm:  "My Product API",
+				Secret: "my-jwk",
+				Token:  "$arg_token",
+			},
+			msg: "jwt with token in query",
+		},
+		{
+			jwt: &v1.JWTAuth{
+				Realm:       "My Product API",
+				Secret:      "my-jwk",
+				TokenCookie: "session",
+			},
+			msg: "jwt with token cookie",
+		},
+		{
+			jwt: &v1.JWTAuth{
+				Realm:       "My Product API",
+				TokenCookie: "session",
+				JwksURI:     "https://idp.com/token",
+				KeyCache:    "1h",
+			},
+			msg: "jwt with token cookie and jwksURI",
+		},
 		{
 			jwt: &v1.JWTAuth{
 				Realm:    "My Product API",
@@ -1007,6 +1150,24 @@ func TestValidateJWT_PassesOnValidInput(t *testing.T) {
 			},
 			msg: "SNI enabled and no server name passed",
 		},
+		{
+			jwt: &v1.JWTAuth{
+				Realm:     "My Product API",
+				Secret:    "my-jwk",
+				Audiences: []string{"my-api", "https://api.example.com"},
+				Issuer:    "https://idp.example.com/realms/main",
+			},
+			msg: "jwt with audiences and issuer",
+		},
+		{
+			jwt: &v1.JWTAuth{
+				Realm:     "My Product API",
+				JwksURI:   "https://idp.example.com:443/keys",
+				KeyCache:  "1h",
+				Resolvers: []string{"127.0.0.1:5353", "kube-dns.kube-system.svc.cluster.local"},
+			},
+			msg: "jwks with resolvers",
+		},
 	}
 	for _, test := range tests {
 		allErrs := validateJWT(test.jwt, field.NewPath("jwt"))
@@ -1050,6 +1211,32 @@ func TestValidateJWT_FailsOnInvalidInput(t *testing.T) {
 			},
 			msg: "invalid variable use in token",
 		},
+		{
+			jwt: &v1.JWTAuth{
+				Realm:       "My Product API",
+				Secret:      "my-jwk",
+				Token:       "$cookie_auth_token",
+				TokenCookie: "session",
+			},
+			msg: "both token and tokenCookie present",
+		},
+		{
+			jwt: &v1.JWTAuth{
+				Realm:       "My Product API",
+				Secret:      "my-jwk",
+				TokenCookie: "$session",
+			},
+			msg: "invalid tokenCookie name",
+		},
+		{
+			jwt: &v1.JWTAuth{
+				Realm:       "My Product API",
+				TokenCookie: "session-id",
+				JwksURI:     "https://idp.com/token",
+				KeyCache:    "1h",
+			},
+			msg: "invalid tokenCookie name with jwksURI",
+		},
 		{
 			jwt: &v1.JWTAuth{
 				Realm:  "My Product API",
@@ -1078,6 +1265,73 @@ func TestValidateJWT_FailsOnInvalidInput(t *testing.T) {
 			},
 			msg: "invalid variable use in realm without curly braces",
 		},
+		{
+			jwt: &v1.JWTAuth{
+				Realm:     "My Product API",
+				Secret:    "my-jwk",
+				Audiences: []string{""},
+			},
+			msg: "empty audience",
+		},
+		{
+			jwt: &v1.JWTAuth{
+				Realm:     "My Product API",
+				Secret:    "my-jwk",
+				Audiences: []string{"my-api", "my-api"},
+			},
+			msg: "duplicate audience",
+		},
+		{
+			jwt: &v1.JWTAuth{
+				Realm:     "My Product API",
+				Secret:    "my-jwk",
+				Audiences: []string{"my-api;"},
+			},
+			msg: "invalid audience",
+		},
+		{
+			jwt: &v1.JWTAuth{
+				Realm:  "My Product API",
+				Secret: "my-jwk",
+				Issuer: "https://idp.example.com/${realm}",
+			},
+			msg: "invalid issuer",
+		},
+		{
+			jwt: &v1.JWTAuth{
+				Realm:     "My Product API",
+				Secret:    "my-jwk",
+				Resolvers: []string{"127.0.0.1"},
+			},
+			msg: "resolvers with secret",
+		},
+		{
+			jwt: &v1.JWTAuth{
+				Realm:     "My Product API",
+				JwksURI:   "https://idp.example.com:443/keys",
+				KeyCache:  "1h",
+				Resolvers: []string{"127.0.0.1:99999"},
+			},
+			msg: "resolver with invalid port",
+		},
+		{
+			jwt: &v1.JWTAuth{
+				Realm:     "My Product API",
+				JwksURI:   "https://idp.example.com:443/keys",
+				KeyCache:  "1h",
+				Resolvers: []string{"127.0.0.1", "127.0.0.1"},
+			},
+			msg: "duplicate resolvers",
+		},
+		{
+			jwt: &v1.JWTAuth{
+				Realm:     "My Product API",
+				JwksURI:   "https://idp.example.com:443/keys",
+				KeyCache:  "1h",
+				Resolvers: []string{"127.0.0.1 valid=10s"},
+			},
+			msg: "resolver with parameters",
+		},
 		{
 			jwt: &v1.JWTAuth{
 				Realm:    "My Product api",
@@ -1406,6 +1660,13 @@ func TestValidateIngressMTLS_PassesOnValidInput(t *testing.T) {
 			},
 			msg: "optional parameters",
 		},
+		{
+			ing: &v1.IngressMTLS{
+				ClientCertSecret: "ingress-mtls-secret",
+				CrlFileNames:     []string{"root-ca.crl", "intermediate-ca.crl"},
+			},
+			msg: "multiple crl files",
+		},
 	}
 	for _, test := range tests {
 		allErrs := validateIngressMTLS(test.ing, field.NewPath("ingressMTLS"))
@@ -1448,6 +1709,35 @@ func TestValidateIngressMTLS_FailsOnInvalidInput(t *testing.T) {
 			},
 			msg: "invalid depth",
 		},
+		{
+			ing: &v1.IngressMTLS{
+				ClientCertSecret: "ingress-mtls-secret",
+				CrlFileName:      "ca.crl",
+				CrlFileNames:     []string{"root-ca.crl"},
+			},
+			msg: "crl file name and crl file names",
+		},
+		{
+			ing: &v1.IngressMTLS{
+				ClientCertSecret: "ingress-mtls-secret",
+				CrlFileNames:     []string{"root-ca.crl", ""},
+			},
+			msg: "empty crl file name",
+		},
+		{
+			ing: &v1.IngressMTLS{
+				ClientCertSecret: "ingress-mtls-secret",
+				CrlFileNames:     []string{"../root-ca.crl"},
+			},
+			msg: "crl file name with path",
+		},
+		{
+			ing: &v1.IngressMTLS{
+				ClientCertSecret: "ingress-mtls-secret",
+				CrlFileNames:     []string{"root-ca.crl", "root-ca.crl"},
+			},
+			msg: "duplicate crl file names",
+		},
 	}
 	for _, test := range tests {
 		allErrs := validateIngressMTLS(test.ing, field.NewPath("ingressMTLS"))
@@ -1514,6 +1804,18 @@ func TestValidateEgressMTLS_PassesOnValidInput(t *testing.T) {
 			},
 			msg: "ssl name",
 		},
+		{
+			eg: &v1.EgressMTLS{
+				SSLName: "${http_x_tenant}",
+			},
+			msg: "ssl name from a header",
+		},
+		{
+			eg: &v1.EgressMTLS{
+				SSLName: "${host}",
+			},
+			msg: "ssl name from the host",
+		},
 	}
 	for _, test := range tests {
 		allErrs := validateEgressMTLS(test.eg, field.NewPath("egressMTLS"))
@@ -1555,6 +1857,24 @@ func TestValidateEgressMTLS_FailsOnInvalidInput(t *testing.T) {
 			},
 			msg: "invalid name",
 		},
+		{
+			eg: &v1.EgressMTLS{
+				SSLName: "$http_x_tenant",
+			},
+			msg: "variable without curly braces",
+		},
+		{
+			eg: &v1.EgressMTLS{
+				SSLName: "${http_x_tenant}.example.com",
+			},
+			msg: "variable combined with text",
+		},
+		{
+			eg: &v1.EgressMTLS{
+				SSLName: "${request_uri}",
+			},
+			msg: "unsupported variable",
+		},
 	}
 
 	for _, test := range tests {
@@ -1588,6 +1908,17 @@ func TestValidateOIDC_PassesOnValidOIDC(t *testing.T) {
 			},
 			msg: "verify full oidc",
 		},
+		{
+			oidc: &v1.OIDC{
+				AuthEndpoint:  "https://accounts.google.com/o/oauth2/v2/auth",
+				TokenEndpoint: "https://oauth2.googleapis.com/token",
+				JWKSURI:       "https://www.googleapis.com/oauth2/v3/certs",
+				ClientID:      "random-string",
+				ClientSecret:  "random-secret",
+				Resolvers:     []string{"127.0.0.1:5353"},
+			},
+			msg: "verify oidc with resolvers",
+		},
 		{
 			oidc: &v1.OIDC{
 				AuthEndpoint:          "https://login.microsoftonline.com/dd-fff-eee-1234-9be/oauth2/v2.0/authorize",
@@ -1910,6 +2241,30 @@ func TestValidateOIDC_FailsOnInvalidOIDC(t *testing.T) {
 			fieldPath: "oidc.zoneSyncLeeway",
 			msg:       "invalid zoneSyncLeeway value",
 		},
+		{
+			oidc: &v1.OIDC{
+				AuthEndpoint:  "http://127.0.0.1:8080/realms/master/protocol/openid-connect/auth",
+				TokenEndpoint: "http://127.0.0.1:8080/realms/master/protocol/openid-connect/token",
+				JWKSURI:       "http://127.0.0.1:8080/realms/master/protocol/openid-connect/certs",
+				ClientID:      "foobar",
+				ClientSecret:  "secret",
+				Resolvers:     []string{""},
+			},
+			fieldPath: "oidc.resolvers[0]",
+			msg:       "empty resolver",
+		},
+		{
+			oidc: &v1.OIDC{
+				AuthEndpoint:  "http://127.0.0.1:8080/realms/master/protocol/openid-connect/auth",
+				TokenEndpoint: "http://127.0.0.1:8080/realms/master/protocol/openid-connect/token",
+				JWKSURI:       "http://127.0.0.1:8080/realms/master/protocol/openid-connect/certs",
+				ClientID:      "foobar",
+				ClientSecret:  "secret",
+				Resolvers:     []string{"127.0.0.1;"},
+			},
+			fieldPath: "oidc.resolvers[0]",
+			msg:       "invalid resolver",
+		},
 	}
 
 	for _, test := range tests {
@@ -1942,6 +2297,18 @@ func TestValidateAPIKeyPolicy_PassOnValidInput(t *testing.T) {
 				ClientSecret: "secret",
 			},
 		},
+		{
+			apiKey: &v1.APIKey{
+				SuppliedIn: &v1.SuppliedIn{
+					Header: []string{
+						"X-API-Key",
+					},
+				},
+				ClientSecret: "secret",
+				RejectCode:   new(429),
+			},
+			msg: "custom reject code",
+		},
 	}
 
 	for _, test := range tests {
@@ -2014,6 +2381,30 @@ func TestValidateAPIKeyPolicy_FailsOnInvalidInput(t *testing.T) {
 			},
 			msg: "no suppliedIn provided",
 		},
+		{
+			apiKey: &v1.APIKey{
+				SuppliedIn: &v1.SuppliedIn{
+					Header: []string{
+						"X-API-Key",
+					},
+				},
+				ClientSecret: "secret",
+				RejectCode:   new(503),
+			},
+			msg: "reject code not in 4xx range",
+		},
+		{
+			apiKey: &v1.APIKey{
+				SuppliedIn: &v1.SuppliedIn{
+					Header: []string{
+						"X-API-Key",
+					},
+				},
+				ClientSecret: "secret",
+				RejectCode:   new(399),
+			},
+			msg: "reject code below 4xx range",
+		},
 
 		{
 			apiKey: nil, msg: "no apikey provided",
@@ -2328,6 +2719,80 @@ func TestValidateBasic_FailsOnMissingSecret(t *testing.T) {
 	}
 }
 
+func TestValidateBasic_PassesOnValidUsers(t *testing.T) {
+	t.Parallel()
+
+	users := []v1.BasicAuthUser{
+		{Name: "apr1", Hash: "$apr1$abcdefgh$h9FWgUz3n9YxylKLlR5SQ/"},
+		{Name: "md5", Hash: "$1$abcdefgh$cHJi5PXp/ki/ktXzqlk6I1"},
+		{Name: "sha256", Hash: "$5$saltsalt$0IyaXrmV7.sGNS6tirgqHLqX/G.FBvgkYA.lpPdS5sA"},
+		{Name: "sha512", Hash: "$6$saltsalt$TVLlQcbpFVof5W3Yz4DTP6gRstiNuHwwTt6GLc1E5n0U0aDehy0S5knV8wiOQSpT0Y77vwPZN.Pq.H91p5hVO1"},
+		{Name: "sha", Hash: "{SHA}5en6G6MezRroT3XKqkdPOmY/BfQ="},
+		{Name: "ssha", Hash: "{SSHA}gVK8WC9YyFT1gMsQHTGCgT3sSv5zYWx0"},
+	}
+	errList := validateBasic(&v1.BasicAuth{Realm: "realm", Users: users}, field.NewPath("basicAuth"))
+	if len(errList) != 0 {
+		t.Errorf("want no errors, got %v", errList)
+	}
+}
+
+func TestValidateBasic_FailsOnInvalidUsers(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		basic *v1.BasicAuth
+		msg   string
+	}{
+		{
+			basic: &v1.BasicAuth{
+				Secret: "secret",
+				Users:  []v1.BasicAuthUser{{Name: "user", Hash: "{SHA}5en6G6MezRroT3XKqkdPOmY/BfQ="}},
+			},
+			msg: "both secret and users",
+		},
+		{
+			basic: &v1.BasicAuth{
+				Users: []v1.BasicAuthUser{{Name: "us:er", Hash: "{SHA}5en6G6MezRroT3XKqkdPOmY/BfQ="}},
+			},
+			msg: "name with a colon",
+		},
+		{
+			basic: &v1.BasicAuth{
+				Users: []v1.BasicAuthUser{{Name: "", Hash: "{SHA}5en6G6MezRroT3XKqkdPOmY/BfQ="}},
+			},
+			msg: "empty name",
+		},
+		{
+			basic: &v1.BasicAuth{
+				Users: []v1.BasicAuthUser{
+					{Name: "user", Hash: "{SHA}5en6G6MezRroT3XKqkdPOmY/BfQ="},
+					{Name: "user", Hash: "$apr1$abcdefgh$h9FWgUz3n9YxylKLlR5SQ/"},
+				},
+			},
+			msg: "duplicate names",
+		},
+		{
+			basic: &v1.BasicAuth{
+				Users: []v1.BasicAuthUser{{Name: "user", Hash: "secret"}},
+			},
+			msg: "plain text password",
+		},
+		{
+			basic: &v1.BasicAuth{
+				Users: []v1.BasicAuthUser{{Name: "user", Hash: "$apr1$abcdefgh$h9FWgUz3n9YxylKLlR5SQ/\nadmin"}},
+			},
+			msg: "hash with a new line",
+		},
+	}
+
+	for _, test := range tests {
+		errList := validateBasic(test.basic, field.NewPath("basicAuth"))
+		if len(errList) == 0 {
+			t.Errorf("validateBasic() returned no errors for the case of %s", test.msg)
+		}
+	}
+}
+
 func TestValidateWAF_FailsOnPresentBothApLogBundleAndApLogConf(t *testing.T) {
 	t.Parallel()
 
@@ -2617,6 +3082,32 @@ func TestValidatePolicy_IsNotValidCachePolicy(t *testing.T) {
 			},
 			isPlus: false,
 		},
+		{
+			name: "allowedMethods with unsupported method",
+			policy: &v1.Policy{
+				Spec: v1.PolicySpec{
+					Cache: &v1.Cache{
+						CacheZoneName:  "test",
+						CacheZoneSize:  "10m",
+						AllowedMethods: []string{"GET", "PUT"},
+					},
+				},
+			},
+			isPlus: false,
+		},
+		{
+			name: "allowedMethods with duplicate methods",
+			policy: &v1.Policy{
+				Spec: v1.PolicySpec{
+					Cache: &v1.Cache{
+						CacheZoneName:  "test",
+						CacheZoneSize:  "10m",
+						AllowedMethods: []string{"GET", "GET"},
+					},
+				},
+			},
+			isPlus: false,
+		},
 		{
 			name: "cache policy with invalid minUses (zero)",
 			policy: &v1.Policy{
@@ -2736,6 +3227,45 @@ func TestValidatePolicy_IsNotValidCachePolicy(t *testing.T) {
 			},
 			isPlus: false,
 		},
+		{
+			name: "cache policy with non_idempotent cacheUseStale parameter",
+			policy: &v1.Policy{
+				Spec: v1.PolicySpec{
+					Cache: &v1.Cache{
+						CacheZoneName: "nonidempotentstale",
+						CacheZoneSize: "10m",
+						CacheUseStale: []string{"error", "non_idempotent"},
+					},
+				},
+			},
+			isPlus: false,
+		},
+		{
+			name: "cache policy with empty cacheUseStale parameter",
+			policy: &v1.Policy{
+				Spec: v1.PolicySpec{
+					Cache: &v1.Cache{
+						CacheZoneName: "emptystaleparameter",
+						CacheZoneSize: "10m",
+						CacheUseStale: []string{"error", ""},
+					},
+				},
+			},
+			isPlus: false,
+		},
+		{
+			name: "cache policy with off combined with other cacheUseStale parameters",
+			policy: &v1.Policy{
+				Spec: v1.PolicySpec{
+					Cache: &v1.Cache{
+						CacheZoneName: "offstale",
+						CacheZoneSize: "10m",
+						CacheUseStale: []string{"off", "error"},
+					},
+				},
+			},
+			isPlus: false,
+		},
 		{
 			name: "cache policy with duplicate cacheUseStale parameters",
 			policy: &v1.Policy{
@@ -3026,6 +3556,32 @@ func TestValidatePolicy_IsValidCachePolicy(t *testing.T) {
 			},
 			isPlus: false,
 		},
+		{
+			name: "cache policy with off cacheUseStale parameter",
+			policy: &v1.Policy{
+				Spec: v1.PolicySpec{
+					Cache: &v1.Cache{
+						CacheZoneName: "offstale",
+						CacheZoneSize: "10m",
+						CacheUseStale: []string{"off"},
+					},
+				},
+			},
+			isPlus: false,
+		},
+		{
+			name: "cache policy with http_429 cacheUseStale parameter",
+			policy: &v1.Policy{
+				Spec: v1.PolicySpec{
+					Cache: &v1.Cache{
+						CacheZoneName: "ratelimitedstale",
+						CacheZoneSize: "10m",
+						CacheUseStale: []string{"error", "timeout", "updating", "http_429"},
+					},
+				},
+			},
+			isPlus: false,
+		},
 		{
 			name: "cache policy with empty cacheUseStale (should be valid)",
 			policy: &v1.Policy{
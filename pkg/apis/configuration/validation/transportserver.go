@@ -366,6 +366,9 @@ func validateTransportServerUpstreamParameters(upstreamParameters *conf_v1.Upstr
 	allErrs = append(allErrs, validateTime(upstreamParameters.ConnectTimeout, fieldPath.Child("connectTimeout"))...)
 	allErrs = append(allErrs, validateTime(upstreamParameters.NextUpstreamTimeout, fieldPath.Child("nextUpstreamTimeout"))...)
 	allErrs = append(allErrs, validatePositiveIntOrZero(upstreamParameters.NextUpstreamTries, fieldPath.Child("nextUpstreamTries"))...)
+	if upstreamParameters.ProxyProtocol && protocol == "UDP" {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("proxyProtocol"), "is not allowed for UDP TransportServers"))
+	}
 	return allErrs
 }
 
@@ -857,6 +857,26 @@ func TestValidateUpstreamParameters(t *testing.T) {
 	}
 }
 
+func TestValidateUpstreamParameters_ProxyProtocol(t *testing.T) {
+	t.Parallel()
+	parameters := &conf_v1.UpstreamParameters{ProxyProtocol: true}
+
+	allErrs := validateTransportServerUpstreamParameters(parameters, field.NewPath("upstreamParameters"), "TCP")
+	if len(allErrs) > 0 {
+		t.Errorf("validateTransportServerUpstreamParameters() returned errors %v for valid input", allErrs)
+	}
+}
+
+func TestValidateUpstreamParameters_FailsOnProxyProtocolForUDP(t *testing.T) {
+	t.Parallel()
+	parameters := &conf_v1.UpstreamParameters{ProxyProtocol: true}
+
+	allErrs := validateTransportServerUpstreamParameters(parameters, field.NewPath("upstreamParameters"), "UDP")
+	if len(allErrs) == 0 {
+		t.Error("validateTransportServerUpstreamParameters() returned no errors for the PROXY protocol with a UDP listener")
+	}
+}
+
 func TestValidateSessionParameters(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -2,9 +2,15 @@ package validation
 
 import (
 	"fmt"
+	"maps"
+	"net"
+	"net/url"
+	"path"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/dlclark/regexp2/v2"
@@ -45,11 +51,14 @@ type VsvOption func(*VirtualServerValidator)
 
 // VirtualServerValidator validates a VirtualServer/VirtualServerRoute resource.
 type VirtualServerValidator struct {
-	isPlus                       bool
-	isDosEnabled                 bool
-	isCertManagerEnabled         bool
-	isExternalDNSEnabled         bool
-	isDirectiveAutoadjustEnabled bool
+	isPlus                        bool
+	isDosEnabled                  bool
+	isCertManagerEnabled          bool
+	isExternalDNSEnabled          bool
+	isDirectiveAutoadjustEnabled  bool
+	isInternalProxyPassURLAllowed bool
+	filesActionRoots              []string
+	isWeightChangesDynamicReload  bool
 }
 
 // IsPlus modifies the VirtualServerValidator to set the isPlus option.
@@ -87,14 +96,36 @@ func IsDirectiveAutoadjustEnabled(autoadjust bool) VsvOption {
 	}
 }
 
+// IsInternalProxyPassURLAllowed modifies the VirtualServerValidator to set the isInternalProxyPassURLAllowed option.
+func IsInternalProxyPassURLAllowed(allow bool) VsvOption {
+	return func(v *VirtualServerValidator) {
+		v.isInternalProxyPassURLAllowed = allow
+	}
+}
+
+// FilesActionRoots modifies the VirtualServerValidator to set the directories that the files action can serve.
+func FilesActionRoots(roots []string) VsvOption {
+	return func(v *VirtualServerValidator) {
+		v.filesActionRoots = roots
+	}
+}
+
+// IsWeightChangesDynamicReload modifies the VirtualServerValidator to set the isWeightChangesDynamicReload option.
+func IsWeightChangesDynamicReload(dynamicReload bool) VsvOption {
+	return func(v *VirtualServerValidator) {
+		v.isWeightChangesDynamicReload = dynamicReload
+	}
+}
+
 // NewVirtualServerValidator creates a new VirtualServerValidator.
 func NewVirtualServerValidator(opts ...VsvOption) *VirtualServerValidator {
 	vsv := VirtualServerValidator{
-		isPlus:                       false,
-		isDosEnabled:                 false,
-		isCertManagerEnabled:         false,
-		isExternalDNSEnabled:         false,
-		isDirectiveAutoadjustEnabled: false,
+		isPlus:                        false,
+		isDosEnabled:                  false,
+		isCertManagerEnabled:          false,
+		isExternalDNSEnabled:          false,
+		isDirectiveAutoadjustEnabled:  false,
+		isInternalProxyPassURLAllowed: false,
 	}
 	for _, o := range opts {
 		o(&vsv)
@@ -133,6 +164,10 @@ func (vsv *VirtualServerValidator) validateVirtualServerSpec(spec *v1.VirtualSer
 	upstreamErrs, upstreamNames := vsv.validateUpstreams(spec.Upstreams, fieldPath.Child("upstreams"))
 	allErrs = append(allErrs, upstreamErrs...)
 
+	allErrs = append(allErrs, validateHealthCheckMatches(spec.HealthCheckMatches, spec.Upstreams, fieldPath)...)
+	allErrs = append(allErrs, validateTimeoutProfiles(spec.TimeoutProfiles, spec.Upstreams, fieldPath)...)
+	allErrs = append(allErrs, validateGeos(spec.Geos, spec.Routes, fieldPath)...)
+
 	allErrs = append(allErrs, vsv.validateVirtualServerRoutes(spec.Routes, fieldPath.Child("routes"), upstreamNames, namespace)...)
 
 	allErrs = append(allErrs, validateDos(vsv.isDosEnabled, spec.Dos, fieldPath.Child("dos"))...)
@@ -143,6 +178,129 @@ func (vsv *VirtualServerValidator) validateVirtualServerSpec(spec *v1.VirtualSer
 		allErrs = append(allErrs, validateAddHeaderInherit(spec.AddHeaderInherit, fieldPath.Child("add-header-inherit"))...)
 	}
 
+	if spec.RequestID != nil {
+		allErrs = append(allErrs, validateRequestID(spec.RequestID, fieldPath.Child("requestID"))...)
+	}
+
+	allErrs = append(allErrs, vsv.validateResponseHeaders(spec.ResponseHeaders, fieldPath.Child("responseHeaders"))...)
+
+	if spec.Tracing != nil {
+		allErrs = append(allErrs, validateTracing(spec.Tracing, fieldPath.Child("tracing"))...)
+	}
+
+	allErrs = append(allErrs, validateErrorLog(spec.ErrorLogLevel, spec.ErrorLogDestination, fieldPath)...)
+
+	allErrs = append(allErrs, validateDefaultType(spec.DefaultType, fieldPath.Child("defaultType"))...)
+
+	allErrs = append(allErrs, validateStrictHost(spec.StrictHost, fieldPath.Child("strictHost"))...)
+	allErrs = append(allErrs, vsv.validateNoEndpoints(spec.NoEndpoints, fieldPath.Child("noEndpoints"))...)
+
+	return allErrs
+}
+
+var validStrictHostStatusCodes = map[int]bool{
+	400: true,
+	403: true,
+	404: true,
+	421: true,
+	444: true,
+}
+
+func validateStrictHost(strictHost *v1.StrictHost, fieldPath *field.Path) field.ErrorList {
+	if strictHost == nil || strictHost.Code == nil {
+		return nil
+	}
+
+	if !validStrictHostStatusCodes[*strictHost.Code] {
+		return field.ErrorList{field.Invalid(fieldPath.Child("code"), *strictHost.Code, "status code out of accepted range. accepted values are '400', '403', '404', '421', '444'")}
+	}
+	return nil
+}
+
+func (vsv *VirtualServerValidator) validateNoEndpoints(noEndpoints *v1.NoEndpoints, fieldPath *field.Path) field.ErrorList {
+	if noEndpoints == nil {
+		return nil
+	}
+
+	if noEndpoints.Return != nil {
+		allErrs := field.ErrorList{}
+		if noEndpoints.Code != 0 {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("code"), "cannot be used together with return"))
+		}
+		if noEndpoints.RetryAfter != nil {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("retryAfter"), "cannot be used together with return"))
+		}
+		return append(allErrs, vsv.validateActionReturn(noEndpoints.Return, fieldPath.Child("return"), returnBodySpecialVariables, returnBodyVariables)...)
+	}
+
+	if noEndpoints.Code != 0 && noEndpoints.Code != 502 && noEndpoints.Code != 503 {
+		return field.ErrorList{field.NotSupported(fieldPath.Child("code"), noEndpoints.Code, []string{"502", "503"})}
+	}
+	if noEndpoints.RetryAfter != nil {
+		if noEndpoints.Code != 503 {
+			return field.ErrorList{field.Forbidden(fieldPath.Child("retryAfter"), "requires the code 503")}
+		}
+		return validatePositiveInt(*noEndpoints.RetryAfter, fieldPath.Child("retryAfter"))
+	}
+	return nil
+}
+
+var errorLogDestinationRegexp = regexp.MustCompile("^(stderr|syslog:" + nginxTokenFmt + ")$")
+
+func validateErrorLog(level string, destination string, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if level != "" {
+		if _, err := configs.ParseErrorLogLevel(level); err != nil {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("errorLogLevel"), level, err.Error()))
+		}
+	}
+
+	if destination == "" {
+		return allErrs
+	}
+
+	if level == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("errorLogLevel"), "must be set when errorLogDestination is set"))
+	}
+
+	if !errorLogDestinationRegexp.MatchString(destination) {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("errorLogDestination"), destination, "must be stderr or syslog:server=<address>[,parameters]"))
+	}
+
+	return allErrs
+}
+
+func (vsv *VirtualServerValidator) validateResponseHeaders(responseHeaders *v1.ResponseHeaders, fieldPath *field.Path) field.ErrorList {
+	if responseHeaders == nil {
+		return nil
+	}
+
+	allErrs := field.ErrorList{}
+	for i, header := range responseHeaders.Add {
+		allErrs = append(allErrs, vsv.validateActionProxyHeader(header.Header, fieldPath.Child("add").Index(i))...)
+	}
+	return allErrs
+}
+
+var validTracingContexts = []string{"extract", "inject", "propagate", "ignore"}
+
+func validateTracing(tracing *v1.Tracing, fieldPath *field.Path) field.ErrorList {
+	if tracing.Context != "" && !slices.Contains(validTracingContexts, tracing.Context) {
+		return field.ErrorList{field.NotSupported(fieldPath.Child("context"), tracing.Context, validTracingContexts)}
+	}
+	return nil
+}
+
+func validateRequestID(requestID *v1.RequestID, fieldPath *field.Path) field.ErrorList {
+	if requestID.Header == "" {
+		return nil
+	}
+
+	allErrs := field.ErrorList{}
+	for _, msg := range validation.IsHTTPHeaderName(requestID.Header) {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("header"), requestID.Header, msg))
+	}
 	return allErrs
 }
 
@@ -214,6 +372,55 @@ func (vsv *VirtualServerValidator) validateTLS(tls *v1.TLS, fieldPath *field.Pat
 	allErrs := validateSecretName(tls.Secret, fieldPath.Child("secret"))
 	allErrs = append(allErrs, validateTLSRedirect(tls.Redirect, fieldPath.Child("redirect"))...)
 	allErrs = append(allErrs, validateTLSCmFields(tls.CertManager, vsv.isCertManagerEnabled, tls.Secret, fieldPath.Child("cert-manager"))...)
+	allErrs = append(allErrs, validateTLSProfile(tls.Profile, fieldPath.Child("profile"))...)
+	return allErrs
+}
+
+var validTLSProfileNames = map[string]bool{
+	"modern":       true,
+	"intermediate": true,
+}
+
+var validSSLProtocols = map[string]bool{
+	"TLSv1":   true,
+	"TLSv1.1": true,
+	"TLSv1.2": true,
+	"TLSv1.3": true,
+}
+
+const (
+	sslCiphersFmt    = `[A-Za-z0-9_+!@=:.-]+`
+	sslCiphersErrMsg = "must be a list of ciphers in the OpenSSL format"
+)
+
+var sslCiphersRegexp = regexp.MustCompile("^" + sslCiphersFmt + "$")
+
+func validateTLSProfile(profile *v1.TLSProfile, fieldPath *field.Path) field.ErrorList {
+	if profile == nil {
+		return nil
+	}
+
+	allErrs := field.ErrorList{}
+	if profile.Name != "" && !validTLSProfileNames[profile.Name] {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("name"), profile.Name, "accepted values are 'modern', 'intermediate'"))
+	}
+
+	if profile.Name == "" && profile.Protocols == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("protocols"), "must be specified when the name is not specified"))
+	}
+
+	for _, p := range strings.Fields(profile.Protocols) {
+		if !validSSLProtocols[p] {
+			msg := fmt.Sprintf("not a valid protocol. Accepted protocols are : %v", mapToPrettyString(validSSLProtocols))
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("protocols"), p, msg))
+		}
+	}
+
+	if profile.Ciphers != "" && !sslCiphersRegexp.MatchString(profile.Ciphers) {
+		msg := validation.RegexError(sslCiphersErrMsg, sslCiphersFmt, "ECDHE-RSA-AES128-GCM-SHA256:ECDHE-RSA-AES256-GCM-SHA384", "HIGH:!aNULL:!MD5")
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("ciphers"), profile.Ciphers, msg))
+	}
+
 	return allErrs
 }
 
@@ -383,11 +590,20 @@ func validateUpstreamHealthCheck(hc *v1.HealthCheck, typeName string, fieldPath
 	allErrs = append(allErrs, validateTime(hc.ReadTimeout, fieldPath.Child("read-timeout"))...)
 	allErrs = append(allErrs, validateTime(hc.SendTimeout, fieldPath.Child("send-timeout"))...)
 	allErrs = append(allErrs, validateStatusMatch(hc.StatusMatch, fieldPath.Child("statusMatch"))...)
+	if hc.Match != "" {
+		if hc.StatusMatch != "" {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("match"), "cannot specify both `match` and `statusMatch`"))
+		}
+		allErrs = append(allErrs, validateDNS1035Label(hc.Match, fieldPath.Child("match"))...)
+	}
 	allErrs = append(allErrs, validateTime(hc.KeepaliveTime, fieldPath.Child("keepalive-time"))...)
+	if hc.DisableKeepalive && hc.KeepaliveTime != "" {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("keepalive-time"), "cannot be set when `disable-keepalive` is true"))
+	}
 
 	for i, header := range hc.Headers {
 		idxPath := fieldPath.Child("headers").Index(i)
-		allErrs = append(allErrs, validateHeader(header, idxPath)...)
+		allErrs = append(allErrs, validateHealthCheckHeader(header, idxPath)...)
 	}
 
 	if hc.Port > 0 {
@@ -400,6 +616,167 @@ func validateUpstreamHealthCheck(hc *v1.HealthCheck, typeName string, fieldPath
 		allErrs = append(allErrs, field.Required(fieldPath.Child("mandatory"), "must be true when `persistent` is true"))
 	}
 
+	if hc.TLS != nil && hc.TLS.SessionReuse != nil {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("tls", "sessionReuse"), "is not supported in health checks"))
+	}
+
+	if hc.TLS != nil && len(hc.TLS.Ports) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("tls", "ports"), "is not supported in health checks"))
+	}
+
+	return allErrs
+}
+
+// healthCheckHeaderVariables are the variables that the headers of health check requests can reference.
+// Health check requests are not tied to a client request, so only the variables that do not depend on one are allowed.
+var healthCheckHeaderVariables = map[string]bool{
+	"host":          true,
+	"server_name":   true,
+	"hostname":      true,
+	"proxy_host":    true,
+	"nginx_version": true,
+	"pid":           true,
+	"msec":          true,
+	"time_iso8601":  true,
+}
+
+func validateHealthCheckHeader(h v1.Header, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if h.Name == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("name"), ""))
+	}
+
+	for _, msg := range validation.IsHTTPHeaderName(h.Name) {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("name"), h.Name, msg))
+	}
+
+	allErrs = append(allErrs, validateEscapedStringWithVariables(h.Value, fieldPath.Child("value"), nil, healthCheckHeaderVariables, true)...)
+
+	return allErrs
+}
+
+// validateHealthCheckMatches validates the health check matches of a VirtualServer and the references to them
+// in the health checks of its upstreams.
+func validateHealthCheckMatches(matches []v1.HealthCheckMatch, upstreams []v1.Upstream, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	names := sets.Set[string]{}
+	for i, m := range matches {
+		idxPath := fieldPath.Child("healthCheckMatches").Index(i)
+		if names.Has(m.Name) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), m.Name))
+		} else {
+			allErrs = append(allErrs, validateDNS1035Label(m.Name, idxPath.Child("name"))...)
+			names.Insert(m.Name)
+		}
+		if m.Status == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("status"), ""))
+		} else {
+			allErrs = append(allErrs, validateStatusMatch(m.Status, idxPath.Child("status"))...)
+		}
+	}
+
+	for i, u := range upstreams {
+		if u.HealthCheck == nil || u.HealthCheck.Match == "" {
+			continue
+		}
+		if !names.Has(u.HealthCheck.Match) {
+			allErrs = append(allErrs, field.NotFound(fieldPath.Child("upstreams").Index(i).Child("healthCheck", "match"), u.HealthCheck.Match))
+		}
+	}
+
+	return allErrs
+}
+
+// validateTimeoutProfiles validates the timeout profiles of a VirtualServer and the references to them
+// in its upstreams.
+func validateTimeoutProfiles(profiles []v1.TimeoutProfile, upstreams []v1.Upstream, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	names := sets.Set[string]{}
+	for i, p := range profiles {
+		idxPath := fieldPath.Child("timeoutProfiles").Index(i)
+		if names.Has(p.Name) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), p.Name))
+		} else {
+			allErrs = append(allErrs, validateDNS1035Label(p.Name, idxPath.Child("name"))...)
+			names.Insert(p.Name)
+		}
+		allErrs = append(allErrs, validateTime(p.ConnectTimeout, idxPath.Child("connect-timeout"))...)
+		allErrs = append(allErrs, validateTime(p.ReadTimeout, idxPath.Child("read-timeout"))...)
+		allErrs = append(allErrs, validateTime(p.SendTimeout, idxPath.Child("send-timeout"))...)
+	}
+
+	for i, u := range upstreams {
+		if u.TimeoutProfile == "" {
+			continue
+		}
+		if !names.Has(u.TimeoutProfile) {
+			allErrs = append(allErrs, field.NotFound(fieldPath.Child("upstreams").Index(i).Child("timeout-profile"), u.TimeoutProfile))
+		}
+	}
+
+	return allErrs
+}
+
+const (
+	geoValueFmt    string = "[-._A-Za-z0-9]+"
+	geoValueErrMsg string = "a valid geo value must consist of alphanumeric characters, '-', '_' or '.'"
+)
+
+var geoValueRegexp = regexp.MustCompile("^" + geoValueFmt + "$")
+
+func validateGeoValue(value string, fieldPath *field.Path) field.ErrorList {
+	if !geoValueRegexp.MatchString(value) {
+		return field.ErrorList{field.Invalid(fieldPath, value, validation.RegexError(geoValueErrMsg, geoValueFmt, "eu-west"))}
+	}
+	return nil
+}
+
+func validateGeos(geos []v1.Geo, routes []v1.Route, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	names := sets.Set[string]{}
+	for i, g := range geos {
+		idxPath := fieldPath.Child("geos").Index(i)
+		if names.Has(g.Name) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), g.Name))
+		} else {
+			allErrs = append(allErrs, validateDNS1035Label(g.Name, idxPath.Child("name"))...)
+			names.Insert(g.Name)
+		}
+		if g.Default != "" {
+			allErrs = append(allErrs, validateGeoValue(g.Default, idxPath.Child("default"))...)
+		}
+		if len(g.Ranges) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath.Child("ranges"), "must specify at least one range"))
+		}
+		for j, r := range g.Ranges {
+			rangePath := idxPath.Child("ranges").Index(j)
+			allErrs = append(allErrs, validateIPorCIDR(r.CIDR, rangePath.Child("cidr"))...)
+			allErrs = append(allErrs, validateGeoValue(r.Value, rangePath.Child("value"))...)
+		}
+	}
+
+	// the geos referenced by the conditions of the VirtualServerRoutes are checked when the configuration is generated
+	validateConditionGeos := func(conditions []v1.Condition, conditionsPath *field.Path) {
+		for i, c := range conditions {
+			if c.Geo != "" && !names.Has(c.Geo) {
+				allErrs = append(allErrs, field.NotFound(conditionsPath.Index(i).Child("geo"), c.Geo))
+			}
+		}
+	}
+	for i, r := range routes {
+		routePath := fieldPath.Child("routes").Index(i)
+		for j, m := range r.Matches {
+			validateConditionGeos(m.Conditions, routePath.Child("matches").Index(j).Child("conditions"))
+		}
+		if r.Tarpit != nil {
+			validateConditionGeos(r.Tarpit.Conditions, routePath.Child("tarpit", "conditions"))
+		}
+	}
+
 	return allErrs
 }
 
@@ -422,6 +799,9 @@ func validateGrpcHealthCheck(hc *v1.HealthCheck, typeName string, fieldPath *fie
 	if hc.StatusMatch != "" {
 		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("statusMatch"), "cannot specify `statusMatch` on gRPC type health checks"))
 	}
+	if hc.Match != "" {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("match"), "cannot specify `match` on gRPC type health checks"))
+	}
 
 	allErrs = append(allErrs, validateGrpcStatus(hc.GRPCStatus, fieldPath.Child("grpcStatus"))...)
 
@@ -485,6 +865,77 @@ func validateSessionCookie(sc *v1.SessionCookie, fieldPath *field.Path) field.Er
 	return allErrs
 }
 
+func validateSplitStickyCookie(route v1.Route, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	hasSplits := len(route.Splits) > 0
+	for _, m := range route.Matches {
+		if len(m.Splits) > 0 {
+			hasSplits = true
+		}
+	}
+	if !hasSplits {
+		allErrs = append(allErrs, field.Forbidden(fieldPath, "requires `splits` in the route or its matches"))
+	}
+
+	sc := route.StickyCookie
+	if sc.Name == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("name"), ""))
+	} else {
+		for _, msg := range isCookieName(sc.Name) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("name"), sc.Name, msg))
+		}
+	}
+
+	if sc.Path != "" {
+		allErrs = append(allErrs, validatePath(sc.Path, fieldPath.Child("path"))...)
+	}
+
+	if sc.TTL != nil {
+		allErrs = append(allErrs, validatePositiveInt(*sc.TTL, fieldPath.Child("ttl"))...)
+	}
+
+	return allErrs
+}
+
+const (
+	splitWeightsKeyFmt    = `[a-zA-Z0-9_.-]+`
+	splitWeightsKeyErrMsg = "must consist of alphanumeric characters, '_', '.' or '-'"
+)
+
+var splitWeightsKeyRegexp = regexp.MustCompile("^" + splitWeightsKeyFmt + "$")
+
+func (vsv *VirtualServerValidator) validateSplitWeights(route v1.Route, fieldPath *field.Path) field.ErrorList {
+	if !vsv.isPlus {
+		return field.ErrorList{field.Forbidden(fieldPath, "split weights are only supported in NGINX Plus")}
+	}
+	if !vsv.isWeightChangesDynamicReload {
+		return field.ErrorList{field.Forbidden(fieldPath, "split weights require the -weight-changes-dynamic-reload command-line argument")}
+	}
+
+	allErrs := field.ErrorList{}
+	if len(route.Splits) != 2 {
+		allErrs = append(allErrs, field.Forbidden(fieldPath, "requires exactly two `splits` in the route"))
+	}
+
+	for _, s := range route.Splits {
+		if s.Promoted {
+			allErrs = append(allErrs, field.Forbidden(fieldPath, "cannot be used together with a promoted split"))
+			break
+		}
+	}
+
+	key := route.SplitWeights.Key
+	if key == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("key"), ""))
+	} else if !splitWeightsKeyRegexp.MatchString(key) {
+		msg := validation.RegexError(splitWeightsKeyErrMsg, splitWeightsKeyFmt, "canary", "cafe.canary-weight")
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("key"), key, msg))
+	}
+
+	return allErrs
+}
+
 // validateUpstreamType validates that the protocol type of the upstream is of a supported protocol.
 // Current supported protocols are "http" and "grpc". If unset, it will default to "http".
 func validateUpstreamType(typeName string, fieldPath *field.Path) field.ErrorList {
@@ -646,6 +1097,9 @@ func (vsv *VirtualServerValidator) validateUpstreams(upstreams []v1.Upstream, fi
 		allErrs = append(allErrs, validateTime(u.ProxyConnectTimeout, idxPath.Child("connect-timeout"))...)
 		allErrs = append(allErrs, validateTime(u.ProxyReadTimeout, idxPath.Child("read-timeout"))...)
 		allErrs = append(allErrs, validateTime(u.ProxySendTimeout, idxPath.Child("send-timeout"))...)
+		if u.TimeoutProfile != "" {
+			allErrs = append(allErrs, validateDNS1035Label(u.TimeoutProfile, idxPath.Child("timeout-profile"))...)
+		}
 		allErrs = append(allErrs, validateNextUpstream(u.ProxyNextUpstream, idxPath.Child("next-upstream"))...)
 		allErrs = append(allErrs, validateTime(u.ProxyNextUpstreamTimeout, idxPath.Child("next-upstream-timeout"))...)
 		allErrs = append(allErrs, validatePositiveIntOrZeroFromPointer(&u.ProxyNextUpstreamTries, idxPath.Child("next-upstream-tries"))...)
@@ -653,22 +1107,32 @@ func (vsv *VirtualServerValidator) validateUpstreams(upstreams []v1.Upstream, fi
 		allErrs = append(allErrs, validateTime(u.FailTimeout, idxPath.Child("fail-timeout"))...)
 		allErrs = append(allErrs, validatePositiveIntOrZeroFromPointer(u.MaxFails, idxPath.Child("max-fails"))...)
 		allErrs = append(allErrs, validatePositiveIntOrZeroFromPointer(u.Keepalive, idxPath.Child("keepalive"))...)
+		allErrs = append(allErrs, validateTime(u.KeepaliveTime, idxPath.Child("keepalive-time"))...)
 		allErrs = append(allErrs, validatePositiveIntOrZeroFromPointer(u.MaxConns, idxPath.Child("max-conns"))...)
 		allErrs = append(allErrs, validateOffset(u.ClientMaxBodySize, idxPath.Child("client-max-body-size"))...)
+		allErrs = append(allErrs, validateSize(u.ClientBodyBufferSize, idxPath.Child("client-body-buffer-size"))...)
 		allErrs = append(allErrs, validateUpstreamHealthCheck(u.HealthCheck, u.Type, idxPath.Child("healthCheck"))...)
 		allErrs = append(allErrs, validateTime(u.SlowStart, idxPath.Child("slow-start"))...)
+		allErrs = append(allErrs, validateTime(u.ResolveInterval, idxPath.Child("resolve-interval"))...)
 		allErrs = append(allErrs, validateBuffer(u.ProxyBuffers, idxPath.Child("buffers"))...)
 		allErrs = append(allErrs, validateSize(u.ProxyBufferSize, idxPath.Child("buffer-size"))...)
 		allErrs = append(allErrs, validateSize(u.ProxyBusyBuffersSize, idxPath.Child("busy-buffers-size"))...)
+		allErrs = append(allErrs, validateSize(u.ProxyMaxTempFileSize, idxPath.Child("max-temp-file-size"))...)
 		allErrs = append(allErrs, validateQueue(u.Queue, idxPath.Child("queue"))...)
 		allErrs = append(allErrs, validateSessionCookie(u.SessionCookie, idxPath.Child("sessionCookie"))...)
 		allErrs = append(allErrs, validateUpstreamType(u.Type, idxPath.Child("type"))...)
+		allErrs = append(allErrs, validateUpstreamWebSocket(u.WebSocket, u.Type, idxPath.Child("websocket"))...)
+		if u.ProxyIgnoreClientAbort != nil && u.Type == "grpc" {
+			allErrs = append(allErrs, field.Forbidden(idxPath.Child("ignore-client-abort"), "is not supported for upstreams of type grpc"))
+		}
 
 		for _, msg := range validation.IsValidPortNum(int(u.Port)) {
 			allErrs = append(allErrs, field.Invalid(idxPath.Child("port"), u.Port, msg))
 		}
 
+		allErrs = append(allErrs, validateUpstreamTLSPorts(u.TLS, idxPath.Child("tls"))...)
 		allErrs = append(allErrs, validateBackup(u.Backup, u.BackupPort, u.LBMethod, idxPath)...)
+		allErrs = append(allErrs, validateUpstreamFailover(u, idxPath)...)
 
 		allErrs = append(allErrs, rejectPlusResourcesInOSS(u, idxPath, vsv.isPlus)...)
 
@@ -676,6 +1140,48 @@ func (vsv *VirtualServerValidator) validateUpstreams(upstreams []v1.Upstream, fi
 	return allErrs, upstreamNames
 }
 
+func validateUpstreamWebSocket(webSocket *v1.UpstreamWebSocket, upstreamType string, fieldPath *field.Path) field.ErrorList {
+	if webSocket == nil {
+		return nil
+	}
+
+	allErrs := field.ErrorList{}
+	if webSocket.Enable && upstreamType == "grpc" {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("enable"), "WebSocket is not supported for upstreams of type grpc"))
+	}
+	if webSocket.ReadTimeout != "" && !webSocket.Enable {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("read-timeout"), "requires enable to be true"))
+	}
+	allErrs = append(allErrs, validateTime(webSocket.ReadTimeout, fieldPath.Child("read-timeout"))...)
+
+	return allErrs
+}
+
+func validateUpstreamTLSPorts(tls v1.UpstreamTLS, fieldPath *field.Path) field.ErrorList {
+	if len(tls.Ports) == 0 {
+		return nil
+	}
+
+	allErrs := field.ErrorList{}
+	if !tls.Enable {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("ports"), "requires enable to be true"))
+	}
+
+	ports := sets.Set[uint16]{}
+	for i, port := range tls.Ports {
+		idxPath := fieldPath.Child("ports").Index(i)
+		for _, msg := range validation.IsValidPortNum(int(port)) {
+			allErrs = append(allErrs, field.Invalid(idxPath, port, msg))
+		}
+		if ports.Has(port) {
+			allErrs = append(allErrs, field.Duplicate(idxPath, port))
+		}
+		ports.Insert(port)
+	}
+
+	return allErrs
+}
+
 // validateBackup validates backup service name and port semantics and business logic.
 //
 // Backup can't be used with load balancing methods: 'hash', 'hash_ip' and 'random'.
@@ -704,6 +1210,36 @@ func validateBackup(backup string, backupPort *uint16, lbMethod string, idxPath
 	return allErrs
 }
 
+// validateUpstreamFailover validates the failover of an upstream to its backup servers, which sets
+// the next-upstream-tries and next-upstream-timeout of the upstream.
+func validateUpstreamFailover(upstream v1.Upstream, idxPath *field.Path) field.ErrorList {
+	failover := upstream.Failover
+	if failover == nil {
+		return nil
+	}
+
+	fieldPath := idxPath.Child("failover")
+	allErrs := field.ErrorList{}
+	if upstream.Backup == "" {
+		allErrs = append(allErrs, field.Forbidden(fieldPath, "requires the backup service"))
+	}
+	if upstream.ProxyNextUpstream == "off" {
+		allErrs = append(allErrs, field.Forbidden(fieldPath, "can not be used when next-upstream is off"))
+	}
+	if upstream.ProxyNextUpstreamTries != 0 {
+		allErrs = append(allErrs, field.Forbidden(idxPath.Child("next-upstream-tries"), "can not be used together with failover"))
+	}
+	if upstream.ProxyNextUpstreamTimeout != "" {
+		allErrs = append(allErrs, field.Forbidden(idxPath.Child("next-upstream-timeout"), "can not be used together with failover"))
+	}
+	if failover.PrimaryTries != nil && *failover.PrimaryTries <= 0 {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("primaryTries"), *failover.PrimaryTries, "must be positive"))
+	}
+	allErrs = append(allErrs, validateTime(failover.Timeout, fieldPath.Child("timeout"))...)
+
+	return allErrs
+}
+
 var validNextUpstreamParams = map[string]bool{
 	"error":          true,
 	"timeout":        true,
@@ -863,65 +1399,276 @@ func (vsv *VirtualServerValidator) validateVirtualServerRoutes(routes []v1.Route
 	return allErrs
 }
 
-func (vsv *VirtualServerValidator) validateRoute(route v1.Route, fieldPath *field.Path, upstreamNames sets.Set[string], isRouteFieldForbidden bool, namespace string) field.ErrorList {
-	allErrs := validateRoutePath(route.Path, fieldPath.Child("path"))
-	allErrs = append(allErrs, validatePolicies(route.Policies, fieldPath.Child("policies"), namespace)...)
+func (vsv *VirtualServerValidator) validateRoute(route v1.Route, fieldPath *field.Path, upstreamNames sets.Set[string], isRouteFieldForbidden bool, namespace string) field.ErrorList {
+	allErrs := validateRoutePath(route.Path, fieldPath.Child("path"))
+	allErrs = append(allErrs, validatePolicies(route.Policies, fieldPath.Child("policies"), namespace)...)
+
+	fieldCount := 0
+
+	if route.Action != nil {
+		allErrs = append(allErrs, vsv.validateAction(route.Action, fieldPath.Child("action"), upstreamNames, route.Path, false)...)
+		fieldCount++
+	}
+
+	if len(route.Splits) > 0 {
+		allErrs = append(allErrs, vsv.validateSplits(route.Splits, fieldPath.Child("splits"), upstreamNames, route.Path)...)
+		fieldCount++
+	}
+
+	// Matches are optional. that's why we don't do fieldCount++
+	if len(route.Matches) > 0 {
+		for i, m := range route.Matches {
+			allErrs = append(allErrs, vsv.validateMatch(m, fieldPath.Child("matches").Index(i), upstreamNames, route.Path)...)
+		}
+	}
+
+	if route.RouteSelector != nil {
+		allErrs = append(allErrs, vsv.validateRouteSelector(route.RouteSelector, fieldPath.Child("routeSelector"))...)
+		fieldCount++
+	}
+
+	for i, e := range route.ErrorPages {
+		allErrs = append(allErrs, vsv.validateErrorPage(e, fieldPath.Child("errorPages").Index(i))...)
+	}
+
+	allErrs = append(allErrs, validatePassthroughErrorCodes(route.PassthroughErrorCodes, fieldPath.Child("passthroughErrorCodes"))...)
+
+	if route.InheritErrorPages != nil && !isRouteFieldForbidden {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("inheritErrorPages"), "is only allowed in the subroutes of VirtualServerRoutes"))
+	}
+
+	if route.Route != "" {
+		if isRouteFieldForbidden {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("route"), "is not allowed"))
+		} else {
+			allErrs = append(allErrs, validateRouteField(route.Route, fieldPath.Child("route"))...)
+			fieldCount++
+		}
+	}
+
+	if fieldCount != 1 {
+		msg := "must specify exactly one of `action`, `splits`, `route` or `routeSelector`"
+		if isRouteFieldForbidden || len(route.Matches) > 0 {
+			msg = "must specify exactly one of `action` or `splits`"
+		}
+
+		allErrs = append(allErrs, field.Invalid(fieldPath, "", msg))
+	}
+
+	if route.AddHeaderInherit != "" {
+		allErrs = append(allErrs, validateAddHeaderInherit(route.AddHeaderInherit, fieldPath.Child("add-header-inherit"))...)
+	}
+
+	if route.StickyCookie != nil {
+		allErrs = append(allErrs, validateSplitStickyCookie(route, fieldPath.Child("stickyCookie"))...)
+	}
+
+	if route.SplitWeights != nil {
+		allErrs = append(allErrs, vsv.validateSplitWeights(route, fieldPath.Child("splitWeights"))...)
+	}
+
+	if route.Tarpit != nil {
+		if route.Route != "" || route.RouteSelector != nil {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("tarpit"), "is not allowed for routes that reference VirtualServerRoutes"))
+		} else {
+			allErrs = append(allErrs, validateTarpit(route.Tarpit, fieldPath.Child("tarpit"))...)
+		}
+	}
+
+	if route.ClientIPReturn != nil {
+		if route.Route != "" || route.RouteSelector != nil {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("clientIPReturn"), "is not allowed for routes that reference VirtualServerRoutes"))
+		} else {
+			allErrs = append(allErrs, validateClientIPReturn(route.ClientIPReturn, fieldPath.Child("clientIPReturn"))...)
+		}
+	}
+
+	if route.StatusZone != "" {
+		if route.Route != "" || route.RouteSelector != nil {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("statusZone"), "is not allowed for routes that reference VirtualServerRoutes"))
+		} else if !vsv.isPlus {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("statusZone"), "status zones of routes are only supported in NGINX Plus"))
+		} else {
+			allErrs = append(allErrs, validateRouteStatusZone(route.StatusZone, fieldPath.Child("statusZone"))...)
+		}
+	}
+
+	if len(route.Rewrites) > 0 {
+		if route.Route != "" || route.RouteSelector != nil {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("rewrites"), "is not allowed for routes that reference VirtualServerRoutes"))
+		} else if len(route.Matches) > 0 || len(route.Splits) > 0 {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("rewrites"), "is not allowed for routes with matches or splits"))
+		} else if route.Action != nil && (route.Action.Return != nil || route.Action.Redirect != nil) {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("rewrites"), "is not allowed for routes with return or redirect actions"))
+		} else {
+			allErrs = append(allErrs, validateRouteRewrites(route.Rewrites, fieldPath.Child("rewrites"))...)
+		}
+	}
+
+	if route.Tracing != nil {
+		if route.Route != "" || route.RouteSelector != nil {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("tracing"), "is not allowed for routes that reference VirtualServerRoutes"))
+		} else {
+			allErrs = append(allErrs, validateTracing(route.Tracing, fieldPath.Child("tracing"))...)
+		}
+	}
+
+	if len(route.GRPCErrorResponses) > 0 {
+		if route.Route != "" || route.RouteSelector != nil {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("grpcErrorResponses"), "is not allowed for routes that reference VirtualServerRoutes"))
+		} else {
+			allErrs = append(allErrs, validateGRPCErrorResponses(route.GRPCErrorResponses, fieldPath.Child("grpcErrorResponses"))...)
+		}
+	}
+
+	if route.PreserveHostHeader && (route.Route != "" || route.RouteSelector != nil) {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("preserveHostHeader"), "is not allowed for routes that reference VirtualServerRoutes"))
+	}
+
+	allErrs = append(allErrs, validateDos(vsv.isDosEnabled, route.Dos, fieldPath.Child("dos"))...)
+
+	return allErrs
+}
+
+// grpcErrorStatuses are the gRPC statuses that NGINX returns for the failed requests to gRPC upstreams.
+var grpcErrorStatuses = []string{"DEADLINE_EXCEEDED", "PERMISSION_DENIED", "RESOURCE_EXHAUSTED", "UNIMPLEMENTED", "INTERNAL", "UNAVAILABLE", "UNAUTHENTICATED"}
+
+// grpcStatuses are the gRPC statuses other than OK.
+var grpcStatuses = []string{
+	"CANCELLED", "UNKNOWN", "INVALID_ARGUMENT", "DEADLINE_EXCEEDED", "NOT_FOUND", "ALREADY_EXISTS", "PERMISSION_DENIED", "RESOURCE_EXHAUSTED",
+	"FAILED_PRECONDITION", "ABORTED", "OUT_OF_RANGE", "UNIMPLEMENTED", "INTERNAL", "UNAVAILABLE", "DATA_LOSS", "UNAUTHENTICATED",
+}
+
+func validateGRPCErrorResponses(responses []v1.GRPCErrorResponse, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	statuses := sets.Set[string]{}
+
+	for i, r := range responses {
+		idxPath := fieldPath.Index(i)
+
+		if r.Status == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("status"), ""))
+		} else if !slices.Contains(grpcErrorStatuses, r.Status) {
+			allErrs = append(allErrs, field.NotSupported(idxPath.Child("status"), r.Status, grpcErrorStatuses))
+		} else if statuses.Has(r.Status) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("status"), r.Status))
+		}
+		statuses.Insert(r.Status)
+
+		returnPath := idxPath.Child("return")
+		if r.Return.Status != "" && !slices.Contains(grpcStatuses, r.Return.Status) {
+			allErrs = append(allErrs, field.NotSupported(returnPath.Child("status"), r.Return.Status, grpcStatuses))
+		}
+		for _, msg := range isValidHeaderValue(r.Return.Message) {
+			allErrs = append(allErrs, field.Invalid(returnPath.Child("message"), r.Return.Message, msg))
+		}
+		for j, h := range r.Return.Headers {
+			allErrs = append(allErrs, validateHeader(h, returnPath.Child("headers").Index(j))...)
+		}
+	}
+
+	return allErrs
+}
+
+var validRewriteFlags = []string{"last", "break", "redirect", "permanent"}
 
-	fieldCount := 0
+func validateRouteRewrites(rewrites []v1.Rewrite, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
 
-	if route.Action != nil {
-		allErrs = append(allErrs, vsv.validateAction(route.Action, fieldPath.Child("action"), upstreamNames, route.Path, false)...)
-		fieldCount++
+	for i, r := range rewrites {
+		idxPath := fieldPath.Index(i)
+
+		allErrs = append(allErrs, validateRewriteRegex(r.Regex, idxPath.Child("regex"))...)
+
+		if r.Replacement == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("replacement"), ""))
+		} else {
+			allErrs = append(allErrs, validateActionProxyRewritePathForRegexp(r.Replacement, idxPath.Child("replacement"))...)
+		}
+
+		if r.Flag != "" && !slices.Contains(validRewriteFlags, r.Flag) {
+			allErrs = append(allErrs, field.NotSupported(idxPath.Child("flag"), r.Flag, validRewriteFlags))
+		}
 	}
 
-	if len(route.Splits) > 0 {
-		allErrs = append(allErrs, vsv.validateSplits(route.Splits, fieldPath.Child("splits"), upstreamNames, route.Path)...)
-		fieldCount++
+	return allErrs
+}
+
+func validateRewriteRegex(regex string, fieldPath *field.Path) field.ErrorList {
+	if regex == "" {
+		return field.ErrorList{field.Required(fieldPath, "")}
+	}
+	if _, err := regexp2.Compile(regex); err != nil {
+		return field.ErrorList{field.Invalid(fieldPath, regex, fmt.Sprintf("must be a valid regular expression: %v", err))}
 	}
+	if err := ValidateEscapedString(regex, "^/old(.*)$", "^/images/(.*)\\.png$"); err != nil {
+		return field.ErrorList{field.Invalid(fieldPath, regex, err.Error())}
+	}
+	return nil
+}
 
-	// Matches are optional. that's why we don't do fieldCount++
-	if len(route.Matches) > 0 {
-		for i, m := range route.Matches {
-			allErrs = append(allErrs, vsv.validateMatch(m, fieldPath.Child("matches").Index(i), upstreamNames, route.Path)...)
-		}
+func validateRouteStatusZone(statusZone string, fieldPath *field.Path) field.ErrorList {
+	if !nginxTokenRegexp.MatchString(statusZone) {
+		msg := validation.RegexError(nginxTokenErrMsg, nginxTokenFmt, "auto", "tea-api", "cafe/coffee")
+		return field.ErrorList{field.Invalid(fieldPath, statusZone, msg)}
 	}
+	return nil
+}
 
-	if route.RouteSelector != nil {
-		allErrs = append(allErrs, vsv.validateRouteSelector(route.RouteSelector, fieldPath.Child("routeSelector"))...)
-		fieldCount++
+func validateClientIPReturn(clientIPReturn *v1.ClientIPReturn, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if len(clientIPReturn.Ranges) == 0 {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("ranges"), "must specify at least one range"))
 	}
 
-	for i, e := range route.ErrorPages {
-		allErrs = append(allErrs, vsv.validateErrorPage(e, fieldPath.Child("errorPages").Index(i))...)
+	for i, r := range clientIPReturn.Ranges {
+		allErrs = append(allErrs, validateIPorCIDR(r, fieldPath.Child("ranges").Index(i))...)
 	}
 
-	if route.Route != "" {
-		if isRouteFieldForbidden {
-			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("route"), "is not allowed"))
-		} else {
-			allErrs = append(allErrs, validateRouteField(route.Route, fieldPath.Child("route"))...)
-			fieldCount++
-		}
+	if clientIPReturn.Code != 0 {
+		allErrs = append(allErrs, validateActionReturnCode(clientIPReturn.Code, fieldPath.Child("code"))...)
 	}
 
-	if fieldCount != 1 {
-		msg := "must specify exactly one of `action`, `splits`, `route` or `routeSelector`"
-		if isRouteFieldForbidden || len(route.Matches) > 0 {
-			msg = "must specify exactly one of `action` or `splits`"
-		}
+	return allErrs
+}
 
-		allErrs = append(allErrs, field.Invalid(fieldPath, "", msg))
+func validateTarpit(tarpit *v1.Tarpit, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if len(tarpit.Conditions) == 0 {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("conditions"), "must specify at least one condition"))
 	}
 
-	if route.AddHeaderInherit != "" {
-		allErrs = append(allErrs, validateAddHeaderInherit(route.AddHeaderInherit, fieldPath.Child("add-header-inherit"))...)
+	for i, c := range tarpit.Conditions {
+		allErrs = append(allErrs, validateCondition(c, fieldPath.Child("conditions").Index(i))...)
 	}
 
-	allErrs = append(allErrs, validateDos(vsv.isDosEnabled, route.Dos, fieldPath.Child("dos"))...)
+	allErrs = append(allErrs, validateTarpitDelay(tarpit.Delay, fieldPath.Child("delay"))...)
+
+	if tarpit.Code != 0 {
+		allErrs = append(allErrs, validateActionReturnCode(tarpit.Code, fieldPath.Child("code"))...)
+	}
 
 	return allErrs
 }
 
+// maxTarpitDelay limits the time a connection is held by a tarpit.
+const maxTarpitDelay = 60 * time.Second
+
+func validateTarpitDelay(delay string, fieldPath *field.Path) field.ErrorList {
+	if delay == "" {
+		return field.ErrorList{field.Required(fieldPath, "")}
+	}
+
+	d, err := configs.ParseTimeDuration(delay)
+	if err != nil || d <= 0 || d > maxTarpitDelay {
+		return field.ErrorList{field.Invalid(fieldPath, delay, "must be a time between 1ms and 60s, for example, 5s or 500ms")}
+	}
+
+	return nil
+}
+
 func errorPageHasRequiredFields(errorPage v1.ErrorPage) bool {
 	var count int
 
@@ -961,9 +1708,31 @@ func (vsv *VirtualServerValidator) validateErrorPage(errorPage v1.ErrorPage, fie
 	return allErrs
 }
 
+func validatePassthroughErrorCodes(codes []int, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	seen := sets.New[int]()
+
+	for i, c := range codes {
+		idxPath := fieldPath.Index(i)
+		for _, msg := range validation.IsInRange(c, 300, 599) {
+			allErrs = append(allErrs, field.Invalid(idxPath, c, msg))
+		}
+		if seen.Has(c) {
+			allErrs = append(allErrs, field.Duplicate(idxPath, c))
+		}
+		seen.Insert(c)
+	}
+
+	return allErrs
+}
+
 var errorPageReturnBodyVariable = map[string]bool{"upstream_status": true}
 
 func (vsv *VirtualServerValidator) validateErrorPageReturn(r *v1.ErrorPageReturn, fieldPath *field.Path) field.ErrorList {
+	if len(r.Bodies) > 0 {
+		return field.ErrorList{field.Forbidden(fieldPath.Child("bodies"), "is not supported in error pages")}
+	}
+
 	allErrs := vsv.validateActionReturn(&r.ActionReturn, fieldPath, nil, errorPageReturnBodyVariable)
 
 	for i, header := range r.Headers {
@@ -1018,6 +1787,14 @@ func countActions(action *v1.Action) int {
 		count++
 	}
 
+	if action.ProxyPassURL != "" {
+		count++
+	}
+
+	if action.Files != nil {
+		count++
+	}
+
 	return count
 }
 
@@ -1060,7 +1837,7 @@ var validRedirectVariableNames = map[string]bool{
 
 func (vsv *VirtualServerValidator) validateAction(action *v1.Action, fieldPath *field.Path, upstreamNames sets.Set[string], path string, internal bool) field.ErrorList {
 	if countActions(action) != 1 {
-		return field.ErrorList{field.Required(fieldPath, "action must specify exactly one of `pass`, `redirect`, `return` or `proxy`")}
+		return field.ErrorList{field.Required(fieldPath, "action must specify exactly one of `pass`, `redirect`, `return`, `proxy`, `proxyPassURL` or `files`")}
 	}
 
 	allErrs := field.ErrorList{}
@@ -1083,9 +1860,212 @@ func (vsv *VirtualServerValidator) validateAction(action *v1.Action, fieldPath *
 		allErrs = append(allErrs, vsv.validateActionProxy(action.Proxy, fieldPath.Child("proxy"), upstreamNames, path, internal)...)
 	}
 
+	if action.ProxyPassURL != "" {
+		allErrs = append(allErrs, vsv.validateProxyPassURL(action.ProxyPassURL, fieldPath.Child("proxyPassURL"))...)
+	}
+
+	if action.Files != nil {
+		if internal {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("files"), "is not supported in splits or matches"))
+		} else {
+			allErrs = append(allErrs, vsv.validateActionFiles(action.Files, fieldPath.Child("files"))...)
+		}
+	}
+
+	if action.Expires != "" {
+		if action.Redirect != nil || action.Return != nil || action.Files != nil {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("expires"), "can only be used with `pass`, `proxy` or `proxyPassURL`"))
+		} else {
+			allErrs = append(allErrs, validateExpires(action.Expires, fieldPath.Child("expires"))...)
+		}
+	}
+
+	if action.SubFilter != nil {
+		if action.Redirect != nil || action.Return != nil || action.Files != nil {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("subFilter"), "can only be used with `pass`, `proxy` or `proxyPassURL`"))
+		} else {
+			allErrs = append(allErrs, validateActionSubFilter(action.SubFilter, fieldPath.Child("subFilter"))...)
+		}
+	}
+
+	return allErrs
+}
+
+const (
+	tryFileFmt    = `(\$uri|/)(` + nginxTokenFmt + `)?`
+	tryFileErrMsg = "must start with $uri or / and " + nginxTokenErrMsg
+)
+
+var tryFileRegexp = regexp.MustCompile("^" + tryFileFmt + "$")
+
+// validateActionFiles validates the files action. The root must be one of the directories allowed by the
+// -files-action-roots command-line argument or their subdirectory, so the files action is forbidden when none is allowed.
+func (vsv *VirtualServerValidator) validateActionFiles(files *v1.ActionFiles, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	rootErrs := validatePath(files.Root, fieldPath.Child("root"))
+	allErrs = append(allErrs, rootErrs...)
+	if len(rootErrs) == 0 {
+		if slices.Contains(strings.Split(files.Root, "/"), "..") {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("root"), files.Root, "path traversal not allowed, must not contain '..' segments"))
+		} else if len(vsv.filesActionRoots) == 0 {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("root"), "the files action is not enabled, use the -files-action-roots command-line argument"))
+		} else if !isFilesActionRootAllowed(path.Clean(files.Root), vsv.filesActionRoots) {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("root"), fmt.Sprintf("must be one of %s or their subdirectory", strings.Join(vsv.filesActionRoots, ", "))))
+		}
+	}
+
+	for i, f := range files.TryFiles {
+		idxPath := fieldPath.Child("tryFiles").Index(i)
+		if !tryFileRegexp.MatchString(f) {
+			allErrs = append(allErrs, field.Invalid(idxPath, f, validation.RegexError(tryFileErrMsg, tryFileFmt, "$uri", "$uri/", "/default.html")))
+			continue
+		}
+		if slices.Contains(strings.Split(f, "/"), "..") {
+			allErrs = append(allErrs, field.Invalid(idxPath, f, "path traversal not allowed, must not contain '..' segments"))
+		}
+	}
+
+	if code, ok := strings.CutPrefix(files.Fallback, "="); ok {
+		c, err := strconv.Atoi(code)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("fallback"), files.Fallback, "must be a URI or = followed by a status code, for example, =404"))
+		} else {
+			allErrs = append(allErrs, validateActionReturnCode(c, fieldPath.Child("fallback"))...)
+		}
+	} else if files.Fallback != "" {
+		allErrs = append(allErrs, validatePath(files.Fallback, fieldPath.Child("fallback"))...)
+	}
+
+	return allErrs
+}
+
+// isFilesActionRootAllowed checks if the cleaned root is one of the allowed roots or their subdirectory.
+func isFilesActionRootAllowed(root string, allowedRoots []string) bool {
+	for _, r := range allowedRoots {
+		if root == r || strings.HasPrefix(root, r+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+var validExpiresKeywords = map[string]bool{
+	"max":   true,
+	"epoch": true,
+	"off":   true,
+}
+
+func validateExpires(expires string, fieldPath *field.Path) field.ErrorList {
+	if validExpiresKeywords[expires] {
+		return nil
+	}
+	if _, err := configs.ParseTime(expires); err != nil {
+		return field.ErrorList{field.Invalid(fieldPath, expires, "must be a valid time, for example, 30d, or one of max, epoch or off")}
+	}
+	return nil
+}
+
+const (
+	subFilterTypeFmt    = `\*|[a-zA-Z0-9][\w!#&^.+-]*/[\w!#&^.+*-]+`
+	subFilterTypeErrMsg = "must be a MIME type or *"
+)
+
+var subFilterTypeRegexp = regexp.MustCompile("^(" + subFilterTypeFmt + ")$")
+
+func validateActionSubFilter(subFilter *v1.ActionSubFilter, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if len(subFilter.Rules) == 0 {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("rules"), "must include at least one rule"))
+	}
+
+	for i, r := range subFilter.Rules {
+		idxPath := fieldPath.Child("rules").Index(i)
+		if r.From == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("from"), ""))
+		}
+		for _, msg := range isValidHeaderValue(r.From) {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("from"), r.From, msg))
+		}
+		for _, msg := range isValidHeaderValue(r.To) {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("to"), r.To, msg))
+		}
+	}
+
+	for i, t := range subFilter.Types {
+		if !subFilterTypeRegexp.MatchString(t) {
+			msg := validation.RegexError(subFilterTypeErrMsg, subFilterTypeFmt, "text/css", "application/json", "*")
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("types").Index(i), t, msg))
+		}
+	}
+
+	return allErrs
+}
+
+// clusterInternalHostSuffixes includes hostname suffixes that resolve to cluster-internal addresses.
+var clusterInternalHostSuffixes = []string{".svc", ".cluster.local", ".local", ".internal"}
+
+func (vsv *VirtualServerValidator) validateProxyPassURL(rawURL string, fieldPath *field.Path) field.ErrorList {
+	if ContainsDangerousChars(rawURL) {
+		return field.ErrorList{field.Invalid(fieldPath, rawURL, "url contains dangerous characters")}
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return field.ErrorList{field.Invalid(fieldPath, rawURL, err.Error())}
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return field.ErrorList{field.Invalid(fieldPath, rawURL, "scheme must be http or https")}
+	}
+	if u.User != nil {
+		return field.ErrorList{field.Invalid(fieldPath, rawURL, "must not contain user information")}
+	}
+	if u.Hostname() == "" {
+		return field.ErrorList{field.Invalid(fieldPath, rawURL, "hostname required")}
+	}
+	if (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" {
+		return field.ErrorList{field.Invalid(fieldPath, rawURL, "must not contain a path, query or fragment")}
+	}
+
+	allErrs := field.ErrorList{}
+	if port := u.Port(); port != "" {
+		allErrs = append(allErrs, validatePortNumber(port, fieldPath)...)
+	}
+
+	host := u.Hostname()
+	ip := net.ParseIP(host)
+	if ip == nil {
+		allErrs = append(allErrs, validateSSLName(host, fieldPath)...)
+	}
+
+	if !vsv.isInternalProxyPassURLAllowed && isClusterInternalHost(host, ip) {
+		allErrs = append(allErrs, field.Forbidden(fieldPath, "cluster-internal addresses are not allowed"))
+	}
+
 	return allErrs
 }
 
+// isClusterInternalHost checks if the host is a loopback, private or link-local address,
+// or a hostname that resolves inside the cluster.
+func isClusterInternalHost(host string, ip net.IP) bool {
+	if ip != nil {
+		return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsUnspecified()
+	}
+
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "localhost" || !strings.Contains(host, ".") {
+		return true
+	}
+	for _, suffix := range clusterInternalHostSuffixes {
+		if strings.HasSuffix(host, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (vsv *VirtualServerValidator) validateActionRedirect(redirect *v1.ActionRedirect, fieldPath *field.Path, validVars map[string]bool) field.ErrorList {
 	allErrs := vsv.validateRedirectURL(redirect.URL, fieldPath.Child("url"), validVars)
 
@@ -1131,7 +2111,15 @@ func validateActionReturnCode(code int, fieldPath *field.Path) field.ErrorList {
 }
 
 func (vsv *VirtualServerValidator) validateActionReturn(r *v1.ActionReturn, fieldPath *field.Path, specialValidVars []string, validVars map[string]bool) field.ErrorList {
+	if len(r.Bodies) > 0 {
+		return vsv.validateActionReturnBodies(r, fieldPath, specialValidVars, validVars)
+	}
+
 	if r.Body == "" {
+		// the responses with these status codes have no body
+		if r.Code == 204 || r.Code == 304 {
+			return nil
+		}
 		return field.ErrorList{field.Required(fieldPath.Child("body"), "")}
 	}
 
@@ -1139,12 +2127,68 @@ func (vsv *VirtualServerValidator) validateActionReturn(r *v1.ActionReturn, fiel
 	if r.Type != "" {
 		allErrs = append(allErrs, validateActionReturnType(r.Type, fieldPath.Child("type"))...)
 	}
-	if r.Code != 0 {
+	if r.Code != 0 && r.Code != 304 {
+		allErrs = append(allErrs, validateActionReturnCode(r.Code, fieldPath.Child("code"))...)
+	}
+	return allErrs
+}
+
+func (vsv *VirtualServerValidator) validateActionReturnBodies(r *v1.ActionReturn, fieldPath *field.Path, specialValidVars []string, validVars map[string]bool) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if r.Body != "" {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("body"), "cannot be used together with bodies"))
+	}
+	if r.Type != "" {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("type"), "cannot be used together with bodies"))
+	}
+	if r.Code != 0 && r.Code != 304 {
 		allErrs = append(allErrs, validateActionReturnCode(r.Code, fieldPath.Child("code"))...)
 	}
+
+	types := sets.Set[string]{}
+	for i, b := range r.Bodies {
+		idxPath := fieldPath.Child("bodies").Index(i)
+
+		if b.Type == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("type"), ""))
+		} else if !returnBodyTypeRegexp.MatchString(b.Type) {
+			msg := validation.RegexError(returnBodyTypeErr, returnBodyTypeFmt, "application/json", "text/html")
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("type"), b.Type, msg))
+		} else if types.Has(strings.ToLower(b.Type)) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("type"), b.Type))
+		} else {
+			types.Insert(strings.ToLower(b.Type))
+		}
+
+		if b.Body == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("body"), ""))
+		} else {
+			allErrs = append(allErrs, validateEscapedStringWithVariables(b.Body, idxPath.Child("body"), specialValidVars, validVars, vsv.isPlus)...)
+		}
+	}
+
 	return allErrs
 }
 
+const (
+	returnBodyTypeFmt = `[A-Za-z0-9!#$&^_.+-]+/[A-Za-z0-9!#$&^_.+-]+`
+	returnBodyTypeErr = `must be a MIME type in the type/subtype format without parameters`
+)
+
+var returnBodyTypeRegexp = regexp.MustCompile("^" + returnBodyTypeFmt + "$")
+
+func validateDefaultType(defaultType string, fieldPath *field.Path) field.ErrorList {
+	if defaultType == "" {
+		return nil
+	}
+	if !returnBodyTypeRegexp.MatchString(defaultType) {
+		msg := validation.RegexError(returnBodyTypeErr, returnBodyTypeFmt, "application/json", "text/html")
+		return field.ErrorList{field.Invalid(fieldPath, defaultType, msg)}
+	}
+	return nil
+}
+
 func validateEscapedStringWithVariables(body string, fieldPath *field.Path, specialValidVars []string, validVars map[string]bool, isPlus bool) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -1199,6 +2243,8 @@ func (vsv *VirtualServerValidator) validateActionProxy(p *v1.ActionProxy, fieldP
 	allErrs := validateReferencedUpstream(p.Upstream, fieldPath.Child("upstream"), upstreamNames)
 	allErrs = append(allErrs, vsv.validateActionProxyRequestHeaders(p.RequestHeaders, fieldPath.Child("requestHeaders"))...)
 	allErrs = append(allErrs, vsv.validateActionProxyResponseHeaders(p.ResponseHeaders, fieldPath.Child("responseHeaders"))...)
+	allErrs = append(allErrs, validateActionProxyMethod(p.ProxyMethod, fieldPath.Child("method"))...)
+	allErrs = append(allErrs, validateTime(p.ReadTimeout, fieldPath.Child("readTimeout"))...)
 
 	if strings.HasPrefix(path, "~") || internal {
 		allErrs = append(allErrs, validateActionProxyRewritePathForRegexp(p.RewritePath, fieldPath.Child("rewritePath"))...)
@@ -1209,6 +2255,29 @@ func (vsv *VirtualServerValidator) validateActionProxy(p *v1.ActionProxy, fieldP
 	return allErrs
 }
 
+var validProxyMethods = map[string]bool{
+	"GET":     true,
+	"HEAD":    true,
+	"POST":    true,
+	"PUT":     true,
+	"DELETE":  true,
+	"OPTIONS": true,
+	"TRACE":   true,
+	"PATCH":   true,
+}
+
+func validateActionProxyMethod(method string, fieldPath *field.Path) field.ErrorList {
+	if method == "" {
+		return nil
+	}
+
+	if !validProxyMethods[method] {
+		msg := fmt.Sprintf("not a valid method. Accepted methods are: %v", mapToPrettyString(validProxyMethods))
+		return field.ErrorList{field.Invalid(fieldPath, method, msg)}
+	}
+	return nil
+}
+
 func validateStringNoVariables(s string, fieldPath *field.Path) field.ErrorList {
 	for i, char := range s {
 		charLen := len(string(char))
@@ -1290,7 +2359,33 @@ var actionProxyHeaderVariables = map[string]bool{
 
 var actionProxyHeaderSpecialVariables = []string{"arg_", "http_", "cookie_", "jwt_claim_", "jwt_header_"}
 
+// actionProxyRequestHeaderSpecialVariables also includes the variables generated for the VirtualServer, like the variables
+// of the matches route maps. The configurator ignores the headers that reference a generated variable that is not defined.
+var actionProxyRequestHeaderSpecialVariables = append(slices.Clone(actionProxyHeaderSpecialVariables), "vs_")
+
+// responseHeaderDebugVariables are the variables of the upstream that the response headers can reference for debugging.
+// The configurator ignores the headers that reference them unless debugHeaders of the VirtualServer is enabled.
+var responseHeaderDebugVariables = map[string]bool{
+	"upstream_addr":          true,
+	"upstream_status":        true,
+	"upstream_connect_time":  true,
+	"upstream_header_time":   true,
+	"upstream_response_time": true,
+	"upstream_cache_status":  true,
+}
+
+var actionProxyResponseHeaderVariables = func() map[string]bool {
+	vars := maps.Clone(actionProxyHeaderVariables)
+	maps.Copy(vars, responseHeaderDebugVariables)
+	return vars
+}()
+
+// validateActionProxyHeader validates a header added to the responses.
 func (vsv *VirtualServerValidator) validateActionProxyHeader(h v1.Header, fieldPath *field.Path) field.ErrorList {
+	return vsv.validateActionProxyHeaderWithVariables(h, fieldPath, actionProxyHeaderSpecialVariables, actionProxyResponseHeaderVariables)
+}
+
+func (vsv *VirtualServerValidator) validateActionProxyHeaderWithVariables(h v1.Header, fieldPath *field.Path, specialVars []string, vars map[string]bool) field.ErrorList {
 	allErrs := field.ErrorList{}
 
 	if h.Name == "" {
@@ -1302,7 +2397,7 @@ func (vsv *VirtualServerValidator) validateActionProxyHeader(h v1.Header, fieldP
 	}
 
 	allErrs = append(allErrs, validateEscapedStringWithVariables(h.Value, fieldPath.Child("value"),
-		actionProxyHeaderSpecialVariables, actionProxyHeaderVariables, vsv.isPlus)...)
+		specialVars, vars, vsv.isPlus)...)
 
 	return allErrs
 }
@@ -1314,7 +2409,7 @@ func (vsv *VirtualServerValidator) validateActionProxyRequestHeaders(requestHead
 
 	allErrs := field.ErrorList{}
 	for i, header := range requestHeaders.Set {
-		allErrs = append(allErrs, vsv.validateActionProxyHeader(header, fieldPath.Index(i))...)
+		allErrs = append(allErrs, vsv.validateActionProxyHeaderWithVariables(header, fieldPath.Index(i), actionProxyRequestHeaderSpecialVariables, actionProxyHeaderVariables)...)
 	}
 	return allErrs
 }
@@ -1380,6 +2475,7 @@ func (vsv *VirtualServerValidator) validateSplits(splits []v1.Split, fieldPath *
 
 	allErrs := field.ErrorList{}
 	totalWeight := 0
+	promoted := false
 	for i, s := range splits {
 		idxPath := fieldPath.Index(i)
 
@@ -1387,6 +2483,13 @@ func (vsv *VirtualServerValidator) validateSplits(splits []v1.Split, fieldPath *
 			allErrs = append(allErrs, field.Invalid(idxPath.Child("weight"), s.Weight, msg))
 		}
 
+		if s.Promoted {
+			if promoted {
+				allErrs = append(allErrs, field.Forbidden(idxPath.Child("promoted"), "only one split can be promoted"))
+			}
+			promoted = true
+		}
+
 		if s.Action == nil {
 			allErrs = append(allErrs, field.Required(idxPath.Child("action"), ""))
 		} else {
@@ -1467,7 +2570,7 @@ func validateRegexPath(path string, fieldPath *field.Path) field.ErrorList {
 }
 
 const (
-	grpcFmt    = `[^\s{};]*`
+	grpcFmt    = internalValidation.GRPCServiceFmt
 	grpcErrMsg = "must not include any whitespace character, `{`, `}`, or `;`"
 )
 
@@ -1545,8 +2648,13 @@ func validateCondition(condition v1.Condition, fieldPath *field.Path) field.Erro
 		fieldCount++
 	}
 
+	if condition.Geo != "" {
+		allErrs = append(allErrs, validateDNS1035Label(condition.Geo, fieldPath.Child("geo"))...)
+		fieldCount++
+	}
+
 	if fieldCount != 1 {
-		allErrs = append(allErrs, field.Invalid(fieldPath, "", "must specify exactly one of: `header`, `cookie`, `argument` or `variable`"))
+		allErrs = append(allErrs, field.Invalid(fieldPath, "", "must specify exactly one of: `header`, `cookie`, `argument`, `variable` or `geo`"))
 	}
 
 	for _, msg := range isValidMatchValue(condition.Value) {
@@ -1598,6 +2706,8 @@ var validVariableNames = map[string]bool{
 	"$request_uri":    true,
 	"$request_method": true,
 	"$scheme":         true,
+	// requires an IngressMTLS policy that requests the client certificates optionally
+	"$ssl_client_verify": true,
 }
 
 func validateVariableName(name string, fieldPath *field.Path) field.ErrorList {
@@ -1640,6 +2750,14 @@ func (vsv *VirtualServerValidator) validateVirtualServerRouteSpec(spec *v1.Virtu
 
 	allErrs = append(allErrs, vsv.validateVirtualServerRouteSubroutes(spec.Subroutes, fieldPath.Child("subroutes"), upstreamNames, vsPaths, namespace)...)
 
+	if spec.StatusZone != "" {
+		if !vsv.isPlus {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("statusZone"), "status zones of VirtualServerRoutes are only supported in NGINX Plus"))
+		} else {
+			allErrs = append(allErrs, validateRouteStatusZone(spec.StatusZone, fieldPath.Child("statusZone"))...)
+		}
+	}
+
 	return allErrs
 }
 
@@ -1830,6 +2948,14 @@ func rejectPlusResourcesInOSS(upstream v1.Upstream, idxPath *field.Path, isPlus
 		allErrs = append(allErrs, field.Forbidden(idxPath.Child("ntlm"), "NTLM is only supported in NGINX Plus"))
 	}
 
+	if upstream.Drain {
+		allErrs = append(allErrs, field.Forbidden(idxPath.Child("drain"), "drain is only supported in NGINX Plus"))
+	}
+
+	if upstream.Failover != nil {
+		allErrs = append(allErrs, field.Forbidden(idxPath.Child("failover"), "failover is only supported in NGINX Plus"))
+	}
+
 	return allErrs
 }
 
@@ -9,6 +9,8 @@ package v1
 type AccessControlApplyConfiguration struct {
 	Allow []string `json:"allow,omitempty"`
 	Deny  []string `json:"deny,omitempty"`
+	// Sets how the access control combines with the authentication policies applied in the same context. With any, a request from an allowed IP address bypasses authentication, while requests from other addresses must still authenticate. Requires allow. The default is all.
+	Satisfy *string `json:"satisfy,omitempty"`
 }
 
 // AccessControlApplyConfiguration constructs a declarative configuration of the AccessControl type for use with
@@ -36,3 +38,11 @@ func (b *AccessControlApplyConfiguration) WithDeny(values ...string) *AccessCont
 	}
 	return b
 }
+
+// WithSatisfy sets the Satisfy field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Satisfy field is set to the value of the last call.
+func (b *AccessControlApplyConfiguration) WithSatisfy(value string) *AccessControlApplyConfiguration {
+	b.Satisfy = &value
+	return b
+}
@@ -15,6 +15,14 @@ type ActionApplyConfiguration struct {
 	Return *ActionReturnApplyConfiguration `json:"return,omitempty"`
 	// Passes requests to an upstream with the ability to modify the request/response (for example, rewrite the URI or modify the headers).
 	Proxy *ActionProxyApplyConfiguration `json:"proxy,omitempty"`
+	// Passes requests to a literal URL without the need for a Service. For example, https://example.com. The URL must not include a path, query or fragment. Cluster-internal addresses are rejected unless allowed with the -allow-internal-proxy-pass-url command-line argument.
+	ProxyPassURL *string `json:"proxyPassURL,omitempty"`
+	// Serves static files from a directory mounted into the NGINX Ingress Controller pods. For example, it can be used to host a single-page application with a fallback to /index.html.
+	Files *ActionFilesApplyConfiguration `json:"files,omitempty"`
+	// Sets the expires directive which adds the Expires and Cache-Control response headers. The allowed values are a time, for example, 30d, or one of max, epoch or off. Applies to the pass, proxy and proxyPassURL actions.
+	Expires *string `json:"expires,omitempty"`
+	// Replaces strings in the bodies of the responses, for example, the internal URLs of a legacy application with the public host. Applies to the pass, proxy and proxyPassURL actions.
+	SubFilter *ActionSubFilterApplyConfiguration `json:"subFilter,omitempty"`
 }
 
 // ActionApplyConfiguration constructs a declarative configuration of the Action type for use with
@@ -54,3 +62,35 @@ func (b *ActionApplyConfiguration) WithProxy(value *ActionProxyApplyConfiguratio
 	b.Proxy = value
 	return b
 }
+
+// WithProxyPassURL sets the ProxyPassURL field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ProxyPassURL field is set to the value of the last call.
+func (b *ActionApplyConfiguration) WithProxyPassURL(value string) *ActionApplyConfiguration {
+	b.ProxyPassURL = &value
+	return b
+}
+
+// WithFiles sets the Files field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Files field is set to the value of the last call.
+func (b *ActionApplyConfiguration) WithFiles(value *ActionFilesApplyConfiguration) *ActionApplyConfiguration {
+	b.Files = value
+	return b
+}
+
+// WithExpires sets the Expires field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Expires field is set to the value of the last call.
+func (b *ActionApplyConfiguration) WithExpires(value string) *ActionApplyConfiguration {
+	b.Expires = &value
+	return b
+}
+
+// WithSubFilter sets the SubFilter field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the SubFilter field is set to the value of the last call.
+func (b *ActionApplyConfiguration) WithSubFilter(value *ActionSubFilterApplyConfiguration) *ActionApplyConfiguration {
+	b.SubFilter = value
+	return b
+}
@@ -15,6 +15,12 @@ type ActionProxyApplyConfiguration struct {
 	RequestHeaders *ProxyRequestHeadersApplyConfiguration `json:"requestHeaders,omitempty"`
 	// The response headers modifications.
 	ResponseHeaders *ProxyResponseHeadersApplyConfiguration `json:"responseHeaders,omitempty"`
+	// The HTTP method used for the requests passed to the upstream instead of the method of the client request. Allowed values are GET, HEAD, POST, PUT, DELETE, OPTIONS, TRACE and PATCH. Note: the request body of the client request is passed to the upstream unchanged, unless passRequestBody is false.
+	ProxyMethod *string `json:"method,omitempty"`
+	// Enables or disables passing the request body of the client request to the upstream. When disabled, the Content-Length request header is cleared, unless it is set in requestHeaders. Default is true.
+	ProxyPassRequestBody *bool `json:"passRequestBody,omitempty"`
+	// The timeout for reading a response from the upstream server, which overrides the read-timeout of the upstream for the requests of the action. For example, a longer timeout for the long polling requests selected by a match. The default is the read-timeout of the upstream.
+	ReadTimeout *string `json:"readTimeout,omitempty"`
 }
 
 // ActionProxyApplyConfiguration constructs a declarative configuration of the ActionProxy type for use with
@@ -54,3 +60,27 @@ func (b *ActionProxyApplyConfiguration) WithResponseHeaders(value *ProxyResponse
 	b.ResponseHeaders = value
 	return b
 }
+
+// WithProxyMethod sets the ProxyMethod field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ProxyMethod field is set to the value of the last call.
+func (b *ActionProxyApplyConfiguration) WithProxyMethod(value string) *ActionProxyApplyConfiguration {
+	b.ProxyMethod = &value
+	return b
+}
+
+// WithProxyPassRequestBody sets the ProxyPassRequestBody field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ProxyPassRequestBody field is set to the value of the last call.
+func (b *ActionProxyApplyConfiguration) WithProxyPassRequestBody(value bool) *ActionProxyApplyConfiguration {
+	b.ProxyPassRequestBody = &value
+	return b
+}
+
+// WithReadTimeout sets the ReadTimeout field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ReadTimeout field is set to the value of the last call.
+func (b *ActionProxyApplyConfiguration) WithReadTimeout(value string) *ActionProxyApplyConfiguration {
+	b.ReadTimeout = &value
+	return b
+}
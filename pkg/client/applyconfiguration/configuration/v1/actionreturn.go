@@ -7,14 +7,16 @@ package v1
 //
 // ActionReturn defines a return in an Action.
 type ActionReturnApplyConfiguration struct {
-	// The status code of the response. The allowed values are: 2XX, 4XX or 5XX. The default is 200.
+	// The status code of the response. The allowed values are: 2XX, 304, 4XX or 5XX. The default is 200. The responses with the 204 and 304 status codes have no body and type.
 	Code *int `json:"code,omitempty"`
 	// The MIME type of the response. The default is text/plain.
 	Type *string `json:"type,omitempty"`
-	// The body of the response. Supports NGINX variables*. Variables must be enclosed in curly brackets. For example: Request is ${request_uri}\n.
+	// The body of the response. Supports NGINX variables*. Variables must be enclosed in curly brackets. For example: Request is ${request_uri}\n. Required unless the code is 204 or 304.
 	Body *string `json:"body,omitempty"`
 	// The custom headers of the response.
 	Headers []HeaderApplyConfiguration `json:"headers,omitempty"`
+	// The bodies of the response for different content types. The first body, in the order of the list, whose type is in the Accept header of the request is returned, or the first body if none of the types are. The q-values of the Accept header are ignored, except that the types with q=0 are not selected. Cannot be used together with type and body. Supported in the return action and the return of noEndpoints only, not in the returns of error pages.
+	Bodies []ReturnBodyApplyConfiguration `json:"bodies,omitempty"`
 }
 
 // ActionReturnApplyConfiguration constructs a declarative configuration of the ActionReturn type for use with
@@ -59,3 +61,16 @@ func (b *ActionReturnApplyConfiguration) WithHeaders(values ...*HeaderApplyConfi
 	}
 	return b
 }
+
+// WithBodies adds the given value to the Bodies field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Bodies field.
+func (b *ActionReturnApplyConfiguration) WithBodies(values ...*ReturnBodyApplyConfiguration) *ActionReturnApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithBodies")
+		}
+		b.Bodies = append(b.Bodies, *values[i])
+	}
+	return b
+}
@@ -11,6 +11,8 @@ type APIKeyApplyConfiguration struct {
 	SuppliedIn *SuppliedInApplyConfiguration `json:"suppliedIn,omitempty"`
 	// The key to which the API key is applied. Can contain text, variables, or a combination of them. Accepted variables are $http_, $arg_, $cookie_.
 	ClientSecret *string `json:"clientSecret,omitempty"`
+	// Sets the status code to return in response to requests with a missing or invalid API Key. Must fall into the range 400..499. By default, 401 is returned for a missing API Key and 403 for an invalid one.
+	RejectCode *int `json:"rejectCode,omitempty"`
 }
 
 // APIKeyApplyConfiguration constructs a declarative configuration of the APIKey type for use with
@@ -34,3 +36,11 @@ func (b *APIKeyApplyConfiguration) WithClientSecret(value string) *APIKeyApplyCo
 	b.ClientSecret = &value
 	return b
 }
+
+// WithRejectCode sets the RejectCode field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the RejectCode field is set to the value of the last call.
+func (b *APIKeyApplyConfiguration) WithRejectCode(value int) *APIKeyApplyConfiguration {
+	b.RejectCode = &value
+	return b
+}
@@ -11,6 +11,8 @@ type BasicAuthApplyConfiguration struct {
 	Realm *string `json:"realm,omitempty"`
 	// The name of the Kubernetes secret that stores the Htpasswd configuration. It must be in the same namespace as the Policy resource. The secret must be of the type nginx.org/htpasswd, and the config must be stored in the secret under the key htpasswd, otherwise the secret will be rejected as invalid.
 	Secret *string `json:"secret,omitempty"`
+	// A list of users that can authenticate, specified instead of the secret. Intended for a small number of users; for more users, use the secret.
+	Users []BasicAuthUserApplyConfiguration `json:"users,omitempty"`
 }
 
 // BasicAuthApplyConfiguration constructs a declarative configuration of the BasicAuth type for use with
@@ -34,3 +36,16 @@ func (b *BasicAuthApplyConfiguration) WithSecret(value string) *BasicAuthApplyCo
 	b.Secret = &value
 	return b
 }
+
+// WithUsers adds the given value to the Users field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Users field.
+func (b *BasicAuthApplyConfiguration) WithUsers(values ...*BasicAuthUserApplyConfiguration) *BasicAuthApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithUsers")
+		}
+		b.Users = append(b.Users, *values[i])
+	}
+	return b
+}
@@ -31,6 +31,7 @@ type CacheApplyConfiguration struct {
 	// GET and HEAD are always cached by default even if not specified.
 	// Maximum of 3 items allowed. Examples: ["GET"], ["GET", "HEAD", "POST"].
 	// Invalid methods: PUT, DELETE, PATCH, etc.
+	// When POST is allowed, the cacheKey should include $request_body, so that POST requests with different bodies do not share the cached responses.
 	AllowedMethods []string `json:"allowedMethods,omitempty"`
 	// Time defines the default cache time. Required when allowedCodes is specified.
 	// Must be a number followed by a time unit:
@@ -73,7 +74,7 @@ type CacheApplyConfiguration struct {
 	// Must not contain command execution patterns: $(, `, ;, &&, ||
 	CacheKey *string `json:"cacheKey,omitempty"`
 	// CacheUseStale determines in which cases a stale cached response can be used (proxy_cache_use_stale).
-	// Valid parameters: error, timeout, invalid_header, updating, http_500, http_502, http_503, http_504, http_403, http_404, http_429, off.
+	// Valid parameters: error, timeout, invalid_header, updating, http_500, http_502, http_503, http_504, http_403, http_404, http_429, off. The off parameter cannot be combined with other parameters.
 	CacheUseStale []string `json:"cacheUseStale,omitempty"`
 	// CacheRevalidate enables revalidation of expired cache items using conditional requests (proxy_cache_revalidate).
 	// Uses "If-Modified-Since" and "If-None-Match" header fields.
@@ -15,6 +15,8 @@ type ConditionApplyConfiguration struct {
 	Argument *string `json:"argument,omitempty"`
 	// The name of an NGINX variable. Must start with $.
 	Variable *string `json:"variable,omitempty"`
+	// The name of a geo of the VirtualServer. The condition is matched against the value of the geo for the client IP address of the request.
+	Geo *string `json:"geo,omitempty"`
 	// The value to match the condition against.
 	Value *string `json:"value,omitempty"`
 }
@@ -57,6 +59,14 @@ func (b *ConditionApplyConfiguration) WithVariable(value string) *ConditionApply
 	return b
 }
 
+// WithGeo sets the Geo field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Geo field is set to the value of the last call.
+func (b *ConditionApplyConfiguration) WithGeo(value string) *ConditionApplyConfiguration {
+	b.Geo = &value
+	return b
+}
+
 // WithValue sets the Value field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the Value field is set to the value of the last call.
@@ -23,7 +23,7 @@ type EgressMTLSApplyConfiguration struct {
 	TrustedCertSecret *string `json:"trustedCertSecret,omitempty"`
 	// Enables passing of the server name through Server Name Indication extension.
 	ServerName *bool `json:"serverName,omitempty"`
-	// Allows overriding the server name used to verify the certificate of the upstream HTTPS server.
+	// Allows overriding the server name used to verify the certificate of the upstream HTTPS server. Can be a variable of the request, for example ${http_x_tenant}, to choose the server name per request, in which case the server name is also passed through Server Name Indication extension. Accepted variables are $host, $ssl_server_name, $http_, $arg_ and $cookie_.
 	SSLName *string `json:"sslName,omitempty"`
 }
 
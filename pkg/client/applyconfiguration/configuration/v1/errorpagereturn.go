@@ -52,3 +52,16 @@ func (b *ErrorPageReturnApplyConfiguration) WithHeaders(values ...*HeaderApplyCo
 	}
 	return b
 }
+
+// WithBodies adds the given value to the Bodies field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Bodies field.
+func (b *ErrorPageReturnApplyConfiguration) WithBodies(values ...*ReturnBodyApplyConfiguration) *ErrorPageReturnApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithBodies")
+		}
+		b.ActionReturnApplyConfiguration.Bodies = append(b.ActionReturnApplyConfiguration.Bodies, *values[i])
+	}
+	return b
+}
@@ -29,13 +29,15 @@ type HealthCheckApplyConfiguration struct {
 	ReadTimeout *string `json:"read-timeout,omitempty"`
 	// The timeout for transmitting a request to an upstream server. By default, the send-timeout of the upstream is used.
 	SendTimeout *string `json:"send-timeout,omitempty"`
-	// The request headers used for health check requests. NGINX Plus always sets the Host, User-Agent and Connection headers for health check requests.
+	// The request headers used for health check requests. NGINX Plus always sets the Host, User-Agent and Connection headers for health check requests. The values can contain the variables ${host}, ${server_name}, ${hostname}, ${proxy_host}, ${nginx_version}, ${pid}, ${msec} and ${time_iso8601}. The values cannot reference Secrets, as they are written to the NGINX configuration in plain text.
 	Headers []HeaderApplyConfiguration `json:"headers,omitempty"`
 	// The expected response status codes of a health check. By default, the response should have status code 2xx or 3xx. Examples: "200", "! 500", "301-303 307". This not supported for gRPC type upstreams.
 	StatusMatch *string `json:"statusMatch,omitempty"`
+	// The name of a health check match defined in the healthCheckMatches of the VirtualServer. Cannot be used together with statusMatch. This not supported for gRPC type upstreams.
+	Match *string `json:"match,omitempty"`
 	// The expected gRPC status code of the upstream server response to the Check method. Configure this field only if your gRPC services do not implement the gRPC health checking protocol. For example, configure 12 if the upstream server responds with 12 (UNIMPLEMENTED) status code. Only valid on gRPC type upstreams.
 	GRPCStatus *int `json:"grpcStatus,omitempty"`
-	// The gRPC service to be monitored on the upstream server. Only valid on gRPC type upstreams.
+	// The gRPC service to be monitored on the upstream server. Only valid on gRPC type upstreams. If not set and grpcStatus is not set, the service set by the health-check-grpc-service ConfigMap key is used. By default, the service is not set.
 	GRPCService *string `json:"grpcService,omitempty"`
 	// Require every newly added server to pass all configured health checks before NGINX Plus sends traffic to it. If this is not specified, or is set to false, the server will be initially considered healthy. When combined with slow-start, it gives a new server more time to connect to databases and “warm up” before being asked to handle their full share of traffic.
 	Mandatory *bool `json:"mandatory,omitempty"`
@@ -43,6 +45,8 @@ type HealthCheckApplyConfiguration struct {
 	Persistent *bool `json:"persistent,omitempty"`
 	// Enables keepalive connections for health checks and specifies the time during which requests can be processed through one keepalive connection. The default is 60s.
 	KeepaliveTime *string `json:"keepalive-time,omitempty"`
+	// Disables keepalive connections for health checks, so that the connection of every probe is closed. Use it for upstream servers that leak the connections of probes. Can't be used together with keepalive-time. The default is false.
+	DisableKeepalive *bool `json:"disable-keepalive,omitempty"`
 }
 
 // HealthCheckApplyConfiguration constructs a declarative configuration of the HealthCheck type for use with
@@ -160,6 +164,14 @@ func (b *HealthCheckApplyConfiguration) WithStatusMatch(value string) *HealthChe
 	return b
 }
 
+// WithMatch sets the Match field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Match field is set to the value of the last call.
+func (b *HealthCheckApplyConfiguration) WithMatch(value string) *HealthCheckApplyConfiguration {
+	b.Match = &value
+	return b
+}
+
 // WithGRPCStatus sets the GRPCStatus field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the GRPCStatus field is set to the value of the last call.
@@ -199,3 +211,11 @@ func (b *HealthCheckApplyConfiguration) WithKeepaliveTime(value string) *HealthC
 	b.KeepaliveTime = &value
 	return b
 }
+
+// WithDisableKeepalive sets the DisableKeepalive field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the DisableKeepalive field is set to the value of the last call.
+func (b *HealthCheckApplyConfiguration) WithDisableKeepalive(value bool) *HealthCheckApplyConfiguration {
+	b.DisableKeepalive = &value
+	return b
+}
@@ -11,6 +11,8 @@ type IngressMTLSApplyConfiguration struct {
 	ClientCertSecret *string `json:"clientCertSecret,omitempty"`
 	// The file name of the Certificate Revocation List. NGINX Ingress Controller will look for this file in /etc/nginx/secrets
 	CrlFileName *string `json:"crlFileName,omitempty"`
+	// The file names of the Certificate Revocation Lists. NGINX Ingress Controller will look for these files in /etc/nginx/secrets and combine them into a single file, because NGINX supports only one CRL file. Cannot be used together with crlFileName.
+	CrlFileNames []string `json:"crlFileNames,omitempty"`
 	// Verification for the client. Possible values are "on", "off", "optional", "optional_no_ca". The default is "on".
 	VerifyClient *string `json:"verifyClient,omitempty"`
 	// Sets the verification depth in the client certificates chain. The default is 1.
@@ -39,6 +41,16 @@ func (b *IngressMTLSApplyConfiguration) WithCrlFileName(value string) *IngressMT
 	return b
 }
 
+// WithCrlFileNames adds the given value to the CrlFileNames field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the CrlFileNames field.
+func (b *IngressMTLSApplyConfiguration) WithCrlFileNames(values ...string) *IngressMTLSApplyConfiguration {
+	for i := range values {
+		b.CrlFileNames = append(b.CrlFileNames, values[i])
+	}
+	return b
+}
+
 // WithVerifyClient sets the VerifyClient field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the VerifyClient field is set to the value of the last call.
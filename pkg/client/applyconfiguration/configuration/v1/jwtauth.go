@@ -13,6 +13,8 @@ type JWTAuthApplyConfiguration struct {
 	Secret *string `json:"secret,omitempty"`
 	// The token specifies a variable that contains the JSON Web Token. By default the JWT is passed in the Authorization header as a Bearer Token. JWT may be also passed as a cookie or a part of a query string, for example: $cookie_auth_token. Accepted variables are $http_, $arg_, $cookie_.
 	Token *string `json:"token,omitempty"`
+	// The name of the cookie that contains the JSON Web Token. For example, session. Cannot be used together with token.
+	TokenCookie *string `json:"tokenCookie,omitempty"`
 	// The remote URI where the request will be sent to retrieve JSON Web Key set
 	JwksURI *string `json:"jwksURI,omitempty"`
 	// Enables in-memory caching of JWKS (JSON Web Key Sets) that are obtained from the jwksURI and sets a valid time for expiration.
@@ -27,6 +29,12 @@ type JWTAuthApplyConfiguration struct {
 	TrustedCertSecret *string `json:"trustedCertSecret,omitempty"`
 	// Sets the verification depth in the JWKS server certificates chain. The default is 1.
 	SSLVerifyDepth *int `json:"sslVerifyDepth,omitempty"`
+	// The list of audiences of the JWT. The token is rejected with the 401 status code if its aud claim does not contain any of them. Requires NGINX Plus.
+	Audiences []string `json:"audiences,omitempty"`
+	// The issuer of the JWT. The token is rejected with the 401 status code if its iss claim does not match it. Requires NGINX Plus.
+	Issuer *string `json:"issuer,omitempty"`
+	// The addresses of the DNS servers used to resolve the host of the jwksURI, for example, a local forwarder that sends the queries to the upstream DNS servers over TLS. An address may include a port. By default, the resolver configured with the resolver-addresses ConfigMap key is used. Requires NGINX Plus.
+	Resolvers []string `json:"resolvers,omitempty"`
 }
 
 // JWTAuthApplyConfiguration constructs a declarative configuration of the JWTAuth type for use with
@@ -59,6 +67,14 @@ func (b *JWTAuthApplyConfiguration) WithToken(value string) *JWTAuthApplyConfigu
 	return b
 }
 
+// WithTokenCookie sets the TokenCookie field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the TokenCookie field is set to the value of the last call.
+func (b *JWTAuthApplyConfiguration) WithTokenCookie(value string) *JWTAuthApplyConfiguration {
+	b.TokenCookie = &value
+	return b
+}
+
 // WithJwksURI sets the JwksURI field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the JwksURI field is set to the value of the last call.
@@ -114,3 +130,31 @@ func (b *JWTAuthApplyConfiguration) WithSSLVerifyDepth(value int) *JWTAuthApplyC
 	b.SSLVerifyDepth = &value
 	return b
 }
+
+// WithAudiences adds the given value to the Audiences field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Audiences field.
+func (b *JWTAuthApplyConfiguration) WithAudiences(values ...string) *JWTAuthApplyConfiguration {
+	for i := range values {
+		b.Audiences = append(b.Audiences, values[i])
+	}
+	return b
+}
+
+// WithIssuer sets the Issuer field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Issuer field is set to the value of the last call.
+func (b *JWTAuthApplyConfiguration) WithIssuer(value string) *JWTAuthApplyConfiguration {
+	b.Issuer = &value
+	return b
+}
+
+// WithResolvers adds the given value to the Resolvers field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Resolvers field.
+func (b *JWTAuthApplyConfiguration) WithResolvers(values ...string) *JWTAuthApplyConfiguration {
+	for i := range values {
+		b.Resolvers = append(b.Resolvers, values[i])
+	}
+	return b
+}
@@ -19,6 +19,8 @@ type ListenerApplyConfiguration struct {
 	IPv6 *string `json:"ipv6,omitempty"`
 	// Whether the listener will be listening for SSL connections
 	Ssl *bool `json:"ssl,omitempty"`
+	// The options of the sockets of the listener. Only supported for HTTP listeners.
+	Options *ListenerOptionsApplyConfiguration `json:"options,omitempty"`
 }
 
 // ListenerApplyConfiguration constructs a declarative configuration of the Listener type for use with
@@ -74,3 +76,11 @@ func (b *ListenerApplyConfiguration) WithSsl(value bool) *ListenerApplyConfigura
 	b.Ssl = &value
 	return b
 }
+
+// WithOptions sets the Options field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Options field is set to the value of the last call.
+func (b *ListenerApplyConfiguration) WithOptions(value *ListenerOptionsApplyConfiguration) *ListenerApplyConfiguration {
+	b.Options = value
+	return b
+}
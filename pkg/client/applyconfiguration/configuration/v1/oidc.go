@@ -39,6 +39,8 @@ type OIDCApplyConfiguration struct {
 	TrustedCertSecret *string `json:"trustedCertSecret,omitempty"`
 	// Sets the verification depth in the IDP server certificates chain. The default is 1.
 	SSLVerifyDepth *int `json:"sslVerifyDepth,omitempty"`
+	// The addresses of the DNS servers used to resolve the hosts of the IDP endpoints, for example, a local forwarder that sends the queries to the upstream DNS servers over TLS. An address may include a port. By default, the resolver configured with the resolver-addresses ConfigMap key is used.
+	Resolvers []string `json:"resolvers,omitempty"`
 }
 
 // OIDCApplyConfiguration constructs a declarative configuration of the OIDC type for use with
@@ -176,3 +178,13 @@ func (b *OIDCApplyConfiguration) WithSSLVerifyDepth(value int) *OIDCApplyConfigu
 	b.SSLVerifyDepth = &value
 	return b
 }
+
+// WithResolvers adds the given value to the Resolvers field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Resolvers field.
+func (b *OIDCApplyConfiguration) WithResolvers(values ...string) *OIDCApplyConfiguration {
+	for i := range values {
+		b.Resolvers = append(b.Resolvers, values[i])
+	}
+	return b
+}
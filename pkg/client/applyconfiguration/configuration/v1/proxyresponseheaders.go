@@ -15,6 +15,8 @@ type ProxyResponseHeadersApplyConfiguration struct {
 	Ignore []string `json:"ignore,omitempty"`
 	// Adds headers to the response to the client.
 	Add []AddHeaderApplyConfiguration `json:"add,omitempty"`
+	// Hides the headers of the default-proxy-hide-headers ConfigMap key in addition to the headers of the hide field. The default is true.
+	HideDefaults *bool `json:"hideDefaults,omitempty"`
 }
 
 // ProxyResponseHeadersApplyConfiguration constructs a declarative configuration of the ProxyResponseHeaders type for use with
@@ -65,3 +67,11 @@ func (b *ProxyResponseHeadersApplyConfiguration) WithAdd(values ...*AddHeaderApp
 	}
 	return b
 }
+
+// WithHideDefaults sets the HideDefaults field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the HideDefaults field is set to the value of the last call.
+func (b *ProxyResponseHeadersApplyConfiguration) WithHideDefaults(value bool) *ProxyResponseHeadersApplyConfiguration {
+	b.HideDefaults = &value
+	return b
+}
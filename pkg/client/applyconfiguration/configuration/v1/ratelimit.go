@@ -13,12 +13,16 @@ type RateLimitApplyConfiguration struct {
 	// Variables must be surrounded by ${}. For example: ${binary_remote_addr}. Accepted variables are
 	// $binary_remote_addr, $request_uri, $request_method, $url, $http_, $args, $arg_, $cookie_,$jwt_claim_ .
 	Key *string `json:"key,omitempty"`
+	// A list of keys that are concatenated into a composite key to which the rate limit is applied, for example, ${binary_remote_addr} and ${http_x_api_client}. Every key follows the same rules as key. Cannot be used together with key.
+	Keys []string `json:"keys,omitempty"`
 	// The delay parameter specifies a limit at which excessive requests become delayed. If not set all excessive requests are delayed.
 	Delay *int `json:"delay,omitempty"`
 	// Disables the delaying of excessive requests while requests are being limited. Overrides delay if both are set.
 	NoDelay *bool `json:"noDelay,omitempty"`
 	// Excessive requests are delayed until their number exceeds the burst size, in which case the request is terminated with an error.
 	Burst *int `json:"burst,omitempty"`
+	// Sets the burst size as a multiple of the rate window. For example, a rate of 10r/s with a burstFactor of 2 results in a burst of 20. Cannot be used together with burst.
+	BurstFactor *int `json:"burstFactor,omitempty"`
 	// Size of the shared memory zone. Only positive values are allowed. Allowed suffixes are k or m, if none are present k is assumed.
 	ZoneSize *string `json:"zoneSize,omitempty"`
 	// Enables the dry run mode. In this mode, the rate limit is not actually applied, but the number of excessive requests is accounted as usual in the shared memory zone.
@@ -29,6 +33,10 @@ type RateLimitApplyConfiguration struct {
 	RejectCode *int `json:"rejectCode,omitempty"`
 	// Enables a constant rate-limit by dividing the configured rate by the number of nginx-ingress pods currently serving traffic. This adjustment ensures that the rate-limit remains consistent, even as the number of nginx-pods fluctuates due to autoscaling. This will not work properly if requests from a client are not evenly distributed across all ingress pods (Such as with sticky sessions, long lived TCP Connections with many requests, and so forth). In such cases using zone-sync instead would give better results. Enabling zone-sync will suppress this setting.
 	Scale *bool `json:"scale,omitempty"`
+	// Shares the zone of the rate limit among all VirtualServers that reference the policy, so that one limit is enforced for the requests to all of them. By default, every VirtualServer gets its own zone. Cannot be used together with condition or scale.
+	Shared *bool `json:"shared,omitempty"`
+	// Increases the size of the zone to the size recommended for the key, when the zoneSize is smaller. The recommended size is estimated for 8192 distinct keys, for example, clients, and only for the keys with variables other than $request_method.
+	AutoZoneSize *bool `json:"autoZoneSize,omitempty"`
 	// Add a condition to a rate-limit policy.
 	Condition *RateLimitConditionApplyConfiguration `json:"condition,omitempty"`
 }
@@ -55,6 +63,16 @@ func (b *RateLimitApplyConfiguration) WithKey(value string) *RateLimitApplyConfi
 	return b
 }
 
+// WithKeys adds the given value to the Keys field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Keys field.
+func (b *RateLimitApplyConfiguration) WithKeys(values ...string) *RateLimitApplyConfiguration {
+	for i := range values {
+		b.Keys = append(b.Keys, values[i])
+	}
+	return b
+}
+
 // WithDelay sets the Delay field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the Delay field is set to the value of the last call.
@@ -79,6 +97,14 @@ func (b *RateLimitApplyConfiguration) WithBurst(value int) *RateLimitApplyConfig
 	return b
 }
 
+// WithBurstFactor sets the BurstFactor field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the BurstFactor field is set to the value of the last call.
+func (b *RateLimitApplyConfiguration) WithBurstFactor(value int) *RateLimitApplyConfiguration {
+	b.BurstFactor = &value
+	return b
+}
+
 // WithZoneSize sets the ZoneSize field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the ZoneSize field is set to the value of the last call.
@@ -119,6 +145,22 @@ func (b *RateLimitApplyConfiguration) WithScale(value bool) *RateLimitApplyConfi
 	return b
 }
 
+// WithShared sets the Shared field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Shared field is set to the value of the last call.
+func (b *RateLimitApplyConfiguration) WithShared(value bool) *RateLimitApplyConfiguration {
+	b.Shared = &value
+	return b
+}
+
+// WithAutoZoneSize sets the AutoZoneSize field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the AutoZoneSize field is set to the value of the last call.
+func (b *RateLimitApplyConfiguration) WithAutoZoneSize(value bool) *RateLimitApplyConfiguration {
+	b.AutoZoneSize = &value
+	return b
+}
+
 // WithCondition sets the Condition field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the Condition field is set to the value of the last call.
@@ -23,16 +23,38 @@ type RouteApplyConfiguration struct {
 	Action *ActionApplyConfiguration `json:"action,omitempty"`
 	// The default splits configuration for traffic splitting. Must include at least 2 splits.
 	Splits []SplitApplyConfiguration `json:"splits,omitempty"`
+	// Enables session persistence for the splits of the route. Traffic is split based on the value of the cookie, which NGINX sets if it is not present in the request.
+	StickyCookie *SplitStickyCookieApplyConfiguration `json:"stickyCookie,omitempty"`
+	// Reads the weights of the two default splits of the route from a shared key at request time, so that the traffic of many routes can be shifted in one place. Requires the -weight-changes-dynamic-reload command-line argument. Note: this feature is supported only in NGINX Plus.
+	SplitWeights *SplitWeightsApplyConfiguration `json:"splitWeights,omitempty"`
 	// The matching rules for advanced content-based routing. Requires the default Action or Splits. Unmatched requests will be handled by the default Action or Splits.
 	Matches []MatchApplyConfiguration `json:"matches,omitempty"`
 	// The custom responses for error codes. NGINX will use those responses instead of returning the error responses from the upstream servers or the default responses generated by NGINX. A custom response can be a redirect or a canned response. For example, a redirect to another URL if an upstream server responded with a 404 status code.
 	ErrorPages []ErrorPageApplyConfiguration `json:"errorPages,omitempty"`
+	// A list of status codes that are passed to the client as they are, even if the error pages of the route, including the error pages inherited from the VirtualServer, include them. For example, to return the original 502, 503 and 504 responses of the upstream servers.
+	PassthroughErrorCodes []int `json:"passthroughErrorCodes,omitempty"`
+	// Uses the error pages of the route of the VirtualServer that references the VirtualServerRoute when the subroute doesn't define its own error pages. Set to false for the subroute to use no error pages. Supported in the subroutes of VirtualServerRoutes only. The default is true.
+	InheritErrorPages *bool `json:"inheritErrorPages,omitempty"`
+	// The custom responses for the gRPC statuses that NGINX returns for the failed requests to gRPC upstreams, for example, UNAVAILABLE when the upstream servers are unavailable. The gRPC statuses that the upstream servers return in the trailers of their responses are passed to the clients as they are. Not supported for routes that reference VirtualServerRoutes.
+	GRPCErrorResponses []GRPCErrorResponseApplyConfiguration `json:"grpcErrorResponses,omitempty"`
 	// Sets a custom snippet in the location context. Overrides the location-snippets ConfigMap key.
 	LocationSnippets *string `json:"location-snippets,omitempty"`
 	// Controls header inheritance behavior at the location level. Allowed values are: on, off, merge. When set to "merge", headers from this context are merged with headers in child contexts. When set to "on", standard NGINX inheritance applies. When set to "off", no headers are inherited from parent contexts.
 	AddHeaderInherit *string `json:"add-header-inherit,omitempty"`
 	// A reference to a DosProtectedResource, setting this enables DOS protection of the VirtualServer route.
 	Dos *string `json:"dos,omitempty"`
+	// Deliberately delays the responses to the requests that match the conditions of the tarpit.
+	Tarpit *TarpitApplyConfiguration `json:"tarpit,omitempty"`
+	// Returns a response with the configured status code to the requests from the listed client IP addresses before any other processing of the requests. The requests from other client IP addresses are handled by the route as usual.
+	ClientIPReturn *ClientIPReturnApplyConfiguration `json:"clientIPReturn,omitempty"`
+	// The name of the status zone that collects the metrics of the requests handled by the route. The value auto derives the name from the host of the VirtualServer and the path of the route. By default, the metrics are collected in the status zone named after the service of the route. Supported in NGINX Plus only.
+	StatusZone *string `json:"statusZone,omitempty"`
+	// Configures the OpenTelemetry tracing of the requests handled by the route, which overrides the tracing of the VirtualServer. The spans of the route are tagged with the path of the route.
+	Tracing *TracingApplyConfiguration `json:"tracing,omitempty"`
+	// A list of rewrites of the URI of the requests handled by the route. The rewrites are applied in order before the action of the route. Not supported for routes with matches or splits, or with return or redirect actions.
+	Rewrites []RewriteApplyConfiguration `json:"rewrites,omitempty"`
+	// Passes the Host header of the client request to the upstream servers as it is ($http_host) instead of the host of the request ($host), which is the default. For example, for the upstream servers that expect the port or the original case of the Host header. A Host header set to another value in the requestHeaders of the proxy action is not changed.
+	PreserveHostHeader *bool `json:"preserveHostHeader,omitempty"`
 }
 
 // RouteApplyConfiguration constructs a declarative configuration of the Route type for use with
@@ -99,6 +121,22 @@ func (b *RouteApplyConfiguration) WithSplits(values ...*SplitApplyConfiguration)
 	return b
 }
 
+// WithStickyCookie sets the StickyCookie field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the StickyCookie field is set to the value of the last call.
+func (b *RouteApplyConfiguration) WithStickyCookie(value *SplitStickyCookieApplyConfiguration) *RouteApplyConfiguration {
+	b.StickyCookie = value
+	return b
+}
+
+// WithSplitWeights sets the SplitWeights field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the SplitWeights field is set to the value of the last call.
+func (b *RouteApplyConfiguration) WithSplitWeights(value *SplitWeightsApplyConfiguration) *RouteApplyConfiguration {
+	b.SplitWeights = value
+	return b
+}
+
 // WithMatches adds the given value to the Matches field in the declarative configuration
 // and returns the receiver, so that objects can be build by chaining "With" function invocations.
 // If called multiple times, values provided by each call will be appended to the Matches field.
@@ -125,6 +163,37 @@ func (b *RouteApplyConfiguration) WithErrorPages(values ...*ErrorPageApplyConfig
 	return b
 }
 
+// WithPassthroughErrorCodes adds the given value to the PassthroughErrorCodes field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the PassthroughErrorCodes field.
+func (b *RouteApplyConfiguration) WithPassthroughErrorCodes(values ...int) *RouteApplyConfiguration {
+	for i := range values {
+		b.PassthroughErrorCodes = append(b.PassthroughErrorCodes, values[i])
+	}
+	return b
+}
+
+// WithInheritErrorPages sets the InheritErrorPages field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the InheritErrorPages field is set to the value of the last call.
+func (b *RouteApplyConfiguration) WithInheritErrorPages(value bool) *RouteApplyConfiguration {
+	b.InheritErrorPages = &value
+	return b
+}
+
+// WithGRPCErrorResponses adds the given value to the GRPCErrorResponses field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the GRPCErrorResponses field.
+func (b *RouteApplyConfiguration) WithGRPCErrorResponses(values ...*GRPCErrorResponseApplyConfiguration) *RouteApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithGRPCErrorResponses")
+		}
+		b.GRPCErrorResponses = append(b.GRPCErrorResponses, *values[i])
+	}
+	return b
+}
+
 // WithLocationSnippets sets the LocationSnippets field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the LocationSnippets field is set to the value of the last call.
@@ -148,3 +217,56 @@ func (b *RouteApplyConfiguration) WithDos(value string) *RouteApplyConfiguration
 	b.Dos = &value
 	return b
 }
+
+// WithTarpit sets the Tarpit field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Tarpit field is set to the value of the last call.
+func (b *RouteApplyConfiguration) WithTarpit(value *TarpitApplyConfiguration) *RouteApplyConfiguration {
+	b.Tarpit = value
+	return b
+}
+
+// WithClientIPReturn sets the ClientIPReturn field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ClientIPReturn field is set to the value of the last call.
+func (b *RouteApplyConfiguration) WithClientIPReturn(value *ClientIPReturnApplyConfiguration) *RouteApplyConfiguration {
+	b.ClientIPReturn = value
+	return b
+}
+
+// WithStatusZone sets the StatusZone field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the StatusZone field is set to the value of the last call.
+func (b *RouteApplyConfiguration) WithStatusZone(value string) *RouteApplyConfiguration {
+	b.StatusZone = &value
+	return b
+}
+
+// WithTracing sets the Tracing field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Tracing field is set to the value of the last call.
+func (b *RouteApplyConfiguration) WithTracing(value *TracingApplyConfiguration) *RouteApplyConfiguration {
+	b.Tracing = value
+	return b
+}
+
+// WithRewrites adds the given value to the Rewrites field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Rewrites field.
+func (b *RouteApplyConfiguration) WithRewrites(values ...*RewriteApplyConfiguration) *RouteApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithRewrites")
+		}
+		b.Rewrites = append(b.Rewrites, *values[i])
+	}
+	return b
+}
+
+// WithPreserveHostHeader sets the PreserveHostHeader field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the PreserveHostHeader field is set to the value of the last call.
+func (b *RouteApplyConfiguration) WithPreserveHostHeader(value bool) *RouteApplyConfiguration {
+	b.PreserveHostHeader = &value
+	return b
+}
@@ -11,6 +11,8 @@ type SplitApplyConfiguration struct {
 	Weight *int `json:"weight,omitempty"`
 	// The action to perform for a request.
 	Action *ActionApplyConfiguration `json:"action,omitempty"`
+	// Sends all requests to the action of the split, regardless of the weights of the splits. Only one split can be promoted. When the dynamic weight changes reload is enabled, promoting a split of a two-way split does not reload NGINX.
+	Promoted *bool `json:"promoted,omitempty"`
 }
 
 // SplitApplyConfiguration constructs a declarative configuration of the Split type for use with
@@ -34,3 +36,11 @@ func (b *SplitApplyConfiguration) WithAction(value *ActionApplyConfiguration) *S
 	b.Action = value
 	return b
 }
+
+// WithPromoted sets the Promoted field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Promoted field is set to the value of the last call.
+func (b *SplitApplyConfiguration) WithPromoted(value bool) *SplitApplyConfiguration {
+	b.Promoted = &value
+	return b
+}
@@ -13,6 +13,8 @@ type TLSApplyConfiguration struct {
 	Redirect *TLSRedirectApplyConfiguration `json:"redirect,omitempty"`
 	// The cert-manager configuration of the TLS for a VirtualServer.
 	CertManager *CertManagerApplyConfiguration `json:"cert-manager,omitempty"`
+	// The TLS profile of a VirtualServer that sets the SSL protocols and ciphers of the TLS listener.
+	Profile *TLSProfileApplyConfiguration `json:"profile,omitempty"`
 }
 
 // TLSApplyConfiguration constructs a declarative configuration of the TLS type for use with
@@ -44,3 +46,11 @@ func (b *TLSApplyConfiguration) WithCertManager(value *CertManagerApplyConfigura
 	b.CertManager = value
 	return b
 }
+
+// WithProfile sets the Profile field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Profile field is set to the value of the last call.
+func (b *TLSApplyConfiguration) WithProfile(value *TLSProfileApplyConfiguration) *TLSApplyConfiguration {
+	b.Profile = value
+	return b
+}
@@ -25,18 +25,28 @@ type UpstreamApplyConfiguration struct {
 	MaxConns *int `json:"max-conns,omitempty"`
 	// Configures the cache for connections to upstream servers. The value 0 disables the cache. The default is set in the keepalive ConfigMap key.
 	Keepalive *int `json:"keepalive,omitempty"`
+	// Limits the maximum time during which requests can be processed through one keepalive connection to an upstream server. After this time is reached, the connection is closed following the subsequent request processing. Setting it is recommended for services of type ExternalName, as the keepalive connections to the previously resolved addresses are otherwise reused for up to one hour. The default is 1h.
+	KeepaliveTime *string `json:"keepalive-time,omitempty"`
 	// The timeout for establishing a connection with an upstream server. The default is specified in the proxy-connect-timeout ConfigMap key.
 	ProxyConnectTimeout *string `json:"connect-timeout,omitempty"`
 	// The timeout for reading a response from an upstream server. The default is specified in the proxy-read-timeout ConfigMap key.
 	ProxyReadTimeout *string `json:"read-timeout,omitempty"`
 	// The timeout for transmitting a request to an upstream server. The default is specified in the proxy-send-timeout ConfigMap key.
 	ProxySendTimeout *string `json:"send-timeout,omitempty"`
-	// Specifies in which cases a request should be passed to the next upstream server. The default is error timeout.
+	// The name of a timeout profile defined in the timeoutProfiles of the VirtualServer. The profile sets the connect, read and send timeouts of the upstream, unless they are set in the connect-timeout, read-timeout and send-timeout fields of the upstream.
+	TimeoutProfile *string `json:"timeout-profile,omitempty"`
+	// Specifies in which cases a request should be passed to the next upstream server. For gRPC upstreams, grpc_next_upstream is configured instead of proxy_next_upstream, along with the next-upstream-timeout and next-upstream-tries. The default is error timeout.
 	ProxyNextUpstream *string `json:"next-upstream,omitempty"`
 	// The time during which a request can be passed to the next upstream server. The 0 value turns off the time limit. The default is 0.
 	ProxyNextUpstreamTimeout *string `json:"next-upstream-timeout,omitempty"`
 	// The number of possible tries for passing a request to the next upstream server. The 0 value turns off this limit. The default is 0.
 	ProxyNextUpstreamTries *int `json:"next-upstream-tries,omitempty"`
+	// Enables TCP keepalive for the connections to the upstream servers. For gRPC upstreams, grpc_socket_keepalive is configured instead of proxy_socket_keepalive. The default is false.
+	ProxySocketKeepalive *bool `json:"socket-keepalive,omitempty"`
+	// Keeps the connection to the upstream server open when the client closes the connection without waiting for the response, so that the upstream server completes the request. Use it for the requests with side effects that must not be interrupted. The connections of the aborted requests are kept until the upstream server responds, which can exhaust the connections when the upstream server is slow. Not supported for upstreams of type grpc. The default is false.
+	ProxyIgnoreClientAbort *bool `json:"ignore-client-abort,omitempty"`
+	// Configures the proxying of WebSocket connections to the upstream servers.
+	WebSocket *UpstreamWebSocketApplyConfiguration `json:"websocket,omitempty"`
 	// Enables buffering of responses from the upstream server.  The default is set in the proxy-buffering ConfigMap key.
 	ProxyBuffering *bool `json:"buffering,omitempty"`
 	// Configures the buffers used for reading a response from the upstream server for a single connection.
@@ -45,11 +55,16 @@ type UpstreamApplyConfiguration struct {
 	ProxyBufferSize *string `json:"buffer-size,omitempty"`
 	// Sets the size of the buffers used for reading a response from the upstream server when the proxy_buffering is enabled. The default is set in the proxy-busy-buffers-size ConfigMap key.'
 	ProxyBusyBuffersSize *string `json:"busy-buffers-size,omitempty"`
-	// Sets the maximum allowed size of the client request body. The default is set in the client-max-body-size ConfigMap key.
+	// Sets the maximum size of the temporary file for buffering a response from the upstream server. The 0 value disables the buffering of responses to temporary files. The default is set in the proxy-max-temp-file-size ConfigMap key.
+	ProxyMaxTempFileSize *string `json:"max-temp-file-size,omitempty"`
+	// Sets the maximum allowed size of the client request body. The default is set in the client-max-body-size ConfigMap key. The 0 value, which disables the checking of the size, requires allow-unlimited-body-size, otherwise the default is used.
 	ClientMaxBodySize *string `json:"client-max-body-size,omitempty"`
+	// Allows the 0 value of client-max-body-size, which accepts the client request bodies of unlimited size. The default is false.
+	AllowUnlimitedBodySize *bool `json:"allow-unlimited-body-size,omitempty"`
 	// ClientBodyBufferSize sets the size of the buffer used for reading the client request body. Must be specified as a number followed by:
 	// 'k' for kilobytes or 'm' for megabytes.
 	// Examples: "10m" or "512k".
+	// A request body larger than the buffer is written to a temporary file. Sizes above client-max-body-size have no effect, as such request bodies are rejected.
 	ClientBodyBufferSize *string `json:"client-body-buffer-size,omitempty"`
 	// The TLS configuration for the Upstream.
 	TLS *UpstreamTLSApplyConfiguration `json:"tls,omitempty"`
@@ -65,12 +80,20 @@ type UpstreamApplyConfiguration struct {
 	UseClusterIP *bool `json:"use-cluster-ip,omitempty"`
 	// Allows proxying requests with NTLM Authentication. In order for NTLM authentication to work, it is necessary to enable keepalive connections to upstream servers using the keepalive field. Note: this feature is supported only in NGINX Plus.
 	NTLM *bool `json:"ntlm,omitempty"`
+	// Keeps the endpoints of the terminating pods of the service that are still serving in the upstream marked down, so that NGINX stops sending them traffic while they are still shown in the upstream. The endpoints are removed once the pods finish terminating. If drain is enabled, the endpoints are kept in the draining mode instead. The default is false. Note: this feature is not applied to upstreams with a subselector.
+	KeepTerminatingEndpoints *bool `json:"keep-terminating-endpoints,omitempty"`
+	// Keeps the endpoints of the terminating pods of the service in the upstream in the draining mode, so that the requests bound to them by session persistence can still be completed. The endpoints are removed once the pods finish terminating. The default is false. Note: this feature is supported only in NGINX Plus and is not applied to upstreams with a subselector.
+	Drain *bool `json:"drain,omitempty"`
+	// The interval of re-resolving the hostname of the ExternalName service of the upstream, for example, 5s. Overrides the valid time of the resolver configured in the ConfigMap for the upstream. Note: this feature is supported only in NGINX Plus and requires the resolver-addresses ConfigMap key.
+	ResolveInterval *string `json:"resolve-interval,omitempty"`
 	// The type of the upstream. Supported values are http and grpc. The default is http. For gRPC, it is necessary to enable HTTP/2 in the ConfigMap and configure TLS termination in the VirtualServer.
 	Type *string `json:"type,omitempty"`
 	// The name of the backup service of type ExternalName. This will be used when the primary servers are unavailable. Note: The parameter cannot be used along with the random, hash or ip_hash load balancing methods.
 	Backup *string `json:"backup,omitempty"`
 	// The port of the backup service. The backup port is required if the backup service name is provided. The port must fall into the range 1..65535.
 	BackupPort *uint16 `json:"backupPort,omitempty"`
+	// Configures the failover from the servers of the upstream to the servers of the backup service. Requires the backup service. Every hostname of the backup service gets one try, even if it resolves to several addresses. The failover sets the next-upstream-tries and next-upstream-timeout of the upstream, so they cannot be used together with it. Note: this feature is supported only in NGINX Plus.
+	Failover *UpstreamFailoverApplyConfiguration `json:"failover,omitempty"`
 }
 
 // UpstreamApplyConfiguration constructs a declarative configuration of the Upstream type for use with
@@ -157,6 +180,14 @@ func (b *UpstreamApplyConfiguration) WithKeepalive(value int) *UpstreamApplyConf
 	return b
 }
 
+// WithKeepaliveTime sets the KeepaliveTime field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the KeepaliveTime field is set to the value of the last call.
+func (b *UpstreamApplyConfiguration) WithKeepaliveTime(value string) *UpstreamApplyConfiguration {
+	b.KeepaliveTime = &value
+	return b
+}
+
 // WithProxyConnectTimeout sets the ProxyConnectTimeout field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the ProxyConnectTimeout field is set to the value of the last call.
@@ -181,6 +212,14 @@ func (b *UpstreamApplyConfiguration) WithProxySendTimeout(value string) *Upstrea
 	return b
 }
 
+// WithTimeoutProfile sets the TimeoutProfile field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the TimeoutProfile field is set to the value of the last call.
+func (b *UpstreamApplyConfiguration) WithTimeoutProfile(value string) *UpstreamApplyConfiguration {
+	b.TimeoutProfile = &value
+	return b
+}
+
 // WithProxyNextUpstream sets the ProxyNextUpstream field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the ProxyNextUpstream field is set to the value of the last call.
@@ -205,6 +244,30 @@ func (b *UpstreamApplyConfiguration) WithProxyNextUpstreamTries(value int) *Upst
 	return b
 }
 
+// WithProxySocketKeepalive sets the ProxySocketKeepalive field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ProxySocketKeepalive field is set to the value of the last call.
+func (b *UpstreamApplyConfiguration) WithProxySocketKeepalive(value bool) *UpstreamApplyConfiguration {
+	b.ProxySocketKeepalive = &value
+	return b
+}
+
+// WithProxyIgnoreClientAbort sets the ProxyIgnoreClientAbort field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ProxyIgnoreClientAbort field is set to the value of the last call.
+func (b *UpstreamApplyConfiguration) WithProxyIgnoreClientAbort(value bool) *UpstreamApplyConfiguration {
+	b.ProxyIgnoreClientAbort = &value
+	return b
+}
+
+// WithWebSocket sets the WebSocket field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the WebSocket field is set to the value of the last call.
+func (b *UpstreamApplyConfiguration) WithWebSocket(value *UpstreamWebSocketApplyConfiguration) *UpstreamApplyConfiguration {
+	b.WebSocket = value
+	return b
+}
+
 // WithProxyBuffering sets the ProxyBuffering field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the ProxyBuffering field is set to the value of the last call.
@@ -237,6 +300,14 @@ func (b *UpstreamApplyConfiguration) WithProxyBusyBuffersSize(value string) *Ups
 	return b
 }
 
+// WithProxyMaxTempFileSize sets the ProxyMaxTempFileSize field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ProxyMaxTempFileSize field is set to the value of the last call.
+func (b *UpstreamApplyConfiguration) WithProxyMaxTempFileSize(value string) *UpstreamApplyConfiguration {
+	b.ProxyMaxTempFileSize = &value
+	return b
+}
+
 // WithClientMaxBodySize sets the ClientMaxBodySize field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the ClientMaxBodySize field is set to the value of the last call.
@@ -245,6 +316,14 @@ func (b *UpstreamApplyConfiguration) WithClientMaxBodySize(value string) *Upstre
 	return b
 }
 
+// WithAllowUnlimitedBodySize sets the AllowUnlimitedBodySize field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the AllowUnlimitedBodySize field is set to the value of the last call.
+func (b *UpstreamApplyConfiguration) WithAllowUnlimitedBodySize(value bool) *UpstreamApplyConfiguration {
+	b.AllowUnlimitedBodySize = &value
+	return b
+}
+
 // WithClientBodyBufferSize sets the ClientBodyBufferSize field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the ClientBodyBufferSize field is set to the value of the last call.
@@ -309,6 +388,30 @@ func (b *UpstreamApplyConfiguration) WithNTLM(value bool) *UpstreamApplyConfigur
 	return b
 }
 
+// WithKeepTerminatingEndpoints sets the KeepTerminatingEndpoints field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the KeepTerminatingEndpoints field is set to the value of the last call.
+func (b *UpstreamApplyConfiguration) WithKeepTerminatingEndpoints(value bool) *UpstreamApplyConfiguration {
+	b.KeepTerminatingEndpoints = &value
+	return b
+}
+
+// WithDrain sets the Drain field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Drain field is set to the value of the last call.
+func (b *UpstreamApplyConfiguration) WithDrain(value bool) *UpstreamApplyConfiguration {
+	b.Drain = &value
+	return b
+}
+
+// WithResolveInterval sets the ResolveInterval field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ResolveInterval field is set to the value of the last call.
+func (b *UpstreamApplyConfiguration) WithResolveInterval(value string) *UpstreamApplyConfiguration {
+	b.ResolveInterval = &value
+	return b
+}
+
 // WithType sets the Type field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the Type field is set to the value of the last call.
@@ -332,3 +435,11 @@ func (b *UpstreamApplyConfiguration) WithBackupPort(value uint16) *UpstreamApply
 	b.BackupPort = &value
 	return b
 }
+
+// WithFailover sets the Failover field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Failover field is set to the value of the last call.
+func (b *UpstreamApplyConfiguration) WithFailover(value *UpstreamFailoverApplyConfiguration) *UpstreamApplyConfiguration {
+	b.Failover = value
+	return b
+}
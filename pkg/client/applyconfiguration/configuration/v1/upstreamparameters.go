@@ -19,6 +19,8 @@ type UpstreamParametersApplyConfiguration struct {
 	NextUpstreamTimeout *string `json:"nextUpstreamTimeout,omitempty"`
 	// The number of tries for passing a connection to the next server. The default is 0.
 	NextUpstreamTries *int `json:"nextUpstreamTries,omitempty"`
+	// Enables the PROXY protocol for connections to the proxied server, for upstreams that expect the original client address in a PROXY protocol header. Not supported for UDP TransportServers. The default is false.
+	ProxyProtocol *bool `json:"proxyProtocol,omitempty"`
 }
 
 // UpstreamParametersApplyConfiguration constructs a declarative configuration of the UpstreamParameters type for use with
@@ -74,3 +76,11 @@ func (b *UpstreamParametersApplyConfiguration) WithNextUpstreamTries(value int)
 	b.NextUpstreamTries = &value
 	return b
 }
+
+// WithProxyProtocol sets the ProxyProtocol field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ProxyProtocol field is set to the value of the last call.
+func (b *UpstreamParametersApplyConfiguration) WithProxyProtocol(value bool) *UpstreamParametersApplyConfiguration {
+	b.ProxyProtocol = &value
+	return b
+}
@@ -9,6 +9,10 @@ package v1
 type UpstreamTLSApplyConfiguration struct {
 	// Enables HTTPS for requests to upstream servers. The default is False , meaning that HTTP will be used. Note: by default, NGINX will not verify the upstream server certificate. To enable the verification, configure an EgressMTLS Policy.
 	Enable *bool `json:"enable,omitempty"`
+	// Enables the reuse of TLS sessions when connecting to upstream servers. The default is true. Disable it for upstream servers that rotate their session ticket keys and fail to resume the sessions. Not supported in health checks. Ignored in the routes with an EgressMTLS policy, which configures the session reuse.
+	SessionReuse *bool `json:"sessionReuse,omitempty"`
+	// The ports that serve TLS, for example, 8443, for the services that serve plaintext and TLS on different ports. When set, HTTPS is used only when the port of the upstream is one of these ports, and HTTP otherwise. The backup servers use the same protocol as the port of the upstream. Requires enable.
+	Ports []uint16 `json:"ports,omitempty"`
 }
 
 // UpstreamTLSApplyConfiguration constructs a declarative configuration of the UpstreamTLS type for use with
@@ -24,3 +28,21 @@ func (b *UpstreamTLSApplyConfiguration) WithEnable(value bool) *UpstreamTLSApply
 	b.Enable = &value
 	return b
 }
+
+// WithSessionReuse sets the SessionReuse field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the SessionReuse field is set to the value of the last call.
+func (b *UpstreamTLSApplyConfiguration) WithSessionReuse(value bool) *UpstreamTLSApplyConfiguration {
+	b.SessionReuse = &value
+	return b
+}
+
+// WithPorts adds the given value to the Ports field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Ports field.
+func (b *UpstreamTLSApplyConfiguration) WithPorts(values ...uint16) *UpstreamTLSApplyConfiguration {
+	for i := range values {
+		b.Ports = append(b.Ports, values[i])
+	}
+	return b
+}
@@ -11,6 +11,8 @@ type VirtualServerListenerApplyConfiguration struct {
 	HTTP *string `json:"http,omitempty"`
 	// The name of an HTTPS listener defined in a GlobalConfiguration resource.
 	HTTPS *string `json:"https,omitempty"`
+	// The names of additional HTTP or HTTPS listeners defined in a GlobalConfiguration resource. The VirtualServer is served on the ports of these listeners in addition to the ports of the http and https listeners. The HTTPS listeners are ignored when TLS passthrough is enabled.
+	Additional []string `json:"additional,omitempty"`
 }
 
 // VirtualServerListenerApplyConfiguration constructs a declarative configuration of the VirtualServerListener type for use with
@@ -34,3 +36,13 @@ func (b *VirtualServerListenerApplyConfiguration) WithHTTPS(value string) *Virtu
 	b.HTTPS = &value
 	return b
 }
+
+// WithAdditional adds the given value to the Additional field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Additional field.
+func (b *VirtualServerListenerApplyConfiguration) WithAdditional(values ...string) *VirtualServerListenerApplyConfiguration {
+	for i := range values {
+		b.Additional = append(b.Additional, values[i])
+	}
+	return b
+}
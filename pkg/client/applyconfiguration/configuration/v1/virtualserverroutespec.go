@@ -15,6 +15,8 @@ type VirtualServerRouteSpecApplyConfiguration struct {
 	Upstreams []UpstreamApplyConfiguration `json:"upstreams,omitempty"`
 	// A list of subroutes.
 	Subroutes []RouteApplyConfiguration `json:"subroutes,omitempty"`
+	// The name of the status zone that collects the metrics of the requests handled by the subroutes that don't set their own statusZone, so that the metrics of the VirtualServerRoute can be told apart from the metrics of the VirtualServer. The value auto derives the name from the namespace and the name of the VirtualServerRoute, for example, tea/tea-vsr. Supported in NGINX Plus only.
+	StatusZone *string `json:"statusZone,omitempty"`
 }
 
 // VirtualServerRouteSpecApplyConfiguration constructs a declarative configuration of the VirtualServerRouteSpec type for use with
@@ -64,3 +66,11 @@ func (b *VirtualServerRouteSpecApplyConfiguration) WithSubroutes(values ...*Rout
 	}
 	return b
 }
+
+// WithStatusZone sets the StatusZone field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the StatusZone field is set to the value of the last call.
+func (b *VirtualServerRouteSpecApplyConfiguration) WithStatusZone(value string) *VirtualServerRouteSpecApplyConfiguration {
+	b.StatusZone = &value
+	return b
+}
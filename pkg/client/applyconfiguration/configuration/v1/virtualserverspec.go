@@ -15,12 +15,46 @@ type VirtualServerSpecApplyConfiguration struct {
 	Listener *VirtualServerListenerApplyConfiguration `json:"listener,omitempty"`
 	// The TLS termination configuration.
 	TLS *TLSApplyConfiguration `json:"tls,omitempty"`
+	// Enables or disables HTTP/2 for the TLS listener of the VirtualServer. Overrides the http2 ConfigMap key.
+	HTTP2 *bool `json:"http2,omitempty"`
+	// Enables or disables HTTP/3 for the TLS listener of the VirtualServer. Requires the -enable-http3 command-line argument and NGINX 1.25.0 or NGINX Plus R30 or later. The support of HTTP/3 is advertised to the clients with the Alt-Svc response header.
+	HTTP3 *bool `json:"http3,omitempty"`
 	// Enables or disables decompression of gzipped responses for clients. Allowed values “on”/“off”, “true”/“false” or “yes”/“no”. If the gunzip value is not set, it defaults to off.
 	Gunzip *bool `json:"gunzip,omitempty"`
+	// Enables or disables the use of underscores in client request header fields for the VirtualServer. Applies only to the HTTPS requests, as NGINX reads the headers of the HTTP requests with the value of the default server of the listener. If not set, the value of the underscores_in_headers directive in the http context is used, which is off by default.
+	UnderscoresInHeaders *bool `json:"underscoresInHeaders,omitempty"`
+	// Enables or disables the compression of two or more adjacent slashes in the URI of a request into a single slash for the VirtualServer. If not set, the value of the merge_slashes directive in the http context is used, which is on by default. Note: when disabled, the URIs with adjacent slashes, for example, //path, are not matched by the routes for /path, so the policies of those routes are not applied to them.
+	MergeSlashes *bool `json:"mergeSlashes,omitempty"`
+	// Sets the MIME type of the responses generated by NGINX for the VirtualServer whose type is not otherwise determined, for example, the files with an unknown extension. It has no effect on the responses proxied from an upstream server, which are passed with the Content-Type header of the upstream server, if any. If not set, the default_type of the http context is used, which is application/octet-stream.
+	DefaultType *string `json:"defaultType,omitempty"`
+	// Sets the level of the error log of the VirtualServer, overriding the error-log-level ConfigMap key for the server. Allowed values are debug, info, notice, warn, error, crit, alert and emerg. The debug level requires the nginx-debug binary.
+	ErrorLogLevel *string `json:"errorLogLevel,omitempty"`
+	// Sets the destination of the error log of the VirtualServer. Allowed values are stderr and syslog:server=<address>[,parameters]. Requires errorLogLevel to be set. If not set, the default is stderr.
+	ErrorLogDestination *string `json:"errorLogDestination,omitempty"`
+	// Rejects the requests whose Host header doesn't match the host of the VirtualServer, for example, the requests for another host sent over a TLS connection established for the VirtualServer.
+	StrictHost *StrictHostApplyConfiguration `json:"strictHost,omitempty"`
+	// Enables the variables with the request length and the bytes sent of each route for a custom access log format. The variables are named $vs_<namespace>_<name>_route_<index>_request_length and $vs_<namespace>_<name>_route_<index>_bytes_sent, where the dashes in the namespace and the name are replaced with underscores and the index is the position of the route among the routes of the VirtualServer followed by the subroutes of the VirtualServerRoutes. The variables are empty for the requests of the other routes. The default is false.
+	RouteSizeVariables *bool `json:"routeSizeVariables,omitempty"`
+	// Allows the response headers added by the VirtualServer and its routes to reference the variables of the upstream, like $upstream_addr and $upstream_response_time, to show which upstream server processed a request. When disabled, the response headers that reference those variables are ignored, so that they are not exposed in production. The default is false.
+	DebugHeaders *bool `json:"debugHeaders,omitempty"`
+	// Configures the response to the requests for an upstream without available endpoints, which is a 502 response by default. It doesn't apply to the routes with error pages for the responses of the upstream servers, nor to the routes that pass the 502 errors through.
+	NoEndpoints *NoEndpointsApplyConfiguration `json:"noEndpoints,omitempty"`
+	// Passes the request ID sent by the client, or a request ID generated by NGINX if the client didn't send one, to the upstream servers and back to the client.
+	RequestID *RequestIDApplyConfiguration `json:"requestID,omitempty"`
+	// The response headers added to every response of the VirtualServer. The headers are also added to the responses of the routes that add their own response headers, unless the route adds a header with the same name, which takes precedence, or add-header-inherit is set.
+	ResponseHeaders *ResponseHeadersApplyConfiguration `json:"responseHeaders,omitempty"`
+	// Configures the OpenTelemetry tracing of the requests of the VirtualServer. Requires the otel-exporter-endpoint ConfigMap key.
+	Tracing *TracingApplyConfiguration `json:"tracing,omitempty"`
 	// A list of policies.
 	Policies []PolicyReferenceApplyConfiguration `json:"policies,omitempty"`
 	// A list of upstreams.
 	Upstreams []UpstreamApplyConfiguration `json:"upstreams,omitempty"`
+	// A list of health check matches that the health checks of the upstreams of the VirtualServer and its VirtualServerRoutes can reference by name. Each match is generated once, no matter how many upstreams reference it.
+	HealthCheckMatches []HealthCheckMatchApplyConfiguration `json:"healthCheckMatches,omitempty"`
+	// A list of timeout profiles that the upstreams of the VirtualServer and its VirtualServerRoutes can reference by name, so that the same connect, read and send timeouts are not repeated in every upstream.
+	TimeoutProfiles []TimeoutProfileApplyConfiguration `json:"timeoutProfiles,omitempty"`
+	// A list of geos that the conditions of the routes of the VirtualServer and its VirtualServerRoutes can reference by name to route the requests by the client IP address, for example, to regional upstreams.
+	Geos []GeoApplyConfiguration `json:"geos,omitempty"`
 	// A list of routes.
 	Routes []RouteApplyConfiguration `json:"routes,omitempty"`
 	// Sets a custom snippet in the http context.
@@ -73,6 +107,22 @@ func (b *VirtualServerSpecApplyConfiguration) WithTLS(value *TLSApplyConfigurati
 	return b
 }
 
+// WithHTTP2 sets the HTTP2 field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the HTTP2 field is set to the value of the last call.
+func (b *VirtualServerSpecApplyConfiguration) WithHTTP2(value bool) *VirtualServerSpecApplyConfiguration {
+	b.HTTP2 = &value
+	return b
+}
+
+// WithHTTP3 sets the HTTP3 field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the HTTP3 field is set to the value of the last call.
+func (b *VirtualServerSpecApplyConfiguration) WithHTTP3(value bool) *VirtualServerSpecApplyConfiguration {
+	b.HTTP3 = &value
+	return b
+}
+
 // WithGunzip sets the Gunzip field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the Gunzip field is set to the value of the last call.
@@ -81,6 +131,102 @@ func (b *VirtualServerSpecApplyConfiguration) WithGunzip(value bool) *VirtualSer
 	return b
 }
 
+// WithUnderscoresInHeaders sets the UnderscoresInHeaders field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the UnderscoresInHeaders field is set to the value of the last call.
+func (b *VirtualServerSpecApplyConfiguration) WithUnderscoresInHeaders(value bool) *VirtualServerSpecApplyConfiguration {
+	b.UnderscoresInHeaders = &value
+	return b
+}
+
+// WithMergeSlashes sets the MergeSlashes field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the MergeSlashes field is set to the value of the last call.
+func (b *VirtualServerSpecApplyConfiguration) WithMergeSlashes(value bool) *VirtualServerSpecApplyConfiguration {
+	b.MergeSlashes = &value
+	return b
+}
+
+// WithDefaultType sets the DefaultType field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the DefaultType field is set to the value of the last call.
+func (b *VirtualServerSpecApplyConfiguration) WithDefaultType(value string) *VirtualServerSpecApplyConfiguration {
+	b.DefaultType = &value
+	return b
+}
+
+// WithErrorLogLevel sets the ErrorLogLevel field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ErrorLogLevel field is set to the value of the last call.
+func (b *VirtualServerSpecApplyConfiguration) WithErrorLogLevel(value string) *VirtualServerSpecApplyConfiguration {
+	b.ErrorLogLevel = &value
+	return b
+}
+
+// WithErrorLogDestination sets the ErrorLogDestination field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ErrorLogDestination field is set to the value of the last call.
+func (b *VirtualServerSpecApplyConfiguration) WithErrorLogDestination(value string) *VirtualServerSpecApplyConfiguration {
+	b.ErrorLogDestination = &value
+	return b
+}
+
+// WithStrictHost sets the StrictHost field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the StrictHost field is set to the value of the last call.
+func (b *VirtualServerSpecApplyConfiguration) WithStrictHost(value *StrictHostApplyConfiguration) *VirtualServerSpecApplyConfiguration {
+	b.StrictHost = value
+	return b
+}
+
+// WithRouteSizeVariables sets the RouteSizeVariables field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the RouteSizeVariables field is set to the value of the last call.
+func (b *VirtualServerSpecApplyConfiguration) WithRouteSizeVariables(value bool) *VirtualServerSpecApplyConfiguration {
+	b.RouteSizeVariables = &value
+	return b
+}
+
+// WithDebugHeaders sets the DebugHeaders field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the DebugHeaders field is set to the value of the last call.
+func (b *VirtualServerSpecApplyConfiguration) WithDebugHeaders(value bool) *VirtualServerSpecApplyConfiguration {
+	b.DebugHeaders = &value
+	return b
+}
+
+// WithNoEndpoints sets the NoEndpoints field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the NoEndpoints field is set to the value of the last call.
+func (b *VirtualServerSpecApplyConfiguration) WithNoEndpoints(value *NoEndpointsApplyConfiguration) *VirtualServerSpecApplyConfiguration {
+	b.NoEndpoints = value
+	return b
+}
+
+// WithRequestID sets the RequestID field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the RequestID field is set to the value of the last call.
+func (b *VirtualServerSpecApplyConfiguration) WithRequestID(value *RequestIDApplyConfiguration) *VirtualServerSpecApplyConfiguration {
+	b.RequestID = value
+	return b
+}
+
+// WithResponseHeaders sets the ResponseHeaders field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ResponseHeaders field is set to the value of the last call.
+func (b *VirtualServerSpecApplyConfiguration) WithResponseHeaders(value *ResponseHeadersApplyConfiguration) *VirtualServerSpecApplyConfiguration {
+	b.ResponseHeaders = value
+	return b
+}
+
+// WithTracing sets the Tracing field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Tracing field is set to the value of the last call.
+func (b *VirtualServerSpecApplyConfiguration) WithTracing(value *TracingApplyConfiguration) *VirtualServerSpecApplyConfiguration {
+	b.Tracing = value
+	return b
+}
+
 // WithPolicies adds the given value to the Policies field in the declarative configuration
 // and returns the receiver, so that objects can be build by chaining "With" function invocations.
 // If called multiple times, values provided by each call will be appended to the Policies field.
@@ -107,6 +253,45 @@ func (b *VirtualServerSpecApplyConfiguration) WithUpstreams(values ...*UpstreamA
 	return b
 }
 
+// WithHealthCheckMatches adds the given value to the HealthCheckMatches field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the HealthCheckMatches field.
+func (b *VirtualServerSpecApplyConfiguration) WithHealthCheckMatches(values ...*HealthCheckMatchApplyConfiguration) *VirtualServerSpecApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithHealthCheckMatches")
+		}
+		b.HealthCheckMatches = append(b.HealthCheckMatches, *values[i])
+	}
+	return b
+}
+
+// WithTimeoutProfiles adds the given value to the TimeoutProfiles field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the TimeoutProfiles field.
+func (b *VirtualServerSpecApplyConfiguration) WithTimeoutProfiles(values ...*TimeoutProfileApplyConfiguration) *VirtualServerSpecApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithTimeoutProfiles")
+		}
+		b.TimeoutProfiles = append(b.TimeoutProfiles, *values[i])
+	}
+	return b
+}
+
+// WithGeos adds the given value to the Geos field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Geos field.
+func (b *VirtualServerSpecApplyConfiguration) WithGeos(values ...*GeoApplyConfiguration) *VirtualServerSpecApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithGeos")
+		}
+		b.Geos = append(b.Geos, *values[i])
+	}
+	return b
+}
+
 // WithRoutes adds the given value to the Routes field in the declarative configuration
 // and returns the receiver, so that objects can be build by chaining "With" function invocations.
 // If called multiple times, values provided by each call will be appended to the Routes field.
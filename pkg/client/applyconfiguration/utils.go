@@ -48,18 +48,24 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &applyconfigurationconfigurationv1.AccessControlApplyConfiguration{}
 	case configurationv1.SchemeGroupVersion.WithKind("Action"):
 		return &applyconfigurationconfigurationv1.ActionApplyConfiguration{}
+	case configurationv1.SchemeGroupVersion.WithKind("ActionFiles"):
+		return &applyconfigurationconfigurationv1.ActionFilesApplyConfiguration{}
 	case configurationv1.SchemeGroupVersion.WithKind("ActionProxy"):
 		return &applyconfigurationconfigurationv1.ActionProxyApplyConfiguration{}
 	case configurationv1.SchemeGroupVersion.WithKind("ActionRedirect"):
 		return &applyconfigurationconfigurationv1.ActionRedirectApplyConfiguration{}
 	case configurationv1.SchemeGroupVersion.WithKind("ActionReturn"):
 		return &applyconfigurationconfigurationv1.ActionReturnApplyConfiguration{}
+	case configurationv1.SchemeGroupVersion.WithKind("ActionSubFilter"):
+		return &applyconfigurationconfigurationv1.ActionSubFilterApplyConfiguration{}
 	case configurationv1.SchemeGroupVersion.WithKind("AddHeader"):
 		return &applyconfigurationconfigurationv1.AddHeaderApplyConfiguration{}
 	case configurationv1.SchemeGroupVersion.WithKind("APIKey"):
 		return &applyconfigurationconfigurationv1.APIKeyApplyConfiguration{}
 	case configurationv1.SchemeGroupVersion.WithKind("BasicAuth"):
 		return &applyconfigurationconfigurationv1.BasicAuthApplyConfiguration{}
+	case configurationv1.SchemeGroupVersion.WithKind("BasicAuthUser"):
+		return &applyconfigurationconfigurationv1.BasicAuthUserApplyConfiguration{}
 	case configurationv1.SchemeGroupVersion.WithKind("BundleSource"):
 		return &applyconfigurationconfigurationv1.BundleSourceApplyConfiguration{}
 	case configurationv1.SchemeGroupVersion.WithKind("Cache"):
@@ -72,6 +78,8 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &applyconfigurationconfigurationv1.CacheManagerApplyConfiguration{}
 	case configurationv1.SchemeGroupVersion.WithKind("CertManager"):
 		return &applyconfigurationconfigurationv1.CertManagerApplyConfiguration{}
+	case configurationv1.SchemeGroupVersion.WithKind("ClientIPReturn"):
+		return &applyconfigurationconfigurationv1.ClientIPReturnApplyConfiguration{}
 	case configurationv1.SchemeGroupVersion.WithKind("Condition"):
 		return &applyconfigurationconfigurationv1.ConditionApplyConfiguration{}
 	case configurationv1.SchemeGroupVersion.WithKind("CORS"):
@@ -90,6 +98,14 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &applyconfigurationconfigurationv1.ExternalDNSApplyConfiguration{}
 	case configurationv1.SchemeGroupVersion.WithKind("ExternalEndpoint"):
 		return &applyconfigurationconfigurationv1.ExternalEndpointApplyConfiguration{}
+	case configurationv1.SchemeGroupVersion.WithKind("GRPCErrorResponse"):
+		return &applyconfigurationconfigurationv1.GRPCErrorResponseApplyConfiguration{}
+	case configurationv1.SchemeGroupVersion.WithKind("GRPCErrorReturn"):
+		return &applyconfigurationconfigurationv1.GRPCErrorReturnApplyConfiguration{}
+	case configurationv1.SchemeGroupVersion.WithKind("Geo"):
+		return &applyconfigurationconfigurationv1.GeoApplyConfiguration{}
+	case configurationv1.SchemeGroupVersion.WithKind("GeoRange"):
+		return &applyconfigurationconfigurationv1.GeoRangeApplyConfiguration{}
 	case configurationv1.SchemeGroupVersion.WithKind("GlobalConfiguration"):
 		return &applyconfigurationconfigurationv1.GlobalConfigurationApplyConfiguration{}
 	case configurationv1.SchemeGroupVersion.WithKind("GlobalConfigurationSpec"):
@@ -98,6 +114,8 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &applyconfigurationconfigurationv1.HeaderApplyConfiguration{}
 	case configurationv1.SchemeGroupVersion.WithKind("HealthCheck"):
 		return &applyconfigurationconfigurationv1.HealthCheckApplyConfiguration{}
+	case configurationv1.SchemeGroupVersion.WithKind("HealthCheckMatch"):
+		return &applyconfigurationconfigurationv1.HealthCheckMatchApplyConfiguration{}
 	case configurationv1.SchemeGroupVersion.WithKind("HSTS"):
 		return &applyconfigurationconfigurationv1.HSTSApplyConfiguration{}
 	case configurationv1.SchemeGroupVersion.WithKind("IngressMTLS"):
@@ -108,8 +126,12 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &applyconfigurationconfigurationv1.JWTConditionApplyConfiguration{}
 	case configurationv1.SchemeGroupVersion.WithKind("Listener"):
 		return &applyconfigurationconfigurationv1.ListenerApplyConfiguration{}
+	case configurationv1.SchemeGroupVersion.WithKind("ListenerOptions"):
+		return &applyconfigurationconfigurationv1.ListenerOptionsApplyConfiguration{}
 	case configurationv1.SchemeGroupVersion.WithKind("Match"):
 		return &applyconfigurationconfigurationv1.MatchApplyConfiguration{}
+	case configurationv1.SchemeGroupVersion.WithKind("NoEndpoints"):
+		return &applyconfigurationconfigurationv1.NoEndpointsApplyConfiguration{}
 	case configurationv1.SchemeGroupVersion.WithKind("OIDC"):
 		return &applyconfigurationconfigurationv1.OIDCApplyConfiguration{}
 	case configurationv1.SchemeGroupVersion.WithKind("Policy"):
@@ -130,6 +152,14 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &applyconfigurationconfigurationv1.RateLimitApplyConfiguration{}
 	case configurationv1.SchemeGroupVersion.WithKind("RateLimitCondition"):
 		return &applyconfigurationconfigurationv1.RateLimitConditionApplyConfiguration{}
+	case configurationv1.SchemeGroupVersion.WithKind("RequestID"):
+		return &applyconfigurationconfigurationv1.RequestIDApplyConfiguration{}
+	case configurationv1.SchemeGroupVersion.WithKind("ResponseHeaders"):
+		return &applyconfigurationconfigurationv1.ResponseHeadersApplyConfiguration{}
+	case configurationv1.SchemeGroupVersion.WithKind("ReturnBody"):
+		return &applyconfigurationconfigurationv1.ReturnBodyApplyConfiguration{}
+	case configurationv1.SchemeGroupVersion.WithKind("Rewrite"):
+		return &applyconfigurationconfigurationv1.RewriteApplyConfiguration{}
 	case configurationv1.SchemeGroupVersion.WithKind("Route"):
 		return &applyconfigurationconfigurationv1.RouteApplyConfiguration{}
 	case configurationv1.SchemeGroupVersion.WithKind("SecurityLog"):
@@ -140,12 +170,28 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &applyconfigurationconfigurationv1.SessionParametersApplyConfiguration{}
 	case configurationv1.SchemeGroupVersion.WithKind("Split"):
 		return &applyconfigurationconfigurationv1.SplitApplyConfiguration{}
+	case configurationv1.SchemeGroupVersion.WithKind("SplitStickyCookie"):
+		return &applyconfigurationconfigurationv1.SplitStickyCookieApplyConfiguration{}
+	case configurationv1.SchemeGroupVersion.WithKind("SplitWeights"):
+		return &applyconfigurationconfigurationv1.SplitWeightsApplyConfiguration{}
+	case configurationv1.SchemeGroupVersion.WithKind("StrictHost"):
+		return &applyconfigurationconfigurationv1.StrictHostApplyConfiguration{}
+	case configurationv1.SchemeGroupVersion.WithKind("SubFilterRule"):
+		return &applyconfigurationconfigurationv1.SubFilterRuleApplyConfiguration{}
 	case configurationv1.SchemeGroupVersion.WithKind("SuppliedIn"):
 		return &applyconfigurationconfigurationv1.SuppliedInApplyConfiguration{}
 	case configurationv1.SchemeGroupVersion.WithKind("TLS"):
 		return &applyconfigurationconfigurationv1.TLSApplyConfiguration{}
+	case configurationv1.SchemeGroupVersion.WithKind("TLSProfile"):
+		return &applyconfigurationconfigurationv1.TLSProfileApplyConfiguration{}
 	case configurationv1.SchemeGroupVersion.WithKind("TLSRedirect"):
 		return &applyconfigurationconfigurationv1.TLSRedirectApplyConfiguration{}
+	case configurationv1.SchemeGroupVersion.WithKind("Tarpit"):
+		return &applyconfigurationconfigurationv1.TarpitApplyConfiguration{}
+	case configurationv1.SchemeGroupVersion.WithKind("TimeoutProfile"):
+		return &applyconfigurationconfigurationv1.TimeoutProfileApplyConfiguration{}
+	case configurationv1.SchemeGroupVersion.WithKind("Tracing"):
+		return &applyconfigurationconfigurationv1.TracingApplyConfiguration{}
 	case configurationv1.SchemeGroupVersion.WithKind("TransportServer"):
 		return &applyconfigurationconfigurationv1.TransportServerApplyConfiguration{}
 	case configurationv1.SchemeGroupVersion.WithKind("TransportServerAction"):
@@ -168,12 +214,16 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &applyconfigurationconfigurationv1.UpstreamApplyConfiguration{}
 	case configurationv1.SchemeGroupVersion.WithKind("UpstreamBuffers"):
 		return &applyconfigurationconfigurationv1.UpstreamBuffersApplyConfiguration{}
+	case configurationv1.SchemeGroupVersion.WithKind("UpstreamFailover"):
+		return &applyconfigurationconfigurationv1.UpstreamFailoverApplyConfiguration{}
 	case configurationv1.SchemeGroupVersion.WithKind("UpstreamParameters"):
 		return &applyconfigurationconfigurationv1.UpstreamParametersApplyConfiguration{}
 	case configurationv1.SchemeGroupVersion.WithKind("UpstreamQueue"):
 		return &applyconfigurationconfigurationv1.UpstreamQueueApplyConfiguration{}
 	case configurationv1.SchemeGroupVersion.WithKind("UpstreamTLS"):
 		return &applyconfigurationconfigurationv1.UpstreamTLSApplyConfiguration{}
+	case configurationv1.SchemeGroupVersion.WithKind("UpstreamWebSocket"):
+		return &applyconfigurationconfigurationv1.UpstreamWebSocketApplyConfiguration{}
 	case configurationv1.SchemeGroupVersion.WithKind("VariableCondition"):
 		return &applyconfigurationconfigurationv1.VariableConditionApplyConfiguration{}
 	case configurationv1.SchemeGroupVersion.WithKind("VirtualServer"):